@@ -0,0 +1,62 @@
+// Command migrate-uploads copies existing uploads from a local directory
+// to the configured storage backend (e.g., after switching STORAGE_BACKEND to s3).
+//
+// Usage:
+//
+//	go run ./cmd/migrate-uploads -env ./.env -from ./uploads
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+func main() {
+	envPath := flag.String("env", "./.env", "Path to the .env file")
+	fromDir := flag.String("from", "", "Local upload directory to copy from (default: UPLOAD_DIR)")
+	flag.Parse()
+
+	if _, err := os.Stat(*envPath); os.IsNotExist(err) {
+		log.Printf("No .env found, using env vars")
+	} else if err := godotenv.Load(*envPath); err != nil {
+		log.Fatalf("Error loading .env: %v", err)
+	}
+
+	cfg := config.Load()
+	if *fromDir == "" {
+		*fromDir = cfg.UploadDir
+	}
+
+	storageConfig := services.ConfigToStorageConfig(cfg)
+	if err := services.ValidateStorageConfig(storageConfig); err != nil {
+		log.Fatalf("Invalid upload storage configuration: %v", err)
+	}
+
+	if !strings.EqualFold(cfg.StorageBackend, "s3") {
+		from, _ := filepath.Abs(*fromDir)
+		to, _ := filepath.Abs(cfg.UploadDir)
+		if from == to {
+			log.Fatalf("Source and target are the same directory (%s); set STORAGE_BACKEND=s3 or a different UPLOAD_DIR", from)
+		}
+	}
+
+	target, err := services.NewStorage(storageConfig)
+	if err != nil {
+		log.Fatalf("Failed to initialize %s storage: %v", cfg.StorageBackend, err)
+	}
+
+	log.Printf("Copying uploads from %s to %s storage...", *fromDir, cfg.StorageBackend)
+	copied, err := services.CopyStorage(services.NewLocalStorage(*fromDir), target)
+	if err != nil {
+		log.Fatalf("Migration failed after %d files: %v", copied, err)
+	}
+
+	log.Printf("Copied %d files", copied)
+}
@@ -0,0 +1,55 @@
+// Command mock-oidc runs a local OpenID Connect provider for trying out single sign-on.
+// Every login is approved without asking, as the user given by the flags.
+//
+// Usage:
+//
+//	go run ./cmd/mock-oidc -addr localhost:9400 -email staff@example.com -groups staff,vets
+//
+// and in .env:
+//
+//	OIDC_PROVIDERS=mock
+//	OIDC_MOCK_ISSUER=http://localhost:9400
+//	OIDC_MOCK_CLIENT_ID=gassigeher
+//	OIDC_MOCK_CLIENT_SECRET=secret
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/tranmh/gassigeher/internal/oidcmock"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:9400", "Address to listen on")
+	clientID := flag.String("client-id", "gassigeher", "Client ID")
+	clientSecret := flag.String("client-secret", "secret", "Client secret")
+	subject := flag.String("sub", "mock-user-1", "Subject (stable user ID)")
+	email := flag.String("email", "staff@example.com", "Email address of the user")
+	name := flag.String("name", "Mock User", "Name of the user")
+	verified := flag.Bool("email-verified", true, "Whether the email address is verified")
+	groups := flag.String("groups", "", "Comma-separated groups of the user")
+	flag.Parse()
+
+	issuer := "http://" + *addr
+	provider, err := oidcmock.New(issuer, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatalf("Failed to create provider: %v", err)
+	}
+
+	claims := map[string]interface{}{
+		"sub":            *subject,
+		"email":          *email,
+		"email_verified": *verified,
+		"name":           *name,
+	}
+	if *groups != "" {
+		claims["groups"] = strings.Split(*groups, ",")
+	}
+	provider.SetUser(claims)
+
+	log.Printf("Mock OIDC provider at %s, logging in %s", issuer, *email)
+	log.Fatal(http.ListenAndServe(*addr, provider))
+}
@@ -0,0 +1,70 @@
+// Command rotate-jwt-key creates a new JWT signing key and retires the active one.
+// Tokens signed with the retired key stay valid for JWT_KEY_GRACE_HOURS, so nobody is logged out.
+// Running instances pick up the new key within a minute.
+//
+// Usage:
+//
+//	go run ./cmd/rotate-jwt-key -env ./.env
+//	go run ./cmd/rotate-jwt-key -env ./.env -list
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/database"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+func main() {
+	envPath := flag.String("env", "./.env", "Path to the .env file")
+	list := flag.Bool("list", false, "Only list the keys that verify tokens")
+	flag.Parse()
+
+	if _, err := os.Stat(*envPath); os.IsNotExist(err) {
+		log.Printf("No .env found, using env vars")
+	} else if err := godotenv.Load(*envPath); err != nil {
+		log.Fatalf("Error loading .env: %v", err)
+	}
+
+	cfg := config.Load()
+	db, dialect, err := database.InitializeWithConfig(cfg.GetDBConfig())
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	if err := database.RunMigrationsWithDialect(db, dialect); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	keys, err := services.NewJWTKeyRingFromConfig(repository.NewJWTKeyRepository(db, cfg.JWTKeyEncryptionKeys), cfg)
+	if err != nil {
+		log.Fatalf("Invalid JWT key configuration: %v", err)
+	}
+
+	if !*list {
+		key, err := keys.Rotate()
+		if err != nil {
+			log.Fatalf("Rotation failed: %v", err)
+		}
+		log.Printf("New signing key %s (%s)", key.Kid, key.Algorithm)
+	}
+
+	current, err := keys.Keys()
+	if err != nil {
+		log.Fatalf("Failed to list keys: %v", err)
+	}
+	for _, key := range current {
+		switch {
+		case key.Active:
+			log.Printf("%s  %-6s  active since %s", key.Kid, key.Algorithm, key.CreatedAt.Format("2006-01-02 15:04"))
+		case key.VerifiesUntil != nil:
+			log.Printf("%s  %-6s  retired, verifies until %s", key.Kid, key.Algorithm, key.VerifiesUntil.Format("2006-01-02 15:04"))
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/tranmh/gassigeher/internal/handlers"
 	"github.com/tranmh/gassigeher/internal/logging"
 	"github.com/tranmh/gassigeher/internal/middleware"
+	"github.com/tranmh/gassigeher/internal/models"
 	"github.com/tranmh/gassigeher/internal/repository"
 	"github.com/tranmh/gassigeher/internal/services"
 	"github.com/tranmh/gassigeher/internal/static"
@@ -64,6 +65,9 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if err := logging.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	// Initialize database with multi-database support
 	dbConfig := cfg.GetDBConfig()
@@ -86,6 +90,13 @@ func main() {
 		log.Fatalf("Failed to seed database: %v", err)
 	}
 
+	// Rebuild the dog search index, picking up seeded dogs and changes made outside the application
+	if count, err := repository.NewDogSearchRepository(db, cfg.DBType).Rebuild(); err != nil {
+		log.Printf("Warning: Failed to rebuild dog search index: %v", err)
+	} else {
+		log.Printf("Indexed %d dogs for search", count)
+	}
+
 	// DONE: Phase 2 - Check and update Super Admin password
 	superAdminService := services.NewSuperAdminService(db, cfg)
 	if err := superAdminService.CheckAndUpdatePassword(); err != nil {
@@ -93,6 +104,27 @@ func main() {
 		// Don't exit - allow server to start
 	}
 
+	// Validate upload storage before handlers start using it
+	if err := services.ValidateStorageConfig(services.ConfigToStorageConfig(cfg)); err != nil {
+		log.Fatalf("Invalid upload storage configuration: %v", err)
+	}
+	log.Printf("Using upload storage: %s", cfg.StorageBackend)
+
+	// Import the JWT key file or create the first signing key before tokens are issued
+	if len(cfg.JWTKeyEncryptionKeys) == 0 {
+		log.Println("Warning: JWT_KEY_ENCRYPTION_KEY is not set, JWT private keys are stored unencrypted")
+	}
+	jwtKeys, err := services.NewJWTKeyRingFromConfig(repository.NewJWTKeyRepository(db, cfg.JWTKeyEncryptionKeys), cfg)
+	if err != nil {
+		log.Fatalf("Invalid JWT key configuration: %v", err)
+	}
+	if err := jwtKeys.EnsureActiveKey(); err != nil {
+		log.Fatalf("Failed to initialize JWT signing keys: %v", err)
+	}
+	if err := services.ValidateOIDCProviders(cfg.OIDCProviders); err != nil {
+		log.Fatalf("Invalid single sign-on configuration: %v", err)
+	}
+
 	// Initialize router
 	router := mux.NewRouter()
 
@@ -103,6 +135,7 @@ func main() {
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(db, cfg)
+	oidcHandler := handlers.NewOIDCHandler(db, cfg)
 	userHandler := handlers.NewUserHandler(db, cfg)
 	dogHandler := handlers.NewDogHandler(db, cfg)
 	bookingHandler := handlers.NewBookingHandler(db, cfg)
@@ -111,6 +144,22 @@ func main() {
 	experienceHandler := handlers.NewExperienceRequestHandler(db, cfg)
 	reactivationHandler := handlers.NewReactivationRequestHandler(db, cfg)
 	dashboardHandler := handlers.NewDashboardHandler(db, cfg)
+	walkReportHandler := handlers.NewWalkReportHandler(db, cfg)
+	incidentHandler := handlers.NewIncidentHandler(db, cfg)
+	dogHealthHandler := handlers.NewDogHealthHandler(db, cfg)
+	dogPhotoHandler := handlers.NewDogPhotoHandler(db, cfg)
+	dogFavoriteHandler := handlers.NewDogFavoriteHandler(db, cfg)
+	dogTagHandler := handlers.NewDogTagHandler(db, cfg)
+	dogStatsHandler := handlers.NewDogStatsHandler(db, cfg)
+	dogImportHandler := handlers.NewDogImportHandler(db, cfg)
+	publicFeedHandler := handlers.NewPublicFeedHandler(db, cfg)
+	dogPageHandler := handlers.NewDogPageHandler(db, cfg)
+	roleHandler := handlers.NewRoleHandler(db, cfg)
+	auditHandler := handlers.NewAuditHandler(db, cfg)
+	twoFactorHandler := handlers.NewTwoFactorHandler(db, cfg)
+	sessionHandler := handlers.NewSessionHandler(db, cfg)
+	jwtKeyHandler := handlers.NewJWTKeyHandler(db, cfg)
+	uploadHandler := handlers.NewUploadHandler(db, cfg)
 	healthHandler := handlers.NewHealthHandler()
 	router.HandleFunc("/api/health", healthHandler.Health).Methods("GET")
 
@@ -143,7 +192,19 @@ func main() {
 	loginRoute := router.PathPrefix("/api/auth/login").Subrouter()
 	loginRoute.Use(middleware.RateLimitLogin)
 	loginRoute.HandleFunc("", authHandler.Login).Methods("POST")
+	loginRoute.HandleFunc("/2fa", authHandler.LoginTwoFactor).Methods("POST")
+	loginRoute.HandleFunc("/2fa/setup", authHandler.LoginTwoFactorSetup).Methods("POST")
+	loginRoute.HandleFunc("/magic-link", authHandler.LoginMagicLink).Methods("POST")
+	loginRoute.HandleFunc("/sso", authHandler.LoginSSO).Methods("POST")
 	// DONE: BUG #6 - Rate limiting applied to login
+	router.HandleFunc("/api/auth/methods", authHandler.GetAuthMethods).Methods("GET")
+	// Login links send emails, so they have their own, stricter limit
+	router.Handle("/api/auth/magic-link", middleware.RateLimitMagicLink(http.HandlerFunc(authHandler.RequestMagicLink))).Methods("POST")
+	// Single sign-on with OpenID Connect providers (browser redirects)
+	router.Handle("/api/auth/oidc/{provider}/login", middleware.RateLimitSSO(http.HandlerFunc(oidcHandler.Start))).Methods("GET")
+	router.HandleFunc("/api/auth/oidc/{provider}/callback", oidcHandler.Callback).Methods("GET")
+	router.HandleFunc("/api/auth/refresh", authHandler.Refresh).Methods("POST")
+	router.HandleFunc("/api/auth/logout", authHandler.Logout).Methods("POST")
 	router.HandleFunc("/api/auth/forgot-password", authHandler.ForgotPassword).Methods("POST")
 	router.HandleFunc("/api/auth/reset-password", authHandler.ResetPassword).Methods("POST")
 
@@ -158,30 +219,70 @@ func main() {
 	// Featured dogs (public - for homepage)
 	router.HandleFunc("/api/dogs/featured", dogHandler.GetFeaturedDogs).Methods("GET")
 
+	// Adoptable dogs feed and widget (public - for the shelter website)
+	router.HandleFunc("/api/public/dogs", publicFeedHandler.GetDogsJSON).Methods("GET")
+	router.HandleFunc("/api/public/dogs/rss", publicFeedHandler.GetDogsRSS).Methods("GET")
+	router.HandleFunc("/api/public/dogs/atom", publicFeedHandler.GetDogsAtom).Methods("GET")
+	router.HandleFunc("/api/public/dogs/widget", publicFeedHandler.GetDogsWidget).Methods("GET")
+
+	// Public keys of the JWT signing keys, for services that verify our tokens
+	router.HandleFunc("/.well-known/jwks.json", jwtKeyHandler.JWKS).Methods("GET")
+
+	// Profile and incident photos (public route, access is granted by the signed, expiring URL)
+	router.HandleFunc("/api/photos/users/{file}", userHandler.ServeProfilePhoto).Methods("GET")
+	router.HandleFunc("/api/photos/incidents/{file}", incidentHandler.ServeIncidentPhoto).Methods("GET")
+
 	// Protected routes (authenticated users)
 	protected := router.PathPrefix("/api").Subrouter()
-	protected.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+	protected.Use(middleware.AuthMiddlewareWithValidator(services.NewAuthServiceWithKeys(jwtKeys, cfg.JWTExpirationHours)))
+	protected.Use(middleware.RequireActiveSession(repository.NewSessionRepository(db)))
+	// Permissions from the user's roles, for handlers that show more to staff
+	roleRepo := repository.NewRoleRepository(db)
+	protected.Use(middleware.LoadPermissions(roleRepo))
 
 	// Auth
 	protected.HandleFunc("/auth/change-password", authHandler.ChangePassword).Methods("PUT")
+	protected.HandleFunc("/auth/logout-all", authHandler.LogoutAll).Methods("POST")
 
 	// Users
 	protected.HandleFunc("/users/me", userHandler.GetMe).Methods("GET")
 	protected.HandleFunc("/users/me", userHandler.UpdateMe).Methods("PUT")
 	protected.HandleFunc("/users/me/photo", userHandler.UploadPhoto).Methods("POST")
 	protected.HandleFunc("/users/me", userHandler.DeleteAccount).Methods("DELETE")
+	protected.HandleFunc("/users/me/notifications", dogFavoriteHandler.GetNotificationPreferences).Methods("GET")
+	protected.HandleFunc("/users/me/notifications", dogFavoriteHandler.UpdateNotificationPreferences).Methods("PUT")
+	protected.HandleFunc("/users/me/sessions", sessionHandler.ListSessions).Methods("GET")
+	protected.HandleFunc("/users/me/sessions/{id}", sessionHandler.RevokeSession).Methods("DELETE")
+	protected.HandleFunc("/users/me/2fa", twoFactorHandler.GetStatus).Methods("GET")
+	protected.HandleFunc("/users/me/2fa/setup", twoFactorHandler.Setup).Methods("POST")
+	protected.HandleFunc("/users/me/2fa/enable", twoFactorHandler.Enable).Methods("POST")
+	protected.HandleFunc("/users/me/2fa/disable", twoFactorHandler.Disable).Methods("POST")
+	protected.HandleFunc("/users/me/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes).Methods("POST")
 
 	// Dogs (read-only for authenticated users)
 	protected.HandleFunc("/dogs", dogHandler.ListDogs).Methods("GET")
 	protected.HandleFunc("/dogs/breeds", dogHandler.GetBreeds).Methods("GET")
+	protected.HandleFunc("/dogs/favorites", dogFavoriteHandler.ListFavorites).Methods("GET")
+	protected.HandleFunc("/dogs/facets", dogHandler.ListDogFacets).Methods("GET")
+	protected.HandleFunc("/dog-tags", dogTagHandler.ListTags).Methods("GET")
 	protected.HandleFunc("/dogs/{id}", dogHandler.GetDog).Methods("GET")
+	protected.HandleFunc("/dogs/{id}/compatible", dogHandler.GetCompatibleDogs).Methods("GET")
+	protected.HandleFunc("/dogs/{id}/photos", dogPhotoHandler.ListPhotos).Methods("GET")
+	protected.HandleFunc("/dogs/{id}/favorite", dogFavoriteHandler.SetFavorite).Methods("PUT")
+	protected.HandleFunc("/dogs/{id}/favorite", dogFavoriteHandler.RemoveFavorite).Methods("DELETE")
+	protected.HandleFunc("/dogs/{id}/restrictions/active", dogHealthHandler.GetActiveRestrictions).Methods("GET")
 
 	// Bookings (authenticated users)
 	protected.HandleFunc("/bookings", bookingHandler.ListBookings).Methods("GET")
 	protected.HandleFunc("/bookings", bookingHandler.CreateBooking).Methods("POST")
+	protected.HandleFunc("/bookings/open", bookingHandler.ListOpenBookings).Methods("GET")
 	protected.HandleFunc("/bookings/{id}", bookingHandler.GetBooking).Methods("GET")
+	protected.HandleFunc("/bookings/{id}/join", bookingHandler.JoinBooking).Methods("POST")
+	protected.HandleFunc("/bookings/{id}/leave", bookingHandler.LeaveBooking).Methods("POST")
 	protected.HandleFunc("/bookings/{id}/cancel", bookingHandler.CancelBooking).Methods("PUT")
 	protected.HandleFunc("/bookings/{id}/notes", bookingHandler.AddNotes).Methods("PUT")
+	protected.HandleFunc("/bookings/{id}/report", walkReportHandler.SaveReport).Methods("PUT")
+	protected.HandleFunc("/bookings/{id}/reports", walkReportHandler.GetBookingReports).Methods("GET")
 	protected.HandleFunc("/bookings/calendar/{year}/{month}", bookingHandler.GetCalendarData).Methods("GET")
 
 	// Blocked dates (read-only for authenticated users)
@@ -191,72 +292,155 @@ func main() {
 	protected.HandleFunc("/experience-requests", experienceHandler.CreateRequest).Methods("POST")
 	protected.HandleFunc("/experience-requests", experienceHandler.ListRequests).Methods("GET")
 
-	// Admin-only routes
-	admin := protected.PathPrefix("").Subrouter()
-	admin.Use(middleware.RequireAdmin)
-
-	// Dog management (admin only)
-	admin.HandleFunc("/dogs", dogHandler.CreateDog).Methods("POST")
-	admin.HandleFunc("/dogs/{id}", dogHandler.UpdateDog).Methods("PUT")
-	admin.HandleFunc("/dogs/{id}", dogHandler.DeleteDog).Methods("DELETE")
-	admin.HandleFunc("/dogs/{id}/photo", dogHandler.UploadDogPhoto).Methods("POST")
-	admin.HandleFunc("/dogs/{id}/availability", dogHandler.ToggleAvailability).Methods("PUT")
-	admin.HandleFunc("/dogs/{id}/featured", dogHandler.SetFeatured).Methods("PUT")
-
-	// Blocked dates management (admin only)
-	admin.HandleFunc("/blocked-dates", blockedDateHandler.CreateBlockedDate).Methods("POST")
-	admin.HandleFunc("/blocked-dates/{id}", blockedDateHandler.DeleteBlockedDate).Methods("DELETE")
-
-	// Booking management (admin only)
-	admin.HandleFunc("/bookings/{id}/move", bookingHandler.MoveBooking).Methods("PUT")
-
-	// System settings (admin only)
-	admin.HandleFunc("/settings", settingsHandler.GetAllSettings).Methods("GET")
-	admin.HandleFunc("/settings/{key}", settingsHandler.UpdateSetting).Methods("PUT")
-
-	// Experience requests management (admin only)
-	admin.HandleFunc("/experience-requests/{id}/approve", experienceHandler.ApproveRequest).Methods("PUT")
-	admin.HandleFunc("/experience-requests/{id}/deny", experienceHandler.DenyRequest).Methods("PUT")
-
-	// User management (admin only)
-	admin.HandleFunc("/users", userHandler.ListUsers).Methods("GET")
-	admin.HandleFunc("/users/{id}", userHandler.GetUser).Methods("GET")
-	admin.HandleFunc("/users/{id}/activate", userHandler.ActivateUser).Methods("PUT")
-	admin.HandleFunc("/users/{id}/deactivate", userHandler.DeactivateUser).Methods("PUT")
-
-	// Reactivation requests management (admin only)
-	admin.HandleFunc("/reactivation-requests", reactivationHandler.ListRequests).Methods("GET")
-	admin.HandleFunc("/reactivation-requests/{id}/approve", reactivationHandler.ApproveRequest).Methods("PUT")
-	admin.HandleFunc("/reactivation-requests/{id}/deny", reactivationHandler.DenyRequest).Methods("PUT")
-
-	// Admin dashboard (admin only)
-	admin.HandleFunc("/admin/stats", dashboardHandler.GetStats).Methods("GET")
-	admin.HandleFunc("/admin/activity", dashboardHandler.GetRecentActivity).Methods("GET")
-
-	// Booking time management (admin only)
-	admin.HandleFunc("/admin/booking-times/rules", bookingTimeHandler.GetRules).Methods("GET")
-	admin.HandleFunc("/admin/booking-times/rules", bookingTimeHandler.UpdateRules).Methods("PUT")
-	admin.HandleFunc("/admin/booking-times/rules", bookingTimeHandler.CreateRule).Methods("POST")
-	admin.HandleFunc("/admin/booking-times/rules/{id}", bookingTimeHandler.DeleteRule).Methods("DELETE")
-
-	// Holiday management (admin only)
-	admin.HandleFunc("/admin/holidays", holidayHandler.CreateHoliday).Methods("POST")
-	admin.HandleFunc("/admin/holidays/{id}", holidayHandler.UpdateHoliday).Methods("PUT")
-	admin.HandleFunc("/admin/holidays/{id}", holidayHandler.DeleteHoliday).Methods("DELETE")
-
-	// Booking approval management (admin only)
-	admin.HandleFunc("/bookings/pending-approvals", bookingHandler.GetPendingApprovals).Methods("GET")
-	admin.HandleFunc("/bookings/{id}/approve", bookingHandler.ApprovePendingBooking).Methods("PUT")
-	admin.HandleFunc("/bookings/{id}/reject", bookingHandler.RejectPendingBooking).Methods("PUT")
-
-	// DONE: Phase 4 - Super Admin routes (authenticated + admin + super admin)
-	superAdmin := admin.PathPrefix("").Subrouter()
+	// Incidents (walkers report and follow their own, admins see all)
+	protected.HandleFunc("/incidents", incidentHandler.ListIncidents).Methods("GET")
+	protected.HandleFunc("/incidents", incidentHandler.CreateIncident).Methods("POST")
+	protected.HandleFunc("/incidents/{id}", incidentHandler.GetIncident).Methods("GET")
+	protected.HandleFunc("/incidents/{id}/photos", incidentHandler.UploadIncidentPhoto).Methods("POST")
+	protected.HandleFunc("/incidents/{id}/comments", incidentHandler.AddComment).Methods("POST")
+
+	// Admin routes, grouped by the permission that roles grant (see models.Permission)
+	// The admin role has all permissions, super admins pass every check
+	requirePermission := func(permissions ...models.Permission) *mux.Router {
+		sub := protected.PathPrefix("").Subrouter()
+		sub.Use(middleware.RequirePermission(roleRepo, permissions...))
+		return sub
+	}
+	dogAdmin := requirePermission(models.PermissionManageDogs)
+	dogReports := requirePermission(models.PermissionManageDogs, models.PermissionViewReports)
+	blockedDateAdmin := requirePermission(models.PermissionManageBlockedDates)
+	bookingApprover := requirePermission(models.PermissionApproveBookings)
+	experienceApprover := requirePermission(models.PermissionApproveExperienceRequests)
+	userAdmin := requirePermission(models.PermissionManageUsers)
+	incidentAdmin := requirePermission(models.PermissionManageIncidents)
+	settingsAdmin := requirePermission(models.PermissionManageSettings)
+	reports := requirePermission(models.PermissionViewReports)
+	auditViewer := requirePermission(models.PermissionViewAudit)
+
+	// Dog management (dogs.manage)
+	dogAdmin.HandleFunc("/dogs", dogHandler.CreateDog).Methods("POST")
+	dogAdmin.HandleFunc("/dogs/{id}", dogHandler.UpdateDog).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}", dogHandler.DeleteDog).Methods("DELETE")
+	dogAdmin.HandleFunc("/dogs/{id}/photo", dogHandler.UploadDogPhoto).Methods("POST")
+	dogAdmin.HandleFunc("/dogs/{id}/photos", dogPhotoHandler.UploadPhotos).Methods("POST")
+	dogAdmin.HandleFunc("/dogs/{id}/photos/order", dogPhotoHandler.ReorderPhotos).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}/photos/{photoId}", dogPhotoHandler.UpdatePhoto).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}/photos/{photoId}", dogPhotoHandler.DeletePhoto).Methods("DELETE")
+	dogAdmin.HandleFunc("/dogs/{id}/availability", dogHandler.ToggleAvailability).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}/status", dogHandler.SetDogStatus).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}/status-history", dogHandler.GetDogStatusHistory).Methods("GET")
+	dogAdmin.HandleFunc("/dogs/{id}/featured", dogHandler.SetFeatured).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}/compatible", dogHandler.SetCompatibleDogs).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}/tags", dogTagHandler.SetDogTags).Methods("PUT")
+	dogAdmin.HandleFunc("/dog-tags", dogTagHandler.CreateTag).Methods("POST")
+	dogAdmin.HandleFunc("/dog-tags/{tagId}", dogTagHandler.UpdateTag).Methods("PUT")
+	dogAdmin.HandleFunc("/dog-tags/{tagId}", dogTagHandler.DeleteTag).Methods("DELETE")
+
+	// Walk reports and statistics of a dog (dogs.manage or reports.view)
+	dogReports.HandleFunc("/dogs/{id}/reports", walkReportHandler.GetDogReports).Methods("GET")
+	dogReports.HandleFunc("/dogs/{id}/reports/summary", walkReportHandler.GetDogReportSummary).Methods("GET")
+	dogReports.HandleFunc("/dogs/{id}/history", dogStatsHandler.GetDogHistory).Methods("GET")
+	dogReports.HandleFunc("/dogs/{id}/stats", dogStatsHandler.GetDogStats).Methods("GET")
+
+	// Dog health records (dogs.manage)
+	dogAdmin.HandleFunc("/dogs/{id}/health", dogHealthHandler.GetHealthRecords).Methods("GET")
+	dogAdmin.HandleFunc("/dogs/{id}/vaccinations", dogHealthHandler.CreateVaccination).Methods("POST")
+	dogAdmin.HandleFunc("/dogs/{id}/vaccinations/{recordId}", dogHealthHandler.UpdateVaccination).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}/vaccinations/{recordId}", dogHealthHandler.DeleteVaccination).Methods("DELETE")
+	dogAdmin.HandleFunc("/dogs/{id}/medications", dogHealthHandler.CreateMedication).Methods("POST")
+	dogAdmin.HandleFunc("/dogs/{id}/medications/{recordId}", dogHealthHandler.UpdateMedication).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}/medications/{recordId}", dogHealthHandler.DeleteMedication).Methods("DELETE")
+	dogAdmin.HandleFunc("/dogs/{id}/vet-appointments", dogHealthHandler.CreateVetAppointment).Methods("POST")
+	dogAdmin.HandleFunc("/dogs/{id}/vet-appointments/{recordId}", dogHealthHandler.UpdateVetAppointment).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}/vet-appointments/{recordId}", dogHealthHandler.DeleteVetAppointment).Methods("DELETE")
+	dogAdmin.HandleFunc("/dogs/{id}/restrictions", dogHealthHandler.CreateRestriction).Methods("POST")
+	dogAdmin.HandleFunc("/dogs/{id}/restrictions/{recordId}", dogHealthHandler.UpdateRestriction).Methods("PUT")
+	dogAdmin.HandleFunc("/dogs/{id}/restrictions/{recordId}", dogHealthHandler.DeleteRestriction).Methods("DELETE")
+
+	// Blocked dates management (blocked_dates.manage)
+	blockedDateAdmin.HandleFunc("/blocked-dates", blockedDateHandler.CreateBlockedDate).Methods("POST")
+	blockedDateAdmin.HandleFunc("/blocked-dates/{id}", blockedDateHandler.DeleteBlockedDate).Methods("DELETE")
+
+	// Booking management (bookings.approve)
+	bookingApprover.HandleFunc("/bookings/{id}/move", bookingHandler.MoveBooking).Methods("PUT")
+
+	// Incident review (incidents.manage)
+	incidentAdmin.HandleFunc("/incidents/{id}/status", incidentHandler.UpdateIncidentStatus).Methods("PUT")
+
+	// System settings (settings.manage)
+	settingsAdmin.HandleFunc("/settings", settingsHandler.GetAllSettings).Methods("GET")
+	settingsAdmin.HandleFunc("/settings/{key}", settingsHandler.UpdateSetting).Methods("PUT")
+
+	// Experience requests management (experience_requests.approve)
+	experienceApprover.HandleFunc("/experience-requests/{id}/approve", experienceHandler.ApproveRequest).Methods("PUT")
+	experienceApprover.HandleFunc("/experience-requests/{id}/deny", experienceHandler.DenyRequest).Methods("PUT")
+
+	// User management (users.manage)
+	userAdmin.HandleFunc("/users", userHandler.ListUsers).Methods("GET")
+	userAdmin.HandleFunc("/users/{id}", userHandler.GetUser).Methods("GET")
+	userAdmin.HandleFunc("/users/{id}/activate", userHandler.ActivateUser).Methods("PUT")
+	userAdmin.HandleFunc("/users/{id}/deactivate", userHandler.DeactivateUser).Methods("PUT")
+
+	// Reactivation requests management (users.manage)
+	userAdmin.HandleFunc("/reactivation-requests", reactivationHandler.ListRequests).Methods("GET")
+	userAdmin.HandleFunc("/reactivation-requests/{id}/approve", reactivationHandler.ApproveRequest).Methods("PUT")
+	userAdmin.HandleFunc("/reactivation-requests/{id}/deny", reactivationHandler.DenyRequest).Methods("PUT")
+
+	// Admin dashboard and reports (reports.view)
+	reports.HandleFunc("/admin/stats", dashboardHandler.GetStats).Methods("GET")
+	reports.HandleFunc("/admin/activity", dashboardHandler.GetRecentActivity).Methods("GET")
+	reports.HandleFunc("/admin/reports/neglected-dogs", dogStatsHandler.GetNeglectedDogs).Methods("GET")
+
+	// Audit log of admin actions (audit.view)
+	auditViewer.HandleFunc("/admin/audit", auditHandler.ListAuditLog).Methods("GET")
+	auditViewer.HandleFunc("/admin/audit/export", auditHandler.ExportAuditLog).Methods("GET")
+
+	// Dog bulk import and export (dogs.manage)
+	dogAdmin.HandleFunc("/admin/dogs/import", dogImportHandler.ImportDogs).Methods("POST")
+	dogAdmin.HandleFunc("/admin/dogs/export", dogImportHandler.ExportDogs).Methods("GET")
+
+	// Booking time management (settings.manage)
+	settingsAdmin.HandleFunc("/admin/booking-times/rules", bookingTimeHandler.GetRules).Methods("GET")
+	settingsAdmin.HandleFunc("/admin/booking-times/rules", bookingTimeHandler.UpdateRules).Methods("PUT")
+	settingsAdmin.HandleFunc("/admin/booking-times/rules", bookingTimeHandler.CreateRule).Methods("POST")
+	settingsAdmin.HandleFunc("/admin/booking-times/rules/{id}", bookingTimeHandler.DeleteRule).Methods("DELETE")
+
+	// Upload consistency check (settings.manage)
+	settingsAdmin.HandleFunc("/admin/uploads/check", uploadHandler.CheckUploads).Methods("GET")
+	settingsAdmin.HandleFunc("/admin/uploads/cleanup", uploadHandler.CleanupUploads).Methods("POST")
+
+	// Holiday management (blocked_dates.manage)
+	blockedDateAdmin.HandleFunc("/admin/holidays", holidayHandler.CreateHoliday).Methods("POST")
+	blockedDateAdmin.HandleFunc("/admin/holidays/{id}", holidayHandler.UpdateHoliday).Methods("PUT")
+	blockedDateAdmin.HandleFunc("/admin/holidays/{id}", holidayHandler.DeleteHoliday).Methods("DELETE")
+
+	// Booking approval management (bookings.approve)
+	bookingApprover.HandleFunc("/bookings/pending-approvals", bookingHandler.GetPendingApprovals).Methods("GET")
+	bookingApprover.HandleFunc("/bookings/{id}/approve", bookingHandler.ApprovePendingBooking).Methods("PUT")
+	bookingApprover.HandleFunc("/bookings/{id}/reject", bookingHandler.RejectPendingBooking).Methods("PUT")
+
+	// DONE: Phase 4 - Super Admin routes (authenticated + super admin)
+	superAdmin := protected.PathPrefix("").Subrouter()
 	superAdmin.Use(middleware.RequireSuperAdmin)
 	superAdmin.HandleFunc("/admin/users/{id}/promote", userHandler.PromoteToAdmin).Methods("POST")
 	superAdmin.HandleFunc("/admin/users/{id}/demote", userHandler.DemoteAdmin).Methods("POST")
+	superAdmin.HandleFunc("/admin/users/{id}/2fa", twoFactorHandler.ResetUserTwoFactor).Methods("DELETE")
+
+	// Roles and permissions (super admin only)
+	superAdmin.HandleFunc("/admin/permissions", roleHandler.ListPermissions).Methods("GET")
+	superAdmin.HandleFunc("/admin/roles", roleHandler.ListRoles).Methods("GET")
+	superAdmin.HandleFunc("/admin/roles", roleHandler.CreateRole).Methods("POST")
+	superAdmin.HandleFunc("/admin/roles/{id}", roleHandler.UpdateRole).Methods("PUT")
+	superAdmin.HandleFunc("/admin/roles/{id}", roleHandler.DeleteRole).Methods("DELETE")
+	superAdmin.HandleFunc("/admin/users/{id}/roles", roleHandler.GetUserRoles).Methods("GET")
+	superAdmin.HandleFunc("/admin/users/{id}/roles", roleHandler.SetUserRoles).Methods("PUT")
 
-	// Uploads directory (user photos, dog photos) - must remain on filesystem
-	router.PathPrefix("/uploads/").Handler(http.StripPrefix("/uploads/", http.FileServer(http.Dir("./uploads"))))
+	// JWT signing keys (super admin only)
+	superAdmin.HandleFunc("/admin/jwt-keys", jwtKeyHandler.ListKeys).Methods("GET")
+	superAdmin.HandleFunc("/admin/jwt-keys/rotate", jwtKeyHandler.RotateKey).Methods("POST")
+
+	// Uploads (dog photos) - served from the configured storage backend, private directories are hidden
+	router.PathPrefix("/uploads/").Handler(middleware.PrivateUploadsMiddleware(middleware.UploadCacheMiddleware(http.HandlerFunc(uploadHandler.ServeUpload))))
 
 	// Get embedded frontend filesystem
 	frontendFS, err := static.FrontendFS()
@@ -275,6 +459,9 @@ func main() {
 		serveEmbeddedFile(w, r, frontendFS, "forgot-password.html")
 	}).Methods("GET")
 
+	// Public dog pages with link previews, server-rendered for messengers
+	router.HandleFunc("/hund/{id:[0-9]+}", dogPageHandler.GetDogPage).Methods("GET")
+
 	// Static files from embedded frontend
 	router.PathPrefix("/").Handler(http.FileServer(http.FS(frontendFS)))
 
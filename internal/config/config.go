@@ -36,9 +36,23 @@ type Config struct {
 	JWTSecret          string
 	JWTExpirationHours int
 
+	// JWT signing keys: algorithm of new keys (HS256, EdDSA, RS256), optional PEM key file
+	// and how long retired keys still verify
+	JWTAlgorithm         string
+	JWTPrivateKeyFile    string
+	JWTKeyGraceHours     int
+	JWTKeyEncryptionKeys []string // encrypt stored private keys; the first encrypts, older ones still decrypt
+
+	// Login sessions: short-lived access tokens, renewed with rotating refresh tokens
+	AccessTokenMinutes int
+	RefreshTokenDays   int
+
 	// Super Admin (DONE: replaces ADMIN_EMAILS)
 	SuperAdminEmail string
 
+	// Single sign-on with OpenID Connect providers (OIDC_PROVIDERS)
+	OIDCProviders []OIDCProviderConfig
+
 	// Email Provider Selection
 	EmailProvider string // "gmail" or "smtp"
 
@@ -64,14 +78,25 @@ type Config struct {
 	UploadDir       string
 	MaxUploadSizeMB int
 
+	// Upload Storage
+	StorageBackend string // "local" or "s3"
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3UseSSL       bool
+	S3PublicURL    string
+
 	// System Settings
 	BookingAdvanceDays      int
 	CancellationNoticeHours int
 	AutoDeactivationDays    int
 
 	// Server
-	Port    string
-	BaseURL string // Base URL for email links (e.g., "https://gassigeher.com")
+	Port           string
+	BaseURL        string   // Base URL for email links (e.g., "https://gassigeher.com")
+	TrustedProxies []string // IPs or CIDRs of reverse proxies whose X-Forwarded-For header is trusted
 }
 
 // Load loads configuration from environment variables
@@ -98,12 +123,23 @@ func Load() *Config {
 		DBConnMaxLifetime: getEnvAsInt("DB_CONN_MAX_LIFETIME", 5), // Default: 5 minutes
 
 		// JWT
-		JWTSecret:          getEnv("JWT_SECRET", "change-this-in-production"),
-		JWTExpirationHours: getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
+		JWTSecret:            getEnv("JWT_SECRET", "change-this-in-production"),
+		JWTExpirationHours:   getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
+		JWTAlgorithm:         getEnv("JWT_ALGORITHM", "HS256"),
+		JWTPrivateKeyFile:    getEnv("JWT_PRIVATE_KEY_FILE", ""),
+		JWTKeyGraceHours:     getEnvAsInt("JWT_KEY_GRACE_HOURS", 24),
+		JWTKeyEncryptionKeys: splitList(getEnv("JWT_KEY_ENCRYPTION_KEY", ""), ","),
+
+		// Login sessions
+		AccessTokenMinutes: getEnvAsInt("ACCESS_TOKEN_MINUTES", 15),
+		RefreshTokenDays:   getEnvAsInt("REFRESH_TOKEN_DAYS", 30),
 
 		// Super Admin (DONE: replaces ADMIN_EMAILS)
 		SuperAdminEmail: getEnv("SUPER_ADMIN_EMAIL", ""),
 
+		// Single sign-on
+		OIDCProviders: loadOIDCProviders(),
+
 		// Email Provider (default: gmail for backward compatibility)
 		EmailProvider: getEnv("EMAIL_PROVIDER", "gmail"),
 
@@ -129,15 +165,91 @@ func Load() *Config {
 		UploadDir:       getEnv("UPLOAD_DIR", "./uploads"),
 		MaxUploadSizeMB: getEnvAsInt("MAX_UPLOAD_SIZE_MB", 5),
 
+		// Upload Storage (default: local for backward compatibility)
+		StorageBackend: getEnv("STORAGE_BACKEND", "local"),
+		S3Endpoint:     getEnv("S3_ENDPOINT", ""),
+		S3Region:       getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:       getEnv("S3_BUCKET", ""),
+		S3AccessKey:    getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:    getEnv("S3_SECRET_KEY", ""),
+		S3UseSSL:       getEnvAsBool("S3_USE_SSL", true),
+		S3PublicURL:    getEnv("S3_PUBLIC_URL", ""),
+
 		// System Settings
 		BookingAdvanceDays:      getEnvAsInt("BOOKING_ADVANCE_DAYS", 14),
 		CancellationNoticeHours: getEnvAsInt("CANCELLATION_NOTICE_HOURS", 12),
 		AutoDeactivationDays:    getEnvAsInt("AUTO_DEACTIVATION_DAYS", 365),
 
 		// Server
-		Port:    getEnv("PORT", "8080"),
-		BaseURL: getEnv("BASE_URL", "http://localhost:8080"),
+		Port:           getEnv("PORT", "8080"),
+		BaseURL:        getEnv("BASE_URL", "http://localhost:8080"),
+		TrustedProxies: splitList(getEnv("TRUSTED_PROXIES", ""), ","),
+	}
+}
+
+// OIDCProviderConfig configures an OpenID Connect provider for single sign-on
+// The settings of provider "google" are read from OIDC_GOOGLE_ISSUER, OIDC_GOOGLE_CLIENT_ID and so on
+type OIDCProviderConfig struct {
+	ID             string   // lower case, used in the URLs: /api/auth/oidc/{id}/login
+	Name           string   // shown on the login button
+	IssuerURL      string
+	ClientID       string
+	ClientSecret   string
+	Scopes         []string
+	AllowedDomains []string // optional: only email addresses of these domains may log in
+	AutoCreate     bool     // create accounts for unknown users on their first login
+	DefaultLevel   string   // experience level of created accounts
+	GroupsClaim    string   // ID token claim with the user's groups
+	GroupRoles     map[string][]string // IdP group -> role names, synced on every login
+}
+
+// loadOIDCProviders reads the providers listed in OIDC_PROVIDERS (comma-separated IDs)
+func loadOIDCProviders() []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, id := range splitList(os.Getenv("OIDC_PROVIDERS"), ",") {
+		id = strings.ToLower(id)
+		prefix := "OIDC_" + strings.ToUpper(id) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			ID:             id,
+			Name:           getEnv(prefix+"NAME", id),
+			IssuerURL:      strings.TrimSuffix(getEnv(prefix+"ISSUER", ""), "/"),
+			ClientID:       getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret:   getEnv(prefix+"CLIENT_SECRET", ""),
+			Scopes:         splitList(getEnv(prefix+"SCOPES", "openid email profile"), " "),
+			AllowedDomains: splitList(strings.ToLower(getEnv(prefix+"ALLOWED_DOMAINS", "")), ","),
+			AutoCreate:     getEnvAsBool(prefix+"AUTO_CREATE", false),
+			DefaultLevel:   getEnv(prefix+"DEFAULT_LEVEL", "green"),
+			GroupsClaim:    getEnv(prefix+"GROUPS_CLAIM", "groups"),
+			GroupRoles:     parseGroupRoles(getEnv(prefix+"GROUP_ROLES", "")),
+		})
+	}
+	return providers
+}
+
+// parseGroupRoles parses "group=role;group=role"; the last "=" separates the role,
+// so groups may be LDAP distinguished names
+func parseGroupRoles(value string) map[string][]string {
+	mapping := map[string][]string{}
+	for _, entry := range splitList(value, ";") {
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 || i == len(entry)-1 {
+			continue
+		}
+		group := strings.TrimSpace(entry[:i])
+		mapping[group] = append(mapping[group], strings.TrimSpace(entry[i+1:]))
+	}
+	return mapping
+}
+
+// splitList splits a list and drops empty entries
+func splitList(value, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
 
 // GetDBConfig builds a database configuration from the application config
@@ -2,6 +2,7 @@ package cron
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 	"strconv"
 	"time"
@@ -13,33 +14,54 @@ import (
 
 // CronService handles scheduled tasks
 type CronService struct {
-	db           *sql.DB
-	bookingRepo  *repository.BookingRepository
-	userRepo     *repository.UserRepository
-	settingsRepo *repository.SettingsRepository
-	emailService *services.EmailService
-	stopChan     chan bool
+	db            *sql.DB
+	bookingRepo   *repository.BookingRepository
+	userRepo      *repository.UserRepository
+	settingsRepo  *repository.SettingsRepository
+	healthRepo    *repository.DogHealthRepository
+	statsRepo     *repository.DogStatsRepository
+	emailService  *services.EmailService
+	uploadCheck   *services.UploadCleanupService
+	audit         *services.AuditService
+	sessionRepo   *repository.SessionRepository
+	magicLinkRepo *repository.MagicLinkRepository
+	oidcLoginRepo *repository.OIDCLoginRepository
+	stopChan      chan bool
 }
 
 // NewCronService creates a new cron service
 func NewCronService(db *sql.DB, cfg *config.Config) *CronService {
 	// Initialize email service for reminders (fail gracefully if not configured)
 	var emailService *services.EmailService
+	var uploadCheck *services.UploadCleanupService
 	if cfg != nil {
 		var err error
 		emailService, err = services.NewEmailService(services.ConfigToEmailConfig(cfg))
 		if err != nil {
 			log.Printf("Warning: Email service not available for cron jobs: %v", err)
 		}
+
+		uploadCheck = services.NewUploadCleanupService(
+			repository.NewUploadRepository(db),
+			repository.NewSettingsRepository(db),
+			services.NewStorageFromConfig(cfg),
+		)
 	}
 
 	return &CronService{
-		db:           db,
-		bookingRepo:  repository.NewBookingRepository(db),
-		userRepo:     repository.NewUserRepository(db),
-		settingsRepo: repository.NewSettingsRepository(db),
-		emailService: emailService,
-		stopChan:     make(chan bool),
+		db:            db,
+		bookingRepo:   repository.NewBookingRepository(db),
+		userRepo:      repository.NewUserRepository(db),
+		settingsRepo:  repository.NewSettingsRepository(db),
+		healthRepo:    repository.NewDogHealthRepository(db),
+		statsRepo:     repository.NewDogStatsRepository(db),
+		emailService:  emailService,
+		uploadCheck:   uploadCheck,
+		audit:         services.NewAuditService(repository.NewAuditLogRepository(db), repository.NewSettingsRepository(db)),
+		sessionRepo:   repository.NewSessionRepository(db),
+		magicLinkRepo: repository.NewMagicLinkRepository(db),
+		oidcLoginRepo: repository.NewOIDCLoginRepository(db),
+		stopChan:      make(chan bool),
 	}
 }
 
@@ -55,6 +77,30 @@ func (s *CronService) Start() {
 
 	// Run booking reminder job every 15 minutes
 	go s.runPeriodically("Send booking reminders", 15*time.Minute, s.sendBookingReminders)
+
+	// Run walk report reminder job every hour
+	go s.runPeriodically("Send walk report reminders", time.Hour, s.sendWalkReportReminders)
+
+	// Run vaccination expiry alert job daily at 7am (also runs once on startup)
+	go s.runDaily("Send vaccination expiry alerts", 7, 0, s.sendVaccinationExpiryAlerts)
+
+	// Run upload consistency check daily at 4am (also runs once on startup)
+	go s.runDaily("Check upload consistency", 4, 0, s.checkUploads)
+
+	// Delete audit log entries past the retention period daily at 4:30am (also runs once on startup)
+	go s.runDaily("Clean up audit log", 4, 30, s.cleanupAuditLog)
+
+	// Delete sessions that ended more than 30 days ago daily at 4:45am (also runs once on startup)
+	go s.runDaily("Clean up sessions", 4, 45, s.cleanupSessions)
+
+	// Delete login links a day after they expired daily at 4:50am (also runs once on startup)
+	go s.runDaily("Clean up login links", 4, 50, s.cleanupMagicLinks)
+
+	// Delete single sign-on logins a day after they expired daily at 4:55am (also runs once on startup)
+	go s.runDaily("Clean up SSO logins", 4, 55, s.cleanupOIDCLogins)
+
+	// Send neglected dogs report daily at 8am (not on startup, so restarts don't repeat it)
+	go s.scheduleDaily("Send neglected dogs report", 8, 0, s.sendNeglectedDogsReport)
 }
 
 // Stop stops all cron jobs
@@ -84,7 +130,10 @@ func (s *CronService) runPeriodically(name string, interval time.Duration, fn fu
 }
 
 // autoCompleteBookings marks past scheduled bookings as completed
+// Open group walks without enough walkers are cancelled first, so they are never completed
 func (s *CronService) autoCompleteBookings() {
+	s.cancelUnfilledOpenBookings()
+
 	count, err := s.bookingRepo.AutoComplete()
 	if err != nil {
 		log.Printf("Error auto-completing bookings: %v", err)
@@ -98,6 +147,61 @@ func (s *CronService) autoCompleteBookings() {
 	}
 }
 
+// cancelUnfilledOpenBookings cancels open group walks that still lack walkers open_walk_cancel_hours
+// before they start and tells the organizer and co-walkers, so nobody finds out at the shelter
+func (s *CronService) cancelUnfilledOpenBookings() {
+	hours := 2 // default
+	if setting, err := s.settingsRepo.Get("open_walk_cancel_hours"); err == nil && setting != nil {
+		if h, err := strconv.Atoi(setting.Value); err == nil && h > 0 {
+			hours = h
+		}
+	}
+
+	reason := "Keine qualifizierte Begleitperson gefunden"
+	cancelled, err := s.bookingRepo.CancelUnfilledOpenBookings(time.Now().Add(time.Duration(hours)*time.Hour), reason)
+	if err != nil {
+		log.Printf("Error cancelling unfilled open bookings: %v", err)
+	}
+	if len(cancelled) == 0 {
+		return
+	}
+	log.Printf("Cancelled %d open booking(s) without enough walkers", len(cancelled))
+
+	if s.emailService == nil {
+		return
+	}
+	for _, booking := range cancelled {
+		dogName := "Unbekannter Hund"
+		if booking.Dog != nil && booking.Dog.Name != "" {
+			dogName = booking.Dog.Name
+		}
+		for _, d := range booking.AdditionalDogs {
+			if d.Dog != nil && d.Dog.Name != "" {
+				dogName += " & " + d.Dog.Name
+			}
+		}
+
+		formattedDate := booking.Date
+		if t, err := time.Parse("2006-01-02", booking.Date); err == nil {
+			formattedDate = t.Format("02.01.2006")
+		}
+
+		if booking.User != nil && booking.User.Email != nil && *booking.User.Email != "" {
+			if err := s.emailService.SendAdminCancellation(*booking.User.Email, booking.User.Name, dogName, formattedDate, booking.ScheduledTime, reason); err != nil {
+				log.Printf("Error sending cancellation for booking %d: %v", booking.ID, err)
+			}
+		}
+		for _, p := range booking.Participants {
+			if p.User == nil || p.User.Email == nil || *p.User.Email == "" {
+				continue
+			}
+			if err := s.emailService.SendAdminCancellation(*p.User.Email, p.User.Name, dogName, formattedDate, booking.ScheduledTime, reason); err != nil {
+				log.Printf("Error sending cancellation for booking %d to co-walker %d: %v", booking.ID, p.UserID, err)
+			}
+		}
+	}
+}
+
 // sendBookingReminders sends reminders for upcoming bookings (1-2 hours before)
 func (s *CronService) sendBookingReminders() {
 	// Check if email service is available
@@ -134,6 +238,16 @@ func (s *CronService) sendBookingReminders() {
 			dogName = booking.Dog.Name
 		}
 
+		// Group walks: include additional dogs and remind all co-walkers
+		if err := s.bookingRepo.LoadGroup(booking); err != nil {
+			log.Printf("Error loading group walk for booking %d: %v", booking.ID, err)
+		}
+		for _, d := range booking.AdditionalDogs {
+			if d.Dog != nil && d.Dog.Name != "" {
+				dogName += " & " + d.Dog.Name
+			}
+		}
+
 		// Format date for German locale (DD.MM.YYYY)
 		formattedDate := booking.Date
 		if t, err := time.Parse("2006-01-02", booking.Date); err == nil {
@@ -154,6 +268,15 @@ func (s *CronService) sendBookingReminders() {
 			continue
 		}
 
+		for _, p := range booking.Participants {
+			if p.User == nil || p.User.Email == nil || *p.User.Email == "" {
+				continue
+			}
+			if err := s.emailService.SendBookingReminder(*p.User.Email, p.User.Name, dogName, formattedDate, booking.ScheduledTime); err != nil {
+				log.Printf("Error sending reminder for booking %d to co-walker %d: %v", booking.ID, p.UserID, err)
+			}
+		}
+
 		// Mark reminder as sent
 		if err := s.bookingRepo.MarkReminderSent(booking.ID); err != nil {
 			log.Printf("Error marking reminder sent for booking %d: %v", booking.ID, err)
@@ -165,12 +288,202 @@ func (s *CronService) sendBookingReminders() {
 	}
 }
 
+// sendWalkReportReminders reminds walkers to file a report for completed walks without one
+func (s *CronService) sendWalkReportReminders() {
+	if s.emailService == nil {
+		log.Println("Walk report reminder check: email service not configured, skipping")
+		return
+	}
+
+	hours := 24 // default
+	if setting, err := s.settingsRepo.Get("walk_report_reminder_hours"); err == nil && setting != nil {
+		if h, err := strconv.Atoi(setting.Value); err == nil && h > 0 {
+			hours = h
+		}
+	}
+
+	// Only look back one week past the reminder delay so old walks are never reminded
+	completedBefore := time.Now().Add(-time.Duration(hours) * time.Hour)
+	since := completedBefore.AddDate(0, 0, -7)
+
+	bookings, err := s.bookingRepo.GetForReportReminders(completedBefore, since)
+	if err != nil {
+		log.Printf("Error getting bookings for walk report reminders: %v", err)
+		return
+	}
+
+	if len(bookings) == 0 {
+		log.Println("Walk report reminder check: no reminders to send")
+		return
+	}
+
+	for _, booking := range bookings {
+		if booking.User == nil || booking.User.Email == nil {
+			log.Printf("Skipping walk report reminder for booking %d: no user email", booking.ID)
+			continue
+		}
+
+		dogName := "Unbekannter Hund"
+		if booking.Dog != nil && booking.Dog.Name != "" {
+			dogName = booking.Dog.Name
+		}
+
+		formattedDate := booking.Date
+		if t, err := time.Parse("2006-01-02", booking.Date); err == nil {
+			formattedDate = t.Format("02.01.2006")
+		}
+
+		if err := s.emailService.SendWalkReportReminder(*booking.User.Email, booking.User.Name, dogName, formattedDate); err != nil {
+			log.Printf("Error sending walk report reminder for booking %d: %v", booking.ID, err)
+			continue
+		}
+
+		if err := s.bookingRepo.MarkReportReminderSent(booking.ID); err != nil {
+			log.Printf("Error marking walk report reminder sent for booking %d: %v", booking.ID, err)
+			continue
+		}
+
+		log.Printf("Sent walk report reminder for booking %d (user: %s, dog: %s)", booking.ID, booking.User.Name, dogName)
+	}
+}
+
+// sendVaccinationExpiryAlerts emails admins about vaccinations that expire within the configured days
+// Each vaccination is alerted once; changing its expiry date re-arms the alert
+func (s *CronService) sendVaccinationExpiryAlerts() {
+	if s.emailService == nil {
+		log.Println("Vaccination expiry check: email service not configured, skipping")
+		return
+	}
+
+	days := 30 // default
+	if setting, err := s.settingsRepo.Get("vaccination_expiry_alert_days"); err == nil && setting != nil {
+		if d, err := strconv.Atoi(setting.Value); err == nil && d > 0 {
+			days = d
+		}
+	}
+
+	until := time.Now().AddDate(0, 0, days).Format("2006-01-02")
+	vaccinations, err := s.healthRepo.GetExpiringVaccinations(until)
+	if err != nil {
+		log.Printf("Error getting expiring vaccinations: %v", err)
+		return
+	}
+
+	if len(vaccinations) == 0 {
+		log.Println("Vaccination expiry check: no expiring vaccinations")
+		return
+	}
+
+	items := []string{}
+	for _, v := range vaccinations {
+		expiresOn := v.ExpiresOn
+		if t, err := time.Parse("2006-01-02", v.ExpiresOn); err == nil {
+			expiresOn = t.Format("02.01.2006")
+		}
+		items = append(items, fmt.Sprintf("%s: %s (gültig bis %s)", v.DogName, v.Name, expiresOn))
+	}
+
+	admins, err := s.userRepo.FindAdmins()
+	if err != nil {
+		log.Printf("Error getting admins for vaccination alerts: %v", err)
+		return
+	}
+
+	sent := false
+	for _, admin := range admins {
+		if admin.Email == nil || *admin.Email == "" {
+			continue
+		}
+		if err := s.emailService.SendVaccinationExpiryAlert(*admin.Email, admin.Name, items); err != nil {
+			log.Printf("Error sending vaccination expiry alert to admin %d: %v", admin.ID, err)
+			continue
+		}
+		sent = true
+	}
+
+	// Keep the vaccinations pending so the next run retries if no admin could be reached
+	if !sent {
+		return
+	}
+
+	for _, v := range vaccinations {
+		if err := s.healthRepo.MarkVaccinationAlertSent(v.ID); err != nil {
+			log.Printf("Error marking vaccination alert sent for vaccination %d: %v", v.ID, err)
+		}
+	}
+
+	log.Printf("Sent vaccination expiry alert for %d vaccination(s)", len(vaccinations))
+}
+
+// sendNeglectedDogsReport emails admins the bookable dogs without a walk in the configured days
+func (s *CronService) sendNeglectedDogsReport() {
+	if s.emailService == nil {
+		log.Println("Neglected dogs report: email service not configured, skipping")
+		return
+	}
+
+	days := 7 // default
+	if setting, err := s.settingsRepo.Get("neglected_dog_days"); err == nil && setting != nil {
+		if d, err := strconv.Atoi(setting.Value); err == nil && d > 0 {
+			days = d
+		}
+	}
+
+	dogs, err := s.statsRepo.FindNeglected(days, time.Now())
+	if err != nil {
+		log.Printf("Error getting neglected dogs: %v", err)
+		return
+	}
+
+	if len(dogs) == 0 {
+		log.Println("Neglected dogs report: every dog was walked recently")
+		return
+	}
+
+	items := []string{}
+	for _, dog := range dogs {
+		item := fmt.Sprintf("%s: noch nie ausgeführt (seit %d Tagen bei uns)", dog.Name, dog.DaysSinceLastWalk)
+		if dog.LastWalkDate != nil {
+			item = fmt.Sprintf("%s: seit %d Tagen nicht ausgeführt", dog.Name, dog.DaysSinceLastWalk)
+		}
+		if dog.NextWalkDate != nil {
+			if t, err := time.Parse("2006-01-02", *dog.NextWalkDate); err == nil {
+				item += fmt.Sprintf(", nächster Spaziergang am %s", t.Format("02.01.2006"))
+			}
+		}
+		items = append(items, item)
+	}
+
+	admins, err := s.userRepo.FindAdmins()
+	if err != nil {
+		log.Printf("Error getting admins for neglected dogs report: %v", err)
+		return
+	}
+
+	for _, admin := range admins {
+		if admin.Email == nil || *admin.Email == "" {
+			continue
+		}
+		if err := s.emailService.SendNeglectedDogsReport(*admin.Email, admin.Name, days, items); err != nil {
+			log.Printf("Error sending neglected dogs report to admin %d: %v", admin.ID, err)
+		}
+	}
+
+	log.Printf("Sent neglected dogs report with %d dog(s)", len(dogs))
+}
+
 // runDaily runs a function daily at a specific time (also runs once immediately on startup)
 func (s *CronService) runDaily(name string, hour, minute int, fn func()) {
 	// Run immediately on startup
 	log.Printf("Running daily job on startup: %s", name)
 	fn()
 
+	s.scheduleDaily(name, hour, minute, fn)
+}
+
+// scheduleDaily runs a function daily at a specific time, but not on startup
+// Used for jobs like reports that should not be repeated whenever the server restarts
+func (s *CronService) scheduleDaily(name string, hour, minute int, fn func()) {
 	for {
 		now := time.Now()
 		next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
@@ -230,7 +543,85 @@ func (s *CronService) autoDeactivateInactiveUsers() {
 			log.Printf("Error deactivating user %d: %v", user.ID, err)
 			continue
 		}
+		if _, err := s.sessionRepo.RevokeAllForUser(user.ID, 0); err != nil {
+			log.Printf("Error revoking sessions of user %d: %v", user.ID, err)
+		}
 
 		log.Printf("Auto-deactivated user %d (inactive for %d days)", user.ID, days)
 	}
 }
+
+// checkUploads logs orphaned and missing uploads
+// Orphans older than the grace period are deleted if orphaned_upload_auto_delete is enabled
+func (s *CronService) checkUploads() {
+	if s.uploadCheck == nil {
+		log.Println("Upload consistency check: storage not configured, skipping")
+		return
+	}
+
+	report, err := s.uploadCheck.Check(s.uploadCheck.GracePeriod(), s.uploadCheck.AutoDeleteEnabled())
+	if err != nil {
+		log.Printf("Error checking uploads: %v", err)
+		return
+	}
+
+	for _, missing := range report.Missing {
+		log.Printf("Upload consistency check: missing file %s (%s, id %d)", missing.Path, missing.Source, missing.RecordID)
+	}
+
+	log.Printf("Upload consistency check: %d files, %d references, %d orphaned (%d deleted), %d missing",
+		report.StoredFiles, report.References, len(report.Orphans), report.DeletedCount, len(report.Missing))
+}
+
+// cleanupAuditLog deletes audit log entries older than audit_log_retention_days
+func (s *CronService) cleanupAuditLog() {
+	deleted, err := s.audit.Cleanup()
+	if err != nil {
+		log.Printf("Error cleaning up audit log: %v", err)
+		return
+	}
+
+	if deleted > 0 {
+		log.Printf("Deleted %d audit log entries older than %d days", deleted, s.audit.RetentionDays())
+	}
+}
+
+// cleanupSessions deletes expired and revoked sessions after 30 days; until then,
+// reuse of their refresh tokens is still detected
+func (s *CronService) cleanupSessions() {
+	deleted, err := s.sessionRepo.DeleteEndedBefore(time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		log.Printf("Error cleaning up sessions: %v", err)
+		return
+	}
+
+	if deleted > 0 {
+		log.Printf("Deleted %d ended sessions", deleted)
+	}
+}
+
+// cleanupMagicLinks deletes login links a day after they expired; used links expire too
+func (s *CronService) cleanupMagicLinks() {
+	deleted, err := s.magicLinkRepo.DeleteExpiredBefore(time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		log.Printf("Error cleaning up login links: %v", err)
+		return
+	}
+
+	if deleted > 0 {
+		log.Printf("Deleted %d expired login links", deleted)
+	}
+}
+
+// cleanupOIDCLogins deletes single sign-on logins a day after they expired
+func (s *CronService) cleanupOIDCLogins() {
+	deleted, err := s.oidcLoginRepo.DeleteExpiredBefore(time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		log.Printf("Error cleaning up SSO logins: %v", err)
+		return
+	}
+
+	if deleted > 0 {
+		log.Printf("Deleted %d expired SSO logins", deleted)
+	}
+}
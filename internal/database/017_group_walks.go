@@ -0,0 +1,131 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "017_group_walks",
+		Description: "Add group walk support: additional walkers, additional dogs and dog compatibility rules",
+		Up: map[string]string{
+			"sqlite": `
+-- Additional walkers on a booking (the organizer stays in bookings.user_id)
+CREATE TABLE IF NOT EXISTS booking_participants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    booking_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(booking_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_booking_participants_user ON booking_participants(user_id);
+
+-- Additional dogs on a booking (the primary dog stays in bookings.dog_id)
+CREATE TABLE IF NOT EXISTS booking_dogs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    booking_id INTEGER NOT NULL,
+    dog_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    UNIQUE(booking_id, dog_id)
+);
+CREATE INDEX IF NOT EXISTS idx_booking_dogs_dog ON booking_dogs(dog_id);
+
+-- "Can walk with" rules, stored once per pair with dog_id < compatible_dog_id
+CREATE TABLE IF NOT EXISTS dog_compatibility (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dog_id INTEGER NOT NULL,
+    compatible_dog_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (compatible_dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    UNIQUE(dog_id, compatible_dog_id)
+);
+CREATE INDEX IF NOT EXISTS idx_dog_compatibility_compatible ON dog_compatibility(compatible_dog_id);
+
+-- Group walk limits
+INSERT OR IGNORE INTO system_settings (key, value) VALUES
+('max_dogs_per_booking', '2'),
+('max_walkers_per_booking', '3');
+`,
+			"mysql": `
+-- Additional walkers on a booking (the organizer stays in bookings.user_id)
+CREATE TABLE IF NOT EXISTS booking_participants (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    booking_id INT NOT NULL,
+    user_id INT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_booking_participant (booking_id, user_id),
+    INDEX idx_booking_participants_user (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Additional dogs on a booking (the primary dog stays in bookings.dog_id)
+CREATE TABLE IF NOT EXISTS booking_dogs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    booking_id INT NOT NULL,
+    dog_id INT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_booking_dog (booking_id, dog_id),
+    INDEX idx_booking_dogs_dog (dog_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- "Can walk with" rules, stored once per pair with dog_id < compatible_dog_id
+CREATE TABLE IF NOT EXISTS dog_compatibility (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    dog_id INT NOT NULL,
+    compatible_dog_id INT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (compatible_dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_dog_compatibility (dog_id, compatible_dog_id),
+    INDEX idx_dog_compatibility_compatible (compatible_dog_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Group walk limits
+INSERT IGNORE INTO system_settings (` + "`key`" + `, value) VALUES
+('max_dogs_per_booking', '2'),
+('max_walkers_per_booking', '3');
+`,
+			"postgres": `
+-- Additional walkers on a booking (the organizer stays in bookings.user_id)
+CREATE TABLE IF NOT EXISTS booking_participants (
+    id SERIAL PRIMARY KEY,
+    booking_id INTEGER NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(booking_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_booking_participants_user ON booking_participants(user_id);
+
+-- Additional dogs on a booking (the primary dog stays in bookings.dog_id)
+CREATE TABLE IF NOT EXISTS booking_dogs (
+    id SERIAL PRIMARY KEY,
+    booking_id INTEGER NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(booking_id, dog_id)
+);
+CREATE INDEX IF NOT EXISTS idx_booking_dogs_dog ON booking_dogs(dog_id);
+
+-- "Can walk with" rules, stored once per pair with dog_id < compatible_dog_id
+CREATE TABLE IF NOT EXISTS dog_compatibility (
+    id SERIAL PRIMARY KEY,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    compatible_dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(dog_id, compatible_dog_id)
+);
+CREATE INDEX IF NOT EXISTS idx_dog_compatibility_compatible ON dog_compatibility(compatible_dog_id);
+
+-- Group walk limits
+INSERT INTO system_settings (key, value) VALUES
+('max_dogs_per_booking', '2'),
+('max_walkers_per_booking', '3')
+ON CONFLICT (key) DO NOTHING;
+`,
+		},
+	})
+}
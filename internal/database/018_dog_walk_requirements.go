@@ -0,0 +1,28 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "018_dog_walk_requirements",
+		Description: "Add walk requirements to dogs (minimum walkers, co-walker level, staff escort)",
+		Up: map[string]string{
+			"sqlite": `
+-- Dogs that must not be walked alone or only with a qualified co-walker / staff member
+ALTER TABLE dogs ADD COLUMN min_walkers INTEGER DEFAULT 1;
+ALTER TABLE dogs ADD COLUMN min_co_walker_level TEXT;
+ALTER TABLE dogs ADD COLUMN requires_staff_escort INTEGER DEFAULT 0;
+`,
+			"mysql": `
+-- Dogs that must not be walked alone or only with a qualified co-walker / staff member
+ALTER TABLE dogs ADD COLUMN min_walkers INT NOT NULL DEFAULT 1;
+ALTER TABLE dogs ADD COLUMN min_co_walker_level VARCHAR(20);
+ALTER TABLE dogs ADD COLUMN requires_staff_escort TINYINT(1) NOT NULL DEFAULT 0;
+`,
+			"postgres": `
+-- Dogs that must not be walked alone or only with a qualified co-walker / staff member
+ALTER TABLE dogs ADD COLUMN min_walkers INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE dogs ADD COLUMN min_co_walker_level VARCHAR(20);
+ALTER TABLE dogs ADD COLUMN requires_staff_escort BOOLEAN NOT NULL DEFAULT FALSE;
+`,
+		},
+	})
+}
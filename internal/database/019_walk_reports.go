@@ -0,0 +1,104 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "019_walk_reports",
+		Description: "Add structured walk reports per booking and dog, with report reminder tracking",
+		Up: map[string]string{
+			"sqlite": `
+-- One report per walked dog of a completed booking
+CREATE TABLE IF NOT EXISTS walk_reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    booking_id INTEGER NOT NULL,
+    dog_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    leash_pulling INTEGER NOT NULL CHECK(leash_pulling BETWEEN 1 AND 5),
+    dog_reactivity INTEGER NOT NULL CHECK(dog_reactivity BETWEEN 1 AND 5),
+    people_reactivity INTEGER NOT NULL CHECK(people_reactivity BETWEEN 1 AND 5),
+    bathroom_pee INTEGER DEFAULT 0,
+    bathroom_poop INTEGER DEFAULT 0,
+    distance_km REAL,
+    had_incident INTEGER DEFAULT 0,
+    incident_description TEXT,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(booking_id, dog_id)
+);
+CREATE INDEX IF NOT EXISTS idx_walk_reports_dog ON walk_reports(dog_id, created_at);
+
+-- Track report reminder emails
+ALTER TABLE bookings ADD COLUMN report_reminder_sent_at DATETIME;
+
+INSERT OR IGNORE INTO system_settings (key, value) VALUES
+('walk_report_reminder_hours', '24');
+`,
+			"mysql": `
+-- One report per walked dog of a completed booking
+CREATE TABLE IF NOT EXISTS walk_reports (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    booking_id INT NOT NULL,
+    dog_id INT NOT NULL,
+    user_id INT NOT NULL,
+    leash_pulling TINYINT NOT NULL,
+    dog_reactivity TINYINT NOT NULL,
+    people_reactivity TINYINT NOT NULL,
+    bathroom_pee TINYINT(1) DEFAULT 0,
+    bathroom_poop TINYINT(1) DEFAULT 0,
+    distance_km DOUBLE,
+    had_incident TINYINT(1) DEFAULT 0,
+    incident_description TEXT,
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE CASCADE,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE KEY unique_walk_report (booking_id, dog_id),
+    INDEX idx_walk_reports_dog (dog_id, created_at),
+    CHECK (leash_pulling BETWEEN 1 AND 5),
+    CHECK (dog_reactivity BETWEEN 1 AND 5),
+    CHECK (people_reactivity BETWEEN 1 AND 5)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Track report reminder emails
+ALTER TABLE bookings ADD COLUMN report_reminder_sent_at DATETIME;
+
+INSERT IGNORE INTO system_settings (` + "`key`" + `, value) VALUES
+('walk_report_reminder_hours', '24');
+`,
+			"postgres": `
+-- One report per walked dog of a completed booking
+CREATE TABLE IF NOT EXISTS walk_reports (
+    id SERIAL PRIMARY KEY,
+    booking_id INTEGER NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    leash_pulling INTEGER NOT NULL CHECK(leash_pulling BETWEEN 1 AND 5),
+    dog_reactivity INTEGER NOT NULL CHECK(dog_reactivity BETWEEN 1 AND 5),
+    people_reactivity INTEGER NOT NULL CHECK(people_reactivity BETWEEN 1 AND 5),
+    bathroom_pee BOOLEAN DEFAULT FALSE,
+    bathroom_poop BOOLEAN DEFAULT FALSE,
+    distance_km DOUBLE PRECISION,
+    had_incident BOOLEAN DEFAULT FALSE,
+    incident_description TEXT,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(booking_id, dog_id)
+);
+CREATE INDEX IF NOT EXISTS idx_walk_reports_dog ON walk_reports(dog_id, created_at);
+
+-- Track report reminder emails
+ALTER TABLE bookings ADD COLUMN report_reminder_sent_at TIMESTAMP;
+
+INSERT INTO system_settings (key, value) VALUES
+('walk_report_reminder_hours', '24')
+ON CONFLICT (key) DO NOTHING;
+`,
+		},
+	})
+}
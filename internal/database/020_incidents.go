@@ -0,0 +1,162 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "020_incidents",
+		Description: "Add incident reports with photos, review comments and automatic dog actions",
+		Up: map[string]string{
+			"sqlite": `
+-- Incidents (bites, escapes, injuries) reported by walkers or staff
+CREATE TABLE IF NOT EXISTS incidents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dog_id INTEGER NOT NULL,
+    booking_id INTEGER,
+    reported_by INTEGER NOT NULL,
+    incident_type TEXT NOT NULL CHECK(incident_type IN ('bite', 'escape', 'injury', 'other')),
+    severity TEXT NOT NULL CHECK(severity IN ('low', 'medium', 'high', 'critical')),
+    description TEXT NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    status TEXT DEFAULT 'open' CHECK(status IN ('open', 'in_review', 'resolved', 'dismissed')),
+    resolution TEXT,
+    resolved_by INTEGER,
+    resolved_at TIMESTAMP,
+    dog_action TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL,
+    FOREIGN KEY (reported_by) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_incidents_dog ON incidents(dog_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_incidents_status ON incidents(status);
+
+CREATE TABLE IF NOT EXISTS incident_photos (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    incident_id INTEGER NOT NULL,
+    photo TEXT NOT NULL,
+    uploaded_by INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_incident_photos_incident ON incident_photos(incident_id);
+
+CREATE TABLE IF NOT EXISTS incident_comments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    incident_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    comment TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_incident_comments_incident ON incident_comments(incident_id);
+
+-- Incidents at or above this severity mark the dog unavailable ('none' disables)
+INSERT OR IGNORE INTO system_settings (key, value) VALUES
+('incident_auto_unavailable_severity', 'critical');
+`,
+			"mysql": `
+-- Incidents (bites, escapes, injuries) reported by walkers or staff
+CREATE TABLE IF NOT EXISTS incidents (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    dog_id INT NOT NULL,
+    booking_id INT,
+    reported_by INT NOT NULL,
+    incident_type VARCHAR(20) NOT NULL,
+    severity VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL,
+    occurred_at DATETIME NOT NULL,
+    status VARCHAR(20) DEFAULT 'open',
+    resolution TEXT,
+    resolved_by INT,
+    resolved_at DATETIME,
+    dog_action TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (booking_id) REFERENCES bookings(id) ON DELETE SET NULL,
+    FOREIGN KEY (reported_by) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (resolved_by) REFERENCES users(id) ON DELETE SET NULL,
+    INDEX idx_incidents_dog (dog_id, occurred_at),
+    INDEX idx_incidents_status (status),
+    CHECK (incident_type IN ('bite', 'escape', 'injury', 'other')),
+    CHECK (severity IN ('low', 'medium', 'high', 'critical')),
+    CHECK (status IN ('open', 'in_review', 'resolved', 'dismissed'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS incident_photos (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    incident_id INT NOT NULL,
+    photo VARCHAR(255) NOT NULL,
+    uploaded_by INT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_incident_photos_incident (incident_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS incident_comments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    incident_id INT NOT NULL,
+    user_id INT NOT NULL,
+    comment TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (incident_id) REFERENCES incidents(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    INDEX idx_incident_comments_incident (incident_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Incidents at or above this severity mark the dog unavailable ('none' disables)
+INSERT IGNORE INTO system_settings (` + "`key`" + `, value) VALUES
+('incident_auto_unavailable_severity', 'critical');
+`,
+			"postgres": `
+-- Incidents (bites, escapes, injuries) reported by walkers or staff
+CREATE TABLE IF NOT EXISTS incidents (
+    id SERIAL PRIMARY KEY,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    booking_id INTEGER REFERENCES bookings(id) ON DELETE SET NULL,
+    reported_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    incident_type VARCHAR(20) NOT NULL CHECK(incident_type IN ('bite', 'escape', 'injury', 'other')),
+    severity VARCHAR(20) NOT NULL CHECK(severity IN ('low', 'medium', 'high', 'critical')),
+    description TEXT NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) DEFAULT 'open' CHECK(status IN ('open', 'in_review', 'resolved', 'dismissed')),
+    resolution TEXT,
+    resolved_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    dog_action TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_incidents_dog ON incidents(dog_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_incidents_status ON incidents(status);
+
+CREATE TABLE IF NOT EXISTS incident_photos (
+    id SERIAL PRIMARY KEY,
+    incident_id INTEGER NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    photo VARCHAR(255) NOT NULL,
+    uploaded_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_incident_photos_incident ON incident_photos(incident_id);
+
+CREATE TABLE IF NOT EXISTS incident_comments (
+    id SERIAL PRIMARY KEY,
+    incident_id INTEGER NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    comment TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_incident_comments_incident ON incident_comments(incident_id);
+
+-- Incidents at or above this severity mark the dog unavailable ('none' disables)
+INSERT INTO system_settings (key, value) VALUES
+('incident_auto_unavailable_severity', 'critical')
+ON CONFLICT (key) DO NOTHING;
+`,
+		},
+	})
+}
@@ -0,0 +1,196 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "021_dog_health_records",
+		Description: "Add dog vaccinations, medications, vet appointments and walk restrictions",
+		Up: map[string]string{
+			"sqlite": `
+CREATE TABLE IF NOT EXISTS dog_vaccinations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dog_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    administered_on DATE,
+    expires_on DATE NOT NULL,
+    notes TEXT,
+    expiry_alert_sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_dog_vaccinations_dog ON dog_vaccinations(dog_id);
+CREATE INDEX IF NOT EXISTS idx_dog_vaccinations_expires ON dog_vaccinations(expires_on);
+
+-- dosage_times is a comma separated list of HH:MM; no walks within walk_buffer_minutes of a dose
+CREATE TABLE IF NOT EXISTS dog_medications (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dog_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    dosage TEXT,
+    dosage_times TEXT,
+    walk_buffer_minutes INTEGER DEFAULT 0,
+    start_date DATE,
+    end_date DATE,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_dog_medications_dog ON dog_medications(dog_id);
+
+CREATE TABLE IF NOT EXISTS dog_vet_appointments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dog_id INTEGER NOT NULL,
+    appointment_date DATE NOT NULL,
+    appointment_time TEXT,
+    vet_name TEXT,
+    purpose TEXT NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_dog_vet_appointments_dog ON dog_vet_appointments(dog_id, appointment_date);
+
+CREATE TABLE IF NOT EXISTS dog_restrictions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dog_id INTEGER NOT NULL,
+    restriction TEXT NOT NULL,
+    max_walk_minutes INTEGER,
+    valid_from DATE,
+    valid_until DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_dog_restrictions_dog ON dog_restrictions(dog_id);
+
+INSERT OR IGNORE INTO system_settings (key, value) VALUES
+('vaccination_expiry_alert_days', '30');
+`,
+			"mysql": `
+CREATE TABLE IF NOT EXISTS dog_vaccinations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    dog_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    administered_on DATE,
+    expires_on DATE NOT NULL,
+    notes TEXT,
+    expiry_alert_sent_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    INDEX idx_dog_vaccinations_dog (dog_id),
+    INDEX idx_dog_vaccinations_expires (expires_on)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- dosage_times is a comma separated list of HH:MM; no walks within walk_buffer_minutes of a dose
+CREATE TABLE IF NOT EXISTS dog_medications (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    dog_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    dosage VARCHAR(255),
+    dosage_times VARCHAR(255),
+    walk_buffer_minutes INT DEFAULT 0,
+    start_date DATE,
+    end_date DATE,
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    INDEX idx_dog_medications_dog (dog_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS dog_vet_appointments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    dog_id INT NOT NULL,
+    appointment_date DATE NOT NULL,
+    appointment_time VARCHAR(5),
+    vet_name VARCHAR(255),
+    purpose VARCHAR(255) NOT NULL,
+    notes TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    INDEX idx_dog_vet_appointments_dog (dog_id, appointment_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS dog_restrictions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    dog_id INT NOT NULL,
+    restriction VARCHAR(255) NOT NULL,
+    max_walk_minutes INT,
+    valid_from DATE,
+    valid_until DATE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    INDEX idx_dog_restrictions_dog (dog_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO system_settings (` + "`key`" + `, value) VALUES
+('vaccination_expiry_alert_days', '30');
+`,
+			"postgres": `
+CREATE TABLE IF NOT EXISTS dog_vaccinations (
+    id SERIAL PRIMARY KEY,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    administered_on DATE,
+    expires_on DATE NOT NULL,
+    notes TEXT,
+    expiry_alert_sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_dog_vaccinations_dog ON dog_vaccinations(dog_id);
+CREATE INDEX IF NOT EXISTS idx_dog_vaccinations_expires ON dog_vaccinations(expires_on);
+
+-- dosage_times is a comma separated list of HH:MM; no walks within walk_buffer_minutes of a dose
+CREATE TABLE IF NOT EXISTS dog_medications (
+    id SERIAL PRIMARY KEY,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    dosage VARCHAR(255),
+    dosage_times VARCHAR(255),
+    walk_buffer_minutes INTEGER DEFAULT 0,
+    start_date DATE,
+    end_date DATE,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_dog_medications_dog ON dog_medications(dog_id);
+
+CREATE TABLE IF NOT EXISTS dog_vet_appointments (
+    id SERIAL PRIMARY KEY,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    appointment_date DATE NOT NULL,
+    appointment_time VARCHAR(5),
+    vet_name VARCHAR(255),
+    purpose VARCHAR(255) NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_dog_vet_appointments_dog ON dog_vet_appointments(dog_id, appointment_date);
+
+CREATE TABLE IF NOT EXISTS dog_restrictions (
+    id SERIAL PRIMARY KEY,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    restriction VARCHAR(255) NOT NULL,
+    max_walk_minutes INTEGER,
+    valid_from DATE,
+    valid_until DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_dog_restrictions_dog ON dog_restrictions(dog_id);
+
+INSERT INTO system_settings (key, value) VALUES
+('vaccination_expiry_alert_days', '30')
+ON CONFLICT (key) DO NOTHING;
+`,
+		},
+	})
+}
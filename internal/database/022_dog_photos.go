@@ -0,0 +1,57 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "022_dog_photos",
+		Description: "Add dog photo gallery with captions, ordering and cover photo",
+		Up: map[string]string{
+			"sqlite": `
+-- The cover photo is mirrored into dogs.photo / dogs.photo_thumbnail for backward compatibility
+-- Existing dog photos are adopted as cover when a gallery is first used
+CREATE TABLE IF NOT EXISTS dog_photos (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dog_id INTEGER NOT NULL,
+    photo TEXT NOT NULL,
+    photo_thumbnail TEXT,
+    caption TEXT,
+    sort_order INTEGER DEFAULT 0,
+    is_cover INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_dog_photos_dog ON dog_photos(dog_id, sort_order);
+`,
+			"mysql": `
+-- The cover photo is mirrored into dogs.photo / dogs.photo_thumbnail for backward compatibility
+-- Existing dog photos are adopted as cover when a gallery is first used
+CREATE TABLE IF NOT EXISTS dog_photos (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    dog_id INT NOT NULL,
+    photo VARCHAR(255) NOT NULL,
+    photo_thumbnail VARCHAR(255),
+    caption VARCHAR(255),
+    sort_order INT DEFAULT 0,
+    is_cover TINYINT(1) DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    INDEX idx_dog_photos_dog (dog_id, sort_order)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+`,
+			"postgres": `
+-- The cover photo is mirrored into dogs.photo / dogs.photo_thumbnail for backward compatibility
+-- Existing dog photos are adopted as cover when a gallery is first used
+CREATE TABLE IF NOT EXISTS dog_photos (
+    id SERIAL PRIMARY KEY,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    photo VARCHAR(255) NOT NULL,
+    photo_thumbnail VARCHAR(255),
+    caption VARCHAR(255),
+    sort_order INTEGER DEFAULT 0,
+    is_cover BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_dog_photos_dog ON dog_photos(dog_id, sort_order);
+`,
+		},
+	})
+}
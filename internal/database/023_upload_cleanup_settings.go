@@ -0,0 +1,26 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "023_upload_cleanup_settings",
+		Description: "Add settings for the orphaned upload cleanup",
+		Up: map[string]string{
+			"sqlite": `
+INSERT OR IGNORE INTO system_settings (key, value) VALUES
+('orphaned_upload_grace_hours', '72'),
+('orphaned_upload_auto_delete', 'false');
+`,
+			"mysql": `
+INSERT IGNORE INTO system_settings (` + "`key`" + `, value) VALUES
+('orphaned_upload_grace_hours', '72'),
+('orphaned_upload_auto_delete', 'false');
+`,
+			"postgres": `
+INSERT INTO system_settings (key, value) VALUES
+('orphaned_upload_grace_hours', '72'),
+('orphaned_upload_auto_delete', 'false')
+ON CONFLICT (key) DO NOTHING;
+`,
+		},
+	})
+}
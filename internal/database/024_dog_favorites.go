@@ -0,0 +1,70 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "024_dog_favorites",
+		Description: "Add favourite dogs and availability notification subscriptions",
+		Up: map[string]string{
+			"sqlite": `
+CREATE TABLE IF NOT EXISTS dog_favorites (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    dog_id INTEGER NOT NULL,
+    notify_available INTEGER DEFAULT 0,
+    notify_free_slots INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    UNIQUE(user_id, dog_id)
+);
+CREATE INDEX IF NOT EXISTS idx_dog_favorites_dog ON dog_favorites(dog_id);
+
+-- Users who want to hear about new dogs of their experience level
+CREATE TABLE IF NOT EXISTS new_dog_subscriptions (
+    user_id INTEGER PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+`,
+			"mysql": `
+CREATE TABLE IF NOT EXISTS dog_favorites (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    dog_id INT NOT NULL,
+    notify_available TINYINT(1) DEFAULT 0,
+    notify_free_slots TINYINT(1) DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    UNIQUE KEY uq_dog_favorites_user_dog (user_id, dog_id),
+    INDEX idx_dog_favorites_dog (dog_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Users who want to hear about new dogs of their experience level
+CREATE TABLE IF NOT EXISTS new_dog_subscriptions (
+    user_id INT PRIMARY KEY,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+`,
+			"postgres": `
+CREATE TABLE IF NOT EXISTS dog_favorites (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    notify_available BOOLEAN DEFAULT FALSE,
+    notify_free_slots BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, dog_id)
+);
+CREATE INDEX IF NOT EXISTS idx_dog_favorites_dog ON dog_favorites(dog_id);
+
+-- Users who want to hear about new dogs of their experience level
+CREATE TABLE IF NOT EXISTS new_dog_subscriptions (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		},
+	})
+}
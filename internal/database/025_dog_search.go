@@ -0,0 +1,62 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "025_dog_search",
+		Description: "Add full-text search index for dogs (SQLite FTS5, MySQL FULLTEXT, PostgreSQL tsvector)",
+		Up: map[string]string{
+			"sqlite": `
+-- Holds German-normalised text (lowercase, ä -> ae, ß -> ss) written by the application
+-- history contains walk report notes and incidents and is only searched for admins
+-- The index is rebuilt at startup, so existing dogs are indexed on the next start
+CREATE VIRTUAL TABLE IF NOT EXISTS dog_search USING fts5(
+    dog_id UNINDEXED,
+    name,
+    breed,
+    details,
+    history,
+    tokenize = 'unicode61'
+);
+`,
+			"mysql": `
+-- Holds German-normalised text (lowercase, ä -> ae, ß -> ss) written by the application
+-- history contains walk report notes and incidents and is only searched for admins
+-- The index is rebuilt at startup, so existing dogs are indexed on the next start
+CREATE TABLE IF NOT EXISTS dog_search (
+    dog_id INT PRIMARY KEY,
+    name VARCHAR(255),
+    breed VARCHAR(255),
+    details TEXT,
+    history MEDIUMTEXT,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FULLTEXT INDEX ft_dog_search_name (name),
+    FULLTEXT INDEX ft_dog_search_breed (breed),
+    FULLTEXT INDEX ft_dog_search_public (name, breed, details),
+    FULLTEXT INDEX ft_dog_search_all (name, breed, details, history)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+`,
+			"postgres": `
+-- Holds German-normalised text (lowercase, ä -> ae, ß -> ss) written by the application
+-- history contains walk report notes and incidents and is only searched for admins
+-- The index is rebuilt at startup, so existing dogs are indexed on the next start
+CREATE TABLE IF NOT EXISTS dog_search (
+    dog_id INTEGER PRIMARY KEY REFERENCES dogs(id) ON DELETE CASCADE,
+    name TEXT,
+    breed TEXT,
+    details TEXT,
+    history TEXT,
+    document tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(breed, '')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(details, '')), 'C')
+    ) STORED,
+    history_document tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(history, '')), 'D')
+    ) STORED
+);
+CREATE INDEX IF NOT EXISTS idx_dog_search_document ON dog_search USING GIN (document);
+CREATE INDEX IF NOT EXISTS idx_dog_search_all ON dog_search USING GIN ((document || history_document));
+`,
+		},
+	})
+}
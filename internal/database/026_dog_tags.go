@@ -0,0 +1,84 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "026_dog_tags",
+		Description: "Add admin-managed dog tags (traits) for faceted filtering",
+		Up: map[string]string{
+			"sqlite": `
+CREATE TABLE IF NOT EXISTS dog_tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS dog_tag_assignments (
+    dog_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (dog_id, tag_id),
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES dog_tags(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_dog_tag_assignments_tag ON dog_tag_assignments(tag_id);
+
+-- Starter tags; admins can rename or delete them
+INSERT OR IGNORE INTO dog_tags (name, description) VALUES
+('Kinderfreundlich', 'Verträgt sich gut mit Kindern'),
+('Nicht mit Katzen', 'Darf Katzen nicht begegnen'),
+('Maulkorbpflicht', 'Muss beim Spaziergang einen Maulkorb tragen'),
+('Anfängerfreundlich', 'Gut geeignet für neue Gassigeher'),
+('Senior', 'Älterer Hund, ruhiges Tempo');
+`,
+			"mysql": `
+CREATE TABLE IF NOT EXISTS dog_tags (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS dog_tag_assignments (
+    dog_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    PRIMARY KEY (dog_id, tag_id),
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES dog_tags(id) ON DELETE CASCADE,
+    INDEX idx_dog_tag_assignments_tag (tag_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Starter tags; admins can rename or delete them
+INSERT IGNORE INTO dog_tags (name, description) VALUES
+('Kinderfreundlich', 'Verträgt sich gut mit Kindern'),
+('Nicht mit Katzen', 'Darf Katzen nicht begegnen'),
+('Maulkorbpflicht', 'Muss beim Spaziergang einen Maulkorb tragen'),
+('Anfängerfreundlich', 'Gut geeignet für neue Gassigeher'),
+('Senior', 'Älterer Hund, ruhiges Tempo');
+`,
+			"postgres": `
+CREATE TABLE IF NOT EXISTS dog_tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS dog_tag_assignments (
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES dog_tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (dog_id, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_dog_tag_assignments_tag ON dog_tag_assignments(tag_id);
+
+-- Starter tags; admins can rename or delete them
+INSERT INTO dog_tags (name, description) VALUES
+('Kinderfreundlich', 'Verträgt sich gut mit Kindern'),
+('Nicht mit Katzen', 'Darf Katzen nicht begegnen'),
+('Maulkorbpflicht', 'Muss beim Spaziergang einen Maulkorb tragen'),
+('Anfängerfreundlich', 'Gut geeignet für neue Gassigeher'),
+('Senior', 'Älterer Hund, ruhiges Tempo')
+ON CONFLICT (name) DO NOTHING;
+`,
+		},
+	})
+}
@@ -0,0 +1,65 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "027_dog_lifecycle",
+		Description: "Add dog lifecycle status (reserved, adopted, archived, ...) with status history",
+		Up: map[string]string{
+			"sqlite": `
+-- Lifecycle of a dog in the shelter; is_available stays the temporary availability toggle
+ALTER TABLE dogs ADD COLUMN status TEXT NOT NULL DEFAULT 'available';
+ALTER TABLE dogs ADD COLUMN status_changed_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_dogs_status ON dogs(status);
+
+CREATE TABLE IF NOT EXISTS dog_status_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    dog_id INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    note TEXT,
+    changed_by INTEGER,
+    cancelled_bookings INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_dog_status_changes_dog ON dog_status_changes(dog_id, created_at);
+`,
+			"mysql": `
+-- Lifecycle of a dog in the shelter; is_available stays the temporary availability toggle
+ALTER TABLE dogs ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'available';
+ALTER TABLE dogs ADD COLUMN status_changed_at DATETIME NULL;
+CREATE INDEX idx_dogs_status ON dogs(status);
+
+CREATE TABLE IF NOT EXISTS dog_status_changes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    dog_id INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    note TEXT,
+    changed_by INT NULL,
+    cancelled_bookings INT NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (dog_id) REFERENCES dogs(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id) ON DELETE SET NULL,
+    INDEX idx_dog_status_changes_dog (dog_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+`,
+			"postgres": `
+-- Lifecycle of a dog in the shelter; is_available stays the temporary availability toggle
+ALTER TABLE dogs ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'available';
+ALTER TABLE dogs ADD COLUMN status_changed_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_dogs_status ON dogs(status);
+
+CREATE TABLE IF NOT EXISTS dog_status_changes (
+    id SERIAL PRIMARY KEY,
+    dog_id INTEGER NOT NULL REFERENCES dogs(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    note TEXT,
+    changed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    cancelled_bookings INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_dog_status_changes_dog ON dog_status_changes(dog_id, created_at);
+`,
+		},
+	})
+}
@@ -0,0 +1,23 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "028_neglected_dog_report",
+		Description: "Add setting for the daily neglected dogs report",
+		Up: map[string]string{
+			"sqlite": `
+INSERT OR IGNORE INTO system_settings (key, value) VALUES
+('neglected_dog_days', '7');
+`,
+			"mysql": `
+INSERT IGNORE INTO system_settings (` + "`key`" + `, value) VALUES
+('neglected_dog_days', '7');
+`,
+			"postgres": `
+INSERT INTO system_settings (key, value) VALUES
+('neglected_dog_days', '7')
+ON CONFLICT (key) DO NOTHING;
+`,
+		},
+	})
+}
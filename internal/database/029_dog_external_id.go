@@ -0,0 +1,25 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "029_dog_external_id",
+		Description: "Add external ID to dogs for bulk import",
+		Up: map[string]string{
+			"sqlite": `
+-- ID of the dog in the shelter's own records, used to update dogs on re-import
+ALTER TABLE dogs ADD COLUMN external_id TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dogs_external_id ON dogs(external_id);
+`,
+			"mysql": `
+-- ID of the dog in the shelter's own records, used to update dogs on re-import
+ALTER TABLE dogs ADD COLUMN external_id VARCHAR(100) NULL;
+CREATE UNIQUE INDEX idx_dogs_external_id ON dogs(external_id);
+`,
+			"postgres": `
+-- ID of the dog in the shelter's own records, used to update dogs on re-import
+ALTER TABLE dogs ADD COLUMN external_id VARCHAR(100);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dogs_external_id ON dogs(external_id);
+`,
+		},
+	})
+}
@@ -0,0 +1,198 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "030_roles_permissions",
+		Description: "Add roles and permissions, and give existing admins the admin role",
+		Up: map[string]string{
+			"sqlite": `
+-- Permissions are checked in code; the table lists them and keeps role permissions valid
+CREATE TABLE IF NOT EXISTS permissions (
+    name TEXT PRIMARY KEY,
+    description TEXT
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    is_system INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INTEGER NOT NULL,
+    permission TEXT NOT NULL,
+    PRIMARY KEY (role_id, permission),
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    FOREIGN KEY (permission) REFERENCES permissions(name) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INTEGER NOT NULL,
+    role_id INTEGER NOT NULL,
+    assigned_by INTEGER,
+    assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    FOREIGN KEY (assigned_by) REFERENCES users(id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles(role_id);
+
+INSERT OR IGNORE INTO permissions (name, description) VALUES
+('dogs.manage', 'Hunde, Fotos, Tags, Gesundheitsdaten und Import verwalten'),
+('blocked_dates.manage', 'Gesperrte Tage und Feiertage verwalten'),
+('bookings.approve', 'Buchungen genehmigen, ablehnen und verschieben'),
+('experience_requests.approve', 'Anträge auf Erfahrungsstufen genehmigen'),
+('users.manage', 'Benutzer und Reaktivierungsanträge verwalten'),
+('incidents.manage', 'Vorfälle bearbeiten'),
+('settings.manage', 'Einstellungen, Buchungszeiten und Uploads verwalten'),
+('reports.view', 'Statistiken und Berichte ansehen');
+
+-- System roles cannot be deleted; admin always has all permissions
+INSERT OR IGNORE INTO roles (name, description, is_system) VALUES
+('admin', 'Volle Verwaltung', 1),
+('dog_coordinator', 'Hunde und gesperrte Tage verwalten, keine Benutzer', 1),
+('approver', 'Buchungen und Anträge auf Erfahrungsstufen genehmigen', 1),
+('reporting', 'Statistiken und Berichte nur lesen', 1);
+
+INSERT OR IGNORE INTO role_permissions (role_id, permission)
+SELECT r.id, p.name FROM roles r JOIN permissions p ON r.name = 'admin'
+    OR (r.name = 'dog_coordinator' AND p.name IN ('dogs.manage', 'blocked_dates.manage'))
+    OR (r.name = 'approver' AND p.name IN ('bookings.approve', 'experience_requests.approve'))
+    OR (r.name = 'reporting' AND p.name = 'reports.view');
+
+-- Existing admins get the admin role
+INSERT OR IGNORE INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM users u JOIN roles r ON r.name = 'admin' WHERE u.is_admin = 1;
+`,
+			"mysql": `
+-- Permissions are checked in code; the table lists them and keeps role permissions valid
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description VARCHAR(255)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS roles (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    is_system TINYINT(1) DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INT NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role_id, permission),
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    FOREIGN KEY (permission) REFERENCES permissions(name) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INT NOT NULL,
+    role_id INT NOT NULL,
+    assigned_by INT NULL,
+    assigned_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    FOREIGN KEY (assigned_by) REFERENCES users(id) ON DELETE SET NULL,
+    INDEX idx_user_roles_role (role_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO permissions (name, description) VALUES
+('dogs.manage', 'Hunde, Fotos, Tags, Gesundheitsdaten und Import verwalten'),
+('blocked_dates.manage', 'Gesperrte Tage und Feiertage verwalten'),
+('bookings.approve', 'Buchungen genehmigen, ablehnen und verschieben'),
+('experience_requests.approve', 'Anträge auf Erfahrungsstufen genehmigen'),
+('users.manage', 'Benutzer und Reaktivierungsanträge verwalten'),
+('incidents.manage', 'Vorfälle bearbeiten'),
+('settings.manage', 'Einstellungen, Buchungszeiten und Uploads verwalten'),
+('reports.view', 'Statistiken und Berichte ansehen');
+
+-- System roles cannot be deleted; admin always has all permissions
+INSERT IGNORE INTO roles (name, description, is_system) VALUES
+('admin', 'Volle Verwaltung', 1),
+('dog_coordinator', 'Hunde und gesperrte Tage verwalten, keine Benutzer', 1),
+('approver', 'Buchungen und Anträge auf Erfahrungsstufen genehmigen', 1),
+('reporting', 'Statistiken und Berichte nur lesen', 1);
+
+INSERT IGNORE INTO role_permissions (role_id, permission)
+SELECT r.id, p.name FROM roles r JOIN permissions p ON r.name = 'admin'
+    OR (r.name = 'dog_coordinator' AND p.name IN ('dogs.manage', 'blocked_dates.manage'))
+    OR (r.name = 'approver' AND p.name IN ('bookings.approve', 'experience_requests.approve'))
+    OR (r.name = 'reporting' AND p.name = 'reports.view');
+
+-- Existing admins get the admin role
+INSERT IGNORE INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM users u JOIN roles r ON r.name = 'admin' WHERE u.is_admin = 1;
+`,
+			"postgres": `
+-- Permissions are checked in code; the table lists them and keeps role permissions valid
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description VARCHAR(255)
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description VARCHAR(255),
+    is_system BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    assigned_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    assigned_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, role_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles(role_id);
+
+INSERT INTO permissions (name, description) VALUES
+('dogs.manage', 'Hunde, Fotos, Tags, Gesundheitsdaten und Import verwalten'),
+('blocked_dates.manage', 'Gesperrte Tage und Feiertage verwalten'),
+('bookings.approve', 'Buchungen genehmigen, ablehnen und verschieben'),
+('experience_requests.approve', 'Anträge auf Erfahrungsstufen genehmigen'),
+('users.manage', 'Benutzer und Reaktivierungsanträge verwalten'),
+('incidents.manage', 'Vorfälle bearbeiten'),
+('settings.manage', 'Einstellungen, Buchungszeiten und Uploads verwalten'),
+('reports.view', 'Statistiken und Berichte ansehen')
+ON CONFLICT (name) DO NOTHING;
+
+-- System roles cannot be deleted; admin always has all permissions
+INSERT INTO roles (name, description, is_system) VALUES
+('admin', 'Volle Verwaltung', TRUE),
+('dog_coordinator', 'Hunde und gesperrte Tage verwalten, keine Benutzer', TRUE),
+('approver', 'Buchungen und Anträge auf Erfahrungsstufen genehmigen', TRUE),
+('reporting', 'Statistiken und Berichte nur lesen', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT r.id, p.name FROM roles r JOIN permissions p ON r.name = 'admin'
+    OR (r.name = 'dog_coordinator' AND p.name IN ('dogs.manage', 'blocked_dates.manage'))
+    OR (r.name = 'approver' AND p.name IN ('bookings.approve', 'experience_requests.approve'))
+    OR (r.name = 'reporting' AND p.name = 'reports.view')
+ON CONFLICT (role_id, permission) DO NOTHING;
+
+-- Existing admins get the admin role
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM users u JOIN roles r ON r.name = 'admin' WHERE u.is_admin = TRUE
+ON CONFLICT (user_id, role_id) DO NOTHING;
+`,
+		},
+	})
+}
@@ -0,0 +1,94 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "031_audit_log",
+		Description: "Add audit log of admin actions, its permission and retention setting",
+		Up: map[string]string{
+			"sqlite": `
+-- No foreign keys: entries must outlive deleted users and records
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor_id INTEGER,
+    actor_email TEXT,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id INTEGER,
+    changes TEXT,
+    ip_address TEXT,
+    request_id TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target_type, target_id);
+
+INSERT OR IGNORE INTO permissions (name, description) VALUES
+('audit.view', 'Protokoll der Verwaltungsaktionen ansehen');
+
+INSERT OR IGNORE INTO role_permissions (role_id, permission)
+SELECT id, 'audit.view' FROM roles WHERE name = 'admin';
+
+INSERT OR IGNORE INTO system_settings (key, value) VALUES
+('audit_log_retention_days', '730');
+`,
+			"mysql": `
+-- No foreign keys: entries must outlive deleted users and records
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    actor_id INT NULL,
+    actor_email VARCHAR(255),
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id INT NULL,
+    changes TEXT,
+    ip_address VARCHAR(45),
+    request_id VARCHAR(64),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_log_created (created_at),
+    INDEX idx_audit_log_actor (actor_id),
+    INDEX idx_audit_log_target (target_type, target_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO permissions (name, description) VALUES
+('audit.view', 'Protokoll der Verwaltungsaktionen ansehen');
+
+INSERT IGNORE INTO role_permissions (role_id, permission)
+SELECT id, 'audit.view' FROM roles WHERE name = 'admin';
+
+INSERT IGNORE INTO system_settings (` + "`key`" + `, value) VALUES
+('audit_log_retention_days', '730');
+`,
+			"postgres": `
+-- No foreign keys: entries must outlive deleted users and records
+CREATE TABLE IF NOT EXISTS audit_log (
+    id SERIAL PRIMARY KEY,
+    actor_id INTEGER,
+    actor_email VARCHAR(255),
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id INTEGER,
+    changes TEXT,
+    ip_address VARCHAR(45),
+    request_id VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_target ON audit_log(target_type, target_id);
+
+INSERT INTO permissions (name, description) VALUES
+('audit.view', 'Protokoll der Verwaltungsaktionen ansehen')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT id, 'audit.view' FROM roles WHERE name = 'admin'
+ON CONFLICT (role_id, permission) DO NOTHING;
+
+INSERT INTO system_settings (key, value) VALUES
+('audit_log_retention_days', '730')
+ON CONFLICT (key) DO NOTHING;
+`,
+		},
+	})
+}
@@ -0,0 +1,84 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "032_two_factor_auth",
+		Description: "Add TOTP two-factor authentication, recovery codes and the setting to require it for admins",
+		Up: map[string]string{
+			"sqlite": `
+-- One row per user; enabled stays 0 until the first code has been confirmed
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id INTEGER PRIMARY KEY,
+    secret TEXT NOT NULL,
+    enabled INTEGER DEFAULT 0,
+    enabled_at TIMESTAMP,
+    last_used_step INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user ON user_recovery_codes(user_id);
+
+INSERT OR IGNORE INTO system_settings (key, value) VALUES
+('require_2fa_for_admins', 'false');
+`,
+			"mysql": `
+-- One row per user; enabled stays 0 until the first code has been confirmed
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id INT PRIMARY KEY,
+    secret VARCHAR(64) NOT NULL,
+    enabled TINYINT(1) DEFAULT 0,
+    enabled_at DATETIME NULL,
+    last_used_step BIGINT DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_recovery_codes_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO system_settings (` + "`key`" + `, value) VALUES
+('require_2fa_for_admins', 'false');
+`,
+			"postgres": `
+-- One row per user; enabled stays FALSE until the first code has been confirmed
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled BOOLEAN DEFAULT FALSE,
+    enabled_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user ON user_recovery_codes(user_id);
+
+INSERT INTO system_settings (key, value) VALUES
+('require_2fa_for_admins', 'false')
+ON CONFLICT (key) DO NOTHING;
+`,
+		},
+	})
+}
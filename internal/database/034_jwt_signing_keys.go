@@ -0,0 +1,49 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "034_jwt_signing_keys",
+		Description: "Add JWT signing keys identified by kid for key rotation",
+		Up: map[string]string{
+			"sqlite": `
+-- The newest key without retired_at signs new tokens; retired keys still verify during the grace period.
+-- private_key is empty for keys loaded from JWT_PRIVATE_KEY_FILE, which never leave the file
+CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+    kid TEXT PRIMARY KEY,
+    algorithm TEXT NOT NULL,
+    private_key TEXT,
+    public_key TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    retired_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_jwt_signing_keys_retired ON jwt_signing_keys(retired_at);
+`,
+			"mysql": `
+-- The newest key without retired_at signs new tokens; retired keys still verify during the grace period.
+-- private_key is empty for keys loaded from JWT_PRIVATE_KEY_FILE, which never leave the file
+CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+    kid VARCHAR(64) PRIMARY KEY,
+    algorithm VARCHAR(10) NOT NULL,
+    private_key TEXT NULL,
+    public_key TEXT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    retired_at DATETIME NULL,
+    INDEX idx_jwt_signing_keys_retired (retired_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+`,
+			"postgres": `
+-- The newest key without retired_at signs new tokens; retired keys still verify during the grace period.
+-- private_key is empty for keys loaded from JWT_PRIVATE_KEY_FILE, which never leave the file
+CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+    kid VARCHAR(64) PRIMARY KEY,
+    algorithm VARCHAR(10) NOT NULL,
+    private_key TEXT,
+    public_key TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    retired_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS idx_jwt_signing_keys_retired ON jwt_signing_keys(retired_at);
+`,
+		},
+	})
+}
@@ -0,0 +1,70 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "035_magic_link_login",
+		Description: "Add passwordless login with single-use email links",
+		Up: map[string]string{
+			"sqlite": `
+-- Only SHA-256 hashes are stored; device_hash binds a link to the browser that requested it
+CREATE TABLE IF NOT EXISTS magic_link_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    device_hash TEXT NOT NULL,
+    ip_address TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_magic_link_tokens_user ON magic_link_tokens(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_magic_link_tokens_expires ON magic_link_tokens(expires_at);
+
+INSERT OR IGNORE INTO system_settings (key, value) VALUES
+('magic_link_login_enabled', 'false'),
+('magic_link_expiry_minutes', '15');
+`,
+			"mysql": `
+-- Only SHA-256 hashes are stored; device_hash binds a link to the browser that requested it
+CREATE TABLE IF NOT EXISTS magic_link_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    device_hash VARCHAR(64) NOT NULL,
+    ip_address VARCHAR(45),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    INDEX idx_magic_link_tokens_user (user_id, created_at),
+    INDEX idx_magic_link_tokens_expires (expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO system_settings (` + "`key`" + `, value) VALUES
+('magic_link_login_enabled', 'false'),
+('magic_link_expiry_minutes', '15');
+`,
+			"postgres": `
+-- Only SHA-256 hashes are stored; device_hash binds a link to the browser that requested it
+CREATE TABLE IF NOT EXISTS magic_link_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    device_hash VARCHAR(64) NOT NULL,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS idx_magic_link_tokens_user ON magic_link_tokens(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_magic_link_tokens_expires ON magic_link_tokens(expires_at);
+
+INSERT INTO system_settings (key, value) VALUES
+('magic_link_login_enabled', 'false'),
+('magic_link_expiry_minutes', '15')
+ON CONFLICT (key) DO NOTHING;
+`,
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "036_oidc_sso",
+		Description: "Add single sign-on with OpenID Connect: linked identities and pending logins",
+		Up: map[string]string{
+			"sqlite": `
+-- Accounts of identity providers linked to users, by the provider's stable subject
+CREATE TABLE IF NOT EXISTS user_identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP,
+    UNIQUE (provider, subject),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_user_identities_user ON user_identities(user_id);
+
+-- Logins in progress: state, nonce and PKCE verifier until the callback,
+-- then the single-use code the browser exchanges for a session (only hashes are stored)
+CREATE TABLE IF NOT EXISTS oidc_logins (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    state_hash TEXT NOT NULL UNIQUE,
+    provider TEXT NOT NULL,
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    redirect_path TEXT,
+    user_id INTEGER,
+    login_code_hash TEXT UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    callback_at TIMESTAMP,
+    used_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_oidc_logins_expires ON oidc_logins(expires_at);
+`,
+			"mysql": `
+-- Accounts of identity providers linked to users, by the provider's stable subject
+CREATE TABLE IF NOT EXISTS user_identities (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_login_at DATETIME NULL,
+    UNIQUE KEY uq_user_identities_provider_subject (provider, subject),
+    INDEX idx_user_identities_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Logins in progress: state, nonce and PKCE verifier until the callback,
+-- then the single-use code the browser exchanges for a session (only hashes are stored)
+CREATE TABLE IF NOT EXISTS oidc_logins (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    state_hash VARCHAR(64) NOT NULL UNIQUE,
+    provider VARCHAR(50) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    redirect_path VARCHAR(500),
+    user_id INT NULL,
+    login_code_hash VARCHAR(64) UNIQUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL,
+    callback_at DATETIME NULL,
+    used_at DATETIME NULL,
+    INDEX idx_oidc_logins_expires (expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+`,
+			"postgres": `
+-- Accounts of identity providers linked to users, by the provider's stable subject
+CREATE TABLE IF NOT EXISTS user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (provider, subject)
+);
+CREATE INDEX IF NOT EXISTS idx_user_identities_user ON user_identities(user_id);
+
+-- Logins in progress: state, nonce and PKCE verifier until the callback,
+-- then the single-use code the browser exchanges for a session (only hashes are stored)
+CREATE TABLE IF NOT EXISTS oidc_logins (
+    id SERIAL PRIMARY KEY,
+    state_hash VARCHAR(64) NOT NULL UNIQUE,
+    provider VARCHAR(50) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    redirect_path VARCHAR(500),
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    login_code_hash VARCHAR(64) UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    callback_at TIMESTAMP WITH TIME ZONE,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS idx_oidc_logins_expires ON oidc_logins(expires_at);
+`,
+		},
+	})
+}
@@ -0,0 +1,33 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "037_staff_escort_permission",
+		Description: "Add the permission that makes a walker count as staff escort",
+		Up: map[string]string{
+			"sqlite": `
+INSERT OR IGNORE INTO permissions (name, description) VALUES
+('walks.escort', 'Als Begleitung durch Personal für Hunde mit Begleitpflicht zählen');
+
+INSERT OR IGNORE INTO role_permissions (role_id, permission)
+SELECT id, 'walks.escort' FROM roles WHERE name = 'admin';
+`,
+			"mysql": `
+INSERT IGNORE INTO permissions (name, description) VALUES
+('walks.escort', 'Als Begleitung durch Personal für Hunde mit Begleitpflicht zählen');
+
+INSERT IGNORE INTO role_permissions (role_id, permission)
+SELECT id, 'walks.escort' FROM roles WHERE name = 'admin';
+`,
+			"postgres": `
+INSERT INTO permissions (name, description) VALUES
+('walks.escort', 'Als Begleitung durch Personal für Hunde mit Begleitpflicht zählen')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT id, 'walks.escort' FROM roles WHERE name = 'admin'
+ON CONFLICT (role_id, permission) DO NOTHING;
+`,
+		},
+	})
+}
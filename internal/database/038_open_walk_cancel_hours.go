@@ -0,0 +1,23 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "038_open_walk_cancel_hours",
+		Description: "Add the setting when open walks without enough walkers are cancelled",
+		Up: map[string]string{
+			"sqlite": `
+INSERT OR IGNORE INTO system_settings (key, value) VALUES
+('open_walk_cancel_hours', '2');
+`,
+			"mysql": `
+INSERT IGNORE INTO system_settings (` + "`key`" + `, value) VALUES
+('open_walk_cancel_hours', '2');
+`,
+			"postgres": `
+INSERT INTO system_settings (key, value) VALUES
+('open_walk_cancel_hours', '2')
+ON CONFLICT (key) DO NOTHING;
+`,
+		},
+	})
+}
@@ -0,0 +1,22 @@
+package database
+
+func init() {
+	RegisterMigration(&Migration{
+		ID:          "039_two_factor_lockout",
+		Description: "Count wrong two-factor codes and lock the second login step after too many",
+		Up: map[string]string{
+			"sqlite": `
+ALTER TABLE user_totp ADD COLUMN failed_attempts INTEGER DEFAULT 0;
+ALTER TABLE user_totp ADD COLUMN locked_until TIMESTAMP;
+`,
+			"mysql": `
+ALTER TABLE user_totp ADD COLUMN failed_attempts INT DEFAULT 0;
+ALTER TABLE user_totp ADD COLUMN locked_until DATETIME NULL;
+`,
+			"postgres": `
+ALTER TABLE user_totp ADD COLUMN failed_attempts INTEGER DEFAULT 0;
+ALTER TABLE user_totp ADD COLUMN locked_until TIMESTAMP WITH TIME ZONE;
+`,
+		},
+	})
+}
@@ -14,8 +14,8 @@ import (
 func TestMigrationRegistry(t *testing.T) {
 	migrations := GetAllMigrations()
 
-	t.Run("All_15_migrations_registered", func(t *testing.T) {
-		assert.Len(t, migrations, 15, "Should have 15 migrations")
+	t.Run("All_38_migrations_registered", func(t *testing.T) {
+		assert.Len(t, migrations, 38, "Should have 38 migrations")
 	})
 
 	t.Run("Migrations_have_unique_IDs", func(t *testing.T) {
@@ -74,7 +74,7 @@ func TestRunMigrations_SQLite(t *testing.T) {
 	var count int
 	err = db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count)
 	assert.NoError(t, err)
-	assert.Equal(t, 15, count, "Should have 15 applied migrations")
+	assert.Equal(t, 38, count, "Should have 38 applied migrations")
 
 	// Verify all tables created
 	tables := []string{
@@ -87,10 +87,10 @@ func TestRunMigrations_SQLite(t *testing.T) {
 		assert.NoError(t, err, "Table %s should exist", table)
 	}
 
-	// Verify default settings inserted (3 from migration 008 + 5 from migration 012)
+	// Verify default settings inserted (3 from migration 008 + 5 from migration 012 + 2 from migration 017 + 1 from migration 019 + 1 from migration 020 + 1 from migration 021 + 2 from migration 023 + 1 from migration 028 + 1 from migration 031 + 1 from migration 032 + 2 from migration 035 + 1 from migration 038)
 	err = db.QueryRow("SELECT COUNT(*) FROM system_settings").Scan(&count)
 	assert.NoError(t, err)
-	assert.Equal(t, 8, count, "Should have 8 default settings")
+	assert.Equal(t, 21, count, "Should have 21 default settings")
 
 	// Verify photo_thumbnail column exists in dogs table
 	err = db.QueryRow(`
@@ -119,16 +119,16 @@ func TestRunMigrations_Idempotent(t *testing.T) {
 	var count int
 	err = db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count)
 	assert.NoError(t, err)
-	assert.Equal(t, 15, count)
+	assert.Equal(t, 38, count)
 
 	// Run migrations second time (should be idempotent)
 	err = RunMigrationsWithDialect(db, dialect)
 	assert.NoError(t, err, "Second migration run should succeed (idempotent)")
 
-	// Count should still be 15 (no duplicates)
+	// Count should stay the same (no duplicates)
 	err = db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count)
 	assert.NoError(t, err)
-	assert.Equal(t, 15, count, "Should still have 15 migrations (no duplicates)")
+	assert.Equal(t, 38, count, "Should still have 38 migrations (no duplicates)")
 }
 
 // TestGetMigrationStatus tests migration status reporting
@@ -146,7 +146,7 @@ func TestGetMigrationStatus(t *testing.T) {
 	applied, pending, err := GetMigrationStatus(db, dialect)
 	assert.NoError(t, err)
 	assert.Equal(t, 0, applied)
-	assert.Equal(t, 15, pending)
+	assert.Equal(t, 38, pending)
 
 	// After migrations
 	err = RunMigrationsWithDialect(db, dialect)
@@ -154,7 +154,7 @@ func TestGetMigrationStatus(t *testing.T) {
 
 	applied, pending, err = GetMigrationStatus(db, dialect)
 	assert.NoError(t, err)
-	assert.Equal(t, 15, applied)
+	assert.Equal(t, 38, applied)
 	assert.Equal(t, 0, pending)
 }
 
@@ -365,6 +365,29 @@ func TestMigrationOrder(t *testing.T) {
 		"014_add_featured_dogs",
 		"015_add_external_link",
 		"016_add_reminder_sent",
+		"017_group_walks",
+		"018_dog_walk_requirements",
+		"019_walk_reports",
+		"020_incidents",
+		"021_dog_health_records",
+		"022_dog_photos",
+		"023_upload_cleanup_settings",
+		"024_dog_favorites",
+		"025_dog_search",
+		"026_dog_tags",
+		"027_dog_lifecycle",
+		"028_neglected_dog_report",
+		"029_dog_external_id",
+		"030_roles_permissions",
+		"031_audit_log",
+		"032_two_factor_auth",
+		"033_user_sessions",
+		"034_jwt_signing_keys",
+		"035_magic_link_login",
+		"036_oidc_sso",
+		"037_staff_escort_permission",
+		"038_open_walk_cancel_hours",
+		"039_two_factor_lockout",
 	}
 
 	assert.Len(t, migrations, len(expectedOrder))
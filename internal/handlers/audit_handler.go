@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/logging"
+	"github.com/tranmh/gassigeher/internal/middleware"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// auditExportColumns are the column headers of the audit log CSV export
+var auditExportColumns = []string{"id", "created_at", "actor_id", "actor_email", "action", "target_type", "target_id", "changes", "ip_address", "request_id"}
+
+// AuditHandler handles the audit log of admin actions
+type AuditHandler struct {
+	db        *sql.DB
+	cfg       *config.Config
+	auditRepo *repository.AuditLogRepository
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(db *sql.DB, cfg *config.Config) *AuditHandler {
+	return &AuditHandler{
+		db:        db,
+		cfg:       cfg,
+		auditRepo: repository.NewAuditLogRepository(db),
+	}
+}
+
+// ListAuditLog handles GET /api/admin/audit - filtered, paginated audit log (audit.view)
+func (h *AuditHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseAuditFilter(w, r)
+	if !ok {
+		return
+	}
+
+	filter.Limit = 50
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > 500 {
+			respondError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+			return
+		}
+		filter.Limit = limit
+	}
+	if value := r.URL.Query().Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			respondError(w, http.StatusBadRequest, "offset must not be negative")
+			return
+		}
+		filter.Offset = offset
+	}
+
+	page, err := h.auditRepo.Find(filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get audit log")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, page)
+}
+
+// ExportAuditLog handles GET /api/admin/audit/export - all entries matching the filter as CSV (audit.view)
+func (h *AuditHandler) ExportAuditLog(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseAuditFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.auditRepo.Find(filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get audit log")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := writeAuditCSV(&buf, page.Entries); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to export audit log")
+		return
+	}
+
+	filename := fmt.Sprintf("audit-log-%s.csv", time.Now().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// parseAuditFilter reads the filter query parameters: actor_id, action, target_type, target_id,
+// from and to (YYYY-MM-DD, both inclusive)
+func parseAuditFilter(w http.ResponseWriter, r *http.Request) (*models.AuditLogFilter, bool) {
+	query := r.URL.Query()
+	filter := &models.AuditLogFilter{
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+	}
+
+	for param, target := range map[string]**int{"actor_id": &filter.ActorID, "target_id": &filter.TargetID} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid "+param)
+			return nil, false
+		}
+		*target = &id
+	}
+
+	from, to, ok := parseDateRange(w, r)
+	if !ok {
+		return nil, false
+	}
+	if from != "" {
+		start, _ := time.ParseInLocation("2006-01-02", from, time.Local)
+		filter.From = &start
+	}
+	if to != "" {
+		end, _ := time.ParseInLocation("2006-01-02", to, time.Local)
+		end = end.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	return filter, true
+}
+
+// writeAuditCSV writes the entries as CSV with a UTF-8 BOM, so Excel detects the encoding
+func writeAuditCSV(out io.Writer, entries []*models.AuditLogEntry) error {
+	if _, err := io.WriteString(out, "\ufeff"); err != nil {
+		return err
+	}
+
+	optionalInt := func(value *int) string {
+		if value == nil {
+			return ""
+		}
+		return strconv.Itoa(*value)
+	}
+	optional := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+
+	writer := csv.NewWriter(out)
+	if err := writer.Write(auditExportColumns); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		changes := ""
+		if len(entry.Changes) > 0 {
+			data, err := json.Marshal(entry.Changes)
+			if err != nil {
+				return err
+			}
+			changes = string(data)
+		}
+		if err := writer.Write([]string{
+			strconv.Itoa(entry.ID),
+			entry.CreatedAt.Format(time.RFC3339),
+			optionalInt(entry.ActorID),
+			optional(entry.ActorEmail),
+			entry.Action,
+			entry.TargetType,
+			optionalInt(entry.TargetID),
+			changes,
+			optional(entry.IPAddress),
+			optional(entry.RequestID),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// newAuditService creates the audit service used by handlers that change data on behalf of admins
+func newAuditService(db *sql.DB) *services.AuditService {
+	return services.NewAuditService(repository.NewAuditLogRepository(db), repository.NewSettingsRepository(db))
+}
+
+// auditActor returns the user, client IP and request ID of a request for the audit log
+func auditActor(r *http.Request) *models.AuditActor {
+	actor := &models.AuditActor{IPAddress: logging.GetClientIP(r)}
+	if userID, ok := r.Context().Value(middleware.UserIDKey).(int); ok {
+		actor.UserID = &userID
+	}
+	actor.Email, _ = r.Context().Value(middleware.EmailKey).(string)
+	actor.RequestID, _ = r.Context().Value(middleware.RequestIDKey).(string)
+	return actor
+}
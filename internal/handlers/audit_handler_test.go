@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/middleware"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestAuditHandler tests that admin actions are recorded and can be listed, filtered and exported
+func TestAuditHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewAuditHandler(db, cfg)
+	userHandler := NewUserHandler(db, cfg)
+	settingsHandler := NewSettingsHandler(db, cfg)
+
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	userID := testutil.SeedTestUser(t, db, "helfer@example.com", "Helfer", "green")
+
+	call := func(fn http.HandlerFunc, method, target string, vars map[string]string, reqBody interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		req.RemoteAddr = "192.0.2.10:4321"
+		req = mux.SetURLVars(req, vars)
+		ctx := contextWithUser(req.Context(), adminID, "admin@example.com", true)
+		req = req.WithContext(context.WithValue(ctx, middleware.RequestIDKey, "req-abc"))
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+
+	rec := call(userHandler.DeactivateUser, "PUT", "/api/users/2/deactivate", map[string]string{"id": fmt.Sprint(userID)},
+		map[string]string{"reason": "Keine Rückmeldung"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected deactivation to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = call(settingsHandler.UpdateSetting, "PUT", "/api/settings/booking_advance_days", map[string]string{"key": "booking_advance_days"},
+		models.UpdateSettingRequest{Value: "21"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected setting update to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	t.Run("list with filter", func(t *testing.T) {
+		rec := call(handler.ListAuditLog, "GET", "/api/admin/audit?target_type=user&target_id="+fmt.Sprint(userID), nil, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+
+		var page models.AuditLogPage
+		json.Unmarshal(rec.Body.Bytes(), &page)
+		if page.Total != 1 || page.Limit != 50 {
+			t.Fatalf("Expected 1 entry with the default limit, got %+v", page)
+		}
+		entry := page.Entries[0]
+		if entry.Action != models.AuditUserDeactivate || *entry.ActorID != adminID || *entry.ActorEmail != "admin@example.com" {
+			t.Errorf("Unexpected entry: %+v", entry)
+		}
+		if *entry.IPAddress != "192.0.2.10" || *entry.RequestID != "req-abc" {
+			t.Errorf("Expected IP and request ID of the request, got %v / %v", *entry.IPAddress, *entry.RequestID)
+		}
+		if entry.Changes["is_active"].Before != true || entry.Changes["deactivation_reason"].After != "Keine Rückmeldung" {
+			t.Errorf("Unexpected changes: %v", entry.Changes)
+		}
+	})
+
+	t.Run("setting changes", func(t *testing.T) {
+		rec := call(handler.ListAuditLog, "GET", "/api/admin/audit?action=setting.update", nil, nil)
+		var page models.AuditLogPage
+		json.Unmarshal(rec.Body.Bytes(), &page)
+		if page.Total != 1 || page.Entries[0].Changes["booking_advance_days"].After != "21" {
+			t.Errorf("Expected the setting change to be recorded, got %+v", page.Entries)
+		}
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"limit=0", "limit=501", "offset=-1", "actor_id=abc", "from=18.10.2026"} {
+			if rec := call(handler.ListAuditLog, "GET", "/api/admin/audit?"+query, nil, nil); rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, rec.Code)
+			}
+		}
+	})
+
+	t.Run("csv export", func(t *testing.T) {
+		rec := call(handler.ExportAuditLog, "GET", "/api/admin/audit/export", nil, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Header().Get("Content-Disposition"), "audit-log-") {
+			t.Errorf("Unexpected Content-Disposition: %s", rec.Header().Get("Content-Disposition"))
+		}
+
+		records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(rec.Body.String(), "\ufeff"))).ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to parse CSV: %v", err)
+		}
+		if len(records) != 3 || records[0][4] != "action" {
+			t.Errorf("Expected header and 2 rows, got %v", records)
+		}
+	})
+}
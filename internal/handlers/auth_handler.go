@@ -3,12 +3,15 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/logging"
 	"github.com/tranmh/gassigeher/internal/middleware"
 	"github.com/tranmh/gassigeher/internal/models"
 	"github.com/tranmh/gassigeher/internal/repository"
@@ -20,6 +23,10 @@ type AuthHandler struct {
 	userRepo     *repository.UserRepository
 	authService  *services.AuthService
 	emailService *services.EmailService
+	twoFactor    *services.TwoFactorService
+	sessions     *services.SessionService
+	magicLinks   *services.MagicLinkService
+	oidc         *services.OIDCService
 	config       *config.Config
 }
 
@@ -31,11 +38,22 @@ func NewAuthHandler(db *sql.DB, cfg *config.Config) *AuthHandler {
 		fmt.Printf("Warning: Failed to initialize email service: %v\n", err)
 	}
 
+	authService := newAuthService(db, cfg)
 	return &AuthHandler{
 		userRepo:     repository.NewUserRepository(db),
-		authService:  services.NewAuthService(cfg.JWTSecret, cfg.JWTExpirationHours),
+		authService:  authService,
 		emailService: emailService,
-		config:       cfg,
+		twoFactor:    newTwoFactorService(db),
+		sessions:     newSessionService(db, cfg),
+		magicLinks: services.NewMagicLinkService(
+			repository.NewMagicLinkRepository(db),
+			repository.NewUserRepository(db),
+			repository.NewSettingsRepository(db),
+			authService,
+			emailService,
+		),
+		oidc:   newOIDCService(db, cfg),
+		config: cfg,
 	}
 }
 
@@ -229,27 +247,335 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.continueLogin(w, r, user)
+}
+
+// continueLogin finishes the first login step of a user, by password or login link
+// Second step: with 2FA enabled (or required but not set up yet) only a pre-auth token is issued
+func (h *AuthHandler) continueLogin(w http.ResponseWriter, r *http.Request, user *models.User) {
+	twoFactorEnabled, err := h.twoFactor.IsEnabled(user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if twoFactorEnabled || h.twoFactor.IsRequired(user) {
+		preAuthToken, err := h.authService.GeneratePreAuthToken(user.ID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to generate token")
+			return
+		}
+		respondJSON(w, http.StatusOK, models.LoginResponse{
+			TwoFactorRequired:      twoFactorEnabled,
+			TwoFactorSetupRequired: !twoFactorEnabled,
+			PreAuthToken:           preAuthToken,
+		})
+		return
+	}
+
+	h.completeLogin(w, r, user, nil)
+}
+
+// GetAuthMethods handles GET /api/auth/methods - the login methods enabled on this instance
+func (h *AuthHandler) GetAuthMethods(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, models.AuthMethods{
+		Password:     true,
+		MagicLink:    h.magicLinks.IsEnabled(),
+		SSOProviders: h.oidc.Providers(),
+	})
+}
+
+// RequestMagicLink handles POST /api/auth/magic-link - sends a single-use login link by email
+// The response is the same whether the account exists or not; the returned device token
+// must be sent along with the link's token, so the link only works in this browser
+func (h *AuthHandler) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req models.MagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Email) == "" {
+		respondError(w, http.StatusBadRequest, "Email is required")
+		return
+	}
+
+	link, err := h.magicLinks.Request(strings.TrimSpace(req.Email), logging.GetClientIP(r))
+	if errors.Is(err, services.ErrMagicLinkDisabled) {
+		respondError(w, http.StatusForbidden, "Anmeldung per Login-Link ist deaktiviert")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create login link")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.MagicLinkResponse{
+		Message:     "Wenn ein Konto mit dieser E-Mail existiert, erhalten Sie einen Login-Link.",
+		DeviceToken: link.DeviceToken,
+		ExpiresIn:   int(time.Until(link.ExpiresAt).Seconds()),
+	})
+}
+
+// LoginMagicLink handles POST /api/auth/login/magic-link - the first login step with a login link
+// Like a password login, it continues with 2FA if the user has it enabled
+func (h *AuthHandler) LoginMagicLink(w http.ResponseWriter, r *http.Request) {
+	var req models.MagicLinkLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Token) == "" || strings.TrimSpace(req.DeviceToken) == "" {
+		respondError(w, http.StatusBadRequest, "Token and device token are required")
+		return
+	}
+
+	user, err := h.magicLinks.Verify(req.Token, req.DeviceToken)
+	switch {
+	case errors.Is(err, services.ErrMagicLinkDisabled):
+		respondError(w, http.StatusForbidden, "Anmeldung per Login-Link ist deaktiviert")
+		return
+	case errors.Is(err, services.ErrMagicLinkOtherDevice):
+		respondError(w, http.StatusUnauthorized, "Bitte öffnen Sie den Link in dem Browser, in dem Sie ihn angefordert haben")
+		return
+	case errors.Is(err, services.ErrMagicLinkInvalid):
+		respondError(w, http.StatusUnauthorized, "Ungültiger oder abgelaufener Login-Link")
+		return
+	case err != nil:
+		respondError(w, http.StatusInternalServerError, "Failed to verify login link")
+		return
+	}
+
+	h.continueLogin(w, r, user)
+}
+
+// LoginSSO handles POST /api/auth/login/sso - exchanges the single-use code of a finished
+// single sign-on login for a session; it only works in the browser that started the login
+// Like a password login, it continues with 2FA if the user has it enabled
+func (h *AuthHandler) LoginSSO(w http.ResponseWriter, r *http.Request) {
+	var req models.SSOLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	cookie, err := r.Cookie(ssoStateCookie)
+	if strings.TrimSpace(req.Code) == "" || err != nil {
+		respondError(w, http.StatusUnauthorized, "Anmeldung abgelaufen, bitte erneut anmelden")
+		return
+	}
+
+	user, err := h.oidc.Redeem(req.Code, cookie.Value)
+	switch {
+	case errors.Is(err, services.ErrOIDCInvalidState), errors.Is(err, services.ErrOIDCAccountInactive):
+		respondError(w, http.StatusUnauthorized, "Anmeldung abgelaufen, bitte erneut anmelden")
+		return
+	case err != nil:
+		respondError(w, http.StatusInternalServerError, "Failed to complete login")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: ssoStateCookie, Path: "/api/auth", MaxAge: -1, HttpOnly: true})
+	h.continueLogin(w, r, user)
+}
+
+// LoginTwoFactorSetup handles POST /api/auth/login/2fa/setup - creates a TOTP secret for
+// admins who must use 2FA but have not set it up yet
+func (h *AuthHandler) LoginTwoFactorSetup(w http.ResponseWriter, r *http.Request) {
+	var req models.TwoFactorSetupLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user, ok := h.preAuthUser(w, req.PreAuthToken)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.twoFactor.IsEnabled(user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if enabled {
+		respondError(w, http.StatusConflict, "Zwei-Faktor-Authentifizierung ist bereits eingerichtet")
+		return
+	}
+
+	setup, err := h.twoFactor.Setup(user.ID, *user.Email)
+	if errors.Is(err, services.ErrTwoFactorLocked) {
+		respondError(w, http.StatusTooManyRequests, "Zu viele falsche Codes, bitte in 15 Minuten erneut versuchen")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to set up two-factor authentication")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, setup)
+}
+
+// LoginTwoFactor handles POST /api/auth/login/2fa - the second login step with a TOTP or recovery code
+// If 2FA was set up during login, the first code enables it and the recovery codes are returned
+func (h *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req models.TwoFactorLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Code) == "" {
+		respondError(w, http.StatusBadRequest, "Code is required")
+		return
+	}
+
+	user, ok := h.preAuthUser(w, req.PreAuthToken)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.twoFactor.IsEnabled(user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	var recoveryCodes []string
+	if enabled {
+		valid, err := h.twoFactor.Verify(user.ID, req.Code)
+		if errors.Is(err, services.ErrTwoFactorLocked) {
+			respondError(w, http.StatusTooManyRequests, "Zu viele falsche Codes, bitte in 15 Minuten erneut versuchen")
+			return
+		}
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to verify code")
+			return
+		}
+		if !valid {
+			respondError(w, http.StatusUnauthorized, "Ungültiger Code")
+			return
+		}
+	} else {
+		codes, valid, err := h.twoFactor.Enable(user.ID, req.Code)
+		if errors.Is(err, services.ErrTwoFactorLocked) {
+			respondError(w, http.StatusTooManyRequests, "Zu viele falsche Codes, bitte in 15 Minuten erneut versuchen")
+			return
+		}
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to enable two-factor authentication")
+			return
+		}
+		if !valid {
+			respondError(w, http.StatusUnauthorized, "Ungültiger Code")
+			return
+		}
+		recoveryCodes = codes
+	}
+
+	h.completeLogin(w, r, user, recoveryCodes)
+}
+
+// preAuthUser validates a pre-auth token and returns its user, who must still be allowed to log in
+func (h *AuthHandler) preAuthUser(w http.ResponseWriter, preAuthToken string) (*models.User, bool) {
+	userID, err := h.authService.ValidatePreAuthToken(preAuthToken)
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "Anmeldung abgelaufen, bitte erneut anmelden")
+		return nil, false
+	}
+
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return nil, false
+	}
+	if user == nil || user.Email == nil || !user.IsVerified || !user.IsActive {
+		respondError(w, http.StatusUnauthorized, "Ungültige Anmeldedaten")
+		return nil, false
+	}
+	return user, true
+}
+
+// completeLogin starts a session after all login steps succeeded and returns its tokens
+func (h *AuthHandler) completeLogin(w http.ResponseWriter, r *http.Request, user *models.User, recoveryCodes []string) {
 	// Update last activity
 	if err := h.userRepo.UpdateLastActivity(user.ID); err != nil {
 		fmt.Printf("Failed to update last activity: %v\n", err)
 	}
 
-	// DONE: Get admin status from database (not config)
-	isAdmin := user.IsAdmin
-	isSuperAdmin := user.IsSuperAdmin // DONE: Phase 3
-
-	// Generate JWT
-	// DONE: Phase 3 - Include isSuperAdmin in JWT
-	token, err := h.authService.GenerateJWT(user.ID, req.Email, isAdmin, isSuperAdmin)
+	// Admin flags of the access token come from the database (not config)
+	tokens, err := h.sessions.Create(user, r.UserAgent(), logging.GetClientIP(r))
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to generate token")
 		return
 	}
 
 	respondJSON(w, http.StatusOK, models.LoginResponse{
-		Token:   token,
-		User:    user,
-		IsAdmin: isAdmin,
+		Token:         tokens.AccessToken,
+		RefreshToken:  tokens.RefreshToken,
+		ExpiresIn:     tokens.ExpiresIn,
+		User:          user,
+		IsAdmin:       user.IsAdmin,
+		RecoveryCodes: recoveryCodes,
+	})
+}
+
+// Refresh handles POST /api/auth/refresh - exchanges a refresh token for a new access and refresh token
+// The refresh token can only be used once; the response contains the current user
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.RefreshToken) == "" {
+		respondError(w, http.StatusBadRequest, "Refresh token is required")
+		return
+	}
+
+	tokens, user, err := h.sessions.Refresh(req.RefreshToken, r.UserAgent(), logging.GetClientIP(r))
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "Sitzung abgelaufen, bitte erneut anmelden")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.LoginResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
+		IsAdmin:      user.IsAdmin,
+	})
+}
+
+// Logout handles POST /api/auth/logout - ends the session of the refresh token
+// It works without a valid access token, so clients can always log out
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if strings.TrimSpace(req.RefreshToken) != "" {
+		if err := h.sessions.RevokeByRefreshToken(req.RefreshToken); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to log out")
+			return
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Abgemeldet"})
+}
+
+// LogoutAll handles POST /api/auth/logout-all - ends all sessions of the current user, on all devices
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	count, err := h.sessions.RevokeAll(userID, 0)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to log out")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":          "Auf allen Geräten abgemeldet",
+		"revoked_sessions": count,
 	})
 }
 
@@ -366,6 +692,7 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to update password")
 		return
 	}
+	h.sessions.RevokeAllQuietly(user.ID, "password reset")
 
 	respondJSON(w, http.StatusOK, map[string]string{
 		"message": "Password reset successful. You can now login with your new password.",
@@ -427,6 +754,12 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Log out other devices; the current session stays
+	sessionID, _ := r.Context().Value(middleware.SessionIDKey).(int)
+	if _, err := h.sessions.RevokeAll(user.ID, sessionID); err != nil {
+		log.Printf("Failed to revoke sessions after password change: %v", err)
+	}
+
 	respondJSON(w, http.StatusOK, map[string]string{
 		"message": "Password changed successfully",
 	})
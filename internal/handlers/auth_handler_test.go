@@ -994,3 +994,85 @@ func contextWithUser(ctx context.Context, userID int, email string, isAdmin bool
 
 	return ctx
 }
+
+// DONE: TestAuthHandler_MagicLink tests the auth methods, requesting a login link and logging in with it
+func TestAuthHandler_MagicLink(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret", JWTExpirationHours: 24}
+	handler := NewAuthHandler(db, cfg)
+	settingsRepo := repository.NewSettingsRepository(db)
+	userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+
+	call := func(fn http.HandlerFunc, method, target string, reqBody interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		rec := call(handler.GetAuthMethods, "GET", "/api/auth/methods", nil)
+		var methods models.AuthMethods
+		json.Unmarshal(rec.Body.Bytes(), &methods)
+		if !methods.Password || methods.MagicLink {
+			t.Errorf("Expected only password login, got %+v", methods)
+		}
+
+		rec = call(handler.RequestMagicLink, "POST", "/api/auth/magic-link", models.MagicLinkRequest{Email: "anna@example.com"})
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", rec.Code)
+		}
+	})
+
+	settingsRepo.Update("magic_link_login_enabled", "true")
+
+	t.Run("uniform response", func(t *testing.T) {
+		for _, email := range []string{"anna@example.com", "nobody@example.com"} {
+			rec := call(handler.RequestMagicLink, "POST", "/api/auth/magic-link", models.MagicLinkRequest{Email: email})
+			var resp models.MagicLinkResponse
+			json.Unmarshal(rec.Body.Bytes(), &resp)
+			if rec.Code != http.StatusOK || resp.DeviceToken == "" || resp.ExpiresIn <= 0 {
+				t.Errorf("Expected a device token for %s, got %d: %s", email, rec.Code, rec.Body.String())
+			}
+		}
+	})
+
+	t.Run("login", func(t *testing.T) {
+		link, err := handler.magicLinks.Request("anna@example.com", "")
+		if err != nil || link.Token == "" {
+			t.Fatalf("Request() failed: %+v (%v)", link, err)
+		}
+
+		rec := call(handler.LoginMagicLink, "POST", "/api/auth/login/magic-link", models.MagicLinkLoginRequest{Token: link.Token, DeviceToken: "other"})
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected another device to be rejected, got %d", rec.Code)
+		}
+
+		rec = call(handler.LoginMagicLink, "POST", "/api/auth/login/magic-link", models.MagicLinkLoginRequest{Token: link.Token, DeviceToken: link.DeviceToken})
+		var resp models.LoginResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != http.StatusOK || resp.Token == "" || resp.User == nil || resp.User.ID != userID {
+			t.Fatalf("Expected the login to succeed, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = call(handler.LoginMagicLink, "POST", "/api/auth/login/magic-link", models.MagicLinkLoginRequest{Token: link.Token, DeviceToken: link.DeviceToken})
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected the link to work only once, got %d", rec.Code)
+		}
+	})
+
+	t.Run("two-factor step still applies", func(t *testing.T) {
+		db.Exec(`DELETE FROM magic_link_tokens`)
+		db.Exec(`UPDATE users SET is_admin = 1 WHERE id = ?`, userID)
+		settingsRepo.Update("require_2fa_for_admins", "true")
+
+		link, _ := handler.magicLinks.Request("anna@example.com", "")
+		rec := call(handler.LoginMagicLink, "POST", "/api/auth/login/magic-link", models.MagicLinkLoginRequest{Token: link.Token, DeviceToken: link.DeviceToken})
+		var resp models.LoginResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != http.StatusOK || resp.Token != "" || !resp.TwoFactorSetupRequired || resp.PreAuthToken == "" {
+			t.Errorf("Expected only a pre-auth token, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
@@ -24,6 +24,7 @@ type BlockedDateHandler struct {
 	userRepo        *repository.UserRepository
 	dogRepo         *repository.DogRepository
 	emailService    *services.EmailService
+	audit           *services.AuditService
 }
 
 // NewBlockedDateHandler creates a new blocked date handler
@@ -42,6 +43,7 @@ func NewBlockedDateHandler(db *sql.DB, cfg *config.Config) *BlockedDateHandler {
 		userRepo:        repository.NewUserRepository(db),
 		dogRepo:         repository.NewDogRepository(db),
 		emailService:    emailService,
+		audit:           newAuditService(db),
 	}
 }
 
@@ -139,6 +141,9 @@ func (h *BlockedDateHandler) CreateBlockedDate(w http.ResponseWriter, r *http.Re
 		}
 	}
 
+	h.audit.Record(auditActor(r), models.AuditBlockedDateCreate, models.AuditTargetBlockedDate, &blockedDate.ID,
+		nil, map[string]interface{}{"date": blockedDate.Date, "reason": blockedDate.Reason, "cancelled_bookings": cancelledCount})
+
 	// Return response with cancellation count
 	response := map[string]interface{}{
 		"blocked_date":      blockedDate,
@@ -158,11 +163,22 @@ func (h *BlockedDateHandler) DeleteBlockedDate(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Snapshot for the audit log
+	blockedDate, err := h.blockedDateRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get blocked date")
+		return
+	}
+
 	// Delete blocked date
 	if err := h.blockedDateRepo.Delete(id); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to delete blocked date")
 		return
 	}
+	if blockedDate != nil {
+		h.audit.Record(auditActor(r), models.AuditBlockedDateDelete, models.AuditTargetBlockedDate, &id,
+			map[string]interface{}{"date": blockedDate.Date, "reason": blockedDate.Reason}, nil)
+	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Blocked date deleted successfully"})
 }
@@ -3,7 +3,9 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -26,8 +28,12 @@ type BookingHandler struct {
 	userRepo             *repository.UserRepository
 	blockedDateRepo      *repository.BlockedDateRepository
 	settingsRepo         *repository.SettingsRepository
+	healthRepo           *repository.DogHealthRepository
+	roleRepo             *repository.RoleRepository
 	bookingTimeService   *services.BookingTimeService
 	emailService         *services.EmailService
+	favoriteNotifier     *services.FavoriteNotificationService
+	audit                *services.AuditService
 }
 
 // NewBookingHandler creates a new booking handler
@@ -53,8 +59,12 @@ func NewBookingHandler(db *sql.DB, cfg *config.Config) *BookingHandler {
 		userRepo:             repository.NewUserRepository(db),
 		blockedDateRepo:      repository.NewBlockedDateRepository(db),
 		settingsRepo:         settingsRepo,
+		healthRepo:           repository.NewDogHealthRepository(db),
+		roleRepo:             repository.NewRoleRepository(db),
 		bookingTimeService:   bookingTimeService,
 		emailService:         emailService,
+		favoriteNotifier:     services.NewFavoriteNotificationService(repository.NewDogFavoriteRepository(db), emailService),
+		audit:                newAuditService(db),
 	}
 }
 
@@ -108,8 +118,8 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if dog is available
-	if !dog.IsAvailable {
+	// Check if dog is available and still in the shelter
+	if !dog.IsBookable() {
 		respondError(w, http.StatusBadRequest, "Dog is currently unavailable")
 		return
 	}
@@ -175,6 +185,30 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Validate group walk (additional dogs); co-walkers join open walks themselves
+	additionalDogs, status, message := h.validateGroupWalk(&req, user, dog)
+	if status != 0 {
+		respondError(w, status, message)
+		return
+	}
+
+	// Check dog walk requirements (minimum walkers, co-walker level, staff escort)
+	bookingDogs := append([]*models.Dog{dog}, additionalDogs...)
+	unmetRequirement := checkWalkRequirements(bookingDogs, user, nil, h.canEscort)
+	if unmetRequirement != "" && !req.OpenForCoWalkers {
+		respondError(w, http.StatusBadRequest, unmetRequirement)
+		return
+	}
+
+	// Walks must keep their distance to medication times
+	if conflict, err := h.checkMedicationTimes(bookingDogs, req.Date, req.ScheduledTime); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to check medications")
+		return
+	} else if conflict != "" {
+		respondError(w, http.StatusBadRequest, conflict)
+		return
+	}
+
 	// Validate booking time (check if time is allowed/blocked)
 	if err := h.bookingTimeService.ValidateBookingTime(req.Date, req.ScheduledTime); err != nil {
 		respondError(w, http.StatusBadRequest, err.Error())
@@ -198,19 +232,33 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Set approval status based on whether approval is required
-	if requiresApproval {
+	// Open walks wait for co-walkers first; approval (if required) follows once they are filled
+	if unmetRequirement != "" {
+		booking.ApprovalStatus = "awaiting_walkers"
+	} else if requiresApproval {
 		booking.ApprovalStatus = "pending"
 	} else {
 		booking.ApprovalStatus = "approved"
 	}
 
-	if err := h.bookingRepo.Create(booking); err != nil {
+	var createErr error
+	if len(req.AdditionalDogIDs) > 0 {
+		createErr = h.bookingRepo.CreateGroup(booking, req.AdditionalDogIDs)
+	} else {
+		createErr = h.bookingRepo.Create(booking)
+	}
+
+	if err := createErr; err != nil {
 		// BUGFIX #2: Detect UNIQUE constraint violation (race condition scenario)
 		// SQLite returns error containing "UNIQUE constraint failed" when duplicate booking occurs
-		if strings.Contains(err.Error(), "UNIQUE constraint") || strings.Contains(err.Error(), "unique constraint") {
+		if errors.Is(err, repository.ErrDogDoubleBooked) || strings.Contains(err.Error(), "UNIQUE constraint") || strings.Contains(err.Error(), "unique constraint") {
 			respondError(w, http.StatusConflict, "This dog is already booked for this time")
 			return
 		}
+		if errors.Is(err, repository.ErrWalkerDoubleBooked) {
+			respondError(w, http.StatusConflict, "You already have a walk at this time")
+			return
+		}
 		respondError(w, http.StatusInternalServerError, "Failed to create booking")
 		return
 	}
@@ -218,9 +266,23 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 	// Update user last activity
 	h.userRepo.UpdateLastActivity(userID)
 
-	// Send confirmation email
-	if user.Email != nil && h.emailService != nil {
-		go h.emailService.SendBookingConfirmation(*user.Email, user.Name, dog.Name, booking.Date, booking.ScheduledTime)
+	// Attach joined data for group walks so the response and emails show everyone
+	dogNames := dog.Name
+	if booking.IsGroupWalk() {
+		for i, d := range booking.AdditionalDogs {
+			d.Dog = additionalDogs[i]
+		}
+		booking.Dog = dog
+		dogNames = bookingDogNames(booking)
+	}
+
+	// Send confirmation email to the organizer
+	// Open walks are only confirmed once enough qualified co-walkers have joined
+	if h.emailService != nil && !booking.IsOpen() {
+		restrictions := h.bookingRestrictions(bookingDogs, booking.Date)
+		if user.Email != nil {
+			go h.emailService.SendBookingConfirmation(*user.Email, user.Name, dogNames, booking.Date, booking.ScheduledTime, restrictions)
+		}
 	}
 
 	respondJSON(w, http.StatusCreated, booking)
@@ -230,7 +292,7 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 func (h *BookingHandler) ListBookings(w http.ResponseWriter, r *http.Request) {
 	// Get user ID and admin status from context
 	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
-	isAdmin, _ := r.Context().Value(middleware.IsAdminKey).(bool)
+	isAdmin := middleware.HasPermission(r.Context(), models.PermissionApproveBookings, models.PermissionViewReports)
 
 	// Parse query parameters
 	filter := &models.BookingFilterRequest{}
@@ -271,6 +333,14 @@ func (h *BookingHandler) ListBookings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Attach co-walkers and additional dogs of group walks
+	for _, booking := range bookings {
+		if err := h.bookingRepo.LoadGroup(booking); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get bookings")
+			return
+		}
+	}
+
 	respondJSON(w, http.StatusOK, bookings)
 }
 
@@ -286,7 +356,7 @@ func (h *BookingHandler) GetBooking(w http.ResponseWriter, r *http.Request) {
 
 	// Get user ID and admin status
 	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
-	isAdmin, _ := r.Context().Value(middleware.IsAdminKey).(bool)
+	isAdmin := middleware.HasPermission(r.Context(), models.PermissionApproveBookings, models.PermissionViewReports)
 
 	// Get booking
 	booking, err := h.bookingRepo.FindByID(id)
@@ -299,8 +369,13 @@ func (h *BookingHandler) GetBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check authorization (user can only see their own bookings)
-	if !isAdmin && booking.UserID != userID {
+	if err := h.bookingRepo.LoadGroup(booking); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+
+	// Check authorization (user can only see their own bookings, including group walks they joined)
+	if !isAdmin && !booking.HasParticipant(userID) {
 		respondError(w, http.StatusForbidden, "Access denied")
 		return
 	}
@@ -320,7 +395,7 @@ func (h *BookingHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 
 	// Get user ID and admin status
 	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
-	isAdmin, _ := r.Context().Value(middleware.IsAdminKey).(bool)
+	isAdmin := middleware.HasPermission(r.Context(), models.PermissionApproveBookings)
 
 	// Parse request
 	var req models.CancelBookingRequest
@@ -346,6 +421,12 @@ func (h *BookingHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Co-walkers and additional dogs are needed for the notifications
+	if err := h.bookingRepo.LoadGroup(booking); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+
 	// Check if already cancelled or completed
 	if booking.Status != "scheduled" {
 		respondError(w, http.StatusBadRequest, "Booking is already "+booking.Status)
@@ -410,19 +491,33 @@ func (h *BookingHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to cancel booking")
 		return
 	}
+	if isAdmin && booking.UserID != userID {
+		h.audit.Record(auditActor(r), models.AuditBookingCancel, models.AuditTargetBooking, &id,
+			map[string]interface{}{"status": booking.Status},
+			map[string]interface{}{"status": "cancelled", "admin_cancellation_reason": req.Reason})
+	}
 
 	// Update user last activity
 	h.userRepo.UpdateLastActivity(userID)
 
-	// Send cancellation email
-	if booking.User.Email != nil && h.emailService != nil {
-		if isAdmin && req.Reason != nil {
-			// Admin cancelled
-			go h.emailService.SendAdminCancellation(*booking.User.Email, booking.User.Name, booking.Dog.Name, booking.Date, booking.ScheduledTime, *req.Reason)
-		} else {
-			// User cancelled
-			go h.emailService.SendBookingCancellation(*booking.User.Email, booking.User.Name, booking.Dog.Name, booking.Date, booking.ScheduledTime)
+	go h.notifyFreeSlot(booking)
+
+	// Send cancellation email to the organizer and all co-walkers
+	if h.emailService != nil {
+		dogNames := bookingDogNames(booking)
+		send := func(to, name string) {
+			if isAdmin && req.Reason != nil {
+				// Admin cancelled
+				h.emailService.SendAdminCancellation(to, name, dogNames, booking.Date, booking.ScheduledTime, *req.Reason)
+			} else {
+				// User cancelled
+				h.emailService.SendBookingCancellation(to, name, dogNames, booking.Date, booking.ScheduledTime)
+			}
+		}
+		if booking.User.Email != nil {
+			go send(*booking.User.Email, booking.User.Name)
 		}
+		notifyCoWalkers(booking, send)
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Booking cancelled successfully"})
@@ -554,6 +649,23 @@ func (h *BookingHandler) MoveBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Additional dogs of a group walk must be free at the new time as well
+	if err := h.bookingRepo.LoadGroup(booking); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	for _, d := range booking.AdditionalDogs {
+		isDoubleBooked, err := h.bookingRepo.CheckDoubleBooking(d.DogID, req.Date, req.ScheduledTime)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to check availability")
+			return
+		}
+		if isDoubleBooked {
+			respondError(w, http.StatusConflict, fmt.Sprintf("%s is already booked for this time", d.Dog.Name))
+			return
+		}
+	}
+
 	// Update booking
 	booking.Date = req.Date
 	booking.ScheduledTime = req.ScheduledTime
@@ -562,22 +674,32 @@ func (h *BookingHandler) MoveBooking(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to move booking")
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditBookingMove, models.AuditTargetBooking, &id,
+		map[string]interface{}{"date": oldDate, "scheduled_time": oldTime},
+		map[string]interface{}{"date": req.Date, "scheduled_time": req.ScheduledTime, "reason": req.Reason})
 
 	// Update user last activity
 	h.userRepo.UpdateLastActivity(userID)
 
-	// Send email notification to user
-	if booking.User.Email != nil && h.emailService != nil {
-		go h.emailService.SendBookingMoved(
-			*booking.User.Email,
-			booking.User.Name,
-			booking.Dog.Name,
-			oldDate,
-			oldTime,
-			req.Date,
-			req.ScheduledTime,
-			req.Reason,
-		)
+	// Send email notification to the organizer and all co-walkers
+	if h.emailService != nil {
+		dogNames := bookingDogNames(booking)
+		send := func(to, name string) {
+			h.emailService.SendBookingMoved(
+				to,
+				name,
+				dogNames,
+				oldDate,
+				oldTime,
+				req.Date,
+				req.ScheduledTime,
+				req.Reason,
+			)
+		}
+		if booking.User.Email != nil {
+			go send(*booking.User.Email, booking.User.Name)
+		}
+		notifyCoWalkers(booking, send)
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Booking moved successfully"})
@@ -671,9 +793,8 @@ func (h *BookingHandler) GetCalendarData(w http.ResponseWriter, r *http.Request)
 // GET /api/bookings/pending-approvals
 func (h *BookingHandler) GetPendingApprovals(w http.ResponseWriter, r *http.Request) {
 	// Check admin permission
-	isAdmin, ok := r.Context().Value(middleware.IsAdminKey).(bool)
-	if !ok || !isAdmin {
-		respondError(w, http.StatusForbidden, "Admin access required")
+	if !middleware.HasPermission(r.Context(), models.PermissionApproveBookings) {
+		respondError(w, http.StatusForbidden, "Permission required")
 		return
 	}
 
@@ -690,9 +811,8 @@ func (h *BookingHandler) GetPendingApprovals(w http.ResponseWriter, r *http.Requ
 // PUT /api/bookings/:id/approve
 func (h *BookingHandler) ApprovePendingBooking(w http.ResponseWriter, r *http.Request) {
 	// Check admin permission
-	isAdmin, ok := r.Context().Value(middleware.IsAdminKey).(bool)
-	if !ok || !isAdmin {
-		respondError(w, http.StatusForbidden, "Admin access required")
+	if !middleware.HasPermission(r.Context(), models.PermissionApproveBookings) {
+		respondError(w, http.StatusForbidden, "Permission required")
 		return
 	}
 
@@ -713,23 +833,36 @@ func (h *BookingHandler) ApprovePendingBooking(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Get booking details before approving (for email)
+	booking, err := h.bookingRepo.FindByIDWithDetails(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	if booking != nil {
+		if err := h.bookingRepo.LoadGroup(booking); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get booking")
+			return
+		}
+	}
+
 	if err := h.bookingRepo.ApproveBooking(id, adminID); err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditBookingApprove, models.AuditTargetBooking, &id,
+		map[string]interface{}{"approval_status": "pending"}, map[string]interface{}{"approval_status": "approved"})
 
-	// Send email notification to user
-	if h.emailService != nil {
-		booking, err := h.bookingRepo.FindByIDWithDetails(id)
-		if err == nil && booking != nil && booking.User != nil && booking.User.Email != nil && *booking.User.Email != "" {
-			go h.emailService.SendBookingApproved(
-				*booking.User.Email,
-				booking.User.Name,
-				booking.Dog.Name,
-				booking.Date,
-				booking.ScheduledTime,
-			)
+	// Send email notification to the organizer and all co-walkers
+	if h.emailService != nil && booking != nil {
+		dogNames := bookingDogNames(booking)
+		send := func(to, name string) {
+			h.emailService.SendBookingApproved(to, name, dogNames, booking.Date, booking.ScheduledTime)
 		}
+		if booking.User != nil && booking.User.Email != nil && *booking.User.Email != "" {
+			go send(*booking.User.Email, booking.User.Name)
+		}
+		notifyCoWalkers(booking, send)
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{
@@ -741,9 +874,8 @@ func (h *BookingHandler) ApprovePendingBooking(w http.ResponseWriter, r *http.Re
 // PUT /api/bookings/:id/reject
 func (h *BookingHandler) RejectPendingBooking(w http.ResponseWriter, r *http.Request) {
 	// Check admin permission
-	isAdmin, ok := r.Context().Value(middleware.IsAdminKey).(bool)
-	if !ok || !isAdmin {
-		respondError(w, http.StatusForbidden, "Admin access required")
+	if !middleware.HasPermission(r.Context(), models.PermissionApproveBookings) {
+		respondError(w, http.StatusForbidden, "Permission required")
 		return
 	}
 
@@ -778,26 +910,557 @@ func (h *BookingHandler) RejectPendingBooking(w http.ResponseWriter, r *http.Req
 	}
 
 	// Get booking details before rejecting (for email)
-	booking, _ := h.bookingRepo.FindByIDWithDetails(id)
+	booking, err := h.bookingRepo.FindByIDWithDetails(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	if booking != nil {
+		if err := h.bookingRepo.LoadGroup(booking); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get booking")
+			return
+		}
+	}
 
 	if err := h.bookingRepo.RejectBooking(id, adminID, req.Reason); err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditBookingReject, models.AuditTargetBooking, &id,
+		map[string]interface{}{"approval_status": "pending", "status": "scheduled"},
+		map[string]interface{}{"approval_status": "rejected", "status": "cancelled", "rejection_reason": req.Reason})
+
+	if booking != nil {
+		go h.notifyFreeSlot(booking)
+	}
 
-	// Send email notification to user with reason
-	if h.emailService != nil && booking != nil && booking.User != nil && booking.User.Email != nil && *booking.User.Email != "" {
-		go h.emailService.SendBookingRejected(
-			*booking.User.Email,
-			booking.User.Name,
-			booking.Dog.Name,
-			booking.Date,
-			booking.ScheduledTime,
-			req.Reason,
-		)
+	// Send email notification with reason to the organizer and all co-walkers
+	if h.emailService != nil && booking != nil {
+		dogNames := bookingDogNames(booking)
+		send := func(to, name string) {
+			h.emailService.SendBookingRejected(to, name, dogNames, booking.Date, booking.ScheduledTime, req.Reason)
+		}
+		if booking.User != nil && booking.User.Email != nil && *booking.User.Email != "" {
+			go send(*booking.User.Email, booking.User.Name)
+		}
+		notifyCoWalkers(booking, send)
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{
 		"message": "Booking rejected successfully",
 	})
 }
+
+// ListOpenBookings returns open group walks the current user could join as a co-walker
+func (h *BookingHandler) ListOpenBookings(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user")
+		return
+	}
+	if user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	bookings, err := h.bookingRepo.FindOpen()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get open bookings")
+		return
+	}
+
+	openBookings := []*models.Booking{}
+	for _, booking := range bookings {
+		if err := h.bookingRepo.LoadGroup(booking); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get open bookings")
+			return
+		}
+		if booking.HasParticipant(userID) {
+			continue
+		}
+
+		dogs, err := h.loadBookingDogs(booking)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get open bookings")
+			return
+		}
+
+		// Only list walks the user is qualified for
+		canJoin := true
+		for _, dog := range dogs {
+			if !repository.CanUserAccessDog(user.ExperienceLevel, dog.Category) {
+				canJoin = false
+				break
+			}
+		}
+		if !canJoin {
+			continue
+		}
+
+		booking.Dog = dogs[0]
+		openBookings = append(openBookings, booking)
+	}
+
+	respondJSON(w, http.StatusOK, openBookings)
+}
+
+// JoinBooking adds the current user as a co-walker to an open group walk
+// Once all walk requirements are met, the walk is confirmed (or sent for approval)
+func (h *BookingHandler) JoinBooking(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user")
+		return
+	}
+	if user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if !user.IsActive {
+		respondError(w, http.StatusForbidden, "Your account is deactivated")
+		return
+	}
+
+	booking, err := h.bookingRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	if booking == nil {
+		respondError(w, http.StatusNotFound, "Booking not found")
+		return
+	}
+	if booking.Status != "scheduled" || !booking.IsOpen() {
+		respondError(w, http.StatusBadRequest, "This walk is not open for co-walkers")
+		return
+	}
+
+	if err := h.bookingRepo.LoadGroup(booking); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	if booking.HasParticipant(userID) {
+		respondError(w, http.StatusConflict, "You are already part of this walk")
+		return
+	}
+
+	maxWalkers := h.getIntSetting("max_walkers_per_booking", 3)
+	if len(booking.Participants)+2 > maxWalkers {
+		respondError(w, http.StatusBadRequest, "This walk is already full")
+		return
+	}
+
+	dogs, err := h.loadBookingDogs(booking)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get dog")
+		return
+	}
+	for _, dog := range dogs {
+		if !repository.CanUserAccessDog(user.ExperienceLevel, dog.Category) {
+			respondError(w, http.StatusForbidden, fmt.Sprintf("You don't have the required experience level for %s", dog.Name))
+			return
+		}
+	}
+
+	// The repository re-checks the capacity atomically, as other users may join at the same time
+	if err := h.bookingRepo.AddParticipant(booking.ID, userID, maxWalkers-1); err != nil {
+		if errors.Is(err, repository.ErrBookingFull) {
+			respondError(w, http.StatusBadRequest, "This walk is already full")
+			return
+		}
+		if errors.Is(err, repository.ErrWalkerDoubleBooked) {
+			respondError(w, http.StatusConflict, "You already have a walk at this time")
+			return
+		}
+		if strings.Contains(err.Error(), "UNIQUE constraint") || strings.Contains(err.Error(), "unique constraint") {
+			respondError(w, http.StatusConflict, "You are already part of this walk")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to join walk")
+		return
+	}
+
+	// Reload walkers including the new co-walker and re-check the requirements
+	if err := h.bookingRepo.LoadGroup(booking); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	organizer, coWalkers, err := h.loadBookingWalkers(booking)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user")
+		return
+	}
+	booking.Dog = dogs[0]
+	booking.User = organizer
+
+	if checkWalkRequirements(dogs, organizer, coWalkers, h.canEscort) == "" {
+		approvalStatus := "approved"
+		if booking.RequiresApproval {
+			approvalStatus = "pending"
+		}
+		if err := h.bookingRepo.SetApprovalStatus(booking.ID, approvalStatus); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to update booking")
+			return
+		}
+		booking.ApprovalStatus = approvalStatus
+
+		// Walk is confirmed - notify everyone
+		if approvalStatus == "approved" && h.emailService != nil {
+			dogNames := bookingDogNames(booking)
+			restrictions := h.bookingRestrictions(dogs, booking.Date)
+			if organizer.Email != nil {
+				go h.emailService.SendBookingConfirmation(*organizer.Email, organizer.Name, dogNames, booking.Date, booking.ScheduledTime, restrictions)
+			}
+			notifyCoWalkers(booking, func(to, name string) {
+				h.emailService.SendBookingConfirmation(to, name, dogNames, booking.Date, booking.ScheduledTime, restrictions)
+			})
+		}
+	}
+
+	h.userRepo.UpdateLastActivity(userID)
+
+	respondJSON(w, http.StatusOK, booking)
+}
+
+// LeaveBooking removes the current user as co-walker from a scheduled walk
+// If the walk no longer meets the walk requirements of its dogs, it is open for co-walkers again
+func (h *BookingHandler) LeaveBooking(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	booking, err := h.bookingRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	if booking == nil {
+		respondError(w, http.StatusNotFound, "Booking not found")
+		return
+	}
+	if err := h.bookingRepo.LoadGroup(booking); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	if booking.UserID == userID {
+		respondError(w, http.StatusBadRequest, "Organizers cancel the walk instead")
+		return
+	}
+	if !booking.HasParticipant(userID) {
+		respondError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+	if booking.Status != "scheduled" {
+		respondError(w, http.StatusBadRequest, "Booking is already "+booking.Status)
+		return
+	}
+
+	removed, err := h.bookingRepo.RemoveParticipant(booking.ID, userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to leave walk")
+		return
+	}
+	if !removed {
+		respondError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	// Re-check the requirements without the co-walker who left
+	if err := h.bookingRepo.LoadGroup(booking); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	dogs, err := h.loadBookingDogs(booking)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get dog")
+		return
+	}
+	organizer, coWalkers, err := h.loadBookingWalkers(booking)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user")
+		return
+	}
+	if !booking.IsOpen() && checkWalkRequirements(dogs, organizer, coWalkers, h.canEscort) != "" {
+		if err := h.bookingRepo.SetApprovalStatus(booking.ID, "awaiting_walkers"); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to update booking")
+			return
+		}
+	}
+
+	h.userRepo.UpdateLastActivity(userID)
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "You have left the walk"})
+}
+
+// loadBookingDogs loads the full records of all dogs on a booking, primary dog first
+func (h *BookingHandler) loadBookingDogs(booking *models.Booking) ([]*models.Dog, error) {
+	dogIDs := []int{booking.DogID}
+	for _, d := range booking.AdditionalDogs {
+		dogIDs = append(dogIDs, d.DogID)
+	}
+
+	dogs := []*models.Dog{}
+	for _, dogID := range dogIDs {
+		dog, err := h.dogRepo.FindByID(dogID)
+		if err != nil {
+			return nil, err
+		}
+		if dog == nil {
+			return nil, fmt.Errorf("dog %d not found", dogID)
+		}
+		dogs = append(dogs, dog)
+	}
+	return dogs, nil
+}
+
+// loadBookingWalkers loads the full records of the organizer and co-walkers of a booking
+func (h *BookingHandler) loadBookingWalkers(booking *models.Booking) (*models.User, []*models.User, error) {
+	organizer, err := h.userRepo.FindByID(booking.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if organizer == nil {
+		return nil, nil, fmt.Errorf("user %d not found", booking.UserID)
+	}
+
+	coWalkers := []*models.User{}
+	for _, p := range booking.Participants {
+		coWalker, err := h.userRepo.FindByID(p.UserID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if coWalker != nil {
+			coWalkers = append(coWalkers, coWalker)
+		}
+	}
+	return organizer, coWalkers, nil
+}
+
+// validateGroupWalk checks the additional dogs of a booking request
+// Returns the loaded dogs in request order, or a non-zero HTTP status with a message
+// Messages do not name the dogs, as they are looked up by the IDs of the request
+func (h *BookingHandler) validateGroupWalk(req *models.CreateBookingRequest, organizer *models.User, primaryDog *models.Dog) ([]*models.Dog, int, string) {
+	if len(req.AdditionalDogIDs) == 0 {
+		return nil, 0, ""
+	}
+
+	// Check group size limit
+	maxDogs := h.getIntSetting("max_dogs_per_booking", 2)
+	if len(req.AdditionalDogIDs)+1 > maxDogs {
+		return nil, http.StatusBadRequest, fmt.Sprintf("A walk can include at most %d dogs", maxDogs)
+	}
+
+	// Load and check additional dogs
+	dogs := []*models.Dog{primaryDog}
+	additionalDogs := []*models.Dog{}
+	for _, dogID := range req.AdditionalDogIDs {
+		dog, err := h.dogRepo.FindByID(dogID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, "Failed to get dog"
+		}
+		if dog == nil {
+			return nil, http.StatusNotFound, "Dog not found"
+		}
+		if !dog.IsBookable() {
+			return nil, http.StatusBadRequest, "An additional dog is currently unavailable"
+		}
+		if !repository.CanUserAccessDog(organizer.ExperienceLevel, dog.Category) {
+			return nil, http.StatusForbidden, "You don't have the required experience level for all dogs of this walk"
+		}
+
+		isDoubleBooked, err := h.bookingRepo.CheckDoubleBooking(dog.ID, req.Date, req.ScheduledTime)
+		if err != nil {
+			return nil, http.StatusInternalServerError, "Failed to check availability"
+		}
+		if isDoubleBooked {
+			return nil, http.StatusConflict, "An additional dog is already booked for this time"
+		}
+
+		dogs = append(dogs, dog)
+		additionalDogs = append(additionalDogs, dog)
+	}
+
+	// Every pair of dogs must be allowed to walk together
+	for i := 0; i < len(dogs); i++ {
+		for j := i + 1; j < len(dogs); j++ {
+			compatible, err := h.dogRepo.AreDogsCompatible(dogs[i].ID, dogs[j].ID)
+			if err != nil {
+				return nil, http.StatusInternalServerError, "Failed to check dog compatibility"
+			}
+			if !compatible {
+				return nil, http.StatusBadRequest, "These dogs cannot be walked together"
+			}
+		}
+	}
+
+	return additionalDogs, 0, ""
+}
+
+// getIntSetting reads a numeric system setting, falling back to the default if missing or invalid
+func (h *BookingHandler) getIntSetting(key string, defaultValue int) int {
+	setting, err := h.settingsRepo.Get(key)
+	if err != nil || setting == nil {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(setting.Value)
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// bookingDogNames joins the names of all dogs on a booking for notifications (e.g. "Bella & Max")
+func bookingDogNames(booking *models.Booking) string {
+	names := []string{}
+	if booking.Dog != nil && booking.Dog.Name != "" {
+		names = append(names, booking.Dog.Name)
+	}
+	for _, d := range booking.AdditionalDogs {
+		if d.Dog != nil && d.Dog.Name != "" {
+			names = append(names, d.Dog.Name)
+		}
+	}
+	return strings.Join(names, " & ")
+}
+
+// notifyFreeSlot tells users who follow the dogs of a cancelled or rejected booking that the slot is free
+// There is no waitlist; favourites with free slot notifications are told and the first to book gets the slot
+func (h *BookingHandler) notifyFreeSlot(booking *models.Booking) {
+	if h.emailService == nil {
+		return
+	}
+
+	dogs := []*models.Dog{}
+	for _, dogID := range booking.AllDogIDs() {
+		dog, err := h.dogRepo.FindByID(dogID)
+		if err != nil || dog == nil || !dog.IsBookable() {
+			continue
+		}
+		dogs = append(dogs, dog)
+	}
+
+	h.favoriteNotifier.NotifyFreeSlot(dogs, booking.Date, booking.ScheduledTime, booking.AllUserIDs())
+}
+
+// notifyCoWalkers sends a notification to every co-walker of a group booking that has an email address
+// The organizer is notified separately by the caller
+func notifyCoWalkers(booking *models.Booking, send func(to, name string)) {
+	for _, p := range booking.Participants {
+		if p.User != nil && p.User.Email != nil && *p.User.Email != "" {
+			go send(*p.User.Email, p.User.Name)
+		}
+	}
+}
+
+// canEscort returns true if a user may escort dogs that require a staff member (walks.escort permission)
+func (h *BookingHandler) canEscort(userID int) bool {
+	permissions, err := h.roleRepo.GetUserPermissions(userID)
+	if err != nil {
+		log.Printf("Error loading permissions of user %d: %v", userID, err)
+		return false
+	}
+	for _, p := range permissions {
+		if p == models.PermissionEscortWalks {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWalkRequirements checks the walk requirements of every dog against the walkers of a booking
+// canEscort tells whether a walker counts as staff escort
+// Returns an empty string if all requirements are met, otherwise the first unmet requirement
+func checkWalkRequirements(dogs []*models.Dog, organizer *models.User, coWalkers []*models.User, canEscort func(userID int) bool) string {
+	walkers := append([]*models.User{organizer}, coWalkers...)
+
+	for _, dog := range dogs {
+		if !dog.HasWalkRequirements() {
+			continue
+		}
+
+		if len(walkers) < dog.MinWalkers {
+			return fmt.Sprintf("%s must be walked by at least %d people", dog.Name, dog.MinWalkers)
+		}
+
+		// Experience levels are ordered like dog categories, so the same check applies
+		if dog.MinCoWalkerLevel != nil && *dog.MinCoWalkerLevel != "" {
+			qualified := false
+			for _, coWalker := range coWalkers {
+				if repository.CanUserAccessDog(coWalker.ExperienceLevel, *dog.MinCoWalkerLevel) {
+					qualified = true
+					break
+				}
+			}
+			if !qualified {
+				return fmt.Sprintf("%s requires a co-walker with experience level %s or higher", dog.Name, *dog.MinCoWalkerLevel)
+			}
+		}
+
+		if dog.RequiresStaffEscort {
+			escorted := false
+			for _, walker := range walkers {
+				if canEscort(walker.ID) {
+					escorted = true
+					break
+				}
+			}
+			if !escorted {
+				return fmt.Sprintf("%s may only be walked together with a staff member", dog.Name)
+			}
+		}
+	}
+
+	return ""
+}
+
+// checkMedicationTimes returns a message if the walk is too close to a dosage time of one of the dogs, or ""
+func (h *BookingHandler) checkMedicationTimes(dogs []*models.Dog, date, scheduledTime string) (string, error) {
+	for _, dog := range dogs {
+		medications, err := h.healthRepo.FindMedications(dog.ID)
+		if err != nil {
+			return "", err
+		}
+		for _, medication := range medications {
+			if !medication.IsActiveOn(date) {
+				continue
+			}
+			if dosageTime := medication.ConflictsWith(scheduledTime); dosageTime != "" {
+				return fmt.Sprintf("%s gets %s at %s, please keep at least %d minutes distance to the walk",
+					dog.Name, medication.Name, dosageTime, medication.WalkBufferMinutes), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// bookingRestrictions returns the walk restrictions of the dogs that apply on the date, for emails
+func (h *BookingHandler) bookingRestrictions(dogs []*models.Dog, date string) []string {
+	restrictions := []string{}
+	for _, dog := range dogs {
+		active, err := h.healthRepo.GetActiveRestrictions(dog.ID, date)
+		if err != nil {
+			log.Printf("Warning: Failed to load restrictions for dog %d: %v", dog.ID, err)
+			continue
+		}
+		for _, restriction := range active {
+			restrictions = append(restrictions, dog.Name+": "+restriction.Describe())
+		}
+	}
+	return restrictions
+}
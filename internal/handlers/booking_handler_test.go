@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/middleware"
 	"github.com/tranmh/gassigeher/internal/models"
 	"github.com/tranmh/gassigeher/internal/repository"
 	"github.com/tranmh/gassigeher/internal/services"
@@ -630,6 +632,22 @@ func TestBookingHandler_GetBooking(t *testing.T) {
 		}
 	})
 
+	t.Run("role with a booking permission can get any booking", func(t *testing.T) {
+		approverID := testutil.SeedTestUser(t, db, "approver@example.com", "Approver", "green")
+
+		req := httptest.NewRequest("GET", "/api/bookings/"+fmt.Sprintf("%d", bookingID), nil)
+		ctx := context.WithValue(contextWithUser(req.Context(), approverID, "approver@example.com", false),
+			middleware.PermissionsKey, []models.Permission{models.PermissionApproveBookings})
+		req = mux.SetURLVars(req.WithContext(ctx), map[string]string{"id": fmt.Sprintf("%d", bookingID)})
+
+		rec := httptest.NewRecorder()
+		handler.GetBooking(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200 for the approver, got %d", rec.Code)
+		}
+	})
+
 	t.Run("booking not found", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/api/bookings/99999", nil)
 		req = mux.SetURLVars(req, map[string]string{"id": "99999"})
@@ -1374,3 +1392,344 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+// DONE: TestBookingHandler_GroupWalk tests booking creation with additional dogs and co-walkers
+func TestBookingHandler_GroupWalk(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewBookingHandler(db, cfg)
+	dogRepo := repository.NewDogRepository(db)
+
+	organizer := testutil.SeedTestUser(t, db, "organizer@example.com", "Organizer", "blue")
+	coWalker := testutil.SeedTestUser(t, db, "cowalker@example.com", "Co Walker", "blue")
+	bella := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	max := testutil.SeedTestDog(t, db, "Max", "Beagle", "blue")
+	rex := testutil.SeedTestDog(t, db, "Rex", "Schäferhund", "green")
+	luna := testutil.SeedTestDog(t, db, "Luna", "Pudel", "green")
+	if err := dogRepo.SetCompatibleDogs(bella, []int{max}); err != nil {
+		t.Fatalf("Failed to pair dogs: %v", err)
+	}
+	if err := dogRepo.SetCompatibleDogs(luna, []int{max}); err != nil {
+		t.Fatalf("Failed to pair dogs: %v", err)
+	}
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+	createBooking := func(reqBody map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest("POST", "/api/bookings", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(contextWithUser(req.Context(), organizer, "organizer@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.CreateBooking(rec, req)
+		return rec
+	}
+
+	t.Run("incompatible dogs are rejected", func(t *testing.T) {
+		rec := createBooking(map[string]interface{}{
+			"dog_id":             bella,
+			"date":               tomorrow,
+			"scheduled_time":     "09:00",
+			"additional_dog_ids": []int{rex},
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		if stringContains(rec.Body.String(), "Rex") {
+			t.Errorf("Expected no dog names in the error, got %s", rec.Body.String())
+		}
+	})
+
+	var groupBookingID int
+	t.Run("successful group walk", func(t *testing.T) {
+		rec := createBooking(map[string]interface{}{
+			"dog_id":             bella,
+			"date":               tomorrow,
+			"scheduled_time":     "09:00",
+			"additional_dog_ids": []int{max},
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.Booking
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		groupBookingID = response.ID
+
+		if len(response.AdditionalDogs) != 1 {
+			t.Errorf("Expected 1 additional dog, got %d", len(response.AdditionalDogs))
+		}
+	})
+
+	t.Run("additional dog already booked", func(t *testing.T) {
+		rec := createBooking(map[string]interface{}{
+			"dog_id":             luna,
+			"date":               tomorrow,
+			"scheduled_time":     "09:00",
+			"additional_dog_ids": []int{max},
+		})
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("co-walker can view the booking", func(t *testing.T) {
+		if err := repository.NewBookingRepository(db).AddParticipant(groupBookingID, coWalker, 2); err != nil {
+			t.Fatalf("Failed to add co-walker: %v", err)
+		}
+		id := fmt.Sprintf("%d", groupBookingID)
+		req := httptest.NewRequest("GET", "/api/bookings/"+id, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUser(req.Context(), coWalker, "cowalker@example.com", false))
+
+		rec := httptest.NewRecorder()
+		handler.GetBooking(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", rec.Code)
+		}
+	})
+}
+
+// DONE: TestBookingHandler_WalkRequirements tests dogs that must not be walked alone
+func TestBookingHandler_WalkRequirements(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewBookingHandler(db, cfg)
+
+	organizer := testutil.SeedTestUser(t, db, "organizer@example.com", "Organizer", "orange")
+	greenWalker := testutil.SeedTestUser(t, db, "green@example.com", "Green Walker", "green")
+	orangeWalker := testutil.SeedTestUser(t, db, "orange@example.com", "Orange Walker", "orange")
+	staff := testutil.SeedTestUser(t, db, "staff@example.com", "Staff", "orange")
+	db.Exec("UPDATE users SET is_admin = 1 WHERE id = ?", staff)
+
+	rocky := testutil.SeedTestDog(t, db, "Rocky", "Herdenschutzhund", "orange")
+	db.Exec("UPDATE dogs SET min_walkers = 2, min_co_walker_level = 'orange' WHERE id = ?", rocky)
+	bruno := testutil.SeedTestDog(t, db, "Bruno", "Kangal", "orange")
+	db.Exec("UPDATE dogs SET requires_staff_escort = 1 WHERE id = ?", bruno)
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+	createBooking := func(userID int, reqBody map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest("POST", "/api/bookings", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.CreateBooking(rec, req)
+		return rec
+	}
+
+	joinBooking := func(userID, bookingID int) *httptest.ResponseRecorder {
+		id := fmt.Sprintf("%d", bookingID)
+		req := httptest.NewRequest("POST", "/api/bookings/"+id+"/join", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.JoinBooking(rec, req)
+		return rec
+	}
+
+	t.Run("solo booking is rejected", func(t *testing.T) {
+		rec := createBooking(organizer, map[string]interface{}{
+			"dog_id":         rocky,
+			"date":           tomorrow,
+			"scheduled_time": "09:00",
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("staff escort required", func(t *testing.T) {
+		rec := createBooking(organizer, map[string]interface{}{
+			"dog_id":              bruno,
+			"date":                tomorrow,
+			"scheduled_time":      "10:00",
+			"open_for_co_walkers": true,
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		var response models.Booking
+		json.Unmarshal(rec.Body.Bytes(), &response)
+
+		rec = joinBooking(orangeWalker, response.ID)
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if rec.Code != http.StatusOK || response.ApprovalStatus != "awaiting_walkers" {
+			t.Errorf("Expected the walk to wait for a staff member, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = joinBooking(staff, response.ID)
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if rec.Code != http.StatusOK || response.IsOpen() {
+			t.Errorf("Expected the walk to be filled with staff member, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	var openBookingID int
+	t.Run("open booking waits for co-walker", func(t *testing.T) {
+		rec := createBooking(organizer, map[string]interface{}{
+			"dog_id":              rocky,
+			"date":                tomorrow,
+			"scheduled_time":      "09:00",
+			"open_for_co_walkers": true,
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var response models.Booking
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		openBookingID = response.ID
+
+		if response.ApprovalStatus != "awaiting_walkers" {
+			t.Errorf("Expected approval status 'awaiting_walkers', got %s", response.ApprovalStatus)
+		}
+	})
+
+	t.Run("open booking is listed for qualified users only", func(t *testing.T) {
+		list := func(userID int) []models.Booking {
+			req := httptest.NewRequest("GET", "/api/bookings/open", nil)
+			req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", false))
+			rec := httptest.NewRecorder()
+			handler.ListOpenBookings(rec, req)
+			var bookings []models.Booking
+			json.Unmarshal(rec.Body.Bytes(), &bookings)
+			return bookings
+		}
+
+		if bookings := list(orangeWalker); len(bookings) != 1 {
+			t.Errorf("Expected 1 open booking for orange walker, got %d", len(bookings))
+		}
+		if bookings := list(greenWalker); len(bookings) != 0 {
+			t.Errorf("Expected no open bookings for green walker, got %d", len(bookings))
+		}
+		if bookings := list(organizer); len(bookings) != 0 {
+			t.Errorf("Expected organizer not to see own open booking, got %d", len(bookings))
+		}
+	})
+
+	t.Run("unqualified user cannot join", func(t *testing.T) {
+		rec := joinBooking(greenWalker, openBookingID)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("qualified co-walker fills the walk", func(t *testing.T) {
+		rec := joinBooking(orangeWalker, openBookingID)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		// 09:00 is in the morning window, so the filled walk goes on to admin approval
+		var response models.Booking
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response.ApprovalStatus != "pending" {
+			t.Errorf("Expected approval status 'pending', got %s", response.ApprovalStatus)
+		}
+		if len(response.Participants) != 1 {
+			t.Errorf("Expected 1 participant, got %d", len(response.Participants))
+		}
+	})
+
+	t.Run("confirmed walk can no longer be joined", func(t *testing.T) {
+		rec := joinBooking(staff, openBookingID)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("escort permission counts as staff", func(t *testing.T) {
+		roleRepo := repository.NewRoleRepository(db)
+		escorts := &models.Role{Name: "escorts", Permissions: []models.Permission{models.PermissionEscortWalks}}
+		if err := roleRepo.Create(escorts); err != nil {
+			t.Fatalf("Failed to create role: %v", err)
+		}
+		roleRepo.SetUserRoles(orangeWalker, []int{escorts.ID}, 0)
+
+		rec := createBooking(organizer, map[string]interface{}{
+			"dog_id":              bruno,
+			"date":                tomorrow,
+			"scheduled_time":      "16:00",
+			"open_for_co_walkers": true,
+		})
+		var response models.Booking
+		json.Unmarshal(rec.Body.Bytes(), &response)
+
+		rec = joinBooking(orangeWalker, response.ID)
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if rec.Code != http.StatusOK || response.IsOpen() {
+			t.Errorf("Expected the walk to be filled with escort, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+// DONE: TestBookingHandler_JoinBooking tests that co-walkers cannot be booked twice at the same time and can leave again
+func TestBookingHandler_JoinBooking(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewBookingHandler(db, cfg)
+	bookingRepo := repository.NewBookingRepository(db)
+
+	organizer := testutil.SeedTestUser(t, db, "organizer@example.com", "Organizer", "green")
+	busyWalker := testutil.SeedTestUser(t, db, "busy@example.com", "Busy Walker", "green")
+	freeWalker := testutil.SeedTestUser(t, db, "free@example.com", "Free Walker", "green")
+	rocky := testutil.SeedTestDog(t, db, "Rocky", "Labrador", "green")
+	db.Exec("UPDATE dogs SET min_walkers = 2 WHERE id = ?", rocky)
+	bella := testutil.SeedTestDog(t, db, "Bella", "Beagle", "green")
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	open := &models.Booking{UserID: organizer, DogID: rocky, Date: tomorrow, ScheduledTime: "16:00", ApprovalStatus: "awaiting_walkers"}
+	if err := bookingRepo.Create(open); err != nil {
+		t.Fatalf("Failed to create booking: %v", err)
+	}
+	if err := bookingRepo.Create(&models.Booking{UserID: busyWalker, DogID: bella, Date: tomorrow, ScheduledTime: "16:00"}); err != nil {
+		t.Fatalf("Failed to create booking: %v", err)
+	}
+
+	joinBooking := func(userID int) *httptest.ResponseRecorder {
+		id := fmt.Sprintf("%d", open.ID)
+		req := httptest.NewRequest("POST", "/api/bookings/"+id+"/join", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.JoinBooking(rec, req)
+		return rec
+	}
+
+	if rec := joinBooking(busyWalker); rec.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a walker with another walk at this time, got %d. Body: %s", rec.Code, rec.Body.String())
+	}
+	if rec := joinBooking(freeWalker); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+	}
+
+	leaveBooking := func(userID int) *httptest.ResponseRecorder {
+		id := fmt.Sprintf("%d", open.ID)
+		req := httptest.NewRequest("POST", "/api/bookings/"+id+"/leave", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.LeaveBooking(rec, req)
+		return rec
+	}
+
+	if rec := leaveBooking(organizer); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for the organizer, got %d", rec.Code)
+	}
+	if rec := leaveBooking(busyWalker); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a user who is not a co-walker, got %d", rec.Code)
+	}
+	if rec := leaveBooking(freeWalker); rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+	}
+
+	// Without the co-walker, the walk waits for walkers again
+	booking, _ := bookingRepo.FindByID(open.ID)
+	bookingRepo.LoadGroup(booking)
+	if len(booking.Participants) != 0 || !booking.IsOpen() {
+		t.Errorf("Expected the walk to be open again without co-walkers, got %s with %d", booking.ApprovalStatus, len(booking.Participants))
+	}
+}
@@ -52,9 +52,8 @@ func (h *BookingTimeHandler) GetAvailableSlots(w http.ResponseWriter, r *http.Re
 // GET /api/booking-times/rules
 func (h *BookingTimeHandler) GetRules(w http.ResponseWriter, r *http.Request) {
 	// Check admin permission
-	isAdmin, ok := r.Context().Value(middleware.IsAdminKey).(bool)
-	if !ok || !isAdmin {
-		respondError(w, http.StatusForbidden, "Admin access required")
+	if !middleware.HasPermission(r.Context(), models.PermissionManageSettings) {
+		respondError(w, http.StatusForbidden, "Permission required")
 		return
 	}
 
@@ -89,9 +88,8 @@ func (h *BookingTimeHandler) GetRulesForDate(w http.ResponseWriter, r *http.Requ
 // PUT /api/booking-times/rules
 func (h *BookingTimeHandler) UpdateRules(w http.ResponseWriter, r *http.Request) {
 	// Check admin permission
-	isAdmin, ok := r.Context().Value(middleware.IsAdminKey).(bool)
-	if !ok || !isAdmin {
-		respondError(w, http.StatusForbidden, "Admin access required")
+	if !middleware.HasPermission(r.Context(), models.PermissionManageSettings) {
+		respondError(w, http.StatusForbidden, "Permission required")
 		return
 	}
 
@@ -126,9 +124,8 @@ func (h *BookingTimeHandler) UpdateRules(w http.ResponseWriter, r *http.Request)
 // POST /api/booking-times/rules
 func (h *BookingTimeHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
 	// Check admin permission
-	isAdmin, ok := r.Context().Value(middleware.IsAdminKey).(bool)
-	if !ok || !isAdmin {
-		respondError(w, http.StatusForbidden, "Admin access required")
+	if !middleware.HasPermission(r.Context(), models.PermissionManageSettings) {
+		respondError(w, http.StatusForbidden, "Permission required")
 		return
 	}
 
@@ -155,9 +152,8 @@ func (h *BookingTimeHandler) CreateRule(w http.ResponseWriter, r *http.Request)
 // DELETE /api/booking-times/rules/:id
 func (h *BookingTimeHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
 	// Check admin permission
-	isAdmin, ok := r.Context().Value(middleware.IsAdminKey).(bool)
-	if !ok || !isAdmin {
-		respondError(w, http.StatusForbidden, "Admin access required")
+	if !middleware.HasPermission(r.Context(), models.PermissionManageSettings) {
+		respondError(w, http.StatusForbidden, "Permission required")
 		return
 	}
 
@@ -74,9 +74,10 @@ func (h *DashboardHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 		stats.InactiveUsers = len(inactiveUsers)
 	}
 
-	// Get available/unavailable dogs
+	// Get available/unavailable dogs (adopted and archived dogs are not counted)
 	availableDogs, err := h.dogRepo.FindAll(&models.DogFilterRequest{
 		Available: boolPtr(true),
+		Statuses:  models.ActiveDogStatuses,
 	})
 	if err == nil {
 		stats.AvailableDogs = len(availableDogs)
@@ -84,6 +85,7 @@ func (h *DashboardHandler) GetStats(w http.ResponseWriter, r *http.Request) {
 
 	unavailableDogs, err := h.dogRepo.FindAll(&models.DogFilterRequest{
 		Available: boolPtr(false),
+		Statuses:  models.ActiveDogStatuses,
 	})
 	if err == nil {
 		stats.UnavailableDogs = len(unavailableDogs)
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/middleware"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+// DogFavoriteHandler handles favourite dogs and notification preferences
+type DogFavoriteHandler struct {
+	db           *sql.DB
+	cfg          *config.Config
+	dogRepo      *repository.DogRepository
+	favoriteRepo *repository.DogFavoriteRepository
+}
+
+// NewDogFavoriteHandler creates a new dog favourite handler
+func NewDogFavoriteHandler(db *sql.DB, cfg *config.Config) *DogFavoriteHandler {
+	return &DogFavoriteHandler{
+		db:           db,
+		cfg:          cfg,
+		dogRepo:      repository.NewDogRepository(db),
+		favoriteRepo: repository.NewDogFavoriteRepository(db),
+	}
+}
+
+// ListFavorites handles GET /api/dogs/favorites - list the favourite dogs of the current user
+func (h *DogFavoriteHandler) ListFavorites(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	favorites, err := h.favoriteRepo.FindByUser(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get favorites")
+		return
+	}
+
+	result := []*models.DogFavorite{}
+	for _, favorite := range favorites {
+		dog, err := h.dogRepo.FindByID(favorite.DogID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get favorites")
+			return
+		}
+		if dog == nil {
+			continue
+		}
+		favorite.Dog = dog
+		result = append(result, favorite)
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// SetFavorite handles PUT /api/dogs/:id/favorite - mark a dog as favourite and choose notifications
+// The body is optional; without it the dog is a favourite without notifications
+func (h *DogFavoriteHandler) SetFavorite(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return
+	}
+
+	var req models.SetDogFavoriteRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	favorite := &models.DogFavorite{
+		UserID:          userID,
+		DogID:           dog.ID,
+		NotifyAvailable: req.NotifyAvailable,
+		NotifyFreeSlots: req.NotifyFreeSlots,
+	}
+	if err := h.favoriteRepo.Set(favorite); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to save favorite")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, favorite)
+}
+
+// RemoveFavorite handles DELETE /api/dogs/:id/favorite - remove a dog from the favourites
+func (h *DogFavoriteHandler) RemoveFavorite(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	dogID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid dog ID")
+		return
+	}
+
+	if err := h.favoriteRepo.Remove(userID, dogID); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to remove favorite")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Favorite removed"})
+}
+
+// GetNotificationPreferences handles GET /api/users/me/notifications
+func (h *DogFavoriteHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	subscribed, err := h.favoriteRepo.HasNewDogSubscription(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get notification preferences")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.NotificationPreferences{NewDogs: subscribed})
+}
+
+// UpdateNotificationPreferences handles PUT /api/users/me/notifications
+func (h *DogFavoriteHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	var req models.NotificationPreferences
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.favoriteRepo.SetNewDogSubscription(userID, req.NewDogs); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update notification preferences")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, req)
+}
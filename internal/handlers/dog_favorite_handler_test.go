@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogFavoriteHandler tests favourite dogs and notification preferences
+func TestDogFavoriteHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewDogFavoriteHandler(db, cfg)
+
+	userID := testutil.SeedTestUser(t, db, "user@example.com", "User", "green")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+
+	call := func(fn http.HandlerFunc, method string, vars map[string]string, reqBody interface{}) *httptest.ResponseRecorder {
+		var body []byte
+		if reqBody != nil {
+			body, _ = json.Marshal(reqBody)
+		}
+		req := httptest.NewRequest(method, "/api/dogs/favorites", bytes.NewReader(body))
+		req = mux.SetURLVars(req, vars)
+		req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", false))
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+	dogVars := map[string]string{"id": fmt.Sprintf("%d", dogID)}
+
+	t.Run("mark favourite without body", func(t *testing.T) {
+		rec := call(handler.SetFavorite, "PUT", dogVars, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var favorite models.DogFavorite
+		json.Unmarshal(rec.Body.Bytes(), &favorite)
+		if favorite.DogID != dogID || favorite.NotifyAvailable || favorite.NotifyFreeSlots {
+			t.Errorf("Expected a favourite without notifications, got %+v", favorite)
+		}
+	})
+
+	t.Run("subscribe to notifications", func(t *testing.T) {
+		rec := call(handler.SetFavorite, "PUT", dogVars, models.SetDogFavoriteRequest{NotifyAvailable: true, NotifyFreeSlots: true})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = call(handler.ListFavorites, "GET", nil, nil)
+		var favorites []*models.DogFavorite
+		json.Unmarshal(rec.Body.Bytes(), &favorites)
+		if len(favorites) != 1 {
+			t.Fatalf("Expected 1 favourite, got %d", len(favorites))
+		}
+		if !favorites[0].NotifyAvailable || !favorites[0].NotifyFreeSlots {
+			t.Error("Expected notifications to be enabled")
+		}
+		if favorites[0].Dog == nil || favorites[0].Dog.Name != "Bella" {
+			t.Error("Expected the dog to be included")
+		}
+	})
+
+	t.Run("unknown dog", func(t *testing.T) {
+		rec := call(handler.SetFavorite, "PUT", map[string]string{"id": "9999"}, nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("remove favourite", func(t *testing.T) {
+		rec := call(handler.RemoveFavorite, "DELETE", dogVars, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+
+		rec = call(handler.ListFavorites, "GET", nil, nil)
+		var favorites []*models.DogFavorite
+		json.Unmarshal(rec.Body.Bytes(), &favorites)
+		if len(favorites) != 0 {
+			t.Errorf("Expected no favourites, got %d", len(favorites))
+		}
+	})
+
+	t.Run("new dog notifications", func(t *testing.T) {
+		rec := call(handler.UpdateNotificationPreferences, "PUT", nil, models.NotificationPreferences{NewDogs: true})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+
+		rec = call(handler.GetNotificationPreferences, "GET", nil, nil)
+		var prefs models.NotificationPreferences
+		json.Unmarshal(rec.Body.Bytes(), &prefs)
+		if !prefs.NewDogs {
+			t.Error("Expected new dog notifications to be enabled")
+		}
+	})
+}
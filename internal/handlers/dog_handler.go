@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -21,12 +20,17 @@ import (
 
 // DogHandler handles dog-related endpoints
 type DogHandler struct {
-	dogRepo      *repository.DogRepository
-	userRepo     *repository.UserRepository
-	bookingRepo  *repository.BookingRepository
-	imageService *services.ImageService
-	emailService *services.EmailService
-	config       *config.Config
+	dogRepo          *repository.DogRepository
+	userRepo         *repository.UserRepository
+	bookingRepo      *repository.BookingRepository
+	photoRepo        *repository.DogPhotoRepository
+	searchRepo       *repository.DogSearchRepository
+	tagRepo          *repository.DogTagRepository
+	imageService     *services.ImageService
+	emailService     *services.EmailService
+	favoriteNotifier *services.FavoriteNotificationService
+	audit            *services.AuditService
+	config           *config.Config
 }
 
 // NewDogHandler creates a new dog handler
@@ -38,17 +42,100 @@ func NewDogHandler(db *sql.DB, cfg *config.Config) *DogHandler {
 	}
 
 	return &DogHandler{
-		dogRepo:      repository.NewDogRepository(db),
-		userRepo:     repository.NewUserRepository(db),
-		bookingRepo:  repository.NewBookingRepository(db),
-		imageService: services.NewImageService(cfg.UploadDir),
-		emailService: emailService,
-		config:       cfg,
+		dogRepo:          repository.NewDogRepository(db),
+		userRepo:         repository.NewUserRepository(db),
+		bookingRepo:      repository.NewBookingRepository(db),
+		photoRepo:        repository.NewDogPhotoRepository(db),
+		searchRepo:       repository.NewDogSearchRepository(db, cfg.DBType),
+		tagRepo:          repository.NewDogTagRepository(db),
+		imageService:     services.NewImageServiceWithStorage(services.NewStorageFromConfig(cfg)),
+		emailService:     emailService,
+		favoriteNotifier: services.NewFavoriteNotificationService(repository.NewDogFavoriteRepository(db), emailService),
+		audit:            newAuditService(db),
+		config:           cfg,
 	}
 }
 
 // ListDogs handles GET /api/dogs - list all dogs with optional filters
 func (h *DogHandler) ListDogs(w http.ResponseWriter, r *http.Request) {
+	dogs, err := h.findDogs(r)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch dogs: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch dogs")
+		return
+	}
+
+	if err := h.loadTags(dogs...); err != nil {
+		log.Printf("ERROR: Failed to fetch dog tags: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch dogs")
+		return
+	}
+
+	// If user is authenticated, filter based on their experience level
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if ok {
+		user, err := h.userRepo.FindByID(userID)
+		if err == nil && user != nil {
+			filteredDogs := []*models.Dog{}
+			for _, dog := range dogs {
+				// Check if user can access this dog
+				if repository.CanUserAccessDog(user.ExperienceLevel, dog.Category) {
+					filteredDogs = append(filteredDogs, dog)
+				} else {
+					// Include but mark as inaccessible (frontend will handle display)
+					filteredDogs = append(filteredDogs, dog)
+				}
+			}
+			dogs = filteredDogs
+		}
+	}
+
+	respondJSON(w, http.StatusOK, dogs)
+}
+
+// ListDogFacets handles GET /api/dogs/facets - count dogs per tag for the filters of GET /api/dogs
+// Counts are for the current result, so they show how many dogs remain when a tag is added
+func (h *DogHandler) ListDogFacets(w http.ResponseWriter, r *http.Request) {
+	dogs, err := h.findDogs(r)
+	if err != nil {
+		log.Printf("ERROR: Failed to fetch dogs: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch dogs")
+		return
+	}
+
+	tags, err := h.tagRepo.FindAll()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch tags")
+		return
+	}
+
+	ids := make([]int, len(dogs))
+	for i, dog := range dogs {
+		ids[i] = dog.ID
+	}
+	dogTags, err := h.tagRepo.FindByDogs(ids)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch tags")
+		return
+	}
+
+	counts := map[int]int{}
+	for _, assigned := range dogTags {
+		for _, tag := range assigned {
+			counts[tag.ID]++
+		}
+	}
+
+	facets := &models.DogFacets{Total: len(dogs), Tags: []*models.DogTagFacet{}}
+	for _, tag := range tags {
+		facets.Tags = append(facets.Tags, &models.DogTagFacet{ID: tag.ID, Name: tag.Name, Count: counts[tag.ID]})
+	}
+
+	respondJSON(w, http.StatusOK, facets)
+}
+
+// findDogs returns the dogs matching the filters in the query string of the request
+func (h *DogHandler) findDogs(r *http.Request) ([]*models.Dog, error) {
 	// Parse query parameters for filtering
 	filter := &models.DogFilterRequest{}
 
@@ -86,38 +173,46 @@ func (h *DogHandler) ListDogs(w http.ResponseWriter, r *http.Request) {
 		filter.Available = &avail
 	}
 
+	// Lifecycle states: active dogs by default; admins can ask for others (e.g., status=adopted,archived or status=all)
+	isAdmin := middleware.HasPermission(r.Context(), models.PermissionManageDogs, models.PermissionViewReports)
+	filter.Statuses = models.ActiveDogStatuses
+	if status := r.URL.Query().Get("status"); isAdmin && status != "" {
+		statuses, all := parseStatusList(status)
+		if all {
+			filter.Statuses = nil
+		} else if len(statuses) > 0 {
+			filter.Statuses = statuses
+		}
+	}
+
+	// Full-text search; admins also search walk reports and incidents
+	var searchResults []int
 	if search := r.URL.Query().Get("search"); search != "" {
-		filter.Search = &search
+		ids, err := h.searchRepo.Search(search, isAdmin)
+		if err != nil {
+			// Fall back to matching name and breed
+			log.Printf("Warning: Dog search failed, using simple search: %v", err)
+			filter.Search = &search
+		} else {
+			searchResults = ids
+		}
 	}
 
+	// Traits the dog must have (tags) or must not have (exclude_tags), comma-separated IDs
+	filter.TagIDs = parseIDList(r.URL.Query().Get("tags"))
+	filter.ExcludeTagIDs = parseIDList(r.URL.Query().Get("exclude_tags"))
+
 	// Get dogs
 	dogs, err := h.dogRepo.FindAll(filter)
 	if err != nil {
-		log.Printf("ERROR: Failed to fetch dogs: %v", err)
-		respondError(w, http.StatusInternalServerError, "Failed to fetch dogs")
-		return
+		return nil, err
 	}
 
-	// If user is authenticated, filter based on their experience level
-	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
-	if ok {
-		user, err := h.userRepo.FindByID(userID)
-		if err == nil && user != nil {
-			filteredDogs := []*models.Dog{}
-			for _, dog := range dogs {
-				// Check if user can access this dog
-				if repository.CanUserAccessDog(user.ExperienceLevel, dog.Category) {
-					filteredDogs = append(filteredDogs, dog)
-				} else {
-					// Include but mark as inaccessible (frontend will handle display)
-					filteredDogs = append(filteredDogs, dog)
-				}
-			}
-			dogs = filteredDogs
-		}
+	if searchResults != nil {
+		dogs = orderBySearchResults(dogs, searchResults)
 	}
 
-	respondJSON(w, http.StatusOK, dogs)
+	return dogs, nil
 }
 
 // GetDog handles GET /api/dogs/:id - get a single dog
@@ -140,6 +235,11 @@ func (h *DogHandler) GetDog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.loadTags(dog); err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
 	respondJSON(w, http.StatusOK, dog)
 }
 
@@ -151,28 +251,34 @@ func (h *DogHandler) CreateDog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate required fields
-	if strings.TrimSpace(req.Name) == "" {
-		respondError(w, http.StatusBadRequest, "Name is required")
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if strings.TrimSpace(req.Breed) == "" {
-		respondError(w, http.StatusBadRequest, "Breed is required")
-		return
+	if req.ExternalID != nil && *req.ExternalID != "" {
+		if !h.externalIDAvailable(w, *req.ExternalID, 0) {
+			return
+		}
 	}
 
-	if req.Size != "small" && req.Size != "medium" && req.Size != "large" {
-		respondError(w, http.StatusBadRequest, "Size must be small, medium, or large")
-		return
-	}
+	dog := newDogFromRequest(&req)
 
-	if req.Category != "green" && req.Category != "blue" && req.Category != "orange" {
-		respondError(w, http.StatusBadRequest, "Category must be green, blue, or orange")
+	if err := h.dogRepo.Create(dog); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create dog")
 		return
 	}
+	indexDogForSearch(h.searchRepo, dog.ID)
+	h.audit.Record(auditActor(r), models.AuditDogCreate, models.AuditTargetDog, &dog.ID, nil, dog)
+
+	// Tell subscribers of the dog's experience level about the new dog
+	go h.favoriteNotifier.NotifyNewDog(dog)
+
+	respondJSON(w, http.StatusCreated, dog)
+}
 
-	// Create dog
+// newDogFromRequest builds a new, available dog from a validated create request
+func newDogFromRequest(req *models.CreateDogRequest) *models.Dog {
 	dog := &models.Dog{
 		Name:                req.Name,
 		Breed:               req.Breed,
@@ -188,14 +294,38 @@ func (h *DogHandler) CreateDog(w http.ResponseWriter, r *http.Request) {
 		DefaultEveningTime:  req.DefaultEveningTime,
 		ExternalLink:        req.ExternalLink,
 		IsAvailable:         true, // Default to available
+		MinWalkers:          1,
 	}
 
-	if err := h.dogRepo.Create(dog); err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to create dog")
-		return
+	// Optional walk requirements
+	if req.MinWalkers != nil {
+		dog.MinWalkers = *req.MinWalkers
+	}
+	if req.MinCoWalkerLevel != nil && *req.MinCoWalkerLevel != "" {
+		dog.MinCoWalkerLevel = req.MinCoWalkerLevel
+	}
+	if req.RequiresStaffEscort != nil {
+		dog.RequiresStaffEscort = *req.RequiresStaffEscort
+	}
+	if req.ExternalID != nil && *req.ExternalID != "" {
+		dog.ExternalID = req.ExternalID
 	}
 
-	respondJSON(w, http.StatusCreated, dog)
+	return dog
+}
+
+// externalIDAvailable responds with a conflict if another dog already has the external ID
+func (h *DogHandler) externalIDAvailable(w http.ResponseWriter, externalID string, dogID int) bool {
+	existingID, err := h.dogRepo.FindByExternalID(externalID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return false
+	}
+	if existingID != 0 && existingID != dogID {
+		respondError(w, http.StatusConflict, "Another dog already has this external ID")
+		return false
+	}
+	return true
 }
 
 // UpdateDog handles PUT /api/dogs/:id - update a dog (admin only)
@@ -226,6 +356,13 @@ func (h *DogHandler) UpdateDog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := models.ValidateWalkRequirements(req.MinWalkers, req.MinCoWalkerLevel); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	before := *dog
+
 	// Update fields if provided
 	if req.Name != nil {
 		dog.Name = *req.Name
@@ -266,12 +403,39 @@ func (h *DogHandler) UpdateDog(w http.ResponseWriter, r *http.Request) {
 	if req.ExternalLink != nil {
 		dog.ExternalLink = req.ExternalLink
 	}
+	if req.MinWalkers != nil {
+		dog.MinWalkers = *req.MinWalkers
+	}
+	if req.MinCoWalkerLevel != nil {
+		// Empty string removes the requirement
+		if *req.MinCoWalkerLevel == "" {
+			dog.MinCoWalkerLevel = nil
+		} else {
+			dog.MinCoWalkerLevel = req.MinCoWalkerLevel
+		}
+	}
+	if req.RequiresStaffEscort != nil {
+		dog.RequiresStaffEscort = *req.RequiresStaffEscort
+	}
+	if req.ExternalID != nil {
+		// Empty string removes the external ID
+		if *req.ExternalID == "" {
+			dog.ExternalID = nil
+		} else {
+			if !h.externalIDAvailable(w, *req.ExternalID, dog.ID) {
+				return
+			}
+			dog.ExternalID = req.ExternalID
+		}
+	}
 
 	// Update in database
 	if err := h.dogRepo.Update(dog); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update dog")
 		return
 	}
+	indexDogForSearch(h.searchRepo, dog.ID)
+	h.audit.Record(auditActor(r), models.AuditDogUpdate, models.AuditTargetDog, &dog.ID, &before, dog)
 
 	respondJSON(w, http.StatusOK, dog)
 }
@@ -334,6 +498,9 @@ func (h *DogHandler) DeleteDog(w http.ResponseWriter, r *http.Request) {
 			respondError(w, http.StatusInternalServerError, "Failed to delete dog")
 			return
 		}
+		indexDogForSearch(h.searchRepo, id)
+		h.audit.Record(auditActor(r), models.AuditDogDelete, models.AuditTargetDog, &id,
+			dog, map[string]interface{}{"cancelled_bookings": len(bookings)})
 
 		respondJSON(w, http.StatusOK, map[string]interface{}{
 			"message":          "Hund erfolgreich gelöscht",
@@ -342,6 +509,9 @@ func (h *DogHandler) DeleteDog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Snapshot for the audit log; the delete itself reports missing dogs
+	dog, _ := h.dogRepo.FindByID(id)
+
 	// Normal delete (will fail if future bookings exist)
 	err = h.dogRepo.Delete(id)
 	if err != nil {
@@ -363,6 +533,8 @@ func (h *DogHandler) DeleteDog(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
+	indexDogForSearch(h.searchRepo, id)
+	h.audit.Record(auditActor(r), models.AuditDogDelete, models.AuditTargetDog, &id, dog, nil)
 
 	respondJSON(w, http.StatusOK, map[string]string{
 		"message": "Hund erfolgreich gelöscht",
@@ -370,6 +542,7 @@ func (h *DogHandler) DeleteDog(w http.ResponseWriter, r *http.Request) {
 }
 
 // UploadDogPhoto handles POST /api/dogs/:id/photo - upload dog photo (admin only)
+// The photo replaces the cover of the gallery
 func (h *DogHandler) UploadDogPhoto(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
@@ -416,9 +589,13 @@ func (h *DogHandler) UploadDogPhoto(w http.ResponseWriter, r *http.Request) {
 		// This handles the new naming scheme (dog_{id}_full.jpg, dog_{id}_thumb.jpg)
 		h.imageService.DeleteDogPhotos(id)
 
-		// Also try to delete old photo with original naming scheme (backward compatibility)
-		oldPath := filepath.Join(h.config.UploadDir, *dog.Photo)
-		os.Remove(oldPath) // Ignore errors if file doesn't exist
+		// Also delete the content hashed variants, or the old photo with original naming scheme
+		h.imageService.DeleteDogPhotoVariants(*dog.Photo)
+
+		// The cover may be a gallery photo with its own thumbnail
+		if dog.PhotoThumbnail != nil && *dog.PhotoThumbnail != "" {
+			h.imageService.DeletePhoto(*dog.PhotoThumbnail)
+		}
 	}
 
 	// Process the uploaded photo (resize, compress, create thumbnail)
@@ -434,7 +611,13 @@ func (h *DogHandler) UploadDogPhoto(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.dogRepo.Update(dog); err != nil {
 		// If database update fails, clean up the newly created files
-		h.imageService.DeleteDogPhotos(id)
+		h.imageService.DeleteDogPhotoVariants(fullPath)
+		respondError(w, http.StatusInternalServerError, "Failed to update dog")
+		return
+	}
+
+	// Keep the gallery in sync: the uploaded photo replaces the cover
+	if err := h.photoRepo.ReplaceCover(id, fullPath, &thumbPath); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update dog")
 		return
 	}
@@ -443,6 +626,7 @@ func (h *DogHandler) UploadDogPhoto(w http.ResponseWriter, r *http.Request) {
 		"message":   "Photo uploaded successfully",
 		"photo":     fullPath,
 		"thumbnail": thumbPath,
+		"variants":  services.DogPhotoVariants(fullPath),
 	})
 }
 
@@ -467,6 +651,14 @@ func (h *DogHandler) ToggleAvailability(w http.ResponseWriter, r *http.Request)
 		req.UnavailableReason = &defaultReason
 	}
 
+	// Remember the previous state, favourites are only notified when the dog becomes available again
+	previous, err := h.dogRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	wasAvailable := previous != nil && previous.IsAvailable
+
 	// Toggle availability
 	if err := h.dogRepo.ToggleAvailability(id, req.IsAvailable, req.UnavailableReason); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to toggle availability")
@@ -479,10 +671,168 @@ func (h *DogHandler) ToggleAvailability(w http.ResponseWriter, r *http.Request)
 		respondError(w, http.StatusInternalServerError, "Failed to fetch updated dog")
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditDogUpdate, models.AuditTargetDog, &id, previous, dog)
+
+	if dog != nil && dog.IsBookable() && !wasAvailable {
+		go h.favoriteNotifier.NotifyDogAvailable(dog)
+	}
 
 	respondJSON(w, http.StatusOK, dog)
 }
 
+// SetDogStatus handles PUT /api/dogs/:id/status - change the lifecycle status of a dog (admin only)
+// When the dog leaves the shelter (trial adoption, adopted, deceased, archived) all future walks
+// with it are cancelled and the walkers are told why. The dog and its walk history are kept.
+func (h *DogHandler) SetDogStatus(w http.ResponseWriter, r *http.Request) {
+	adminID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid dog ID")
+		return
+	}
+
+	var req models.SetDogStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dog, err := h.dogRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch dog")
+		return
+	}
+	if dog == nil {
+		respondError(w, http.StatusNotFound, "Dog not found")
+		return
+	}
+
+	previous := dog.Status
+	if err := previous.CanChangeTo(req.Status); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bookings := []*models.Booking{}
+	if !req.Status.IsBookable() {
+		bookings, err = h.dogRepo.GetFutureBookingsWithDog(id)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to fetch bookings")
+			return
+		}
+	}
+
+	// Change the status first, so no new walks can be booked while the others are cancelled
+	change := &models.DogStatusChange{
+		DogID:             id,
+		Status:            req.Status,
+		Note:              req.Note,
+		ChangedBy:         &adminID,
+		CancelledBookings: len(bookings),
+	}
+	if err := h.dogRepo.SetStatus(change); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to change dog status")
+		return
+	}
+
+	cancellationReason := fmt.Sprintf("Hund %s ist nicht mehr im Tierheim", dog.Name)
+	for _, booking := range bookings {
+		if err := h.bookingRepo.Cancel(booking.ID, &cancellationReason); err != nil {
+			log.Printf("ERROR: Failed to cancel booking %d: %v", booking.ID, err)
+			continue
+		}
+		h.sendDogStatusCancellation(booking, dog.Name, req.Status)
+	}
+
+	dog, err = h.dogRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch updated dog")
+		return
+	}
+	indexDogForSearch(h.searchRepo, id)
+	h.audit.Record(auditActor(r), models.AuditDogStatus, models.AuditTargetDog, &id,
+		map[string]interface{}{"status": previous},
+		map[string]interface{}{"status": req.Status, "note": req.Note, "cancelled_bookings": len(bookings)})
+
+	// The dog is back in the shelter, e.g. after a trial adoption that did not work out
+	if !previous.IsBookable() && dog.IsBookable() {
+		go h.favoriteNotifier.NotifyDogAvailable(dog)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"dog":             dog,
+		"cancelled_count": len(bookings),
+	})
+}
+
+// GetDogStatusHistory handles GET /api/dogs/:id/status-history - lifecycle history of a dog (admin only)
+func (h *DogHandler) GetDogStatusHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid dog ID")
+		return
+	}
+
+	dog, err := h.dogRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch dog")
+		return
+	}
+	if dog == nil {
+		respondError(w, http.StatusNotFound, "Dog not found")
+		return
+	}
+
+	history, err := h.dogRepo.GetStatusHistory(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch status history")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, history)
+}
+
+// sendDogStatusCancellation e-mails the organizer and co-walkers of a walk cancelled by a status change
+func (h *DogHandler) sendDogStatusCancellation(booking *models.Booking, dogName string, status models.DogStatus) {
+	if h.emailService == nil {
+		return
+	}
+
+	recipients := []*models.User{}
+	if booking.User != nil {
+		recipients = append(recipients, booking.User)
+	}
+	if err := h.bookingRepo.LoadGroup(booking); err != nil {
+		log.Printf("ERROR: Failed to load group of booking %d: %v", booking.ID, err)
+	}
+	for _, participant := range booking.Participants {
+		if participant.User != nil {
+			recipients = append(recipients, participant.User)
+		}
+	}
+
+	for _, user := range recipients {
+		if user.Email == nil || *user.Email == "" {
+			continue
+		}
+		go h.emailService.SendDogStatusBookingCancelled(
+			*user.Email,
+			user.Name,
+			dogName,
+			booking.Date,
+			booking.ScheduledTime,
+			string(status),
+		)
+	}
+}
+
 // GetBreeds handles GET /api/dogs/breeds - get list of all breeds
 func (h *DogHandler) GetBreeds(w http.ResponseWriter, r *http.Request) {
 	breeds, err := h.dogRepo.GetBreeds()
@@ -555,3 +905,175 @@ func (h *DogHandler) SetFeatured(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, dog)
 }
+
+// GetCompatibleDogs handles GET /api/dogs/:id/compatible - list dogs that can be walked together with this dog
+func (h *DogHandler) GetCompatibleDogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid dog ID")
+		return
+	}
+
+	dog, err := h.dogRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if dog == nil {
+		respondError(w, http.StatusNotFound, "Dog not found")
+		return
+	}
+
+	ids, err := h.dogRepo.GetCompatibleDogIDs(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch compatible dogs")
+		return
+	}
+
+	dogs := []*models.Dog{}
+	for _, compatibleID := range ids {
+		compatible, err := h.dogRepo.FindByID(compatibleID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to fetch compatible dogs")
+			return
+		}
+		if compatible != nil {
+			dogs = append(dogs, compatible)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, dogs)
+}
+
+// SetCompatibleDogs handles PUT /api/dogs/:id/compatible - replace "can walk with" rules (admin only)
+func (h *DogHandler) SetCompatibleDogs(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid dog ID")
+		return
+	}
+
+	var req models.SetCompatibleDogsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	dog, err := h.dogRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if dog == nil {
+		respondError(w, http.StatusNotFound, "Dog not found")
+		return
+	}
+
+	// Validate that all referenced dogs exist
+	seen := map[int]bool{}
+	for _, otherID := range req.DogIDs {
+		if otherID == id {
+			respondError(w, http.StatusBadRequest, "A dog cannot be compatible with itself")
+			return
+		}
+		if seen[otherID] {
+			continue
+		}
+		seen[otherID] = true
+
+		other, err := h.dogRepo.FindByID(otherID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		if other == nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("Dog %d not found", otherID))
+			return
+		}
+	}
+
+	ids := make([]int, 0, len(seen))
+	for otherID := range seen {
+		ids = append(ids, otherID)
+	}
+
+	if err := h.dogRepo.SetCompatibleDogs(id, ids); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update compatible dogs")
+		return
+	}
+
+	h.GetCompatibleDogs(w, r)
+}
+
+// orderBySearchResults keeps the dogs found by the search, in order of relevance
+func orderBySearchResults(dogs []*models.Dog, ids []int) []*models.Dog {
+	byID := make(map[int]*models.Dog, len(dogs))
+	for _, dog := range dogs {
+		byID[dog.ID] = dog
+	}
+
+	ordered := []*models.Dog{}
+	for _, id := range ids {
+		if dog, ok := byID[id]; ok {
+			ordered = append(ordered, dog)
+		}
+	}
+	return ordered
+}
+
+// indexDogForSearch updates the search index of a dog after it, a walk report or an incident changed
+// Failures are only logged; the index is rebuilt at the next start
+func indexDogForSearch(searchRepo *repository.DogSearchRepository, dogID int) {
+	if err := searchRepo.IndexDog(dogID); err != nil {
+		log.Printf("Warning: Failed to update search index of dog %d: %v", dogID, err)
+	}
+}
+
+// loadTags attaches the tags to the dogs
+func (h *DogHandler) loadTags(dogs ...*models.Dog) error {
+	ids := make([]int, len(dogs))
+	for i, dog := range dogs {
+		ids[i] = dog.ID
+	}
+
+	tags, err := h.tagRepo.FindByDogs(ids)
+	if err != nil {
+		return err
+	}
+	for _, dog := range dogs {
+		dog.Tags = tags[dog.ID]
+	}
+	return nil
+}
+
+// parseIDList parses comma-separated IDs like "1,4,7", skipping invalid and duplicate entries
+func parseIDList(value string) []int {
+	ids := []int{}
+	seen := map[int]bool{}
+	for _, part := range strings.Split(value, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// parseStatusList parses a comma-separated list of lifecycle states, skipping unknown ones
+// all is true if the list contains "all"
+func parseStatusList(value string) (statuses []models.DogStatus, all bool) {
+	for _, part := range strings.Split(value, ",") {
+		status := models.DogStatus(strings.TrimSpace(part))
+		if status == "all" {
+			return nil, true
+		}
+		if status.IsValid() {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, false
+}
@@ -53,8 +53,14 @@ func setupTestDB(t *testing.T) *sql.DB {
 			is_available INTEGER DEFAULT 1,
 			unavailable_reason TEXT,
 			unavailable_since TIMESTAMP,
+			min_walkers INTEGER DEFAULT 1,
+			min_co_walker_level TEXT,
+			requires_staff_escort INTEGER DEFAULT 0,
 			is_featured INTEGER DEFAULT 0,
 			external_link TEXT,
+			status TEXT NOT NULL DEFAULT 'available',
+			status_changed_at TIMESTAMP,
+			external_id TEXT UNIQUE,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)
@@ -63,6 +69,23 @@ func setupTestDB(t *testing.T) *sql.DB {
 		t.Fatalf("Failed to create dogs table: %v", err)
 	}
 
+	// Create dog_photos table (gallery, kept in sync with the cover photo)
+	_, err = db.Exec(`
+		CREATE TABLE dog_photos (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			dog_id INTEGER NOT NULL,
+			photo TEXT NOT NULL,
+			photo_thumbnail TEXT,
+			caption TEXT,
+			sort_order INTEGER DEFAULT 0,
+			is_cover INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create dog_photos table: %v", err)
+	}
+
 	return db
 }
 
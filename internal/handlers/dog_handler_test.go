@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/repository"
 	"github.com/tranmh/gassigeher/internal/testutil"
 )
 
@@ -95,6 +98,86 @@ func TestDogHandler_ListDogs(t *testing.T) {
 	})
 }
 
+// DONE: TestDogHandler_SearchDogs tests full-text search with relevance and admin-only history
+func TestDogHandler_SearchDogs(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret", JWTExpirationHours: 24}
+	handler := NewDogHandler(db, cfg)
+
+	bellaID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	maxID := testutil.SeedTestDog(t, db, "Max", "Schäferhund", "blue")
+	db.Exec(`UPDATE dogs SET special_instructions = ? WHERE id = ?`, "Nicht zusammen mit Bella ausführen", maxID)
+
+	userID := testutil.SeedTestUser(t, db, "user@example.com", "User", "green")
+	db.Exec(`INSERT INTO incidents (dog_id, reported_by, incident_type, severity, description, occurred_at)
+		VALUES (?, ?, 'escape', 'low', 'Aus dem Geschirr geschlüpft', CURRENT_TIMESTAMP)`, bellaID, userID)
+	repository.NewDogSearchRepository(db, cfg.DBType).Rebuild()
+
+	search := func(query string, isAdmin bool) []map[string]interface{} {
+		req := httptest.NewRequest("GET", "/api/dogs?search="+url.QueryEscape(query), nil)
+		req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", isAdmin))
+		rec := httptest.NewRecorder()
+		handler.ListDogs(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		var dogs []map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &dogs)
+		return dogs
+	}
+
+	t.Run("relevance order", func(t *testing.T) {
+		dogs := search("bella", false)
+		if len(dogs) != 2 || dogs[0]["name"] != "Bella" || dogs[1]["name"] != "Max" {
+			t.Errorf("Expected Bella before Max, got %v", dogs)
+		}
+	})
+
+	t.Run("umlauts", func(t *testing.T) {
+		dogs := search("schaeferhund", false)
+		if len(dogs) != 1 || dogs[0]["name"] != "Max" {
+			t.Errorf("Expected Max, got %v", dogs)
+		}
+	})
+
+	t.Run("combined with filters", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/dogs?search=bella&category=blue", nil)
+		req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.ListDogs(rec, req)
+		var dogs []map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &dogs)
+		if len(dogs) != 1 || dogs[0]["name"] != "Max" {
+			t.Errorf("Expected only Max, got %v", dogs)
+		}
+	})
+
+	t.Run("incident history for admins only", func(t *testing.T) {
+		if dogs := search("geschirr", false); len(dogs) != 0 {
+			t.Errorf("Expected no dogs for users, got %d", len(dogs))
+		}
+		if dogs := search("geschirr", true); len(dogs) != 1 || dogs[0]["name"] != "Bella" {
+			t.Errorf("Expected Bella for admins, got %v", dogs)
+		}
+	})
+
+	t.Run("new dogs are indexed", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{
+			"name": "Rocky", "breed": "Dackel", "size": "small", "age": 3, "category": "green",
+		})
+		req := httptest.NewRequest("POST", "/api/dogs", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.CreateDog(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		if dogs := search("dackel", false); len(dogs) != 1 || dogs[0]["name"] != "Rocky" {
+			t.Errorf("Expected Rocky, got %v", dogs)
+		}
+	})
+}
+
 // DONE: TestDogHandler_GetDog tests getting single dog by ID
 func TestDogHandler_GetDog(t *testing.T) {
 	db := testutil.SetupTestDB(t)
@@ -539,6 +622,130 @@ func TestDogHandler_ToggleAvailability(t *testing.T) {
 	})
 }
 
+// DONE: TestDogHandler_SetDogStatus tests lifecycle changes, booking cancellation and status filtering
+func TestDogHandler_SetDogStatus(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret", JWTExpirationHours: 24}
+	handler := NewDogHandler(db, cfg)
+
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	userID := testutil.SeedTestUser(t, db, "user@example.com", "User", "green")
+	bellaID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	maxID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+
+	future := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	past := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+	futureBooking := testutil.SeedTestBooking(t, db, userID, bellaID, future, "10:00", "scheduled")
+	pastBooking := testutil.SeedTestBooking(t, db, userID, bellaID, past, "10:00", "completed")
+	groupBooking := testutil.SeedTestBooking(t, db, userID, maxID, future, "15:00", "scheduled")
+	db.Exec(`INSERT INTO booking_dogs (booking_id, dog_id) VALUES (?, ?)`, groupBooking, bellaID)
+
+	setStatus := func(dogID int, reqBody interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest("PUT", fmt.Sprintf("/api/dogs/%d/status", dogID), bytes.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": fmt.Sprintf("%d", dogID)})
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		handler.SetDogStatus(rec, req)
+		return rec
+	}
+
+	listDogs := func(query string, isAdmin bool) []map[string]interface{} {
+		req := httptest.NewRequest("GET", "/api/dogs"+query, nil)
+		req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", isAdmin))
+		rec := httptest.NewRecorder()
+		handler.ListDogs(rec, req)
+		var dogs []map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &dogs)
+		return dogs
+	}
+
+	bookingStatus := func(id int) string {
+		var status string
+		db.QueryRow("SELECT status FROM bookings WHERE id = ?", id).Scan(&status)
+		return status
+	}
+
+	t.Run("reserved keeps bookings", func(t *testing.T) {
+		rec := setStatus(bellaID, map[string]interface{}{"status": "reserved"})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		if bookingStatus(futureBooking) != "scheduled" {
+			t.Error("Reserving a dog should keep its walks")
+		}
+	})
+
+	t.Run("adopted cancels future walks", func(t *testing.T) {
+		rec := setStatus(bellaID, map[string]interface{}{"status": "adopted", "note": "Familie Müller"})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var response map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response["cancelled_count"] != float64(2) {
+			t.Errorf("Expected 2 cancelled walks, got %v", response["cancelled_count"])
+		}
+		if bookingStatus(futureBooking) != "cancelled" || bookingStatus(groupBooking) != "cancelled" {
+			t.Error("Future walks with the dog should be cancelled")
+		}
+		if bookingStatus(pastBooking) != "completed" {
+			t.Error("Walk history should be kept")
+		}
+	})
+
+	t.Run("adopted dogs are hidden by default", func(t *testing.T) {
+		if dogs := listDogs("", false); len(dogs) != 1 || dogs[0]["name"] != "Max" {
+			t.Errorf("Expected only Max, got %v", dogs)
+		}
+		if dogs := listDogs("?status=adopted", false); len(dogs) != 1 {
+			t.Errorf("Expected users to only see active dogs, got %d", len(dogs))
+		}
+		if dogs := listDogs("?status=adopted", true); len(dogs) != 1 || dogs[0]["name"] != "Bella" {
+			t.Errorf("Expected admins to see Bella, got %v", dogs)
+		}
+		if dogs := listDogs("?status=all", true); len(dogs) != 2 {
+			t.Errorf("Expected 2 dogs, got %d", len(dogs))
+		}
+	})
+
+	t.Run("deceased dogs can only be archived", func(t *testing.T) {
+		if rec := setStatus(maxID, map[string]interface{}{"status": "deceased"}); rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		if rec := setStatus(maxID, map[string]interface{}{"status": "available"}); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+		if rec := setStatus(maxID, map[string]interface{}{"status": "archived"}); rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", rec.Code)
+		}
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		if rec := setStatus(bellaID, map[string]interface{}{"status": "lost"}); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("status history", func(t *testing.T) {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/dogs/%d/status-history", bellaID), nil)
+		req = mux.SetURLVars(req, map[string]string{"id": fmt.Sprintf("%d", bellaID)})
+		rec := httptest.NewRecorder()
+		handler.GetDogStatusHistory(rec, req)
+
+		var history []map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &history)
+		if len(history) != 2 {
+			t.Fatalf("Expected 2 changes, got %d", len(history))
+		}
+		if history[0]["status"] != "adopted" || history[0]["note"] != "Familie Müller" ||
+			history[0]["cancelled_bookings"] != float64(2) || history[0]["changed_by_name"] != "Admin" {
+			t.Errorf("Unexpected latest change: %v", history[0])
+		}
+	})
+}
+
 // DONE: TestDogHandler_GetBreeds tests getting list of unique breeds
 func TestDogHandler_GetBreeds(t *testing.T) {
 	db := testutil.SetupTestDB(t)
@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+// DogHealthHandler handles dog health record HTTP requests
+type DogHealthHandler struct {
+	db         *sql.DB
+	cfg        *config.Config
+	healthRepo *repository.DogHealthRepository
+	dogRepo    *repository.DogRepository
+}
+
+// NewDogHealthHandler creates a new dog health handler
+func NewDogHealthHandler(db *sql.DB, cfg *config.Config) *DogHealthHandler {
+	return &DogHealthHandler{
+		db:         db,
+		cfg:        cfg,
+		healthRepo: repository.NewDogHealthRepository(db),
+		dogRepo:    repository.NewDogRepository(db),
+	}
+}
+
+// GetHealthRecords returns all health records of a dog (admin only)
+func (h *DogHealthHandler) GetHealthRecords(w http.ResponseWriter, r *http.Request) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return
+	}
+
+	records, err := h.healthRepo.GetAll(dog.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get health records")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, records)
+}
+
+// GetActiveRestrictions returns the walk restrictions of a dog that apply today
+// Available to all users so walkers know them before booking
+func (h *DogHealthHandler) GetActiveRestrictions(w http.ResponseWriter, r *http.Request) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return
+	}
+
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", date); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid date format")
+		return
+	}
+
+	restrictions, err := h.healthRepo.GetActiveRestrictions(dog.ID, date)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get restrictions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, restrictions)
+}
+
+// CreateVaccination adds a vaccination to a dog (admin only)
+func (h *DogHealthHandler) CreateVaccination(w http.ResponseWriter, r *http.Request) {
+	h.saveVaccination(w, r, false)
+}
+
+// UpdateVaccination updates a vaccination of a dog (admin only)
+func (h *DogHealthHandler) UpdateVaccination(w http.ResponseWriter, r *http.Request) {
+	h.saveVaccination(w, r, true)
+}
+
+// saveVaccination creates or updates a vaccination
+func (h *DogHealthHandler) saveVaccination(w http.ResponseWriter, r *http.Request, update bool) {
+	dogID, recordID, ok := h.requireRecordIDs(w, r, update)
+	if !ok {
+		return
+	}
+
+	var req models.DogVaccinationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	vaccination := &models.DogVaccination{
+		ID:             recordID,
+		DogID:          dogID,
+		Name:           strings.TrimSpace(req.Name),
+		AdministeredOn: req.AdministeredOn,
+		ExpiresOn:      req.ExpiresOn,
+		Notes:          req.Notes,
+	}
+
+	if update {
+		h.respondSaved(w, h.healthRepo.UpdateVaccination(vaccination), http.StatusOK, vaccination)
+	} else {
+		h.respondSaved(w, h.healthRepo.CreateVaccination(vaccination), http.StatusCreated, vaccination)
+	}
+}
+
+// DeleteVaccination deletes a vaccination of a dog (admin only)
+func (h *DogHealthHandler) DeleteVaccination(w http.ResponseWriter, r *http.Request) {
+	h.deleteRecord(w, r, h.healthRepo.DeleteVaccination)
+}
+
+// CreateMedication adds a medication to a dog (admin only)
+func (h *DogHealthHandler) CreateMedication(w http.ResponseWriter, r *http.Request) {
+	h.saveMedication(w, r, false)
+}
+
+// UpdateMedication updates a medication of a dog (admin only)
+func (h *DogHealthHandler) UpdateMedication(w http.ResponseWriter, r *http.Request) {
+	h.saveMedication(w, r, true)
+}
+
+// saveMedication creates or updates a medication
+func (h *DogHealthHandler) saveMedication(w http.ResponseWriter, r *http.Request, update bool) {
+	dogID, recordID, ok := h.requireRecordIDs(w, r, update)
+	if !ok {
+		return
+	}
+
+	var req models.DogMedicationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	dosageTimes := req.DosageTimes
+	if dosageTimes == nil {
+		dosageTimes = []string{}
+	}
+
+	medication := &models.DogMedication{
+		ID:                recordID,
+		DogID:             dogID,
+		Name:              strings.TrimSpace(req.Name),
+		Dosage:            req.Dosage,
+		DosageTimes:       dosageTimes,
+		WalkBufferMinutes: req.WalkBufferMinutes,
+		StartDate:         req.StartDate,
+		EndDate:           req.EndDate,
+		Notes:             req.Notes,
+	}
+
+	if update {
+		h.respondSaved(w, h.healthRepo.UpdateMedication(medication), http.StatusOK, medication)
+	} else {
+		h.respondSaved(w, h.healthRepo.CreateMedication(medication), http.StatusCreated, medication)
+	}
+}
+
+// DeleteMedication deletes a medication of a dog (admin only)
+func (h *DogHealthHandler) DeleteMedication(w http.ResponseWriter, r *http.Request) {
+	h.deleteRecord(w, r, h.healthRepo.DeleteMedication)
+}
+
+// CreateVetAppointment adds a vet appointment to a dog (admin only)
+func (h *DogHealthHandler) CreateVetAppointment(w http.ResponseWriter, r *http.Request) {
+	h.saveVetAppointment(w, r, false)
+}
+
+// UpdateVetAppointment updates a vet appointment of a dog (admin only)
+func (h *DogHealthHandler) UpdateVetAppointment(w http.ResponseWriter, r *http.Request) {
+	h.saveVetAppointment(w, r, true)
+}
+
+// saveVetAppointment creates or updates a vet appointment
+func (h *DogHealthHandler) saveVetAppointment(w http.ResponseWriter, r *http.Request, update bool) {
+	dogID, recordID, ok := h.requireRecordIDs(w, r, update)
+	if !ok {
+		return
+	}
+
+	var req models.DogVetAppointmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	appointment := &models.DogVetAppointment{
+		ID:              recordID,
+		DogID:           dogID,
+		AppointmentDate: req.AppointmentDate,
+		AppointmentTime: req.AppointmentTime,
+		VetName:         req.VetName,
+		Purpose:         strings.TrimSpace(req.Purpose),
+		Notes:           req.Notes,
+	}
+
+	if update {
+		h.respondSaved(w, h.healthRepo.UpdateVetAppointment(appointment), http.StatusOK, appointment)
+	} else {
+		h.respondSaved(w, h.healthRepo.CreateVetAppointment(appointment), http.StatusCreated, appointment)
+	}
+}
+
+// DeleteVetAppointment deletes a vet appointment of a dog (admin only)
+func (h *DogHealthHandler) DeleteVetAppointment(w http.ResponseWriter, r *http.Request) {
+	h.deleteRecord(w, r, h.healthRepo.DeleteVetAppointment)
+}
+
+// CreateRestriction adds a walk restriction to a dog (admin only)
+func (h *DogHealthHandler) CreateRestriction(w http.ResponseWriter, r *http.Request) {
+	h.saveRestriction(w, r, false)
+}
+
+// UpdateRestriction updates a walk restriction of a dog (admin only)
+func (h *DogHealthHandler) UpdateRestriction(w http.ResponseWriter, r *http.Request) {
+	h.saveRestriction(w, r, true)
+}
+
+// saveRestriction creates or updates a walk restriction
+func (h *DogHealthHandler) saveRestriction(w http.ResponseWriter, r *http.Request, update bool) {
+	dogID, recordID, ok := h.requireRecordIDs(w, r, update)
+	if !ok {
+		return
+	}
+
+	var req models.DogRestrictionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	restriction := &models.DogRestriction{
+		ID:             recordID,
+		DogID:          dogID,
+		Restriction:    strings.TrimSpace(req.Restriction),
+		MaxWalkMinutes: req.MaxWalkMinutes,
+		ValidFrom:      req.ValidFrom,
+		ValidUntil:     req.ValidUntil,
+	}
+
+	if update {
+		h.respondSaved(w, h.healthRepo.UpdateRestriction(restriction), http.StatusOK, restriction)
+	} else {
+		h.respondSaved(w, h.healthRepo.CreateRestriction(restriction), http.StatusCreated, restriction)
+	}
+}
+
+// DeleteRestriction deletes a walk restriction of a dog (admin only)
+func (h *DogHealthHandler) DeleteRestriction(w http.ResponseWriter, r *http.Request) {
+	h.deleteRecord(w, r, h.healthRepo.DeleteRestriction)
+}
+
+// requireRecordIDs parses the dog ID and, for updates and deletes, the record ID from the URL
+func (h *DogHealthHandler) requireRecordIDs(w http.ResponseWriter, r *http.Request, withRecord bool) (int, int, bool) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return 0, 0, false
+	}
+	if !withRecord {
+		return dog.ID, 0, true
+	}
+
+	recordID, err := strconv.Atoi(mux.Vars(r)["recordId"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid record ID")
+		return 0, 0, false
+	}
+
+	return dog.ID, recordID, true
+}
+
+// respondSaved responds with the saved record or maps the repository error
+func (h *DogHealthHandler) respondSaved(w http.ResponseWriter, err error, status int, record interface{}) {
+	if err != nil {
+		if err.Error() == "health record not found" {
+			respondError(w, http.StatusNotFound, "Health record not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to save health record")
+		return
+	}
+
+	respondJSON(w, status, record)
+}
+
+// deleteRecord deletes a health record of the dog in the URL
+func (h *DogHealthHandler) deleteRecord(w http.ResponseWriter, r *http.Request, deleteFn func(dogID, id int) error) {
+	dogID, recordID, ok := h.requireRecordIDs(w, r, true)
+	if !ok {
+		return
+	}
+
+	if err := deleteFn(dogID, recordID); err != nil {
+		if err.Error() == "health record not found" {
+			respondError(w, http.StatusNotFound, "Health record not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to delete health record")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Health record deleted successfully"})
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogHealthHandler tests admin CRUD of dog health records
+func TestDogHealthHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewDogHealthHandler(db, cfg)
+
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	otherDogID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+
+	call := func(fn http.HandlerFunc, method string, vars map[string]string, reqBody interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(method, "/api/dogs/"+vars["id"], bytes.NewReader(body))
+		req = mux.SetURLVars(req, vars)
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+
+	dogVars := map[string]string{"id": fmt.Sprintf("%d", dogID)}
+	var vaccination models.DogVaccination
+
+	t.Run("create vaccination", func(t *testing.T) {
+		rec := call(handler.CreateVaccination, "POST", dogVars, map[string]interface{}{
+			"name":       "Tollwut",
+			"expires_on": "2026-03-01",
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		json.Unmarshal(rec.Body.Bytes(), &vaccination)
+	})
+
+	t.Run("invalid vaccination", func(t *testing.T) {
+		rec := call(handler.CreateVaccination, "POST", dogVars, map[string]interface{}{"name": "Tollwut"})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("update vaccination of another dog", func(t *testing.T) {
+		vars := map[string]string{"id": fmt.Sprintf("%d", otherDogID), "recordId": fmt.Sprintf("%d", vaccination.ID)}
+		rec := call(handler.UpdateVaccination, "PUT", vars, map[string]interface{}{"name": "Tollwut", "expires_on": "2027-03-01"})
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("create restriction and medication", func(t *testing.T) {
+		rec := call(handler.CreateRestriction, "POST", dogVars, map[string]interface{}{
+			"restriction":      "Keine Treppen",
+			"max_walk_minutes": 20,
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = call(handler.CreateMedication, "POST", dogVars, map[string]interface{}{
+			"name":                "Schmerzmittel",
+			"dosage_times":        []string{"12:00"},
+			"walk_buffer_minutes": 30,
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("get health records", func(t *testing.T) {
+		rec := call(handler.GetHealthRecords, "GET", dogVars, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+
+		var records models.DogHealthRecords
+		json.Unmarshal(rec.Body.Bytes(), &records)
+		if len(records.Vaccinations) != 1 || len(records.Restrictions) != 1 || len(records.Medications) != 1 {
+			t.Errorf("Unexpected records: %s", rec.Body.String())
+		}
+	})
+
+	t.Run("active restrictions", func(t *testing.T) {
+		rec := call(handler.GetActiveRestrictions, "GET", dogVars, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+
+		var restrictions []models.DogRestriction
+		json.Unmarshal(rec.Body.Bytes(), &restrictions)
+		if len(restrictions) != 1 || restrictions[0].Restriction != "Keine Treppen" {
+			t.Errorf("Expected 1 active restriction, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("delete vaccination", func(t *testing.T) {
+		vars := map[string]string{"id": fmt.Sprintf("%d", dogID), "recordId": fmt.Sprintf("%d", vaccination.ID)}
+		rec := call(handler.DeleteVaccination, "DELETE", vars, nil)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", rec.Code)
+		}
+
+		rec = call(handler.DeleteVaccination, "DELETE", vars, nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for already deleted record, got %d", rec.Code)
+		}
+	})
+
+	t.Run("unknown dog", func(t *testing.T) {
+		rec := call(handler.GetHealthRecords, "GET", map[string]string{"id": "9999"}, nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+}
+
+// DONE: TestBookingHandler_MedicationTimes tests that walks keep their distance to dosage times
+func TestBookingHandler_MedicationTimes(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewBookingHandler(db, cfg)
+
+	userID := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	medicationID := 0
+	db.QueryRow(`
+		INSERT INTO dog_medications (dog_id, name, dosage_times, walk_buffer_minutes)
+		VALUES (?, 'Insulin', '08:30', 60) RETURNING id
+	`, dogID).Scan(&medicationID)
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+	createBooking := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{
+			"dog_id":         dogID,
+			"date":           tomorrow,
+			"scheduled_time": "09:00",
+		})
+		req := httptest.NewRequest("POST", "/api/bookings", bytes.NewReader(body))
+		req = req.WithContext(contextWithUser(req.Context(), userID, "walker@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.CreateBooking(rec, req)
+		return rec
+	}
+
+	t.Run("walk too close to dosage time", func(t *testing.T) {
+		rec := createBooking()
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status 400, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		if !strings.Contains(rec.Body.String(), "Insulin") {
+			t.Errorf("Expected error to name the medication, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("finished medication no longer blocks walks", func(t *testing.T) {
+		yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+		db.Exec("UPDATE dog_medications SET end_date = ? WHERE id = ?", yesterday, medicationID)
+
+		rec := createBooking()
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
@@ -0,0 +1,478 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+	"github.com/xuri/excelize/v2"
+)
+
+// maxDogImportRows limits the number of dogs in one import file
+const maxDogImportRows = 1000
+
+// dogExportColumns are the columns of the dog export; id, status and is_available are ignored on import
+var dogExportColumns = append(append([]string{"id"}, models.DogImportColumns...), "status", "is_available")
+
+// DogImportHandler handles bulk import and export of dogs
+type DogImportHandler struct {
+	db         *sql.DB
+	cfg        *config.Config
+	dogRepo    *repository.DogRepository
+	searchRepo *repository.DogSearchRepository
+	audit      *services.AuditService
+}
+
+// NewDogImportHandler creates a new dog import handler
+func NewDogImportHandler(db *sql.DB, cfg *config.Config) *DogImportHandler {
+	return &DogImportHandler{
+		db:         db,
+		cfg:        cfg,
+		dogRepo:    repository.NewDogRepository(db),
+		searchRepo: repository.NewDogSearchRepository(db, cfg.DBType),
+		audit:      newAuditService(db),
+	}
+}
+
+// ImportDogs handles POST /api/admin/dogs/import - create or update dogs from a CSV or XLSX file (admin only)
+// Form fields: file, format (csv or xlsx, default from the file name), mapping (JSON object of
+// file column -> dog field) and dry_run. Dogs are matched by external_id. Nothing is written if
+// a row is invalid or a dog fails to save, a dry run returns the preview with row-level errors.
+func (h *DogImportHandler) ImportDogs(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(int64(h.cfg.MaxUploadSizeMB) << 20); err != nil {
+		respondError(w, http.StatusBadRequest, "File too large")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "No file uploaded")
+		return
+	}
+	defer file.Close()
+
+	format := strings.ToLower(r.FormValue("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+	if format != "csv" && format != "xlsx" {
+		respondError(w, http.StatusBadRequest, "Format must be csv or xlsx")
+		return
+	}
+
+	mapping := map[string]string{}
+	if value := r.FormValue("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid column mapping")
+			return
+		}
+	}
+
+	records, err := readDogSheet(file, format)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Could not read the file: "+err.Error())
+		return
+	}
+	if len(records) < 2 {
+		respondError(w, http.StatusBadRequest, "The file has no dogs")
+		return
+	}
+	if len(records)-1 > maxDogImportRows {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("At most %d dogs can be imported at once", maxDogImportRows))
+		return
+	}
+
+	columns, ignored, err := models.ResolveDogImportColumns(records[0], mapping)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid column mapping: "+err.Error())
+		return
+	}
+
+	result := &models.DogImportResult{
+		DryRun:         r.FormValue("dry_run") == "true",
+		Columns:        map[string]string{},
+		IgnoredColumns: ignored,
+		Rows:           []*models.DogImportRow{},
+	}
+	for i, field := range columns {
+		result.Columns[records[0][i]] = field
+	}
+
+	planned, err := h.planImport(records, columns, result)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to check dogs")
+		return
+	}
+
+	if result.DryRun {
+		respondJSON(w, http.StatusOK, result)
+		return
+	}
+	if result.Failed > 0 {
+		result.Error = "The file has invalid rows, no dogs were imported"
+		respondJSON(w, http.StatusBadRequest, result)
+		return
+	}
+
+	if err := h.applyImport(planned); err != nil {
+		log.Printf("Error importing dogs: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to import dogs, no dogs were imported")
+		return
+	}
+	h.audit.Record(auditActor(r), models.AuditDogImport, models.AuditTargetDog, nil, nil, map[string]interface{}{
+		"total": result.Total, "created": result.Created, "updated": result.Updated, "failed": result.Failed,
+	})
+	respondJSON(w, http.StatusOK, result)
+}
+
+// plannedDogImport is a valid row of an import, ready to be written
+type plannedDogImport struct {
+	row      *models.DogImportRow
+	req      *models.CreateDogRequest
+	existing *models.Dog
+}
+
+// planImport validates all rows and decides whether each dog is created or updated
+func (h *DogImportHandler) planImport(records [][]string, columns map[int]string, result *models.DogImportResult) ([]*plannedDogImport, error) {
+	planned := []*plannedDogImport{}
+	externalIDs := map[string]int{}
+
+	for i, record := range records[1:] {
+		values := map[string]string{}
+		blank := true
+		for col, field := range columns {
+			if col < len(record) {
+				values[field] = unescapeFormula(record[col])
+				if strings.TrimSpace(record[col]) != "" {
+					blank = false
+				}
+			} else {
+				values[field] = ""
+			}
+		}
+		if blank {
+			continue
+		}
+
+		row := &models.DogImportRow{Row: i + 2, Errors: []string{}}
+		result.Rows = append(result.Rows, row)
+		result.Total++
+
+		var existing *models.Dog
+		if externalID := strings.TrimSpace(values["external_id"]); externalID != "" {
+			row.ExternalID = &externalID
+			if first, ok := externalIDs[externalID]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("external_id: Already used in row %d", first))
+			}
+			externalIDs[externalID] = row.Row
+
+			dogID, err := h.dogRepo.FindByExternalID(externalID)
+			if err != nil {
+				return nil, err
+			}
+			if dogID != 0 {
+				if existing, err = h.dogRepo.FindByID(dogID); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		// Updates keep the values of columns that are not in the file
+		req := &models.CreateDogRequest{}
+		if existing != nil {
+			req = models.CreateDogRequestFromDog(existing)
+		}
+		row.Errors = append(row.Errors, models.ApplyDogImportValues(req, values)...)
+		if len(row.Errors) == 0 {
+			if err := req.Validate(); err != nil {
+				row.Errors = append(row.Errors, err.Error())
+			}
+		}
+		row.Name = req.Name
+
+		if len(row.Errors) > 0 {
+			row.Action = models.DogImportActionError
+			result.Failed++
+			continue
+		}
+
+		row.Errors = nil
+		if existing != nil {
+			row.Action = models.DogImportActionUpdate
+			row.DogID = &existing.ID
+			result.Updated++
+		} else {
+			row.Action = models.DogImportActionCreate
+			result.Created++
+		}
+		planned = append(planned, &plannedDogImport{row: row, req: req, existing: existing})
+	}
+
+	return planned, nil
+}
+
+// applyImport writes the planned dogs in one transaction; if one fails, none are written
+// Imported dogs do not trigger new dog notifications, so onboarding a shelter sends no mass emails.
+func (h *DogImportHandler) applyImport(planned []*plannedDogImport) error {
+	dogs := make([]*models.Dog, len(planned))
+	for i, p := range planned {
+		if p.existing != nil {
+			applyDogRequest(p.existing, p.req)
+			dogs[i] = p.existing
+		} else {
+			dogs[i] = newDogFromRequest(p.req)
+		}
+	}
+
+	if err := h.dogRepo.Import(dogs); err != nil {
+		return err
+	}
+
+	for i, p := range planned {
+		p.row.DogID = &dogs[i].ID
+		indexDogForSearch(h.searchRepo, dogs[i].ID)
+	}
+	return nil
+}
+
+// applyDogRequest sets the importable fields of an existing dog from a validated request
+func applyDogRequest(dog *models.Dog, req *models.CreateDogRequest) {
+	dog.Name = req.Name
+	dog.Breed = req.Breed
+	dog.Size = req.Size
+	dog.Age = req.Age
+	dog.Category = req.Category
+	dog.SpecialNeeds = req.SpecialNeeds
+	dog.PickupLocation = req.PickupLocation
+	dog.WalkRoute = req.WalkRoute
+	dog.WalkDuration = req.WalkDuration
+	dog.SpecialInstructions = req.SpecialInstructions
+	dog.DefaultMorningTime = req.DefaultMorningTime
+	dog.DefaultEveningTime = req.DefaultEveningTime
+	dog.ExternalLink = req.ExternalLink
+	dog.MinWalkers = 1
+	if req.MinWalkers != nil {
+		dog.MinWalkers = *req.MinWalkers
+	}
+	dog.MinCoWalkerLevel = req.MinCoWalkerLevel
+	dog.RequiresStaffEscort = req.RequiresStaffEscort != nil && *req.RequiresStaffEscort
+	dog.ExternalID = req.ExternalID
+}
+
+// ExportDogs handles GET /api/admin/dogs/export - all dogs as CSV or XLSX (admin only)
+// The file can be edited and imported again
+func (h *DogImportHandler) ExportDogs(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "xlsx" {
+		respondError(w, http.StatusBadRequest, "Format must be csv or xlsx")
+		return
+	}
+
+	dogs, err := h.dogRepo.FindAll(nil)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get dogs")
+		return
+	}
+
+	rows := [][]interface{}{}
+	for _, dog := range dogs {
+		rows = append(rows, dogExportRow(dog))
+	}
+
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		err = writeDogXLSX(&buf, rows)
+	} else {
+		err = writeDogCSV(&buf, rows)
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to export dogs")
+		return
+	}
+
+	filename := fmt.Sprintf("hunde-%s.%s", time.Now().Format("2006-01-02"), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// dogExportRow returns the cells of a dog in the order of dogExportColumns, with text escaped for spreadsheets
+func dogExportRow(dog *models.Dog) []interface{} {
+	optional := func(value *string) interface{} {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	optionalInt := func(value *int) interface{} {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	yesNo := func(value bool) string {
+		if value {
+			return "yes"
+		}
+		return "no"
+	}
+
+	row := []interface{}{
+		dog.ID,
+		optional(dog.ExternalID),
+		dog.Name,
+		dog.Breed,
+		dog.Size,
+		dog.Age,
+		dog.Category,
+		optional(dog.SpecialNeeds),
+		optional(dog.PickupLocation),
+		optional(dog.WalkRoute),
+		optionalInt(dog.WalkDuration),
+		optional(dog.SpecialInstructions),
+		optional(dog.DefaultMorningTime),
+		optional(dog.DefaultEveningTime),
+		optional(dog.ExternalLink),
+		dog.MinWalkers,
+		optional(dog.MinCoWalkerLevel),
+		yesNo(dog.RequiresStaffEscort),
+		string(dog.Status),
+		yesNo(dog.IsAvailable),
+	}
+	for i, cell := range row {
+		if text, ok := cell.(string); ok {
+			row[i] = escapeFormula(text)
+		}
+	}
+	return row
+}
+
+// formulaPrefixes are the first characters that make spreadsheet programs run a cell as formula
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula prefixes text that would run as formula with ', so it is shown as text (CSV injection)
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeFormula removes the ' added by escapeFormula, so exported files can be imported again
+func unescapeFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// writeDogCSV writes the export as UTF-8 CSV with a byte order mark, so Excel shows umlauts correctly
+func writeDogCSV(out io.Writer, rows [][]interface{}) error {
+	if _, err := io.WriteString(out, "\ufeff"); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(out)
+	if err := writer.Write(dogExportColumns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			record[i] = fmt.Sprint(cell)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeDogXLSX writes the export as a single sheet workbook
+func writeDogXLSX(out io.Writer, rows [][]interface{}) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := "Hunde"
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(dogExportColumns))
+	for i, column := range dogExportColumns {
+		header[i] = column
+	}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return err
+		}
+	}
+
+	return f.Write(out)
+}
+
+// readDogSheet reads all rows of a CSV file or of the first sheet of an XLSX file
+func readDogSheet(file io.Reader, format string) ([][]string, error) {
+	if format == "xlsx" {
+		f, err := excelize.OpenReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open workbook: %w", err)
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("workbook has no sheets")
+		}
+		return f.GetRows(sheets[0])
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectCSVDelimiter(data)
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// detectCSVDelimiter returns ';' for files saved by German Excel, ',' otherwise
+func detectCSVDelimiter(data []byte) rune {
+	firstLine := data
+	if end := bytes.IndexByte(data, '\n'); end >= 0 {
+		firstLine = data[:end]
+	}
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
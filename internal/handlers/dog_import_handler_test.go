@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+	"github.com/xuri/excelize/v2"
+)
+
+// DONE: TestDogImportHandler tests the dry run preview, upsert by external ID and the export
+func TestDogImportHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret", MaxUploadSizeMB: 10}
+	handler := NewDogImportHandler(db, cfg)
+	dogRepo := repository.NewDogRepository(db)
+
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	existingID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	existing, _ := dogRepo.FindByID(existingID)
+	externalID := "T-1"
+	existing.ExternalID = &externalID
+	duration := 30
+	existing.WalkDuration = &duration
+	if err := dogRepo.Update(existing); err != nil {
+		t.Fatalf("Failed to set external ID: %v", err)
+	}
+
+	upload := func(filename string, content []byte, fields map[string]string) (*httptest.ResponseRecorder, models.DogImportResult) {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", filename)
+		part.Write(content)
+		for key, value := range fields {
+			writer.WriteField(key, value)
+		}
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/api/admin/dogs/import", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		handler.ImportDogs(rec, req)
+
+		var result models.DogImportResult
+		json.Unmarshal(rec.Body.Bytes(), &result)
+		return rec, result
+	}
+
+	dogCount := func() int {
+		dogs, _ := dogRepo.FindAll(nil)
+		return len(dogs)
+	}
+
+	// German Excel saves CSV with semicolons
+	invalidCSV := []byte("Tiernummer;Rufname;Rasse;Größe;Kategorie;Morgens\n" +
+		"T-1;Bella;Labrador;large;blue;08:00\n" +
+		"T-2;Rocky;Dackel;tiny;green;\n" +
+		"T-3;Luna;Mischling;medium;green;morgens\n" +
+		";;;;;\n")
+	mapping := `{"Tiernummer":"external_id","Rufname":"name","Rasse":"breed","Größe":"size","Kategorie":"category","Morgens":"default_morning_time"}`
+
+	t.Run("dry run reports row errors", func(t *testing.T) {
+		rec, result := upload("hunde.csv", invalidCSV, map[string]string{"mapping": mapping, "dry_run": "true"})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if result.Total != 3 || result.Updated != 1 || result.Failed != 2 {
+			t.Errorf("Expected 3 rows with 1 update and 2 errors, got %+v", result)
+		}
+		if result.Rows[0].Action != models.DogImportActionUpdate || *result.Rows[0].DogID != existingID {
+			t.Errorf("Expected row 2 to update Bella, got %+v", result.Rows[0])
+		}
+		if result.Rows[1].Row != 3 || !strings.HasPrefix(result.Rows[1].Errors[0], "size:") {
+			t.Errorf("Expected size error in row 3, got %+v", result.Rows[1])
+		}
+		if !strings.HasPrefix(result.Rows[2].Errors[0], "default_morning_time:") {
+			t.Errorf("Expected time error in row 4, got %+v", result.Rows[2])
+		}
+		if dogCount() != 1 {
+			t.Error("Dry run must not create dogs")
+		}
+	})
+
+	t.Run("invalid rows block the import", func(t *testing.T) {
+		rec, result := upload("hunde.csv", invalidCSV, map[string]string{"mapping": mapping})
+		if rec.Code != http.StatusBadRequest || result.Error == "" {
+			t.Errorf("Expected status 400 with error, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if dogCount() != 1 {
+			t.Error("No dogs must be imported if a row is invalid")
+		}
+	})
+
+	t.Run("import creates and updates dogs", func(t *testing.T) {
+		validCSV := []byte("external_id,name,breed,size,category,requires_staff_escort\n" +
+			"T-1,Bella,Labrador,large,blue,nein\n" +
+			"T-2,Rocky,Dackel,small,green,ja\n" +
+			",Luna,Mischling,medium,green,\n")
+		rec, result := upload("hunde.csv", validCSV, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if result.Created != 2 || result.Updated != 1 || result.Failed != 0 {
+			t.Errorf("Expected 2 created and 1 updated, got %+v", result)
+		}
+
+		bella, _ := dogRepo.FindByID(existingID)
+		if bella.Category != "blue" || bella.Size != "large" {
+			t.Errorf("Expected Bella to be updated, got %+v", bella)
+		}
+		if bella.WalkDuration == nil || *bella.WalkDuration != 30 {
+			t.Error("Fields without a column must be kept on update")
+		}
+
+		rockyID, _ := dogRepo.FindByExternalID("T-2")
+		rocky, _ := dogRepo.FindByID(rockyID)
+		if rocky == nil || !rocky.RequiresStaffEscort || !rocky.IsAvailable {
+			t.Errorf("Expected Rocky to be created with staff escort, got %+v", rocky)
+		}
+
+		// Dogs with an external ID are updated when imported again
+		_, result = upload("hunde.csv", validCSV[:bytes.LastIndex(validCSV, []byte(",Luna"))], nil)
+		if result.Created != 0 || result.Updated != 2 {
+			t.Errorf("Expected re-import to update, got %+v", result)
+		}
+	})
+
+	t.Run("duplicate external IDs and unknown format", func(t *testing.T) {
+		_, result := upload("hunde.csv", []byte("external_id,name,breed,size,category\nT-9,A,B,small,green\nT-9,C,D,small,green\n"), map[string]string{"dry_run": "true"})
+		if result.Failed != 1 || !strings.Contains(result.Rows[1].Errors[0], "row 2") {
+			t.Errorf("Expected duplicate external ID error, got %+v", result)
+		}
+
+		rec, _ := upload("hunde.txt", []byte("name\nA\n"), nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	export := func(format string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/admin/dogs/export?format="+format, nil)
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		handler.ExportDogs(rec, req)
+		return rec
+	}
+
+	t.Run("export csv", func(t *testing.T) {
+		rec := export("csv")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Header().Get("Content-Disposition"), ".csv") {
+			t.Errorf("Expected csv attachment, got %q", rec.Header().Get("Content-Disposition"))
+		}
+		records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(rec.Body.String(), "\ufeff"))).ReadAll()
+		if err != nil {
+			t.Fatalf("Invalid CSV: %v", err)
+		}
+		if len(records) != 4 || records[0][0] != "id" || records[0][1] != "external_id" {
+			t.Errorf("Expected header and 3 dogs, got %v", records)
+		}
+	})
+
+	t.Run("export xlsx can be imported again", func(t *testing.T) {
+		rec := export("xlsx")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		f, err := excelize.OpenReader(bytes.NewReader(rec.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("Invalid XLSX: %v", err)
+		}
+		rows, _ := f.GetRows(f.GetSheetList()[0])
+		if len(rows) != 4 {
+			t.Errorf("Expected header and 3 dogs, got %d rows", len(rows))
+		}
+
+		_, result := upload("hunde.xlsx", rec.Body.Bytes(), nil)
+		if result.Updated != 2 || result.Created != 1 || result.Failed != 0 {
+			t.Errorf("Expected 2 updates and 1 new dog without external ID, got %+v", result)
+		}
+		if len(result.IgnoredColumns) != 3 {
+			t.Errorf("Expected id, status and is_available to be ignored, got %v", result.IgnoredColumns)
+		}
+	})
+
+	t.Run("export escapes formulas and import reads them back", func(t *testing.T) {
+		bella, _ := dogRepo.FindByID(existingID)
+		bella.Breed = "=HYPERLINK(\"http://evil.example\")"
+		if err := dogRepo.Update(bella); err != nil {
+			t.Fatalf("Failed to update dog: %v", err)
+		}
+
+		rec := export("csv")
+		if !strings.Contains(rec.Body.String(), "'=HYPERLINK") {
+			t.Errorf("Expected formula to be prefixed with ', got %s", rec.Body.String())
+		}
+
+		rec = export("xlsx")
+		f, err := excelize.OpenReader(bytes.NewReader(rec.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("Invalid XLSX: %v", err)
+		}
+		rows, _ := f.GetRows(f.GetSheetList()[0])
+		if rows[1][3] != "'"+bella.Breed {
+			t.Errorf("Expected escaped breed in XLSX, got %q", rows[1][3])
+		}
+
+		bella.Breed = "Labrador"
+		dogRepo.Update(bella)
+		if _, result := upload("hunde.xlsx", rec.Body.Bytes(), nil); result.Failed != 0 {
+			t.Fatalf("Expected import to succeed, got %+v", result)
+		}
+		bella, _ = dogRepo.FindByID(existingID)
+		if bella.Breed != "=HYPERLINK(\"http://evil.example\")" {
+			t.Errorf("Expected ' to be removed on import, got %q", bella.Breed)
+		}
+	})
+
+	t.Run("invalid export format", func(t *testing.T) {
+		if rec := export("pdf"); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// DogPageHandler serves the public dog pages with link previews for messengers and social networks
+type DogPageHandler struct {
+	db      *sql.DB
+	cfg     *config.Config
+	dogRepo *repository.DogRepository
+	storage services.Storage
+}
+
+// NewDogPageHandler creates a new dog page handler
+func NewDogPageHandler(db *sql.DB, cfg *config.Config) *DogPageHandler {
+	return &DogPageHandler{
+		db:      db,
+		cfg:     cfg,
+		dogRepo: repository.NewDogRepository(db),
+		storage: services.NewStorageFromConfig(cfg),
+	}
+}
+
+// dogPage is the data of the dog page template
+type dogPage struct {
+	Dog         *models.PublicDog
+	Title       string
+	Description string
+	ImageURL    string
+	Note        string
+	BookingURL  string
+}
+
+// GetDogPage handles GET /hund/{id} - server-rendered dog page with Open Graph tags (public)
+// Booking walks happens in the frontend, which the page links to
+func (h *DogPageHandler) GetDogPage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		h.renderNotFound(w)
+		return
+	}
+
+	dog, err := h.dogRepo.FindByID(id)
+	if err != nil {
+		log.Printf("Error fetching dog %d for dog page: %v", id, err)
+		http.Error(w, "Failed to fetch dog", http.StatusInternalServerError)
+		return
+	}
+	// Pages of deceased and archived dogs are gone, the dog stays only in the history
+	if dog == nil || (!dog.Status.IsActive() && dog.Status != models.DogStatusAdopted) {
+		h.renderNotFound(w)
+		return
+	}
+
+	public := toPublicDog(dog, strings.TrimRight(h.cfg.BaseURL, "/"), h.storage, false)
+	page := &dogPage{
+		Dog:   public,
+		Title: fmt.Sprintf("%s – %s", public.Name, public.Breed),
+		Note:  dogPageNote(dog),
+	}
+
+	summary := fmt.Sprintf("%s, %s, %s.", public.Breed, ageLabel(public.Age), public.SizeLabel())
+	if page.Note != "" {
+		page.Description = summary + " " + page.Note
+	} else {
+		page.Description = fmt.Sprintf("%s %s freut sich auf einen Spaziergang mit dir.", summary, public.Name)
+	}
+
+	// Previews use the small cover photo, it loads fast on mobile connections
+	if public.ThumbnailURL != nil {
+		page.ImageURL = *public.ThumbnailURL
+	} else if public.PhotoURL != nil {
+		page.ImageURL = *public.PhotoURL
+	}
+	if dog.Status.IsBookable() && dog.IsAvailable {
+		page.BookingURL = fmt.Sprintf("/dogs.html?dog=%d", dog.ID)
+	}
+
+	var buf bytes.Buffer
+	if err := dogPageTemplate.Execute(&buf, page); err != nil {
+		log.Printf("Error rendering dog page: %v", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		return
+	}
+	serveCached(w, r, "text/html; charset=utf-8", buf.Bytes(), &public.UpdatedAt)
+}
+
+// renderNotFound writes the dog page for unknown dogs
+func (h *DogPageHandler) renderNotFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	if err := dogPageTemplate.Execute(w, &dogPage{Title: "Hund nicht gefunden"}); err != nil {
+		log.Printf("Error rendering dog page: %v", err)
+	}
+}
+
+// dogPageNote returns the hint on the page of dogs that are not looking for a home anymore
+func dogPageNote(dog *models.Dog) string {
+	switch dog.Status {
+	case models.DogStatusReserved:
+		return fmt.Sprintf("%s ist bereits reserviert, freut sich bis zum Auszug aber weiter über Spaziergänge.", dog.Name)
+	case models.DogStatusTrialAdoption:
+		return fmt.Sprintf("%s wohnt gerade zur Probe in einem neuen Zuhause.", dog.Name)
+	case models.DogStatusAdopted:
+		return fmt.Sprintf("%s hat ein Zuhause gefunden.", dog.Name)
+	}
+	if !dog.IsAvailable {
+		return fmt.Sprintf("%s kann im Moment nicht ausgeführt werden.", dog.Name)
+	}
+	return ""
+}
+
+// dogPageTemplate renders the public dog page; the meta tags are read by link previews
+var dogPageTemplate = template.Must(template.New("dog-page").Funcs(template.FuncMap{
+	"age": ageLabel,
+}).Parse(`<!DOCTYPE html>
+<html lang="de">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}} - Gassigeher</title>
+{{- with .Dog}}
+    <meta name="description" content="{{$.Description}}">
+    <link rel="canonical" href="{{.URL}}">
+    <meta property="og:type" content="website">
+    <meta property="og:site_name" content="Gassigeher">
+    <meta property="og:locale" content="de_DE">
+    <meta property="og:title" content="{{$.Title}}">
+    <meta property="og:description" content="{{$.Description}}">
+    <meta property="og:url" content="{{.URL}}">
+{{- if $.ImageURL}}
+    <meta property="og:image" content="{{$.ImageURL}}">
+    <meta property="og:image:alt" content="{{.Name}}">
+{{- end}}
+    <meta name="twitter:card" content="{{if $.ImageURL}}summary_large_image{{else}}summary{{end}}">
+    <meta name="twitter:title" content="{{$.Title}}">
+    <meta name="twitter:description" content="{{$.Description}}">
+{{- if $.ImageURL}}
+    <meta name="twitter:image" content="{{$.ImageURL}}">
+{{- end}}
+{{- else}}
+    <meta name="robots" content="noindex">
+{{- end}}
+    <link rel="stylesheet" href="/assets/css/main.css">
+</head>
+<body>
+    <header>
+        <div class="container">
+            <a href="/" class="logo">🐕 Gassigeher</a>
+        </div>
+    </header>
+
+    <main class="container-narrow" style="padding: 30px 20px;">
+        <div class="card">
+{{- with .Dog}}
+            {{if .PhotoURL}}<img src="{{.PhotoURL}}" alt="{{.Name}}" style="width: 100%; max-height: 420px; object-fit: cover; border-radius: 8px;">{{end}}
+            <h1>{{.Name}}</h1>
+            <p>{{.Breed}} · {{age .Age}} · {{.SizeLabel}}</p>
+            {{if $.Note}}<div class="alert alert-info">{{$.Note}}</div>{{end}}
+            {{if $.BookingURL}}<a href="{{$.BookingURL}}" class="btn btn-block">Mit {{.Name}} Gassi gehen</a>{{end}}
+{{- else}}
+            <h1>Hund nicht gefunden</h1>
+            <p>Diesen Hund gibt es nicht mehr bei uns.</p>
+            <a href="/dogs.html" class="btn">Alle Hunde ansehen</a>
+{{- end}}
+        </div>
+    </main>
+
+    <footer>
+        <div class="container">
+            <p>&copy; 2025 Gassigeher. Alle Rechte vorbehalten.</p>
+        </div>
+    </footer>
+</body>
+</html>
+`))
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogPageHandler tests the Open Graph tags, escaping and hidden dogs of the public dog pages
+func TestDogPageHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret", BaseURL: "https://gassi.example.org", UploadDir: t.TempDir()}
+	handler := NewDogPageHandler(db, cfg)
+	dogRepo := repository.NewDogRepository(db)
+
+	bellaID := testutil.SeedTestDog(t, db, `Bella "<3"`, "Labrador", "green")
+	bella, _ := dogRepo.FindByID(bellaID)
+	photo, thumbnail, needs := "dogs/bella_full.jpg", "dogs/bella_thumb.jpg", "Braucht Medikamente"
+	bella.Photo, bella.PhotoThumbnail, bella.SpecialNeeds = &photo, &thumbnail, &needs
+	dogRepo.Update(bella)
+
+	maxID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+	dogRepo.SetStatus(&models.DogStatusChange{DogID: maxID, Status: models.DogStatusAdopted})
+
+	rockyID := testutil.SeedTestDog(t, db, "Rocky", "Dackel", "blue")
+	dogRepo.SetStatus(&models.DogStatusChange{DogID: rockyID, Status: models.DogStatusArchived})
+
+	get := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/hund/"+id, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		rec := httptest.NewRecorder()
+		handler.GetDogPage(rec, req)
+		return rec
+	}
+
+	t.Run("open graph tags", func(t *testing.T) {
+		rec := get(fmt.Sprint(bellaID))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		body := rec.Body.String()
+
+		expected := []string{
+			`<meta property="og:title" content="Bella &#34;&lt;3&#34; – Labrador">`,
+			`<meta property="og:image" content="https://gassi.example.org/uploads/dogs/bella_thumb.jpg">`,
+			fmt.Sprintf(`<meta property="og:url" content="https://gassi.example.org/hund/%d">`, bellaID),
+			`<meta name="twitter:card" content="summary_large_image">`,
+			fmt.Sprintf(`href="/dogs.html?dog=%d"`, bellaID),
+		}
+		for _, want := range expected {
+			if !strings.Contains(body, want) {
+				t.Errorf("Expected page to contain %s", want)
+			}
+		}
+		if strings.Contains(body, `"<3"`) || strings.Contains(body, needs) {
+			t.Error("Page must escape the name and hide internal details")
+		}
+		if rec.Header().Get("ETag") == "" {
+			t.Error("Expected the page to be cacheable")
+		}
+	})
+
+	t.Run("adopted dogs cannot be booked", func(t *testing.T) {
+		rec := get(fmt.Sprint(maxID))
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK || !strings.Contains(body, "hat ein Zuhause gefunden") {
+			t.Errorf("Expected adopted page, got %d", rec.Code)
+		}
+		if strings.Contains(body, "/dogs.html?dog=") || !strings.Contains(body, `content="summary"`) {
+			t.Error("Expected no booking link and a small preview without photo")
+		}
+	})
+
+	t.Run("archived and unknown dogs are not found", func(t *testing.T) {
+		for _, id := range []string{fmt.Sprint(rockyID), "99999"} {
+			rec := get(id)
+			if rec.Code != http.StatusNotFound || strings.Contains(rec.Body.String(), "og:title") {
+				t.Errorf("Expected 404 without preview for dog %s, got %d", id, rec.Code)
+			}
+		}
+	})
+}
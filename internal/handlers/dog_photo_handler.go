@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// maxDogPhotos limits the number of photos in the gallery of a dog
+const maxDogPhotos = 20
+
+// DogPhotoHandler handles dog photo gallery HTTP requests
+type DogPhotoHandler struct {
+	db           *sql.DB
+	cfg          *config.Config
+	dogRepo      *repository.DogRepository
+	photoRepo    *repository.DogPhotoRepository
+	imageService *services.ImageService
+}
+
+// NewDogPhotoHandler creates a new dog photo handler
+func NewDogPhotoHandler(db *sql.DB, cfg *config.Config) *DogPhotoHandler {
+	return &DogPhotoHandler{
+		db:           db,
+		cfg:          cfg,
+		dogRepo:      repository.NewDogRepository(db),
+		photoRepo:    repository.NewDogPhotoRepository(db),
+		imageService: services.NewImageServiceWithStorage(services.NewStorageFromConfig(cfg)),
+	}
+}
+
+// ListPhotos returns the gallery of a dog in display order
+func (h *DogPhotoHandler) ListPhotos(w http.ResponseWriter, r *http.Request) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return
+	}
+
+	photos, err := h.photoRepo.FindByDog(dog.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get photos")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, withVariants(photos...))
+}
+
+// UploadPhotos adds one or more photos to the gallery of a dog (admin only)
+// Files are sent as multipart field "photos", optional captions as "captions" in the same order
+// Files that are already in the gallery are skipped
+func (h *DogPhotoHandler) UploadPhotos(w http.ResponseWriter, r *http.Request) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseMultipartForm(int64(h.cfg.MaxUploadSizeMB) << 20); err != nil {
+		respondError(w, http.StatusBadRequest, "File too large or invalid form")
+		return
+	}
+
+	headers := r.MultipartForm.File["photos"]
+	if len(headers) == 0 {
+		respondError(w, http.StatusBadRequest, "No file uploaded")
+		return
+	}
+
+	for _, header := range headers {
+		ext := strings.ToLower(filepath.Ext(header.Filename))
+		if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+			respondError(w, http.StatusBadRequest, "Only JPEG and PNG files are allowed")
+			return
+		}
+	}
+
+	existing, err := h.photoRepo.FindByDog(dog.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get photos")
+		return
+	}
+	if len(existing)+len(headers) > maxDogPhotos {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("At most %d photos per dog", maxDogPhotos))
+		return
+	}
+	inGallery := make(map[string]bool, len(existing))
+	for _, photo := range existing {
+		inGallery[photo.Photo] = true
+	}
+
+	captions := r.MultipartForm.Value["captions"]
+	created := []*models.DogPhoto{}
+	for i, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Failed to read uploaded file")
+			return
+		}
+
+		fullPath, thumbPath, err := h.imageService.ProcessDogPhoto(file, dog.ID)
+		file.Close()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to process image: %v", err))
+			return
+		}
+
+		// Filenames are content hashed, so the same file maps to the same photo
+		if inGallery[fullPath] {
+			continue
+		}
+		inGallery[fullPath] = true
+
+		photo := &models.DogPhoto{
+			DogID:          dog.ID,
+			Photo:          fullPath,
+			PhotoThumbnail: &thumbPath,
+		}
+		if i < len(captions) && strings.TrimSpace(captions[i]) != "" {
+			caption := strings.TrimSpace(captions[i])
+			photo.Caption = &caption
+		}
+
+		if err := h.photoRepo.Create(photo); err != nil {
+			// If database update fails, clean up the newly created files
+			h.imageService.DeleteDogPhotoVariants(fullPath)
+			respondError(w, http.StatusInternalServerError, "Failed to save photo")
+			return
+		}
+		created = append(created, photo)
+	}
+
+	respondJSON(w, http.StatusCreated, withVariants(created...))
+}
+
+// UpdatePhoto changes the caption of a photo or makes it the cover (admin only)
+func (h *DogPhotoHandler) UpdatePhoto(w http.ResponseWriter, r *http.Request) {
+	dogID, photoID, ok := h.requirePhotoIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateDogPhotoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Caption != nil {
+		caption := strings.TrimSpace(*req.Caption)
+		var captionPtr *string
+		if caption != "" {
+			captionPtr = &caption
+		}
+		if err := h.photoRepo.UpdateCaption(dogID, photoID, captionPtr); err != nil {
+			h.respondPhotoError(w, err, "Failed to update photo")
+			return
+		}
+	}
+
+	if req.IsCover != nil {
+		if err := h.photoRepo.SetCover(dogID, photoID); err != nil {
+			h.respondPhotoError(w, err, "Failed to update photo")
+			return
+		}
+	}
+
+	photo, err := h.photoRepo.FindByID(dogID, photoID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get photo")
+		return
+	}
+	if photo == nil {
+		respondError(w, http.StatusNotFound, "Photo not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, withVariants(photo)[0])
+}
+
+// ReorderPhotos sets the display order of the gallery (admin only)
+func (h *DogPhotoHandler) ReorderPhotos(w http.ResponseWriter, r *http.Request) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return
+	}
+
+	var req models.ReorderDogPhotosRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.photoRepo.Reorder(dog.ID, req.PhotoIDs); err != nil {
+		if err.Error() == "photo IDs do not match gallery" {
+			respondError(w, http.StatusBadRequest, "Photo IDs must contain every photo of the dog exactly once")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to reorder photos")
+		return
+	}
+
+	photos, err := h.photoRepo.FindByDog(dog.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get photos")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, withVariants(photos...))
+}
+
+// DeletePhoto removes a photo from the gallery and deletes its files (admin only)
+// If the cover is deleted, the next photo in order becomes the cover
+func (h *DogPhotoHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	dogID, photoID, ok := h.requirePhotoIDs(w, r)
+	if !ok {
+		return
+	}
+
+	photo, err := h.photoRepo.Delete(dogID, photoID)
+	if err != nil {
+		h.respondPhotoError(w, err, "Failed to delete photo")
+		return
+	}
+
+	// The single photo upload may have stored the same file as another photo of the gallery
+	remaining, err := h.photoRepo.FindByDog(dogID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get photos")
+		return
+	}
+	shared := false
+	for _, other := range remaining {
+		shared = shared || other.Photo == photo.Photo
+	}
+
+	if !shared {
+		h.imageService.DeleteDogPhotoVariants(photo.Photo)
+		if photo.PhotoThumbnail != nil {
+			h.imageService.DeletePhoto(*photo.PhotoThumbnail)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Photo deleted successfully"})
+}
+
+// withVariants fills in the responsive variants of the photos for the response
+func withVariants(photos ...*models.DogPhoto) []*models.DogPhoto {
+	for _, photo := range photos {
+		photo.Variants = services.DogPhotoVariants(photo.Photo)
+	}
+	return photos
+}
+
+// requirePhotoIDs parses the dog ID and the photo ID from the URL
+func (h *DogPhotoHandler) requirePhotoIDs(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return 0, 0, false
+	}
+
+	photoID, err := strconv.Atoi(mux.Vars(r)["photoId"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid photo ID")
+		return 0, 0, false
+	}
+
+	return dog.ID, photoID, true
+}
+
+// respondPhotoError maps a repository error to a response
+func (h *DogPhotoHandler) respondPhotoError(w http.ResponseWriter, err error, message string) {
+	if err.Error() == "dog photo not found" {
+		respondError(w, http.StatusNotFound, "Photo not found")
+		return
+	}
+	respondError(w, http.StatusInternalServerError, message)
+}
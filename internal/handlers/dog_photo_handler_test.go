@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// createGalleryUpload creates a multipart body with several "photos" files and captions
+func createGalleryUpload(t *testing.T, filenames []string, captions []string) (*bytes.Buffer, string) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for i, filename := range filenames {
+		// Different sizes give different files, identical files are skipped by the upload
+		imageData, err := createTestImageBytes(400+10*i, 300, "jpeg")
+		if err != nil {
+			t.Fatalf("Failed to create test image: %v", err)
+		}
+
+		part, err := writer.CreateFormFile("photos", filename)
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write(imageData)
+	}
+	for _, caption := range captions {
+		writer.WriteField("captions", caption)
+	}
+	writer.Close()
+
+	return body, writer.FormDataContentType()
+}
+
+// DONE: TestDogPhotoHandler tests the dog photo gallery
+func TestDogPhotoHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	tempDir := t.TempDir()
+	cfg := &config.Config{JWTSecret: "test-secret", UploadDir: tempDir, MaxUploadSizeMB: 10}
+	handler := NewDogPhotoHandler(db, cfg)
+	dogRepo := repository.NewDogRepository(db)
+
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	otherDogID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+
+	call := func(fn http.HandlerFunc, method string, vars map[string]string, reqBody interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(method, "/api/dogs/"+vars["id"]+"/photos", bytes.NewReader(body))
+		req = mux.SetURLVars(req, vars)
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+
+	upload := func(filenames []string, captions []string) *httptest.ResponseRecorder {
+		body, contentType := createGalleryUpload(t, filenames, captions)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/dogs/%d/photos", dogID), body)
+		req.Header.Set("Content-Type", contentType)
+		req = mux.SetURLVars(req, map[string]string{"id": fmt.Sprintf("%d", dogID)})
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		handler.UploadPhotos(rec, req)
+		return rec
+	}
+
+	dogVars := map[string]string{"id": fmt.Sprintf("%d", dogID)}
+	photoVars := func(photoID int) map[string]string {
+		return map[string]string{"id": fmt.Sprintf("%d", dogID), "photoId": fmt.Sprintf("%d", photoID)}
+	}
+	var photos []*models.DogPhoto
+
+	t.Run("upload several photos", func(t *testing.T) {
+		rec := upload([]string{"a.jpg", "b.png", "c.jpeg"}, []string{"Im Park", "", "Beim Spielen"})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		json.Unmarshal(rec.Body.Bytes(), &photos)
+		if len(photos) != 3 {
+			t.Fatalf("Expected 3 photos, got %d", len(photos))
+		}
+		if !photos[0].IsCover || photos[1].IsCover {
+			t.Error("Expected the first photo to become the cover")
+		}
+		if photos[0].Caption == nil || *photos[0].Caption != "Im Park" || photos[1].Caption != nil {
+			t.Error("Expected captions to be assigned in order")
+		}
+		for _, photo := range photos {
+			if _, err := os.Stat(filepath.Join(tempDir, photo.Photo)); os.IsNotExist(err) {
+				t.Errorf("Photo file not created: %s", photo.Photo)
+			}
+			if len(photo.Variants) == 0 {
+				t.Errorf("Expected responsive variants for %s", photo.Photo)
+			}
+		}
+
+		dog, _ := dogRepo.FindByID(dogID)
+		if dog.Photo == nil || *dog.Photo != photos[0].Photo {
+			t.Error("Expected the cover to be mirrored into the dog")
+		}
+	})
+
+	t.Run("skip photos already in the gallery", func(t *testing.T) {
+		rec := upload([]string{"a.jpg"}, nil)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		var created []*models.DogPhoto
+		json.Unmarshal(rec.Body.Bytes(), &created)
+		if len(created) != 0 {
+			t.Errorf("Expected duplicate to be skipped, got %d new photos", len(created))
+		}
+	})
+
+	t.Run("reject invalid file type", func(t *testing.T) {
+		rec := upload([]string{"a.jpg", "b.gif"}, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("set cover and caption", func(t *testing.T) {
+		rec := call(handler.UpdatePhoto, "PUT", photoVars(photos[2].ID), map[string]interface{}{
+			"caption":  "Neues Titelbild",
+			"is_cover": true,
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		dog, _ := dogRepo.FindByID(dogID)
+		if dog.Photo == nil || *dog.Photo != photos[2].Photo {
+			t.Error("Expected the new cover to be mirrored into the dog")
+		}
+		if dog.PhotoThumbnail == nil || *dog.PhotoThumbnail != *photos[2].PhotoThumbnail {
+			t.Error("Expected the cover thumbnail to be mirrored into the dog")
+		}
+	})
+
+	t.Run("photo of another dog", func(t *testing.T) {
+		vars := map[string]string{"id": fmt.Sprintf("%d", otherDogID), "photoId": fmt.Sprintf("%d", photos[0].ID)}
+		rec := call(handler.UpdatePhoto, "PUT", vars, map[string]interface{}{"is_cover": true})
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("reorder photos", func(t *testing.T) {
+		rec := call(handler.ReorderPhotos, "PUT", dogVars, map[string]interface{}{
+			"photo_ids": []int{photos[2].ID, photos[0].ID},
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for incomplete order, got %d", rec.Code)
+		}
+
+		rec = call(handler.ReorderPhotos, "PUT", dogVars, map[string]interface{}{
+			"photo_ids": []int{photos[2].ID, photos[0].ID, photos[1].ID},
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var ordered []*models.DogPhoto
+		json.Unmarshal(rec.Body.Bytes(), &ordered)
+		if len(ordered) != 3 || ordered[0].ID != photos[2].ID || ordered[2].ID != photos[1].ID {
+			t.Error("Expected photos in the new order")
+		}
+	})
+
+	t.Run("delete cover promotes next photo", func(t *testing.T) {
+		rec := call(handler.DeletePhoto, "DELETE", photoVars(photos[2].ID), nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		if _, err := os.Stat(filepath.Join(tempDir, photos[2].Photo)); !os.IsNotExist(err) {
+			t.Error("Expected photo file to be deleted")
+		}
+
+		dog, _ := dogRepo.FindByID(dogID)
+		if dog.Photo == nil || *dog.Photo != photos[0].Photo {
+			t.Error("Expected the next photo in order to become the cover")
+		}
+	})
+
+	t.Run("delete all photos clears dog photo", func(t *testing.T) {
+		call(handler.DeletePhoto, "DELETE", photoVars(photos[0].ID), nil)
+		call(handler.DeletePhoto, "DELETE", photoVars(photos[1].ID), nil)
+
+		rec := call(handler.ListPhotos, "GET", dogVars, nil)
+		var remaining []*models.DogPhoto
+		json.Unmarshal(rec.Body.Bytes(), &remaining)
+		if len(remaining) != 0 {
+			t.Errorf("Expected empty gallery, got %d photos", len(remaining))
+		}
+
+		dog, _ := dogRepo.FindByID(dogID)
+		if dog.Photo != nil {
+			t.Error("Expected dog photo to be cleared")
+		}
+	})
+
+	t.Run("delete unknown photo", func(t *testing.T) {
+		rec := call(handler.DeletePhoto, "DELETE", photoVars(9999), nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+}
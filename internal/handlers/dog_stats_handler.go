@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+// DogStatsHandler handles walk history and statistics of dogs
+type DogStatsHandler struct {
+	db           *sql.DB
+	cfg          *config.Config
+	dogRepo      *repository.DogRepository
+	statsRepo    *repository.DogStatsRepository
+	settingsRepo *repository.SettingsRepository
+}
+
+// NewDogStatsHandler creates a new dog stats handler
+func NewDogStatsHandler(db *sql.DB, cfg *config.Config) *DogStatsHandler {
+	return &DogStatsHandler{
+		db:           db,
+		cfg:          cfg,
+		dogRepo:      repository.NewDogRepository(db),
+		statsRepo:    repository.NewDogStatsRepository(db),
+		settingsRepo: repository.NewSettingsRepository(db),
+	}
+}
+
+// GetDogHistory handles GET /api/dogs/:id/history - completed walks of a dog (admin only)
+func (h *DogStatsHandler) GetDogHistory(w http.ResponseWriter, r *http.Request) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parseDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	walks, err := h.statsRepo.FindWalks(dog.ID, from, to)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get walk history")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, walks)
+}
+
+// GetDogStats handles GET /api/dogs/:id/stats - walk statistics of a dog (admin only)
+func (h *DogStatsHandler) GetDogStats(w http.ResponseWriter, r *http.Request) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return
+	}
+
+	from, to, ok := parseDateRange(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.statsRepo.GetStats(dog, from, to, time.Now())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get walk statistics")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, stats)
+}
+
+// GetNeglectedDogs handles GET /api/admin/reports/neglected-dogs - dogs without a walk in N days (admin only)
+// N is the days query parameter, or the neglected_dog_days setting
+func (h *DogStatsHandler) GetNeglectedDogs(w http.ResponseWriter, r *http.Request) {
+	days := h.neglectedDogDays()
+	if param := r.URL.Query().Get("days"); param != "" {
+		d, err := strconv.Atoi(param)
+		if err != nil || d <= 0 {
+			respondError(w, http.StatusBadRequest, "days must be a positive integer")
+			return
+		}
+		days = d
+	}
+
+	dogs, err := h.statsRepo.FindNeglected(days, time.Now())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get neglected dogs")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"days": days,
+		"dogs": dogs,
+	})
+}
+
+// neglectedDogDays returns the neglected_dog_days setting
+func (h *DogStatsHandler) neglectedDogDays() int {
+	days := 7 // default
+	if setting, err := h.settingsRepo.Get("neglected_dog_days"); err == nil && setting != nil {
+		if d, err := strconv.Atoi(setting.Value); err == nil && d > 0 {
+			days = d
+		}
+	}
+	return days
+}
+
+// parseDateRange reads the optional from and to query parameters (YYYY-MM-DD)
+func parseDateRange(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			respondError(w, http.StatusBadRequest, "Dates must use the format YYYY-MM-DD")
+			return "", "", false
+		}
+	}
+	return from, to, true
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogStatsHandler tests the walk history, statistics and neglected dogs endpoints
+func TestDogStatsHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewDogStatsHandler(db, cfg)
+
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	userID := testutil.SeedTestUser(t, db, "user@example.com", "User", "green")
+	bellaID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	maxID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+	db.Exec(`UPDATE dogs SET created_at = ?`, time.Now().AddDate(0, 0, -30))
+
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	lastWeek := time.Now().AddDate(0, 0, -10).Format("2006-01-02")
+	testutil.SeedTestBooking(t, db, userID, bellaID, yesterday, "10:00", "completed")
+	testutil.SeedTestBooking(t, db, userID, maxID, lastWeek, "10:00", "completed")
+
+	call := func(fn http.HandlerFunc, target string, vars map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		req = mux.SetURLVars(req, vars)
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+	bellaVars := map[string]string{"id": fmt.Sprintf("%d", bellaID)}
+
+	t.Run("history", func(t *testing.T) {
+		rec := call(handler.GetDogHistory, "/api/dogs/x/history", bellaVars)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var walks []map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &walks)
+		if len(walks) != 1 || walks[0]["date"] != yesterday {
+			t.Errorf("Expected yesterday's walk, got %v", walks)
+		}
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		rec := call(handler.GetDogStats, "/api/dogs/x/stats", bellaVars)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var stats map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &stats)
+		if stats["total_walks"] != float64(1) || stats["days_since_last_walk"] != float64(1) {
+			t.Errorf("Unexpected stats: %v", stats)
+		}
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		if rec := call(handler.GetDogStats, "/api/dogs/x/stats?from=01.06.2025", bellaVars); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for invalid date, got %d", rec.Code)
+		}
+		if rec := call(handler.GetDogHistory, "/api/dogs/x/history", map[string]string{"id": "9999"}); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+		if rec := call(handler.GetNeglectedDogs, "/api/admin/reports/neglected-dogs?days=0", nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for invalid days, got %d", rec.Code)
+		}
+	})
+
+	t.Run("neglected dogs use the setting", func(t *testing.T) {
+		rec := call(handler.GetNeglectedDogs, "/api/admin/reports/neglected-dogs", nil)
+		var report struct {
+			Days int                      `json:"days"`
+			Dogs []map[string]interface{} `json:"dogs"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &report)
+		if report.Days != 7 || len(report.Dogs) != 1 || report.Dogs[0]["name"] != "Max" {
+			t.Errorf("Expected Max with the default of 7 days, got %+v", report)
+		}
+
+		rec = call(handler.GetNeglectedDogs, "/api/admin/reports/neglected-dogs?days=1", nil)
+		json.Unmarshal(rec.Body.Bytes(), &report)
+		if report.Days != 1 || len(report.Dogs) != 2 {
+			t.Errorf("Expected both dogs for 1 day, got %+v", report)
+		}
+	})
+}
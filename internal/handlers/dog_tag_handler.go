@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+// DogTagHandler handles dog tags (traits) and their assignment to dogs
+type DogTagHandler struct {
+	db      *sql.DB
+	cfg     *config.Config
+	dogRepo *repository.DogRepository
+	tagRepo *repository.DogTagRepository
+}
+
+// NewDogTagHandler creates a new dog tag handler
+func NewDogTagHandler(db *sql.DB, cfg *config.Config) *DogTagHandler {
+	return &DogTagHandler{
+		db:      db,
+		cfg:     cfg,
+		dogRepo: repository.NewDogRepository(db),
+		tagRepo: repository.NewDogTagRepository(db),
+	}
+}
+
+// ListTags handles GET /api/dog-tags - list all tags
+func (h *DogTagHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.tagRepo.FindAll()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get tags")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tags)
+}
+
+// CreateTag handles POST /api/dog-tags - create a tag (admin only)
+func (h *DogTagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeTagRequest(w, r, 0)
+	if !ok {
+		return
+	}
+
+	tag := &models.DogTag{Name: req.Name, Description: req.Description}
+	if err := h.tagRepo.Create(tag); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create tag")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, tag)
+}
+
+// UpdateTag handles PUT /api/dog-tags/:tagId - rename a tag or change its description (admin only)
+func (h *DogTagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	tag, ok := h.requireTag(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := h.decodeTagRequest(w, r, tag.ID)
+	if !ok {
+		return
+	}
+
+	tag.Name = req.Name
+	tag.Description = req.Description
+	if err := h.tagRepo.Update(tag); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update tag")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tag)
+}
+
+// DeleteTag handles DELETE /api/dog-tags/:tagId - delete a tag and remove it from all dogs (admin only)
+func (h *DogTagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	tag, ok := h.requireTag(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tagRepo.Delete(tag.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to delete tag")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Tag deleted"})
+}
+
+// SetDogTags handles PUT /api/dogs/:id/tags - replace the tags of a dog (admin only)
+func (h *DogTagHandler) SetDogTags(w http.ResponseWriter, r *http.Request) {
+	dogID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid dog ID")
+		return
+	}
+
+	dog, err := h.dogRepo.FindByID(dogID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get dog")
+		return
+	}
+	if dog == nil {
+		respondError(w, http.StatusNotFound, "Dog not found")
+		return
+	}
+
+	var req models.SetDogTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	tagIDs := []int{}
+	seen := map[int]bool{}
+	for _, tagID := range req.TagIDs {
+		if seen[tagID] {
+			continue
+		}
+		seen[tagID] = true
+
+		tag, err := h.tagRepo.FindByID(tagID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get tag")
+			return
+		}
+		if tag == nil {
+			respondError(w, http.StatusBadRequest, "Unknown tag: "+strconv.Itoa(tagID))
+			return
+		}
+		tagIDs = append(tagIDs, tagID)
+	}
+
+	if err := h.tagRepo.SetDogTags(dogID, tagIDs); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update dog tags")
+		return
+	}
+
+	tags, err := h.tagRepo.FindByDogs([]int{dogID})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get tags")
+		return
+	}
+	result := tags[dogID]
+	if result == nil {
+		result = []*models.DogTag{}
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// decodeTagRequest decodes and validates a tag request; names must be unique
+func (h *DogTagHandler) decodeTagRequest(w http.ResponseWriter, r *http.Request, tagID int) (*models.DogTagRequest, bool) {
+	var req models.DogTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return nil, false
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	exists, err := h.tagRepo.NameExists(req.Name, tagID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to check tag name")
+		return nil, false
+	}
+	if exists {
+		respondError(w, http.StatusConflict, "A tag with this name already exists")
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// requireTag parses the tag ID from the URL and loads the tag
+func (h *DogTagHandler) requireTag(w http.ResponseWriter, r *http.Request) (*models.DogTag, bool) {
+	tagID, err := strconv.Atoi(mux.Vars(r)["tagId"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid tag ID")
+		return nil, false
+	}
+
+	tag, err := h.tagRepo.FindByID(tagID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get tag")
+		return nil, false
+	}
+	if tag == nil {
+		respondError(w, http.StatusNotFound, "Tag not found")
+		return nil, false
+	}
+
+	return tag, true
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogTagHandler tests tag management, dog tags and faceted filtering
+func TestDogTagHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewDogTagHandler(db, cfg)
+	dogHandler := NewDogHandler(db, cfg)
+
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	bellaID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	maxID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+	testutil.SeedTestDog(t, db, "Rocky", "Dackel", "blue")
+
+	call := func(fn http.HandlerFunc, method, target string, vars map[string]string, reqBody interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		req = mux.SetURLVars(req, vars)
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+
+	var calm, muzzle models.DogTag
+	t.Run("create tags", func(t *testing.T) {
+		rec := call(handler.CreateTag, "POST", "/api/dog-tags", nil, models.DogTagRequest{Name: "Ruhig"})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		json.Unmarshal(rec.Body.Bytes(), &calm)
+
+		rec = call(handler.CreateTag, "POST", "/api/dog-tags", nil, models.DogTagRequest{Name: " Braucht Maulkorb "})
+		json.Unmarshal(rec.Body.Bytes(), &muzzle)
+		if muzzle.Name != "Braucht Maulkorb" {
+			t.Errorf("Expected trimmed name, got %q", muzzle.Name)
+		}
+	})
+
+	t.Run("duplicate and empty names", func(t *testing.T) {
+		rec := call(handler.CreateTag, "POST", "/api/dog-tags", nil, models.DogTagRequest{Name: "ruhig"})
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", rec.Code)
+		}
+		rec = call(handler.CreateTag, "POST", "/api/dog-tags", nil, models.DogTagRequest{Name: "  "})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("rename tag", func(t *testing.T) {
+		vars := map[string]string{"tagId": fmt.Sprintf("%d", calm.ID)}
+		rec := call(handler.UpdateTag, "PUT", "/api/dog-tags/x", vars, models.DogTagRequest{Name: "Sehr ruhig"})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("set dog tags", func(t *testing.T) {
+		rec := call(handler.SetDogTags, "PUT", "/api/dogs/x/tags", map[string]string{"id": fmt.Sprintf("%d", bellaID)},
+			models.SetDogTagsRequest{TagIDs: []int{calm.ID, muzzle.ID, calm.ID}})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var tags []*models.DogTag
+		json.Unmarshal(rec.Body.Bytes(), &tags)
+		if len(tags) != 2 {
+			t.Errorf("Expected 2 tags, got %d", len(tags))
+		}
+
+		call(handler.SetDogTags, "PUT", "/api/dogs/x/tags", map[string]string{"id": fmt.Sprintf("%d", maxID)},
+			models.SetDogTagsRequest{TagIDs: []int{calm.ID}})
+
+		rec = call(handler.SetDogTags, "PUT", "/api/dogs/x/tags", map[string]string{"id": fmt.Sprintf("%d", maxID)},
+			models.SetDogTagsRequest{TagIDs: []int{9999}})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for unknown tag, got %d", rec.Code)
+		}
+	})
+
+	t.Run("filter dogs by tags", func(t *testing.T) {
+		rec := call(dogHandler.ListDogs, "GET", fmt.Sprintf("/api/dogs?tags=%d&exclude_tags=%d", calm.ID, muzzle.ID), nil, nil)
+		var dogs []*models.Dog
+		json.Unmarshal(rec.Body.Bytes(), &dogs)
+		if len(dogs) != 1 || dogs[0].ID != maxID {
+			t.Fatalf("Expected only Max, got %d dogs", len(dogs))
+		}
+		if len(dogs[0].Tags) != 1 || dogs[0].Tags[0].Name != "Sehr ruhig" {
+			t.Errorf("Expected the tags to be included, got %v", dogs[0].Tags)
+		}
+	})
+
+	t.Run("facets", func(t *testing.T) {
+		rec := call(dogHandler.ListDogFacets, "GET", "/api/dogs/facets?category=green", nil, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		var facets models.DogFacets
+		json.Unmarshal(rec.Body.Bytes(), &facets)
+		if facets.Total != 2 {
+			t.Errorf("Expected 2 dogs, got %d", facets.Total)
+		}
+
+		counts := map[int]int{}
+		for _, facet := range facets.Tags {
+			counts[facet.ID] = facet.Count
+		}
+		if counts[calm.ID] != 2 || counts[muzzle.ID] != 1 {
+			t.Errorf("Expected counts 2 and 1, got %v", counts)
+		}
+		if len(facets.Tags) != 7 {
+			t.Errorf("Expected all 7 tags to be listed, got %d", len(facets.Tags))
+		}
+	})
+
+	t.Run("delete tag", func(t *testing.T) {
+		rec := call(handler.DeleteTag, "DELETE", "/api/dog-tags/x", map[string]string{"tagId": fmt.Sprintf("%d", muzzle.ID)}, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		rec = call(handler.DeleteTag, "DELETE", "/api/dog-tags/x", map[string]string{"tagId": fmt.Sprintf("%d", muzzle.ID)}, nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+}
@@ -20,7 +20,9 @@ type ExperienceRequestHandler struct {
 	cfg        *config.Config
 	requestRepo *repository.ExperienceRequestRepository
 	userRepo    *repository.UserRepository
+	roleRepo    *repository.RoleRepository
 	emailService *services.EmailService
+	audit        *services.AuditService
 }
 
 // NewExperienceRequestHandler creates a new experience request handler
@@ -36,7 +38,9 @@ func NewExperienceRequestHandler(db *sql.DB, cfg *config.Config) *ExperienceRequ
 		cfg:          cfg,
 		requestRepo:  repository.NewExperienceRequestRepository(db),
 		userRepo:     repository.NewUserRepository(db),
+		roleRepo:     repository.NewRoleRepository(db),
 		emailService: emailService,
+		audit:        newAuditService(db),
 	}
 }
 
@@ -119,9 +123,9 @@ func (h *ExperienceRequestHandler) CreateRequest(w http.ResponseWriter, r *http.
 
 // ListRequests lists experience requests (user sees own, admin sees all pending)
 func (h *ExperienceRequestHandler) ListRequests(w http.ResponseWriter, r *http.Request) {
-	// Get user ID and admin status from context
+	// Get user ID and admin status from context; approvers see the requests like admins
 	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
-	isAdmin, _ := r.Context().Value(middleware.IsAdminKey).(bool)
+	isAdmin := hasPermission(r, h.roleRepo, models.PermissionApproveExperienceRequests)
 
 	var requests []*models.ExperienceRequest
 	var err error
@@ -207,11 +211,15 @@ func (h *ExperienceRequestHandler) ApproveRequest(w http.ResponseWriter, r *http
 	}
 
 	// Update user experience level
+	previousLevel := user.ExperienceLevel
 	user.ExperienceLevel = experienceRequest.RequestedLevel
 	if err := h.userRepo.Update(user); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update user level")
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditExperienceRequestApprove, models.AuditTargetExperienceRequest, &id,
+		map[string]interface{}{"status": experienceRequest.Status, "user_id": user.ID, "experience_level": previousLevel},
+		map[string]interface{}{"status": "approved", "user_id": user.ID, "experience_level": user.ExperienceLevel, "message": req.Message})
 
 	// Send email notification
 	if user.Email != nil && h.emailService != nil {
@@ -274,6 +282,9 @@ func (h *ExperienceRequestHandler) DenyRequest(w http.ResponseWriter, r *http.Re
 		respondError(w, http.StatusInternalServerError, "Failed to deny request")
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditExperienceRequestDeny, models.AuditTargetExperienceRequest, &id,
+		map[string]interface{}{"status": experienceRequest.Status, "user_id": user.ID},
+		map[string]interface{}{"status": "denied", "user_id": user.ID, "message": req.Message})
 
 	// Send email notification
 	if user.Email != nil && h.emailService != nil {
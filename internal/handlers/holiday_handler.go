@@ -54,9 +54,8 @@ func (h *HolidayHandler) GetHolidays(w http.ResponseWriter, r *http.Request) {
 // POST /api/holidays
 func (h *HolidayHandler) CreateHoliday(w http.ResponseWriter, r *http.Request) {
 	// Check admin permission
-	isAdmin, ok := r.Context().Value(middleware.IsAdminKey).(bool)
-	if !ok || !isAdmin {
-		respondError(w, http.StatusForbidden, "Admin access required")
+	if !middleware.HasPermission(r.Context(), models.PermissionManageBlockedDates) {
+		respondError(w, http.StatusForbidden, "Permission required")
 		return
 	}
 
@@ -89,9 +88,8 @@ func (h *HolidayHandler) CreateHoliday(w http.ResponseWriter, r *http.Request) {
 // PUT /api/holidays/:id
 func (h *HolidayHandler) UpdateHoliday(w http.ResponseWriter, r *http.Request) {
 	// Check admin permission
-	isAdmin, ok := r.Context().Value(middleware.IsAdminKey).(bool)
-	if !ok || !isAdmin {
-		respondError(w, http.StatusForbidden, "Admin access required")
+	if !middleware.HasPermission(r.Context(), models.PermissionManageBlockedDates) {
+		respondError(w, http.StatusForbidden, "Permission required")
 		return
 	}
 
@@ -129,9 +127,8 @@ func (h *HolidayHandler) UpdateHoliday(w http.ResponseWriter, r *http.Request) {
 // DELETE /api/holidays/:id
 func (h *HolidayHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
 	// Check admin permission
-	isAdmin, ok := r.Context().Value(middleware.IsAdminKey).(bool)
-	if !ok || !isAdmin {
-		respondError(w, http.StatusForbidden, "Admin access required")
+	if !middleware.HasPermission(r.Context(), models.PermissionManageBlockedDates) {
+		respondError(w, http.StatusForbidden, "Permission required")
 		return
 	}
 
@@ -0,0 +1,497 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/middleware"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// maxIncidentPhotos limits the number of photos per incident
+const maxIncidentPhotos = 5
+
+// IncidentHandler handles incident reporting and review HTTP requests
+type IncidentHandler struct {
+	db           *sql.DB
+	cfg          *config.Config
+	incidentRepo *repository.IncidentRepository
+	dogRepo      *repository.DogRepository
+	bookingRepo  *repository.BookingRepository
+	userRepo     *repository.UserRepository
+	roleRepo     *repository.RoleRepository
+	settingsRepo *repository.SettingsRepository
+	searchRepo   *repository.DogSearchRepository
+	imageService *services.ImageService
+	storage      services.Storage
+	photoSigner  *services.PhotoURLSigner
+	emailService *services.EmailService
+	audit        *services.AuditService
+}
+
+// NewIncidentHandler creates a new incident handler
+func NewIncidentHandler(db *sql.DB, cfg *config.Config) *IncidentHandler {
+	emailService, err := services.NewEmailService(services.ConfigToEmailConfig(cfg))
+	if err != nil {
+		log.Printf("Warning: Failed to initialize email service in IncidentHandler: %v", err)
+	}
+	storage := services.NewStorageFromConfig(cfg)
+
+	return &IncidentHandler{
+		db:           db,
+		cfg:          cfg,
+		incidentRepo: repository.NewIncidentRepository(db),
+		dogRepo:      repository.NewDogRepository(db),
+		bookingRepo:  repository.NewBookingRepository(db),
+		userRepo:     repository.NewUserRepository(db),
+		roleRepo:     repository.NewRoleRepository(db),
+		settingsRepo: repository.NewSettingsRepository(db),
+		searchRepo:   repository.NewDogSearchRepository(db, cfg.DBType),
+		imageService: services.NewImageServiceWithStorage(storage),
+		storage:      storage,
+		photoSigner:  services.NewPhotoURLSigner(cfg.JWTSecret, services.IncidentPhotoURLTTL),
+		emailService: emailService,
+		audit:        newAuditService(db),
+	}
+}
+
+// CreateIncident reports a new incident (walkers for their own walks, admins for any dog)
+func (h *IncidentHandler) CreateIncident(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+	isAdmin := middleware.HasPermission(r.Context(), models.PermissionManageIncidents)
+
+	var req models.CreateIncidentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Only staff may change the dog directly; walkers rely on the automatic rules
+	if !isAdmin && (req.SetDogUnavailable || req.NewDogCategory != nil) {
+		respondError(w, http.StatusForbidden, "Only admins can change the dog when reporting an incident")
+		return
+	}
+
+	dog, err := h.dogRepo.FindByID(req.DogID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get dog")
+		return
+	}
+	if dog == nil {
+		respondError(w, http.StatusNotFound, "Dog not found")
+		return
+	}
+
+	// Walkers report incidents from one of their walks
+	if req.BookingID == nil && !isAdmin {
+		respondError(w, http.StatusBadRequest, "Booking ID is required")
+		return
+	}
+	if req.BookingID != nil {
+		booking, err := h.bookingRepo.FindByID(*req.BookingID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get booking")
+			return
+		}
+		if booking == nil {
+			respondError(w, http.StatusNotFound, "Booking not found")
+			return
+		}
+		if err := h.bookingRepo.LoadGroup(booking); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get booking")
+			return
+		}
+		if !isAdmin && !booking.HasParticipant(userID) {
+			respondError(w, http.StatusForbidden, "Access denied")
+			return
+		}
+		onBooking := false
+		for _, bookingDogID := range booking.AllDogIDs() {
+			if bookingDogID == dog.ID {
+				onBooking = true
+				break
+			}
+		}
+		if !onBooking {
+			respondError(w, http.StatusBadRequest, "Dog was not part of this walk")
+			return
+		}
+	}
+
+	occurredAt := time.Now()
+	if req.OccurredAt != nil {
+		occurredAt, _ = time.Parse(time.RFC3339, *req.OccurredAt)
+	}
+
+	incident := &models.Incident{
+		DogID:        dog.ID,
+		BookingID:    req.BookingID,
+		ReportedBy:   userID,
+		IncidentType: req.IncidentType,
+		Severity:     req.Severity,
+		Description:  strings.TrimSpace(req.Description),
+		OccurredAt:   occurredAt,
+		Status:       models.IncidentStatusOpen,
+	}
+
+	if err := h.incidentRepo.Create(incident); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create incident")
+		return
+	}
+	indexDogForSearch(h.searchRepo, incident.DogID)
+
+	// Severe incidents take the dog out of the booking pool automatically
+	action := req.IncidentDogAction
+	threshold := "critical" // default
+	if setting, err := h.settingsRepo.Get("incident_auto_unavailable_severity"); err == nil && setting != nil {
+		threshold = setting.Value
+	}
+	if models.SeverityAtLeast(incident.Severity, threshold) {
+		action.SetDogUnavailable = true
+	}
+
+	if err := h.applyDogAction(incident.ID, dog, action); err != nil {
+		log.Printf("Failed to apply dog action for incident %d: %v", incident.ID, err)
+	}
+
+	created, err := h.incidentRepo.FindByID(incident.ID)
+	if err != nil || created == nil {
+		respondError(w, http.StatusInternalServerError, "Failed to load incident")
+		return
+	}
+
+	if h.emailService != nil {
+		go h.notifyAdmins(created)
+	}
+
+	respondJSON(w, http.StatusCreated, created)
+}
+
+// ListIncidents lists incidents (admins and incident reviewers see all, users see the incidents they reported)
+func (h *IncidentHandler) ListIncidents(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+	isAdmin := hasPermission(r, h.roleRepo, models.PermissionManageIncidents)
+
+	filter := &models.IncidentFilterRequest{}
+	if !isAdmin {
+		filter.ReportedBy = &userID
+	}
+
+	if status := r.URL.Query().Get("status"); status != "" {
+		filter.Status = &status
+	}
+	if severity := r.URL.Query().Get("severity"); severity != "" {
+		filter.Severity = &severity
+	}
+	if dogIDStr := r.URL.Query().Get("dog_id"); dogIDStr != "" {
+		dogID, err := strconv.Atoi(dogIDStr)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid dog ID")
+			return
+		}
+		filter.DogID = &dogID
+	}
+
+	incidents, err := h.incidentRepo.FindAll(filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get incidents")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, incidents)
+}
+
+// GetIncident returns an incident with its photos and comments
+func (h *IncidentHandler) GetIncident(w http.ResponseWriter, r *http.Request) {
+	incident, ok := h.requireIncident(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.incidentRepo.LoadDetails(incident); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get incident")
+		return
+	}
+	h.setPhotoURLs(incident.Photos...)
+
+	respondJSON(w, http.StatusOK, incident)
+}
+
+// UploadIncidentPhoto attaches a photo to an open incident
+func (h *IncidentHandler) UploadIncidentPhoto(w http.ResponseWriter, r *http.Request) {
+	incident, ok := h.requireIncident(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	if incident.IsClosed() {
+		respondError(w, http.StatusBadRequest, "Incident is already closed")
+		return
+	}
+
+	photos, err := h.incidentRepo.GetPhotos(incident.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get incident photos")
+		return
+	}
+	if len(photos) >= maxIncidentPhotos {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("At most %d photos per incident", maxIncidentPhotos))
+		return
+	}
+
+	if err := r.ParseMultipartForm(int64(h.cfg.MaxUploadSizeMB) << 20); err != nil {
+		respondError(w, http.StatusBadRequest, "File too large or invalid form")
+		return
+	}
+
+	file, header, err := r.FormFile("photo")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "No file uploaded")
+		return
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if ext != ".jpg" && ext != ".jpeg" && ext != ".png" {
+		respondError(w, http.StatusBadRequest, "Only JPEG and PNG files are allowed")
+		return
+	}
+
+	photoPath, err := h.imageService.ProcessIncidentPhoto(file, incident.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to process image: %v", err))
+		return
+	}
+
+	photo := &models.IncidentPhoto{
+		IncidentID: incident.ID,
+		Photo:      photoPath,
+		UploadedBy: userID,
+	}
+	if err := h.incidentRepo.AddPhoto(photo); err != nil {
+		// If database update fails, clean up the newly created file
+		h.imageService.DeletePhoto(photoPath)
+		respondError(w, http.StatusInternalServerError, "Failed to save incident photo")
+		return
+	}
+
+	h.setPhotoURLs(photo)
+	respondJSON(w, http.StatusCreated, photo)
+}
+
+// ServeIncidentPhoto serves an incident photo through a signed, expiring URL
+// The URL is only handed out with incidents the user may access
+func (h *IncidentHandler) ServeIncidentPhoto(w http.ResponseWriter, r *http.Request) {
+	filename := mux.Vars(r)["file"]
+	if filename == "" || filename != filepath.Base(filename) || strings.HasPrefix(filename, ".") {
+		respondError(w, http.StatusNotFound, "Photo not found")
+		return
+	}
+
+	relPath := "incidents/" + filename
+	query := r.URL.Query()
+	if !h.photoSigner.Verify(relPath, query.Get("expires"), query.Get("sig")) {
+		respondError(w, http.StatusForbidden, "Invalid or expired photo link")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	serveStoredFile(w, r, h.storage, relPath)
+}
+
+// setPhotoURLs sets the signed URLs of incident photos
+func (h *IncidentHandler) setPhotoURLs(photos ...*models.IncidentPhoto) {
+	for _, photo := range photos {
+		photo.PhotoURL = h.photoSigner.SignedURL(photo.Photo)
+	}
+}
+
+// AddComment adds a comment to an incident (reporter or admin)
+func (h *IncidentHandler) AddComment(w http.ResponseWriter, r *http.Request) {
+	incident, ok := h.requireIncident(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	var req models.AddIncidentCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment := &models.IncidentComment{
+		IncidentID: incident.ID,
+		UserID:     userID,
+		Comment:    strings.TrimSpace(req.Comment),
+	}
+	if err := h.incidentRepo.AddComment(comment); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to add comment")
+		return
+	}
+	indexDogForSearch(h.searchRepo, incident.DogID)
+
+	respondJSON(w, http.StatusCreated, comment)
+}
+
+// UpdateIncidentStatus reviews or resolves an incident, optionally changing the dog (admin only)
+func (h *IncidentHandler) UpdateIncidentStatus(w http.ResponseWriter, r *http.Request) {
+	incident, ok := h.requireIncident(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	var req models.UpdateIncidentStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.incidentRepo.UpdateStatus(incident.ID, req.Status, req.Resolution, userID); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update incident")
+		return
+	}
+	indexDogForSearch(h.searchRepo, incident.DogID)
+
+	dog, err := h.dogRepo.FindByID(incident.DogID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get dog")
+		return
+	}
+	if dog != nil {
+		if err := h.applyDogAction(incident.ID, dog, req.IncidentDogAction); err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to update dog")
+			return
+		}
+	}
+	h.audit.Record(auditActor(r), models.AuditIncidentStatus, models.AuditTargetIncident, &incident.ID,
+		map[string]interface{}{"status": incident.Status, "resolution": incident.Resolution}, req)
+
+	updated, err := h.incidentRepo.FindByID(incident.ID)
+	if err != nil || updated == nil {
+		respondError(w, http.StatusInternalServerError, "Failed to load incident")
+		return
+	}
+	if err := h.incidentRepo.LoadDetails(updated); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to load incident")
+		return
+	}
+	h.setPhotoURLs(updated.Photos...)
+
+	respondJSON(w, http.StatusOK, updated)
+}
+
+// requireIncident loads the incident from the URL and checks that the user may access it
+func (h *IncidentHandler) requireIncident(w http.ResponseWriter, r *http.Request) (*models.Incident, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid incident ID")
+		return nil, false
+	}
+
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+	isAdmin := hasPermission(r, h.roleRepo, models.PermissionManageIncidents)
+
+	incident, err := h.incidentRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get incident")
+		return nil, false
+	}
+	if incident == nil {
+		respondError(w, http.StatusNotFound, "Incident not found")
+		return nil, false
+	}
+
+	if !isAdmin && incident.ReportedBy != userID {
+		respondError(w, http.StatusForbidden, "Access denied")
+		return nil, false
+	}
+
+	return incident, true
+}
+
+// applyDogAction applies the requested dog changes and records them on the incident
+func (h *IncidentHandler) applyDogAction(incidentID int, dog *models.Dog, action models.IncidentDogAction) error {
+	if action.SetDogUnavailable && dog.IsAvailable {
+		reason := fmt.Sprintf("Vorfall #%d", incidentID)
+		if err := h.dogRepo.ToggleAvailability(dog.ID, false, &reason); err != nil {
+			return err
+		}
+		dog.IsAvailable = false
+		if err := h.incidentRepo.AppendDogAction(incidentID, "Hund als nicht verfügbar markiert"); err != nil {
+			return err
+		}
+	}
+
+	if action.NewDogCategory != nil && *action.NewDogCategory != dog.Category {
+		oldCategory := dog.Category
+		// Reload so the availability change above is not overwritten
+		current, err := h.dogRepo.FindByID(dog.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return fmt.Errorf("dog not found")
+		}
+		current.Category = *action.NewDogCategory
+		if err := h.dogRepo.Update(current); err != nil {
+			return err
+		}
+		dog.Category = current.Category
+		if err := h.incidentRepo.AppendDogAction(incidentID, fmt.Sprintf("Kategorie von %s auf %s geändert", oldCategory, current.Category)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notifyAdmins emails every active admin about a new incident
+func (h *IncidentHandler) notifyAdmins(incident *models.Incident) {
+	admins, err := h.userRepo.FindAdmins()
+	if err != nil {
+		log.Printf("Failed to load admins for incident %d: %v", incident.ID, err)
+		return
+	}
+
+	for _, admin := range admins {
+		if admin.Email == nil || *admin.Email == "" {
+			continue
+		}
+		if err := h.emailService.SendIncidentAlert(*admin.Email, admin.Name, incident.DogName, incident.IncidentType, incident.Severity, incident.ReporterName, incident.Description); err != nil {
+			log.Printf("Failed to send incident alert for incident %d to admin %d: %v", incident.ID, admin.ID, err)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestIncidentHandler tests reporting, reviewing and resolving incidents
+func TestIncidentHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret", UploadDir: t.TempDir(), MaxUploadSizeMB: 5}
+	handler := NewIncidentHandler(db, cfg)
+
+	walkerID := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+	otherID := testutil.SeedTestUser(t, db, "other@example.com", "Other", "green")
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	otherDogID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+	bookingID := testutil.SeedTestBooking(t, db, walkerID, dogID, "2025-11-03", "09:00", "completed")
+
+	createIncident := func(userID int, isAdmin bool, reqBody map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest("POST", "/api/incidents", bytes.NewReader(body))
+		req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", isAdmin))
+		rec := httptest.NewRecorder()
+		handler.CreateIncident(rec, req)
+		return rec
+	}
+
+	dogAvailable := func(id int) bool {
+		var available bool
+		db.QueryRow("SELECT is_available FROM dogs WHERE id = ?", id).Scan(&available)
+		return available
+	}
+
+	var incidentID int
+
+	t.Run("walker reports incident from own walk", func(t *testing.T) {
+		rec := createIncident(walkerID, false, map[string]interface{}{
+			"dog_id":        dogID,
+			"booking_id":    bookingID,
+			"incident_type": "escape",
+			"severity":      "medium",
+			"description":   "Hat sich aus dem Geschirr gewunden",
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var incident models.Incident
+		json.Unmarshal(rec.Body.Bytes(), &incident)
+		incidentID = incident.ID
+		if incident.Status != models.IncidentStatusOpen {
+			t.Errorf("Expected status open, got %s", incident.Status)
+		}
+		if !dogAvailable(dogID) {
+			t.Error("Medium incident should not make the dog unavailable")
+		}
+	})
+
+	t.Run("walker needs a booking", func(t *testing.T) {
+		rec := createIncident(walkerID, false, map[string]interface{}{
+			"dog_id":        dogID,
+			"incident_type": "bite",
+			"severity":      "low",
+			"description":   "Knabbern",
+		})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("other user cannot report on foreign walk", func(t *testing.T) {
+		rec := createIncident(otherID, false, map[string]interface{}{
+			"dog_id":        dogID,
+			"booking_id":    bookingID,
+			"incident_type": "bite",
+			"severity":      "low",
+			"description":   "Knabbern",
+		})
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("walker cannot change the dog", func(t *testing.T) {
+		rec := createIncident(walkerID, false, map[string]interface{}{
+			"dog_id":              dogID,
+			"booking_id":          bookingID,
+			"incident_type":       "bite",
+			"severity":            "low",
+			"description":         "Knabbern",
+			"set_dog_unavailable": true,
+		})
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("critical incident marks dog unavailable automatically", func(t *testing.T) {
+		rec := createIncident(adminID, true, map[string]interface{}{
+			"dog_id":        otherDogID,
+			"incident_type": "bite",
+			"severity":      "critical",
+			"description":   "Biss in die Hand",
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		if dogAvailable(otherDogID) {
+			t.Error("Critical incident should make the dog unavailable")
+		}
+
+		var incident models.Incident
+		json.Unmarshal(rec.Body.Bytes(), &incident)
+		if incident.DogAction == nil {
+			t.Error("Expected dog action to be recorded")
+		}
+	})
+
+	t.Run("list incidents", func(t *testing.T) {
+		list := func(userID int, isAdmin bool) []models.Incident {
+			req := httptest.NewRequest("GET", "/api/incidents", nil)
+			req = req.WithContext(contextWithUser(req.Context(), userID, "user@example.com", isAdmin))
+			rec := httptest.NewRecorder()
+			handler.ListIncidents(rec, req)
+			var incidents []models.Incident
+			json.Unmarshal(rec.Body.Bytes(), &incidents)
+			return incidents
+		}
+
+		if got := len(list(walkerID, false)); got != 1 {
+			t.Errorf("Walker should see 1 incident, got %d", got)
+		}
+		if got := len(list(otherID, false)); got != 0 {
+			t.Errorf("Other user should see no incidents, got %d", got)
+		}
+		if got := len(list(adminID, true)); got != 2 {
+			t.Errorf("Admin should see 2 incidents, got %d", got)
+		}
+	})
+
+	t.Run("other user cannot view incident", func(t *testing.T) {
+		id := fmt.Sprintf("%d", incidentID)
+		req := httptest.NewRequest("GET", "/api/incidents/"+id, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUser(req.Context(), otherID, "other@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.GetIncident(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("reporter uploads photo", func(t *testing.T) {
+		imageData, err := createTestImageBytes(1200, 900, "png")
+		if err != nil {
+			t.Fatalf("Failed to create test image: %v", err)
+		}
+		body, contentType, err := createMultipartUpload("photo", "wound.png", imageData)
+		if err != nil {
+			t.Fatalf("Failed to create upload: %v", err)
+		}
+
+		id := fmt.Sprintf("%d", incidentID)
+		req := httptest.NewRequest("POST", "/api/incidents/"+id+"/photos", body)
+		req.Header.Set("Content-Type", contentType)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUser(req.Context(), walkerID, "walker@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.UploadIncidentPhoto(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var photo models.IncidentPhoto
+		json.Unmarshal(rec.Body.Bytes(), &photo)
+		if photo.Photo == "" {
+			t.Error("Expected photo path to be returned")
+		}
+		if !strings.HasPrefix(photo.PhotoURL, "/api/photos/incidents/") || !strings.Contains(photo.PhotoURL, "sig=") {
+			t.Fatalf("Expected signed photo URL, got %q", photo.PhotoURL)
+		}
+
+		serve := func(signedURL string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", signedURL, nil)
+			req = mux.SetURLVars(req, map[string]string{"file": path.Base(req.URL.Path)})
+			rec := httptest.NewRecorder()
+			handler.ServeIncidentPhoto(rec, req)
+			return rec
+		}
+		if rec := serve(photo.PhotoURL); rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/jpeg" {
+			t.Errorf("Expected signed URL to serve the photo, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if rec := serve(strings.Replace(photo.PhotoURL, "sig=", "sig=0", 1)); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for an invalid signature, got %d", rec.Code)
+		}
+		if rec := serve("/api/photos/incidents/" + path.Base(photo.Photo)); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 without signature, got %d", rec.Code)
+		}
+	})
+
+	t.Run("admin resolves incident and changes category", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]interface{}{
+			"status":           "resolved",
+			"resolution":       "Neues Sicherheitsgeschirr",
+			"new_dog_category": "blue",
+		})
+		id := fmt.Sprintf("%d", incidentID)
+		req := httptest.NewRequest("PUT", "/api/incidents/"+id+"/status", bytes.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		handler.UpdateIncidentStatus(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var incident models.Incident
+		json.Unmarshal(rec.Body.Bytes(), &incident)
+		if incident.Status != models.IncidentStatusResolved || incident.ResolvedBy == nil || *incident.ResolvedBy != adminID {
+			t.Errorf("Expected incident resolved by admin, got status %s", incident.Status)
+		}
+		if len(incident.Photos) != 1 {
+			t.Errorf("Expected 1 photo on incident, got %d", len(incident.Photos))
+		}
+
+		var category string
+		db.QueryRow("SELECT category FROM dogs WHERE id = ?", dogID).Scan(&category)
+		if category != "blue" {
+			t.Errorf("Expected dog category blue, got %s", category)
+		}
+	})
+
+	t.Run("reporter comments on incident", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{"comment": "Danke für die schnelle Reaktion"})
+		id := fmt.Sprintf("%d", incidentID)
+		req := httptest.NewRequest("POST", "/api/incidents/"+id+"/comments", bytes.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUser(req.Context(), walkerID, "walker@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.AddComment(rec, req)
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// JWTKeyHandler handles the JWT signing keys: listing and rotation for the super admin, and the public JWKS
+type JWTKeyHandler struct {
+	db    *sql.DB
+	cfg   *config.Config
+	keys  *services.JWTKeyRing
+	audit *services.AuditService
+}
+
+// NewJWTKeyHandler creates a new JWT key handler
+func NewJWTKeyHandler(db *sql.DB, cfg *config.Config) *JWTKeyHandler {
+	return &JWTKeyHandler{
+		db:    db,
+		cfg:   cfg,
+		keys:  newJWTKeyRing(db, cfg),
+		audit: newAuditService(db),
+	}
+}
+
+// ListKeys handles GET /api/admin/jwt-keys - keys that verify tokens, without secrets
+func (h *JWTKeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.keys.Keys()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get JWT keys")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, keys)
+}
+
+// RotateKey handles POST /api/admin/jwt-keys/rotate - new tokens are signed with a new key,
+// tokens of the old key stay valid during the grace period
+func (h *JWTKeyHandler) RotateKey(w http.ResponseWriter, r *http.Request) {
+	key, err := h.keys.Rotate()
+	if errors.Is(err, services.ErrJWTKeysFromFile) {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Error rotating JWT key: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to rotate JWT key")
+		return
+	}
+
+	h.audit.Record(auditActor(r), models.AuditJWTKeyRotate, models.AuditTargetJWTKey, nil,
+		nil, map[string]interface{}{"kid": key.Kid, "algorithm": key.Algorithm})
+
+	respondJSON(w, http.StatusOK, key)
+}
+
+// JWKS handles GET /.well-known/jwks.json - public keys of asymmetric signing keys (empty for HS256)
+func (h *JWTKeyHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	set, err := h.keys.JWKS()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get JWT keys")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	respondJSON(w, http.StatusOK, set)
+}
+
+// newJWTKeyRing creates the key ring of the configuration; an invalid key file is only logged
+// here, as the server already refuses to start with it
+func newJWTKeyRing(db *sql.DB, cfg *config.Config) *services.JWTKeyRing {
+	keys, err := services.NewJWTKeyRingFromConfig(repository.NewJWTKeyRepository(db, cfg.JWTKeyEncryptionKeys), cfg)
+	if err != nil {
+		log.Printf("Error loading JWT key file: %v", err)
+	}
+	return keys
+}
+
+// newAuthService creates an auth service that signs tokens with the rotating JWT keys
+func newAuthService(db *sql.DB, cfg *config.Config) *services.AuthService {
+	return services.NewAuthServiceWithKeys(newJWTKeyRing(db, cfg), cfg.JWTExpirationHours)
+}
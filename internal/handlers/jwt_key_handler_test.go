@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestJWTKeyHandler tests key rotation by the super admin, the key listing and the JWKS
+func TestJWTKeyHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret", JWTExpirationHours: 24, JWTAlgorithm: models.JWTAlgorithmEdDSA}
+	handler := NewJWTKeyHandler(db, cfg)
+	authService := newAuthService(db, cfg)
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+
+	call := func(fn http.HandlerFunc, method, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+
+	oldToken, err := authService.GenerateJWT(adminID, "admin@example.com", true, true)
+	if err != nil {
+		t.Fatalf("GenerateJWT() failed: %v", err)
+	}
+
+	rec := call(handler.RotateKey, "POST", "/api/admin/jwt-keys/rotate")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected rotation to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var rotated models.JWTSigningKey
+	json.Unmarshal(rec.Body.Bytes(), &rotated)
+	if !rotated.Active || rotated.Algorithm != models.JWTAlgorithmEdDSA {
+		t.Errorf("Expected a new active EdDSA key, got %+v", rotated)
+	}
+	if containsKey(rec.Body.Bytes(), "private_key") {
+		t.Error("Expected no private key in the response")
+	}
+
+	if _, err := authService.ValidateJWT(oldToken); err != nil {
+		t.Errorf("Expected tokens of the retired key to stay valid: %v", err)
+	}
+
+	rec = call(handler.ListKeys, "GET", "/api/admin/jwt-keys")
+	var keys []models.JWTSigningKey
+	json.Unmarshal(rec.Body.Bytes(), &keys)
+	if len(keys) != 2 || keys[0].Kid != rotated.Kid || keys[1].VerifiesUntil == nil {
+		t.Errorf("Expected the new key and the retired key, got %+v", keys)
+	}
+
+	rec = call(handler.JWKS, "GET", "/.well-known/jwks.json")
+	var set models.JWKS
+	json.Unmarshal(rec.Body.Bytes(), &set)
+	if len(set.Keys) != 2 || set.Keys[0].Kty != "OKP" || set.Keys[0].Kid != rotated.Kid {
+		t.Errorf("Expected both public keys in the JWKS, got %+v", set)
+	}
+
+	page, _ := repository.NewAuditLogRepository(db).Find(&models.AuditLogFilter{Action: models.AuditJWTKeyRotate})
+	if page.Total != 1 {
+		t.Errorf("Expected the rotation to be audited, got %d entries", page.Total)
+	}
+}
+
+// containsKey reports whether a JSON object has a top-level key
+func containsKey(data []byte, key string) bool {
+	var object map[string]interface{}
+	json.Unmarshal(data, &object)
+	_, ok := object[key]
+	return ok
+}
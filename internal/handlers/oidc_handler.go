@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/logging"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// ssoStateCookie binds a single sign-on login to the browser that started it
+const ssoStateCookie = "gassigeher_sso_state"
+
+// OIDCHandler handles the browser redirects of single sign-on with OpenID Connect providers
+type OIDCHandler struct {
+	oidc   *services.OIDCService
+	config *config.Config
+}
+
+// NewOIDCHandler creates a new OIDC handler
+func NewOIDCHandler(db *sql.DB, cfg *config.Config) *OIDCHandler {
+	return &OIDCHandler{
+		oidc:   newOIDCService(db, cfg),
+		config: cfg,
+	}
+}
+
+// Start handles GET /api/auth/oidc/{provider}/login - redirects to the provider's login
+// The optional redirect parameter is the page to open after the login
+func (h *OIDCHandler) Start(w http.ResponseWriter, r *http.Request) {
+	start, err := h.oidc.Start(r.Context(), mux.Vars(r)["provider"], localRedirect(r.URL.Query().Get("redirect")))
+	if errors.Is(err, services.ErrOIDCUnknownProvider) {
+		respondError(w, http.StatusNotFound, "Unknown login provider")
+		return
+	}
+	if err != nil {
+		log.Printf("Error starting single sign-on: %v", err)
+		h.redirectToLogin(w, r, "failed")
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookie,
+		Value:    start.State,
+		Path:     "/api/auth",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.config.BaseURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, start.AuthURL, http.StatusFound)
+}
+
+// Callback handles GET /api/auth/oidc/{provider}/callback - the provider's redirect after the login
+// On success the browser continues on the login page with a single-use code in the URL fragment,
+// which it exchanges at POST /api/auth/login/sso; errors are shown there via sso_error
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		h.redirectToLogin(w, r, "denied")
+		return
+	}
+
+	cookie, err := r.Cookie(ssoStateCookie)
+	if err != nil || query.Get("state") == "" || cookie.Value != query.Get("state") || query.Get("code") == "" {
+		h.redirectToLogin(w, r, "expired")
+		return
+	}
+
+	loginCode, redirectPath, err := h.oidc.Callback(r.Context(), mux.Vars(r)["provider"], cookie.Value, query.Get("code"), logging.GetClientIP(r))
+	if err != nil {
+		log.Printf("Single sign-on failed: %v", err)
+		h.redirectToLogin(w, r, ssoErrorCode(err))
+		return
+	}
+
+	target := "/login.html"
+	if redirectPath != "" {
+		target += "?redirect=" + url.QueryEscape(redirectPath)
+	}
+	http.Redirect(w, r, target+"#sso_code="+loginCode, http.StatusFound)
+}
+
+// redirectToLogin sends the browser back to the login page with an error code
+func (h *OIDCHandler) redirectToLogin(w http.ResponseWriter, r *http.Request, code string) {
+	http.Redirect(w, r, "/login.html?sso_error="+code, http.StatusFound)
+}
+
+// ssoErrorCode maps errors to the codes the login page shows messages for
+func ssoErrorCode(err error) string {
+	switch {
+	case errors.Is(err, services.ErrOIDCInvalidState):
+		return "expired"
+	case errors.Is(err, services.ErrOIDCNoAccount):
+		return "no_account"
+	case errors.Is(err, services.ErrOIDCEmailNotVerified):
+		return "email_not_verified"
+	case errors.Is(err, services.ErrOIDCAccountNotVerified):
+		return "account_not_verified"
+	case errors.Is(err, services.ErrOIDCDomainNotAllowed):
+		return "domain_not_allowed"
+	case errors.Is(err, services.ErrOIDCAccountInactive):
+		return "inactive"
+	}
+	return "failed"
+}
+
+// localRedirect returns a redirect path if it stays on this site, otherwise ""
+func localRedirect(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.HasPrefix(path, "/\\") {
+		return ""
+	}
+	return path
+}
+
+// newOIDCService creates the single sign-on service of the configured providers; an invalid
+// configuration is only logged here, as the server already refuses to start with it
+func newOIDCService(db *sql.DB, cfg *config.Config) *services.OIDCService {
+	oidc, err := services.NewOIDCService(cfg.OIDCProviders, cfg.BaseURL,
+		repository.NewOIDCLoginRepository(db),
+		repository.NewUserIdentityRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewRoleRepository(db),
+		newAuthService(db, cfg),
+		newAuditService(db),
+		newSessionService(db, cfg),
+	)
+	if err != nil {
+		log.Printf("Error configuring single sign-on: %v", err)
+		oidc, _ = services.NewOIDCService(nil, cfg.BaseURL, nil, nil, nil, nil, nil, nil, nil)
+	}
+	return oidc
+}
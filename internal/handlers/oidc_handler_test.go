@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/oidcmock"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestOIDCHandler_Login tests the single sign-on redirects and the login with the code
+// against a mock provider
+func TestOIDCHandler_Login(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	provider, server, err := oidcmock.NewServer("gassigeher", "secret")
+	if err != nil {
+		t.Fatalf("Failed to start mock provider: %v", err)
+	}
+	defer server.Close()
+
+	cfg := &config.Config{
+		JWTSecret:          "test-secret",
+		JWTExpirationHours: 24,
+		BaseURL:            "http://localhost:8080",
+		OIDCProviders: []config.OIDCProviderConfig{{
+			ID: "mock", Name: "Mock", IssuerURL: provider.Issuer(), ClientID: "gassigeher", ClientSecret: "secret",
+			Scopes: []string{"openid", "email"}, DefaultLevel: "green", GroupsClaim: "groups",
+		}},
+	}
+	oidcHandler := NewOIDCHandler(db, cfg)
+	authHandler := NewAuthHandler(db, cfg)
+	userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+	provider.SetUser(map[string]interface{}{"sub": "idp-anna", "email": "anna@example.com", "email_verified": true})
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/auth/oidc/{provider}/login", oidcHandler.Start)
+	router.HandleFunc("/api/auth/oidc/{provider}/callback", oidcHandler.Callback)
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+
+	t.Run("methods list the provider", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		authHandler.GetAuthMethods(rec, httptest.NewRequest("GET", "/api/auth/methods", nil))
+		var methods models.AuthMethods
+		json.Unmarshal(rec.Body.Bytes(), &methods)
+		if len(methods.SSOProviders) != 1 || methods.SSOProviders[0].LoginURL != "/api/auth/oidc/mock/login" {
+			t.Errorf("Expected the mock provider, got %+v", methods.SSOProviders)
+		}
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/auth/oidc/other/login", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("callback without state cookie", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/auth/oidc/mock/callback?state=x&code=y", nil))
+		if rec.Header().Get("Location") != "/login.html?sso_error=expired" {
+			t.Errorf("Expected a redirect to the login page, got %q", rec.Header().Get("Location"))
+		}
+	})
+
+	t.Run("login", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/auth/oidc/mock/login?redirect=//evil.example", nil))
+		if rec.Code != http.StatusFound || len(rec.Result().Cookies()) != 1 {
+			t.Fatalf("Expected a redirect with the state cookie, got %d", rec.Code)
+		}
+		stateCookie := rec.Result().Cookies()[0]
+
+		resp, err := noRedirect.Get(rec.Header().Get("Location"))
+		if err != nil {
+			t.Fatalf("Authorization request failed: %v", err)
+		}
+		resp.Body.Close()
+		callback, _ := url.Parse(resp.Header.Get("Location"))
+
+		req := httptest.NewRequest("GET", callback.RequestURI(), nil)
+		req.AddCookie(stateCookie)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		location := rec.Header().Get("Location")
+		if !strings.HasPrefix(location, "/login.html#sso_code=") {
+			t.Fatalf("Expected the login page with a code and without the foreign redirect, got %q", location)
+		}
+
+		login := func(cookie *http.Cookie) *httptest.ResponseRecorder {
+			body, _ := json.Marshal(models.SSOLoginRequest{Code: strings.TrimPrefix(location, "/login.html#sso_code=")})
+			req := httptest.NewRequest("POST", "/api/auth/login/sso", bytes.NewReader(body))
+			if cookie != nil {
+				req.AddCookie(cookie)
+			}
+			rec := httptest.NewRecorder()
+			authHandler.LoginSSO(rec, req)
+			return rec
+		}
+
+		if rec := login(nil); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected a login without the state cookie to fail, got %d", rec.Code)
+		}
+		rec = login(stateCookie)
+		var loginResp models.LoginResponse
+		json.Unmarshal(rec.Body.Bytes(), &loginResp)
+		if rec.Code != http.StatusOK || loginResp.User == nil || loginResp.User.ID != userID {
+			t.Fatalf("Expected the login to succeed, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if rec := login(stateCookie); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected the code to work only once, got %d", rec.Code)
+		}
+	})
+}
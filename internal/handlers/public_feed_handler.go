@@ -0,0 +1,464 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// publicFeedMaxAge is how long browsers and proxies may cache the public feed (seconds)
+const publicFeedMaxAge = 300
+
+// publicFeedTitle is the title of the feed and the default title of the widget
+const publicFeedTitle = "Gassigeher - Unsere Hunde"
+
+// PublicFeedHandler serves the public feed of adoptable dogs for the shelter website
+type PublicFeedHandler struct {
+	db      *sql.DB
+	cfg     *config.Config
+	dogRepo *repository.DogRepository
+	storage services.Storage
+}
+
+// NewPublicFeedHandler creates a new public feed handler
+func NewPublicFeedHandler(db *sql.DB, cfg *config.Config) *PublicFeedHandler {
+	return &PublicFeedHandler{
+		db:      db,
+		cfg:     cfg,
+		dogRepo: repository.NewDogRepository(db),
+		storage: services.NewStorageFromConfig(cfg),
+	}
+}
+
+// GetDogsJSON handles GET /api/public/dogs - adoptable dogs as JSON (public)
+func (h *PublicFeedHandler) GetDogsJSON(w http.ResponseWriter, r *http.Request) {
+	feed, ok := h.loadFeed(w, r)
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(feed)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to build feed")
+		return
+	}
+
+	// Any website may load the feed; it holds no personal data and needs no credentials
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Del("Access-Control-Allow-Credentials")
+	serveFeed(w, r, "application/json", body)
+}
+
+// GetDogsRSS handles GET /api/public/dogs/rss - adoptable dogs as RSS 2.0 feed (public)
+func (h *PublicFeedHandler) GetDogsRSS(w http.ResponseWriter, r *http.Request) {
+	feed, ok := h.loadFeed(w, r)
+	if !ok {
+		return
+	}
+
+	rss := rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       feed.Title,
+			Link:        feed.URL,
+			Description: "Hunde, die ein Zuhause suchen",
+			Language:    "de-de",
+			AtomLink:    atomLink{Href: h.baseURL() + r.URL.RequestURI(), Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	if feed.UpdatedAt != nil {
+		rss.Channel.LastBuildDate = feed.UpdatedAt.UTC().Format(time.RFC1123Z)
+	}
+	for _, dog := range newestFirst(feed.Dogs) {
+		rss.Channel.Items = append(rss.Channel.Items, rssItem{
+			Title:       dog.Name,
+			Link:        dog.URL,
+			Description: publicDogSummaryHTML(dog),
+			GUID:        rssGUID{Value: h.dogGUID(dog), IsPermaLink: false},
+			PubDate:     dog.CreatedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	body, err := marshalXML(rss)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to build feed")
+		return
+	}
+	serveFeed(w, r, "application/rss+xml; charset=utf-8", body)
+}
+
+// GetDogsAtom handles GET /api/public/dogs/atom - adoptable dogs as Atom feed (public)
+func (h *PublicFeedHandler) GetDogsAtom(w http.ResponseWriter, r *http.Request) {
+	feed, ok := h.loadFeed(w, r)
+	if !ok {
+		return
+	}
+
+	updated := time.Unix(0, 0)
+	if feed.UpdatedAt != nil {
+		updated = *feed.UpdatedAt
+	}
+
+	atom := atomFeed{
+		Title:   feed.Title,
+		ID:      h.baseURL() + "/api/public/dogs",
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: feed.URL, Rel: "alternate", Type: "text/html"},
+			{Href: h.baseURL() + r.URL.RequestURI(), Rel: "self", Type: "application/atom+xml"},
+		},
+		Author: atomAuthor{Name: "Gassigeher"},
+	}
+	for _, dog := range newestFirst(feed.Dogs) {
+		atom.Entries = append(atom.Entries, atomEntry{
+			Title:     dog.Name,
+			ID:        h.dogGUID(dog),
+			Updated:   dog.UpdatedAt.UTC().Format(time.RFC3339),
+			Published: dog.CreatedAt.UTC().Format(time.RFC3339),
+			Links:     []atomLink{{Href: dog.URL, Rel: "alternate", Type: "text/html"}},
+			Summary:   atomText{Type: "html", Value: publicDogSummaryHTML(dog)},
+		})
+	}
+
+	body, err := marshalXML(atom)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to build feed")
+		return
+	}
+	serveFeed(w, r, "application/atom+xml; charset=utf-8", body)
+}
+
+// GetDogsWidget handles GET /api/public/dogs/widget - adoptable dogs as HTML for an iframe (public)
+// The optional title query parameter replaces the heading
+func (h *PublicFeedHandler) GetDogsWidget(w http.ResponseWriter, r *http.Request) {
+	feed, ok := h.loadFeed(w, r)
+	if !ok {
+		return
+	}
+
+	title := strings.TrimSpace(r.URL.Query().Get("title"))
+	if title == "" {
+		title = "Unsere Hunde"
+	}
+	if runes := []rune(title); len(runes) > 100 {
+		title = string(runes[:100])
+	}
+
+	var buf bytes.Buffer
+	if err := dogWidgetTemplate.Execute(&buf, map[string]interface{}{
+		"Title": title,
+		"Feed":  feed,
+	}); err != nil {
+		log.Printf("Error rendering dog widget: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to build widget")
+		return
+	}
+
+	// The widget is made to be embedded in other websites
+	w.Header().Del("X-Frame-Options")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; img-src * data:; style-src 'unsafe-inline'; frame-ancestors *")
+	serveFeed(w, r, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// loadFeed loads the adoptable dogs for the query parameters:
+// limit (1-100, default 50), featured=true for featured dogs only and
+// link=external to link dogs to their external link instead of Gassigeher
+func (h *PublicFeedHandler) loadFeed(w http.ResponseWriter, r *http.Request) (*models.PublicDogFeed, bool) {
+	query := r.URL.Query()
+
+	limit := 50
+	if value := query.Get("limit"); value != "" {
+		l, err := strconv.Atoi(value)
+		if err != nil || l < 1 || l > 100 {
+			respondError(w, http.StatusBadRequest, "limit must be between 1 and 100")
+			return nil, false
+		}
+		limit = l
+	}
+
+	// Same rule as the featured dogs: in the shelter and not temporarily unavailable
+	available := true
+	dogs, err := h.dogRepo.FindAll(&models.DogFilterRequest{
+		Available: &available,
+		Statuses:  []models.DogStatus{models.DogStatusAvailable},
+	})
+	if err != nil {
+		log.Printf("Error fetching dogs for public feed: %v", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch dogs")
+		return nil, false
+	}
+
+	feed := &models.PublicDogFeed{
+		Title: publicFeedTitle,
+		URL:   h.baseURL() + "/",
+		Dogs:  []*models.PublicDog{},
+	}
+	externalLinks := query.Get("link") == "external"
+	featuredOnly := query.Get("featured") == "true"
+
+	for _, dog := range dogs {
+		if featuredOnly && !dog.IsFeatured {
+			continue
+		}
+		if len(feed.Dogs) == limit {
+			break
+		}
+
+		public := toPublicDog(dog, h.baseURL(), h.storage, externalLinks)
+		feed.Dogs = append(feed.Dogs, public)
+		if feed.UpdatedAt == nil || public.UpdatedAt.After(*feed.UpdatedAt) {
+			updatedAt := public.UpdatedAt
+			feed.UpdatedAt = &updatedAt
+		}
+	}
+
+	return feed, true
+}
+
+// dogGUID returns a permanent ID of a dog for feed readers, independent of the link
+func (h *PublicFeedHandler) dogGUID(dog *models.PublicDog) string {
+	return fmt.Sprintf("%s/api/public/dogs#%d", h.baseURL(), dog.ID)
+}
+
+// baseURL returns the configured base URL without trailing slash
+func (h *PublicFeedHandler) baseURL() string {
+	return strings.TrimRight(h.cfg.BaseURL, "/")
+}
+
+// toPublicDog copies the public-safe fields of a dog; URLs are absolute, as they are used on other sites
+// The dog links to its public page, or to its external link if externalLink is set and the dog has one
+func toPublicDog(dog *models.Dog, baseURL string, storage services.Storage, externalLink bool) *models.PublicDog {
+	absoluteURL := func(url string) string {
+		if strings.HasPrefix(url, "/") {
+			return baseURL + url
+		}
+		return url
+	}
+
+	public := &models.PublicDog{
+		ID:         dog.ID,
+		Name:       dog.Name,
+		Breed:      dog.Breed,
+		Size:       dog.Size,
+		Age:        dog.Age,
+		URL:        fmt.Sprintf("%s/hund/%d", baseURL, dog.ID),
+		IsFeatured: dog.IsFeatured,
+		CreatedAt:  dog.CreatedAt,
+		UpdatedAt:  dog.UpdatedAt,
+	}
+	if externalLink && dog.ExternalLink != nil && *dog.ExternalLink != "" {
+		public.URL = *dog.ExternalLink
+	}
+	if dog.Photo != nil && *dog.Photo != "" {
+		photoURL := absoluteURL(storage.URL(*dog.Photo))
+		public.PhotoURL = &photoURL
+	}
+	if dog.PhotoThumbnail != nil && *dog.PhotoThumbnail != "" {
+		thumbnailURL := absoluteURL(storage.URL(*dog.PhotoThumbnail))
+		public.ThumbnailURL = &thumbnailURL
+	}
+	return public
+}
+
+// serveFeed writes a feed response; it has no Last-Modified, as dogs leaving the feed do not
+// change the newest update of the dogs still in it. The ETag changes with them.
+func serveFeed(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	serveCached(w, r, contentType, body, nil)
+}
+
+// serveCached writes a public response with caching headers and answers conditional requests with 304 Not Modified
+// The ETag is a hash of the body, so it changes with every change of the shown dogs or the query
+func serveCached(w http.ResponseWriter, r *http.Request, contentType string, body []byte, lastModified *time.Time) {
+	hash := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", publicFeedMaxAge))
+	if lastModified != nil {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// notModified checks If-None-Match, or If-Modified-Since if no ETag was sent
+func notModified(r *http.Request, etag string, lastModified *time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := r.Header.Get("If-Modified-Since"); since != "" && lastModified != nil {
+		if t, err := http.ParseTime(since); err == nil {
+			return !lastModified.Truncate(time.Second).After(t)
+		}
+	}
+	return false
+}
+
+// newestFirst returns the dogs sorted by the day they were added, newest first
+func newestFirst(dogs []*models.PublicDog) []*models.PublicDog {
+	sorted := append([]*models.PublicDog{}, dogs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+	return sorted
+}
+
+// publicDogSummaryHTML returns the feed item text with the photo of a dog
+func publicDogSummaryHTML(dog *models.PublicDog) string {
+	summary := template.HTMLEscapeString(fmt.Sprintf("%s, %s, %s", dog.Breed, ageLabel(dog.Age), dog.SizeLabel()))
+	if dog.ThumbnailURL != nil {
+		summary = fmt.Sprintf(`<img src="%s" alt="%s"><br>%s`,
+			template.HTMLEscapeString(*dog.ThumbnailURL), template.HTMLEscapeString(dog.Name), summary)
+	}
+	return summary
+}
+
+// ageLabel returns the German age of a dog, e.g. "3 Jahre"
+func ageLabel(age int) string {
+	switch {
+	case age < 1:
+		return "unter 1 Jahr"
+	case age == 1:
+		return "1 Jahr"
+	}
+	return fmt.Sprintf("%d Jahre", age)
+}
+
+// marshalXML returns the indented XML document with declaration
+func marshalXML(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// atomFeed is an Atom 1.0 document
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title     string     `xml:"title"`
+	ID        string     `xml:"id"`
+	Updated   string     `xml:"updated"`
+	Published string     `xml:"published"`
+	Links     []atomLink `xml:"link"`
+	Summary   atomText   `xml:"summary"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// dogWidgetTemplate renders the embeddable list of dogs
+var dogWidgetTemplate = template.Must(template.New("widget").Funcs(template.FuncMap{
+	"age": ageLabel,
+}).Parse(`<!DOCTYPE html>
+<html lang="de">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; padding: 12px; font-family: -apple-system, "Segoe UI", Roboto, Arial, sans-serif; color: #26000E; background: transparent; }
+h2 { margin: 0 0 12px; font-size: 1.25rem; }
+.dogs { display: grid; grid-template-columns: repeat(auto-fill, minmax(160px, 1fr)); gap: 12px; }
+.dog { display: block; text-decoration: none; color: inherit; background: #fff; border-radius: 8px; overflow: hidden; box-shadow: 0 1px 4px rgba(0,0,0,.15); }
+.dog img, .dog .no-photo { display: block; width: 100%; aspect-ratio: 4 / 3; object-fit: cover; background: #f0ece4; }
+.dog .info { padding: 8px 10px; }
+.dog .name { font-weight: 600; }
+.dog .details { font-size: .85rem; color: #555; }
+.empty { color: #555; }
+</style>
+</head>
+<body>
+<h2>{{.Title}}</h2>
+{{if .Feed.Dogs}}<div class="dogs">
+{{range .Feed.Dogs}}<a class="dog" href="{{.URL}}" target="_blank" rel="noopener">
+{{if .ThumbnailURL}}<img src="{{.ThumbnailURL}}" alt="{{.Name}}" loading="lazy">{{else}}<div class="no-photo"></div>{{end}}
+<div class="info"><div class="name">{{.Name}}</div><div class="details">{{.Breed}} · {{age .Age}} · {{.SizeLabel}}</div></div>
+</a>
+{{end}}</div>{{else}}<p class="empty">Im Moment suchen keine Hunde ein Zuhause.</p>{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestPublicFeedHandler tests the public dog feed formats, public-safe fields and caching
+func TestPublicFeedHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret", BaseURL: "https://gassi.example.org/", UploadDir: t.TempDir()}
+	handler := NewPublicFeedHandler(db, cfg)
+	dogRepo := repository.NewDogRepository(db)
+
+	bellaID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	bella, _ := dogRepo.FindByID(bellaID)
+	photo, thumbnail := "dogs/bella_full.jpg", "dogs/bella_thumb.jpg"
+	needs, link := "Braucht Medikamente", "https://tierheim.example.org/bella"
+	bella.Photo, bella.PhotoThumbnail = &photo, &thumbnail
+	bella.SpecialNeeds, bella.ExternalLink = &needs, &link
+	dogRepo.Update(bella)
+
+	maxID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+	dogRepo.SetStatus(&models.DogStatusChange{DogID: maxID, Status: models.DogStatusAdopted})
+
+	rockyID := testutil.SeedTestDog(t, db, "Rocky", "Dackel", "blue")
+	rocky, _ := dogRepo.FindByID(rockyID)
+	rocky.IsAvailable = false
+	dogRepo.Update(rocky)
+
+	get := func(fn http.HandlerFunc, target string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+
+	t.Run("json lists only adoptable dogs with public fields", func(t *testing.T) {
+		rec := get(handler.GetDogsJSON, "/api/public/dogs", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if strings.Contains(rec.Body.String(), needs) || strings.Contains(rec.Body.String(), "special_needs") {
+			t.Error("Feed must not contain internal dog details")
+		}
+
+		var feed models.PublicDogFeed
+		json.Unmarshal(rec.Body.Bytes(), &feed)
+		if len(feed.Dogs) != 1 || feed.Dogs[0].Name != "Bella" {
+			t.Fatalf("Expected only Bella, got %+v", feed.Dogs)
+		}
+		if feed.Dogs[0].PhotoURL == nil || *feed.Dogs[0].PhotoURL != "https://gassi.example.org/uploads/dogs/bella_full.jpg" {
+			t.Errorf("Expected absolute photo URL, got %v", feed.Dogs[0].PhotoURL)
+		}
+		if feed.Dogs[0].URL != fmt.Sprintf("https://gassi.example.org/hund/%d", bellaID) {
+			t.Errorf("Expected link to the dog page, got %s", feed.Dogs[0].URL)
+		}
+		if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Error("Expected the JSON feed to be readable from any origin")
+		}
+	})
+
+	t.Run("external links", func(t *testing.T) {
+		rec := get(handler.GetDogsJSON, "/api/public/dogs?link=external", nil)
+		var feed models.PublicDogFeed
+		json.Unmarshal(rec.Body.Bytes(), &feed)
+		if len(feed.Dogs) != 1 || feed.Dogs[0].URL != link {
+			t.Errorf("Expected external link, got %+v", feed.Dogs)
+		}
+	})
+
+	t.Run("conditional requests", func(t *testing.T) {
+		rec := get(handler.GetDogsJSON, "/api/public/dogs", nil)
+		etag := rec.Header().Get("ETag")
+		if etag == "" || !strings.Contains(rec.Header().Get("Cache-Control"), "public") {
+			t.Fatalf("Expected caching headers, got %v", rec.Header())
+		}
+
+		rec = get(handler.GetDogsJSON, "/api/public/dogs", map[string]string{"If-None-Match": etag})
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("Expected 304 for matching ETag, got %d", rec.Code)
+		}
+
+		// Without Last-Modified, a dog leaving the feed cannot be answered with a stale 304
+		if rec.Header().Get("Last-Modified") != "" {
+			t.Error("Expected no Last-Modified for the feed")
+		}
+		future := time.Now().Add(48 * time.Hour).UTC().Format(http.TimeFormat)
+		rec = get(handler.GetDogsJSON, "/api/public/dogs", map[string]string{"If-Modified-Since": future})
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected If-Modified-Since to be ignored, got %d", rec.Code)
+		}
+
+		// A changed feed gets a new ETag
+		dogRepo.SetFeatured(bellaID, true)
+		rec = get(handler.GetDogsJSON, "/api/public/dogs", map[string]string{"If-None-Match": etag})
+		if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+			t.Errorf("Expected new content after a change, got %d", rec.Code)
+		}
+
+		// So does a dog leaving the feed
+		etag = rec.Header().Get("ETag")
+		dogRepo.SetStatus(&models.DogStatusChange{DogID: bellaID, Status: models.DogStatusReserved})
+		rec = get(handler.GetDogsJSON, "/api/public/dogs", map[string]string{"If-None-Match": etag})
+		if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+			t.Errorf("Expected new content after a dog left the feed, got %d", rec.Code)
+		}
+		dogRepo.SetStatus(&models.DogStatusChange{DogID: bellaID, Status: models.DogStatusAvailable})
+	})
+
+	t.Run("rss and atom", func(t *testing.T) {
+		rec := get(handler.GetDogsRSS, "/api/public/dogs/rss", nil)
+		var rss rssFeed
+		if err := xml.Unmarshal(rec.Body.Bytes(), &rss); err != nil {
+			t.Fatalf("Invalid RSS: %v", err)
+		}
+		if len(rss.Channel.Items) != 1 || rss.Channel.Items[0].Title != "Bella" {
+			t.Errorf("Expected Bella in RSS, got %+v", rss.Channel.Items)
+		}
+		if !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/rss+xml") {
+			t.Errorf("Unexpected content type %q", rec.Header().Get("Content-Type"))
+		}
+
+		rec = get(handler.GetDogsAtom, "/api/public/dogs/atom", nil)
+		var atom atomFeed
+		if err := xml.Unmarshal(rec.Body.Bytes(), &atom); err != nil {
+			t.Fatalf("Invalid Atom: %v", err)
+		}
+		if len(atom.Entries) != 1 || atom.Entries[0].Title != "Bella" || atom.Entries[0].ID != rss.Channel.Items[0].GUID.Value {
+			t.Errorf("Expected Bella with the same ID as in RSS, got %+v", atom.Entries)
+		}
+	})
+
+	t.Run("widget can be embedded", func(t *testing.T) {
+		rec := get(handler.GetDogsWidget, "/api/public/dogs/widget?title=<b>Hunde</b>", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		body := rec.Body.String()
+		if !strings.Contains(body, "Bella") || strings.Contains(body, "Rocky") {
+			t.Error("Expected only Bella in the widget")
+		}
+		if strings.Contains(body, "<b>Hunde</b>") {
+			t.Error("Title must be escaped")
+		}
+		if rec.Header().Get("X-Frame-Options") != "" || !strings.Contains(rec.Header().Get("Content-Security-Policy"), "frame-ancestors *") {
+			t.Errorf("Expected framing to be allowed, got %v", rec.Header())
+		}
+
+		// Long titles are cut by characters, not bytes
+		rec = get(handler.GetDogsWidget, "/api/public/dogs/widget?title="+url.QueryEscape(strings.Repeat("ü", 150)), nil)
+		if body := rec.Body.String(); !utf8.ValidString(body) || !strings.Contains(body, "<h2>"+strings.Repeat("ü", 100)+"</h2>") {
+			t.Error("Expected the title to be cut after 100 characters")
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		rec := get(handler.GetDogsJSON, "/api/public/dogs?limit=500", nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+}
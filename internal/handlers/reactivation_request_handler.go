@@ -21,6 +21,7 @@ type ReactivationRequestHandler struct {
 	requestRepo *repository.ReactivationRequestRepository
 	userRepo    *repository.UserRepository
 	emailService *services.EmailService
+	audit        *services.AuditService
 }
 
 // NewReactivationRequestHandler creates a new reactivation request handler
@@ -36,6 +37,7 @@ func NewReactivationRequestHandler(db *sql.DB, cfg *config.Config) *Reactivation
 		requestRepo:  repository.NewReactivationRequestRepository(db),
 		userRepo:     repository.NewUserRepository(db),
 		emailService: emailService,
+		audit:        newAuditService(db),
 	}
 }
 
@@ -175,6 +177,9 @@ func (h *ReactivationRequestHandler) ApproveRequest(w http.ResponseWriter, r *ht
 		respondError(w, http.StatusInternalServerError, "Failed to activate user")
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditReactivationRequestApprove, models.AuditTargetReactivationRequest, &id,
+		map[string]interface{}{"status": reactivationRequest.Status, "user_id": user.ID, "user_is_active": user.IsActive},
+		map[string]interface{}{"status": "approved", "user_id": user.ID, "user_is_active": true, "message": req.Message})
 
 	// Send email notification
 	if user.Email != nil && h.emailService != nil {
@@ -237,6 +242,9 @@ func (h *ReactivationRequestHandler) DenyRequest(w http.ResponseWriter, r *http.
 		respondError(w, http.StatusInternalServerError, "Failed to deny request")
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditReactivationRequestDeny, models.AuditTargetReactivationRequest, &id,
+		map[string]interface{}{"status": reactivationRequest.Status, "user_id": user.ID},
+		map[string]interface{}{"status": "denied", "user_id": user.ID, "message": req.Message})
 
 	// Send email notification
 	if user.Email != nil && h.emailService != nil {
@@ -0,0 +1,378 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/middleware"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// RoleHandler handles roles, their permissions and their assignment to users (Super Admin only)
+type RoleHandler struct {
+	db       *sql.DB
+	cfg      *config.Config
+	roleRepo *repository.RoleRepository
+	userRepo *repository.UserRepository
+	audit    *services.AuditService
+	sessions *services.SessionService
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(db *sql.DB, cfg *config.Config) *RoleHandler {
+	return &RoleHandler{
+		db:       db,
+		cfg:      cfg,
+		roleRepo: repository.NewRoleRepository(db),
+		userRepo: repository.NewUserRepository(db),
+		audit:    newAuditService(db),
+		sessions: newSessionService(db, cfg),
+	}
+}
+
+// ListPermissions handles GET /api/admin/permissions - list all permissions (super admin only)
+func (h *RoleHandler) ListPermissions(w http.ResponseWriter, r *http.Request) {
+	permissions, err := h.roleRepo.ListPermissions()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get permissions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, permissions)
+}
+
+// ListRoles handles GET /api/admin/roles - list all roles with their permissions (super admin only)
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.roleRepo.FindAll()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get roles")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, roles)
+}
+
+// CreateRole handles POST /api/admin/roles - create a custom role (super admin only)
+func (h *RoleHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeRoleRequest(w, r, 0)
+	if !ok {
+		return
+	}
+
+	role := &models.Role{Name: req.Name, Description: req.Description, Permissions: req.Permissions}
+	if err := h.roleRepo.Create(role); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create role")
+		return
+	}
+	h.audit.Record(auditActor(r), models.AuditRoleCreate, models.AuditTargetRole, &role.ID, nil, role)
+
+	respondJSON(w, http.StatusCreated, role)
+}
+
+// UpdateRole handles PUT /api/admin/roles/:id - change a role and its permissions (super admin only)
+// System roles keep their name, the admin role cannot be changed
+func (h *RoleHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := h.requireRole(w, r)
+	if !ok {
+		return
+	}
+	if role.Name == models.RoleAdmin {
+		respondError(w, http.StatusBadRequest, "The admin role always has all permissions")
+		return
+	}
+
+	req, ok := h.decodeRoleRequest(w, r, role.ID)
+	if !ok {
+		return
+	}
+	if role.IsSystem && req.Name != role.Name {
+		respondError(w, http.StatusBadRequest, "System roles cannot be renamed")
+		return
+	}
+
+	permissionsBefore, ok := h.roleUserPermissions(w, role.ID)
+	if !ok {
+		return
+	}
+
+	before := *role
+	role.Name = req.Name
+	role.Description = req.Description
+	role.Permissions = req.Permissions
+	if err := h.roleRepo.Update(role); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update role")
+		return
+	}
+	h.audit.Record(auditActor(r), models.AuditRoleUpdate, models.AuditTargetRole, &role.ID, &before, role)
+	h.revokeIfPermissionsLost(permissionsBefore)
+
+	respondJSON(w, http.StatusOK, role)
+}
+
+// DeleteRole handles DELETE /api/admin/roles/:id - delete a custom role and remove it from all users (super admin only)
+func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	role, ok := h.requireRole(w, r)
+	if !ok {
+		return
+	}
+	if role.IsSystem {
+		respondError(w, http.StatusBadRequest, "System roles cannot be deleted")
+		return
+	}
+
+	permissionsBefore, ok := h.roleUserPermissions(w, role.ID)
+	if !ok {
+		return
+	}
+
+	if err := h.roleRepo.Delete(role.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to delete role")
+		return
+	}
+	h.audit.Record(auditActor(r), models.AuditRoleDelete, models.AuditTargetRole, &role.ID, role, nil)
+	h.revokeIfPermissionsLost(permissionsBefore)
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Role deleted"})
+}
+
+// GetUserRoles handles GET /api/admin/users/:id/roles - roles and resulting permissions of a user (super admin only)
+func (h *RoleHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	h.respondUserRoles(w, user.ID)
+}
+
+// SetUserRoles handles PUT /api/admin/users/:id/roles - replace the roles of a user (super admin only)
+// Assigning or removing the admin role also promotes or demotes the user
+func (h *RoleHandler) SetUserRoles(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	if user.IsSuperAdmin {
+		respondError(w, http.StatusBadRequest, "Cannot modify Super Admin")
+		return
+	}
+
+	var req models.SetUserRolesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	roleIDs := []int{}
+	seen := map[int]bool{}
+	for _, roleID := range req.RoleIDs {
+		if seen[roleID] {
+			continue
+		}
+		seen[roleID] = true
+
+		role, err := h.roleRepo.FindByID(roleID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get role")
+			return
+		}
+		if role == nil {
+			respondError(w, http.StatusBadRequest, "Unknown role: "+strconv.Itoa(roleID))
+			return
+		}
+		roleIDs = append(roleIDs, roleID)
+	}
+
+	before, err := h.roleNames(user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user roles")
+		return
+	}
+	permissionsBefore, err := h.roleRepo.GetUserPermissions(user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user permissions")
+		return
+	}
+
+	assignedBy, _ := r.Context().Value(middleware.UserIDKey).(int)
+	if err := h.roleRepo.SetUserRoles(user.ID, roleIDs, assignedBy); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update user roles")
+		return
+	}
+	if after, err := h.roleNames(user.ID); err == nil {
+		h.audit.Record(auditActor(r), models.AuditUserRolesSet, models.AuditTargetUser, &user.ID,
+			map[string]interface{}{"roles": before}, map[string]interface{}{"roles": after})
+	}
+
+	h.revokeIfPermissionsLost(map[int][]models.Permission{user.ID: permissionsBefore})
+
+	h.respondUserRoles(w, user.ID)
+}
+
+// roleUserPermissions returns the permissions of all users of a role, keyed by user ID
+func (h *RoleHandler) roleUserPermissions(w http.ResponseWriter, roleID int) (map[int][]models.Permission, bool) {
+	userIDs, err := h.roleRepo.FindUserIDs(roleID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get role users")
+		return nil, false
+	}
+
+	permissions := map[int][]models.Permission{}
+	for _, userID := range userIDs {
+		granted, err := h.roleRepo.GetUserPermissions(userID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get user permissions")
+			return nil, false
+		}
+		permissions[userID] = granted
+	}
+	return permissions, true
+}
+
+// revokeIfPermissionsLost ends all sessions of users who lost a permission through a role change,
+// so they log in again with what they may still do (e.g. demoted admins still hold an is_admin token)
+func (h *RoleHandler) revokeIfPermissionsLost(before map[int][]models.Permission) {
+	for userID, granted := range before {
+		after, err := h.roleRepo.GetUserPermissions(userID)
+		if err != nil {
+			log.Printf("Error checking permissions of user %d after a role change: %v", userID, err)
+			h.sessions.RevokeAllQuietly(userID, "role change")
+			continue
+		}
+		if models.PermissionsLost(granted, after) {
+			h.sessions.RevokeAllQuietly(userID, "role change")
+		}
+	}
+}
+
+// respondUserRoles writes the roles and permissions of a user
+func (h *RoleHandler) respondUserRoles(w http.ResponseWriter, userID int) {
+	roles, err := h.roleRepo.FindByUser(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user roles")
+		return
+	}
+	permissions, err := h.roleRepo.GetUserPermissions(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user permissions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"user_id":     userID,
+		"roles":       roles,
+		"permissions": permissions,
+	})
+}
+
+// roleNames returns the names of the roles assigned to a user, for the audit log
+func (h *RoleHandler) roleNames(userID int) ([]string, error) {
+	roles, err := h.roleRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names, nil
+}
+
+// decodeRoleRequest decodes and validates a role request; names must be unique
+func (h *RoleHandler) decodeRoleRequest(w http.ResponseWriter, r *http.Request, roleID int) (*models.RoleRequest, bool) {
+	var req models.RoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return nil, false
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	exists, err := h.roleRepo.NameExists(req.Name, roleID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to check role name")
+		return nil, false
+	}
+	if exists {
+		respondError(w, http.StatusConflict, "A role with this name already exists")
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// requireRole parses the role ID from the URL and loads the role
+func (h *RoleHandler) requireRole(w http.ResponseWriter, r *http.Request) (*models.Role, bool) {
+	roleID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid role ID")
+		return nil, false
+	}
+
+	role, err := h.roleRepo.FindByID(roleID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get role")
+		return nil, false
+	}
+	if role == nil {
+		respondError(w, http.StatusNotFound, "Role not found")
+		return nil, false
+	}
+
+	return role, true
+}
+
+// requireUser parses the user ID from the URL and loads the user
+func (h *RoleHandler) requireUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return nil, false
+	}
+
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user")
+		return nil, false
+	}
+	if user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return nil, false
+	}
+
+	return user, true
+}
+
+// hasPermission checks if the user is an admin or has the permission through a role
+// Used by endpoints open to all users that show more to admins
+func hasPermission(r *http.Request, roleRepo *repository.RoleRepository, permission models.Permission) bool {
+	if middleware.HasPermission(r.Context(), permission) {
+		return true
+	}
+
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int)
+	if !ok {
+		return false
+	}
+	permissions, err := roleRepo.GetUserPermissions(userID)
+	if err != nil {
+		log.Printf("Error loading permissions of user %d: %v", userID, err)
+		return false
+	}
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
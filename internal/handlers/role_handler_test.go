@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestRoleHandler tests role management and assigning roles to users
+func TestRoleHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewRoleHandler(db, cfg)
+
+	superAdminID := testutil.SeedTestUser(t, db, "super@example.com", "Super", "orange")
+	db.Exec(`UPDATE users SET is_admin = 1, is_super_admin = 1 WHERE id = ?`, superAdminID)
+	userID := testutil.SeedTestUser(t, db, "helfer@example.com", "Helfer", "blue")
+
+	call := func(fn http.HandlerFunc, method, target string, vars map[string]string, reqBody interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		req = mux.SetURLVars(req, vars)
+		req = req.WithContext(contextWithUser(req.Context(), superAdminID, "super@example.com", true))
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+
+	sessionRepo := repository.NewSessionRepository(db)
+	startSession := func() int {
+		session := &models.UserSession{UserID: userID, RefreshTokenHash: fmt.Sprint(time.Now().UnixNano()), ExpiresAt: time.Now().Add(time.Hour)}
+		if err := sessionRepo.Create(session); err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		return session.ID
+	}
+	sessionActive := func(id int) bool {
+		active, _ := sessionRepo.IsActive(id)
+		return active
+	}
+
+	var roles []*models.Role
+	json.Unmarshal(call(handler.ListRoles, "GET", "/api/admin/roles", nil, nil).Body.Bytes(), &roles)
+	roleIDs := map[string]int{}
+	for _, role := range roles {
+		roleIDs[role.Name] = role.ID
+	}
+	if len(roleIDs) != 4 {
+		t.Fatalf("Expected 4 system roles, got %v", roleIDs)
+	}
+
+	var custom models.Role
+	t.Run("create custom role", func(t *testing.T) {
+		rec := call(handler.CreateRole, "POST", "/api/admin/roles", nil, models.RoleRequest{
+			Name:        "Vorfallteam",
+			Permissions: []models.Permission{models.PermissionManageIncidents, models.PermissionManageIncidents},
+		})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		json.Unmarshal(rec.Body.Bytes(), &custom)
+		if len(custom.Permissions) != 1 {
+			t.Errorf("Expected duplicate permissions to be removed, got %v", custom.Permissions)
+		}
+
+		rec = call(handler.CreateRole, "POST", "/api/admin/roles", nil, models.RoleRequest{Name: "Test", Permissions: []models.Permission{"dogs.fly"}})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for unknown permission, got %d", rec.Code)
+		}
+		rec = call(handler.CreateRole, "POST", "/api/admin/roles", nil, models.RoleRequest{Name: "approver"})
+		if rec.Code != http.StatusConflict {
+			t.Errorf("Expected status 409 for duplicate name, got %d", rec.Code)
+		}
+	})
+
+	t.Run("system roles are protected", func(t *testing.T) {
+		adminVars := map[string]string{"id": fmt.Sprint(roleIDs[models.RoleAdmin])}
+		rec := call(handler.UpdateRole, "PUT", "/api/admin/roles/1", adminVars, models.RoleRequest{Name: models.RoleAdmin})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 when changing the admin role, got %d", rec.Code)
+		}
+
+		approverVars := map[string]string{"id": fmt.Sprint(roleIDs["approver"])}
+		rec = call(handler.UpdateRole, "PUT", "/api/admin/roles/3", approverVars, models.RoleRequest{Name: "Genehmiger"})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 when renaming a system role, got %d", rec.Code)
+		}
+		rec = call(handler.UpdateRole, "PUT", "/api/admin/roles/3", approverVars, models.RoleRequest{
+			Name:        "approver",
+			Permissions: []models.Permission{models.PermissionApproveBookings},
+		})
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected permissions of system roles to be editable, got %d", rec.Code)
+		}
+
+		rec = call(handler.DeleteRole, "DELETE", "/api/admin/roles/3", approverVars, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 when deleting a system role, got %d", rec.Code)
+		}
+	})
+
+	t.Run("assign roles to a user", func(t *testing.T) {
+		vars := map[string]string{"id": fmt.Sprint(userID)}
+		rec := call(handler.SetUserRoles, "PUT", "/api/admin/users/2/roles", vars, models.SetUserRolesRequest{
+			RoleIDs: []int{roleIDs["dog_coordinator"], custom.ID},
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		var result struct {
+			Roles       []*models.Role      `json:"roles"`
+			Permissions []models.Permission `json:"permissions"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &result)
+		if len(result.Roles) != 2 || len(result.Permissions) != 3 {
+			t.Errorf("Expected 2 roles with 3 permissions, got %+v", result)
+		}
+
+		rec = call(handler.SetUserRoles, "PUT", "/api/admin/users/2/roles", vars, models.SetUserRolesRequest{RoleIDs: []int{999}})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for unknown role, got %d", rec.Code)
+		}
+
+		superVars := map[string]string{"id": fmt.Sprint(superAdminID)}
+		rec = call(handler.SetUserRoles, "PUT", "/api/admin/users/1/roles", superVars, models.SetUserRolesRequest{})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for the super admin, got %d", rec.Code)
+		}
+	})
+
+	t.Run("losing a permission ends the sessions", func(t *testing.T) {
+		userVars := map[string]string{"id": fmt.Sprint(userID)}
+		customVars := map[string]string{"id": fmt.Sprint(custom.ID)}
+		setRoles := func(roleIDs ...int) {
+			rec := call(handler.SetUserRoles, "PUT", "/api/admin/users/2/roles", userVars, models.SetUserRolesRequest{RoleIDs: roleIDs})
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+		}
+		updateCustom := func(permissions ...models.Permission) {
+			rec := call(handler.UpdateRole, "PUT", "/api/admin/roles/5", customVars, models.RoleRequest{Name: custom.Name, Permissions: permissions})
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+		}
+
+		session := startSession()
+		setRoles(roleIDs["dog_coordinator"], custom.ID, roleIDs["approver"])
+		if !sessionActive(session) {
+			t.Error("Gaining a role must not end the sessions")
+		}
+
+		updateCustom(models.PermissionViewReports)
+		if sessionActive(session) {
+			t.Error("Removing a permission from a role must end the sessions of its users")
+		}
+
+		session = startSession()
+		setRoles(roleIDs["dog_coordinator"], custom.ID)
+		if sessionActive(session) {
+			t.Error("Removing a role with its permissions must end the sessions")
+		}
+
+		session = startSession()
+		updateCustom(models.PermissionViewReports, models.PermissionManageIncidents)
+		if !sessionActive(session) {
+			t.Error("Adding a permission to a role must not end the sessions")
+		}
+	})
+
+	t.Run("delete custom role", func(t *testing.T) {
+		session := startSession()
+		vars := map[string]string{"id": fmt.Sprint(custom.ID)}
+		if rec := call(handler.DeleteRole, "DELETE", "/api/admin/roles/5", vars, nil); rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", rec.Code)
+		}
+		if sessionActive(session) {
+			t.Error("Deleting a role must end the sessions of its users")
+		}
+
+		rec := call(handler.GetUserRoles, "GET", "/api/admin/users/2/roles", map[string]string{"id": fmt.Sprint(userID)}, nil)
+		var result struct {
+			Roles []*models.Role `json:"roles"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &result)
+		if len(result.Roles) != 1 {
+			t.Errorf("Expected the deleted role to be removed from the user, got %d roles", len(result.Roles))
+		}
+	})
+}
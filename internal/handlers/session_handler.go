@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/middleware"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// SessionHandler handles the login sessions of the current user
+type SessionHandler struct {
+	db       *sql.DB
+	cfg      *config.Config
+	sessions *services.SessionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(db *sql.DB, cfg *config.Config) *SessionHandler {
+	return &SessionHandler{
+		db:       db,
+		cfg:      cfg,
+		sessions: newSessionService(db, cfg),
+	}
+}
+
+// ListSessions handles GET /api/users/me/sessions - active sessions, the current one marked
+func (h *SessionHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+	sessionID, _ := r.Context().Value(middleware.SessionIDKey).(int)
+
+	sessions, err := h.sessions.List(userID, sessionID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get sessions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sessions)
+}
+
+// RevokeSession handles DELETE /api/users/me/sessions/{id} - logs out one device
+func (h *SessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+	sessionID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	found, err := h.sessions.Revoke(userID, sessionID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to revoke session")
+		return
+	}
+	if !found {
+		respondError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Sitzung beendet"})
+}
+
+// newSessionService creates the session service with the token lifetimes of the configuration
+func newSessionService(db *sql.DB, cfg *config.Config) *services.SessionService {
+	return services.NewSessionService(
+		repository.NewSessionRepository(db),
+		repository.NewUserRepository(db),
+		newAuthService(db, cfg),
+		time.Duration(cfg.AccessTokenMinutes)*time.Minute,
+		time.Duration(cfg.RefreshTokenDays)*24*time.Hour,
+	)
+}
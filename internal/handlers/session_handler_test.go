@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/middleware"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestSessionHandler tests refresh, logout, the sessions list and revocation on deactivation and password change
+func TestSessionHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret", JWTExpirationHours: 24, AccessTokenMinutes: 15, RefreshTokenDays: 30}
+	handler := NewSessionHandler(db, cfg)
+	authHandler := NewAuthHandler(db, cfg)
+	userHandler := NewUserHandler(db, cfg)
+	sessionRepo := repository.NewSessionRepository(db)
+
+	authService := services.NewAuthService(cfg.JWTSecret, cfg.JWTExpirationHours)
+	hash, _ := authService.HashPassword("Test1234")
+	userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	db.Exec(`UPDATE users SET password_hash = ?`, hash)
+
+	call := func(fn http.HandlerFunc, method, target string, id, sessionID int, vars map[string]string, reqBody interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		if id != 0 {
+			ctx := contextWithUser(req.Context(), id, "", id == adminID)
+			req = req.WithContext(context.WithValue(ctx, middleware.SessionIDKey, sessionID))
+		}
+		req = mux.SetURLVars(req, vars)
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+	login := func() models.LoginResponse {
+		rec := call(authHandler.Login, "POST", "/api/auth/login", 0, 0, nil, models.LoginRequest{Email: "anna@example.com", Password: "Test1234"})
+		var resp models.LoginResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != http.StatusOK || resp.Token == "" || resp.RefreshToken == "" || resp.ExpiresIn != 900 {
+			t.Fatalf("Expected access and refresh token, got %d: %s", rec.Code, rec.Body.String())
+		}
+		return resp
+	}
+	sessionOf := func(resp models.LoginResponse) int {
+		claims, _ := newAuthService(db, cfg).ValidateJWT(resp.Token)
+		return int((*claims)["sid"].(float64))
+	}
+
+	t.Run("refresh and logout", func(t *testing.T) {
+		resp := login()
+		rec := call(authHandler.Refresh, "POST", "/api/auth/refresh", 0, 0, nil, models.RefreshTokenRequest{RefreshToken: resp.RefreshToken})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected refresh to succeed, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var refreshed models.LoginResponse
+		json.Unmarshal(rec.Body.Bytes(), &refreshed)
+		if refreshed.RefreshToken == resp.RefreshToken || refreshed.User == nil {
+			t.Errorf("Expected a rotated refresh token and the user, got %+v", refreshed)
+		}
+
+		rec = call(authHandler.Logout, "POST", "/api/auth/logout", 0, 0, nil, models.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected logout to succeed, got %d", rec.Code)
+		}
+		if active, _ := sessionRepo.IsActive(sessionOf(resp)); active {
+			t.Error("Expected the session to be revoked after logout")
+		}
+		rec = call(authHandler.Refresh, "POST", "/api/auth/refresh", 0, 0, nil, models.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected refresh after logout to fail, got %d", rec.Code)
+		}
+	})
+
+	t.Run("sessions list and revoke", func(t *testing.T) {
+		current := sessionOf(login())
+		other := sessionOf(login())
+
+		rec := call(handler.ListSessions, "GET", "/api/users/me/sessions", userID, current, nil, nil)
+		var sessions []*models.UserSession
+		json.Unmarshal(rec.Body.Bytes(), &sessions)
+		if len(sessions) != 2 {
+			t.Fatalf("Expected 2 active sessions, got %d", len(sessions))
+		}
+		for _, session := range sessions {
+			if session.Current != (session.ID == current) {
+				t.Errorf("Unexpected current flag on session %d", session.ID)
+			}
+		}
+
+		rec = call(handler.RevokeSession, "DELETE", "/api/users/me/sessions/x", adminID, 0, map[string]string{"id": fmt.Sprint(other)}, nil)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected sessions of other users not to be found, got %d", rec.Code)
+		}
+		rec = call(handler.RevokeSession, "DELETE", "/api/users/me/sessions/x", userID, current, map[string]string{"id": fmt.Sprint(other)}, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected revoke to succeed, got %d", rec.Code)
+		}
+		if active, _ := sessionRepo.IsActive(other); active {
+			t.Error("Expected the session to be revoked")
+		}
+	})
+
+	t.Run("password change keeps only the current session", func(t *testing.T) {
+		current := sessionOf(login())
+		other := sessionOf(login())
+
+		rec := call(authHandler.ChangePassword, "PUT", "/api/auth/change-password", userID, current, nil, map[string]string{
+			"old_password": "Test1234", "new_password": "Test5678", "confirm_password": "Test5678",
+		})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected password change to succeed, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if active, _ := sessionRepo.IsActive(current); !active {
+			t.Error("Expected the current session to stay active")
+		}
+		if active, _ := sessionRepo.IsActive(other); active {
+			t.Error("Expected other sessions to be revoked")
+		}
+		db.Exec(`UPDATE users SET password_hash = ? WHERE id = ?`, hash, userID)
+	})
+
+	t.Run("logout all and deactivation", func(t *testing.T) {
+		first := sessionOf(login())
+		rec := call(authHandler.LogoutAll, "POST", "/api/auth/logout-all", userID, first, nil, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected logout-all to succeed, got %d", rec.Code)
+		}
+		if active, _ := sessionRepo.IsActive(first); active {
+			t.Error("Expected all sessions to be revoked")
+		}
+
+		second := sessionOf(login())
+		rec = call(userHandler.DeactivateUser, "PUT", "/api/users/x/deactivate", adminID, 0, map[string]string{"id": fmt.Sprint(userID)},
+			map[string]string{"reason": "test"})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected deactivation to succeed, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if active, _ := sessionRepo.IsActive(second); active {
+			t.Error("Expected deactivation to revoke the sessions")
+		}
+	})
+}
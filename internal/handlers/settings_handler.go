@@ -10,6 +10,7 @@ import (
 	"github.com/tranmh/gassigeher/internal/config"
 	"github.com/tranmh/gassigeher/internal/models"
 	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
 )
 
 // SettingsHandler handles system settings-related HTTP requests
@@ -17,6 +18,7 @@ type SettingsHandler struct {
 	db           *sql.DB
 	cfg          *config.Config
 	settingsRepo *repository.SettingsRepository
+	audit        *services.AuditService
 }
 
 // NewSettingsHandler creates a new settings handler
@@ -25,6 +27,7 @@ func NewSettingsHandler(db *sql.DB, cfg *config.Config) *SettingsHandler {
 		db:           db,
 		cfg:          cfg,
 		settingsRepo: repository.NewSettingsRepository(db),
+		audit:        newAuditService(db),
 	}
 }
 
@@ -61,9 +64,18 @@ func (h *SettingsHandler) UpdateSetting(w http.ResponseWriter, r *http.Request)
 	// BUGFIX #3: Validate numeric settings to prevent silent failures
 	// These settings must be valid positive integers
 	numericSettings := map[string]bool{
-		"booking_advance_days":      true,
-		"cancellation_notice_hours": true,
-		"auto_deactivation_days":    true,
+		"booking_advance_days":          true,
+		"cancellation_notice_hours":     true,
+		"auto_deactivation_days":        true,
+		"max_dogs_per_booking":          true,
+		"max_walkers_per_booking":       true,
+		"walk_report_reminder_hours":    true,
+		"vaccination_expiry_alert_days": true,
+		"orphaned_upload_grace_hours":   true,
+		"neglected_dog_days":            true,
+		"audit_log_retention_days":      true,
+		"magic_link_expiry_minutes":     true,
+		"open_walk_cancel_hours":        true,
 	}
 
 	if numericSettings[key] {
@@ -73,6 +85,23 @@ func (h *SettingsHandler) UpdateSetting(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	if (key == "orphaned_upload_auto_delete" || key == "require_2fa_for_admins" || key == "magic_link_login_enabled") && req.Value != "true" && req.Value != "false" {
+		respondError(w, http.StatusBadRequest, "Value must be true or false")
+		return
+	}
+
+	if key == "incident_auto_unavailable_severity" && req.Value != "none" && !models.IsValidIncidentSeverity(req.Value) {
+		respondError(w, http.StatusBadRequest, "Value must be low, medium, high, critical, or none")
+		return
+	}
+
+	// Previous value for the audit log
+	previous, err := h.settingsRepo.Get(key)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get setting")
+		return
+	}
+
 	// Update setting
 	if err := h.settingsRepo.Update(key, req.Value); err != nil {
 		if err.Error() == "setting not found" {
@@ -82,6 +111,10 @@ func (h *SettingsHandler) UpdateSetting(w http.ResponseWriter, r *http.Request)
 		respondError(w, http.StatusInternalServerError, "Failed to update setting")
 		return
 	}
+	if previous != nil {
+		h.audit.Record(auditActor(r), models.AuditSettingUpdate, models.AuditTargetSetting, nil,
+			map[string]string{key: previous.Value}, map[string]string{key: req.Value})
+	}
 
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Setting updated successfully"})
 }
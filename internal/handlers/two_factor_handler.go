@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/middleware"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// TwoFactorHandler handles TOTP two-factor authentication of the current user and its reset by super admins
+type TwoFactorHandler struct {
+	db          *sql.DB
+	cfg         *config.Config
+	userRepo    *repository.UserRepository
+	authService *services.AuthService
+	twoFactor   *services.TwoFactorService
+	audit       *services.AuditService
+}
+
+// NewTwoFactorHandler creates a new two-factor handler
+func NewTwoFactorHandler(db *sql.DB, cfg *config.Config) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		db:          db,
+		cfg:         cfg,
+		userRepo:    repository.NewUserRepository(db),
+		authService: services.NewAuthService(cfg.JWTSecret, cfg.JWTExpirationHours),
+		twoFactor:   newTwoFactorService(db),
+		audit:       newAuditService(db),
+	}
+}
+
+// GetStatus handles GET /api/users/me/2fa
+func (h *TwoFactorHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	status, err := h.twoFactor.Status(user)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get two-factor status")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, status)
+}
+
+// Setup handles POST /api/users/me/2fa/setup - creates a new secret to scan in the authenticator app
+func (h *TwoFactorHandler) Setup(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.twoFactor.IsEnabled(user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get two-factor status")
+		return
+	}
+	if enabled {
+		respondError(w, http.StatusConflict, "Zwei-Faktor-Authentifizierung ist bereits eingerichtet")
+		return
+	}
+
+	setup, err := h.twoFactor.Setup(user.ID, *user.Email)
+	if errors.Is(err, services.ErrTwoFactorLocked) {
+		respondError(w, http.StatusTooManyRequests, "Zu viele falsche Codes, bitte in 15 Minuten erneut versuchen")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to set up two-factor authentication")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, setup)
+}
+
+// Enable handles POST /api/users/me/2fa/enable - confirms the secret with a code and returns the recovery codes
+func (h *TwoFactorHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Code) == "" {
+		respondError(w, http.StatusBadRequest, "Code is required")
+		return
+	}
+
+	codes, valid, err := h.twoFactor.Enable(user.ID, req.Code)
+	if errors.Is(err, services.ErrTwoFactorLocked) {
+		respondError(w, http.StatusTooManyRequests, "Zu viele falsche Codes, bitte in 15 Minuten erneut versuchen")
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to enable two-factor authentication")
+		return
+	}
+	if !valid {
+		respondError(w, http.StatusBadRequest, "Ungültiger Code oder keine Einrichtung begonnen")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable handles POST /api/users/me/2fa/disable - requires the password and a current code
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.DisableTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if h.twoFactor.IsRequired(user) {
+		respondError(w, http.StatusForbidden, "Zwei-Faktor-Authentifizierung ist für Administratoren vorgeschrieben")
+		return
+	}
+	if user.PasswordHash == nil || !h.authService.CheckPassword(req.Password, *user.PasswordHash) {
+		respondError(w, http.StatusUnauthorized, "Passwort ist falsch")
+		return
+	}
+	if !h.verifyCode(w, user.ID, req.Code) {
+		return
+	}
+
+	if err := h.twoFactor.Disable(user.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to disable two-factor authentication")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Zwei-Faktor-Authentifizierung deaktiviert"})
+}
+
+// RegenerateRecoveryCodes handles POST /api/users/me/2fa/recovery-codes - replaces all recovery codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if !h.verifyCode(w, user.ID, req.Code) {
+		return
+	}
+
+	codes, err := h.twoFactor.RegenerateRecoveryCodes(user.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to generate recovery codes")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// ResetUserTwoFactor handles DELETE /api/admin/users/{id}/2fa - turns off 2FA of a user who lost
+// their authenticator and recovery codes (Super Admin only)
+func (h *TwoFactorHandler) ResetUserTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user")
+		return
+	}
+	if user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	enabled, err := h.twoFactor.IsEnabled(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get two-factor status")
+		return
+	}
+	if err := h.twoFactor.Disable(userID); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to reset two-factor authentication")
+		return
+	}
+	h.audit.Record(auditActor(r), models.AuditUser2FAReset, models.AuditTargetUser, &userID,
+		map[string]interface{}{"two_factor_enabled": enabled}, map[string]interface{}{"two_factor_enabled": false})
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Zwei-Faktor-Authentifizierung zurückgesetzt"})
+}
+
+// currentUser loads the authenticated user
+func (h *TwoFactorHandler) currentUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get user")
+		return nil, false
+	}
+	if user == nil || user.Email == nil {
+		respondError(w, http.StatusNotFound, "User not found")
+		return nil, false
+	}
+	return user, true
+}
+
+// verifyCode checks a TOTP or recovery code of a user with 2FA enabled and responds with an error otherwise
+func (h *TwoFactorHandler) verifyCode(w http.ResponseWriter, userID int, code string) bool {
+	if strings.TrimSpace(code) == "" {
+		respondError(w, http.StatusBadRequest, "Code is required")
+		return false
+	}
+
+	valid, err := h.twoFactor.Verify(userID, code)
+	if errors.Is(err, services.ErrTwoFactorLocked) {
+		respondError(w, http.StatusTooManyRequests, "Zu viele falsche Codes, bitte in 15 Minuten erneut versuchen")
+		return false
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to verify code")
+		return false
+	}
+	if !valid {
+		respondError(w, http.StatusUnauthorized, "Ungültiger Code")
+		return false
+	}
+	return true
+}
+
+// newTwoFactorService creates the two-factor service used by the login and 2FA handlers
+func newTwoFactorService(db *sql.DB) *services.TwoFactorService {
+	return services.NewTwoFactorService(repository.NewTwoFactorRepository(db), repository.NewSettingsRepository(db), repository.NewRoleRepository(db))
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestTwoFactorHandler tests enrolment, the second login step, recovery codes, the lock after
+// wrong codes and the super admin reset
+func TestTwoFactorHandler(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret", JWTExpirationHours: 24}
+	handler := NewTwoFactorHandler(db, cfg)
+	authHandler := NewAuthHandler(db, cfg)
+
+	authService := services.NewAuthService(cfg.JWTSecret, cfg.JWTExpirationHours)
+	hash, _ := authService.HashPassword("Test1234")
+	userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	db.Exec(`UPDATE users SET password_hash = ?`, hash)
+	db.Exec(`UPDATE users SET is_admin = 1 WHERE id = ?`, adminID)
+
+	call := func(fn http.HandlerFunc, target string, id int, email string, vars map[string]string, reqBody interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		req := httptest.NewRequest("POST", target, bytes.NewReader(body))
+		if id != 0 {
+			req = req.WithContext(contextWithUser(req.Context(), id, email, id == adminID))
+		}
+		req = mux.SetURLVars(req, vars)
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+	login := func(email string) models.LoginResponse {
+		rec := call(authHandler.Login, "/api/auth/login", 0, "", nil, models.LoginRequest{Email: email, Password: "Test1234"})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected login to succeed, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp models.LoginResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return resp
+	}
+	currentCode := func(secret string) string {
+		code, _ := services.TOTPCode(secret, services.TOTPStep(time.Now()))
+		return code
+	}
+
+	// Enrol the user
+	rec := call(handler.Setup, "/api/users/me/2fa/setup", userID, "anna@example.com", nil, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected setup to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var setup models.TwoFactorSetupResponse
+	json.Unmarshal(rec.Body.Bytes(), &setup)
+
+	if rec := call(handler.Enable, "/api/users/me/2fa/enable", userID, "anna@example.com", nil, models.TwoFactorCodeRequest{Code: "000000"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a wrong code to be rejected, got %d", rec.Code)
+	}
+	usedCode := currentCode(setup.Secret)
+	rec = call(handler.Enable, "/api/users/me/2fa/enable", userID, "anna@example.com", nil, models.TwoFactorCodeRequest{Code: usedCode})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected enabling to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var recovery models.RecoveryCodesResponse
+	json.Unmarshal(rec.Body.Bytes(), &recovery)
+	if len(recovery.RecoveryCodes) != services.RecoveryCodeCount {
+		t.Fatalf("Expected %d recovery codes, got %v", services.RecoveryCodeCount, recovery.RecoveryCodes)
+	}
+
+	t.Run("login requires the second factor", func(t *testing.T) {
+		resp := login("anna@example.com")
+		if resp.Token != "" || !resp.TwoFactorRequired || resp.PreAuthToken == "" {
+			t.Fatalf("Expected only a pre-auth token, got %+v", resp)
+		}
+
+		step := func(code string) *httptest.ResponseRecorder {
+			return call(authHandler.LoginTwoFactor, "/api/auth/login/2fa", 0, "", nil,
+				models.TwoFactorLoginRequest{PreAuthToken: resp.PreAuthToken, Code: code})
+		}
+		if rec := step(usedCode); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected an already used code to be rejected, got %d", rec.Code)
+		}
+		if rec := step(recovery.RecoveryCodes[0]); rec.Code != http.StatusOK {
+			t.Fatalf("Expected the recovery code to be accepted, got %d: %s", rec.Code, rec.Body.String())
+		} else {
+			var final models.LoginResponse
+			json.Unmarshal(rec.Body.Bytes(), &final)
+			if final.Token == "" || final.User == nil {
+				t.Errorf("Expected a session token, got %+v", final)
+			}
+		}
+		if rec := step(recovery.RecoveryCodes[0]); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected a used recovery code to be rejected, got %d", rec.Code)
+		}
+
+		sessionToken, _ := authService.GenerateJWT(userID, "anna@example.com", false, false)
+		if rec := call(authHandler.LoginTwoFactor, "/api/auth/login/2fa", 0, "", nil,
+			models.TwoFactorLoginRequest{PreAuthToken: sessionToken, Code: recovery.RecoveryCodes[1]}); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected a session token to be rejected as pre-auth token, got %d", rec.Code)
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		rec := call(handler.GetStatus, "/api/users/me/2fa", userID, "anna@example.com", nil, nil)
+		var status models.TwoFactorStatus
+		json.Unmarshal(rec.Body.Bytes(), &status)
+		if !status.Enabled || status.Required || status.RecoveryCodesRemaining != services.RecoveryCodeCount-1 {
+			t.Errorf("Unexpected status: %+v", status)
+		}
+	})
+
+	t.Run("wrong codes lock the second step", func(t *testing.T) {
+		// Start without the wrong codes of the earlier subtests
+		db.Exec(`UPDATE user_totp SET failed_attempts = 0 WHERE user_id = ?`, userID)
+		resp := login("anna@example.com")
+		step := func(code string) int {
+			return call(authHandler.LoginTwoFactor, "/api/auth/login/2fa", 0, "", nil,
+				models.TwoFactorLoginRequest{PreAuthToken: resp.PreAuthToken, Code: code}).Code
+		}
+		for i := 1; i < services.MaxTwoFactorAttempts; i++ {
+			if code := step("000000"); code != http.StatusUnauthorized {
+				t.Fatalf("Expected wrong code %d to be rejected, got %d", i, code)
+			}
+		}
+		if code := step("000000"); code != http.StatusTooManyRequests {
+			t.Errorf("Expected the last wrong code to lock, got %d", code)
+		}
+		if code := step(recovery.RecoveryCodes[3]); code != http.StatusTooManyRequests {
+			t.Errorf("Expected a valid code to be refused while locked, got %d", code)
+		}
+
+		db.Exec(`UPDATE user_totp SET locked_until = ? WHERE user_id = ?`, time.Now().Add(-time.Minute), userID)
+		if code := step(recovery.RecoveryCodes[3]); code != http.StatusOK {
+			t.Errorf("Expected the code to be accepted after the lock, got %d", code)
+		}
+	})
+
+	t.Run("mandatory for admins", func(t *testing.T) {
+		db.Exec(`UPDATE system_settings SET value = 'true' WHERE key = 'require_2fa_for_admins'`)
+		defer db.Exec(`UPDATE system_settings SET value = 'false' WHERE key = 'require_2fa_for_admins'`)
+
+		resp := login("admin@example.com")
+		if resp.Token != "" || !resp.TwoFactorSetupRequired || resp.PreAuthToken == "" {
+			t.Fatalf("Expected the admin to set up 2FA first, got %+v", resp)
+		}
+
+		rec := call(authHandler.LoginTwoFactorSetup, "/api/auth/login/2fa/setup", 0, "", nil,
+			models.TwoFactorSetupLoginRequest{PreAuthToken: resp.PreAuthToken})
+		var adminSetup models.TwoFactorSetupResponse
+		json.Unmarshal(rec.Body.Bytes(), &adminSetup)
+
+		rec = call(authHandler.LoginTwoFactor, "/api/auth/login/2fa", 0, "", nil,
+			models.TwoFactorLoginRequest{PreAuthToken: resp.PreAuthToken, Code: currentCode(adminSetup.Secret)})
+		var final models.LoginResponse
+		json.Unmarshal(rec.Body.Bytes(), &final)
+		if rec.Code != http.StatusOK || final.Token == "" || len(final.RecoveryCodes) != services.RecoveryCodeCount {
+			t.Fatalf("Expected a session token and recovery codes, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		rec = call(handler.Disable, "/api/users/me/2fa/disable", adminID, "admin@example.com", nil,
+			models.DisableTwoFactorRequest{Password: "Test1234", Code: final.RecoveryCodes[0]})
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected admins not to be able to disable mandatory 2FA, got %d", rec.Code)
+		}
+	})
+
+	t.Run("mandatory for roles with admin permissions", func(t *testing.T) {
+		db.Exec(`UPDATE system_settings SET value = 'true' WHERE key = 'require_2fa_for_admins'`)
+		defer db.Exec(`UPDATE system_settings SET value = 'false' WHERE key = 'require_2fa_for_admins'`)
+
+		walkerID := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+		managerID := testutil.SeedTestUser(t, db, "manager@example.com", "Manager", "green")
+		db.Exec(`UPDATE users SET password_hash = ? WHERE id IN (?, ?)`, hash, walkerID, managerID)
+		roleRepo := repository.NewRoleRepository(db)
+		escort := &models.Role{Name: "Begleitung", Permissions: []models.Permission{models.PermissionEscortWalks}}
+		manager := &models.Role{Name: "Hundeverwaltung", Permissions: []models.Permission{models.PermissionManageDogs}}
+		roleRepo.Create(escort)
+		roleRepo.Create(manager)
+		roleRepo.SetUserRoles(walkerID, []int{escort.ID}, adminID)
+		roleRepo.SetUserRoles(managerID, []int{manager.ID}, adminID)
+
+		if resp := login("walker@example.com"); resp.Token == "" {
+			t.Errorf("Expected no 2FA for a role without admin permissions, got %+v", resp)
+		}
+		if resp := login("manager@example.com"); resp.Token != "" || !resp.TwoFactorSetupRequired {
+			t.Errorf("Expected 2FA for a role with admin permissions, got %+v", resp)
+		}
+	})
+
+	t.Run("wrong codes lock enabling", func(t *testing.T) {
+		lockedID := testutil.SeedTestUser(t, db, "locked@example.com", "Locked", "green")
+		call(handler.Setup, "/api/users/me/2fa/setup", lockedID, "locked@example.com", nil, nil)
+		enable := func(code string) int {
+			return call(handler.Enable, "/api/users/me/2fa/enable", lockedID, "locked@example.com", nil, models.TwoFactorCodeRequest{Code: code}).Code
+		}
+		for i := 1; i < services.MaxTwoFactorAttempts; i++ {
+			if code := enable("000000"); code != http.StatusBadRequest {
+				t.Fatalf("Expected wrong code %d to be rejected, got %d", i, code)
+			}
+		}
+		if code := enable("000000"); code != http.StatusTooManyRequests {
+			t.Errorf("Expected the last wrong code to lock, got %d", code)
+		}
+		if rec := call(handler.Setup, "/api/users/me/2fa/setup", lockedID, "locked@example.com", nil, nil); rec.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected a new setup to be refused while locked, got %d", rec.Code)
+		}
+	})
+
+	t.Run("disable requires password and code", func(t *testing.T) {
+		rec := call(handler.Disable, "/api/users/me/2fa/disable", userID, "anna@example.com", nil,
+			models.DisableTwoFactorRequest{Password: "wrong", Code: recovery.RecoveryCodes[2]})
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected a wrong password to be rejected, got %d", rec.Code)
+		}
+		rec = call(handler.Disable, "/api/users/me/2fa/disable", userID, "anna@example.com", nil,
+			models.DisableTwoFactorRequest{Password: "Test1234", Code: recovery.RecoveryCodes[2]})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected disabling to succeed, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if resp := login("anna@example.com"); resp.Token == "" {
+			t.Errorf("Expected a password-only login after disabling, got %+v", resp)
+		}
+	})
+
+	t.Run("super admin reset", func(t *testing.T) {
+		rec := call(handler.ResetUserTwoFactor, "/api/admin/users/2/2fa", userID, "anna@example.com",
+			map[string]string{"id": fmt.Sprint(adminID)}, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected reset to succeed, got %d: %s", rec.Code, rec.Body.String())
+		}
+
+		if totp, _ := repository.NewTwoFactorRepository(db).FindByUserID(adminID); totp != nil {
+			t.Error("Expected the TOTP secret to be deleted")
+		}
+		page, _ := repository.NewAuditLogRepository(db).Find(&models.AuditLogFilter{Action: models.AuditUser2FAReset})
+		if page.Total != 1 || page.Entries[0].Changes["two_factor_enabled"].Before != true {
+			t.Errorf("Expected the reset to be audited, got %+v", page.Entries)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/services"
+)
+
+// UploadHandler serves uploaded files and checks them against the database
+type UploadHandler struct {
+	storage        services.Storage
+	cleanupService *services.UploadCleanupService
+}
+
+// NewUploadHandler creates a new upload handler
+func NewUploadHandler(db *sql.DB, cfg *config.Config) *UploadHandler {
+	storage := services.NewStorageFromConfig(cfg)
+
+	return &UploadHandler{
+		storage: storage,
+		cleanupService: services.NewUploadCleanupService(
+			repository.NewUploadRepository(db),
+			repository.NewSettingsRepository(db),
+			storage,
+		),
+	}
+}
+
+// ServeUpload serves a file under /uploads/
+// Files in a public bucket are redirected to the bucket, otherwise they are streamed from storage
+func (h *UploadHandler) ServeUpload(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/uploads/")
+
+	if url := h.storage.URL(key); !strings.HasPrefix(url, "/uploads/") {
+		http.Redirect(w, r, url, http.StatusFound)
+		return
+	}
+
+	serveStoredFile(w, r, h.storage, key)
+}
+
+// CheckUploads reports orphaned and missing uploads without changing anything (admin only)
+func (h *UploadHandler) CheckUploads(w http.ResponseWriter, r *http.Request) {
+	report, err := h.cleanupService.Check(h.cleanupService.GracePeriod(), false)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to check uploads")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// CleanupUploads deletes orphaned uploads older than the grace period (admin only)
+// The grace period can be overridden with ?grace_hours=N
+func (h *UploadHandler) CleanupUploads(w http.ResponseWriter, r *http.Request) {
+	grace := h.cleanupService.GracePeriod()
+	if value := r.URL.Query().Get("grace_hours"); value != "" {
+		hours, err := strconv.Atoi(value)
+		if err != nil || hours <= 0 {
+			respondError(w, http.StatusBadRequest, "grace_hours must be a positive integer")
+			return
+		}
+		grace = time.Duration(hours) * time.Hour
+	}
+
+	report, err := h.cleanupService.Check(grace, true)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to clean up uploads")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// serveStoredFile streams a file from storage with support for range and conditional requests
+func serveStoredFile(w http.ResponseWriter, r *http.Request, storage services.Storage, key string) {
+	if key == "" || strings.HasSuffix(key, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	reader, err := storage.Get(key)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to read file")
+		return
+	}
+	defer reader.Close()
+
+	content, ok := reader.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to read file")
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+
+	http.ServeContent(w, r, path.Base(key), time.Time{}, content)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestUploadHandler_ServeUpload tests serving uploads from storage
+func TestUploadHandler_ServeUpload(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{StorageBackend: "local", UploadDir: tempDir}
+	db := testutil.SetupTestDB(t)
+	handler := NewUploadHandler(db, cfg)
+
+	os.MkdirAll(filepath.Join(tempDir, "dogs"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "dogs", "dog_1_full.jpg"), []byte("photo"), 0644)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{"existing file", "/uploads/dogs/dog_1_full.jpg", http.StatusOK, "photo"},
+		{"missing file", "/uploads/dogs/dog_2_full.jpg", http.StatusNotFound, ""},
+		{"directory", "/uploads/dogs/", http.StatusNotFound, ""},
+		{"directory without slash", "/uploads/dogs", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeUpload(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("Expected body %q, got %q", tt.body, rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("content type from extension", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/uploads/dogs/dog_1_full.jpg", nil)
+		rec := httptest.NewRecorder()
+		handler.ServeUpload(rec, req)
+
+		if rec.Header().Get("Content-Type") != "image/jpeg" {
+			t.Errorf("Expected image/jpeg, got %q", rec.Header().Get("Content-Type"))
+		}
+	})
+}
+
+// DONE: TestUploadHandler_CleanupUploads tests the admin upload consistency endpoints
+func TestUploadHandler_CleanupUploads(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{StorageBackend: "local", UploadDir: tempDir}
+	db := testutil.SetupTestDB(t)
+	handler := NewUploadHandler(db, cfg)
+
+	orphan := filepath.Join(tempDir, "dogs", "dog_9_full.jpg")
+	os.MkdirAll(filepath.Dir(orphan), 0755)
+	os.WriteFile(orphan, []byte("photo"), 0644)
+	old := time.Now().Add(-48 * time.Hour)
+	os.Chtimes(orphan, old, old)
+
+	t.Run("check reports without deleting", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.CheckUploads(rec, httptest.NewRequest("GET", "/api/admin/uploads/check", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var report models.UploadCheckReport
+		json.Unmarshal(rec.Body.Bytes(), &report)
+		if len(report.Orphans) != 1 || report.Orphans[0].Path != "dogs/dog_9_full.jpg" {
+			t.Errorf("Expected the orphan in the report, got %+v", report.Orphans)
+		}
+		if _, err := os.Stat(orphan); err != nil {
+			t.Error("Expected check not to delete files")
+		}
+	})
+
+	t.Run("invalid grace period", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.CleanupUploads(rec, httptest.NewRequest("POST", "/api/admin/uploads/cleanup?grace_hours=0", nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("default grace period keeps younger orphans", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.CleanupUploads(rec, httptest.NewRequest("POST", "/api/admin/uploads/cleanup", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		if _, err := os.Stat(orphan); err != nil {
+			t.Error("Expected orphan younger than 72 hours to be kept")
+		}
+	})
+
+	t.Run("cleanup with custom grace period", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.CleanupUploads(rec, httptest.NewRequest("POST", "/api/admin/uploads/cleanup?grace_hours=24", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+
+		var report models.UploadCheckReport
+		json.Unmarshal(rec.Body.Bytes(), &report)
+		if report.DeletedCount != 1 || !report.Orphans[0].Deleted {
+			t.Errorf("Expected the orphan to be deleted, got %+v", report)
+		}
+		if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+			t.Error("Expected orphan file to be deleted")
+		}
+	})
+}
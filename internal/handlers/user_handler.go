@@ -3,9 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
-	"io"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -22,8 +20,14 @@ import (
 // UserHandler handles user-related endpoints
 type UserHandler struct {
 	userRepo     *repository.UserRepository
+	roleRepo     *repository.RoleRepository
+	audit        *services.AuditService
+	sessions     *services.SessionService
 	authService  *services.AuthService
 	emailService *services.EmailService
+	imageService *services.ImageService
+	storage      services.Storage
+	photoSigner  *services.PhotoURLSigner
 	config       *config.Config
 }
 
@@ -33,11 +37,18 @@ func NewUserHandler(db *sql.DB, cfg *config.Config) *UserHandler {
 	if err != nil {
 		println("Warning: Failed to initialize email service:", err.Error())
 	}
+	storage := services.NewStorageFromConfig(cfg)
 
 	return &UserHandler{
 		userRepo:     repository.NewUserRepository(db),
+		roleRepo:     repository.NewRoleRepository(db),
+		audit:        newAuditService(db),
+		sessions:     newSessionService(db, cfg),
 		authService:  services.NewAuthService(cfg.JWTSecret, cfg.JWTExpirationHours),
 		emailService: emailService,
+		imageService: services.NewImageServiceWithStorage(storage),
+		storage:      storage,
+		photoSigner:  services.NewPhotoURLSigner(cfg.JWTSecret, services.ProfilePhotoURLTTL),
 		config:       cfg,
 	}
 }
@@ -50,9 +61,6 @@ func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get admin status from context
-	isAdmin, _ := r.Context().Value(middleware.IsAdminKey).(bool)
-
 	user, err := h.userRepo.FindByID(userID)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Database error")
@@ -68,16 +76,30 @@ func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	user.VerificationToken = nil
 	user.PasswordResetToken = nil
 
-	// Create response with user data + is_admin flag
+	// Permissions from the user's roles, so the frontend can show the admin functions they may use
+	permissions := models.AllPermissions
+	if !user.IsSuperAdmin {
+		permissions, err = h.roleRepo.GetUserPermissions(userID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+	}
+
+	// Create response with user data + is_admin flag, which opens the admin area
+	// to everyone with an admin permission through their roles
 	// Keep user fields at top level for backward compatibility
 	type UserResponse struct {
 		*models.User
-		IsAdmin bool `json:"is_admin"`
+		IsAdmin     bool                `json:"is_admin"`
+		Permissions []models.Permission `json:"permissions"`
 	}
 
+	h.setPhotoURLs(user)
 	response := &UserResponse{
-		User:    user,
-		IsAdmin: isAdmin,
+		User:        user,
+		IsAdmin:     models.HasAdminPermission(permissions),
+		Permissions: permissions,
 	}
 
 	respondJSON(w, http.StatusOK, response)
@@ -174,6 +196,7 @@ func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 	user.PasswordHash = nil
 	user.VerificationToken = nil
 	user.PasswordResetToken = nil
+	h.setPhotoURLs(user)
 
 	message := "Profile updated successfully"
 	if emailChanged {
@@ -214,57 +237,80 @@ func (h *UserHandler) UploadPhoto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create upload directory if it doesn't exist
-	userDir := filepath.Join(h.config.UploadDir, "users")
-	if err := os.MkdirAll(userDir, 0755); err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to create upload directory")
+	user, err := h.userRepo.FindByID(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if user == nil {
+		respondError(w, http.StatusNotFound, "User not found")
 		return
 	}
 
-	// Generate filename
-	filename := filepath.Join("users", filepath.Base(header.Filename))
-	destPath := filepath.Join(h.config.UploadDir, filename)
-
-	// Save file
-	dest, err := os.Create(destPath)
+	// Resize, create thumbnail and strip EXIF like dog photos
+	fullPath, _, err := h.imageService.ProcessUserPhoto(file, userID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to save file")
+		respondError(w, http.StatusBadRequest, "Invalid image file")
 		return
 	}
-	defer dest.Close()
 
-	if _, err := io.Copy(dest, file); err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to save file")
+	oldPhoto := user.ProfilePhoto
+	user.ProfilePhoto = &fullPath
+	if err := h.userRepo.Update(user); err != nil {
+		// If database update fails, clean up the newly created files
+		h.imageService.DeleteUserPhoto(fullPath)
+		respondError(w, http.StatusInternalServerError, "Failed to update profile")
 		return
 	}
 
-	// Update user profile
-	user, err := h.userRepo.FindByID(userID)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Database error")
-		return
+	// Delete old photo if exists (unless the same file was uploaded again)
+	if oldPhoto != nil && *oldPhoto != "" && *oldPhoto != fullPath {
+		h.imageService.DeleteUserPhoto(*oldPhoto)
 	}
-	if user == nil {
-		respondError(w, http.StatusNotFound, "User not found")
+
+	h.setPhotoURLs(user)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":             "Photo uploaded successfully",
+		"photo":               fullPath,
+		"photo_url":           user.ProfilePhotoURL,
+		"photo_thumbnail_url": user.ProfilePhotoThumbnailURL,
+	})
+}
+
+// ServeProfilePhoto serves a profile photo through a signed, expiring URL
+// Profile photos are not available under the public /uploads/ path
+func (h *UserHandler) ServeProfilePhoto(w http.ResponseWriter, r *http.Request) {
+	filename := mux.Vars(r)["file"]
+	if filename == "" || filename != filepath.Base(filename) || strings.HasPrefix(filename, ".") {
+		respondError(w, http.StatusNotFound, "Photo not found")
 		return
 	}
 
-	// Delete old photo if exists
-	if user.ProfilePhoto != nil && *user.ProfilePhoto != "" {
-		oldPath := filepath.Join(h.config.UploadDir, *user.ProfilePhoto)
-		os.Remove(oldPath) // Ignore errors
+	relPath := "users/" + filename
+	query := r.URL.Query()
+	if !h.photoSigner.Verify(relPath, query.Get("expires"), query.Get("sig")) {
+		respondError(w, http.StatusForbidden, "Invalid or expired photo link")
+		return
 	}
 
-	user.ProfilePhoto = &filename
-	if err := h.userRepo.Update(user); err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to update profile")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	serveStoredFile(w, r, h.storage, relPath)
+}
+
+// setPhotoURLs sets the signed URLs of the user's profile photo
+func (h *UserHandler) setPhotoURLs(user *models.User) {
+	if user.ProfilePhoto == nil || *user.ProfilePhoto == "" {
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{
-		"message": "Photo uploaded successfully",
-		"photo":   filename,
-	})
+	fullURL := h.photoSigner.SignedURL(*user.ProfilePhoto)
+	thumbURL := fullURL
+	if strings.HasSuffix(*user.ProfilePhoto, "_full.jpg") {
+		thumbURL = h.photoSigner.SignedURL(strings.TrimSuffix(*user.ProfilePhoto, "_full.jpg") + "_thumb.jpg")
+	}
+
+	user.ProfilePhotoURL = &fullURL
+	user.ProfilePhotoThumbnailURL = &thumbURL
 }
 
 // DeleteAccount deletes the current user's account (GDPR anonymization)
@@ -318,6 +364,11 @@ func (h *UserHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The anonymized account keeps no photo, so remove the files as well
+	if user.ProfilePhoto != nil && *user.ProfilePhoto != "" {
+		h.imageService.DeleteUserPhoto(*user.ProfilePhoto)
+	}
+
 	// Send confirmation email to original email
 	if emailForConfirmation != "" && h.emailService != nil {
 		go h.emailService.SendAccountDeletionConfirmation(emailForConfirmation, user.Name)
@@ -346,6 +397,7 @@ func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 		user.PasswordHash = nil
 		user.VerificationToken = nil
 		user.PasswordResetToken = nil
+		h.setPhotoURLs(user)
 	}
 
 	respondJSON(w, http.StatusOK, users)
@@ -375,6 +427,7 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	user.PasswordHash = nil
 	user.VerificationToken = nil
 	user.PasswordResetToken = nil
+	h.setPhotoURLs(user)
 
 	respondJSON(w, http.StatusOK, user)
 }
@@ -413,12 +466,20 @@ func (h *UserHandler) DeactivateUser(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusNotFound, "User not found")
 		return
 	}
+	if !canManageAccount(r, user) {
+		respondError(w, http.StatusForbidden, "Only admins can deactivate admin accounts")
+		return
+	}
 
 	// Deactivate
 	if err := h.userRepo.Deactivate(userID, req.Reason); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to deactivate user")
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditUserDeactivate, models.AuditTargetUser, &userID,
+		map[string]interface{}{"is_active": user.IsActive, "deactivation_reason": user.DeactivationReason},
+		map[string]interface{}{"is_active": false, "deactivation_reason": req.Reason})
+	h.sessions.RevokeAllQuietly(userID, "deactivation")
 
 	// Send email notification
 	if user.Email != nil && h.emailService != nil {
@@ -454,12 +515,19 @@ func (h *UserHandler) ActivateUser(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusNotFound, "User not found")
 		return
 	}
+	if !canManageAccount(r, user) {
+		respondError(w, http.StatusForbidden, "Only admins can activate admin accounts")
+		return
+	}
 
 	// Activate
 	if err := h.userRepo.Activate(userID); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to activate user")
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditUserActivate, models.AuditTargetUser, &userID,
+		map[string]interface{}{"is_active": user.IsActive, "deactivation_reason": user.DeactivationReason},
+		map[string]interface{}{"is_active": true, "deactivation_reason": nil})
 
 	// Send email notification
 	if user.Email != nil && h.emailService != nil {
@@ -469,6 +537,21 @@ func (h *UserHandler) ActivateUser(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]string{"message": "User activated successfully"})
 }
 
+// canManageAccount checks if the caller may activate or deactivate the account.
+// users.manage is enough for walkers; admin accounts need an admin and the super admin needs
+// the super admin.
+func canManageAccount(r *http.Request, target *models.User) bool {
+	isAdmin, _ := r.Context().Value(middleware.IsAdminKey).(bool)
+	isSuperAdmin, _ := r.Context().Value(middleware.IsSuperAdminKey).(bool)
+	if target.IsSuperAdmin {
+		return isSuperAdmin
+	}
+	if target.IsAdmin {
+		return isAdmin || isSuperAdmin
+	}
+	return true
+}
+
 // PromoteToAdmin promotes a user to admin role (Super Admin only)
 // DONE: Phase 4
 func (h *UserHandler) PromoteToAdmin(w http.ResponseWriter, r *http.Request) {
@@ -516,6 +599,8 @@ func (h *UserHandler) PromoteToAdmin(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to promote user")
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditUserPromote, models.AuditTargetUser, &userID,
+		map[string]interface{}{"is_admin": false}, map[string]interface{}{"is_admin": true})
 
 	// Get updated user
 	updatedUser, err := h.userRepo.FindByID(userID)
@@ -577,6 +662,9 @@ func (h *UserHandler) DemoteAdmin(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to demote admin")
 		return
 	}
+	h.audit.Record(auditActor(r), models.AuditUserDemote, models.AuditTargetUser, &userID,
+		map[string]interface{}{"is_admin": true}, map[string]interface{}{"is_admin": false})
+	h.sessions.RevokeAllQuietly(userID, "demotion")
 
 	// Get updated user
 	updatedUser, err := h.userRepo.FindByID(userID)
@@ -2,15 +2,21 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/middleware"
 	"github.com/tranmh/gassigeher/internal/models"
 	"github.com/tranmh/gassigeher/internal/repository"
 	"github.com/tranmh/gassigeher/internal/services"
@@ -565,6 +571,46 @@ func TestUserHandler_DeactivateUser(t *testing.T) {
 		}
 	})
 
+	t.Run("user manager cannot deactivate admins", func(t *testing.T) {
+		adminID := testutil.SeedTestUser(t, db, "other-admin@example.com", "Other Admin", "green")
+		superAdminID := testutil.SeedTestUser(t, db, "super@example.com", "Super Admin", "green")
+		db.Exec("UPDATE users SET is_admin = 1 WHERE id IN (?, ?)", adminID, superAdminID)
+		db.Exec("UPDATE users SET is_super_admin = 1 WHERE id = ?", superAdminID)
+
+		deactivate := func(targetID int, ctx context.Context) int {
+			body, _ := json.Marshal(map[string]string{"reason": "Test"})
+			req := httptest.NewRequest("PUT", fmt.Sprintf("/api/users/%d/deactivate", targetID), bytes.NewReader(body))
+			req = mux.SetURLVars(req.WithContext(ctx), map[string]string{"id": fmt.Sprintf("%d", targetID)})
+			rec := httptest.NewRecorder()
+			handler.DeactivateUser(rec, req)
+			return rec.Code
+		}
+
+		// A role with users.manage only, as granted by RequirePermission
+		manager := context.WithValue(contextWithUser(context.Background(), 50, "manager@example.com", false),
+			middleware.PermissionsKey, []models.Permission{models.PermissionManageUsers})
+		if code := deactivate(adminID, manager); code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for an admin target, got %d", code)
+		}
+		if code := deactivate(superAdminID, manager); code != http.StatusForbidden {
+			t.Errorf("Expected status 403 for the super admin target, got %d", code)
+		}
+		if code := deactivate(superAdminID, contextWithUser(context.Background(), 1, "admin@example.com", true)); code != http.StatusForbidden {
+			t.Errorf("Expected only the super admin to deactivate the super admin, got %d", code)
+		}
+		if user, _ := userRepo.FindByID(adminID); !user.IsActive {
+			t.Error("Admin should stay active")
+		}
+
+		walkerID := testutil.SeedTestUser(t, db, "walker-managed@example.com", "Walker", "green")
+		if code := deactivate(walkerID, manager); code != http.StatusOK {
+			t.Errorf("Expected the user manager to deactivate a walker, got %d", code)
+		}
+		if code := deactivate(adminID, contextWithUser(context.Background(), 1, "admin@example.com", true)); code != http.StatusOK {
+			t.Errorf("Expected an admin to deactivate an admin, got %d", code)
+		}
+	})
+
 	t.Run("invalid request body", func(t *testing.T) {
 		userID := testutil.SeedTestUser(t, db, "invalid@example.com", "Invalid Body", "green")
 
@@ -671,3 +717,166 @@ func TestUserHandler_ActivateUser(t *testing.T) {
 		}
 	})
 }
+
+// DONE: TestUserHandler_ProfilePhoto tests profile photo upload, signed serving and deletion
+func TestUserHandler_ProfilePhoto(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		JWTSecret:          "test-secret",
+		JWTExpirationHours: 24,
+		UploadDir:          tempDir,
+		MaxUploadSizeMB:    10,
+	}
+	handler := NewUserHandler(db, cfg)
+
+	authService := services.NewAuthService(cfg.JWTSecret, cfg.JWTExpirationHours)
+	hash, _ := authService.HashPassword("Test1234")
+	userRepo := repository.NewUserRepository(db)
+
+	email := "photo@example.com"
+	user := &models.User{
+		Name:            "Photo User",
+		Email:           &email,
+		PasswordHash:    &hash,
+		ExperienceLevel: "green",
+		IsVerified:      true,
+		IsActive:        true,
+		TermsAcceptedAt: time.Now(),
+		LastActivityAt:  time.Now(),
+	}
+	userRepo.Create(user)
+
+	upload := func(width int) *httptest.ResponseRecorder {
+		imageData, err := createTestImageBytes(width, 1200, "jpeg")
+		if err != nil {
+			t.Fatalf("Failed to create test image: %v", err)
+		}
+		body, contentType, err := createMultipartUpload("photo", "me.jpg", imageData)
+		if err != nil {
+			t.Fatalf("Failed to create upload: %v", err)
+		}
+
+		req := httptest.NewRequest("POST", "/api/users/me/photo", body)
+		req.Header.Set("Content-Type", contentType)
+		req = req.WithContext(contextWithUser(req.Context(), user.ID, email, false))
+		rec := httptest.NewRecorder()
+		handler.UploadPhoto(rec, req)
+		return rec
+	}
+
+	serve := func(signedURL string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", signedURL, nil)
+		req = mux.SetURLVars(req, map[string]string{"file": path.Base(req.URL.Path)})
+		rec := httptest.NewRecorder()
+		handler.ServeProfilePhoto(rec, req)
+		return rec
+	}
+
+	var photo, photoURL string
+
+	t.Run("upload processes photo", func(t *testing.T) {
+		rec := upload(1600)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var response map[string]string
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		photo = response["photo"]
+		photoURL = response["photo_url"]
+		if !strings.HasPrefix(photo, "users/user_") || !strings.HasSuffix(photo, "_full.jpg") {
+			t.Errorf("Expected processed profile photo path, got %q", photo)
+		}
+		if !strings.HasPrefix(photoURL, "/api/photos/users/") || !strings.Contains(photoURL, "sig=") {
+			t.Errorf("Expected signed photo URL, got %q", photoURL)
+		}
+		if !strings.Contains(response["photo_thumbnail_url"], "_thumb.jpg") {
+			t.Errorf("Expected signed thumbnail URL, got %q", response["photo_thumbnail_url"])
+		}
+		for _, file := range []string{photo, strings.Replace(photo, "_full.jpg", "_thumb.jpg", 1)} {
+			if _, err := os.Stat(filepath.Join(tempDir, file)); err != nil {
+				t.Errorf("Expected %s to exist: %v", file, err)
+			}
+		}
+	})
+
+	t.Run("replacing photo deletes old files", func(t *testing.T) {
+		oldPhoto := photo
+		rec := upload(1400)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var response map[string]string
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		photo = response["photo"]
+		photoURL = response["photo_url"]
+		if photo == oldPhoto {
+			t.Fatal("Expected a new photo path")
+		}
+		if _, err := os.Stat(filepath.Join(tempDir, oldPhoto)); !os.IsNotExist(err) {
+			t.Error("Expected old photo to be deleted")
+		}
+	})
+
+	t.Run("serve with valid signature", func(t *testing.T) {
+		rec := serve(photoURL)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("Cache-Control") != "private, max-age=3600" {
+			t.Errorf("Expected private caching, got %q", rec.Header().Get("Cache-Control"))
+		}
+	})
+
+	t.Run("serve with invalid signature", func(t *testing.T) {
+		rec := serve(strings.Replace(photoURL, "sig=", "sig=0", 1))
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", rec.Code)
+		}
+
+		rec = serve("/api/photos/" + photo)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403 without signature, got %d", rec.Code)
+		}
+	})
+
+	t.Run("serve with expired signature", func(t *testing.T) {
+		expired := services.NewPhotoURLSigner(cfg.JWTSecret, -time.Minute).SignedURL(photo)
+		rec := serve(expired)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("me includes signed URLs", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/users/me", nil)
+		req = req.WithContext(contextWithUser(req.Context(), user.ID, email, false))
+		rec := httptest.NewRecorder()
+		handler.GetMe(rec, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if url, _ := response["profile_photo_url"].(string); !strings.HasPrefix(url, "/api/photos/users/") {
+			t.Errorf("Expected profile_photo_url, got %v", response["profile_photo_url"])
+		}
+	})
+
+	t.Run("delete account removes photo files", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{"password": "Test1234"})
+		req := httptest.NewRequest("DELETE", "/api/users/me", bytes.NewReader(body))
+		req = req.WithContext(contextWithUser(req.Context(), user.ID, email, false))
+		rec := httptest.NewRecorder()
+		handler.DeleteAccount(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+		for _, file := range []string{photo, strings.Replace(photo, "_full.jpg", "_thumb.jpg", 1)} {
+			if _, err := os.Stat(filepath.Join(tempDir, file)); !os.IsNotExist(err) {
+				t.Errorf("Expected %s to be deleted", file)
+			}
+		}
+	})
+}
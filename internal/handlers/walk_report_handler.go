@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/middleware"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+// WalkReportHandler handles structured walk report HTTP requests
+type WalkReportHandler struct {
+	db          *sql.DB
+	cfg         *config.Config
+	reportRepo  *repository.WalkReportRepository
+	bookingRepo *repository.BookingRepository
+	dogRepo     *repository.DogRepository
+	searchRepo  *repository.DogSearchRepository
+}
+
+// NewWalkReportHandler creates a new walk report handler
+func NewWalkReportHandler(db *sql.DB, cfg *config.Config) *WalkReportHandler {
+	return &WalkReportHandler{
+		db:          db,
+		cfg:         cfg,
+		reportRepo:  repository.NewWalkReportRepository(db),
+		bookingRepo: repository.NewBookingRepository(db),
+		dogRepo:     repository.NewDogRepository(db),
+		searchRepo:  repository.NewDogSearchRepository(db, cfg.DBType),
+	}
+}
+
+// SaveReport files or updates the walk report for one dog of a completed booking
+func (h *WalkReportHandler) SaveReport(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+
+	var req models.WalkReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	booking, err := h.bookingRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	if booking == nil {
+		respondError(w, http.StatusNotFound, "Booking not found")
+		return
+	}
+	if err := h.bookingRepo.LoadGroup(booking); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+
+	// Only walkers of the booking can report on it
+	if !booking.HasParticipant(userID) {
+		respondError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	if booking.Status != "completed" {
+		respondError(w, http.StatusBadRequest, "Can only report on completed bookings")
+		return
+	}
+
+	dogID := booking.DogID
+	if req.DogID != nil {
+		dogID = *req.DogID
+	}
+	onBooking := false
+	for _, bookingDogID := range booking.AllDogIDs() {
+		if bookingDogID == dogID {
+			onBooking = true
+			break
+		}
+	}
+	if !onBooking {
+		respondError(w, http.StatusBadRequest, "Dog was not part of this walk")
+		return
+	}
+
+	report := &models.WalkReport{
+		BookingID:           booking.ID,
+		DogID:               dogID,
+		UserID:              userID,
+		LeashPulling:        req.LeashPulling,
+		DogReactivity:       req.DogReactivity,
+		PeopleReactivity:    req.PeopleReactivity,
+		BathroomPee:         req.BathroomPee,
+		BathroomPoop:        req.BathroomPoop,
+		DistanceKm:          req.DistanceKm,
+		HadIncident:         req.HadIncident,
+		IncidentDescription: req.IncidentDescription,
+		Notes:               req.Notes,
+	}
+	if !report.HadIncident {
+		report.IncidentDescription = nil
+	}
+
+	if err := h.reportRepo.Save(report); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to save walk report")
+		return
+	}
+	indexDogForSearch(h.searchRepo, dogID)
+
+	// Keep user_notes in sync so existing booking views still show the free text
+	if report.Notes != nil && *report.Notes != "" && dogID == booking.DogID {
+		h.bookingRepo.AddNotes(booking.ID, *report.Notes)
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// GetBookingReports returns all walk reports of a booking
+func (h *WalkReportHandler) GetBookingReports(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid booking ID")
+		return
+	}
+
+	userID, _ := r.Context().Value(middleware.UserIDKey).(int)
+	isAdmin := middleware.HasPermission(r.Context(), models.PermissionManageDogs, models.PermissionViewReports)
+
+	booking, err := h.bookingRepo.FindByID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+	if booking == nil {
+		respondError(w, http.StatusNotFound, "Booking not found")
+		return
+	}
+	if err := h.bookingRepo.LoadGroup(booking); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get booking")
+		return
+	}
+
+	if !isAdmin && !booking.HasParticipant(userID) {
+		respondError(w, http.StatusForbidden, "Access denied")
+		return
+	}
+
+	reports, err := h.reportRepo.FindByBookingID(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get walk reports")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, reports)
+}
+
+// GetDogReports returns all walk reports of a dog (admin only)
+func (h *WalkReportHandler) GetDogReports(w http.ResponseWriter, r *http.Request) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return
+	}
+
+	reports, err := h.reportRepo.FindByDogID(dog.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get walk reports")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, reports)
+}
+
+// GetDogReportSummary returns aggregated walk report statistics of a dog (admin only)
+func (h *WalkReportHandler) GetDogReportSummary(w http.ResponseWriter, r *http.Request) {
+	dog, ok := requireDog(w, r, h.dogRepo)
+	if !ok {
+		return
+	}
+
+	summary, err := h.reportRepo.GetDogSummary(dog.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get walk report summary")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, summary)
+}
+
+// requireDog parses the dog ID from the URL and loads the dog; on failure it responds and returns false
+func requireDog(w http.ResponseWriter, r *http.Request, dogRepo *repository.DogRepository) (*models.Dog, bool) {
+	dogID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid dog ID")
+		return nil, false
+	}
+
+	dog, err := dogRepo.FindByID(dogID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get dog")
+		return nil, false
+	}
+	if dog == nil {
+		respondError(w, http.StatusNotFound, "Dog not found")
+		return nil, false
+	}
+
+	return dog, true
+}
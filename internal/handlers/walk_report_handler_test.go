@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestWalkReportHandler_SaveReport tests filing walk reports for completed bookings
+func TestWalkReportHandler_SaveReport(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewWalkReportHandler(db, cfg)
+
+	userID := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+	otherID := testutil.SeedTestUser(t, db, "other@example.com", "Other", "green")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	otherDogID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+	completedID := testutil.SeedTestBooking(t, db, userID, dogID, "2025-11-03", "09:00", "completed")
+	scheduledID := testutil.SeedTestBooking(t, db, userID, dogID, "2099-11-03", "09:00", "scheduled")
+
+	saveReport := func(userID, bookingID int, reqBody map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(reqBody)
+		id := fmt.Sprintf("%d", bookingID)
+		req := httptest.NewRequest("PUT", "/api/bookings/"+id+"/report", bytes.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUser(req.Context(), userID, "walker@example.com", false))
+		rec := httptest.NewRecorder()
+		handler.SaveReport(rec, req)
+		return rec
+	}
+
+	validReport := map[string]interface{}{
+		"leash_pulling":     3,
+		"dog_reactivity":    2,
+		"people_reactivity": 1,
+		"bathroom_pee":      true,
+		"distance_km":       4.2,
+		"notes":             "Schöner Spaziergang",
+	}
+
+	t.Run("walker files report", func(t *testing.T) {
+		rec := saveReport(userID, completedID, validReport)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var report models.WalkReport
+		json.Unmarshal(rec.Body.Bytes(), &report)
+		if report.DogID != dogID {
+			t.Errorf("Expected report for primary dog %d, got %d", dogID, report.DogID)
+		}
+
+		// Free-text notes are mirrored to the booking
+		var notes string
+		db.QueryRow("SELECT user_notes FROM bookings WHERE id = ?", completedID).Scan(&notes)
+		if notes != "Schöner Spaziergang" {
+			t.Errorf("Expected booking notes to be synced, got %q", notes)
+		}
+	})
+
+	t.Run("invalid rating", func(t *testing.T) {
+		rec := saveReport(userID, completedID, map[string]interface{}{"leash_pulling": 9, "dog_reactivity": 1, "people_reactivity": 1})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("other user cannot report", func(t *testing.T) {
+		rec := saveReport(otherID, completedID, validReport)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("booking not completed", func(t *testing.T) {
+		rec := saveReport(userID, scheduledID, validReport)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("dog not part of walk", func(t *testing.T) {
+		withDog := map[string]interface{}{"dog_id": otherDogID}
+		for k, v := range validReport {
+			withDog[k] = v
+		}
+		rec := saveReport(userID, completedID, withDog)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", rec.Code)
+		}
+	})
+}
+
+// DONE: TestWalkReportHandler_GetDogReportSummary tests the per-dog report summary endpoint
+func TestWalkReportHandler_GetDogReportSummary(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := &config.Config{JWTSecret: "test-secret"}
+	handler := NewWalkReportHandler(db, cfg)
+
+	userID := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	bookingID := testutil.SeedTestBooking(t, db, userID, dogID, "2025-11-03", "09:00", "completed")
+	db.Exec(`INSERT INTO walk_reports (booking_id, dog_id, user_id, leash_pulling, dog_reactivity, people_reactivity)
+		VALUES (?, ?, ?, 4, 2, 1)`, bookingID, dogID, userID)
+
+	t.Run("summary for existing dog", func(t *testing.T) {
+		id := fmt.Sprintf("%d", dogID)
+		req := httptest.NewRequest("GET", "/api/dogs/"+id+"/reports/summary", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		handler.GetDogReportSummary(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", rec.Code, rec.Body.String())
+		}
+
+		var summary models.DogReportSummary
+		json.Unmarshal(rec.Body.Bytes(), &summary)
+		if summary.ReportCount != 1 || summary.AvgLeashPulling != 4 {
+			t.Errorf("Unexpected summary: %+v", summary)
+		}
+	})
+
+	t.Run("dog not found", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/dogs/99999/reports/summary", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "99999"})
+		req = req.WithContext(contextWithUser(req.Context(), adminID, "admin@example.com", true))
+		rec := httptest.NewRecorder()
+		handler.GetDogReportSummary(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+}
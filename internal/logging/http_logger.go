@@ -4,8 +4,10 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -142,28 +144,84 @@ func GenerateRequestID() string {
 	return hex.EncodeToString(b)
 }
 
+// trustedProxies are the reverse proxies whose forwarding headers are trusted
+var (
+	trustedProxiesMu sync.RWMutex
+	trustedProxies   []*net.IPNet
+)
+
+// SetTrustedProxies sets the reverse proxies (IPs or CIDRs) whose X-Forwarded-For and X-Real-IP
+// headers GetClientIP trusts. Without trusted proxies, these headers are ignored.
+func SetTrustedProxies(proxies []string) error {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * len(ip.To4())
+			if bits == 0 {
+				bits = 8 * net.IPv6len
+			}
+			proxy = fmt.Sprintf("%s/%d", proxy, bits)
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+
+	trustedProxiesMu.Lock()
+	defer trustedProxiesMu.Unlock()
+	trustedProxies = networks
+	return nil
+}
+
+// isTrustedProxy checks if an address belongs to a trusted proxy
+func isTrustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	trustedProxiesMu.RLock()
+	defer trustedProxiesMu.RUnlock()
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetClientIP extracts the real client IP from request
+// Forwarding headers are only used if the request comes from a trusted proxy, as clients can set them
 func GetClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header (from reverse proxies)
-	xff := r.Header.Get("X-Forwarded-For")
-	if xff != "" {
-		// Take the first IP (original client)
+	ip := r.RemoteAddr
+	// Remove port if present
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !isTrustedProxy(ip) {
+		return ip
+	}
+
+	// Check X-Forwarded-For header; proxies append to it, so the client is the last address
+	// that was not added by a trusted proxy
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
 		parts := strings.Split(xff, ",")
-		return strings.TrimSpace(parts[0])
+		for i := len(parts) - 1; i >= 0; i-- {
+			if addr := strings.TrimSpace(parts[i]); addr != "" && !isTrustedProxy(addr) {
+				return addr
+			}
+		}
 	}
 
 	// Check X-Real-IP header
-	xri := r.Header.Get("X-Real-IP")
-	if xri != "" {
+	if xri := strings.TrimSpace(r.Header.Get("X-Real-IP")); xri != "" {
 		return xri
 	}
-
-	// Fall back to RemoteAddr
-	ip := r.RemoteAddr
-	// Remove port if present
-	if idx := strings.LastIndex(ip, ":"); idx != -1 {
-		ip = ip[:idx]
-	}
 	return ip
 }
 
@@ -4,10 +4,13 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"path"
 	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/tranmh/gassigeher/internal/logging"
+	"github.com/tranmh/gassigeher/internal/models"
 	"github.com/tranmh/gassigeher/internal/services"
 )
 
@@ -18,6 +21,8 @@ const EmailKey contextKey = "email"
 const IsAdminKey contextKey = "isAdmin"
 const IsSuperAdminKey contextKey = "isSuperAdmin" // DONE: Phase 3
 const RequestIDKey contextKey = "requestID"
+const SessionIDKey contextKey = "sessionID"
+const PermissionsKey contextKey = "permissions"
 
 // LoggingMiddleware logs HTTP requests with comprehensive information
 // Includes: timestamp, request ID, client IP, method, path, status code,
@@ -122,8 +127,18 @@ func CORSMiddleware(baseURL string) func(http.Handler) http.Handler {
 
 // DONE: BUG #1 FIXED - CORS now restricted to specific allowed origins
 
-// AuthMiddleware validates JWT tokens
+// TokenValidator validates JWTs and returns their claims
+type TokenValidator interface {
+	ValidateJWT(tokenString string) (*jwt.MapClaims, error)
+}
+
+// AuthMiddleware validates JWT tokens signed with the JWT secret
 func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
+	return AuthMiddlewareWithValidator(services.NewAuthService(jwtSecret, 24)) // expiration not used here
+}
+
+// AuthMiddlewareWithValidator validates JWT tokens with a validator, e.g. an auth service with rotating keys
+func AuthMiddlewareWithValidator(validator TokenValidator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -142,13 +157,18 @@ func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
 			tokenString := parts[1]
 
 			// Validate token
-			authService := services.NewAuthService(jwtSecret, 24) // expiration not used here
-			claims, err := authService.ValidateJWT(tokenString)
+			claims, err := validator.ValidateJWT(tokenString)
 			if err != nil {
 			http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized) // BUG FIX #3
 				return
 			}
 
+			// Pre-auth tokens of the 2FA login step are no session
+			if _, ok := (*claims)["purpose"]; ok {
+				http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+
 			// Extract claims
 			userID, ok := (*claims)["user_id"].(float64)
 			if !ok {
@@ -178,6 +198,9 @@ func AuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
 			ctx = context.WithValue(ctx, EmailKey, email)
 			ctx = context.WithValue(ctx, IsAdminKey, isAdmin)
 			ctx = context.WithValue(ctx, IsSuperAdminKey, isSuperAdmin) // DONE: Phase 3
+			if sessionID, ok := (*claims)["sid"].(float64); ok {
+				ctx = context.WithValue(ctx, SessionIDKey, int(sessionID))
+			}
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -211,6 +234,130 @@ func RequireSuperAdmin(next http.Handler) http.Handler {
 
 // DONE: Phase 3 - Middleware updates complete
 
+// PermissionSource loads the permissions a user has through their roles
+type PermissionSource interface {
+	GetUserPermissions(userID int) ([]models.Permission, error)
+}
+
+// LoadPermissions middleware puts the permissions a user has through their roles into the context,
+// so handlers open to all users can check them with HasPermission. Permissions are loaded on every
+// request, so role changes apply without a new login.
+func LoadPermissions(source PermissionSource) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value(UserIDKey).(int)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			granted, err := source.GetUserPermissions(userID)
+			if err != nil {
+				log.Printf("Error loading permissions of user %d: %v", userID, err)
+				http.Error(w, `{"error":"Failed to check permissions"}`, http.StatusInternalServerError)
+				return
+			}
+			ctx := context.WithValue(r.Context(), PermissionsKey, granted)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequirePermission middleware checks if the user has one of the permissions through their roles
+// It uses the permissions of LoadPermissions, or loads them itself.
+// Super admins have all permissions. The granted permissions are put into the context,
+// so handlers can check the specific permission with HasPermission.
+func RequirePermission(source PermissionSource, permissions ...models.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isSuperAdmin, _ := r.Context().Value(IsSuperAdminKey).(bool); isSuperAdmin {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, ok := r.Context().Value(UserIDKey).(int)
+			if !ok {
+				http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+
+			granted, loaded := r.Context().Value(PermissionsKey).([]models.Permission)
+			if !loaded {
+				var err error
+				granted, err = source.GetUserPermissions(userID)
+				if err != nil {
+					log.Printf("Error loading permissions of user %d: %v", userID, err)
+					http.Error(w, `{"error":"Failed to check permissions"}`, http.StatusInternalServerError)
+					return
+				}
+			}
+
+			for _, permission := range permissions {
+				for _, g := range granted {
+					if g == permission {
+						ctx := context.WithValue(r.Context(), PermissionsKey, granted)
+						next.ServeHTTP(w, r.WithContext(ctx))
+						return
+					}
+				}
+			}
+
+			http.Error(w, `{"error":"Permission required"}`, http.StatusForbidden)
+		})
+	}
+}
+
+// HasPermission checks if the user of the request is an admin or has one of the permissions
+// in the context, as put there by LoadPermissions or RequirePermission.
+func HasPermission(ctx context.Context, permissions ...models.Permission) bool {
+	if isAdmin, _ := ctx.Value(IsAdminKey).(bool); isAdmin {
+		return true
+	}
+	if isSuperAdmin, _ := ctx.Value(IsSuperAdminKey).(bool); isSuperAdmin {
+		return true
+	}
+	granted, _ := ctx.Value(PermissionsKey).([]models.Permission)
+	for _, permission := range permissions {
+		for _, g := range granted {
+			if g == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SessionSource checks whether a login session is still active
+type SessionSource interface {
+	IsActive(sessionID int) (bool, error)
+}
+
+// RequireActiveSession middleware rejects tokens whose login session was revoked (logout, deactivation,
+// demotion, password change) or that belong to no session. It runs after AuthMiddleware
+func RequireActiveSession(source SessionSource) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sessionID, ok := r.Context().Value(SessionIDKey).(int)
+			if !ok {
+				http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+
+			active, err := source.IsActive(sessionID)
+			if err != nil {
+				log.Printf("Error checking session %d: %v", sessionID, err)
+				http.Error(w, `{"error":"Failed to check session"}`, http.StatusInternalServerError)
+				return
+			}
+			if !active {
+				http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // SecurityHeadersMiddleware adds security headers
 func SecurityHeadersMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -232,3 +379,41 @@ func SecurityHeadersMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// privateUploadDirs are the upload directories that are only served through signed URLs
+var privateUploadDirs = []string{"/uploads/users/", "/uploads/incidents/"}
+
+// PrivateUploadsMiddleware hides upload directories that are only served through signed URLs
+// Profile and incident photos are personal data and must not be reachable by guessing a filename
+func PrivateUploadsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cleaned := strings.ToLower(path.Clean(r.URL.Path))
+		for _, dir := range privateUploadDirs {
+			if strings.HasPrefix(cleaned, dir) {
+				http.NotFound(w, r)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UploadCacheMiddleware sets caching headers for uploaded files
+// Content hashed dog photo variants never change and are cached for a year;
+// everything else is revalidated so a replaced file is never shown stale
+func UploadCacheMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(strings.ToLower(r.URL.Path), ".webp") {
+			w.Header().Set("Content-Type", "image/webp")
+		}
+
+		if services.IsContentHashedUpload(r.URL.Path) {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
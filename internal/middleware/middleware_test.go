@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/tranmh/gassigeher/internal/logging"
+	"github.com/tranmh/gassigeher/internal/models"
 	"github.com/tranmh/gassigeher/internal/services"
 )
 
@@ -105,6 +107,20 @@ func TestAuthMiddleware(t *testing.T) {
 		}
 	})
 
+	t.Run("pre-auth token of the 2FA login step", func(t *testing.T) {
+		token, _ := authService.GeneratePreAuthToken(1)
+
+		req := httptest.NewRequest("GET", "/api/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		rec := httptest.NewRecorder()
+		middleware(testHandler).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for pre-auth token, got %d", rec.Code)
+		}
+	})
+
 	t.Run("admin user context", func(t *testing.T) {
 		token, _ := authService.GenerateJWT(1, "admin@example.com", true, false)
 
@@ -176,6 +192,143 @@ func TestRequireAdmin(t *testing.T) {
 	})
 }
 
+// stubPermissions is a permission source with fixed permissions per user
+type stubPermissions map[int][]models.Permission
+
+func (s stubPermissions) GetUserPermissions(userID int) ([]models.Permission, error) {
+	return s[userID], nil
+}
+
+// DONE: TestRequirePermission tests role-based authorization middleware
+func TestRequirePermission(t *testing.T) {
+	source := stubPermissions{
+		1: {models.PermissionManageDogs, models.PermissionManageBlockedDates},
+		2: {models.PermissionViewReports},
+	}
+	middleware := RequirePermission(source, models.PermissionManageDogs, models.PermissionApproveBookings)
+
+	var sawAdmin, sawDogs, sawSettings bool
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawAdmin, _ = r.Context().Value(IsAdminKey).(bool)
+		sawDogs = HasPermission(r.Context(), models.PermissionManageDogs)
+		sawSettings = HasPermission(r.Context(), models.PermissionManageSettings)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := func(ctx context.Context) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/dogs", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		middleware(testHandler).ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("user with permission allowed", func(t *testing.T) {
+		sawAdmin = false
+		rec := request(context.WithValue(context.Background(), UserIDKey, 1))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", rec.Code)
+		}
+		if sawAdmin {
+			t.Error("Handlers behind the permission check should not see the user as admin")
+		}
+		if !sawDogs || sawSettings {
+			t.Errorf("Expected only the granted permissions in the context, got dogs=%v settings=%v", sawDogs, sawSettings)
+		}
+	})
+
+	t.Run("user without permission forbidden", func(t *testing.T) {
+		rec := request(context.WithValue(context.Background(), UserIDKey, 2))
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected status 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("super admin allowed without roles", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), UserIDKey, 3)
+		ctx = context.WithValue(ctx, IsSuperAdminKey, true)
+		if rec := request(ctx); rec.Code != http.StatusOK {
+			t.Errorf("Expected status 200 for super admin, got %d", rec.Code)
+		}
+	})
+
+	t.Run("missing user", func(t *testing.T) {
+		if rec := request(context.Background()); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", rec.Code)
+		}
+	})
+}
+
+// DONE: TestLoadPermissions tests that the permissions of all authenticated requests are in the context
+func TestLoadPermissions(t *testing.T) {
+	source := stubPermissions{1: {models.PermissionApproveBookings}}
+	var sawApprove, sawDogs bool
+	handler := LoadPermissions(source)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sawApprove = HasPermission(r.Context(), models.PermissionApproveBookings)
+		sawDogs = HasPermission(r.Context(), models.PermissionManageDogs, models.PermissionViewReports)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/api/bookings", nil).WithContext(context.WithValue(context.Background(), UserIDKey, 1))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !sawApprove || sawDogs {
+		t.Errorf("Expected only bookings.approve, got status %d approve=%v dogs=%v", rec.Code, sawApprove, sawDogs)
+	}
+
+	// RequirePermission uses the loaded permissions
+	chained := LoadPermissions(source)(RequirePermission(stubPermissions{}, models.PermissionApproveBookings)(handler))
+	rec = httptest.NewRecorder()
+	chained.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+}
+
+// stubSessions is a session source with fixed active sessions
+type stubSessions map[int]bool
+
+func (s stubSessions) IsActive(sessionID int) (bool, error) {
+	return s[sessionID], nil
+}
+
+// DONE: TestRequireActiveSession tests that tokens of revoked sessions are rejected
+func TestRequireActiveSession(t *testing.T) {
+	jwtSecret := "test-secret"
+	authService := services.NewAuthService(jwtSecret, 24)
+	chain := func(next http.Handler) http.Handler {
+		return AuthMiddleware(jwtSecret)(RequireActiveSession(stubSessions{1: true, 2: false})(next))
+	}
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sessionID, _ := r.Context().Value(SessionIDKey).(int); sessionID != 1 {
+			t.Errorf("Expected session ID 1 in context, got %d", sessionID)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		chain(testHandler).ServeHTTP(rec, req)
+		return rec
+	}
+
+	active, _ := authService.GenerateAccessToken(1, "test@example.com", false, false, 1, time.Minute)
+	if rec := request(active); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for an active session, got %d", rec.Code)
+	}
+
+	revoked, _ := authService.GenerateAccessToken(1, "test@example.com", false, false, 2, time.Minute)
+	if rec := request(revoked); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a revoked session, got %d", rec.Code)
+	}
+
+	withoutSession, _ := authService.GenerateJWT(1, "test@example.com", false, false)
+	if rec := request(withoutSession); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a token without session, got %d", rec.Code)
+	}
+}
+
 // DONE: TestCORSMiddleware tests CORS headers middleware
 func TestCORSMiddleware(t *testing.T) {
 	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -296,3 +449,111 @@ func TestLoggingMiddleware(t *testing.T) {
 		}
 	})
 }
+
+// DONE: TestRateLimitClientIP tests that X-Forwarded-For only counts behind a trusted proxy
+func TestRateLimitClientIP(t *testing.T) {
+	limiter := &rateLimiter{requests: make(map[string][]time.Time), limit: 1, window: time.Minute}
+	handler := rateLimit(limiter, "Too many requests", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	request := func(remoteAddr, forwarded string) int {
+		req := httptest.NewRequest("POST", "/api/auth/login", nil)
+		req.RemoteAddr = remoteAddr
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("spoofed header and new ports are the same client", func(t *testing.T) {
+		request("198.51.100.7:40000", "")
+		if code := request("198.51.100.7:40001", "203.0.113.1"); code != http.StatusTooManyRequests {
+			t.Errorf("Expected status 429, got %d", code)
+		}
+	})
+
+	t.Run("clients behind a trusted proxy", func(t *testing.T) {
+		if err := logging.SetTrustedProxies([]string{"10.0.0.0/8"}); err != nil {
+			t.Fatalf("SetTrustedProxies() failed: %v", err)
+		}
+		defer logging.SetTrustedProxies(nil)
+
+		if code := request("10.0.0.2:40000", "192.0.2.10"); code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", code)
+		}
+		// A client-supplied entry before the real client address does not help
+		if code := request("10.0.0.2:40001", "203.0.113.5, 192.0.2.10"); code != http.StatusTooManyRequests {
+			t.Errorf("Expected status 429, got %d", code)
+		}
+		if code := request("10.0.0.2:40002", "192.0.2.11"); code != http.StatusOK {
+			t.Errorf("Expected another client to be allowed, got %d", code)
+		}
+	})
+}
+
+// DONE: TestUploadCacheMiddleware tests caching headers for uploaded files
+func TestUploadCacheMiddleware(t *testing.T) {
+	handler := UploadCacheMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name         string
+		path         string
+		cacheControl string
+		contentType  string
+	}{
+		{"hashed JPEG variant", "/uploads/dogs/dog_5_3f2a9c0d1e4b5a6f7c8d_w640.jpg", "public, max-age=31536000, immutable", ""},
+		{"hashed WebP variant", "/uploads/dogs/dog_5_3f2a9c0d1e4b5a6f7c8d_full.webp", "public, max-age=31536000, immutable", "image/webp"},
+		{"legacy dog photo", "/uploads/dogs/dog_5_full.jpg", "no-cache", ""},
+		{"profile photo", "/uploads/users/user_3.jpg", "no-cache", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Expected Cache-Control %q, got %q", tt.cacheControl, got)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Expected Content-Type %q, got %q", tt.contentType, got)
+			}
+		})
+	}
+}
+
+// DONE: TestPrivateUploadsMiddleware tests that profile and incident photos are not served publicly
+func TestPrivateUploadsMiddleware(t *testing.T) {
+	handler := PrivateUploadsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"dog photo", "/uploads/dogs/dog_5_full.jpg", http.StatusOK},
+		{"profile photo", "/uploads/users/user_3_full.jpg", http.StatusNotFound},
+		{"profile photo with dot segment", "/uploads/dogs/../users/user_3_full.jpg", http.StatusNotFound},
+		{"profile photo with different case", "/uploads/Users/user_3_full.jpg", http.StatusNotFound},
+		{"incident photo", "/uploads/incidents/incident_3_9f86d081884c7d65.jpg", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/tranmh/gassigeher/internal/logging"
 )
 
 // BUG FIX #6: Simple rate limiting for login endpoint
@@ -22,42 +24,76 @@ var loginLimiter = &rateLimiter{
 	window:   1 * time.Minute,   // per minute
 }
 
+// magicLinkLimiter is separate from the login limiter: every request sends an email
+var magicLinkLimiter = &rateLimiter{
+	requests: make(map[string][]time.Time),
+	limit:    3,                 // 3 links
+	window:   10 * time.Minute,  // per 10 minutes
+}
+
+// ssoLimiter limits single sign-on starts; each one stores a pending login
+var ssoLimiter = &rateLimiter{
+	requests: make(map[string][]time.Time),
+	limit:    20,                // 20 logins
+	window:   1 * time.Minute,   // per minute
+}
+
 // RateLimitLogin limits login attempts per IP address
 func RateLimitLogin(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Get client IP
-		ip := r.RemoteAddr
-		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-			ip = forwarded
-		}
+	return rateLimit(loginLimiter, "Zu viele Anmeldeversuche. Bitte versuchen Sie es in einer Minute erneut.", next)
+}
 
-		loginLimiter.mu.Lock()
-		defer loginLimiter.mu.Unlock()
+// RateLimitMagicLink limits requests for login links per IP address
+func RateLimitMagicLink(next http.Handler) http.Handler {
+	return rateLimit(magicLinkLimiter, "Zu viele Anfragen für Login-Links. Bitte versuchen Sie es in 10 Minuten erneut.", next)
+}
 
-		now := time.Now()
+// RateLimitSSO limits single sign-on starts per IP address
+func RateLimitSSO(next http.Handler) http.Handler {
+	return rateLimit(ssoLimiter, "Zu viele Anmeldeversuche. Bitte versuchen Sie es in einer Minute erneut.", next)
+}
 
-		// Clean old requests outside window
-		if requests, exists := loginLimiter.requests[ip]; exists {
-			validRequests := []time.Time{}
-			for _, reqTime := range requests {
-				if now.Sub(reqTime) < loginLimiter.window {
-					validRequests = append(validRequests, reqTime)
-				}
-			}
-			loginLimiter.requests[ip] = validRequests
-		}
+// rateLimit rejects requests of an IP address once the limiter's limit is reached
+func rateLimit(limiter *rateLimiter, message string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Get client IP; X-Forwarded-For only counts behind a trusted proxy
+		ip := logging.GetClientIP(r)
 
-		// Check if limit exceeded
-		if len(loginLimiter.requests[ip]) >= loginLimiter.limit {
-			http.Error(w, `{"error":"Zu viele Anmeldeversuche. Bitte versuchen Sie es in einer Minute erneut."}`, http.StatusTooManyRequests)
+		if !limiter.allow(ip) {
+			http.Error(w, `{"error":"`+message+`"}`, http.StatusTooManyRequests)
 			return
 		}
 
-		// Add current request
-		loginLimiter.requests[ip] = append(loginLimiter.requests[ip], now)
-
 		next.ServeHTTP(w, r)
 	})
 }
 
+// allow records a request of a client and returns false if the limit is exceeded
+func (l *rateLimiter) allow(client string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	// Clean old requests outside window
+	if requests, exists := l.requests[client]; exists {
+		validRequests := []time.Time{}
+		for _, reqTime := range requests {
+			if now.Sub(reqTime) < l.window {
+				validRequests = append(validRequests, reqTime)
+			}
+		}
+		l.requests[client] = validRequests
+	}
+
+	// Check if limit exceeded
+	if len(l.requests[client]) >= l.limit {
+		return false
+	}
+
+	// Add current request
+	l.requests[client] = append(l.requests[client], now)
+	return true
+}
+
 // DONE: BUG #6 FIXED - Rate limiting implemented for login endpoint
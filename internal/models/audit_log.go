@@ -0,0 +1,97 @@
+package models
+
+import "time"
+
+// Audit log actions, named <target type>.<verb>
+const (
+	AuditUserActivate               = "user.activate"
+	AuditUserDeactivate             = "user.deactivate"
+	AuditUserPromote                = "user.promote"
+	AuditUserDemote                 = "user.demote"
+	AuditUserRolesSet               = "user.roles_set"
+	AuditUser2FAReset               = "user.2fa_reset"
+	AuditRoleCreate                 = "role.create"
+	AuditRoleUpdate                 = "role.update"
+	AuditRoleDelete                 = "role.delete"
+	AuditBookingMove                = "booking.move"
+	AuditBookingCancel              = "booking.cancel"
+	AuditBookingApprove             = "booking.approve"
+	AuditBookingReject              = "booking.reject"
+	AuditDogCreate                  = "dog.create"
+	AuditDogUpdate                  = "dog.update"
+	AuditDogDelete                  = "dog.delete"
+	AuditDogStatus                  = "dog.status"
+	AuditDogImport                  = "dog.import"
+	AuditSettingUpdate              = "setting.update"
+	AuditBlockedDateCreate          = "blocked_date.create"
+	AuditBlockedDateDelete          = "blocked_date.delete"
+	AuditExperienceRequestApprove   = "experience_request.approve"
+	AuditExperienceRequestDeny      = "experience_request.deny"
+	AuditReactivationRequestApprove = "reactivation_request.approve"
+	AuditReactivationRequestDeny    = "reactivation_request.deny"
+	AuditIncidentStatus             = "incident.status"
+	AuditJWTKeyRotate               = "jwt_key.rotate"
+)
+
+// Audit log target types
+const (
+	AuditTargetUser                = "user"
+	AuditTargetRole                = "role"
+	AuditTargetBooking             = "booking"
+	AuditTargetDog                 = "dog"
+	AuditTargetSetting             = "setting"
+	AuditTargetBlockedDate         = "blocked_date"
+	AuditTargetExperienceRequest   = "experience_request"
+	AuditTargetReactivationRequest = "reactivation_request"
+	AuditTargetIncident            = "incident"
+	AuditTargetJWTKey              = "jwt_key"
+)
+
+// AuditLogEntry is a recorded admin action
+// Changes holds the fields that differ between the state before and after the action
+type AuditLogEntry struct {
+	ID         int                    `json:"id"`
+	ActorID    *int                   `json:"actor_id,omitempty"`
+	ActorEmail *string                `json:"actor_email,omitempty"`
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"target_type"`
+	TargetID   *int                   `json:"target_id,omitempty"`
+	Changes    map[string]AuditChange `json:"changes,omitempty"`
+	IPAddress  *string                `json:"ip_address,omitempty"`
+	RequestID  *string                `json:"request_id,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// AuditChange is the value of a field before and after an action
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditActor identifies who performed an action and from where
+type AuditActor struct {
+	UserID    *int
+	Email     string
+	IPAddress string
+	RequestID string
+}
+
+// AuditLogFilter filters and pages the audit log
+type AuditLogFilter struct {
+	ActorID    *int
+	Action     string
+	TargetType string
+	TargetID   *int
+	From       *time.Time // inclusive
+	To         *time.Time // exclusive
+	Limit      int        // 0 returns all entries
+	Offset     int
+}
+
+// AuditLogPage is one page of audit log entries
+type AuditLogPage struct {
+	Entries []*AuditLogEntry `json:"entries"`
+	Total   int              `json:"total"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+}
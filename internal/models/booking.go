@@ -19,7 +19,7 @@ type Booking struct {
 
 	// Approval workflow fields
 	RequiresApproval bool       `json:"requires_approval"`
-	ApprovalStatus   string     `json:"approval_status"` // 'pending', 'approved', 'rejected'
+	ApprovalStatus   string     `json:"approval_status"` // 'pending', 'approved', 'rejected', 'awaiting_walkers'
 	ApprovedBy       *int       `json:"approved_by,omitempty"`
 	ApprovedAt       *time.Time `json:"approved_at,omitempty"`
 	RejectionReason  *string    `json:"rejection_reason,omitempty"`
@@ -27,6 +27,72 @@ type Booking struct {
 	// Joined data for responses
 	User *User `json:"user,omitempty"`
 	Dog  *Dog  `json:"dog,omitempty"`
+
+	// Group walk data (co-walkers and dogs besides UserID/DogID)
+	Participants   []*BookingParticipant `json:"participants,omitempty"`
+	AdditionalDogs []*BookingDog         `json:"additional_dogs,omitempty"`
+}
+
+// BookingParticipant represents an additional walker on a group booking
+// The organizer of the walk is always Booking.UserID and is not listed here
+type BookingParticipant struct {
+	ID        int       `json:"id"`
+	BookingID int       `json:"booking_id"`
+	UserID    int       `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Joined data for responses
+	User *User `json:"user,omitempty"`
+}
+
+// BookingDog represents an additional dog on a group booking
+// The primary dog of the walk is always Booking.DogID and is not listed here
+type BookingDog struct {
+	ID        int       `json:"id"`
+	BookingID int       `json:"booking_id"`
+	DogID     int       `json:"dog_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Joined data for responses
+	Dog *Dog `json:"dog,omitempty"`
+}
+
+// IsOpen returns true if the booking is an open group walk still waiting for qualified co-walkers
+func (b *Booking) IsOpen() bool {
+	return b.ApprovalStatus == "awaiting_walkers"
+}
+
+// IsGroupWalk returns true if the booking has co-walkers or additional dogs
+func (b *Booking) IsGroupWalk() bool {
+	return len(b.Participants) > 0 || len(b.AdditionalDogs) > 0
+}
+
+// AllUserIDs returns the organizer followed by all co-walkers
+func (b *Booking) AllUserIDs() []int {
+	ids := []int{b.UserID}
+	for _, p := range b.Participants {
+		ids = append(ids, p.UserID)
+	}
+	return ids
+}
+
+// AllDogIDs returns the primary dog followed by all additional dogs
+func (b *Booking) AllDogIDs() []int {
+	ids := []int{b.DogID}
+	for _, d := range b.AdditionalDogs {
+		ids = append(ids, d.DogID)
+	}
+	return ids
+}
+
+// HasParticipant returns true if the user is the organizer or a co-walker
+func (b *Booking) HasParticipant(userID int) bool {
+	for _, id := range b.AllUserIDs() {
+		if id == userID {
+			return true
+		}
+	}
+	return false
 }
 
 // CreateBookingRequest represents a request to create a booking
@@ -34,6 +100,13 @@ type CreateBookingRequest struct {
 	DogID         int    `json:"dog_id"`
 	Date          string `json:"date"` // YYYY-MM-DD
 	ScheduledTime string `json:"scheduled_time"` // HH:MM
+
+	// Optional group walk fields
+	AdditionalDogIDs []int `json:"additional_dog_ids,omitempty"` // Dogs walked together with DogID
+
+	// If the dogs' walk requirements are not met, create an open walk that waits for
+	// qualified co-walkers instead of rejecting the booking; co-walkers always join themselves
+	OpenForCoWalkers bool `json:"open_for_co_walkers,omitempty"`
 }
 
 // CancelBookingRequest represents a request to cancel a booking
@@ -128,5 +201,17 @@ func (r *CreateBookingRequest) Validate() error {
 		return &ValidationError{Field: "scheduled_time", Message: "Scheduled time must be in HH:MM format"}
 	}
 
+	// Validate group walk dogs (no duplicates, primary dog not repeated)
+	seenDogs := map[int]bool{r.DogID: true}
+	for _, dogID := range r.AdditionalDogIDs {
+		if dogID <= 0 {
+			return &ValidationError{Field: "additional_dog_ids", Message: "Invalid dog ID"}
+		}
+		if seenDogs[dogID] {
+			return &ValidationError{Field: "additional_dog_ids", Message: "Each dog can only be added once"}
+		}
+		seenDogs[dogID] = true
+	}
+
 	return nil
 }
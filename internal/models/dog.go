@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
@@ -26,8 +27,17 @@ type Dog struct {
 	ExternalLink         *string    `json:"external_link,omitempty"`
 	UnavailableReason    *string    `json:"unavailable_reason,omitempty"`
 	UnavailableSince     *time.Time `json:"unavailable_since,omitempty"`
+	MinWalkers           int        `json:"min_walkers"` // walkers needed incl. organizer
+	MinCoWalkerLevel     *string    `json:"min_co_walker_level,omitempty"` // green, blue, orange
+	RequiresStaffEscort  bool       `json:"requires_staff_escort"`
+	Status               DogStatus  `json:"status"`
+	StatusChangedAt      *time.Time `json:"status_changed_at,omitempty"`
+	ExternalID           *string    `json:"external_id,omitempty"` // ID in the shelter's own records, used by the bulk import
 	CreatedAt            time.Time  `json:"created_at"`
 	UpdatedAt            time.Time  `json:"updated_at"`
+
+	// Loaded by handlers for dog responses
+	Tags []*DogTag `json:"tags,omitempty"`
 }
 
 // CreateDogRequest represents the request to create a dog
@@ -45,6 +55,53 @@ type CreateDogRequest struct {
 	DefaultMorningTime  *string `json:"default_morning_time,omitempty"`
 	DefaultEveningTime  *string `json:"default_evening_time,omitempty"`
 	ExternalLink        *string `json:"external_link,omitempty"`
+	MinWalkers          *int    `json:"min_walkers,omitempty"`
+	MinCoWalkerLevel    *string `json:"min_co_walker_level,omitempty"`
+	RequiresStaffEscort *bool   `json:"requires_staff_escort,omitempty"`
+	ExternalID          *string `json:"external_id,omitempty"`
+}
+
+// Validate validates the create dog request
+func (r *CreateDogRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return &ValidationError{Field: "name", Message: "Name is required"}
+	}
+	if strings.TrimSpace(r.Breed) == "" {
+		return &ValidationError{Field: "breed", Message: "Breed is required"}
+	}
+	if r.Size != "small" && r.Size != "medium" && r.Size != "large" {
+		return &ValidationError{Field: "size", Message: "Size must be small, medium, or large"}
+	}
+	if r.Category != "green" && r.Category != "blue" && r.Category != "orange" {
+		return &ValidationError{Field: "category", Message: "Category must be green, blue, or orange"}
+	}
+	if r.Age < 0 || r.Age > 30 {
+		return &ValidationError{Field: "age", Message: "Age must be between 0 and 30"}
+	}
+	if r.WalkDuration != nil && (*r.WalkDuration < 1 || *r.WalkDuration > 480) {
+		return &ValidationError{Field: "walk_duration", Message: "Walk duration must be between 1 and 480 minutes"}
+	}
+	if err := validateDogTime("default_morning_time", r.DefaultMorningTime); err != nil {
+		return err
+	}
+	if err := validateDogTime("default_evening_time", r.DefaultEveningTime); err != nil {
+		return err
+	}
+	if r.ExternalID != nil && len(*r.ExternalID) > 100 {
+		return &ValidationError{Field: "external_id", Message: "External ID must be at most 100 characters"}
+	}
+	return ValidateWalkRequirements(r.MinWalkers, r.MinCoWalkerLevel)
+}
+
+// validateDogTime checks an optional default walk time (HH:MM)
+func validateDogTime(field string, value *string) error {
+	if value == nil || *value == "" {
+		return nil
+	}
+	if _, err := time.Parse("15:04", *value); err != nil {
+		return &ValidationError{Field: field, Message: "Invalid time format (use HH:MM)"}
+	}
+	return nil
 }
 
 // UpdateDogRequest represents the request to update a dog
@@ -62,6 +119,10 @@ type UpdateDogRequest struct {
 	DefaultMorningTime  *string `json:"default_morning_time,omitempty"`
 	DefaultEveningTime  *string `json:"default_evening_time,omitempty"`
 	ExternalLink        *string `json:"external_link,omitempty"`
+	MinWalkers          *int    `json:"min_walkers,omitempty"`
+	MinCoWalkerLevel    *string `json:"min_co_walker_level,omitempty"` // empty string clears the requirement
+	RequiresStaffEscort *bool   `json:"requires_staff_escort,omitempty"`
+	ExternalID          *string `json:"external_id,omitempty"` // empty string clears the external ID
 }
 
 // ToggleAvailabilityRequest represents the request to toggle dog availability
@@ -72,11 +133,37 @@ type ToggleAvailabilityRequest struct {
 
 // DogFilterRequest represents dog filtering parameters
 type DogFilterRequest struct {
-	Breed       *string `json:"breed,omitempty"`
-	Size        *string `json:"size,omitempty"`
-	MinAge      *int    `json:"min_age,omitempty"`
-	MaxAge      *int    `json:"max_age,omitempty"`
-	Category    *string `json:"category,omitempty"`
-	Available   *bool   `json:"available,omitempty"`
-	Search      *string `json:"search,omitempty"` // Search in name, breed
+	Breed         *string     `json:"breed,omitempty"`
+	Size          *string     `json:"size,omitempty"`
+	MinAge        *int        `json:"min_age,omitempty"`
+	MaxAge        *int        `json:"max_age,omitempty"`
+	Category      *string     `json:"category,omitempty"`
+	Available     *bool       `json:"available,omitempty"`
+	Search        *string     `json:"search,omitempty"`          // Simple match on name, breed; GET /api/dogs uses DogSearchRepository
+	TagIDs        []int       `json:"tag_ids,omitempty"`         // Dogs must have all of these tags
+	ExcludeTagIDs []int       `json:"exclude_tag_ids,omitempty"` // Dogs must have none of these tags
+	Statuses      []DogStatus `json:"statuses,omitempty"`        // Lifecycle states; empty means all
+}
+
+// HasWalkRequirements returns true if the dog cannot be walked by a single regular walker
+func (d *Dog) HasWalkRequirements() bool {
+	return d.MinWalkers > 1 || d.RequiresStaffEscort ||
+		(d.MinCoWalkerLevel != nil && *d.MinCoWalkerLevel != "")
+}
+
+// ValidateWalkRequirements checks the walk requirement fields of a create/update request
+func ValidateWalkRequirements(minWalkers *int, minCoWalkerLevel *string) error {
+	if minWalkers != nil && (*minWalkers < 1 || *minWalkers > 10) {
+		return &ValidationError{Field: "min_walkers", Message: "Minimum walkers must be between 1 and 10"}
+	}
+	if minCoWalkerLevel != nil && *minCoWalkerLevel != "" &&
+		*minCoWalkerLevel != "green" && *minCoWalkerLevel != "blue" && *minCoWalkerLevel != "orange" {
+		return &ValidationError{Field: "min_co_walker_level", Message: "Co-walker level must be green, blue, or orange"}
+	}
+	return nil
+}
+
+// SetCompatibleDogsRequest represents the request to set which dogs can be walked together with a dog
+type SetCompatibleDogsRequest struct {
+	DogIDs []int `json:"dog_ids"`
 }
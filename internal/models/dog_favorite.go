@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// DogFavorite represents a dog a user marked as favourite
+// The notify flags subscribe the user to e-mails about the dog
+type DogFavorite struct {
+	ID              int       `json:"id"`
+	UserID          int       `json:"user_id"`
+	DogID           int       `json:"dog_id"`
+	NotifyAvailable bool      `json:"notify_available"`  // Dog becomes available again
+	NotifyFreeSlots bool      `json:"notify_free_slots"` // A booked slot of the dog is cancelled or rejected
+	CreatedAt       time.Time `json:"created_at"`
+
+	// Loaded by the handler when listing favourites
+	Dog *Dog `json:"dog,omitempty"`
+}
+
+// FavoriteNotification is the kind of notification a favourite can subscribe to
+type FavoriteNotification string
+
+const (
+	FavoriteNotifyAvailable FavoriteNotification = "available"
+	FavoriteNotifyFreeSlots FavoriteNotification = "free_slots"
+)
+
+// SetDogFavoriteRequest represents a request to mark a dog as favourite or change its notifications
+type SetDogFavoriteRequest struct {
+	NotifyAvailable bool `json:"notify_available"`
+	NotifyFreeSlots bool `json:"notify_free_slots"`
+}
+
+// NotificationPreferences holds notification settings that are not tied to a favourite dog
+type NotificationPreferences struct {
+	NewDogs bool `json:"new_dogs"` // New dogs of the user's experience level
+}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DogVaccination represents a vaccination of a dog with its expiry date
+type DogVaccination struct {
+	ID             int       `json:"id"`
+	DogID          int       `json:"dog_id"`
+	Name           string    `json:"name"`
+	AdministeredOn *string   `json:"administered_on,omitempty"` // YYYY-MM-DD
+	ExpiresOn      string    `json:"expires_on"`                // YYYY-MM-DD
+	Notes          *string   `json:"notes,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Joined data for expiry alerts
+	DogName string `json:"dog_name,omitempty"`
+}
+
+// DogMedication represents a medication of a dog
+// Walks are not allowed within WalkBufferMinutes of one of the dosage times
+type DogMedication struct {
+	ID                int       `json:"id"`
+	DogID             int       `json:"dog_id"`
+	Name              string    `json:"name"`
+	Dosage            *string   `json:"dosage,omitempty"`
+	DosageTimes       []string  `json:"dosage_times"` // HH:MM
+	WalkBufferMinutes int       `json:"walk_buffer_minutes"`
+	StartDate         *string   `json:"start_date,omitempty"` // YYYY-MM-DD
+	EndDate           *string   `json:"end_date,omitempty"`   // YYYY-MM-DD
+	Notes             *string   `json:"notes,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// DogVetAppointment represents a vet appointment of a dog
+type DogVetAppointment struct {
+	ID              int       `json:"id"`
+	DogID           int       `json:"dog_id"`
+	AppointmentDate string    `json:"appointment_date"`           // YYYY-MM-DD
+	AppointmentTime *string   `json:"appointment_time,omitempty"` // HH:MM
+	VetName         *string   `json:"vet_name,omitempty"`
+	Purpose         string    `json:"purpose"`
+	Notes           *string   `json:"notes,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// DogRestriction represents a walk restriction like "no stairs" or "max 20 min"
+type DogRestriction struct {
+	ID             int       `json:"id"`
+	DogID          int       `json:"dog_id"`
+	Restriction    string    `json:"restriction"`
+	MaxWalkMinutes *int      `json:"max_walk_minutes,omitempty"`
+	ValidFrom      *string   `json:"valid_from,omitempty"`  // YYYY-MM-DD
+	ValidUntil     *string   `json:"valid_until,omitempty"` // YYYY-MM-DD
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// DogHealthRecords bundles all health records of a dog
+type DogHealthRecords struct {
+	DogID           int                  `json:"dog_id"`
+	Vaccinations    []*DogVaccination    `json:"vaccinations"`
+	Medications     []*DogMedication     `json:"medications"`
+	VetAppointments []*DogVetAppointment `json:"vet_appointments"`
+	Restrictions    []*DogRestriction    `json:"restrictions"`
+}
+
+// isInDateRange checks whether date (YYYY-MM-DD) lies within the optional from/until bounds
+func isInDateRange(date string, from, until *string) bool {
+	if from != nil && *from != "" && date < *from {
+		return false
+	}
+	if until != nil && *until != "" && date > *until {
+		return false
+	}
+	return true
+}
+
+// IsActiveOn returns true if the medication is given on the date (YYYY-MM-DD)
+func (m *DogMedication) IsActiveOn(date string) bool {
+	return isInDateRange(date, m.StartDate, m.EndDate)
+}
+
+// ConflictsWith returns the dosage time that is too close to a walk at scheduledTime (HH:MM), or ""
+func (m *DogMedication) ConflictsWith(scheduledTime string) string {
+	if m.WalkBufferMinutes <= 0 {
+		return ""
+	}
+
+	walk, err := time.Parse("15:04", scheduledTime)
+	if err != nil {
+		return ""
+	}
+
+	for _, dosageTime := range m.DosageTimes {
+		dose, err := time.Parse("15:04", dosageTime)
+		if err != nil {
+			continue
+		}
+		diff := walk.Sub(dose)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff < time.Duration(m.WalkBufferMinutes)*time.Minute {
+			return dosageTime
+		}
+	}
+
+	return ""
+}
+
+// IsActiveOn returns true if the restriction applies on the date (YYYY-MM-DD)
+func (r *DogRestriction) IsActiveOn(date string) bool {
+	return isInDateRange(date, r.ValidFrom, r.ValidUntil)
+}
+
+// Describe returns a short German description of the restriction for emails
+func (r *DogRestriction) Describe() string {
+	if r.MaxWalkMinutes != nil {
+		return fmt.Sprintf("%s (max. %d Min.)", r.Restriction, *r.MaxWalkMinutes)
+	}
+	return r.Restriction
+}
+
+// validateDate validates an optional YYYY-MM-DD date
+func validateDate(field string, date *string) error {
+	if date == nil || *date == "" {
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02", *date); err != nil {
+		return &ValidationError{Field: field, Message: "Invalid date format (use YYYY-MM-DD)"}
+	}
+	return nil
+}
+
+// validateDateRange validates two optional dates and that from is not after until
+func validateDateRange(fromField string, from *string, untilField string, until *string) error {
+	if err := validateDate(fromField, from); err != nil {
+		return err
+	}
+	if err := validateDate(untilField, until); err != nil {
+		return err
+	}
+	if from != nil && until != nil && *from != "" && *until != "" && *from > *until {
+		return &ValidationError{Field: untilField, Message: "End date must not be before start date"}
+	}
+	return nil
+}
+
+// DogVaccinationRequest represents a request to create or update a vaccination
+type DogVaccinationRequest struct {
+	Name           string  `json:"name"`
+	AdministeredOn *string `json:"administered_on,omitempty"`
+	ExpiresOn      string  `json:"expires_on"`
+	Notes          *string `json:"notes,omitempty"`
+}
+
+// Validate validates the vaccination request
+func (r *DogVaccinationRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return &ValidationError{Field: "name", Message: "Name is required"}
+	}
+	if r.ExpiresOn == "" {
+		return &ValidationError{Field: "expires_on", Message: "Expiry date is required"}
+	}
+	return validateDateRange("administered_on", r.AdministeredOn, "expires_on", &r.ExpiresOn)
+}
+
+// DogMedicationRequest represents a request to create or update a medication
+type DogMedicationRequest struct {
+	Name              string   `json:"name"`
+	Dosage            *string  `json:"dosage,omitempty"`
+	DosageTimes       []string `json:"dosage_times"`
+	WalkBufferMinutes int      `json:"walk_buffer_minutes"`
+	StartDate         *string  `json:"start_date,omitempty"`
+	EndDate           *string  `json:"end_date,omitempty"`
+	Notes             *string  `json:"notes,omitempty"`
+}
+
+// Validate validates the medication request
+func (r *DogMedicationRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return &ValidationError{Field: "name", Message: "Name is required"}
+	}
+	for _, dosageTime := range r.DosageTimes {
+		if _, err := time.Parse("15:04", dosageTime); err != nil {
+			return &ValidationError{Field: "dosage_times", Message: "Invalid time format (use HH:MM)"}
+		}
+	}
+	if r.WalkBufferMinutes < 0 || r.WalkBufferMinutes > 240 {
+		return &ValidationError{Field: "walk_buffer_minutes", Message: "Walk buffer must be between 0 and 240 minutes"}
+	}
+	if r.WalkBufferMinutes > 0 && len(r.DosageTimes) == 0 {
+		return &ValidationError{Field: "dosage_times", Message: "Dosage times are required for a walk buffer"}
+	}
+	return validateDateRange("start_date", r.StartDate, "end_date", r.EndDate)
+}
+
+// DogVetAppointmentRequest represents a request to create or update a vet appointment
+type DogVetAppointmentRequest struct {
+	AppointmentDate string  `json:"appointment_date"`
+	AppointmentTime *string `json:"appointment_time,omitempty"`
+	VetName         *string `json:"vet_name,omitempty"`
+	Purpose         string  `json:"purpose"`
+	Notes           *string `json:"notes,omitempty"`
+}
+
+// Validate validates the vet appointment request
+func (r *DogVetAppointmentRequest) Validate() error {
+	if r.AppointmentDate == "" {
+		return &ValidationError{Field: "appointment_date", Message: "Date is required"}
+	}
+	if err := validateDate("appointment_date", &r.AppointmentDate); err != nil {
+		return err
+	}
+	if r.AppointmentTime != nil && *r.AppointmentTime != "" {
+		if _, err := time.Parse("15:04", *r.AppointmentTime); err != nil {
+			return &ValidationError{Field: "appointment_time", Message: "Invalid time format (use HH:MM)"}
+		}
+	}
+	if strings.TrimSpace(r.Purpose) == "" {
+		return &ValidationError{Field: "purpose", Message: "Purpose is required"}
+	}
+	return nil
+}
+
+// DogRestrictionRequest represents a request to create or update a walk restriction
+type DogRestrictionRequest struct {
+	Restriction    string  `json:"restriction"`
+	MaxWalkMinutes *int    `json:"max_walk_minutes,omitempty"`
+	ValidFrom      *string `json:"valid_from,omitempty"`
+	ValidUntil     *string `json:"valid_until,omitempty"`
+}
+
+// Validate validates the restriction request
+func (r *DogRestrictionRequest) Validate() error {
+	if strings.TrimSpace(r.Restriction) == "" {
+		return &ValidationError{Field: "restriction", Message: "Restriction is required"}
+	}
+	if len(r.Restriction) > 255 {
+		return &ValidationError{Field: "restriction", Message: "Restriction must be at most 255 characters"}
+	}
+	if r.MaxWalkMinutes != nil && (*r.MaxWalkMinutes < 5 || *r.MaxWalkMinutes > 240) {
+		return &ValidationError{Field: "max_walk_minutes", Message: "Maximum walk duration must be between 5 and 240 minutes"}
+	}
+	return validateDateRange("valid_from", r.ValidFrom, "valid_until", r.ValidUntil)
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+)
+
+// DONE: TestDogMedication_ConflictsWith tests the walk buffer around dosage times
+func TestDogMedication_ConflictsWith(t *testing.T) {
+	medication := &DogMedication{DosageTimes: []string{"08:00", "18:00"}, WalkBufferMinutes: 60}
+
+	tests := []struct {
+		scheduledTime string
+		want          string
+	}{
+		{"08:30", "08:00"},
+		{"07:15", "08:00"},
+		{"09:00", ""},
+		{"17:30", "18:00"},
+		{"12:00", ""},
+	}
+
+	for _, tt := range tests {
+		if got := medication.ConflictsWith(tt.scheduledTime); got != tt.want {
+			t.Errorf("ConflictsWith(%q) = %q, want %q", tt.scheduledTime, got, tt.want)
+		}
+	}
+
+	noBuffer := &DogMedication{DosageTimes: []string{"08:00"}}
+	if got := noBuffer.ConflictsWith("08:00"); got != "" {
+		t.Errorf("Medication without buffer should never conflict, got %q", got)
+	}
+}
+
+// DONE: TestDogRestriction_IsActiveOn tests restriction validity periods
+func TestDogRestriction_IsActiveOn(t *testing.T) {
+	from := "2025-11-01"
+	until := "2025-11-30"
+	minutes := 20
+
+	restriction := &DogRestriction{Restriction: "Keine Treppen", ValidFrom: &from, ValidUntil: &until, MaxWalkMinutes: &minutes}
+
+	if !restriction.IsActiveOn("2025-11-15") {
+		t.Error("Restriction should be active within its period")
+	}
+	if restriction.IsActiveOn("2025-12-01") {
+		t.Error("Restriction should not be active after its period")
+	}
+	if !(&DogRestriction{Restriction: "Maulkorb"}).IsActiveOn("2030-01-01") {
+		t.Error("Restriction without period should always be active")
+	}
+	if got := restriction.Describe(); got != "Keine Treppen (max. 20 Min.)" {
+		t.Errorf("Describe() = %q", got)
+	}
+}
+
+// DONE: TestDogHealthRequests_Validate tests validation of health record requests
+func TestDogHealthRequests_Validate(t *testing.T) {
+	early := "2025-01-01"
+	late := "2025-12-31"
+	badDate := "31.12.2025"
+	tooShort := 1
+
+	tests := []struct {
+		name    string
+		req     interface{ Validate() error }
+		wantErr bool
+	}{
+		{"valid vaccination", &DogVaccinationRequest{Name: "Tollwut", AdministeredOn: &early, ExpiresOn: late}, false},
+		{"vaccination without expiry", &DogVaccinationRequest{Name: "Tollwut"}, true},
+		{"vaccination expires before administered", &DogVaccinationRequest{Name: "Tollwut", AdministeredOn: &late, ExpiresOn: early}, true},
+		{"vaccination bad date", &DogVaccinationRequest{Name: "Tollwut", ExpiresOn: badDate}, true},
+		{"valid medication", &DogMedicationRequest{Name: "Insulin", DosageTimes: []string{"08:00"}, WalkBufferMinutes: 30}, false},
+		{"medication bad time", &DogMedicationRequest{Name: "Insulin", DosageTimes: []string{"8 Uhr"}}, true},
+		{"medication buffer without times", &DogMedicationRequest{Name: "Insulin", WalkBufferMinutes: 30}, true},
+		{"medication end before start", &DogMedicationRequest{Name: "Insulin", StartDate: &late, EndDate: &early}, true},
+		{"valid vet appointment", &DogVetAppointmentRequest{AppointmentDate: early, Purpose: "Kontrolle"}, false},
+		{"vet appointment without purpose", &DogVetAppointmentRequest{AppointmentDate: early}, true},
+		{"valid restriction", &DogRestrictionRequest{Restriction: "Keine Treppen"}, false},
+		{"restriction too short walk", &DogRestrictionRequest{Restriction: "Kurz", MaxWalkMinutes: &tooShort}, true},
+		{"empty restriction", &DogRestrictionRequest{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
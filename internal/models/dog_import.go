@@ -0,0 +1,271 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DogImportColumns are the dog fields of the bulk import, in export order
+var DogImportColumns = []string{
+	"external_id",
+	"name",
+	"breed",
+	"size",
+	"age",
+	"category",
+	"special_needs",
+	"pickup_location",
+	"walk_route",
+	"walk_duration",
+	"special_instructions",
+	"default_morning_time",
+	"default_evening_time",
+	"external_link",
+	"min_walkers",
+	"min_co_walker_level",
+	"requires_staff_escort",
+}
+
+// Actions of an imported row
+const (
+	DogImportActionCreate = "create"
+	DogImportActionUpdate = "update"
+	DogImportActionError  = "error"
+)
+
+// DogImportRow is the outcome of one row of an import file
+type DogImportRow struct {
+	Row        int      `json:"row"` // line in the file, the header is line 1
+	ExternalID *string  `json:"external_id,omitempty"`
+	Name       string   `json:"name"`
+	Action     string   `json:"action"` // create, update or error
+	DogID      *int     `json:"dog_id,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// DogImportResult is the preview or outcome of a dog import
+type DogImportResult struct {
+	DryRun         bool              `json:"dry_run"`
+	Total          int               `json:"total"`
+	Created        int               `json:"created"`
+	Updated        int               `json:"updated"`
+	Failed         int               `json:"failed"`
+	Columns        map[string]string `json:"columns"` // file column -> dog field
+	IgnoredColumns []string          `json:"ignored_columns"`
+	Rows           []*DogImportRow   `json:"rows"`
+	Error          string            `json:"error,omitempty"`
+}
+
+// IsDogImportColumn returns true if field is one of the DogImportColumns
+func IsDogImportColumn(field string) bool {
+	for _, column := range DogImportColumns {
+		if column == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveDogImportColumns maps the header of an import file to dog fields
+// mapping maps file columns to dog fields; an empty field skips the column.
+// Columns without a mapping are used if their name is a dog field (case and spaces are ignored).
+// The result maps column positions to dog fields, ignored lists the unused columns.
+func ResolveDogImportColumns(header []string, mapping map[string]string) (map[int]string, []string, error) {
+	byName := map[string]int{}
+	for i, name := range header {
+		byName[normalizeImportHeader(name)] = i
+	}
+
+	columns := map[int]string{}
+	mapped := map[int]bool{}
+	for column, field := range mapping {
+		i, ok := byName[normalizeImportHeader(column)]
+		if !ok {
+			return nil, nil, fmt.Errorf("column %q is not in the file", column)
+		}
+		if field != "" && !IsDogImportColumn(field) {
+			return nil, nil, fmt.Errorf("unknown dog field %q for column %q", field, column)
+		}
+		mapped[i] = true
+		if field != "" {
+			columns[i] = field
+		}
+	}
+
+	ignored := []string{}
+	for i, name := range header {
+		if mapped[i] {
+			continue
+		}
+		if field := normalizeImportHeader(name); IsDogImportColumn(field) {
+			columns[i] = field
+		} else if strings.TrimSpace(name) != "" {
+			ignored = append(ignored, name)
+		}
+	}
+
+	seen := map[string]bool{}
+	for i := range header {
+		field, ok := columns[i]
+		if !ok {
+			continue
+		}
+		if seen[field] {
+			return nil, nil, fmt.Errorf("several columns are mapped to %q", field)
+		}
+		seen[field] = true
+	}
+
+	return columns, ignored, nil
+}
+
+// ApplyDogImportValues sets the fields of a create request from the cells of an import row
+// values maps dog fields to cell values; empty cells clear optional fields.
+// It returns an error message for each cell that cannot be parsed.
+func ApplyDogImportValues(req *CreateDogRequest, values map[string]string) []string {
+	errors := []string{}
+	for _, field := range DogImportColumns {
+		raw, ok := values[field]
+		if !ok {
+			continue
+		}
+		value := strings.TrimSpace(raw)
+
+		switch field {
+		case "external_id":
+			req.ExternalID = optionalImportString(value)
+		case "name":
+			req.Name = value
+		case "breed":
+			req.Breed = value
+		case "size":
+			req.Size = strings.ToLower(value)
+		case "category":
+			req.Category = strings.ToLower(value)
+		case "special_needs":
+			req.SpecialNeeds = optionalImportString(value)
+		case "pickup_location":
+			req.PickupLocation = optionalImportString(value)
+		case "walk_route":
+			req.WalkRoute = optionalImportString(value)
+		case "special_instructions":
+			req.SpecialInstructions = optionalImportString(value)
+		case "external_link":
+			req.ExternalLink = optionalImportString(value)
+		case "min_co_walker_level":
+			req.MinCoWalkerLevel = optionalImportString(strings.ToLower(value))
+		case "age":
+			age := 0
+			if value != "" {
+				var err error
+				if age, err = strconv.Atoi(value); err != nil {
+					errors = append(errors, "age: Age must be a whole number")
+					continue
+				}
+			}
+			req.Age = age
+		case "walk_duration", "min_walkers":
+			var number *int
+			if value != "" {
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					errors = append(errors, field+": Must be a whole number")
+					continue
+				}
+				number = &n
+			}
+			if field == "walk_duration" {
+				req.WalkDuration = number
+			} else {
+				req.MinWalkers = number
+			}
+		case "default_morning_time", "default_evening_time":
+			walkTime, err := normalizeImportTime(value)
+			if err != nil {
+				errors = append(errors, field+": Invalid time format (use HH:MM)")
+				continue
+			}
+			if field == "default_morning_time" {
+				req.DefaultMorningTime = walkTime
+			} else {
+				req.DefaultEveningTime = walkTime
+			}
+		case "requires_staff_escort":
+			escort, err := ParseImportBool(value)
+			if err != nil {
+				errors = append(errors, "requires_staff_escort: Must be yes or no")
+				continue
+			}
+			req.RequiresStaffEscort = &escort
+		}
+	}
+	return errors
+}
+
+// CreateDogRequestFromDog returns a create request with the importable fields of a dog
+func CreateDogRequestFromDog(dog *Dog) *CreateDogRequest {
+	minWalkers := dog.MinWalkers
+	escort := dog.RequiresStaffEscort
+	return &CreateDogRequest{
+		Name:                dog.Name,
+		Breed:               dog.Breed,
+		Size:                dog.Size,
+		Age:                 dog.Age,
+		Category:            dog.Category,
+		SpecialNeeds:        dog.SpecialNeeds,
+		PickupLocation:      dog.PickupLocation,
+		WalkRoute:           dog.WalkRoute,
+		WalkDuration:        dog.WalkDuration,
+		SpecialInstructions: dog.SpecialInstructions,
+		DefaultMorningTime:  dog.DefaultMorningTime,
+		DefaultEveningTime:  dog.DefaultEveningTime,
+		ExternalLink:        dog.ExternalLink,
+		MinWalkers:          &minWalkers,
+		MinCoWalkerLevel:    dog.MinCoWalkerLevel,
+		RequiresStaffEscort: &escort,
+		ExternalID:          dog.ExternalID,
+	}
+}
+
+// ParseImportBool parses yes/no cells; German and English words, 1/0 and x are accepted
+func ParseImportBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "0", "false", "no", "nein", "n":
+		return false, nil
+	case "1", "true", "yes", "ja", "j", "y", "x":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid yes/no value %q", value)
+}
+
+// normalizeImportHeader turns a column name like "Walk Duration" into walk_duration
+func normalizeImportHeader(name string) string {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+}
+
+// normalizeImportTime turns spreadsheet times like 8:00 or 08:00:00 into HH:MM
+func normalizeImportTime(value string) (*string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			formatted := parsed.Format("15:04")
+			return &formatted, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid time %q", value)
+}
+
+// optionalImportString returns nil for empty cells
+func optionalImportString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"testing"
+)
+
+// DONE: TestCreateDogRequest_Validate tests the rules shared by the dog form and the bulk import
+func TestCreateDogRequest_Validate(t *testing.T) {
+	valid := func() *CreateDogRequest {
+		return &CreateDogRequest{Name: "Bella", Breed: "Labrador", Size: "large", Age: 5, Category: "green"}
+	}
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+
+	tests := []struct {
+		name      string
+		modify    func(r *CreateDogRequest)
+		wantField string
+	}{
+		{"valid", func(r *CreateDogRequest) {}, ""},
+		{"missing name", func(r *CreateDogRequest) { r.Name = " " }, "name"},
+		{"missing breed", func(r *CreateDogRequest) { r.Breed = "" }, "breed"},
+		{"invalid size", func(r *CreateDogRequest) { r.Size = "huge" }, "size"},
+		{"invalid category", func(r *CreateDogRequest) { r.Category = "red" }, "category"},
+		{"negative age", func(r *CreateDogRequest) { r.Age = -1 }, "age"},
+		{"zero walk duration", func(r *CreateDogRequest) { r.WalkDuration = num(0) }, "walk_duration"},
+		{"valid times", func(r *CreateDogRequest) { r.DefaultMorningTime = str("08:30"); r.DefaultEveningTime = str("") }, ""},
+		{"invalid morning time", func(r *CreateDogRequest) { r.DefaultMorningTime = str("8 Uhr") }, "default_morning_time"},
+		{"invalid evening time", func(r *CreateDogRequest) { r.DefaultEveningTime = str("25:00") }, "default_evening_time"},
+		{"invalid min walkers", func(r *CreateDogRequest) { r.MinWalkers = num(0) }, "min_walkers"},
+		{"invalid co-walker level", func(r *CreateDogRequest) { r.MinCoWalkerLevel = str("red") }, "min_co_walker_level"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(req)
+			err := req.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok || validationErr.Field != tt.wantField {
+				t.Errorf("Expected validation error on %s, got %v", tt.wantField, err)
+			}
+		})
+	}
+}
+
+// DONE: TestResolveDogImportColumns tests automatic and explicit column mapping
+func TestResolveDogImportColumns(t *testing.T) {
+	t.Run("matches field names ignoring case and spaces", func(t *testing.T) {
+		columns, ignored, err := ResolveDogImportColumns([]string{"\ufeffName", "Breed", "Walk Duration", "Notizen"}, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if columns[0] != "name" || columns[1] != "breed" || columns[2] != "walk_duration" {
+			t.Errorf("Unexpected columns: %v", columns)
+		}
+		if len(ignored) != 1 || ignored[0] != "Notizen" {
+			t.Errorf("Expected Notizen to be ignored, got %v", ignored)
+		}
+	})
+
+	t.Run("explicit mapping", func(t *testing.T) {
+		columns, ignored, err := ResolveDogImportColumns(
+			[]string{"Tiernummer", "Rufname", "Rasse", "name"},
+			map[string]string{"Tiernummer": "external_id", "Rufname": "name", "Rasse": "breed", "name": ""},
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if columns[0] != "external_id" || columns[1] != "name" || columns[2] != "breed" {
+			t.Errorf("Unexpected columns: %v", columns)
+		}
+		if _, ok := columns[3]; ok || len(ignored) != 0 {
+			t.Errorf("Expected skipped column to be dropped, got %v / %v", columns, ignored)
+		}
+	})
+
+	errorCases := []struct {
+		name    string
+		header  []string
+		mapping map[string]string
+	}{
+		{"unknown column", []string{"name"}, map[string]string{"Rufname": "name"}},
+		{"unknown field", []string{"Rufname"}, map[string]string{"Rufname": "nickname"}},
+		{"field mapped twice", []string{"name", "Rufname"}, map[string]string{"Rufname": "name"}},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ResolveDogImportColumns(tt.header, tt.mapping); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+// DONE: TestApplyDogImportValues tests parsing of import cells
+func TestApplyDogImportValues(t *testing.T) {
+	t.Run("parses all fields", func(t *testing.T) {
+		req := &CreateDogRequest{}
+		errors := ApplyDogImportValues(req, map[string]string{
+			"external_id":           " T-17 ",
+			"name":                  "Bella",
+			"size":                  "Large",
+			"category":              "GREEN",
+			"age":                   "4",
+			"walk_duration":         "45",
+			"default_morning_time":  "8:00",
+			"default_evening_time":  "17:30:00",
+			"min_walkers":           "",
+			"requires_staff_escort": "ja",
+			"special_needs":         "",
+		})
+		if len(errors) != 0 {
+			t.Fatalf("Unexpected errors: %v", errors)
+		}
+		if *req.ExternalID != "T-17" || req.Size != "large" || req.Category != "green" || req.Age != 4 {
+			t.Errorf("Unexpected request: %+v", req)
+		}
+		if *req.WalkDuration != 45 || req.MinWalkers != nil || req.SpecialNeeds != nil {
+			t.Errorf("Unexpected optional fields: %+v", req)
+		}
+		if *req.DefaultMorningTime != "08:00" || *req.DefaultEveningTime != "17:30" {
+			t.Errorf("Expected normalized times, got %s and %s", *req.DefaultMorningTime, *req.DefaultEveningTime)
+		}
+		if !*req.RequiresStaffEscort {
+			t.Error("Expected staff escort")
+		}
+	})
+
+	t.Run("keeps fields without a column", func(t *testing.T) {
+		duration := 30
+		req := &CreateDogRequest{Name: "Bella", WalkDuration: &duration}
+		ApplyDogImportValues(req, map[string]string{"breed": "Labrador"})
+		if req.Name != "Bella" || *req.WalkDuration != 30 || req.Breed != "Labrador" {
+			t.Errorf("Unexpected request: %+v", req)
+		}
+	})
+
+	t.Run("reports every invalid cell", func(t *testing.T) {
+		errors := ApplyDogImportValues(&CreateDogRequest{}, map[string]string{
+			"age":                   "vier",
+			"walk_duration":         "1h",
+			"default_morning_time":  "morgens",
+			"requires_staff_escort": "vielleicht",
+		})
+		if len(errors) != 4 {
+			t.Errorf("Expected 4 errors, got %v", errors)
+		}
+	})
+}
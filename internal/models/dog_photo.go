@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// DogPhoto represents a photo in the gallery of a dog
+// The cover photo is mirrored into Dog.Photo / Dog.PhotoThumbnail
+type DogPhoto struct {
+	ID             int       `json:"id"`
+	DogID          int       `json:"dog_id"`
+	Photo          string    `json:"photo"`
+	PhotoThumbnail *string   `json:"photo_thumbnail,omitempty"`
+	Caption        *string   `json:"caption,omitempty"`
+	SortOrder      int       `json:"sort_order"`
+	IsCover        bool      `json:"is_cover"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Responsive sizes for srcset, empty for photos uploaded before variants existed
+	Variants []ImageVariant `json:"variants,omitempty"`
+}
+
+// ImageVariant is one size of a photo, available as JPEG and WebP
+type ImageVariant struct {
+	Width int    `json:"width"` // Maximum width in pixels
+	JPEG  string `json:"jpeg"`
+	WebP  string `json:"webp"`
+}
+
+// UpdateDogPhotoRequest represents a request to change the caption or make a photo the cover
+type UpdateDogPhotoRequest struct {
+	Caption *string `json:"caption,omitempty"`
+	IsCover *bool   `json:"is_cover,omitempty"`
+}
+
+// Validate validates the update dog photo request
+func (r *UpdateDogPhotoRequest) Validate() error {
+	if r.Caption != nil && len(*r.Caption) > 255 {
+		return &ValidationError{Field: "caption", Message: "Caption must be at most 255 characters"}
+	}
+	if r.IsCover != nil && !*r.IsCover {
+		return &ValidationError{Field: "is_cover", Message: "Choose another photo as cover instead"}
+	}
+	return nil
+}
+
+// ReorderDogPhotosRequest represents a request to change the order of the gallery
+type ReorderDogPhotosRequest struct {
+	PhotoIDs []int `json:"photo_ids"`
+}
+
+// Validate validates the reorder request
+func (r *ReorderDogPhotosRequest) Validate() error {
+	if len(r.PhotoIDs) == 0 {
+		return &ValidationError{Field: "photo_ids", Message: "Photo IDs are required"}
+	}
+	seen := make(map[int]bool, len(r.PhotoIDs))
+	for _, id := range r.PhotoIDs {
+		if seen[id] {
+			return &ValidationError{Field: "photo_ids", Message: "Photo IDs must be unique"}
+		}
+		seen[id] = true
+	}
+	return nil
+}
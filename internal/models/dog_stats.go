@@ -0,0 +1,60 @@
+package models
+
+// DogWalk is a completed walk in the history of a dog
+// Group walks are included, whether the dog was the primary or an additional dog
+type DogWalk struct {
+	BookingID     int       `json:"booking_id"`
+	Date          string    `json:"date"`           // YYYY-MM-DD
+	ScheduledTime string    `json:"scheduled_time"` // HH:MM
+	Walkers       []*Walker `json:"walkers"`        // organizer first, then co-walkers
+	DistanceKm    *float64  `json:"distance_km,omitempty"`
+}
+
+// Walker is a user who took part in a walk
+type Walker struct {
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// DogWalkStats aggregates the walks of a dog so staff can see how often it gets out
+type DogWalkStats struct {
+	DogID             int                 `json:"dog_id"`
+	From              string              `json:"from,omitempty"`
+	To                string              `json:"to,omitempty"`
+	WalkDuration      *int                `json:"walk_duration,omitempty"` // minutes per walk, used for total_minutes
+	TotalWalks        int                 `json:"total_walks"`
+	TotalMinutes      int                 `json:"total_minutes"`
+	TotalDistanceKm   float64             `json:"total_distance_km"`
+	DistinctWalkers   int                 `json:"distinct_walkers"`
+	CancelledWalks    int                 `json:"cancelled_walks"`
+	CancellationRate  float64             `json:"cancellation_rate"` // percentage of cancelled walks
+	LastWalkDate      *string             `json:"last_walk_date,omitempty"`
+	DaysSinceLastWalk *int                `json:"days_since_last_walk,omitempty"`
+	Monthly           []*MonthlyWalkStats `json:"monthly"`
+	TopWalkers        []*WalkerStats      `json:"top_walkers"`
+}
+
+// MonthlyWalkStats holds the walks of a dog in one month (YYYY-MM)
+type MonthlyWalkStats struct {
+	Month   string `json:"month"`
+	Walks   int    `json:"walks"`
+	Minutes int    `json:"minutes"`
+}
+
+// WalkerStats counts the walks of a user with a dog
+type WalkerStats struct {
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+	Walks  int    `json:"walks"`
+}
+
+// NeglectedDog is a bookable dog that has not been walked for a while
+type NeglectedDog struct {
+	DogID             int       `json:"dog_id"`
+	Name              string    `json:"name"`
+	Category          string    `json:"category"`
+	Status            DogStatus `json:"status"`
+	LastWalkDate      *string   `json:"last_walk_date,omitempty"` // empty if the dog was never walked
+	DaysSinceLastWalk int       `json:"days_since_last_walk"`     // since the dog was added if it was never walked
+	NextWalkDate      *string   `json:"next_walk_date,omitempty"`
+}
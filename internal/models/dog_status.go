@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// DogStatus is the lifecycle state of a dog in the shelter
+// It is independent of IsAvailable, which marks a dog as temporarily not walkable (e.g., ill)
+type DogStatus string
+
+const (
+	DogStatusAvailable     DogStatus = "available"      // lives in the shelter, looking for a home
+	DogStatusReserved      DogStatus = "reserved"       // promised to an adopter, still walked in the shelter
+	DogStatusTrialAdoption DogStatus = "trial_adoption" // lives with the adopter on trial, may come back
+	DogStatusAdopted       DogStatus = "adopted"
+	DogStatusDeceased      DogStatus = "deceased"
+	DogStatusArchived      DogStatus = "archived" // kept only for history and statistics
+)
+
+// ActiveDogStatuses are the states of dogs listed by default
+var ActiveDogStatuses = []DogStatus{DogStatusAvailable, DogStatusReserved, DogStatusTrialAdoption}
+
+// IsValid returns true if the status is a known lifecycle state
+func (s DogStatus) IsValid() bool {
+	switch s {
+	case DogStatusAvailable, DogStatusReserved, DogStatusTrialAdoption,
+		DogStatusAdopted, DogStatusDeceased, DogStatusArchived:
+		return true
+	}
+	return false
+}
+
+// IsBookable returns true if walks with a dog in this state can be booked
+func (s DogStatus) IsBookable() bool {
+	return s == DogStatusAvailable || s == DogStatusReserved
+}
+
+// IsActive returns true if dogs in this state are listed by default
+func (s DogStatus) IsActive() bool {
+	for _, active := range ActiveDogStatuses {
+		if s == active {
+			return true
+		}
+	}
+	return false
+}
+
+// CanChangeTo returns an error if a dog cannot move from this state to next
+// A deceased dog can only be archived; every other change is allowed so mistakes can be corrected
+func (s DogStatus) CanChangeTo(next DogStatus) error {
+	if !next.IsValid() {
+		return &ValidationError{Field: "status", Message: "Status must be one of available, reserved, trial_adoption, adopted, deceased, archived"}
+	}
+	if s == next {
+		return &ValidationError{Field: "status", Message: "Dog already has this status"}
+	}
+	if s == DogStatusDeceased && next != DogStatusArchived {
+		return &ValidationError{Field: "status", Message: "A deceased dog can only be archived"}
+	}
+	return nil
+}
+
+// IsBookable returns true if walks with the dog can be booked right now
+func (d *Dog) IsBookable() bool {
+	return d.IsAvailable && d.Status.IsBookable()
+}
+
+// DogStatusChange is an entry of the lifecycle history of a dog
+type DogStatusChange struct {
+	ID                int       `json:"id"`
+	DogID             int       `json:"dog_id"`
+	Status            DogStatus `json:"status"`
+	Note              *string   `json:"note,omitempty"`
+	ChangedBy         *int      `json:"changed_by,omitempty"`
+	ChangedByName     *string   `json:"changed_by_name,omitempty"`
+	CancelledBookings int       `json:"cancelled_bookings"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// SetDogStatusRequest represents the request to change the lifecycle status of a dog
+type SetDogStatusRequest struct {
+	Status DogStatus `json:"status"`
+	Note   *string   `json:"note,omitempty"`
+}
+
+// Validate validates the set dog status request
+func (r *SetDogStatusRequest) Validate() error {
+	if !r.Status.IsValid() {
+		return &ValidationError{Field: "status", Message: "Status must be one of available, reserved, trial_adoption, adopted, deceased, archived"}
+	}
+	if r.Note != nil && len(*r.Note) > 1000 {
+		return &ValidationError{Field: "note", Message: "Note must be at most 1000 characters"}
+	}
+	return nil
+}
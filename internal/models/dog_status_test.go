@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+)
+
+// DONE: TestDogStatus_CanChangeTo tests the allowed lifecycle transitions
+func TestDogStatus_CanChangeTo(t *testing.T) {
+	tests := []struct {
+		from    DogStatus
+		to      DogStatus
+		wantErr bool
+	}{
+		{DogStatusAvailable, DogStatusReserved, false},
+		{DogStatusReserved, DogStatusTrialAdoption, false},
+		{DogStatusTrialAdoption, DogStatusAvailable, false},
+		{DogStatusTrialAdoption, DogStatusAdopted, false},
+		{DogStatusAdopted, DogStatusArchived, false},
+		{DogStatusDeceased, DogStatusArchived, false},
+		{DogStatusDeceased, DogStatusAvailable, true},
+		{DogStatusAvailable, DogStatusAvailable, true},
+		{DogStatusAvailable, DogStatus("lost"), true},
+	}
+
+	for _, tt := range tests {
+		err := tt.from.CanChangeTo(tt.to)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s -> %s: error = %v, wantErr %v", tt.from, tt.to, err, tt.wantErr)
+		}
+	}
+}
+
+// DONE: TestDog_IsBookable tests that only available and reserved dogs in the shelter can be booked
+func TestDog_IsBookable(t *testing.T) {
+	tests := []struct {
+		status      DogStatus
+		isAvailable bool
+		want        bool
+	}{
+		{DogStatusAvailable, true, true},
+		{DogStatusReserved, true, true},
+		{DogStatusAvailable, false, false},
+		{DogStatusTrialAdoption, true, false},
+		{DogStatusAdopted, true, false},
+		{DogStatusDeceased, true, false},
+		{DogStatusArchived, true, false},
+	}
+
+	for _, tt := range tests {
+		dog := &Dog{Status: tt.status, IsAvailable: tt.isAvailable}
+		if got := dog.IsBookable(); got != tt.want {
+			t.Errorf("IsBookable() with status %s, available %v = %v, want %v", tt.status, tt.isAvailable, got, tt.want)
+		}
+	}
+}
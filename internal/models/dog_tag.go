@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// DogTag represents an admin-managed trait of dogs (e.g., "Kinderfreundlich", "Maulkorbpflicht")
+type DogTag struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DogTagRequest represents a request to create or update a tag
+type DogTagRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Validate validates the tag request
+func (r *DogTagRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return &ValidationError{Field: "name", Message: "Name is required"}
+	}
+	if len(r.Name) > 100 {
+		return &ValidationError{Field: "name", Message: "Name must be at most 100 characters"}
+	}
+	if r.Description != nil && len(*r.Description) > 255 {
+		return &ValidationError{Field: "description", Message: "Description must be at most 255 characters"}
+	}
+	return nil
+}
+
+// SetDogTagsRequest represents a request to replace the tags of a dog
+type SetDogTagsRequest struct {
+	TagIDs []int `json:"tag_ids"`
+}
+
+// DogTagFacet is a tag with the number of dogs in the current result that have it
+type DogTagFacet struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// DogFacets summarises a filtered dog list for faceted filtering
+type DogFacets struct {
+	Total int            `json:"total"`
+	Tags  []*DogTagFacet `json:"tags"`
+}
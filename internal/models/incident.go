@@ -0,0 +1,209 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Incident types
+const (
+	IncidentTypeBite   = "bite"
+	IncidentTypeEscape = "escape"
+	IncidentTypeInjury = "injury"
+	IncidentTypeOther  = "other"
+)
+
+// Incident statuses
+const (
+	IncidentStatusOpen      = "open"
+	IncidentStatusInReview  = "in_review"
+	IncidentStatusResolved  = "resolved"
+	IncidentStatusDismissed = "dismissed"
+)
+
+// incidentSeverityRank orders severities so thresholds can be compared
+var incidentSeverityRank = map[string]int{
+	"low":      1,
+	"medium":   2,
+	"high":     3,
+	"critical": 4,
+}
+
+// Incident represents a bite, escape, injury or other incident involving a dog
+type Incident struct {
+	ID           int        `json:"id"`
+	DogID        int        `json:"dog_id"`
+	BookingID    *int       `json:"booking_id,omitempty"`
+	ReportedBy   int        `json:"reported_by"`
+	IncidentType string     `json:"incident_type"` // bite, escape, injury, other
+	Severity     string     `json:"severity"`      // low, medium, high, critical
+	Description  string     `json:"description"`
+	OccurredAt   time.Time  `json:"occurred_at"`
+	Status       string     `json:"status"` // open, in_review, resolved, dismissed
+	Resolution   *string    `json:"resolution,omitempty"`
+	ResolvedBy   *int       `json:"resolved_by,omitempty"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty"`
+	DogAction    *string    `json:"dog_action,omitempty"` // What was changed on the dog because of the incident
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	// Joined data for responses
+	DogName      string             `json:"dog_name,omitempty"`
+	ReporterName string             `json:"reporter_name,omitempty"`
+	Photos       []*IncidentPhoto   `json:"photos,omitempty"`
+	Comments     []*IncidentComment `json:"comments,omitempty"`
+}
+
+// IncidentPhoto represents a photo attached to an incident
+type IncidentPhoto struct {
+	ID         int       `json:"id"`
+	IncidentID int       `json:"incident_id"`
+	Photo      string    `json:"photo"`
+	PhotoURL   string    `json:"photo_url,omitempty"` // Signed, expiring URL (incident photos are not public)
+	UploadedBy int       `json:"uploaded_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IncidentComment represents a review comment on an incident
+type IncidentComment struct {
+	ID         int       `json:"id"`
+	IncidentID int       `json:"incident_id"`
+	UserID     int       `json:"user_id"`
+	Comment    string    `json:"comment"`
+	CreatedAt  time.Time `json:"created_at"`
+	UserName   string    `json:"user_name,omitempty"`
+}
+
+// IsClosed returns true if the incident has been resolved or dismissed
+func (i *Incident) IsClosed() bool {
+	return i.Status == IncidentStatusResolved || i.Status == IncidentStatusDismissed
+}
+
+// IsValidIncidentSeverity checks if a severity level is known
+func IsValidIncidentSeverity(severity string) bool {
+	_, ok := incidentSeverityRank[severity]
+	return ok
+}
+
+// SeverityAtLeast returns true if severity is the same as or worse than threshold
+func SeverityAtLeast(severity, threshold string) bool {
+	rank, ok := incidentSeverityRank[severity]
+	if !ok {
+		return false
+	}
+	minRank, ok := incidentSeverityRank[threshold]
+	if !ok {
+		return false
+	}
+	return rank >= minRank
+}
+
+// IncidentDogAction holds the optional changes to apply to the dog of an incident
+type IncidentDogAction struct {
+	SetDogUnavailable bool    `json:"set_dog_unavailable,omitempty"`
+	NewDogCategory    *string `json:"new_dog_category,omitempty"` // green, blue, orange
+}
+
+// Validate validates the dog action
+func (a *IncidentDogAction) Validate() error {
+	if a.NewDogCategory != nil {
+		category := *a.NewDogCategory
+		if category != "green" && category != "blue" && category != "orange" {
+			return &ValidationError{Field: "new_dog_category", Message: "Category must be green, blue, or orange"}
+		}
+	}
+	return nil
+}
+
+// CreateIncidentRequest represents a request to report an incident
+type CreateIncidentRequest struct {
+	DogID        int     `json:"dog_id"`
+	BookingID    *int    `json:"booking_id,omitempty"`
+	IncidentType string  `json:"incident_type"`
+	Severity     string  `json:"severity"`
+	Description  string  `json:"description"`
+	OccurredAt   *string `json:"occurred_at,omitempty"` // RFC3339, defaults to now
+	IncidentDogAction
+}
+
+// Validate validates the create incident request
+func (r *CreateIncidentRequest) Validate() error {
+	if r.DogID == 0 {
+		return &ValidationError{Field: "dog_id", Message: "Dog ID is required"}
+	}
+
+	switch r.IncidentType {
+	case IncidentTypeBite, IncidentTypeEscape, IncidentTypeInjury, IncidentTypeOther:
+	default:
+		return &ValidationError{Field: "incident_type", Message: "Type must be bite, escape, injury, or other"}
+	}
+
+	if !IsValidIncidentSeverity(r.Severity) {
+		return &ValidationError{Field: "severity", Message: "Severity must be low, medium, high, or critical"}
+	}
+
+	if strings.TrimSpace(r.Description) == "" {
+		return &ValidationError{Field: "description", Message: "Please describe the incident"}
+	}
+	if len(r.Description) > 5000 {
+		return &ValidationError{Field: "description", Message: "Description must be at most 5000 characters"}
+	}
+
+	if r.OccurredAt != nil {
+		occurredAt, err := time.Parse(time.RFC3339, *r.OccurredAt)
+		if err != nil {
+			return &ValidationError{Field: "occurred_at", Message: "Invalid date format (use RFC3339)"}
+		}
+		if occurredAt.After(time.Now().Add(5 * time.Minute)) {
+			return &ValidationError{Field: "occurred_at", Message: "Incident cannot be in the future"}
+		}
+	}
+
+	return r.IncidentDogAction.Validate()
+}
+
+// UpdateIncidentStatusRequest represents an admin review decision on an incident
+type UpdateIncidentStatusRequest struct {
+	Status     string  `json:"status"`
+	Resolution *string `json:"resolution,omitempty"`
+	IncidentDogAction
+}
+
+// Validate validates the status update request
+func (r *UpdateIncidentStatusRequest) Validate() error {
+	switch r.Status {
+	case IncidentStatusOpen, IncidentStatusInReview:
+	case IncidentStatusResolved, IncidentStatusDismissed:
+		if r.Resolution == nil || strings.TrimSpace(*r.Resolution) == "" {
+			return &ValidationError{Field: "resolution", Message: "Resolution is required when closing an incident"}
+		}
+	default:
+		return &ValidationError{Field: "status", Message: "Status must be open, in_review, resolved, or dismissed"}
+	}
+
+	return r.IncidentDogAction.Validate()
+}
+
+// AddIncidentCommentRequest represents a request to comment on an incident
+type AddIncidentCommentRequest struct {
+	Comment string `json:"comment"`
+}
+
+// Validate validates the comment request
+func (r *AddIncidentCommentRequest) Validate() error {
+	if strings.TrimSpace(r.Comment) == "" {
+		return &ValidationError{Field: "comment", Message: "Comment is required"}
+	}
+	if len(r.Comment) > 2000 {
+		return &ValidationError{Field: "comment", Message: "Comment must be at most 2000 characters"}
+	}
+	return nil
+}
+
+// IncidentFilterRequest represents filters for listing incidents
+type IncidentFilterRequest struct {
+	DogID      *int    `json:"dog_id,omitempty"`
+	ReportedBy *int    `json:"reported_by,omitempty"`
+	Status     *string `json:"status,omitempty"`
+	Severity   *string `json:"severity,omitempty"`
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// DONE: TestCreateIncidentRequest_Validate tests validation for incident reports
+func TestCreateIncidentRequest_Validate(t *testing.T) {
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	future := time.Now().Add(24 * time.Hour).Format(time.RFC3339)
+	invalidDate := "gestern"
+	purple := "purple"
+
+	tests := []struct {
+		name    string
+		req     CreateIncidentRequest
+		wantErr bool
+	}{
+		{
+			name:    "valid incident",
+			req:     CreateIncidentRequest{DogID: 1, IncidentType: "bite", Severity: "high", Description: "Hat einen Hund gebissen", OccurredAt: &past},
+			wantErr: false,
+		},
+		{
+			name:    "missing dog",
+			req:     CreateIncidentRequest{IncidentType: "bite", Severity: "high", Description: "Biss"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			req:     CreateIncidentRequest{DogID: 1, IncidentType: "bark", Severity: "low", Description: "Gebellt"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown severity",
+			req:     CreateIncidentRequest{DogID: 1, IncidentType: "escape", Severity: "extreme", Description: "Entlaufen"},
+			wantErr: true,
+		},
+		{
+			name:    "empty description",
+			req:     CreateIncidentRequest{DogID: 1, IncidentType: "injury", Severity: "low", Description: "   "},
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			req:     CreateIncidentRequest{DogID: 1, IncidentType: "other", Severity: "low", Description: "Test", OccurredAt: &invalidDate},
+			wantErr: true,
+		},
+		{
+			name:    "date in the future",
+			req:     CreateIncidentRequest{DogID: 1, IncidentType: "other", Severity: "low", Description: "Test", OccurredAt: &future},
+			wantErr: true,
+		},
+		{
+			name:    "invalid new category",
+			req:     CreateIncidentRequest{DogID: 1, IncidentType: "bite", Severity: "low", Description: "Test", IncidentDogAction: IncidentDogAction{NewDogCategory: &purple}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// DONE: TestUpdateIncidentStatusRequest_Validate tests validation for incident reviews
+func TestUpdateIncidentStatusRequest_Validate(t *testing.T) {
+	resolution := "Training vereinbart"
+
+	tests := []struct {
+		name    string
+		req     UpdateIncidentStatusRequest
+		wantErr bool
+	}{
+		{name: "in review", req: UpdateIncidentStatusRequest{Status: "in_review"}, wantErr: false},
+		{name: "resolved with resolution", req: UpdateIncidentStatusRequest{Status: "resolved", Resolution: &resolution}, wantErr: false},
+		{name: "resolved without resolution", req: UpdateIncidentStatusRequest{Status: "resolved"}, wantErr: true},
+		{name: "dismissed without resolution", req: UpdateIncidentStatusRequest{Status: "dismissed"}, wantErr: true},
+		{name: "unknown status", req: UpdateIncidentStatusRequest{Status: "closed"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// DONE: TestSeverityAtLeast tests severity threshold comparison
+func TestSeverityAtLeast(t *testing.T) {
+	tests := []struct {
+		severity  string
+		threshold string
+		want      bool
+	}{
+		{"critical", "critical", true},
+		{"high", "critical", false},
+		{"critical", "high", true},
+		{"low", "low", true},
+		{"high", "none", false},
+		{"unknown", "low", false},
+	}
+
+	for _, tt := range tests {
+		if got := SeverityAtLeast(tt.severity, tt.threshold); got != tt.want {
+			t.Errorf("SeverityAtLeast(%q, %q) = %v, want %v", tt.severity, tt.threshold, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// JWT signing algorithms
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmEdDSA = "EdDSA"
+	JWTAlgorithmRS256 = "RS256"
+)
+
+// JWTSigningKey is a key that signs or verifies JWTs, identified by the kid header of the tokens
+type JWTSigningKey struct {
+	Kid           string     `json:"kid"`
+	Algorithm     string     `json:"algorithm"`
+	PrivateKey    *string    `json:"-"` // PEM, or the base64 secret for HS256; nil for keys from a file
+	PublicKey     *string    `json:"-"` // PEM, asymmetric keys only
+	CreatedAt     time.Time  `json:"created_at"`
+	RetiredAt     *time.Time `json:"retired_at,omitempty"`
+	Active        bool       `json:"active"`                   // signs new tokens
+	FromFile      bool       `json:"from_file"`                // private key is read from JWT_PRIVATE_KEY_FILE
+	Unreadable    bool       `json:"unreadable,omitempty"`     // private key cannot be decrypted with JWT_KEY_ENCRYPTION_KEY
+	VerifiesUntil *time.Time `json:"verifies_until,omitempty"` // end of the grace period of a retired key
+}
+
+// JWKS is a JSON Web Key Set with the public keys that verify tokens
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv,omitempty"` // OKP keys
+	X   string `json:"x,omitempty"`   // OKP keys
+	N   string `json:"n,omitempty"`   // RSA keys
+	E   string `json:"e,omitempty"`   // RSA keys
+}
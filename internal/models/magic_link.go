@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// MagicLinkToken is a single-use login link sent by email
+type MagicLinkToken struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	TokenHash  string     `json:"-"`
+	DeviceHash string     `json:"-"` // hash of the device token of the browser that requested the link
+	IPAddress  *string    `json:"ip_address,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	UsedAt     *time.Time `json:"used_at,omitempty"`
+}
+
+// MagicLinkRequest requests a login link for an email address
+type MagicLinkRequest struct {
+	Email string `json:"email"`
+}
+
+// MagicLinkResponse is returned for every request, whether an account exists or not
+// The browser keeps the device token; the link only works together with it
+type MagicLinkResponse struct {
+	Message     string `json:"message"`
+	DeviceToken string `json:"device_token"`
+	ExpiresIn   int    `json:"expires_in"` // seconds
+}
+
+// MagicLinkLoginRequest logs in with the token of a link
+type MagicLinkLoginRequest struct {
+	Token       string `json:"token"`
+	DeviceToken string `json:"device_token"`
+}
+
+// AuthMethods lists the login methods of this instance for the login page
+type AuthMethods struct {
+	Password     bool          `json:"password"`
+	MagicLink    bool          `json:"magic_link"`
+	SSOProviders []SSOProvider `json:"sso_providers"`
+}
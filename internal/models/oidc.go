@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// UserIdentity links a user to an account at an OpenID Connect provider
+type UserIdentity struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"-"` // the provider's stable user ID ("sub" claim)
+	Email       *string    `json:"email,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// OIDCLogin is a single sign-on login in progress
+// Until the callback it holds the state, nonce and PKCE verifier; after it,
+// the user and the hash of the single-use code the browser exchanges for a session
+type OIDCLogin struct {
+	ID            int
+	StateHash     string
+	Provider      string
+	Nonce         string
+	CodeVerifier  string
+	RedirectPath  *string
+	UserID        *int
+	LoginCodeHash *string
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+	CallbackAt    *time.Time
+	UsedAt        *time.Time
+}
+
+// SSOProvider is a single sign-on provider shown on the login page
+type SSOProvider struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	LoginURL string `json:"login_url"`
+}
+
+// SSOLoginRequest exchanges the single-use code of a finished SSO login for a session
+type SSOLoginRequest struct {
+	Code string `json:"code"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// PublicDog is the public-safe view of an adoptable dog for the website feed and widget
+// Walk details, health data and internal notes are never part of it
+type PublicDog struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Breed        string    `json:"breed"`
+	Size         string    `json:"size"` // small, medium, large
+	Age          int       `json:"age"`
+	PhotoURL     *string   `json:"photo_url,omitempty"`
+	ThumbnailURL *string   `json:"thumbnail_url,omitempty"`
+	URL          string    `json:"url"` // dog page in Gassigeher, or the external link if requested
+	IsFeatured   bool      `json:"is_featured"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// PublicDogFeed is the public feed of adoptable dogs
+type PublicDogFeed struct {
+	Title     string       `json:"title"`
+	URL       string       `json:"url"`
+	UpdatedAt *time.Time   `json:"updated_at,omitempty"` // latest change of a listed dog
+	Dogs      []*PublicDog `json:"dogs"`
+}
+
+// SizeLabel returns the German label of the dog size for public pages
+func (d *PublicDog) SizeLabel() string {
+	switch d.Size {
+	case "small":
+		return "klein"
+	case "medium":
+		return "mittel"
+	case "large":
+		return "groß"
+	}
+	return d.Size
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Permission is a right to use a group of admin functions
+type Permission string
+
+const (
+	PermissionManageDogs                Permission = "dogs.manage"                 // dogs, photos, tags, health records, import and export
+	PermissionManageBlockedDates        Permission = "blocked_dates.manage"        // blocked dates and holidays
+	PermissionApproveBookings           Permission = "bookings.approve"            // pending approvals and moving bookings
+	PermissionApproveExperienceRequests Permission = "experience_requests.approve" // experience level requests
+	PermissionManageUsers               Permission = "users.manage"                // users and reactivation requests
+	PermissionManageIncidents           Permission = "incidents.manage"            // incident review
+	PermissionManageSettings            Permission = "settings.manage"             // settings, booking times and uploads
+	PermissionViewReports               Permission = "reports.view"                // dashboard, statistics and walk reports
+	PermissionViewAudit                 Permission = "audit.view"                  // audit log of admin actions
+	PermissionEscortWalks               Permission = "walks.escort"                // counts as staff escort for dogs that require one
+)
+
+// AllPermissions lists every permission, in the order they are shown to admins
+var AllPermissions = []Permission{
+	PermissionManageDogs,
+	PermissionManageBlockedDates,
+	PermissionApproveBookings,
+	PermissionApproveExperienceRequests,
+	PermissionManageUsers,
+	PermissionManageIncidents,
+	PermissionManageSettings,
+	PermissionViewReports,
+	PermissionViewAudit,
+	PermissionEscortWalks,
+}
+
+// IsValid returns true if the permission is known
+func (p Permission) IsValid() bool {
+	for _, known := range AllPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdminScoped returns true for permissions to admin functions; walks.escort is a right of walkers
+func (p Permission) IsAdminScoped() bool {
+	return p.IsValid() && p != PermissionEscortWalks
+}
+
+// HasAdminPermission returns true if any of the permissions is admin-scoped
+func HasAdminPermission(permissions []Permission) bool {
+	for _, p := range permissions {
+		if p.IsAdminScoped() {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionsLost returns true if any of the permissions before is missing after a change
+func PermissionsLost(before, after []Permission) bool {
+	kept := map[Permission]bool{}
+	for _, p := range after {
+		kept[p] = true
+	}
+	for _, p := range before {
+		if !kept[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleAdmin is the name of the system role with all permissions; it is kept in sync with users.is_admin
+const RoleAdmin = "admin"
+
+// Role is a named set of permissions assigned to users
+type Role struct {
+	ID          int          `json:"id"`
+	Name        string       `json:"name"`
+	Description *string      `json:"description,omitempty"`
+	IsSystem    bool         `json:"is_system"` // system roles come with the installation and cannot be deleted
+	Permissions []Permission `json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// PermissionInfo describes a permission for the role editor
+type PermissionInfo struct {
+	Name        Permission `json:"name"`
+	Description string     `json:"description"`
+}
+
+// RoleRequest represents a request to create or update a role
+type RoleRequest struct {
+	Name        string       `json:"name"`
+	Description *string      `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Validate validates the role request and removes duplicate permissions
+func (r *RoleRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return &ValidationError{Field: "name", Message: "Name is required"}
+	}
+	if len(r.Name) > 100 {
+		return &ValidationError{Field: "name", Message: "Name must be at most 100 characters"}
+	}
+	if r.Description != nil && len(*r.Description) > 255 {
+		return &ValidationError{Field: "description", Message: "Description must be at most 255 characters"}
+	}
+
+	permissions := []Permission{}
+	seen := map[Permission]bool{}
+	for _, permission := range r.Permissions {
+		if !permission.IsValid() {
+			return &ValidationError{Field: "permissions", Message: fmt.Sprintf("Unknown permission: %s", permission)}
+		}
+		if !seen[permission] {
+			seen[permission] = true
+			permissions = append(permissions, permission)
+		}
+	}
+	r.Permissions = permissions
+	return nil
+}
+
+// SetUserRolesRequest represents a request to replace the roles of a user
+type SetUserRolesRequest struct {
+	RoleIDs []int `json:"role_ids"`
+}
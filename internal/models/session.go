@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// UserSession is a login on one device; its refresh token is rotated on every use
+type UserSession struct {
+	ID                int        `json:"id"`
+	UserID            int        `json:"user_id"`
+	RefreshTokenHash  string     `json:"-"`
+	PreviousTokenHash *string    `json:"-"`
+	UserAgent         *string    `json:"user_agent,omitempty"`
+	IPAddress         *string    `json:"ip_address,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	LastUsedAt        time.Time  `json:"last_used_at"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+	Current           bool       `json:"current"` // the session of the request
+}
+
+// IsActive returns true if the session is neither revoked nor expired
+func (s *UserSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// SessionTokens are the tokens issued at login and on refresh
+type SessionTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int // lifetime of the access token in seconds
+	SessionID    int
+}
+
+// RefreshTokenRequest represents the payload to refresh or end a session
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
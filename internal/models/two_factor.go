@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// UserTOTP is the TOTP secret of a user; it is pending until the first code is confirmed
+type UserTOTP struct {
+	UserID         int        `json:"user_id"`
+	Secret         string     `json:"-"`
+	Enabled        bool       `json:"enabled"`
+	EnabledAt      *time.Time `json:"enabled_at,omitempty"`
+	LastUsedStep   int64      `json:"-"` // time step of the last accepted code, so a code cannot be used twice
+	FailedAttempts int        `json:"-"` // wrong codes since the last accepted one or lock
+	LockedUntil    *time.Time `json:"-"` // no codes are accepted until then after too many wrong ones
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// TwoFactorStatus is the 2FA state of the current user
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	Required               bool       `json:"required"` // 2FA is mandatory for the user and cannot be disabled
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorSetupResponse contains the new secret for the authenticator app
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"` // otpauth:// URI to render as QR code
+}
+
+// TwoFactorCodeRequest is a TOTP code, or a recovery code where allowed
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// DisableTwoFactorRequest represents the payload to turn off 2FA
+type DisableTwoFactorRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// RecoveryCodesResponse contains newly generated recovery codes; they are only shown once
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorLoginRequest is the second login step with the pre-auth token from the first step
+type TwoFactorLoginRequest struct {
+	PreAuthToken string `json:"pre_auth_token"`
+	Code         string `json:"code"`
+}
+
+// TwoFactorSetupLoginRequest starts the enrolment of admins who must use 2FA before they can log in
+type TwoFactorSetupLoginRequest struct {
+	PreAuthToken string `json:"pre_auth_token"`
+}
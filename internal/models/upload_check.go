@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// UploadReference is an upload path stored in the database
+type UploadReference struct {
+	Source   string `json:"source"` // table and column, e.g. "dogs.photo"
+	RecordID int    `json:"record_id"`
+	Path     string `json:"path"`
+}
+
+// OrphanedUpload is a stored file that no database record references
+type OrphanedUpload struct {
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+	Deleted    bool      `json:"deleted"`
+}
+
+// UploadCheckReport is the result of reconciling database references against stored files
+type UploadCheckReport struct {
+	CheckedAt    time.Time          `json:"checked_at"`
+	StoredFiles  int                `json:"stored_files"`
+	References   int                `json:"references"`
+	Orphans      []*OrphanedUpload  `json:"orphans"`
+	Missing      []*UploadReference `json:"missing"`
+	GraceHours   int                `json:"grace_hours"`
+	DeletedCount int                `json:"deleted_count"`
+}
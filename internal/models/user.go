@@ -26,6 +26,9 @@ type User struct {
 	PasswordResetToken       *string    `json:"-"`
 	PasswordResetExpires     *time.Time `json:"-"`
 	ProfilePhoto             *string    `json:"profile_photo,omitempty"`
+	// Signed, expiring URLs of the profile photo; set by handlers, not stored
+	ProfilePhotoURL          *string    `json:"profile_photo_url,omitempty"`
+	ProfilePhotoThumbnailURL *string    `json:"profile_photo_thumbnail_url,omitempty"`
 	AnonymousID              *string    `json:"anonymous_id,omitempty"`
 	TermsAcceptedAt          time.Time  `json:"terms_accepted_at"`
 	LastActivityAt           time.Time  `json:"last_activity_at"`
@@ -54,10 +57,17 @@ type LoginRequest struct {
 }
 
 // LoginResponse represents the login response
+// If a second factor is needed, only the 2FA fields are set and the client continues at /api/auth/login/2fa
 type LoginResponse struct {
-	Token   string `json:"token"`
-	User    *User  `json:"user"`
-	IsAdmin bool   `json:"is_admin"`
+	Token                  string   `json:"token,omitempty"`         // short-lived access token
+	RefreshToken           string   `json:"refresh_token,omitempty"` // exchanged for new tokens at /api/auth/refresh
+	ExpiresIn              int      `json:"expires_in,omitempty"`    // lifetime of the access token in seconds
+	User                   *User    `json:"user,omitempty"`
+	IsAdmin                bool     `json:"is_admin"`
+	TwoFactorRequired      bool     `json:"two_factor_required,omitempty"`
+	TwoFactorSetupRequired bool     `json:"two_factor_setup_required,omitempty"` // 2FA is mandatory but not set up yet
+	PreAuthToken           string   `json:"pre_auth_token,omitempty"`
+	RecoveryCodes          []string `json:"recovery_codes,omitempty"` // set once when 2FA was set up during login
 }
 
 // VerifyEmailRequest represents email verification payload
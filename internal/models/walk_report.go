@@ -0,0 +1,100 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// WalkReport represents a structured report filed after a completed walk
+// There is one report per dog of a booking, so group walks get one report per dog
+type WalkReport struct {
+	ID                  int       `json:"id"`
+	BookingID           int       `json:"booking_id"`
+	DogID               int       `json:"dog_id"`
+	UserID              int       `json:"user_id"`
+	LeashPulling        int       `json:"leash_pulling"`     // 1 (none) - 5 (strong)
+	DogReactivity       int       `json:"dog_reactivity"`    // 1 (calm) - 5 (very reactive)
+	PeopleReactivity    int       `json:"people_reactivity"` // 1 (calm) - 5 (very reactive)
+	BathroomPee         bool      `json:"bathroom_pee"`
+	BathroomPoop        bool      `json:"bathroom_poop"`
+	DistanceKm          *float64  `json:"distance_km,omitempty"`
+	HadIncident         bool      `json:"had_incident"`
+	IncidentDescription *string   `json:"incident_description,omitempty"`
+	Notes               *string   `json:"notes,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+
+	// Joined data for responses
+	BookingDate string `json:"booking_date,omitempty"`
+	UserName    string `json:"user_name,omitempty"`
+}
+
+// WalkReportRequest represents a request to file or update a walk report
+type WalkReportRequest struct {
+	DogID               *int     `json:"dog_id,omitempty"` // Defaults to the primary dog of the booking
+	LeashPulling        int      `json:"leash_pulling"`
+	DogReactivity       int      `json:"dog_reactivity"`
+	PeopleReactivity    int      `json:"people_reactivity"`
+	BathroomPee         bool     `json:"bathroom_pee"`
+	BathroomPoop        bool     `json:"bathroom_poop"`
+	DistanceKm          *float64 `json:"distance_km,omitempty"`
+	HadIncident         bool     `json:"had_incident"`
+	IncidentDescription *string  `json:"incident_description,omitempty"`
+	Notes               *string  `json:"notes,omitempty"`
+}
+
+// Validate validates the walk report request
+func (r *WalkReportRequest) Validate() error {
+	ratings := []struct {
+		field string
+		value int
+	}{
+		{"leash_pulling", r.LeashPulling},
+		{"dog_reactivity", r.DogReactivity},
+		{"people_reactivity", r.PeopleReactivity},
+	}
+	for _, rating := range ratings {
+		if rating.value < 1 || rating.value > 5 {
+			return &ValidationError{Field: rating.field, Message: "Rating must be between 1 and 5"}
+		}
+	}
+
+	if r.DistanceKm != nil && (*r.DistanceKm < 0 || *r.DistanceKm > 50) {
+		return &ValidationError{Field: "distance_km", Message: "Distance must be between 0 and 50 km"}
+	}
+
+	if r.HadIncident && (r.IncidentDescription == nil || strings.TrimSpace(*r.IncidentDescription) == "") {
+		return &ValidationError{Field: "incident_description", Message: "Please describe the incident"}
+	}
+
+	if r.Notes != nil && len(*r.Notes) > 2000 {
+		return &ValidationError{Field: "notes", Message: "Notes must be at most 2000 characters"}
+	}
+
+	return nil
+}
+
+// DogReportSummary aggregates the walk reports of a dog so staff can spot trends
+type DogReportSummary struct {
+	DogID                  int                   `json:"dog_id"`
+	ReportCount            int                   `json:"report_count"`
+	AvgLeashPulling        float64               `json:"avg_leash_pulling"`
+	AvgDogReactivity       float64               `json:"avg_dog_reactivity"`
+	AvgPeopleReactivity    float64               `json:"avg_people_reactivity"`
+	TotalDistanceKm        float64               `json:"total_distance_km"`
+	IncidentCount          int                   `json:"incident_count"`
+	BathroomPeePercentage  float64               `json:"bathroom_pee_percentage"`
+	BathroomPoopPercentage float64               `json:"bathroom_poop_percentage"`
+	LastReportAt           *time.Time            `json:"last_report_at,omitempty"`
+	Monthly                []*MonthlyReportStats `json:"monthly"`
+}
+
+// MonthlyReportStats holds the averaged ratings of one month (YYYY-MM)
+type MonthlyReportStats struct {
+	Month               string  `json:"month"`
+	ReportCount         int     `json:"report_count"`
+	AvgLeashPulling     float64 `json:"avg_leash_pulling"`
+	AvgDogReactivity    float64 `json:"avg_dog_reactivity"`
+	AvgPeopleReactivity float64 `json:"avg_people_reactivity"`
+	IncidentCount       int     `json:"incident_count"`
+}
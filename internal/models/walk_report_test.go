@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+)
+
+// DONE: TestWalkReportRequest_Validate tests validation for walk reports
+func TestWalkReportRequest_Validate(t *testing.T) {
+	distance := 3.5
+	tooFar := 120.0
+	description := "Hat einen Jogger angebellt"
+	empty := "  "
+
+	tests := []struct {
+		name    string
+		req     WalkReportRequest
+		wantErr bool
+	}{
+		{
+			name:    "valid report",
+			req:     WalkReportRequest{LeashPulling: 2, DogReactivity: 1, PeopleReactivity: 3, DistanceKm: &distance},
+			wantErr: false,
+		},
+		{
+			name:    "missing ratings",
+			req:     WalkReportRequest{},
+			wantErr: true,
+		},
+		{
+			name:    "rating out of range",
+			req:     WalkReportRequest{LeashPulling: 6, DogReactivity: 1, PeopleReactivity: 1},
+			wantErr: true,
+		},
+		{
+			name:    "distance out of range",
+			req:     WalkReportRequest{LeashPulling: 1, DogReactivity: 1, PeopleReactivity: 1, DistanceKm: &tooFar},
+			wantErr: true,
+		},
+		{
+			name:    "incident without description",
+			req:     WalkReportRequest{LeashPulling: 1, DogReactivity: 1, PeopleReactivity: 1, HadIncident: true, IncidentDescription: &empty},
+			wantErr: true,
+		},
+		{
+			name:    "incident with description",
+			req:     WalkReportRequest{LeashPulling: 1, DogReactivity: 4, PeopleReactivity: 5, HadIncident: true, IncidentDescription: &description},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
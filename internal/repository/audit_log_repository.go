@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// AuditLogRepository handles the audit log of admin actions
+type AuditLogRepository struct {
+	db *sql.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *sql.DB) *AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+// Create writes an audit log entry
+func (r *AuditLogRepository) Create(entry *models.AuditLogEntry) error {
+	var changes *string
+	if len(entry.Changes) > 0 {
+		data, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit changes: %w", err)
+		}
+		encoded := string(data)
+		changes = &encoded
+	}
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	result, err := r.db.Exec(`
+		INSERT INTO audit_log (actor_id, actor_email, action, target_type, target_id, changes, ip_address, request_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.ActorID, entry.ActorEmail, entry.Action, entry.TargetType, entry.TargetID, changes, entry.IPAddress, entry.RequestID, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create audit log entry: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get audit log entry ID: %w", err)
+	}
+	entry.ID = int(id)
+	return nil
+}
+
+// Find returns the entries matching the filter, newest first, and the total number of matches
+func (r *AuditLogRepository) Find(filter *models.AuditLogFilter) (*models.AuditLogPage, error) {
+	where, args := auditLogWhere(filter)
+
+	page := &models.AuditLogPage{Entries: []*models.AuditLogEntry{}, Limit: filter.Limit, Offset: filter.Offset}
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM audit_log`+where, args...).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("failed to count audit log entries: %w", err)
+	}
+
+	query := `
+		SELECT id, actor_id, actor_email, action, target_type, target_id, changes, ip_address, request_id, created_at
+		FROM audit_log` + where + `
+		ORDER BY created_at DESC, id DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := &models.AuditLogEntry{}
+		var changes sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.ActorID, &entry.ActorEmail, &entry.Action, &entry.TargetType,
+			&entry.TargetID, &changes, &entry.IPAddress, &entry.RequestID, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit log entry: %w", err)
+		}
+		if changes.Valid && changes.String != "" {
+			if err := json.Unmarshal([]byte(changes.String), &entry.Changes); err != nil {
+				return nil, fmt.Errorf("failed to decode audit changes: %w", err)
+			}
+		}
+		page.Entries = append(page.Entries, entry)
+	}
+
+	return page, rows.Err()
+}
+
+// DeleteOlderThan deletes entries created before the cutoff and returns how many were deleted
+func (r *AuditLogRepository) DeleteOlderThan(cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM audit_log WHERE created_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old audit log entries: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// auditLogWhere builds the WHERE clause for a filter
+func auditLogWhere(filter *models.AuditLogFilter) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.ActorID != nil {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, *filter.ActorID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.TargetType != "" {
+		conditions = append(conditions, "target_type = ?")
+		args = append(args, filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, *filter.TargetID)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestAuditLogRepository tests filtering, paging and deleting audit log entries
+func TestAuditLogRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewAuditLogRepository(db)
+
+	adminID, otherAdminID, dogID := 1, 2, 5
+	now := time.Now()
+	entries := []*models.AuditLogEntry{
+		{ActorID: &adminID, Action: models.AuditDogUpdate, TargetType: models.AuditTargetDog, TargetID: &dogID, CreatedAt: now.AddDate(0, 0, -10),
+			Changes: map[string]models.AuditChange{"name": {Before: "Bella", After: "Luna"}}},
+		{ActorID: &adminID, Action: models.AuditDogDelete, TargetType: models.AuditTargetDog, TargetID: &dogID, CreatedAt: now.AddDate(0, 0, -1)},
+		{ActorID: &otherAdminID, Action: models.AuditSettingUpdate, TargetType: models.AuditTargetSetting, CreatedAt: now},
+	}
+	for _, entry := range entries {
+		if err := repo.Create(entry); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+
+	find := func(filter *models.AuditLogFilter) *models.AuditLogPage {
+		page, err := repo.Find(filter)
+		if err != nil {
+			t.Fatalf("Find() failed: %v", err)
+		}
+		return page
+	}
+
+	t.Run("newest first with changes", func(t *testing.T) {
+		page := find(&models.AuditLogFilter{})
+		if page.Total != 3 || len(page.Entries) != 3 {
+			t.Fatalf("Expected 3 entries, got %d", page.Total)
+		}
+		if page.Entries[0].Action != models.AuditSettingUpdate {
+			t.Errorf("Expected newest entry first, got %s", page.Entries[0].Action)
+		}
+		if page.Entries[2].Changes["name"].After != "Luna" {
+			t.Errorf("Expected changes to be decoded, got %v", page.Entries[2].Changes)
+		}
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		if page := find(&models.AuditLogFilter{ActorID: &adminID}); page.Total != 2 {
+			t.Errorf("Expected 2 entries of the actor, got %d", page.Total)
+		}
+		if page := find(&models.AuditLogFilter{TargetType: models.AuditTargetDog, TargetID: &dogID, Action: models.AuditDogDelete}); page.Total != 1 {
+			t.Errorf("Expected 1 delete of the dog, got %d", page.Total)
+		}
+		from := now.AddDate(0, 0, -2)
+		to := now.Add(-time.Hour)
+		if page := find(&models.AuditLogFilter{From: &from, To: &to}); page.Total != 1 || page.Entries[0].Action != models.AuditDogDelete {
+			t.Errorf("Expected only the entry within the date range, got %d", page.Total)
+		}
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		page := find(&models.AuditLogFilter{Limit: 2, Offset: 2})
+		if page.Total != 3 || len(page.Entries) != 1 {
+			t.Errorf("Expected total 3 with 1 entry on the second page, got %d/%d", page.Total, len(page.Entries))
+		}
+	})
+
+	t.Run("delete older than", func(t *testing.T) {
+		deleted, err := repo.DeleteOlderThan(now.AddDate(0, 0, -5))
+		if err != nil {
+			t.Fatalf("DeleteOlderThan() failed: %v", err)
+		}
+		if deleted != 1 {
+			t.Errorf("Expected 1 deleted entry, got %d", deleted)
+		}
+	})
+}
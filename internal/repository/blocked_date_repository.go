@@ -114,6 +114,34 @@ func (r *BlockedDateRepository) FindByDate(date string) (*models.BlockedDate, er
 	return blockedDate, nil
 }
 
+// FindByID finds a blocked date by ID
+func (r *BlockedDateRepository) FindByID(id int) (*models.BlockedDate, error) {
+	query := `
+		SELECT id, date, reason, created_by, created_at
+		FROM blocked_dates
+		WHERE id = ?
+	`
+
+	blockedDate := &models.BlockedDate{}
+	err := r.db.QueryRow(query, id).Scan(
+		&blockedDate.ID,
+		&blockedDate.Date,
+		&blockedDate.Reason,
+		&blockedDate.CreatedBy,
+		&blockedDate.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find blocked date: %w", err)
+	}
+
+	return blockedDate, nil
+}
+
 // Delete deletes a blocked date
 func (r *BlockedDateRepository) Delete(id int) error {
 	query := `DELETE FROM blocked_dates WHERE id = ?`
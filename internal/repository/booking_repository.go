@@ -2,12 +2,30 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/tranmh/gassigeher/internal/models"
 )
 
+var (
+	// ErrDogDoubleBooked is returned when a dog of a new booking is already booked for the time slot
+	ErrDogDoubleBooked = errors.New("dog is already booked for this time")
+	// ErrBookingFull is returned when an open walk already has the maximum number of co-walkers
+	ErrBookingFull = errors.New("walk is already full")
+	// ErrWalkerDoubleBooked is returned when a walker already has another walk at the same time
+	ErrWalkerDoubleBooked = errors.New("walker already has a walk at this time")
+)
+
+// doubleBookingQuery counts scheduled bookings of a dog at a time slot, as primary or additional dog
+const doubleBookingQuery = `
+		SELECT COUNT(*)
+		FROM bookings
+		WHERE (dog_id = ? OR id IN (SELECT booking_id FROM booking_dogs WHERE dog_id = ?))
+		AND date = ? AND scheduled_time = ? AND status = 'scheduled'
+	`
+
 // BookingRepository handles booking database operations
 type BookingRepository struct {
 	db *sql.DB
@@ -39,7 +57,17 @@ func (r *BookingRepository) Create(booking *models.Booking) error {
 		}
 	}
 
-	result, err := r.db.Exec(query,
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockDogs(tx, []int{booking.DogID}); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(query,
 		booking.UserID,
 		booking.DogID,
 		booking.Date,
@@ -61,22 +89,250 @@ func (r *BookingRepository) Create(booking *models.Booking) error {
 	}
 
 	booking.ID = int(id)
+
+	if err := checkDogsFree(tx, booking, []int{booking.DogID}); err != nil {
+		return err
+	}
+	if err := checkWalkerFree(tx, booking); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit booking: %w", err)
+	}
+
 	booking.CreatedAt = now
 	booking.UpdatedAt = now
 
 	return nil
 }
 
+// CreateGroup creates a booking together with its additional dogs
+// All rows are written in one transaction so a group walk is never half-created;
+// co-walkers join later with AddParticipant
+func (r *BookingRepository) CreateGroup(booking *models.Booking, additionalDogIDs []int) error {
+	now := time.Now()
+
+	if booking.Status == "" {
+		booking.Status = "scheduled"
+	}
+	if booking.ApprovalStatus == "" {
+		if booking.RequiresApproval {
+			booking.ApprovalStatus = "pending"
+		} else {
+			booking.ApprovalStatus = "approved"
+		}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	dogIDs := append([]int{booking.DogID}, additionalDogIDs...)
+	if err := lockDogs(tx, dogIDs); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO bookings (user_id, dog_id, date, scheduled_time, status, requires_approval, approval_status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		booking.UserID,
+		booking.DogID,
+		booking.Date,
+		booking.ScheduledTime,
+		booking.Status,
+		booking.RequiresApproval,
+		booking.ApprovalStatus,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get booking ID: %w", err)
+	}
+	booking.ID = int(id)
+
+	booking.Participants = []*models.BookingParticipant{}
+	booking.AdditionalDogs = []*models.BookingDog{}
+	for _, dogID := range additionalDogIDs {
+		res, err := tx.Exec(`INSERT INTO booking_dogs (booking_id, dog_id, created_at) VALUES (?, ?, ?)`,
+			booking.ID, dogID, now)
+		if err != nil {
+			return fmt.Errorf("failed to add dog: %w", err)
+		}
+		did, _ := res.LastInsertId()
+		booking.AdditionalDogs = append(booking.AdditionalDogs, &models.BookingDog{
+			ID: int(did), BookingID: booking.ID, DogID: dogID, CreatedAt: now,
+		})
+	}
+
+	if err := checkDogsFree(tx, booking, dogIDs); err != nil {
+		return err
+	}
+	if err := checkWalkerFree(tx, booking); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit booking: %w", err)
+	}
+
+	booking.CreatedAt = now
+	booking.UpdatedAt = now
+
+	return nil
+}
+
+// lockDogs locks the dogs of a new booking until the transaction ends, so concurrent bookings
+// of the same dog are checked one after the other
+func lockDogs(tx *sql.Tx, dogIDs []int) error {
+	for _, dogID := range dogIDs {
+		if _, err := tx.Exec(`UPDATE dogs SET updated_at = updated_at WHERE id = ?`, dogID); err != nil {
+			return fmt.Errorf("failed to lock dog: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkDogsFree checks inside the transaction that no other booking walks the dogs at the same time
+// The unique constraint of bookings only covers the primary dog, not the additional dogs of group walks
+func checkDogsFree(tx *sql.Tx, booking *models.Booking, dogIDs []int) error {
+	for _, dogID := range dogIDs {
+		var count int
+		if err := tx.QueryRow(doubleBookingQuery, dogID, dogID, booking.Date, booking.ScheduledTime).Scan(&count); err != nil {
+			return fmt.Errorf("failed to check double booking: %w", err)
+		}
+		if count > 1 {
+			return ErrDogDoubleBooked
+		}
+	}
+	return nil
+}
+
+// checkWalkerFree checks inside the transaction that the organizer of a new booking is not a co-walker
+// of another walk at the same time, as AddParticipant checks for co-walkers
+func checkWalkerFree(tx *sql.Tx, booking *models.Booking) error {
+	var count int
+	err := tx.QueryRow(`
+		SELECT COUNT(*)
+		FROM bookings
+		WHERE id IN (SELECT booking_id FROM booking_participants WHERE user_id = ?)
+		AND date = ? AND scheduled_time = ? AND status = 'scheduled'
+	`, booking.UserID, booking.Date, booking.ScheduledTime).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check walker bookings: %w", err)
+	}
+	if count > 0 {
+		return ErrWalkerDoubleBooked
+	}
+	return nil
+}
+
+// LoadGroup populates the co-walkers and additional dogs of a booking
+// Joined user and dog data is included so notifications can be sent to everyone
+func (r *BookingRepository) LoadGroup(booking *models.Booking) error {
+	participants, err := r.GetParticipants(booking.ID)
+	if err != nil {
+		return err
+	}
+
+	dogs, err := r.GetAdditionalDogs(booking.ID)
+	if err != nil {
+		return err
+	}
+
+	booking.Participants = participants
+	booking.AdditionalDogs = dogs
+	return nil
+}
+
+// GetParticipants returns the co-walkers of a booking with user details
+func (r *BookingRepository) GetParticipants(bookingID int) ([]*models.BookingParticipant, error) {
+	query := `
+		SELECT p.id, p.booking_id, p.user_id, p.created_at, u.name, u.email
+		FROM booking_participants p
+		LEFT JOIN users u ON p.user_id = u.id
+		WHERE p.booking_id = ?
+		ORDER BY p.id ASC
+	`
+
+	rows, err := r.db.Query(query, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query participants: %w", err)
+	}
+	defer rows.Close()
+
+	participants := []*models.BookingParticipant{}
+	for rows.Next() {
+		p := &models.BookingParticipant{User: &models.User{}}
+		var userName, userEmail sql.NullString
+		if err := rows.Scan(&p.ID, &p.BookingID, &p.UserID, &p.CreatedAt, &userName, &userEmail); err != nil {
+			return nil, fmt.Errorf("failed to scan participant: %w", err)
+		}
+
+		p.User.ID = p.UserID
+		if userName.Valid {
+			p.User.Name = userName.String
+		} else {
+			p.User.Name = "Deleted User"
+		}
+		if userEmail.Valid {
+			email := userEmail.String
+			p.User.Email = &email
+		}
+
+		participants = append(participants, p)
+	}
+
+	return participants, nil
+}
+
+// GetAdditionalDogs returns the additional dogs of a booking with dog details
+func (r *BookingRepository) GetAdditionalDogs(bookingID int) ([]*models.BookingDog, error) {
+	query := `
+		SELECT bd.id, bd.booking_id, bd.dog_id, bd.created_at, d.name, d.breed, d.category
+		FROM booking_dogs bd
+		JOIN dogs d ON bd.dog_id = d.id
+		WHERE bd.booking_id = ?
+		ORDER BY bd.id ASC
+	`
+
+	rows, err := r.db.Query(query, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query booking dogs: %w", err)
+	}
+	defer rows.Close()
+
+	dogs := []*models.BookingDog{}
+	for rows.Next() {
+		d := &models.BookingDog{Dog: &models.Dog{}}
+		if err := rows.Scan(&d.ID, &d.BookingID, &d.DogID, &d.CreatedAt, &d.Dog.Name, &d.Dog.Breed, &d.Dog.Category); err != nil {
+			return nil, fmt.Errorf("failed to scan booking dog: %w", err)
+		}
+		d.Dog.ID = d.DogID
+		dogs = append(dogs, d)
+	}
+
+	return dogs, nil
+}
+
 // FindByID finds a booking by ID
 func (r *BookingRepository) FindByID(id int) (*models.Booking, error) {
 	query := `
 		SELECT id, user_id, dog_id, date, scheduled_time, status,
-		       completed_at, user_notes, admin_cancellation_reason, created_at, updated_at
+		       completed_at, user_notes, admin_cancellation_reason, created_at, updated_at,
+		       requires_approval, approval_status
 		FROM bookings
 		WHERE id = ?
 	`
 
 	booking := &models.Booking{}
+	var approvalStatus sql.NullString
 	err := r.db.QueryRow(query, id).Scan(
 		&booking.ID,
 		&booking.UserID,
@@ -89,6 +345,8 @@ func (r *BookingRepository) FindByID(id int) (*models.Booking, error) {
 		&booking.AdminCancellationReason,
 		&booking.CreatedAt,
 		&booking.UpdatedAt,
+		&booking.RequiresApproval,
+		&approvalStatus,
 	)
 
 	if err == sql.ErrNoRows {
@@ -99,6 +357,11 @@ func (r *BookingRepository) FindByID(id int) (*models.Booking, error) {
 		return nil, fmt.Errorf("failed to find booking: %w", err)
 	}
 
+	booking.ApprovalStatus = "approved"
+	if approvalStatus.Valid {
+		booking.ApprovalStatus = approvalStatus.String
+	}
+
 	return booking, nil
 }
 
@@ -114,13 +377,14 @@ func (r *BookingRepository) FindAll(filter *models.BookingFilterRequest) ([]*mod
 
 	if filter != nil {
 		if filter.UserID != nil {
-			query += " AND user_id = ?"
-			args = append(args, *filter.UserID)
+			// Include group walks the user joined as a co-walker
+			query += " AND (user_id = ? OR id IN (SELECT booking_id FROM booking_participants WHERE user_id = ?))"
+			args = append(args, *filter.UserID, *filter.UserID)
 		}
 
 		if filter.DogID != nil {
-			query += " AND dog_id = ?"
-			args = append(args, *filter.DogID)
+			query += " AND (dog_id = ? OR id IN (SELECT booking_id FROM booking_dogs WHERE dog_id = ?))"
+			args = append(args, *filter.DogID, *filter.DogID)
 		}
 
 		if filter.DateFrom != nil {
@@ -225,15 +489,10 @@ func (r *BookingRepository) AddNotes(id int, notes string) error {
 }
 
 // CheckDoubleBooking checks if a dog is already booked for the given date and scheduled time
+// A dog counts as booked both as the primary dog and as an additional dog of a group walk
 func (r *BookingRepository) CheckDoubleBooking(dogID int, date, scheduledTime string) (bool, error) {
-	query := `
-		SELECT COUNT(*)
-		FROM bookings
-		WHERE dog_id = ? AND date = ? AND scheduled_time = ? AND status = 'scheduled'
-	`
-
 	var count int
-	err := r.db.QueryRow(query, dogID, date, scheduledTime).Scan(&count)
+	err := r.db.QueryRow(doubleBookingQuery, dogID, dogID, date, scheduledTime).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check double booking: %w", err)
 	}
@@ -252,6 +511,7 @@ func (r *BookingRepository) AutoComplete() (int, error) {
 		UPDATE bookings
 		SET status = 'completed', completed_at = ?, updated_at = ?
 		WHERE status = 'scheduled'
+		AND COALESCE(approval_status, 'approved') != 'awaiting_walkers'
 		AND (
 			date < ?
 			OR (date = ? AND scheduled_time < ?)
@@ -271,19 +531,195 @@ func (r *BookingRepository) AutoComplete() (int, error) {
 	return int(rows), nil
 }
 
+// CancelUnfilledOpenBookings cancels open group walks still waiting for walkers that start before the cutoff
+// The cancelled bookings are returned with user, dog and group data so everyone can be notified
+func (r *BookingRepository) CancelUnfilledOpenBookings(cutoff time.Time, reason string) ([]*models.Booking, error) {
+	cutoffDate := cutoff.Format("2006-01-02")
+	cutoffTime := cutoff.Format("15:04")
+
+	rows, err := r.db.Query(`
+		SELECT id
+		FROM bookings
+		WHERE status = 'scheduled'
+		AND approval_status = 'awaiting_walkers'
+		AND (
+			date < ?
+			OR (date = ? AND scheduled_time < ?)
+		)
+	`, cutoffDate, cutoffDate, cutoffTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unfilled open bookings: %w", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan booking ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	cancelled := []*models.Booking{}
+	for _, id := range ids {
+		// A co-walker may have filled the walk in the meantime
+		result, err := r.db.Exec(`
+			UPDATE bookings
+			SET status = 'cancelled', admin_cancellation_reason = ?, updated_at = ?
+			WHERE id = ? AND status = 'scheduled' AND approval_status = 'awaiting_walkers'
+		`, reason, time.Now(), id)
+		if err != nil {
+			return cancelled, fmt.Errorf("failed to cancel unfilled open booking: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			continue
+		}
+
+		booking, err := r.FindByIDWithDetails(id)
+		if err != nil {
+			return cancelled, err
+		}
+		if booking == nil {
+			continue
+		}
+		if err := r.LoadGroup(booking); err != nil {
+			return cancelled, err
+		}
+		cancelled = append(cancelled, booking)
+	}
+
+	return cancelled, nil
+}
+
+// FindOpen returns upcoming group walks that are still waiting for co-walkers
+func (r *BookingRepository) FindOpen() ([]*models.Booking, error) {
+	query := `
+		SELECT id, user_id, dog_id, date, scheduled_time, status,
+		       completed_at, user_notes, admin_cancellation_reason, created_at, updated_at,
+		       requires_approval, approval_status
+		FROM bookings
+		WHERE status = 'scheduled' AND approval_status = 'awaiting_walkers' AND date >= ?
+		ORDER BY date ASC, scheduled_time ASC
+	`
+
+	rows, err := r.db.Query(query, time.Now().Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open bookings: %w", err)
+	}
+	defer rows.Close()
+
+	bookings := []*models.Booking{}
+	for rows.Next() {
+		booking := &models.Booking{}
+		err := rows.Scan(
+			&booking.ID,
+			&booking.UserID,
+			&booking.DogID,
+			&booking.Date,
+			&booking.ScheduledTime,
+			&booking.Status,
+			&booking.CompletedAt,
+			&booking.UserNotes,
+			&booking.AdminCancellationReason,
+			&booking.CreatedAt,
+			&booking.UpdatedAt,
+			&booking.RequiresApproval,
+			&booking.ApprovalStatus,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", err)
+		}
+		bookings = append(bookings, booking)
+	}
+
+	return bookings, nil
+}
+
+// AddParticipant adds a co-walker to an existing booking, up to maxCoWalkers co-walkers
+// The booking is locked while the walkers are counted, so concurrent joins cannot overfill it,
+// and the co-walker must not have another scheduled walk at the same time
+func (r *BookingRepository) AddParticipant(bookingID, userID, maxCoWalkers int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE bookings SET updated_at = updated_at WHERE id = ?`, bookingID); err != nil {
+		return fmt.Errorf("failed to lock booking: %w", err)
+	}
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM booking_participants WHERE booking_id = ?`, bookingID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count participants: %w", err)
+	}
+	if count >= maxCoWalkers {
+		return ErrBookingFull
+	}
+
+	err = tx.QueryRow(`
+		SELECT COUNT(*)
+		FROM bookings b
+		JOIN bookings target ON target.id = ?
+		WHERE (b.user_id = ? OR b.id IN (SELECT booking_id FROM booking_participants WHERE user_id = ?))
+		AND b.date = target.date AND b.scheduled_time = target.scheduled_time
+		AND b.status = 'scheduled' AND b.id != target.id
+	`, bookingID, userID, userID).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check walker bookings: %w", err)
+	}
+	if count > 0 {
+		return ErrWalkerDoubleBooked
+	}
+
+	if _, err := tx.Exec(`INSERT INTO booking_participants (booking_id, user_id, created_at) VALUES (?, ?, ?)`,
+		bookingID, userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to add participant: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit participant: %w", err)
+	}
+	return nil
+}
+
+// RemoveParticipant removes a co-walker from a booking; it returns false if the user was not a co-walker
+func (r *BookingRepository) RemoveParticipant(bookingID, userID int) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM booking_participants WHERE booking_id = ? AND user_id = ?`, bookingID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove participant: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove participant: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// SetApprovalStatus updates the approval status of a booking (e.g. when an open walk is filled)
+func (r *BookingRepository) SetApprovalStatus(bookingID int, approvalStatus string) error {
+	_, err := r.db.Exec(`UPDATE bookings SET approval_status = ?, updated_at = ? WHERE id = ?`,
+		approvalStatus, time.Now(), bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to update approval status: %w", err)
+	}
+	return nil
+}
+
 // GetUpcoming gets upcoming bookings for a user
 func (r *BookingRepository) GetUpcoming(userID int, limit int) ([]*models.Booking, error) {
 	query := `
 		SELECT id, user_id, dog_id, date, scheduled_time, status,
 		       completed_at, user_notes, admin_cancellation_reason, created_at, updated_at
 		FROM bookings
-		WHERE user_id = ? AND status = 'scheduled' AND date >= ?
+		WHERE (user_id = ? OR id IN (SELECT booking_id FROM booking_participants WHERE user_id = ?))
+		AND status = 'scheduled' AND date >= ?
 		ORDER BY date ASC, scheduled_time ASC
 		LIMIT ?
 	`
 
 	currentDate := time.Now().Format("2006-01-02")
-	rows, err := r.db.Query(query, userID, currentDate, limit)
+	rows, err := r.db.Query(query, userID, userID, currentDate, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query upcoming bookings: %w", err)
 	}
@@ -336,6 +772,7 @@ func (r *BookingRepository) GetForReminders() ([]*models.Booking, error) {
 		LEFT JOIN users u ON b.user_id = u.id
 		LEFT JOIN dogs d ON b.dog_id = d.id
 		WHERE b.status = 'scheduled'
+		AND COALESCE(b.approval_status, 'approved') != 'awaiting_walkers'
 		AND b.reminder_sent_at IS NULL
 		AND b.date = ?
 		AND b.scheduled_time >= ?
@@ -405,6 +842,85 @@ func (r *BookingRepository) MarkReminderSent(bookingID int) error {
 	return nil
 }
 
+// GetForReportReminders gets completed bookings without a walk report
+// Only bookings completed between since and completedBefore are returned, so old history is never reminded
+func (r *BookingRepository) GetForReportReminders(completedBefore, since time.Time) ([]*models.Booking, error) {
+	query := `
+		SELECT b.id, b.user_id, b.dog_id, b.date, b.scheduled_time, b.status,
+		       b.completed_at, b.user_notes, b.admin_cancellation_reason, b.created_at, b.updated_at,
+		       u.name as user_name, u.email as user_email,
+		       d.name as dog_name
+		FROM bookings b
+		LEFT JOIN users u ON b.user_id = u.id
+		LEFT JOIN dogs d ON b.dog_id = d.id
+		WHERE b.status = 'completed'
+		AND b.report_reminder_sent_at IS NULL
+		AND b.completed_at <= ?
+		AND b.completed_at >= ?
+		AND NOT EXISTS (SELECT 1 FROM walk_reports wr WHERE wr.booking_id = b.id)
+	`
+
+	rows, err := r.db.Query(query, completedBefore, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bookings for report reminders: %w", err)
+	}
+	defer rows.Close()
+
+	bookings := []*models.Booking{}
+	for rows.Next() {
+		booking := &models.Booking{
+			User: &models.User{},
+			Dog:  &models.Dog{},
+		}
+		var userName, userEmail, dogName sql.NullString
+
+		err := rows.Scan(
+			&booking.ID,
+			&booking.UserID,
+			&booking.DogID,
+			&booking.Date,
+			&booking.ScheduledTime,
+			&booking.Status,
+			&booking.CompletedAt,
+			&booking.UserNotes,
+			&booking.AdminCancellationReason,
+			&booking.CreatedAt,
+			&booking.UpdatedAt,
+			&userName,
+			&userEmail,
+			&dogName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", err)
+		}
+
+		if userName.Valid {
+			booking.User.Name = userName.String
+		}
+		if userEmail.Valid {
+			email := userEmail.String
+			booking.User.Email = &email
+		}
+		if dogName.Valid {
+			booking.Dog.Name = dogName.String
+		}
+
+		bookings = append(bookings, booking)
+	}
+
+	return bookings, nil
+}
+
+// MarkReportReminderSent marks a booking's walk report reminder as sent
+func (r *BookingRepository) MarkReportReminderSent(bookingID int) error {
+	query := `UPDATE bookings SET report_reminder_sent_at = ? WHERE id = ?`
+	_, err := r.db.Exec(query, time.Now(), bookingID)
+	if err != nil {
+		return fmt.Errorf("failed to mark report reminder sent: %w", err)
+	}
+	return nil
+}
+
 // Update updates a booking (for admin to move bookings)
 func (r *BookingRepository) Update(booking *models.Booking) error {
 	query := `
@@ -70,6 +70,9 @@ func setupTestDB(t *testing.T) *sql.DB {
 		external_link TEXT,
 		unavailable_reason TEXT,
 		unavailable_since TIMESTAMP,
+		min_walkers INTEGER DEFAULT 1,
+		min_co_walker_level TEXT,
+		requires_staff_escort INTEGER DEFAULT 0,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);
@@ -95,6 +98,22 @@ func setupTestDB(t *testing.T) *sql.DB {
 		UNIQUE(dog_id, date, scheduled_time)
 	);
 
+	CREATE TABLE booking_participants (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		booking_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(booking_id, user_id)
+	);
+
+	CREATE TABLE booking_dogs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		booking_id INTEGER NOT NULL,
+		dog_id INTEGER NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(booking_id, dog_id)
+	);
+
 	-- Insert test users
 	INSERT INTO users (id, name, email) VALUES (1, 'Test User', 'test@example.com');
 	INSERT INTO users (id, name, email) VALUES (2, 'Test User 2', 'test2@example.com');
@@ -1461,3 +1480,185 @@ func TestRejectBooking_ReasonRequired(t *testing.T) {
 		}
 	})
 }
+
+// DONE: TestBookingRepository_GroupWalk tests group walks with co-walkers and extra dogs
+func TestBookingRepository_GroupWalk(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewBookingRepository(db)
+
+	organizer := testutil.SeedTestUser(t, db, "organizer@example.com", "Organizer", "green")
+	coWalker := testutil.SeedTestUser(t, db, "cowalker@example.com", "Co Walker", "green")
+	bella := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	max := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+
+	booking := &models.Booking{
+		UserID:        organizer,
+		DogID:         bella,
+		Date:          "2025-12-01",
+		ScheduledTime: "09:00",
+	}
+	if err := repo.CreateGroup(booking, []int{max}); err != nil {
+		t.Fatalf("CreateGroup() failed: %v", err)
+	}
+	if err := repo.AddParticipant(booking.ID, coWalker, 2); err != nil {
+		t.Fatalf("AddParticipant() failed: %v", err)
+	}
+
+	t.Run("group members are loaded", func(t *testing.T) {
+		loaded, err := repo.FindByID(booking.ID)
+		if err != nil || loaded == nil {
+			t.Fatalf("FindByID() failed: %v", err)
+		}
+		if err := repo.LoadGroup(loaded); err != nil {
+			t.Fatalf("LoadGroup() failed: %v", err)
+		}
+
+		if !loaded.IsGroupWalk() {
+			t.Error("Expected booking to be a group walk")
+		}
+		if len(loaded.Participants) != 1 || loaded.Participants[0].UserID != coWalker {
+			t.Errorf("Expected co-walker %d, got %+v", coWalker, loaded.Participants)
+		}
+		if len(loaded.AdditionalDogs) != 1 || loaded.AdditionalDogs[0].Dog.Name != "Max" {
+			t.Errorf("Expected additional dog Max, got %+v", loaded.AdditionalDogs)
+		}
+		if !loaded.HasParticipant(coWalker) || !loaded.HasParticipant(organizer) {
+			t.Error("Expected organizer and co-walker to be participants")
+		}
+	})
+
+	t.Run("additional dog counts as booked", func(t *testing.T) {
+		isBooked, err := repo.CheckDoubleBooking(max, "2025-12-01", "09:00")
+		if err != nil {
+			t.Fatalf("CheckDoubleBooking() failed: %v", err)
+		}
+		if !isBooked {
+			t.Error("Expected Max to be booked through the group walk")
+		}
+	})
+
+	t.Run("co-walker sees the booking in their list", func(t *testing.T) {
+		bookings, err := repo.FindAll(&models.BookingFilterRequest{UserID: &coWalker})
+		if err != nil {
+			t.Fatalf("FindAll() failed: %v", err)
+		}
+		if len(bookings) != 1 || bookings[0].ID != booking.ID {
+			t.Errorf("Expected co-walker to see booking %d, got %d bookings", booking.ID, len(bookings))
+		}
+	})
+
+	t.Run("filter by additional dog", func(t *testing.T) {
+		bookings, err := repo.FindAll(&models.BookingFilterRequest{DogID: &max})
+		if err != nil {
+			t.Fatalf("FindAll() failed: %v", err)
+		}
+		if len(bookings) != 1 {
+			t.Errorf("Expected 1 booking for Max, got %d", len(bookings))
+		}
+	})
+
+	t.Run("co-walkers are limited", func(t *testing.T) {
+		third := testutil.SeedTestUser(t, db, "third@example.com", "Third", "green")
+		if err := repo.AddParticipant(booking.ID, third, 1); err != ErrBookingFull {
+			t.Errorf("Expected ErrBookingFull, got %v", err)
+		}
+		if err := repo.AddParticipant(booking.ID, third, 2); err != nil {
+			t.Errorf("AddParticipant() failed: %v", err)
+		}
+	})
+
+	t.Run("additional dog cannot be booked twice", func(t *testing.T) {
+		luna := testutil.SeedTestDog(t, db, "Luna", "Pudel", "green")
+		other := &models.Booking{UserID: coWalker, DogID: luna, Date: "2025-12-01", ScheduledTime: "09:00"}
+		if err := repo.CreateGroup(other, []int{max}); err != ErrDogDoubleBooked {
+			t.Errorf("Expected ErrDogDoubleBooked for Max as additional dog, got %v", err)
+		}
+		single := &models.Booking{UserID: coWalker, DogID: max, Date: "2025-12-01", ScheduledTime: "09:00"}
+		if err := repo.Create(single); err != ErrDogDoubleBooked {
+			t.Errorf("Expected ErrDogDoubleBooked for Max as primary dog, got %v", err)
+		}
+		if isBooked, _ := repo.CheckDoubleBooking(luna, "2025-12-01", "09:00"); isBooked {
+			t.Error("Expected the rejected booking to be rolled back")
+		}
+	})
+
+	t.Run("co-walker cannot organize another walk at the same time", func(t *testing.T) {
+		rocky := testutil.SeedTestDog(t, db, "Rocky", "Dackel", "green")
+		other := &models.Booking{UserID: coWalker, DogID: rocky, Date: "2025-12-01", ScheduledTime: "09:00"}
+		if err := repo.Create(other); err != ErrWalkerDoubleBooked {
+			t.Errorf("Expected ErrWalkerDoubleBooked, got %v", err)
+		}
+		if isBooked, _ := repo.CheckDoubleBooking(rocky, "2025-12-01", "09:00"); isBooked {
+			t.Error("Expected the rejected booking to be rolled back")
+		}
+	})
+
+	t.Run("co-walker leaves", func(t *testing.T) {
+		if removed, err := repo.RemoveParticipant(booking.ID, coWalker); err != nil || !removed {
+			t.Fatalf("Expected the co-walker to be removed, got %v (%v)", removed, err)
+		}
+		if removed, _ := repo.RemoveParticipant(booking.ID, coWalker); removed {
+			t.Error("Expected nothing to remove the second time")
+		}
+		if removed, _ := repo.RemoveParticipant(booking.ID, organizer); removed {
+			t.Error("Expected the organizer not to be a co-walker")
+		}
+	})
+}
+
+// DONE: TestBookingRepository_CancelUnfilledOpenBookings tests cancelling open walks that do not find walkers in time
+func TestBookingRepository_CancelUnfilledOpenBookings(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewBookingRepository(db)
+
+	organizer := testutil.SeedTestUser(t, db, "organizer@example.com", "Organizer", "green")
+	walker := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+	dogs := make([]int, 4)
+	for i, name := range []string{"Bella", "Max", "Luna", "Rocky"} {
+		dogs[i] = testutil.SeedTestDog(t, db, name, "Labrador", "green")
+	}
+
+	yesterday := time.Now().Add(-24 * time.Hour).Format("2006-01-02")
+	soon := time.Now().Add(time.Hour)
+	nextWeek := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	open := &models.Booking{UserID: organizer, DogID: dogs[0], Date: yesterday, ScheduledTime: "09:00", ApprovalStatus: "awaiting_walkers"}
+	repo.Create(open)
+	startingSoon := &models.Booking{UserID: organizer, DogID: dogs[2], Date: soon.Format("2006-01-02"), ScheduledTime: soon.Format("15:04"), ApprovalStatus: "awaiting_walkers"}
+	repo.Create(startingSoon)
+	later := &models.Booking{UserID: organizer, DogID: dogs[3], Date: nextWeek, ScheduledTime: "09:00", ApprovalStatus: "awaiting_walkers"}
+	repo.Create(later)
+	regular := &models.Booking{UserID: walker, DogID: dogs[1], Date: yesterday, ScheduledTime: "09:00"}
+	repo.Create(regular)
+
+	cancelledBookings, err := repo.CancelUnfilledOpenBookings(time.Now().Add(2*time.Hour), "Keine Begleitperson")
+	if err != nil {
+		t.Fatalf("CancelUnfilledOpenBookings() failed: %v", err)
+	}
+	if len(cancelledBookings) != 2 {
+		t.Fatalf("Expected 2 open bookings to be cancelled, got %d", len(cancelledBookings))
+	}
+	if cancelledBookings[0].User == nil || cancelledBookings[0].Dog == nil {
+		t.Error("Expected cancelled bookings with user and dog for the notification")
+	}
+
+	count, err := repo.AutoComplete()
+	if err != nil {
+		t.Fatalf("AutoComplete() failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 booking to be completed, got %d", count)
+	}
+
+	cancelled, _ := repo.FindByID(startingSoon.ID)
+	if cancelled.Status != "cancelled" {
+		t.Errorf("Expected open booking starting soon to be cancelled, got %s", cancelled.Status)
+	}
+	stillOpen, _ := repo.FindByID(later.ID)
+	if stillOpen.Status != "scheduled" {
+		t.Errorf("Expected later open booking to stay scheduled, got %s", stillOpen.Status)
+	}
+	completed, _ := repo.FindByID(regular.ID)
+	if completed.Status != "completed" {
+		t.Errorf("Expected regular booking to be completed, got %s", completed.Status)
+	}
+}
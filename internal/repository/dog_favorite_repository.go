@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// DogFavoriteRepository handles favourite dogs and notification subscriptions
+type DogFavoriteRepository struct {
+	db *sql.DB
+}
+
+// NewDogFavoriteRepository creates a new dog favourite repository
+func NewDogFavoriteRepository(db *sql.DB) *DogFavoriteRepository {
+	return &DogFavoriteRepository{db: db}
+}
+
+// Find returns the favourite of a user for a dog, or nil if the dog is not a favourite
+func (r *DogFavoriteRepository) Find(userID, dogID int) (*models.DogFavorite, error) {
+	favorite := &models.DogFavorite{}
+	err := r.db.QueryRow(`
+		SELECT id, user_id, dog_id, notify_available, notify_free_slots, created_at
+		FROM dog_favorites
+		WHERE user_id = ? AND dog_id = ?
+	`, userID, dogID).Scan(
+		&favorite.ID,
+		&favorite.UserID,
+		&favorite.DogID,
+		&favorite.NotifyAvailable,
+		&favorite.NotifyFreeSlots,
+		&favorite.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find favorite: %w", err)
+	}
+
+	return favorite, nil
+}
+
+// Set marks a dog as favourite of a user, or updates the notification flags if it already is
+func (r *DogFavoriteRepository) Set(favorite *models.DogFavorite) error {
+	existing, err := r.Find(favorite.UserID, favorite.DogID)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		_, err := r.db.Exec(`
+			UPDATE dog_favorites SET notify_available = ?, notify_free_slots = ?
+			WHERE id = ?
+		`, favorite.NotifyAvailable, favorite.NotifyFreeSlots, existing.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update favorite: %w", err)
+		}
+		favorite.ID = existing.ID
+		favorite.CreatedAt = existing.CreatedAt
+		return nil
+	}
+
+	favorite.CreatedAt = time.Now()
+	result, err := r.db.Exec(`
+		INSERT INTO dog_favorites (user_id, dog_id, notify_available, notify_free_slots, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, favorite.UserID, favorite.DogID, favorite.NotifyAvailable, favorite.NotifyFreeSlots, favorite.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create favorite: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get favorite ID: %w", err)
+	}
+	favorite.ID = int(id)
+
+	return nil
+}
+
+// Remove removes a dog from the favourites of a user
+// Does not return error if the dog is not a favourite (idempotent)
+func (r *DogFavoriteRepository) Remove(userID, dogID int) error {
+	_, err := r.db.Exec(`DELETE FROM dog_favorites WHERE user_id = ? AND dog_id = ?`, userID, dogID)
+	if err != nil {
+		return fmt.Errorf("failed to remove favorite: %w", err)
+	}
+	return nil
+}
+
+// FindByUser returns the favourites of a user, most recent first
+func (r *DogFavoriteRepository) FindByUser(userID int) ([]*models.DogFavorite, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, dog_id, notify_available, notify_free_slots, created_at
+		FROM dog_favorites
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query favorites: %w", err)
+	}
+	defer rows.Close()
+
+	favorites := []*models.DogFavorite{}
+	for rows.Next() {
+		favorite := &models.DogFavorite{}
+		err := rows.Scan(
+			&favorite.ID,
+			&favorite.UserID,
+			&favorite.DogID,
+			&favorite.NotifyAvailable,
+			&favorite.NotifyFreeSlots,
+			&favorite.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan favorite: %w", err)
+		}
+		favorites = append(favorites, favorite)
+	}
+
+	return favorites, nil
+}
+
+// FindSubscribers returns the active users who want to be notified about a favourite dog
+// Only users with an e-mail address are returned
+func (r *DogFavoriteRepository) FindSubscribers(dogID int, kind models.FavoriteNotification) ([]*models.User, error) {
+	column := "notify_available"
+	if kind == models.FavoriteNotifyFreeSlots {
+		column = "notify_free_slots"
+	}
+
+	return r.queryUsers(`
+		SELECT u.id, u.name, u.email, u.experience_level
+		FROM dog_favorites f
+		JOIN users u ON u.id = f.user_id
+		WHERE f.dog_id = ? AND f.`+column+` = ?
+		  AND u.is_active = ? AND u.is_deleted = ? AND u.email IS NOT NULL
+		ORDER BY u.id
+	`, dogID, true, true, false)
+}
+
+// SetNewDogSubscription subscribes or unsubscribes a user from notifications about new dogs
+func (r *DogFavoriteRepository) SetNewDogSubscription(userID int, subscribed bool) error {
+	if _, err := r.db.Exec(`DELETE FROM new_dog_subscriptions WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to update new dog subscription: %w", err)
+	}
+	if !subscribed {
+		return nil
+	}
+
+	_, err := r.db.Exec(`INSERT INTO new_dog_subscriptions (user_id, created_at) VALUES (?, ?)`, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update new dog subscription: %w", err)
+	}
+	return nil
+}
+
+// HasNewDogSubscription checks if a user is subscribed to notifications about new dogs
+func (r *DogFavoriteRepository) HasNewDogSubscription(userID int) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM new_dog_subscriptions WHERE user_id = ?`, userID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check new dog subscription: %w", err)
+	}
+	return count > 0, nil
+}
+
+// FindNewDogSubscribers returns the active users subscribed to notifications about new dogs
+// The caller filters by experience level, since it depends on the category of the new dog
+func (r *DogFavoriteRepository) FindNewDogSubscribers() ([]*models.User, error) {
+	return r.queryUsers(`
+		SELECT u.id, u.name, u.email, u.experience_level
+		FROM new_dog_subscriptions s
+		JOIN users u ON u.id = s.user_id
+		WHERE u.is_active = ? AND u.is_deleted = ? AND u.email IS NOT NULL
+		ORDER BY u.id
+	`, true, false)
+}
+
+// queryUsers runs a query selecting id, name, email and experience level of users
+func (r *DogFavoriteRepository) queryUsers(query string, args ...interface{}) ([]*models.User, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*models.User{}
+	for rows.Next() {
+		user := &models.User{}
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.ExperienceLevel); err != nil {
+			return nil, fmt.Errorf("failed to scan subscriber: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
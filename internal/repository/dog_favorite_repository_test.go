@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogFavoriteRepository tests favourites and notification subscriptions
+func TestDogFavoriteRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewDogFavoriteRepository(db)
+
+	userID := testutil.SeedTestUser(t, db, "user@example.com", "User", "green")
+	otherID := testutil.SeedTestUser(t, db, "other@example.com", "Other", "blue")
+	inactiveID := testutil.SeedTestUser(t, db, "inactive@example.com", "Inactive", "green")
+	db.Exec(`UPDATE users SET is_active = 0 WHERE id = ?`, inactiveID)
+
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	otherDogID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+
+	t.Run("set and update favourite", func(t *testing.T) {
+		favorite := &models.DogFavorite{UserID: userID, DogID: dogID}
+		if err := repo.Set(favorite); err != nil {
+			t.Fatalf("Set() failed: %v", err)
+		}
+		if favorite.ID == 0 {
+			t.Fatal("Expected favourite ID to be set")
+		}
+
+		update := &models.DogFavorite{UserID: userID, DogID: dogID, NotifyAvailable: true, NotifyFreeSlots: true}
+		if err := repo.Set(update); err != nil {
+			t.Fatalf("Set() failed: %v", err)
+		}
+		if update.ID != favorite.ID {
+			t.Errorf("Expected the existing favourite to be updated, got ID %d", update.ID)
+		}
+
+		found, err := repo.Find(userID, dogID)
+		if err != nil {
+			t.Fatalf("Find() failed: %v", err)
+		}
+		if found == nil || !found.NotifyAvailable || !found.NotifyFreeSlots {
+			t.Errorf("Expected notifications to be enabled, got %+v", found)
+		}
+	})
+
+	t.Run("list favourites of a user", func(t *testing.T) {
+		repo.Set(&models.DogFavorite{UserID: userID, DogID: otherDogID})
+		repo.Set(&models.DogFavorite{UserID: otherID, DogID: dogID})
+
+		favorites, err := repo.FindByUser(userID)
+		if err != nil {
+			t.Fatalf("FindByUser() failed: %v", err)
+		}
+		if len(favorites) != 2 {
+			t.Errorf("Expected 2 favourites, got %d", len(favorites))
+		}
+	})
+
+	t.Run("subscribers", func(t *testing.T) {
+		repo.Set(&models.DogFavorite{UserID: inactiveID, DogID: dogID, NotifyAvailable: true})
+
+		users, err := repo.FindSubscribers(dogID, models.FavoriteNotifyAvailable)
+		if err != nil {
+			t.Fatalf("FindSubscribers() failed: %v", err)
+		}
+		// Other has no notifications enabled, inactive users are never notified
+		if len(users) != 1 || users[0].ID != userID {
+			t.Errorf("Expected only the subscribed active user, got %+v", users)
+		}
+
+		users, err = repo.FindSubscribers(otherDogID, models.FavoriteNotifyFreeSlots)
+		if err != nil {
+			t.Fatalf("FindSubscribers() failed: %v", err)
+		}
+		if len(users) != 0 {
+			t.Errorf("Expected no subscribers, got %d", len(users))
+		}
+	})
+
+	t.Run("remove favourite", func(t *testing.T) {
+		if err := repo.Remove(userID, otherDogID); err != nil {
+			t.Fatalf("Remove() failed: %v", err)
+		}
+		// Idempotent
+		if err := repo.Remove(userID, otherDogID); err != nil {
+			t.Fatalf("Remove() failed: %v", err)
+		}
+
+		found, _ := repo.Find(userID, otherDogID)
+		if found != nil {
+			t.Error("Expected favourite to be removed")
+		}
+	})
+
+	t.Run("new dog subscription", func(t *testing.T) {
+		if err := repo.SetNewDogSubscription(otherID, true); err != nil {
+			t.Fatalf("SetNewDogSubscription() failed: %v", err)
+		}
+		// Subscribing twice is fine
+		if err := repo.SetNewDogSubscription(otherID, true); err != nil {
+			t.Fatalf("SetNewDogSubscription() failed: %v", err)
+		}
+
+		subscribed, _ := repo.HasNewDogSubscription(otherID)
+		if !subscribed {
+			t.Error("Expected user to be subscribed")
+		}
+
+		users, err := repo.FindNewDogSubscribers()
+		if err != nil {
+			t.Fatalf("FindNewDogSubscribers() failed: %v", err)
+		}
+		if len(users) != 1 || users[0].ID != otherID || users[0].ExperienceLevel != "blue" {
+			t.Errorf("Expected the subscribed user, got %+v", users)
+		}
+
+		repo.SetNewDogSubscription(otherID, false)
+		subscribed, _ = repo.HasNewDogSubscription(otherID)
+		if subscribed {
+			t.Error("Expected user to be unsubscribed")
+		}
+	})
+}
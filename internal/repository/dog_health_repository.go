@@ -0,0 +1,427 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// DogHealthRepository handles dog vaccination, medication, vet appointment and restriction records
+type DogHealthRepository struct {
+	db *sql.DB
+}
+
+// NewDogHealthRepository creates a new dog health repository
+func NewDogHealthRepository(db *sql.DB) *DogHealthRepository {
+	return &DogHealthRepository{db: db}
+}
+
+// dateOnly normalizes a scanned DATE column to YYYY-MM-DD (some drivers return RFC3339)
+func dateOnly(value string) string {
+	if len(value) > 10 {
+		return value[:10]
+	}
+	return value
+}
+
+// nullableDate converts a scanned nullable DATE column to an optional YYYY-MM-DD string
+func nullableDate(value sql.NullString) *string {
+	if !value.Valid || value.String == "" {
+		return nil
+	}
+	date := dateOnly(value.String)
+	return &date
+}
+
+// emptyToNil stores empty optional strings as NULL
+func emptyToNil(value *string) *string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil
+	}
+	return value
+}
+
+// checkUpdated returns a "health record not found" error if an update or delete touched no row
+func checkUpdated(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("health record not found")
+	}
+	return nil
+}
+
+// GetAll returns all health records of a dog
+func (r *DogHealthRepository) GetAll(dogID int) (*models.DogHealthRecords, error) {
+	vaccinations, err := r.FindVaccinations(dogID)
+	if err != nil {
+		return nil, err
+	}
+	medications, err := r.FindMedications(dogID)
+	if err != nil {
+		return nil, err
+	}
+	appointments, err := r.FindVetAppointments(dogID)
+	if err != nil {
+		return nil, err
+	}
+	restrictions, err := r.FindRestrictions(dogID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DogHealthRecords{
+		DogID:           dogID,
+		Vaccinations:    vaccinations,
+		Medications:     medications,
+		VetAppointments: appointments,
+		Restrictions:    restrictions,
+	}, nil
+}
+
+// CreateVaccination creates a vaccination record
+func (r *DogHealthRepository) CreateVaccination(v *models.DogVaccination) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		INSERT INTO dog_vaccinations (dog_id, name, administered_on, expires_on, notes, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, v.DogID, v.Name, emptyToNil(v.AdministeredOn), v.ExpiresOn, emptyToNil(v.Notes), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create vaccination: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get vaccination ID: %w", err)
+	}
+
+	v.ID = int(id)
+	v.CreatedAt = now
+	v.UpdatedAt = now
+	return nil
+}
+
+// UpdateVaccination updates a vaccination record
+// A changed expiry date re-arms the expiry alert
+func (r *DogHealthRepository) UpdateVaccination(v *models.DogVaccination) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE dog_vaccinations SET
+			name = ?, administered_on = ?,
+			expiry_alert_sent_at = CASE WHEN expires_on = ? THEN expiry_alert_sent_at ELSE NULL END,
+			expires_on = ?, notes = ?, updated_at = ?
+		WHERE id = ? AND dog_id = ?
+	`, v.Name, emptyToNil(v.AdministeredOn), v.ExpiresOn, v.ExpiresOn, emptyToNil(v.Notes), now, v.ID, v.DogID)
+	if err != nil {
+		return fmt.Errorf("failed to update vaccination: %w", err)
+	}
+
+	v.UpdatedAt = now
+	return checkUpdated(result)
+}
+
+// FindVaccinations returns the vaccinations of a dog, soonest expiry first
+func (r *DogHealthRepository) FindVaccinations(dogID int) ([]*models.DogVaccination, error) {
+	return r.findVaccinations("v.dog_id = ?", dogID)
+}
+
+// GetExpiringVaccinations returns vaccinations expiring on or before the date that were not alerted yet
+func (r *DogHealthRepository) GetExpiringVaccinations(until string) ([]*models.DogVaccination, error) {
+	return r.findVaccinations("v.expires_on <= ? AND v.expiry_alert_sent_at IS NULL", until)
+}
+
+// MarkVaccinationAlertSent records that the expiry alert for a vaccination was sent
+func (r *DogHealthRepository) MarkVaccinationAlertSent(id int) error {
+	_, err := r.db.Exec("UPDATE dog_vaccinations SET expiry_alert_sent_at = ? WHERE id = ?", time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark vaccination alert sent: %w", err)
+	}
+	return nil
+}
+
+// findVaccinations runs the common vaccination query with the dog name joined
+func (r *DogHealthRepository) findVaccinations(condition string, args ...interface{}) ([]*models.DogVaccination, error) {
+	rows, err := r.db.Query(`
+		SELECT v.id, v.dog_id, v.name, v.administered_on, v.expires_on, v.notes, v.created_at, v.updated_at, d.name
+		FROM dog_vaccinations v
+		JOIN dogs d ON v.dog_id = d.id
+		WHERE `+condition+`
+		ORDER BY v.expires_on ASC, v.id ASC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vaccinations: %w", err)
+	}
+	defer rows.Close()
+
+	vaccinations := []*models.DogVaccination{}
+	for rows.Next() {
+		v := &models.DogVaccination{}
+		var administeredOn sql.NullString
+		if err := rows.Scan(&v.ID, &v.DogID, &v.Name, &administeredOn, &v.ExpiresOn, &v.Notes, &v.CreatedAt, &v.UpdatedAt, &v.DogName); err != nil {
+			return nil, fmt.Errorf("failed to scan vaccination: %w", err)
+		}
+		v.AdministeredOn = nullableDate(administeredOn)
+		v.ExpiresOn = dateOnly(v.ExpiresOn)
+		vaccinations = append(vaccinations, v)
+	}
+
+	return vaccinations, nil
+}
+
+// DeleteVaccination deletes a vaccination record of a dog
+func (r *DogHealthRepository) DeleteVaccination(dogID, id int) error {
+	return r.deleteRecord("dog_vaccinations", dogID, id)
+}
+
+// CreateMedication creates a medication record
+func (r *DogHealthRepository) CreateMedication(m *models.DogMedication) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		INSERT INTO dog_medications (
+			dog_id, name, dosage, dosage_times, walk_buffer_minutes, start_date, end_date, notes, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, m.DogID, m.Name, emptyToNil(m.Dosage), strings.Join(m.DosageTimes, ","), m.WalkBufferMinutes,
+		emptyToNil(m.StartDate), emptyToNil(m.EndDate), emptyToNil(m.Notes), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create medication: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get medication ID: %w", err)
+	}
+
+	m.ID = int(id)
+	m.CreatedAt = now
+	m.UpdatedAt = now
+	return nil
+}
+
+// UpdateMedication updates a medication record
+func (r *DogHealthRepository) UpdateMedication(m *models.DogMedication) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE dog_medications SET
+			name = ?, dosage = ?, dosage_times = ?, walk_buffer_minutes = ?,
+			start_date = ?, end_date = ?, notes = ?, updated_at = ?
+		WHERE id = ? AND dog_id = ?
+	`, m.Name, emptyToNil(m.Dosage), strings.Join(m.DosageTimes, ","), m.WalkBufferMinutes,
+		emptyToNil(m.StartDate), emptyToNil(m.EndDate), emptyToNil(m.Notes), now, m.ID, m.DogID)
+	if err != nil {
+		return fmt.Errorf("failed to update medication: %w", err)
+	}
+
+	m.UpdatedAt = now
+	return checkUpdated(result)
+}
+
+// FindMedications returns the medications of a dog
+func (r *DogHealthRepository) FindMedications(dogID int) ([]*models.DogMedication, error) {
+	rows, err := r.db.Query(`
+		SELECT id, dog_id, name, dosage, dosage_times, walk_buffer_minutes, start_date, end_date, notes, created_at, updated_at
+		FROM dog_medications
+		WHERE dog_id = ?
+		ORDER BY name ASC, id ASC
+	`, dogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query medications: %w", err)
+	}
+	defer rows.Close()
+
+	medications := []*models.DogMedication{}
+	for rows.Next() {
+		m := &models.DogMedication{}
+		var dosageTimes, startDate, endDate sql.NullString
+		if err := rows.Scan(&m.ID, &m.DogID, &m.Name, &m.Dosage, &dosageTimes, &m.WalkBufferMinutes,
+			&startDate, &endDate, &m.Notes, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan medication: %w", err)
+		}
+		m.DosageTimes = []string{}
+		if dosageTimes.Valid && dosageTimes.String != "" {
+			m.DosageTimes = strings.Split(dosageTimes.String, ",")
+		}
+		m.StartDate = nullableDate(startDate)
+		m.EndDate = nullableDate(endDate)
+		medications = append(medications, m)
+	}
+
+	return medications, nil
+}
+
+// DeleteMedication deletes a medication record of a dog
+func (r *DogHealthRepository) DeleteMedication(dogID, id int) error {
+	return r.deleteRecord("dog_medications", dogID, id)
+}
+
+// CreateVetAppointment creates a vet appointment record
+func (r *DogHealthRepository) CreateVetAppointment(a *models.DogVetAppointment) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		INSERT INTO dog_vet_appointments (dog_id, appointment_date, appointment_time, vet_name, purpose, notes, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, a.DogID, a.AppointmentDate, emptyToNil(a.AppointmentTime), emptyToNil(a.VetName), a.Purpose, emptyToNil(a.Notes), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create vet appointment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get vet appointment ID: %w", err)
+	}
+
+	a.ID = int(id)
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	return nil
+}
+
+// UpdateVetAppointment updates a vet appointment record
+func (r *DogHealthRepository) UpdateVetAppointment(a *models.DogVetAppointment) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE dog_vet_appointments SET
+			appointment_date = ?, appointment_time = ?, vet_name = ?, purpose = ?, notes = ?, updated_at = ?
+		WHERE id = ? AND dog_id = ?
+	`, a.AppointmentDate, emptyToNil(a.AppointmentTime), emptyToNil(a.VetName), a.Purpose, emptyToNil(a.Notes), now, a.ID, a.DogID)
+	if err != nil {
+		return fmt.Errorf("failed to update vet appointment: %w", err)
+	}
+
+	a.UpdatedAt = now
+	return checkUpdated(result)
+}
+
+// FindVetAppointments returns the vet appointments of a dog, most recent first
+func (r *DogHealthRepository) FindVetAppointments(dogID int) ([]*models.DogVetAppointment, error) {
+	rows, err := r.db.Query(`
+		SELECT id, dog_id, appointment_date, appointment_time, vet_name, purpose, notes, created_at, updated_at
+		FROM dog_vet_appointments
+		WHERE dog_id = ?
+		ORDER BY appointment_date DESC, id DESC
+	`, dogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query vet appointments: %w", err)
+	}
+	defer rows.Close()
+
+	appointments := []*models.DogVetAppointment{}
+	for rows.Next() {
+		a := &models.DogVetAppointment{}
+		if err := rows.Scan(&a.ID, &a.DogID, &a.AppointmentDate, &a.AppointmentTime, &a.VetName, &a.Purpose,
+			&a.Notes, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan vet appointment: %w", err)
+		}
+		a.AppointmentDate = dateOnly(a.AppointmentDate)
+		appointments = append(appointments, a)
+	}
+
+	return appointments, nil
+}
+
+// DeleteVetAppointment deletes a vet appointment record of a dog
+func (r *DogHealthRepository) DeleteVetAppointment(dogID, id int) error {
+	return r.deleteRecord("dog_vet_appointments", dogID, id)
+}
+
+// CreateRestriction creates a walk restriction
+func (r *DogHealthRepository) CreateRestriction(res *models.DogRestriction) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		INSERT INTO dog_restrictions (dog_id, restriction, max_walk_minutes, valid_from, valid_until, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, res.DogID, res.Restriction, res.MaxWalkMinutes, emptyToNil(res.ValidFrom), emptyToNil(res.ValidUntil), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create restriction: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get restriction ID: %w", err)
+	}
+
+	res.ID = int(id)
+	res.CreatedAt = now
+	res.UpdatedAt = now
+	return nil
+}
+
+// UpdateRestriction updates a walk restriction
+func (r *DogHealthRepository) UpdateRestriction(res *models.DogRestriction) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE dog_restrictions SET
+			restriction = ?, max_walk_minutes = ?, valid_from = ?, valid_until = ?, updated_at = ?
+		WHERE id = ? AND dog_id = ?
+	`, res.Restriction, res.MaxWalkMinutes, emptyToNil(res.ValidFrom), emptyToNil(res.ValidUntil), now, res.ID, res.DogID)
+	if err != nil {
+		return fmt.Errorf("failed to update restriction: %w", err)
+	}
+
+	res.UpdatedAt = now
+	return checkUpdated(result)
+}
+
+// FindRestrictions returns all walk restrictions of a dog
+func (r *DogHealthRepository) FindRestrictions(dogID int) ([]*models.DogRestriction, error) {
+	rows, err := r.db.Query(`
+		SELECT id, dog_id, restriction, max_walk_minutes, valid_from, valid_until, created_at, updated_at
+		FROM dog_restrictions
+		WHERE dog_id = ?
+		ORDER BY id ASC
+	`, dogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query restrictions: %w", err)
+	}
+	defer rows.Close()
+
+	restrictions := []*models.DogRestriction{}
+	for rows.Next() {
+		res := &models.DogRestriction{}
+		var validFrom, validUntil sql.NullString
+		if err := rows.Scan(&res.ID, &res.DogID, &res.Restriction, &res.MaxWalkMinutes, &validFrom, &validUntil,
+			&res.CreatedAt, &res.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan restriction: %w", err)
+		}
+		res.ValidFrom = nullableDate(validFrom)
+		res.ValidUntil = nullableDate(validUntil)
+		restrictions = append(restrictions, res)
+	}
+
+	return restrictions, nil
+}
+
+// GetActiveRestrictions returns the restrictions of a dog that apply on the date (YYYY-MM-DD)
+func (r *DogHealthRepository) GetActiveRestrictions(dogID int, date string) ([]*models.DogRestriction, error) {
+	restrictions, err := r.FindRestrictions(dogID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := []*models.DogRestriction{}
+	for _, res := range restrictions {
+		if res.IsActiveOn(date) {
+			active = append(active, res)
+		}
+	}
+
+	return active, nil
+}
+
+// DeleteRestriction deletes a walk restriction of a dog
+func (r *DogHealthRepository) DeleteRestriction(dogID, id int) error {
+	return r.deleteRecord("dog_restrictions", dogID, id)
+}
+
+// deleteRecord deletes a record of one of the health tables, scoped to the dog
+func (r *DogHealthRepository) deleteRecord(table string, dogID, id int) error {
+	result, err := r.db.Exec("DELETE FROM "+table+" WHERE id = ? AND dog_id = ?", id, dogID)
+	if err != nil {
+		return fmt.Errorf("failed to delete health record: %w", err)
+	}
+	return checkUpdated(result)
+}
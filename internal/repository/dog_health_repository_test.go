@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogHealthRepository_Vaccinations tests vaccination records and expiry alerts
+func TestDogHealthRepository_Vaccinations(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewDogHealthRepository(db)
+
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	otherDogID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+
+	administered := "2024-11-01"
+	soon := &models.DogVaccination{DogID: dogID, Name: "Tollwut", AdministeredOn: &administered, ExpiresOn: "2025-11-20"}
+	later := &models.DogVaccination{DogID: dogID, Name: "Staupe", ExpiresOn: "2026-06-01"}
+
+	t.Run("create and list", func(t *testing.T) {
+		if err := repo.CreateVaccination(soon); err != nil {
+			t.Fatalf("CreateVaccination() failed: %v", err)
+		}
+		if err := repo.CreateVaccination(later); err != nil {
+			t.Fatalf("CreateVaccination() failed: %v", err)
+		}
+
+		vaccinations, err := repo.FindVaccinations(dogID)
+		if err != nil {
+			t.Fatalf("FindVaccinations() failed: %v", err)
+		}
+		if len(vaccinations) != 2 {
+			t.Fatalf("Expected 2 vaccinations, got %d", len(vaccinations))
+		}
+		if vaccinations[0].Name != "Tollwut" || vaccinations[0].ExpiresOn != "2025-11-20" {
+			t.Errorf("Expected soonest expiry first with YYYY-MM-DD date, got %s %s", vaccinations[0].Name, vaccinations[0].ExpiresOn)
+		}
+		if vaccinations[0].AdministeredOn == nil || *vaccinations[0].AdministeredOn != administered {
+			t.Errorf("Expected administered date %s, got %v", administered, vaccinations[0].AdministeredOn)
+		}
+	})
+
+	t.Run("expiring vaccinations are alerted once", func(t *testing.T) {
+		expiring, err := repo.GetExpiringVaccinations("2025-12-01")
+		if err != nil {
+			t.Fatalf("GetExpiringVaccinations() failed: %v", err)
+		}
+		if len(expiring) != 1 || expiring[0].ID != soon.ID || expiring[0].DogName != "Bella" {
+			t.Fatalf("Expected only the soon expiring vaccination, got %d", len(expiring))
+		}
+
+		repo.MarkVaccinationAlertSent(soon.ID)
+		expiring, _ = repo.GetExpiringVaccinations("2025-12-01")
+		if len(expiring) != 0 {
+			t.Errorf("Expected no pending alerts after marking, got %d", len(expiring))
+		}
+
+		// Renewing the vaccination re-arms the alert
+		soon.ExpiresOn = "2025-11-25"
+		if err := repo.UpdateVaccination(soon); err != nil {
+			t.Fatalf("UpdateVaccination() failed: %v", err)
+		}
+		expiring, _ = repo.GetExpiringVaccinations("2025-12-01")
+		if len(expiring) != 1 {
+			t.Errorf("Expected alert to be re-armed after expiry change, got %d", len(expiring))
+		}
+	})
+
+	t.Run("records are scoped to the dog", func(t *testing.T) {
+		wrongDog := &models.DogVaccination{ID: soon.ID, DogID: otherDogID, Name: "Tollwut", ExpiresOn: "2026-01-01"}
+		if err := repo.UpdateVaccination(wrongDog); err == nil || err.Error() != "health record not found" {
+			t.Errorf("Expected not found for other dog, got %v", err)
+		}
+		if err := repo.DeleteVaccination(otherDogID, soon.ID); err == nil {
+			t.Error("Expected error deleting another dog's vaccination")
+		}
+		if err := repo.DeleteVaccination(dogID, soon.ID); err != nil {
+			t.Errorf("DeleteVaccination() failed: %v", err)
+		}
+	})
+}
+
+// DONE: TestDogHealthRepository_MedicationsAndRestrictions tests medications, appointments and restrictions
+func TestDogHealthRepository_MedicationsAndRestrictions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewDogHealthRepository(db)
+
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+
+	t.Run("medication dosage times round trip", func(t *testing.T) {
+		dosage := "2 IE"
+		medication := &models.DogMedication{DogID: dogID, Name: "Insulin", Dosage: &dosage, DosageTimes: []string{"08:00", "18:00"}, WalkBufferMinutes: 30}
+		if err := repo.CreateMedication(medication); err != nil {
+			t.Fatalf("CreateMedication() failed: %v", err)
+		}
+
+		medications, err := repo.FindMedications(dogID)
+		if err != nil {
+			t.Fatalf("FindMedications() failed: %v", err)
+		}
+		if len(medications) != 1 || len(medications[0].DosageTimes) != 2 || medications[0].DosageTimes[1] != "18:00" {
+			t.Errorf("Expected dosage times to round trip, got %+v", medications)
+		}
+	})
+
+	t.Run("vet appointments", func(t *testing.T) {
+		appointment := &models.DogVetAppointment{DogID: dogID, AppointmentDate: "2025-11-10", Purpose: "Jahreskontrolle"}
+		if err := repo.CreateVetAppointment(appointment); err != nil {
+			t.Fatalf("CreateVetAppointment() failed: %v", err)
+		}
+
+		appointment.Purpose = "Zahnkontrolle"
+		if err := repo.UpdateVetAppointment(appointment); err != nil {
+			t.Fatalf("UpdateVetAppointment() failed: %v", err)
+		}
+
+		appointments, _ := repo.FindVetAppointments(dogID)
+		if len(appointments) != 1 || appointments[0].Purpose != "Zahnkontrolle" || appointments[0].AppointmentDate != "2025-11-10" {
+			t.Errorf("Expected updated appointment, got %+v", appointments)
+		}
+	})
+
+	t.Run("active restrictions", func(t *testing.T) {
+		until := "2025-11-30"
+		minutes := 20
+		repo.CreateRestriction(&models.DogRestriction{DogID: dogID, Restriction: "Keine Treppen"})
+		repo.CreateRestriction(&models.DogRestriction{DogID: dogID, Restriction: "Nur kurze Runden", MaxWalkMinutes: &minutes, ValidUntil: &until})
+
+		active, err := repo.GetActiveRestrictions(dogID, "2025-11-15")
+		if err != nil {
+			t.Fatalf("GetActiveRestrictions() failed: %v", err)
+		}
+		if len(active) != 2 {
+			t.Errorf("Expected 2 active restrictions, got %d", len(active))
+		}
+
+		active, _ = repo.GetActiveRestrictions(dogID, "2025-12-15")
+		if len(active) != 1 || active[0].Restriction != "Keine Treppen" {
+			t.Errorf("Expected only the permanent restriction after expiry, got %d", len(active))
+		}
+	})
+
+	t.Run("get all records", func(t *testing.T) {
+		records, err := repo.GetAll(dogID)
+		if err != nil {
+			t.Fatalf("GetAll() failed: %v", err)
+		}
+		if len(records.Medications) != 1 || len(records.VetAppointments) != 1 || len(records.Restrictions) != 2 || len(records.Vaccinations) != 0 {
+			t.Errorf("Unexpected record counts: %+v", records)
+		}
+	})
+}
@@ -0,0 +1,375 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// DogPhotoRepository handles dog photo gallery database operations
+// The cover photo is kept in sync with dogs.photo / dogs.photo_thumbnail
+type DogPhotoRepository struct {
+	db *sql.DB
+}
+
+// NewDogPhotoRepository creates a new dog photo repository
+func NewDogPhotoRepository(db *sql.DB) *DogPhotoRepository {
+	return &DogPhotoRepository{db: db}
+}
+
+// FindByDog returns the gallery of a dog in display order
+func (r *DogPhotoRepository) FindByDog(dogID int) ([]*models.DogPhoto, error) {
+	if err := r.adoptLegacyCover(dogID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, dog_id, photo, photo_thumbnail, caption, sort_order, is_cover, created_at
+		FROM dog_photos
+		WHERE dog_id = ?
+		ORDER BY sort_order ASC, id ASC
+	`, dogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dog photos: %w", err)
+	}
+	defer rows.Close()
+
+	photos := []*models.DogPhoto{}
+	for rows.Next() {
+		photo := &models.DogPhoto{}
+		err := rows.Scan(
+			&photo.ID,
+			&photo.DogID,
+			&photo.Photo,
+			&photo.PhotoThumbnail,
+			&photo.Caption,
+			&photo.SortOrder,
+			&photo.IsCover,
+			&photo.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dog photo: %w", err)
+		}
+		photos = append(photos, photo)
+	}
+
+	return photos, nil
+}
+
+// FindByID finds a photo of a dog by ID
+func (r *DogPhotoRepository) FindByID(dogID, id int) (*models.DogPhoto, error) {
+	photo := &models.DogPhoto{}
+	err := r.db.QueryRow(`
+		SELECT id, dog_id, photo, photo_thumbnail, caption, sort_order, is_cover, created_at
+		FROM dog_photos
+		WHERE id = ? AND dog_id = ?
+	`, id, dogID).Scan(
+		&photo.ID,
+		&photo.DogID,
+		&photo.Photo,
+		&photo.PhotoThumbnail,
+		&photo.Caption,
+		&photo.SortOrder,
+		&photo.IsCover,
+		&photo.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dog photo: %w", err)
+	}
+
+	return photo, nil
+}
+
+// Count returns the number of photos in the gallery of a dog
+func (r *DogPhotoRepository) Count(dogID int) (int, error) {
+	if err := r.adoptLegacyCover(dogID); err != nil {
+		return 0, err
+	}
+
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM dog_photos WHERE dog_id = ?`, dogID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count dog photos: %w", err)
+	}
+	return count, nil
+}
+
+// Create appends a photo to the end of the gallery
+// The first photo of a gallery becomes the cover
+func (r *DogPhotoRepository) Create(photo *models.DogPhoto) error {
+	if err := r.adoptLegacyCover(photo.DogID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var maxOrder sql.NullInt64
+	var covers int
+	err = tx.QueryRow(`SELECT MAX(sort_order), COUNT(CASE WHEN is_cover = ? THEN 1 END) FROM dog_photos WHERE dog_id = ?`,
+		true, photo.DogID).Scan(&maxOrder, &covers)
+	if err != nil {
+		return fmt.Errorf("failed to check dog photos: %w", err)
+	}
+
+	photo.SortOrder = 0
+	if maxOrder.Valid {
+		photo.SortOrder = int(maxOrder.Int64) + 1
+	}
+	photo.IsCover = covers == 0
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		INSERT INTO dog_photos (dog_id, photo, photo_thumbnail, caption, sort_order, is_cover, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, photo.DogID, photo.Photo, photo.PhotoThumbnail, photo.Caption, photo.SortOrder, photo.IsCover, now)
+	if err != nil {
+		return fmt.Errorf("failed to create dog photo: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get dog photo ID: %w", err)
+	}
+	photo.ID = int(id)
+	photo.CreatedAt = now
+
+	if photo.IsCover {
+		if err := syncDogCover(tx, photo.DogID, photo); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit dog photo: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCaption changes the caption of a photo
+func (r *DogPhotoRepository) UpdateCaption(dogID, id int, caption *string) error {
+	result, err := r.db.Exec(`UPDATE dog_photos SET caption = ? WHERE id = ? AND dog_id = ?`, caption, id, dogID)
+	if err != nil {
+		return fmt.Errorf("failed to update dog photo: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("dog photo not found")
+	}
+
+	return nil
+}
+
+// SetCover makes a photo the cover of the gallery and mirrors it into the dog
+func (r *DogPhotoRepository) SetCover(dogID, id int) error {
+	photo, err := r.FindByID(dogID, id)
+	if err != nil {
+		return err
+	}
+	if photo == nil {
+		return fmt.Errorf("dog photo not found")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := syncDogCover(tx, dogID, photo); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit cover photo: %w", err)
+	}
+
+	return nil
+}
+
+// Reorder sets the display order of the gallery
+// photoIDs must contain every photo of the dog exactly once
+func (r *DogPhotoRepository) Reorder(dogID int, photoIDs []int) error {
+	photos, err := r.FindByDog(dogID)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[int]bool, len(photos))
+	for _, photo := range photos {
+		existing[photo.ID] = true
+	}
+	if len(photoIDs) != len(existing) {
+		return fmt.Errorf("photo IDs do not match gallery")
+	}
+	for _, id := range photoIDs {
+		if !existing[id] {
+			return fmt.Errorf("photo IDs do not match gallery")
+		}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, id := range photoIDs {
+		if _, err := tx.Exec(`UPDATE dog_photos SET sort_order = ? WHERE id = ? AND dog_id = ?`, i, id, dogID); err != nil {
+			return fmt.Errorf("failed to reorder dog photos: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit photo order: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a photo from the gallery and returns it so the files can be deleted
+// If it was the cover, the next photo in order becomes the cover
+func (r *DogPhotoRepository) Delete(dogID, id int) (*models.DogPhoto, error) {
+	photo, err := r.FindByID(dogID, id)
+	if err != nil {
+		return nil, err
+	}
+	if photo == nil {
+		return nil, fmt.Errorf("dog photo not found")
+	}
+
+	photos, err := r.FindByDog(dogID)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextCover *models.DogPhoto
+	for _, other := range photos {
+		if other.ID != id {
+			nextCover = other
+			break
+		}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM dog_photos WHERE id = ? AND dog_id = ?`, id, dogID); err != nil {
+		return nil, fmt.Errorf("failed to delete dog photo: %w", err)
+	}
+
+	if photo.IsCover {
+		if err := syncDogCover(tx, dogID, nextCover); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit dog photo deletion: %w", err)
+	}
+
+	return photo, nil
+}
+
+// ReplaceCover points the cover of the gallery to new files, adding a cover if there is none
+// Used by the single photo upload, which overwrites the cover of the dog
+func (r *DogPhotoRepository) ReplaceCover(dogID int, photo string, thumbnail *string) error {
+	if err := r.adoptLegacyCover(dogID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`UPDATE dog_photos SET photo = ?, photo_thumbnail = ? WHERE dog_id = ? AND is_cover = ?`,
+		photo, thumbnail, dogID, true)
+	if err != nil {
+		return fmt.Errorf("failed to replace cover photo: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows > 0 {
+		return nil
+	}
+
+	return r.Create(&models.DogPhoto{DogID: dogID, Photo: photo, PhotoThumbnail: thumbnail})
+}
+
+// adoptLegacyCover adds the photo of a dog from before the gallery existed as its cover
+// Does nothing if the dog already has gallery photos or no photo at all
+func (r *DogPhotoRepository) adoptLegacyCover(dogID int) error {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM dog_photos WHERE dog_id = ?`, dogID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count dog photos: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	var photo, thumbnail sql.NullString
+	err := r.db.QueryRow(`SELECT photo, photo_thumbnail FROM dogs WHERE id = ?`, dogID).Scan(&photo, &thumbnail)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get dog photo: %w", err)
+	}
+	if !photo.Valid || photo.String == "" {
+		return nil
+	}
+
+	var thumbnailPtr *string
+	if thumbnail.Valid && thumbnail.String != "" {
+		thumbnailPtr = &thumbnail.String
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO dog_photos (dog_id, photo, photo_thumbnail, sort_order, is_cover, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, dogID, photo.String, thumbnailPtr, 0, true, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to adopt dog photo: %w", err)
+	}
+
+	return nil
+}
+
+// syncDogCover marks cover as the only cover of the gallery and mirrors it into the dog
+// A nil cover clears the photo of the dog
+func syncDogCover(tx *sql.Tx, dogID int, cover *models.DogPhoto) error {
+	if _, err := tx.Exec(`UPDATE dog_photos SET is_cover = ? WHERE dog_id = ?`, false, dogID); err != nil {
+		return fmt.Errorf("failed to reset cover photo: %w", err)
+	}
+
+	var photo, thumbnail *string
+	if cover != nil {
+		if _, err := tx.Exec(`UPDATE dog_photos SET is_cover = ? WHERE id = ?`, true, cover.ID); err != nil {
+			return fmt.Errorf("failed to set cover photo: %w", err)
+		}
+		cover.IsCover = true
+		photo = &cover.Photo
+		thumbnail = cover.PhotoThumbnail
+	}
+
+	if _, err := tx.Exec(`UPDATE dogs SET photo = ?, photo_thumbnail = ?, updated_at = ? WHERE id = ?`,
+		photo, thumbnail, time.Now(), dogID); err != nil {
+		return fmt.Errorf("failed to update dog cover photo: %w", err)
+	}
+
+	return nil
+}
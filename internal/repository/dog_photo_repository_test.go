@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogPhotoRepository_LegacyCover tests that a photo from before the gallery becomes its cover
+func TestDogPhotoRepository_LegacyCover(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewDogPhotoRepository(db)
+
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	if _, err := db.Exec(`UPDATE dogs SET photo = ?, photo_thumbnail = ? WHERE id = ?`,
+		"dogs/dog_1_full.jpg", "dogs/dog_1_thumb.jpg", dogID); err != nil {
+		t.Fatalf("Failed to set dog photo: %v", err)
+	}
+
+	t.Run("adopted on first use", func(t *testing.T) {
+		photos, err := repo.FindByDog(dogID)
+		if err != nil {
+			t.Fatalf("FindByDog() failed: %v", err)
+		}
+		if len(photos) != 1 || !photos[0].IsCover || photos[0].Photo != "dogs/dog_1_full.jpg" {
+			t.Fatalf("Expected the legacy photo as cover, got %+v", photos)
+		}
+
+		// Not adopted twice
+		count, err := repo.Count(dogID)
+		if err != nil {
+			t.Fatalf("Count() failed: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected 1 photo, got %d", count)
+		}
+	})
+
+	t.Run("new photos are appended after the cover", func(t *testing.T) {
+		thumb := "dogs/dog_1_abc_thumb.jpg"
+		photo := &models.DogPhoto{DogID: dogID, Photo: "dogs/dog_1_abc_full.jpg", PhotoThumbnail: &thumb}
+		if err := repo.Create(photo); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		if photo.IsCover || photo.SortOrder != 1 {
+			t.Errorf("Expected non-cover photo at position 1, got cover=%v order=%d", photo.IsCover, photo.SortOrder)
+		}
+	})
+
+	t.Run("replace cover keeps gallery in sync", func(t *testing.T) {
+		thumb := "dogs/dog_1_thumb.jpg"
+		if err := repo.ReplaceCover(dogID, "dogs/dog_1_full.jpg", &thumb); err != nil {
+			t.Fatalf("ReplaceCover() failed: %v", err)
+		}
+
+		photos, _ := repo.FindByDog(dogID)
+		if len(photos) != 2 {
+			t.Fatalf("Expected 2 photos, got %d", len(photos))
+		}
+		if !photos[0].IsCover || photos[1].IsCover {
+			t.Error("Expected exactly the first photo as cover")
+		}
+	})
+}
+
+// DONE: TestDogPhotoRepository_ReplaceCoverWithoutGallery tests the single photo upload on an empty gallery
+func TestDogPhotoRepository_ReplaceCoverWithoutGallery(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewDogPhotoRepository(db)
+
+	dogID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+
+	thumb := "dogs/dog_2_thumb.jpg"
+	if err := repo.ReplaceCover(dogID, "dogs/dog_2_full.jpg", &thumb); err != nil {
+		t.Fatalf("ReplaceCover() failed: %v", err)
+	}
+
+	photos, err := repo.FindByDog(dogID)
+	if err != nil {
+		t.Fatalf("FindByDog() failed: %v", err)
+	}
+	if len(photos) != 1 || !photos[0].IsCover {
+		t.Fatalf("Expected one cover photo, got %d", len(photos))
+	}
+
+	var photo string
+	db.QueryRow(`SELECT photo FROM dogs WHERE id = ?`, dogID).Scan(&photo)
+	if photo != "dogs/dog_2_full.jpg" {
+		t.Errorf("Expected dog photo to be the cover, got %q", photo)
+	}
+}
@@ -20,17 +20,35 @@ func NewDogRepository(db *sql.DB) *DogRepository {
 	return &DogRepository{db: db}
 }
 
+// dogExecer runs the writes of a dog on the database or inside a transaction
+type dogExecer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
 // Create creates a new dog
 func (r *DogRepository) Create(dog *models.Dog) error {
+	return createDog(r.db, dog)
+}
+
+// createDog inserts a new dog
+func createDog(db dogExecer, dog *models.Dog) error {
+	if dog.MinWalkers < 1 {
+		dog.MinWalkers = 1
+	}
+	if dog.Status == "" {
+		dog.Status = models.DogStatusAvailable
+	}
+
 	query := `
 		INSERT INTO dogs (
 			name, breed, size, age, category, photo, photo_thumbnail, special_needs,
 			pickup_location, walk_route, walk_duration, special_instructions,
-			default_morning_time, default_evening_time, is_available, external_link
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			default_morning_time, default_evening_time, is_available, external_link,
+			min_walkers, min_co_walker_level, requires_staff_escort, status, external_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := r.db.Exec(
+	result, err := db.Exec(
 		query,
 		dog.Name,
 		dog.Breed,
@@ -48,6 +66,11 @@ func (r *DogRepository) Create(dog *models.Dog) error {
 		dog.DefaultEveningTime,
 		dog.IsAvailable,
 		dog.ExternalLink,
+		dog.MinWalkers,
+		dog.MinCoWalkerLevel,
+		dog.RequiresStaffEscort,
+		dog.Status,
+		dog.ExternalID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create dog: %w", err)
@@ -70,7 +93,9 @@ func (r *DogRepository) FindByID(id int) (*models.Dog, error) {
 		SELECT id, name, breed, size, age, category, photo, photo_thumbnail, special_needs,
 		       pickup_location, walk_route, walk_duration, special_instructions,
 		       default_morning_time, default_evening_time, is_available, is_featured,
-		       external_link, unavailable_reason, unavailable_since, created_at, updated_at
+		       external_link, unavailable_reason, unavailable_since,
+		       min_walkers, min_co_walker_level, requires_staff_escort, status, status_changed_at,
+		       external_id, created_at, updated_at
 		FROM dogs
 		WHERE id = ?
 	`
@@ -97,6 +122,12 @@ func (r *DogRepository) FindByID(id int) (*models.Dog, error) {
 		&dog.ExternalLink,
 		&dog.UnavailableReason,
 		&dog.UnavailableSince,
+		&dog.MinWalkers,
+		&dog.MinCoWalkerLevel,
+		&dog.RequiresStaffEscort,
+		&dog.Status,
+		&dog.StatusChangedAt,
+		&dog.ExternalID,
 		&dog.CreatedAt,
 		&dog.UpdatedAt,
 	)
@@ -111,13 +142,28 @@ func (r *DogRepository) FindByID(id int) (*models.Dog, error) {
 	return dog, nil
 }
 
+// FindByExternalID returns the ID of the dog with the given external ID, or 0 if there is none
+func (r *DogRepository) FindByExternalID(externalID string) (int, error) {
+	var id int
+	err := r.db.QueryRow(`SELECT id FROM dogs WHERE external_id = ?`, externalID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find dog by external ID: %w", err)
+	}
+	return id, nil
+}
+
 // FindAll finds all dogs with optional filtering
 func (r *DogRepository) FindAll(filter *models.DogFilterRequest) ([]*models.Dog, error) {
 	query := `
 		SELECT id, name, breed, size, age, category, photo, photo_thumbnail, special_needs,
 		       pickup_location, walk_route, walk_duration, special_instructions,
 		       default_morning_time, default_evening_time, is_available, is_featured,
-		       external_link, unavailable_reason, unavailable_since, created_at, updated_at
+		       external_link, unavailable_reason, unavailable_since,
+		       min_walkers, min_co_walker_level, requires_staff_escort, status, status_changed_at,
+		       external_id, created_at, updated_at
 		FROM dogs
 		WHERE 1=1
 	`
@@ -156,11 +202,41 @@ func (r *DogRepository) FindAll(filter *models.DogFilterRequest) ([]*models.Dog,
 			args = append(args, *filter.Available)
 		}
 
+		if len(filter.Statuses) > 0 {
+			query += " AND status IN (" + placeholders(len(filter.Statuses)) + ")"
+			for _, status := range filter.Statuses {
+				args = append(args, status)
+			}
+		}
+
 		if filter.Search != nil && *filter.Search != "" {
 			query += " AND (LOWER(name) LIKE LOWER(?) OR LOWER(breed) LIKE LOWER(?))"
 			searchTerm := "%" + *filter.Search + "%"
 			args = append(args, searchTerm, searchTerm)
 		}
+
+		if len(filter.TagIDs) > 0 {
+			query += ` AND id IN (
+				SELECT dog_id FROM dog_tag_assignments
+				WHERE tag_id IN (` + placeholders(len(filter.TagIDs)) + `)
+				GROUP BY dog_id
+				HAVING COUNT(*) = ?
+			)`
+			for _, tagID := range filter.TagIDs {
+				args = append(args, tagID)
+			}
+			args = append(args, len(filter.TagIDs))
+		}
+
+		if len(filter.ExcludeTagIDs) > 0 {
+			query += ` AND id NOT IN (
+				SELECT dog_id FROM dog_tag_assignments
+				WHERE tag_id IN (` + placeholders(len(filter.ExcludeTagIDs)) + `)
+			)`
+			for _, tagID := range filter.ExcludeTagIDs {
+				args = append(args, tagID)
+			}
+		}
 	}
 
 	query += " ORDER BY name ASC"
@@ -195,6 +271,12 @@ func (r *DogRepository) FindAll(filter *models.DogFilterRequest) ([]*models.Dog,
 			&dog.ExternalLink,
 			&dog.UnavailableReason,
 			&dog.UnavailableSince,
+			&dog.MinWalkers,
+			&dog.MinCoWalkerLevel,
+			&dog.RequiresStaffEscort,
+			&dog.Status,
+			&dog.StatusChangedAt,
+			&dog.ExternalID,
 			&dog.CreatedAt,
 			&dog.UpdatedAt,
 		)
@@ -208,15 +290,17 @@ func (r *DogRepository) FindAll(filter *models.DogFilterRequest) ([]*models.Dog,
 }
 
 // GetFeatured returns up to 3 randomly selected featured dogs that are available
-// If more than 3 dogs are featured, a random selection of 3 is returned
+// and still looking for a home. If more than 3 dogs are featured, a random selection of 3 is returned
 func (r *DogRepository) GetFeatured() ([]*models.Dog, error) {
 	query := `
 		SELECT id, name, breed, size, age, category, photo, photo_thumbnail, special_needs,
 		       pickup_location, walk_route, walk_duration, special_instructions,
 		       default_morning_time, default_evening_time, is_available, is_featured,
-		       external_link, unavailable_reason, unavailable_since, created_at, updated_at
+		       external_link, unavailable_reason, unavailable_since,
+		       min_walkers, min_co_walker_level, requires_staff_escort, status, status_changed_at,
+		       external_id, created_at, updated_at
 		FROM dogs
-		WHERE is_featured = 1 AND is_available = 1
+		WHERE is_featured = 1 AND is_available = 1 AND status = 'available'
 		ORDER BY name ASC
 	`
 
@@ -250,6 +334,12 @@ func (r *DogRepository) GetFeatured() ([]*models.Dog, error) {
 			&dog.ExternalLink,
 			&dog.UnavailableReason,
 			&dog.UnavailableSince,
+			&dog.MinWalkers,
+			&dog.MinCoWalkerLevel,
+			&dog.RequiresStaffEscort,
+			&dog.Status,
+			&dog.StatusChangedAt,
+			&dog.ExternalID,
 			&dog.CreatedAt,
 			&dog.UpdatedAt,
 		)
@@ -299,6 +389,11 @@ func (r *DogRepository) CountFeatured() (int, error) {
 
 // Update updates a dog
 func (r *DogRepository) Update(dog *models.Dog) error {
+	return updateDog(r.db, dog)
+}
+
+// updateDog writes all fields of an existing dog
+func updateDog(db dogExecer, dog *models.Dog) error {
 	query := `
 		UPDATE dogs SET
 			name = ?,
@@ -319,11 +414,15 @@ func (r *DogRepository) Update(dog *models.Dog) error {
 			external_link = ?,
 			unavailable_reason = ?,
 			unavailable_since = ?,
+			min_walkers = ?,
+			min_co_walker_level = ?,
+			requires_staff_escort = ?,
+			external_id = ?,
 			updated_at = ?
 		WHERE id = ?
 	`
 
-	_, err := r.db.Exec(
+	_, err := db.Exec(
 		query,
 		dog.Name,
 		dog.Breed,
@@ -343,6 +442,10 @@ func (r *DogRepository) Update(dog *models.Dog) error {
 		dog.ExternalLink,
 		dog.UnavailableReason,
 		dog.UnavailableSince,
+		dog.MinWalkers,
+		dog.MinCoWalkerLevel,
+		dog.RequiresStaffEscort,
+		dog.ExternalID,
 		time.Now(),
 		dog.ID,
 	)
@@ -354,6 +457,32 @@ func (r *DogRepository) Update(dog *models.Dog) error {
 	return nil
 }
 
+// Import creates the dogs without ID and updates the others in one transaction, so an import
+// is written completely or not at all
+func (r *DogRepository) Import(dogs []*models.Dog) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, dog := range dogs {
+		if dog.ID == 0 {
+			err = createDog(tx, dog)
+		} else {
+			err = updateDog(tx, dog)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import: %w", err)
+	}
+	return nil
+}
+
 // Delete deletes a dog (only if no future bookings exist)
 func (r *DogRepository) Delete(id int) error {
 	// Check for future bookings
@@ -416,6 +545,36 @@ func (r *DogRepository) GetFutureBookings(dogID int) ([]*models.Booking, error)
 	}
 	defer rows.Close()
 
+	return scanFutureBookings(rows)
+}
+
+// GetFutureBookingsWithDog returns all future bookings the dog takes part in with user details
+// Unlike GetFutureBookings this includes group walks where the dog is an additional dog
+func (r *DogRepository) GetFutureBookingsWithDog(dogID int) ([]*models.Booking, error) {
+	currentDate := time.Now().Format("2006-01-02")
+	query := `
+		SELECT
+			b.id, b.user_id, b.dog_id, b.date, b.scheduled_time, b.status,
+			b.completed_at, b.user_notes, b.admin_cancellation_reason, b.created_at, b.updated_at,
+			u.name as user_name, u.email as user_email
+		FROM bookings b
+		LEFT JOIN users u ON b.user_id = u.id
+		WHERE (b.dog_id = ? OR b.id IN (SELECT booking_id FROM booking_dogs WHERE dog_id = ?))
+		  AND b.date >= ? AND b.status = 'scheduled'
+		ORDER BY b.date ASC, b.scheduled_time ASC
+	`
+
+	rows, err := r.db.Query(query, dogID, dogID, currentDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query future bookings: %w", err)
+	}
+	defer rows.Close()
+
+	return scanFutureBookings(rows)
+}
+
+// scanFutureBookings scans bookings joined with the name and email of the user
+func scanFutureBookings(rows *sql.Rows) ([]*models.Booking, error) {
 	bookings := []*models.Booking{}
 	for rows.Next() {
 		booking := &models.Booking{
@@ -497,6 +656,80 @@ func (r *DogRepository) ToggleAvailability(id int, isAvailable bool, reason *str
 	return nil
 }
 
+// SetStatus changes the lifecycle status of a dog and records the change in its history
+func (r *DogRepository) SetStatus(change *models.DogStatusChange) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.Exec(
+		`UPDATE dogs SET status = ?, status_changed_at = ?, updated_at = ? WHERE id = ?`,
+		change.Status, now, now, change.DogID,
+	); err != nil {
+		return fmt.Errorf("failed to set dog status: %w", err)
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO dog_status_changes (dog_id, status, note, changed_by, cancelled_bookings, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		change.DogID, change.Status, change.Note, change.ChangedBy, change.CancelledBookings, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit status change: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get status change ID: %w", err)
+	}
+	change.ID = int(id)
+	change.CreatedAt = now
+	return nil
+}
+
+// GetStatusHistory returns the lifecycle history of a dog, newest first
+func (r *DogRepository) GetStatusHistory(dogID int) ([]*models.DogStatusChange, error) {
+	query := `
+		SELECT c.id, c.dog_id, c.status, c.note, c.changed_by, u.name, c.cancelled_bookings, c.created_at
+		FROM dog_status_changes c
+		LEFT JOIN users u ON c.changed_by = u.id
+		WHERE c.dog_id = ?
+		ORDER BY c.created_at DESC, c.id DESC
+	`
+
+	rows, err := r.db.Query(query, dogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*models.DogStatusChange{}
+	for rows.Next() {
+		change := &models.DogStatusChange{}
+		if err := rows.Scan(
+			&change.ID,
+			&change.DogID,
+			&change.Status,
+			&change.Note,
+			&change.ChangedBy,
+			&change.ChangedByName,
+			&change.CancelledBookings,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
 // GetBreeds returns a list of unique breeds
 func (r *DogRepository) GetBreeds() ([]string, error) {
 	query := `SELECT DISTINCT breed FROM dogs ORDER BY breed ASC`
@@ -519,6 +752,89 @@ func (r *DogRepository) GetBreeds() ([]string, error) {
 	return breeds, nil
 }
 
+// GetCompatibleDogIDs returns the IDs of all dogs that may be walked together with the given dog
+func (r *DogRepository) GetCompatibleDogIDs(dogID int) ([]int, error) {
+	query := `
+		SELECT compatible_dog_id FROM dog_compatibility WHERE dog_id = ?
+		UNION
+		SELECT dog_id FROM dog_compatibility WHERE compatible_dog_id = ?
+		ORDER BY 1
+	`
+
+	rows, err := r.db.Query(query, dogID, dogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query compatible dogs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan compatible dog: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// SetCompatibleDogs replaces the "can walk with" rules of a dog
+// Compatibility is symmetric, so each pair is stored once with the lower ID first
+func (r *DogRepository) SetCompatibleDogs(dogID int, compatibleDogIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM dog_compatibility WHERE dog_id = ? OR compatible_dog_id = ?`, dogID, dogID); err != nil {
+		return fmt.Errorf("failed to clear compatibility: %w", err)
+	}
+
+	now := time.Now()
+	for _, otherID := range compatibleDogIDs {
+		if otherID == dogID {
+			continue
+		}
+		low, high := dogID, otherID
+		if low > high {
+			low, high = high, low
+		}
+		if _, err := tx.Exec(`INSERT INTO dog_compatibility (dog_id, compatible_dog_id, created_at) VALUES (?, ?, ?)`,
+			low, high, now); err != nil {
+			return fmt.Errorf("failed to add compatibility: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit compatibility: %w", err)
+	}
+
+	return nil
+}
+
+// AreDogsCompatible checks if two dogs may be walked together
+func (r *DogRepository) AreDogsCompatible(dogID, otherDogID int) (bool, error) {
+	if dogID == otherDogID {
+		return true, nil
+	}
+
+	low, high := dogID, otherDogID
+	if low > high {
+		low, high = high, low
+	}
+
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM dog_compatibility WHERE dog_id = ? AND compatible_dog_id = ?`,
+		low, high).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check compatibility: %w", err)
+	}
+
+	return count > 0, nil
+}
+
 // CanUserAccessDog checks if a user can access a dog based on their experience level
 func CanUserAccessDog(userLevel, dogCategory string) bool {
 	// Define level hierarchy: green < blue < orange
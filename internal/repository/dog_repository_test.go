@@ -504,6 +504,52 @@ func TestDogRepository_Update(t *testing.T) {
 	})
 }
 
+// DONE: TestDogRepository_Import tests that an import is written completely or not at all
+func TestDogRepository_Import(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewDogRepository(db)
+
+	existingID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	newDog := func(name, externalID string) *models.Dog {
+		return &models.Dog{Name: name, Breed: "Mischling", Size: "medium", Category: "green", IsAvailable: true, ExternalID: &externalID}
+	}
+
+	t.Run("creates and updates dogs", func(t *testing.T) {
+		existing, _ := repo.FindByID(existingID)
+		existing.Category = "blue"
+		rocky := newDog("Rocky", "T-1")
+
+		if err := repo.Import([]*models.Dog{existing, rocky}); err != nil {
+			t.Fatalf("Import() failed: %v", err)
+		}
+		if rocky.ID == 0 {
+			t.Error("Dog ID should be set after import")
+		}
+		bella, _ := repo.FindByID(existingID)
+		if bella.Category != "blue" {
+			t.Errorf("Expected Bella to be updated, got %s", bella.Category)
+		}
+	})
+
+	t.Run("failed dog rolls back the import", func(t *testing.T) {
+		existing, _ := repo.FindByID(existingID)
+		existing.Category = "orange"
+
+		err := repo.Import([]*models.Dog{existing, newDog("Luna", "T-2"), newDog("Max", "T-1")})
+		if err == nil {
+			t.Fatal("Expected error for duplicate external ID, got nil")
+		}
+
+		if id, _ := repo.FindByExternalID("T-2"); id != 0 {
+			t.Error("Dogs created before the failure must be rolled back")
+		}
+		bella, _ := repo.FindByID(existingID)
+		if bella.Category != "blue" {
+			t.Errorf("Updates before the failure must be rolled back, got %s", bella.Category)
+		}
+	})
+}
+
 // DONE: TestDogRepository_Delete tests dog deletion
 func TestDogRepository_Delete(t *testing.T) {
 	db := testutil.SetupTestDB(t)
@@ -809,3 +855,68 @@ func TestCanUserAccessDog(t *testing.T) {
 		})
 	}
 }
+
+// DONE: TestDogRepository_Compatibility tests the symmetric dog pairing list
+func TestDogRepository_Compatibility(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewDogRepository(db)
+
+	bella := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	max := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+	luna := testutil.SeedTestDog(t, db, "Luna", "Pudel", "blue")
+
+	t.Run("no pairings by default", func(t *testing.T) {
+		ok, err := repo.AreDogsCompatible(bella, max)
+		if err != nil {
+			t.Fatalf("AreDogsCompatible() failed: %v", err)
+		}
+		if ok {
+			t.Error("Dogs should not be compatible without an explicit pairing")
+		}
+	})
+
+	t.Run("pairing is symmetric", func(t *testing.T) {
+		if err := repo.SetCompatibleDogs(max, []int{bella, luna}); err != nil {
+			t.Fatalf("SetCompatibleDogs() failed: %v", err)
+		}
+
+		ok, err := repo.AreDogsCompatible(bella, max)
+		if err != nil {
+			t.Fatalf("AreDogsCompatible() failed: %v", err)
+		}
+		if !ok {
+			t.Error("Expected Bella and Max to be compatible")
+		}
+
+		ids, err := repo.GetCompatibleDogIDs(bella)
+		if err != nil {
+			t.Fatalf("GetCompatibleDogIDs() failed: %v", err)
+		}
+		if len(ids) != 1 || ids[0] != max {
+			t.Errorf("Expected Bella to be compatible with [%d], got %v", max, ids)
+		}
+	})
+
+	t.Run("replacing the list removes old pairings", func(t *testing.T) {
+		if err := repo.SetCompatibleDogs(max, []int{luna}); err != nil {
+			t.Fatalf("SetCompatibleDogs() failed: %v", err)
+		}
+
+		ok, _ := repo.AreDogsCompatible(max, bella)
+		if ok {
+			t.Error("Expected Bella and Max pairing to be removed")
+		}
+
+		ids, _ := repo.GetCompatibleDogIDs(max)
+		if len(ids) != 1 || ids[0] != luna {
+			t.Errorf("Expected Max to be compatible with [%d], got %v", luna, ids)
+		}
+	})
+
+	t.Run("dog is always compatible with itself", func(t *testing.T) {
+		ok, _ := repo.AreDogsCompatible(bella, bella)
+		if !ok {
+			t.Error("Expected a dog to be compatible with itself")
+		}
+	})
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DogSearchRepository maintains and queries the full-text search index of dogs
+// The index uses the search capability of each database: FTS5 on SQLite,
+// FULLTEXT indexes on MySQL and tsvector on PostgreSQL
+type DogSearchRepository struct {
+	db     *sql.DB
+	dbType string
+}
+
+// NewDogSearchRepository creates a new dog search repository
+// dbType is the configured database type (sqlite, mysql, postgres); empty means sqlite
+func NewDogSearchRepository(db *sql.DB, dbType string) *DogSearchRepository {
+	dbType = strings.ToLower(strings.TrimSpace(dbType))
+	switch dbType {
+	case "", "sqlite3":
+		dbType = "sqlite"
+	case "postgresql":
+		dbType = "postgres"
+	}
+	return &DogSearchRepository{db: db, dbType: dbType}
+}
+
+// IndexDog writes the search document of a dog, or removes it if the dog no longer exists
+// Call after the dog, one of its walk reports or one of its incidents changed
+func (r *DogSearchRepository) IndexDog(dogID int) error {
+	if _, err := r.db.Exec(`DELETE FROM dog_search WHERE dog_id = ?`, dogID); err != nil {
+		return fmt.Errorf("failed to remove dog from search index: %w", err)
+	}
+
+	var name, breed string
+	var specialNeeds, specialInstructions, walkRoute sql.NullString
+	err := r.db.QueryRow(`
+		SELECT name, breed, special_needs, special_instructions, walk_route
+		FROM dogs WHERE id = ?
+	`, dogID).Scan(&name, &breed, &specialNeeds, &specialInstructions, &walkRoute)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load dog for search index: %w", err)
+	}
+
+	history, err := r.loadHistory(dogID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO dog_search (dog_id, name, breed, details, history)
+		VALUES (?, ?, ?, ?, ?)
+	`, dogID,
+		normalizeSearchText(name),
+		normalizeSearchText(breed),
+		normalizeSearchText(specialNeeds.String, specialInstructions.String, walkRoute.String),
+		normalizeSearchText(history...),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to index dog for search: %w", err)
+	}
+
+	return nil
+}
+
+// Rebuild indexes all dogs, returns the number of indexed dogs
+// Runs at startup, so changes made outside the application are picked up as well
+func (r *DogSearchRepository) Rebuild() (int, error) {
+	rows, err := r.db.Query(`SELECT id FROM dogs`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query dogs: %w", err)
+	}
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan dog: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	if _, err := r.db.Exec(`DELETE FROM dog_search`); err != nil {
+		return 0, fmt.Errorf("failed to clear search index: %w", err)
+	}
+	for _, id := range ids {
+		if err := r.IndexDog(id); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(ids), nil
+}
+
+// Search returns the IDs of dogs matching all words of the query, most relevant first
+// Words match as prefixes, so "lab" finds "Labrador". Matches in the name rank above
+// matches in the breed, which rank above special needs, instructions and walk route.
+// includeHistory also searches walk report notes and incidents (admins only).
+func (r *DogSearchRepository) Search(query string, includeHistory bool) ([]int, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return []int{}, nil
+	}
+
+	var sqlQuery string
+	var args []interface{}
+
+	switch r.dbType {
+	case "mysql":
+		// Boolean mode: every word is required in the searched columns, the name and
+		// breed indexes add to the relevance
+		required := make([]string, len(terms))
+		optional := make([]string, len(terms))
+		for i, term := range terms {
+			required[i] = "+" + term + "*"
+			optional[i] = term + "*"
+		}
+		columns := "name, breed, details"
+		if includeHistory {
+			columns += ", history"
+		}
+		sqlQuery = `
+			SELECT dog_id FROM dog_search
+			WHERE MATCH(` + columns + `) AGAINST(? IN BOOLEAN MODE)
+			ORDER BY MATCH(name) AGAINST(? IN BOOLEAN MODE) * 4
+			       + MATCH(breed) AGAINST(? IN BOOLEAN MODE) * 2
+			       + MATCH(` + columns + `) AGAINST(? IN BOOLEAN MODE) DESC, dog_id
+		`
+		args = []interface{}{
+			strings.Join(required, " "),
+			strings.Join(optional, " "),
+			strings.Join(optional, " "),
+			strings.Join(optional, " "),
+		}
+
+	case "postgres":
+		prefixes := make([]string, len(terms))
+		for i, term := range terms {
+			prefixes[i] = term + ":*"
+		}
+		document := "document"
+		if includeHistory {
+			document = "(document || history_document)"
+		}
+		sqlQuery = `
+			SELECT dog_id FROM dog_search
+			WHERE ` + document + ` @@ to_tsquery('simple', ?)
+			ORDER BY ts_rank(` + document + `, to_tsquery('simple', ?)) DESC, dog_id
+		`
+		tsQuery := strings.Join(prefixes, " & ")
+		args = []interface{}{tsQuery, tsQuery}
+
+	default:
+		// FTS5: implicit AND of prefix queries; bm25 is lower for better matches
+		// Weights are per column: dog_id, name, breed, details, history
+		phrases := make([]string, len(terms))
+		for i, term := range terms {
+			phrases[i] = `"` + term + `"*`
+		}
+		match := strings.Join(phrases, " ")
+		if !includeHistory {
+			match = "{name breed details} : (" + match + ")"
+		}
+		sqlQuery = `
+			SELECT dog_id FROM dog_search
+			WHERE dog_search MATCH ?
+			ORDER BY bm25(dog_search, 0.0, 10.0, 5.0, 2.0, 1.0), dog_id
+		`
+		args = []interface{}{match}
+	}
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search dogs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// loadHistory returns the walk report notes and incident texts of a dog
+func (r *DogSearchRepository) loadHistory(dogID int) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT notes FROM walk_reports WHERE dog_id = ?
+		UNION ALL SELECT incident_description FROM walk_reports WHERE dog_id = ?
+		UNION ALL SELECT description FROM incidents WHERE dog_id = ?
+		UNION ALL SELECT resolution FROM incidents WHERE dog_id = ?
+		UNION ALL SELECT c.comment FROM incident_comments c
+		          JOIN incidents i ON i.id = c.incident_id WHERE i.dog_id = ?
+	`, dogID, dogID, dogID, dogID, dogID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dog history for search index: %w", err)
+	}
+	defer rows.Close()
+
+	history := []string{}
+	for rows.Next() {
+		var text sql.NullString
+		if err := rows.Scan(&text); err != nil {
+			return nil, fmt.Errorf("failed to scan dog history: %w", err)
+		}
+		if text.Valid {
+			history = append(history, text.String)
+		}
+	}
+
+	return history, nil
+}
+
+// germanReplacer spells out umlauts and ß the way they are written without them
+var germanReplacer = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss")
+
+// searchTerms splits text into lowercase words with umlauts and ß spelled out
+// "Schäferhund, groß" becomes ["schaeferhund", "gross"]
+func searchTerms(text string) []string {
+	text = germanReplacer.Replace(strings.ToLower(text))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalizeSearchText prepares text for the search index
+// Words with umlauts are indexed spelled out and with the dots dropped ("schaeferhund schaferhund"),
+// so searches for "Schäferhund", "Schaeferhund" and "Schaferhund" all match
+func normalizeSearchText(texts ...string) string {
+	words := []string{}
+	for _, text := range texts {
+		for _, word := range strings.Fields(strings.ToLower(text)) {
+			spelled := searchTerms(word)
+			words = append(words, spelled...)
+			if dropped := searchTerms(stripUmlauts(word)); strings.Join(dropped, " ") != strings.Join(spelled, " ") {
+				words = append(words, dropped...)
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// stripUmlauts drops the dots of umlauts ("ä" becomes "a")
+func stripUmlauts(text string) string {
+	return strings.NewReplacer("ä", "a", "ö", "o", "ü", "u").Replace(text)
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogSearchRepository tests full-text dog search with German normalisation
+func TestDogSearchRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewDogSearchRepository(db, "sqlite")
+
+	bellaID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	rexID := testutil.SeedTestDog(t, db, "Rex", "Deutscher Schäferhund", "blue")
+	maxID := testutil.SeedTestDog(t, db, "Max", "Mischling", "green")
+	db.Exec(`UPDATE dogs SET special_needs = ? WHERE id = ?`, "Mag keine Fahrräder, läuft gern mit Bella", maxID)
+	db.Exec(`UPDATE dogs SET walk_route = ? WHERE id = ?`, "Große Runde am Fluss", rexID)
+
+	// Walk report and incident history of Bella
+	userID := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+	bookingID := testutil.SeedTestBooking(t, db, userID, bellaID, "2025-01-10", "09:00", "completed")
+	db.Exec(`INSERT INTO walk_reports (booking_id, dog_id, user_id, leash_pulling, dog_reactivity, people_reactivity, notes)
+		VALUES (?, ?, ?, 3, 3, 3, ?)`, bookingID, bellaID, userID, "Hat ein Eichhörnchen gejagt")
+	db.Exec(`INSERT INTO incidents (dog_id, reported_by, incident_type, severity, description, occurred_at)
+		VALUES (?, ?, 'escape', 'low', ?, ?)`, bellaID, userID, "Aus dem Geschirr geschlüpft", time.Now())
+
+	count, err := repo.Rebuild()
+	if err != nil {
+		t.Fatalf("Rebuild() failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 indexed dogs, got %d", count)
+	}
+
+	search := func(query string, includeHistory bool) []int {
+		ids, err := repo.Search(query, includeHistory)
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", query, err)
+		}
+		return ids
+	}
+
+	t.Run("umlauts and ß", func(t *testing.T) {
+		for _, query := range []string{"Schäferhund", "schaeferhund", "Schaferhund", "GROSSE", "große"} {
+			if ids := search(query, false); !reflect.DeepEqual(ids, []int{rexID}) {
+				t.Errorf("Search(%q) = %v, expected [%d]", query, ids, rexID)
+			}
+		}
+	})
+
+	t.Run("prefix and all words", func(t *testing.T) {
+		if ids := search("lab", false); !reflect.DeepEqual(ids, []int{bellaID}) {
+			t.Errorf("Expected Bella for a prefix, got %v", ids)
+		}
+		if ids := search("deutscher fluss", false); !reflect.DeepEqual(ids, []int{rexID}) {
+			t.Errorf("Expected Rex for words in breed and walk route, got %v", ids)
+		}
+		if ids := search("labrador fluss", false); len(ids) != 0 {
+			t.Errorf("Expected no dog matching both words, got %v", ids)
+		}
+	})
+
+	t.Run("name ranks above details", func(t *testing.T) {
+		// Max mentions Bella in its special needs
+		if ids := search("bella", false); !reflect.DeepEqual(ids, []int{bellaID, maxID}) {
+			t.Errorf("Expected Bella before Max, got %v", ids)
+		}
+	})
+
+	t.Run("history only for admins", func(t *testing.T) {
+		for _, query := range []string{"eichhörnchen", "geschirr"} {
+			if ids := search(query, false); len(ids) != 0 {
+				t.Errorf("Search(%q) without history = %v, expected none", query, ids)
+			}
+			if ids := search(query, true); !reflect.DeepEqual(ids, []int{bellaID}) {
+				t.Errorf("Search(%q) with history = %v, expected [%d]", query, ids, bellaID)
+			}
+		}
+	})
+
+	t.Run("index follows changes", func(t *testing.T) {
+		db.Exec(`UPDATE dogs SET name = ? WHERE id = ?`, "Jürgen", maxID)
+		if err := repo.IndexDog(maxID); err != nil {
+			t.Fatalf("IndexDog() failed: %v", err)
+		}
+		if ids := search("juergen", false); !reflect.DeepEqual(ids, []int{maxID}) {
+			t.Errorf("Expected the renamed dog, got %v", ids)
+		}
+
+		db.Exec(`DELETE FROM dogs WHERE id = ?`, maxID)
+		repo.IndexDog(maxID)
+		if ids := search("juergen", false); len(ids) != 0 {
+			t.Errorf("Expected the deleted dog to be removed, got %v", ids)
+		}
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		if ids := search(" ,; ", false); len(ids) != 0 {
+			t.Errorf("Expected no results, got %v", ids)
+		}
+	})
+}
+
+// DONE: TestNormalizeSearchText tests German normalisation of indexed text
+func TestNormalizeSearchText(t *testing.T) {
+	tests := map[string]string{
+		"Deutscher Schäferhund": "deutscher schaeferhund schaferhund",
+		"Große Runde":           "grosse runde",
+		"Fahrräder, Bälle!":     "fahrraeder fahrrader baelle balle",
+		"":                      "",
+	}
+	for input, expected := range tests {
+		if got := normalizeSearchText(input); got != expected {
+			t.Errorf("normalizeSearchText(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
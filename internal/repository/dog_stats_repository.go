@@ -0,0 +1,330 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// dogBookingCondition matches the bookings a dog takes part in, as primary or additional dog
+const dogBookingCondition = `(b.dog_id = ? OR b.id IN (SELECT booking_id FROM booking_dogs WHERE dog_id = ?))`
+
+// DogStatsRepository handles walk history and statistics of dogs
+type DogStatsRepository struct {
+	db *sql.DB
+}
+
+// NewDogStatsRepository creates a new dog stats repository
+func NewDogStatsRepository(db *sql.DB) *DogStatsRepository {
+	return &DogStatsRepository{db: db}
+}
+
+// FindWalks returns the completed walks of a dog, newest first
+// from and to (YYYY-MM-DD) limit the walk dates and may be empty
+func (r *DogStatsRepository) FindWalks(dogID int, from, to string) ([]*models.DogWalk, error) {
+	query := `
+		SELECT b.id, b.date, b.scheduled_time, b.user_id, u.name, wr.distance_km
+		FROM bookings b
+		LEFT JOIN users u ON b.user_id = u.id
+		LEFT JOIN walk_reports wr ON wr.booking_id = b.id AND wr.dog_id = ?
+		WHERE ` + dogBookingCondition + ` AND b.status = 'completed'
+	`
+	args := []interface{}{dogID, dogID, dogID}
+	query, args = withDateRange(query, args, from, to)
+	query += " ORDER BY b.date DESC, b.scheduled_time DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query walks: %w", err)
+	}
+	defer rows.Close()
+
+	walks := []*models.DogWalk{}
+	byBooking := map[int]*models.DogWalk{}
+	for rows.Next() {
+		walk := &models.DogWalk{}
+		organizer := &models.Walker{}
+		var organizerName sql.NullString
+		if err := rows.Scan(&walk.BookingID, &walk.Date, &walk.ScheduledTime, &organizer.UserID, &organizerName, &walk.DistanceKm); err != nil {
+			return nil, fmt.Errorf("failed to scan walk: %w", err)
+		}
+		walk.Date = dateOnly(walk.Date)
+		organizer.Name = walkerName(organizerName)
+		walk.Walkers = []*models.Walker{organizer}
+
+		// A dog can have several reports on one booking only by mistake; list the walk once
+		if _, ok := byBooking[walk.BookingID]; ok {
+			continue
+		}
+		byBooking[walk.BookingID] = walk
+		walks = append(walks, walk)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read walks: %w", err)
+	}
+
+	if err := r.loadCoWalkers(byBooking); err != nil {
+		return nil, err
+	}
+
+	return walks, nil
+}
+
+// loadCoWalkers adds the co-walkers of group walks to the walkers of each walk
+func (r *DogStatsRepository) loadCoWalkers(walks map[int]*models.DogWalk) error {
+	if len(walks) == 0 {
+		return nil
+	}
+
+	ids := make([]interface{}, 0, len(walks))
+	for id := range walks {
+		ids = append(ids, id)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT p.booking_id, p.user_id, u.name
+		FROM booking_participants p
+		LEFT JOIN users u ON p.user_id = u.id
+		WHERE p.booking_id IN (`+placeholders(len(ids))+`)
+		ORDER BY p.id ASC
+	`, ids...)
+	if err != nil {
+		return fmt.Errorf("failed to query co-walkers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookingID int
+		walker := &models.Walker{}
+		var name sql.NullString
+		if err := rows.Scan(&bookingID, &walker.UserID, &name); err != nil {
+			return fmt.Errorf("failed to scan co-walker: %w", err)
+		}
+		walker.Name = walkerName(name)
+		walks[bookingID].Walkers = append(walks[bookingID].Walkers, walker)
+	}
+
+	return rows.Err()
+}
+
+// CountCancelled returns the number of cancelled walks of a dog
+// from and to (YYYY-MM-DD) limit the walk dates and may be empty
+func (r *DogStatsRepository) CountCancelled(dogID int, from, to string) (int, error) {
+	query := `SELECT COUNT(*) FROM bookings b WHERE ` + dogBookingCondition + ` AND b.status = 'cancelled'`
+	query, args := withDateRange(query, []interface{}{dogID, dogID}, from, to)
+
+	var count int
+	if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count cancelled walks: %w", err)
+	}
+	return count, nil
+}
+
+// GetStats aggregates the walks of a dog
+// Minutes are based on the walk duration of the dog, as the actual length of walks is not recorded.
+// Aggregation is done in Go to avoid database-specific date functions.
+func (r *DogStatsRepository) GetStats(dog *models.Dog, from, to string, today time.Time) (*models.DogWalkStats, error) {
+	walks, err := r.FindWalks(dog.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled, err := r.CountCancelled(dog.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	minutesPerWalk := 0
+	if dog.WalkDuration != nil {
+		minutesPerWalk = *dog.WalkDuration
+	}
+
+	stats := &models.DogWalkStats{
+		DogID:          dog.ID,
+		From:           from,
+		To:             to,
+		WalkDuration:   dog.WalkDuration,
+		TotalWalks:     len(walks),
+		TotalMinutes:   len(walks) * minutesPerWalk,
+		CancelledWalks: cancelled,
+		Monthly:        []*models.MonthlyWalkStats{},
+		TopWalkers:     []*models.WalkerStats{},
+	}
+
+	if total := stats.TotalWalks + cancelled; total > 0 {
+		stats.CancellationRate = float64(cancelled) / float64(total) * 100
+	}
+
+	if len(walks) == 0 {
+		return stats, nil
+	}
+
+	// Walks are sorted newest first
+	lastWalk := walks[0].Date
+	stats.LastWalkDate = &lastWalk
+	if days, ok := DaysSince(lastWalk, today); ok {
+		stats.DaysSinceLastWalk = &days
+	}
+
+	months := map[string]*models.MonthlyWalkStats{}
+	walkers := map[int]*models.WalkerStats{}
+	for _, walk := range walks {
+		if walk.DistanceKm != nil {
+			stats.TotalDistanceKm += *walk.DistanceKm
+		}
+
+		month := walk.Date
+		if len(month) >= 7 {
+			month = month[:7]
+		}
+		monthly, ok := months[month]
+		if !ok {
+			monthly = &models.MonthlyWalkStats{Month: month}
+			months[month] = monthly
+		}
+		monthly.Walks++
+		monthly.Minutes += minutesPerWalk
+
+		for _, walker := range walk.Walkers {
+			counted, ok := walkers[walker.UserID]
+			if !ok {
+				counted = &models.WalkerStats{UserID: walker.UserID, Name: walker.Name}
+				walkers[walker.UserID] = counted
+			}
+			counted.Walks++
+		}
+	}
+
+	stats.Monthly = fillMonths(months, walks[len(walks)-1].Date, walks[0].Date)
+	stats.DistinctWalkers = len(walkers)
+
+	for _, walker := range walkers {
+		stats.TopWalkers = append(stats.TopWalkers, walker)
+	}
+	sort.Slice(stats.TopWalkers, func(i, j int) bool {
+		if stats.TopWalkers[i].Walks != stats.TopWalkers[j].Walks {
+			return stats.TopWalkers[i].Walks > stats.TopWalkers[j].Walks
+		}
+		return stats.TopWalkers[i].Name < stats.TopWalkers[j].Name
+	})
+	if len(stats.TopWalkers) > 5 {
+		stats.TopWalkers = stats.TopWalkers[:5]
+	}
+
+	return stats, nil
+}
+
+// FindNeglected returns bookable dogs that have not been walked for at least days days
+// Dogs that were never walked count from the day they were added. Longest waiting dogs come first.
+func (r *DogStatsRepository) FindNeglected(days int, today time.Time) ([]*models.NeglectedDog, error) {
+	query := `
+		SELECT d.id, d.name, d.category, d.status, d.created_at,
+		       (SELECT MAX(b.date) FROM bookings b
+		        WHERE (b.dog_id = d.id OR b.id IN (SELECT booking_id FROM booking_dogs WHERE dog_id = d.id))
+		          AND b.status = 'completed'),
+		       (SELECT MIN(b.date) FROM bookings b
+		        WHERE (b.dog_id = d.id OR b.id IN (SELECT booking_id FROM booking_dogs WHERE dog_id = d.id))
+		          AND b.status = 'scheduled' AND b.date >= ?)
+		FROM dogs d
+		WHERE d.is_available = 1 AND d.status IN (?, ?)
+		ORDER BY d.name ASC
+	`
+
+	rows, err := r.db.Query(query, today.Format("2006-01-02"), models.DogStatusAvailable, models.DogStatusReserved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query neglected dogs: %w", err)
+	}
+	defer rows.Close()
+
+	dogs := []*models.NeglectedDog{}
+	for rows.Next() {
+		dog := &models.NeglectedDog{}
+		var createdAt time.Time
+		var lastWalk, nextWalk sql.NullString
+		if err := rows.Scan(&dog.DogID, &dog.Name, &dog.Category, &dog.Status, &createdAt, &lastWalk, &nextWalk); err != nil {
+			return nil, fmt.Errorf("failed to scan neglected dog: %w", err)
+		}
+
+		dog.LastWalkDate = nullableDate(lastWalk)
+		dog.NextWalkDate = nullableDate(nextWalk)
+
+		since := createdAt.Format("2006-01-02")
+		if dog.LastWalkDate != nil {
+			since = *dog.LastWalkDate
+		}
+
+		waiting, ok := DaysSince(since, today)
+		if !ok || waiting < days {
+			continue
+		}
+		dog.DaysSinceLastWalk = waiting
+		dogs = append(dogs, dog)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read neglected dogs: %w", err)
+	}
+
+	sort.SliceStable(dogs, func(i, j int) bool {
+		return dogs[i].DaysSinceLastWalk > dogs[j].DaysSinceLastWalk
+	})
+
+	return dogs, nil
+}
+
+// DaysSince returns the number of calendar days from date (YYYY-MM-DD) to today
+func DaysSince(date string, today time.Time) (int, bool) {
+	day, err := time.ParseInLocation("2006-01-02", dateOnly(date), today.Location())
+	if err != nil {
+		return 0, false
+	}
+	midnight := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	return int(midnight.Sub(day).Hours() / 24), true
+}
+
+// withDateRange appends optional date bounds on b.date to a query
+func withDateRange(query string, args []interface{}, from, to string) (string, []interface{}) {
+	if from != "" {
+		query += " AND b.date >= ?"
+		args = append(args, from)
+	}
+	if to != "" {
+		query += " AND b.date <= ?"
+		args = append(args, to)
+	}
+	return query, args
+}
+
+// fillMonths returns the monthly stats from the month of first to the month of last,
+// including months without walks, so the series can be charted directly
+func fillMonths(months map[string]*models.MonthlyWalkStats, first, last string) []*models.MonthlyWalkStats {
+	start, err := time.Parse("2006-01", first[:7])
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse("2006-01", last[:7])
+	if err != nil {
+		return nil
+	}
+
+	result := []*models.MonthlyWalkStats{}
+	for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+		key := month.Format("2006-01")
+		if stats, ok := months[key]; ok {
+			result = append(result, stats)
+		} else {
+			result = append(result, &models.MonthlyWalkStats{Month: key})
+		}
+	}
+	return result
+}
+
+// walkerName returns the name of a walker, or a placeholder for deleted users
+func walkerName(name sql.NullString) string {
+	if name.Valid {
+		return name.String
+	}
+	return "Deleted User"
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogStatsRepository tests walk history, statistics and the neglected dogs report
+func TestDogStatsRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewDogStatsRepository(db)
+	dogRepo := NewDogRepository(db)
+
+	today := time.Date(2025, 6, 15, 12, 0, 0, 0, time.Local)
+
+	annaID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+	benID := testutil.SeedTestUser(t, db, "ben@example.com", "Ben", "green")
+	bellaID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	maxID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+	rockyID := testutil.SeedTestDog(t, db, "Rocky", "Dackel", "green")
+	db.Exec(`UPDATE dogs SET walk_duration = 45 WHERE id = ?`, bellaID)
+	db.Exec(`UPDATE dogs SET created_at = ?`, today.AddDate(0, -6, 0))
+
+	testutil.SeedTestBooking(t, db, annaID, bellaID, "2025-03-10", "10:00", "completed")
+	testutil.SeedTestBooking(t, db, annaID, bellaID, "2025-05-02", "10:00", "completed")
+	testutil.SeedTestBooking(t, db, benID, bellaID, "2025-05-20", "15:00", "cancelled")
+	reportBooking := testutil.SeedTestBooking(t, db, annaID, bellaID, "2025-06-10", "10:00", "completed")
+	db.Exec(`INSERT INTO walk_reports (booking_id, dog_id, user_id, leash_pulling, dog_reactivity, people_reactivity, distance_km)
+		VALUES (?, ?, ?, 1, 1, 1, 3.5)`, reportBooking, bellaID, annaID)
+
+	// Group walk organised by Ben with Max, Bella joins as additional dog and Anna as co-walker
+	groupID := testutil.SeedTestBooking(t, db, benID, maxID, "2025-06-12", "09:00", "completed")
+	db.Exec(`INSERT INTO booking_dogs (booking_id, dog_id) VALUES (?, ?)`, groupID, bellaID)
+	db.Exec(`INSERT INTO booking_participants (booking_id, user_id) VALUES (?, ?)`, groupID, annaID)
+
+	testutil.SeedTestBooking(t, db, annaID, rockyID, "2025-06-20", "10:00", "scheduled")
+
+	t.Run("walk history", func(t *testing.T) {
+		walks, err := repo.FindWalks(bellaID, "", "")
+		if err != nil {
+			t.Fatalf("FindWalks() failed: %v", err)
+		}
+		if len(walks) != 4 {
+			t.Fatalf("Expected 4 completed walks, got %d", len(walks))
+		}
+		if walks[0].BookingID != groupID || len(walks[0].Walkers) != 2 || walks[0].Walkers[1].Name != "Anna" {
+			t.Errorf("Expected the group walk with both walkers first, got %+v", walks[0])
+		}
+		if walks[1].DistanceKm == nil || *walks[1].DistanceKm != 3.5 {
+			t.Errorf("Expected the distance of the walk report, got %v", walks[1].DistanceKm)
+		}
+
+		walks, _ = repo.FindWalks(bellaID, "2025-05-01", "2025-05-31")
+		if len(walks) != 1 {
+			t.Errorf("Expected 1 walk in May, got %d", len(walks))
+		}
+	})
+
+	t.Run("statistics", func(t *testing.T) {
+		dog, _ := dogRepo.FindByID(bellaID)
+		stats, err := repo.GetStats(dog, "", "", today)
+		if err != nil {
+			t.Fatalf("GetStats() failed: %v", err)
+		}
+
+		if stats.TotalWalks != 4 || stats.TotalMinutes != 180 {
+			t.Errorf("Expected 4 walks and 180 minutes, got %d and %d", stats.TotalWalks, stats.TotalMinutes)
+		}
+		if stats.DistinctWalkers != 2 || stats.TotalDistanceKm != 3.5 {
+			t.Errorf("Expected 2 walkers and 3.5 km, got %d and %v", stats.DistinctWalkers, stats.TotalDistanceKm)
+		}
+		if stats.CancelledWalks != 1 || stats.CancellationRate != 20 {
+			t.Errorf("Expected 1 cancelled walk (20%%), got %d (%v%%)", stats.CancelledWalks, stats.CancellationRate)
+		}
+		if stats.LastWalkDate == nil || *stats.LastWalkDate != "2025-06-12" || stats.DaysSinceLastWalk == nil || *stats.DaysSinceLastWalk != 3 {
+			t.Errorf("Expected last walk 3 days ago on 2025-06-12, got %v / %v", stats.LastWalkDate, stats.DaysSinceLastWalk)
+		}
+
+		if len(stats.Monthly) != 4 || stats.Monthly[1].Month != "2025-04" || stats.Monthly[1].Walks != 0 || stats.Monthly[3].Walks != 2 {
+			t.Errorf("Expected March to June including the empty April, got %+v", stats.Monthly)
+		}
+		if len(stats.TopWalkers) != 2 || stats.TopWalkers[0].Name != "Anna" || stats.TopWalkers[0].Walks != 4 {
+			t.Errorf("Expected Anna as top walker with 4 walks, got %+v", stats.TopWalkers[0])
+		}
+	})
+
+	t.Run("dog without walks", func(t *testing.T) {
+		dog, _ := dogRepo.FindByID(rockyID)
+		stats, err := repo.GetStats(dog, "", "", today)
+		if err != nil {
+			t.Fatalf("GetStats() failed: %v", err)
+		}
+		if stats.TotalWalks != 0 || stats.LastWalkDate != nil || len(stats.Monthly) != 0 {
+			t.Errorf("Expected empty statistics, got %+v", stats)
+		}
+	})
+
+	t.Run("neglected dogs", func(t *testing.T) {
+		dogs, err := repo.FindNeglected(7, today)
+		if err != nil {
+			t.Fatalf("FindNeglected() failed: %v", err)
+		}
+		if len(dogs) != 1 || dogs[0].DogID != rockyID {
+			t.Fatalf("Expected only Rocky, got %d dogs", len(dogs))
+		}
+		if dogs[0].LastWalkDate != nil || dogs[0].NextWalkDate == nil || *dogs[0].NextWalkDate != "2025-06-20" {
+			t.Errorf("Expected Rocky to never have been walked with a walk on 2025-06-20, got %+v", dogs[0])
+		}
+
+		dogs, _ = repo.FindNeglected(3, today)
+		if len(dogs) != 3 || dogs[0].DogID != rockyID {
+			t.Errorf("Expected all dogs with Rocky first, got %d dogs", len(dogs))
+		}
+
+		db.Exec(`UPDATE dogs SET status = ? WHERE id = ?`, models.DogStatusAdopted, rockyID)
+		dogs, _ = repo.FindNeglected(7, today)
+		if len(dogs) != 0 {
+			t.Errorf("Expected adopted dogs to be skipped, got %d dogs", len(dogs))
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// DogTagRepository handles dog tags and their assignment to dogs
+type DogTagRepository struct {
+	db *sql.DB
+}
+
+// NewDogTagRepository creates a new dog tag repository
+func NewDogTagRepository(db *sql.DB) *DogTagRepository {
+	return &DogTagRepository{db: db}
+}
+
+// FindAll returns all tags sorted by name
+func (r *DogTagRepository) FindAll() ([]*models.DogTag, error) {
+	rows, err := r.db.Query(`SELECT id, name, description, created_at FROM dog_tags ORDER BY name ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []*models.DogTag{}
+	for rows.Next() {
+		tag := &models.DogTag{}
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Description, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// FindByID finds a tag by ID
+func (r *DogTagRepository) FindByID(id int) (*models.DogTag, error) {
+	tag := &models.DogTag{}
+	err := r.db.QueryRow(`SELECT id, name, description, created_at FROM dog_tags WHERE id = ?`, id).
+		Scan(&tag.ID, &tag.Name, &tag.Description, &tag.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tag: %w", err)
+	}
+	return tag, nil
+}
+
+// NameExists checks if another tag already has the name (case-insensitive)
+func (r *DogTagRepository) NameExists(name string, excludeID int) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM dog_tags WHERE LOWER(name) = LOWER(?) AND id != ?`, name, excludeID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check tag name: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Create creates a new tag
+func (r *DogTagRepository) Create(tag *models.DogTag) error {
+	tag.CreatedAt = time.Now()
+	result, err := r.db.Exec(`INSERT INTO dog_tags (name, description, created_at) VALUES (?, ?, ?)`,
+		tag.Name, tag.Description, tag.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get tag ID: %w", err)
+	}
+	tag.ID = int(id)
+
+	return nil
+}
+
+// Update changes the name and description of a tag
+func (r *DogTagRepository) Update(tag *models.DogTag) error {
+	_, err := r.db.Exec(`UPDATE dog_tags SET name = ?, description = ? WHERE id = ?`, tag.Name, tag.Description, tag.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a tag and removes it from all dogs
+func (r *DogTagRepository) Delete(id int) error {
+	// Assignments are removed explicitly, SQLite only cascades with foreign keys enabled
+	if _, err := r.db.Exec(`DELETE FROM dog_tag_assignments WHERE tag_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to remove tag from dogs: %w", err)
+	}
+	if _, err := r.db.Exec(`DELETE FROM dog_tags WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+// SetDogTags replaces the tags of a dog
+func (r *DogTagRepository) SetDogTags(dogID int, tagIDs []int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM dog_tag_assignments WHERE dog_id = ?`, dogID); err != nil {
+		return fmt.Errorf("failed to clear dog tags: %w", err)
+	}
+	for _, tagID := range tagIDs {
+		if _, err := tx.Exec(`INSERT INTO dog_tag_assignments (dog_id, tag_id) VALUES (?, ?)`, dogID, tagID); err != nil {
+			return fmt.Errorf("failed to assign tag: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit dog tags: %w", err)
+	}
+	return nil
+}
+
+// FindByDogs returns the tags of the given dogs, keyed by dog ID
+func (r *DogTagRepository) FindByDogs(dogIDs []int) (map[int][]*models.DogTag, error) {
+	result := map[int][]*models.DogTag{}
+	if len(dogIDs) == 0 {
+		return result, nil
+	}
+
+	args := make([]interface{}, len(dogIDs))
+	for i, id := range dogIDs {
+		args[i] = id
+	}
+
+	rows, err := r.db.Query(`
+		SELECT a.dog_id, t.id, t.name, t.description, t.created_at
+		FROM dog_tag_assignments a
+		JOIN dog_tags t ON t.id = a.tag_id
+		WHERE a.dog_id IN (`+placeholders(len(dogIDs))+`)
+		ORDER BY t.name ASC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dog tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dogID int
+		tag := &models.DogTag{}
+		if err := rows.Scan(&dogID, &tag.ID, &tag.Name, &tag.Description, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dog tag: %w", err)
+		}
+		result[dogID] = append(result[dogID], tag)
+	}
+
+	return result, nil
+}
+
+// placeholders returns n comma-separated query placeholders for an IN list
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestDogTagRepository tests tags, assignments and filtering dogs by tags
+func TestDogTagRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewDogTagRepository(db)
+	dogRepo := NewDogRepository(db)
+
+	bellaID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	maxID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+	testutil.SeedTestDog(t, db, "Rocky", "Dackel", "green")
+
+	t.Run("starter tags", func(t *testing.T) {
+		tags, err := repo.FindAll()
+		if err != nil {
+			t.Fatalf("FindAll() failed: %v", err)
+		}
+		if len(tags) != 5 {
+			t.Errorf("Expected 5 starter tags, got %d", len(tags))
+		}
+	})
+
+	kids := &models.DogTag{Name: "Mag Kinder"}
+	cats := &models.DogTag{Name: "Mag Katzen"}
+	t.Run("create and update", func(t *testing.T) {
+		if err := repo.Create(kids); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		if err := repo.Create(cats); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+
+		exists, _ := repo.NameExists("mag kinder", 0)
+		if !exists {
+			t.Error("Expected name check to be case-insensitive")
+		}
+		exists, _ = repo.NameExists("Mag Kinder", kids.ID)
+		if exists {
+			t.Error("Expected the tag itself to be excluded")
+		}
+
+		description := "Liebt Kinder"
+		kids.Description = &description
+		if err := repo.Update(kids); err != nil {
+			t.Fatalf("Update() failed: %v", err)
+		}
+		found, _ := repo.FindByID(kids.ID)
+		if found == nil || found.Description == nil || *found.Description != description {
+			t.Errorf("Expected updated description, got %+v", found)
+		}
+	})
+
+	t.Run("assign and filter", func(t *testing.T) {
+		if err := repo.SetDogTags(bellaID, []int{kids.ID, cats.ID}); err != nil {
+			t.Fatalf("SetDogTags() failed: %v", err)
+		}
+		repo.SetDogTags(maxID, []int{kids.ID})
+
+		tags, err := repo.FindByDogs([]int{bellaID, maxID})
+		if err != nil {
+			t.Fatalf("FindByDogs() failed: %v", err)
+		}
+		if len(tags[bellaID]) != 2 || len(tags[maxID]) != 1 {
+			t.Errorf("Expected 2 and 1 tags, got %d and %d", len(tags[bellaID]), len(tags[maxID]))
+		}
+
+		dogs, _ := dogRepo.FindAll(&models.DogFilterRequest{TagIDs: []int{kids.ID, cats.ID}})
+		if len(dogs) != 1 || dogs[0].ID != bellaID {
+			t.Errorf("Expected only Bella with both tags, got %d dogs", len(dogs))
+		}
+
+		dogs, _ = dogRepo.FindAll(&models.DogFilterRequest{ExcludeTagIDs: []int{cats.ID}})
+		if len(dogs) != 2 {
+			t.Errorf("Expected 2 dogs without the tag, got %d", len(dogs))
+		}
+
+		dogs, _ = dogRepo.FindAll(&models.DogFilterRequest{TagIDs: []int{kids.ID}, ExcludeTagIDs: []int{cats.ID}})
+		if len(dogs) != 1 || dogs[0].ID != maxID {
+			t.Errorf("Expected only Max, got %d dogs", len(dogs))
+		}
+	})
+
+	t.Run("replace tags", func(t *testing.T) {
+		repo.SetDogTags(bellaID, []int{cats.ID})
+		tags, _ := repo.FindByDogs([]int{bellaID})
+		if len(tags[bellaID]) != 1 || tags[bellaID][0].ID != cats.ID {
+			t.Errorf("Expected only the cat tag, got %v", tags[bellaID])
+		}
+	})
+
+	t.Run("delete removes assignments", func(t *testing.T) {
+		if err := repo.Delete(cats.ID); err != nil {
+			t.Fatalf("Delete() failed: %v", err)
+		}
+		tags, _ := repo.FindByDogs([]int{bellaID})
+		if len(tags[bellaID]) != 0 {
+			t.Errorf("Expected no tags, got %d", len(tags[bellaID]))
+		}
+		found, _ := repo.FindByID(cats.ID)
+		if found != nil {
+			t.Error("Expected tag to be deleted")
+		}
+	})
+}
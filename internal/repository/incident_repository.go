@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// IncidentRepository handles incident database operations
+type IncidentRepository struct {
+	db *sql.DB
+}
+
+// NewIncidentRepository creates a new incident repository
+func NewIncidentRepository(db *sql.DB) *IncidentRepository {
+	return &IncidentRepository{db: db}
+}
+
+// Create creates a new incident with status open
+func (r *IncidentRepository) Create(incident *models.Incident) error {
+	now := time.Now()
+	if incident.Status == "" {
+		incident.Status = models.IncidentStatusOpen
+	}
+
+	query := `
+		INSERT INTO incidents (
+			dog_id, booking_id, reported_by, incident_type, severity, description,
+			occurred_at, status, dog_action, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query,
+		incident.DogID, incident.BookingID, incident.ReportedBy, incident.IncidentType,
+		incident.Severity, incident.Description, incident.OccurredAt, incident.Status,
+		incident.DogAction, now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create incident: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get incident ID: %w", err)
+	}
+
+	incident.ID = int(id)
+	incident.CreatedAt = now
+	incident.UpdatedAt = now
+	return nil
+}
+
+// FindByID finds an incident by ID, with dog and reporter names joined
+func (r *IncidentRepository) FindByID(id int) (*models.Incident, error) {
+	incidents, err := r.findWhere("i.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(incidents) == 0 {
+		return nil, nil
+	}
+	return incidents[0], nil
+}
+
+// FindAll returns incidents matching the filter, most recent first
+func (r *IncidentRepository) FindAll(filter *models.IncidentFilterRequest) ([]*models.Incident, error) {
+	condition := "1=1"
+	args := []interface{}{}
+
+	if filter != nil {
+		if filter.DogID != nil {
+			condition += " AND i.dog_id = ?"
+			args = append(args, *filter.DogID)
+		}
+		if filter.ReportedBy != nil {
+			condition += " AND i.reported_by = ?"
+			args = append(args, *filter.ReportedBy)
+		}
+		if filter.Status != nil {
+			condition += " AND i.status = ?"
+			args = append(args, *filter.Status)
+		}
+		if filter.Severity != nil {
+			condition += " AND i.severity = ?"
+			args = append(args, *filter.Severity)
+		}
+	}
+
+	return r.findWhere(condition, args...)
+}
+
+// findWhere runs the common incident query with dog and reporter names joined
+func (r *IncidentRepository) findWhere(condition string, args ...interface{}) ([]*models.Incident, error) {
+	query := `
+		SELECT i.id, i.dog_id, i.booking_id, i.reported_by, i.incident_type, i.severity, i.description,
+		       i.occurred_at, i.status, i.resolution, i.resolved_by, i.resolved_at, i.dog_action,
+		       i.created_at, i.updated_at,
+		       d.name, u.name
+		FROM incidents i
+		JOIN dogs d ON i.dog_id = d.id
+		LEFT JOIN users u ON i.reported_by = u.id
+		WHERE ` + condition + `
+		ORDER BY i.occurred_at DESC, i.id DESC
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incidents: %w", err)
+	}
+	defer rows.Close()
+
+	incidents := []*models.Incident{}
+	for rows.Next() {
+		incident := &models.Incident{}
+		var reporterName sql.NullString
+		err := rows.Scan(
+			&incident.ID,
+			&incident.DogID,
+			&incident.BookingID,
+			&incident.ReportedBy,
+			&incident.IncidentType,
+			&incident.Severity,
+			&incident.Description,
+			&incident.OccurredAt,
+			&incident.Status,
+			&incident.Resolution,
+			&incident.ResolvedBy,
+			&incident.ResolvedAt,
+			&incident.DogAction,
+			&incident.CreatedAt,
+			&incident.UpdatedAt,
+			&incident.DogName,
+			&reporterName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan incident: %w", err)
+		}
+
+		incident.ReporterName = "Deleted User"
+		if reporterName.Valid {
+			incident.ReporterName = reporterName.String
+		}
+
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, nil
+}
+
+// UpdateStatus moves an incident through the review workflow
+// Closing statuses record who resolved it and when; reopening clears that again
+func (r *IncidentRepository) UpdateStatus(id int, status string, resolution *string, reviewerID int) error {
+	now := time.Now()
+
+	var resolvedBy *int
+	var resolvedAt *time.Time
+	if status == models.IncidentStatusResolved || status == models.IncidentStatusDismissed {
+		resolvedBy = &reviewerID
+		resolvedAt = &now
+	}
+
+	query := `
+		UPDATE incidents SET
+			status = ?, resolution = COALESCE(?, resolution), resolved_by = ?, resolved_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+	result, err := r.db.Exec(query, status, resolution, resolvedBy, resolvedAt, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update incident status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("incident not found")
+	}
+
+	return nil
+}
+
+// AppendDogAction records a change that was applied to the dog because of the incident
+func (r *IncidentRepository) AppendDogAction(id int, action string) error {
+	incident, err := r.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if incident == nil {
+		return fmt.Errorf("incident not found")
+	}
+
+	if incident.DogAction != nil && *incident.DogAction != "" {
+		action = *incident.DogAction + "; " + action
+	}
+
+	_, err = r.db.Exec("UPDATE incidents SET dog_action = ?, updated_at = ? WHERE id = ?", action, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update incident dog action: %w", err)
+	}
+
+	return nil
+}
+
+// AddPhoto attaches an uploaded photo to an incident
+func (r *IncidentRepository) AddPhoto(photo *models.IncidentPhoto) error {
+	now := time.Now()
+
+	result, err := r.db.Exec(
+		"INSERT INTO incident_photos (incident_id, photo, uploaded_by, created_at) VALUES (?, ?, ?, ?)",
+		photo.IncidentID, photo.Photo, photo.UploadedBy, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add incident photo: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get incident photo ID: %w", err)
+	}
+
+	photo.ID = int(id)
+	photo.CreatedAt = now
+	return nil
+}
+
+// GetPhotos returns the photos of an incident in upload order
+func (r *IncidentRepository) GetPhotos(incidentID int) ([]*models.IncidentPhoto, error) {
+	rows, err := r.db.Query(`
+		SELECT id, incident_id, photo, uploaded_by, created_at
+		FROM incident_photos
+		WHERE incident_id = ?
+		ORDER BY id ASC
+	`, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident photos: %w", err)
+	}
+	defer rows.Close()
+
+	photos := []*models.IncidentPhoto{}
+	for rows.Next() {
+		photo := &models.IncidentPhoto{}
+		if err := rows.Scan(&photo.ID, &photo.IncidentID, &photo.Photo, &photo.UploadedBy, &photo.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan incident photo: %w", err)
+		}
+		photos = append(photos, photo)
+	}
+
+	return photos, nil
+}
+
+// AddComment adds a review comment to an incident
+func (r *IncidentRepository) AddComment(comment *models.IncidentComment) error {
+	now := time.Now()
+
+	result, err := r.db.Exec(
+		"INSERT INTO incident_comments (incident_id, user_id, comment, created_at) VALUES (?, ?, ?, ?)",
+		comment.IncidentID, comment.UserID, comment.Comment, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add incident comment: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get incident comment ID: %w", err)
+	}
+
+	comment.ID = int(id)
+	comment.CreatedAt = now
+	return nil
+}
+
+// GetComments returns the comments of an incident, oldest first
+func (r *IncidentRepository) GetComments(incidentID int) ([]*models.IncidentComment, error) {
+	rows, err := r.db.Query(`
+		SELECT c.id, c.incident_id, c.user_id, c.comment, c.created_at, u.name
+		FROM incident_comments c
+		LEFT JOIN users u ON c.user_id = u.id
+		WHERE c.incident_id = ?
+		ORDER BY c.created_at ASC, c.id ASC
+	`, incidentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query incident comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []*models.IncidentComment{}
+	for rows.Next() {
+		comment := &models.IncidentComment{}
+		var userName sql.NullString
+		if err := rows.Scan(&comment.ID, &comment.IncidentID, &comment.UserID, &comment.Comment, &comment.CreatedAt, &userName); err != nil {
+			return nil, fmt.Errorf("failed to scan incident comment: %w", err)
+		}
+		comment.UserName = "Deleted User"
+		if userName.Valid {
+			comment.UserName = userName.String
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
+
+// LoadDetails loads the photos and comments of an incident
+func (r *IncidentRepository) LoadDetails(incident *models.Incident) error {
+	photos, err := r.GetPhotos(incident.ID)
+	if err != nil {
+		return err
+	}
+
+	comments, err := r.GetComments(incident.ID)
+	if err != nil {
+		return err
+	}
+
+	incident.Photos = photos
+	incident.Comments = comments
+	return nil
+}
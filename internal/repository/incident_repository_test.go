@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestIncidentRepository tests creating, listing and resolving incidents
+func TestIncidentRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewIncidentRepository(db)
+
+	walkerID := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+	adminID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	otherDogID := testutil.SeedTestDog(t, db, "Max", "Beagle", "green")
+	bookingID := testutil.SeedTestBooking(t, db, walkerID, dogID, "2025-11-03", "09:00", "completed")
+
+	incident := &models.Incident{
+		DogID:        dogID,
+		BookingID:    &bookingID,
+		ReportedBy:   walkerID,
+		IncidentType: models.IncidentTypeEscape,
+		Severity:     "medium",
+		Description:  "Hat sich aus dem Geschirr gewunden",
+		OccurredAt:   time.Now().Add(-time.Hour),
+	}
+
+	t.Run("create incident", func(t *testing.T) {
+		if err := repo.Create(incident); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		if incident.ID == 0 {
+			t.Error("Incident ID should be set after creation")
+		}
+
+		found, err := repo.FindByID(incident.ID)
+		if err != nil || found == nil {
+			t.Fatalf("FindByID() failed: %v", err)
+		}
+		if found.Status != models.IncidentStatusOpen {
+			t.Errorf("Expected status open, got %s", found.Status)
+		}
+		if found.DogName != "Bella" || found.ReporterName != "Walker" {
+			t.Errorf("Expected joined names Bella/Walker, got %s/%s", found.DogName, found.ReporterName)
+		}
+	})
+
+	t.Run("filter incidents", func(t *testing.T) {
+		other := &models.Incident{
+			DogID:        otherDogID,
+			ReportedBy:   adminID,
+			IncidentType: models.IncidentTypeBite,
+			Severity:     "critical",
+			Description:  "Biss in die Hand",
+			OccurredAt:   time.Now(),
+		}
+		if err := repo.Create(other); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+
+		mine, err := repo.FindAll(&models.IncidentFilterRequest{ReportedBy: &walkerID})
+		if err != nil {
+			t.Fatalf("FindAll() failed: %v", err)
+		}
+		if len(mine) != 1 || mine[0].ID != incident.ID {
+			t.Errorf("Expected only the walker's incident, got %d", len(mine))
+		}
+
+		critical := "critical"
+		severe, _ := repo.FindAll(&models.IncidentFilterRequest{Severity: &critical})
+		if len(severe) != 1 || severe[0].DogID != otherDogID {
+			t.Errorf("Expected one critical incident for the other dog, got %d", len(severe))
+		}
+
+		all, _ := repo.FindAll(nil)
+		if len(all) != 2 {
+			t.Errorf("Expected 2 incidents, got %d", len(all))
+		}
+	})
+
+	t.Run("photos and comments", func(t *testing.T) {
+		if err := repo.AddPhoto(&models.IncidentPhoto{IncidentID: incident.ID, Photo: "incidents/a.jpg", UploadedBy: walkerID}); err != nil {
+			t.Fatalf("AddPhoto() failed: %v", err)
+		}
+		if err := repo.AddComment(&models.IncidentComment{IncidentID: incident.ID, UserID: adminID, Comment: "Geschirr wird getauscht"}); err != nil {
+			t.Fatalf("AddComment() failed: %v", err)
+		}
+
+		if err := repo.LoadDetails(incident); err != nil {
+			t.Fatalf("LoadDetails() failed: %v", err)
+		}
+		if len(incident.Photos) != 1 || incident.Photos[0].Photo != "incidents/a.jpg" {
+			t.Errorf("Expected 1 photo, got %d", len(incident.Photos))
+		}
+		if len(incident.Comments) != 1 || incident.Comments[0].UserName != "Admin" {
+			t.Errorf("Expected 1 comment by Admin, got %d", len(incident.Comments))
+		}
+	})
+
+	t.Run("resolve and reopen", func(t *testing.T) {
+		resolution := "Neues Sicherheitsgeschirr"
+		if err := repo.UpdateStatus(incident.ID, models.IncidentStatusResolved, &resolution, adminID); err != nil {
+			t.Fatalf("UpdateStatus() failed: %v", err)
+		}
+
+		resolved, _ := repo.FindByID(incident.ID)
+		if resolved.ResolvedBy == nil || *resolved.ResolvedBy != adminID || resolved.ResolvedAt == nil {
+			t.Error("Expected resolved_by and resolved_at to be set")
+		}
+		if resolved.Resolution == nil || *resolved.Resolution != resolution {
+			t.Error("Expected resolution to be stored")
+		}
+
+		if err := repo.UpdateStatus(incident.ID, models.IncidentStatusInReview, nil, adminID); err != nil {
+			t.Fatalf("UpdateStatus() failed: %v", err)
+		}
+		reopened, _ := repo.FindByID(incident.ID)
+		if reopened.ResolvedAt != nil || reopened.ResolvedBy != nil {
+			t.Error("Expected resolved_by and resolved_at to be cleared when reopening")
+		}
+		if reopened.Resolution == nil {
+			t.Error("Expected resolution to be kept when reopening")
+		}
+
+		if err := repo.UpdateStatus(9999, models.IncidentStatusResolved, &resolution, adminID); err == nil {
+			t.Error("Expected error for unknown incident")
+		}
+	})
+
+	t.Run("append dog action", func(t *testing.T) {
+		repo.AppendDogAction(incident.ID, "first")
+		repo.AppendDogAction(incident.ID, "second")
+
+		found, _ := repo.FindByID(incident.ID)
+		if found.DogAction == nil || *found.DogAction != "first; second" {
+			t.Errorf("Expected combined dog action, got %v", found.DogAction)
+		}
+	})
+}
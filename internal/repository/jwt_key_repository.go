@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// encryptedKeyPrefix marks private keys encrypted with AES-GCM; keys stored before encryption have none
+const encryptedKeyPrefix = "enc:v1:"
+
+// JWTKeyRepository handles the JWT signing keys
+// Private keys are encrypted at rest with AES-GCM and keys derived from JWT_KEY_ENCRYPTION_KEY.
+// The first encryption key encrypts; the others only decrypt keys stored before it was changed.
+type JWTKeyRepository struct {
+	db             *sql.DB
+	encryptionKeys []string
+}
+
+// NewJWTKeyRepository creates a new JWT key repository; without encryption keys, private keys are stored in plain text
+func NewJWTKeyRepository(db *sql.DB, encryptionKeys []string) *JWTKeyRepository {
+	return &JWTKeyRepository{db: db, encryptionKeys: encryptionKeys}
+}
+
+const jwtKeyColumns = `kid, algorithm, private_key, public_key, created_at, retired_at`
+
+// ListVerifying returns the keys that are active or were retired after retiredAfter, newest first
+func (r *JWTKeyRepository) ListVerifying(retiredAfter time.Time) ([]*models.JWTSigningKey, error) {
+	rows, err := r.db.Query(`
+		SELECT `+jwtKeyColumns+` FROM jwt_signing_keys
+		WHERE retired_at IS NULL OR retired_at > ?
+		ORDER BY created_at DESC, kid DESC
+	`, retiredAfter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list JWT keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*models.JWTSigningKey{}
+	for rows.Next() {
+		key := &models.JWTSigningKey{}
+		var privateKey, publicKey sql.NullString
+		var retiredAt sql.NullTime
+		if err := rows.Scan(&key.Kid, &key.Algorithm, &privateKey, &publicKey, &key.CreatedAt, &retiredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan JWT key: %w", err)
+		}
+		if privateKey.Valid && privateKey.String != "" {
+			// A key that cannot be decrypted still verifies with its public key, if it has one
+			if plain, _, err := r.decrypt(key.Kid, privateKey.String); err != nil {
+				log.Printf("Warning: %v", err)
+				key.Unreadable = true
+			} else {
+				key.PrivateKey = &plain
+			}
+		}
+		if publicKey.Valid && publicKey.String != "" {
+			key.PublicKey = &publicKey.String
+		}
+		if retiredAt.Valid {
+			key.RetiredAt = &retiredAt.Time
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Exists returns true if a key with the kid exists, retired or not
+func (r *JWTKeyRepository) Exists(kid string) (bool, error) {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM jwt_signing_keys WHERE kid = ?`, kid).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check JWT key: %w", err)
+	}
+	return count > 0, nil
+}
+
+// FirstCreatedAt returns when the first key was created, or nil if there are no keys
+func (r *JWTKeyRepository) FirstCreatedAt() (*time.Time, error) {
+	var createdAt sql.NullTime
+	err := r.db.QueryRow(`SELECT created_at FROM jwt_signing_keys ORDER BY created_at ASC LIMIT 1`).Scan(&createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get first JWT key: %w", err)
+	}
+	if !createdAt.Valid {
+		return nil, nil
+	}
+	return &createdAt.Time, nil
+}
+
+// CreateIfNoneActive stores the first signing key; it returns false if another process already created one
+func (r *JWTKeyRepository) CreateIfNoneActive(key *models.JWTSigningKey) (bool, error) {
+	privateKey, err := r.encrypt(key)
+	if err != nil {
+		return false, err
+	}
+	result, err := r.db.Exec(`
+		INSERT INTO jwt_signing_keys (kid, algorithm, private_key, public_key, created_at)
+		SELECT ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM jwt_signing_keys WHERE retired_at IS NULL)
+	`, key.Kid, key.Algorithm, privateKey, key.PublicKey, key.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create JWT key: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check JWT key creation: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Rotate stores a new signing key and retires all other active keys
+func (r *JWTKeyRepository) Rotate(key *models.JWTSigningKey) error {
+	privateKey, err := r.encrypt(key)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO jwt_signing_keys (kid, algorithm, private_key, public_key, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, key.Kid, key.Algorithm, privateKey, key.PublicKey, key.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create JWT key: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE jwt_signing_keys SET retired_at = ? WHERE kid != ? AND retired_at IS NULL`,
+		key.CreatedAt, key.Kid); err != nil {
+		return fmt.Errorf("failed to retire JWT keys: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteRetiredBefore deletes keys retired before the cutoff and returns how many were deleted
+func (r *JWTKeyRepository) DeleteRetiredBefore(cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM jwt_signing_keys WHERE retired_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete retired JWT keys: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// EncryptStoredKeys encrypts private keys that are stored in plain text or with an older encryption key
+// with the current encryption key, and returns how many. Keys that cannot be decrypted are left as they are.
+func (r *JWTKeyRepository) EncryptStoredKeys() (int, error) {
+	if len(r.encryptionKeys) == 0 {
+		return 0, nil
+	}
+
+	rows, err := r.db.Query(`SELECT kid, private_key FROM jwt_signing_keys WHERE private_key IS NOT NULL AND private_key != ''`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list JWT keys: %w", err)
+	}
+	stored := map[string]string{}
+	for rows.Next() {
+		var kid, privateKey string
+		if err := rows.Scan(&kid, &privateKey); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan JWT key: %w", err)
+		}
+		stored[kid] = privateKey
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to list JWT keys: %w", err)
+	}
+
+	encrypted := 0
+	for kid, privateKey := range stored {
+		plain, keyIndex, err := r.decrypt(kid, privateKey)
+		if err != nil || keyIndex == 0 {
+			continue
+		}
+		sealed, err := r.seal(kid, plain)
+		if err != nil {
+			return encrypted, err
+		}
+		result, err := r.db.Exec(`UPDATE jwt_signing_keys SET private_key = ? WHERE kid = ? AND private_key = ?`, sealed, kid, privateKey)
+		if err != nil {
+			return encrypted, fmt.Errorf("failed to encrypt JWT key: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			encrypted++
+		}
+	}
+	return encrypted, nil
+}
+
+// encrypt returns the private key of a key to store, or nil if it has none
+func (r *JWTKeyRepository) encrypt(key *models.JWTSigningKey) (*string, error) {
+	if key.PrivateKey == nil || *key.PrivateKey == "" {
+		return nil, nil
+	}
+	if len(r.encryptionKeys) == 0 {
+		return key.PrivateKey, nil
+	}
+	sealed, err := r.seal(key.Kid, *key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// aeadFor returns the cipher of an encryption key
+func aeadFor(encryptionKey string) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, []byte(encryptionKey), nil, "gassigeher jwt signing keys", 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive JWT key encryption key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWT key cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts a private key with the current encryption key; the kid is authenticated, so stored keys cannot be swapped
+func (r *JWTKeyRepository) seal(kid, privateKey string) (string, error) {
+	aead, err := aeadFor(r.encryptionKeys[0])
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(privateKey), []byte(kid))
+	return encryptedKeyPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt returns a stored private key in plain text and the index of the encryption key that decrypted it;
+// keys stored in plain text are returned as they are, with index -1
+func (r *JWTKeyRepository) decrypt(kid, stored string) (string, int, error) {
+	if !strings.HasPrefix(stored, encryptedKeyPrefix) {
+		return stored, -1, nil
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedKeyPrefix))
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid encrypted private key of JWT key %s", kid)
+	}
+	for i, encryptionKey := range r.encryptionKeys {
+		aead, err := aeadFor(encryptionKey)
+		if err != nil {
+			return "", 0, err
+		}
+		if len(sealed) < aead.NonceSize() {
+			return "", 0, fmt.Errorf("invalid encrypted private key of JWT key %s", kid)
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		if plain, err := aead.Open(nil, nonce, ciphertext, []byte(kid)); err == nil {
+			return string(plain), i, nil
+		}
+	}
+	return "", 0, fmt.Errorf("private key of JWT key %s cannot be decrypted with JWT_KEY_ENCRYPTION_KEY", kid)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// MagicLinkRepository handles the tokens of email login links
+type MagicLinkRepository struct {
+	db *sql.DB
+}
+
+// NewMagicLinkRepository creates a new magic link repository
+func NewMagicLinkRepository(db *sql.DB) *MagicLinkRepository {
+	return &MagicLinkRepository{db: db}
+}
+
+// Create stores a new link and invalidates older unused links of the user, so only the latest link works
+func (r *MagicLinkRepository) Create(token *models.MagicLinkToken) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.Exec(`UPDATE magic_link_tokens SET expires_at = ? WHERE user_id = ? AND used_at IS NULL AND expires_at > ?`,
+		now, token.UserID, now); err != nil {
+		return fmt.Errorf("failed to invalidate magic links: %w", err)
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO magic_link_tokens (user_id, token_hash, device_hash, ip_address, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, token.UserID, token.TokenHash, token.DeviceHash, token.IPAddress, now, token.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create magic link: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get magic link ID: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit magic link: %w", err)
+	}
+
+	token.ID = int(id)
+	token.CreatedAt = now
+	return nil
+}
+
+// FindByTokenHash returns the link of a token, or nil
+func (r *MagicLinkRepository) FindByTokenHash(hash string) (*models.MagicLinkToken, error) {
+	token := &models.MagicLinkToken{}
+	var ipAddress sql.NullString
+	var usedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT id, user_id, token_hash, device_hash, ip_address, created_at, expires_at, used_at
+		FROM magic_link_tokens WHERE token_hash = ?
+	`, hash).Scan(&token.ID, &token.UserID, &token.TokenHash, &token.DeviceHash, &ipAddress,
+		&token.CreatedAt, &token.ExpiresAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find magic link: %w", err)
+	}
+
+	if ipAddress.Valid {
+		token.IPAddress = &ipAddress.String
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	return token, nil
+}
+
+// MarkUsed uses up a link; it returns false if it was already used or has expired,
+// so two requests with the same link cannot both log in
+func (r *MagicLinkRepository) MarkUsed(id int) (bool, error) {
+	now := time.Now()
+	result, err := r.db.Exec(`UPDATE magic_link_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL AND expires_at > ?`,
+		now, id, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to use magic link: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check magic link: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// CountSince returns how many links were requested for a user since a time
+func (r *MagicLinkRepository) CountSince(userID int, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM magic_link_tokens WHERE user_id = ? AND created_at > ?`, userID, since).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count magic links: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteByUser deletes all links of a user
+func (r *MagicLinkRepository) DeleteByUser(userID int) error {
+	if _, err := r.db.Exec(`DELETE FROM magic_link_tokens WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete magic links: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredBefore deletes links that expired before the cutoff and returns how many were deleted
+func (r *MagicLinkRepository) DeleteExpiredBefore(cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM magic_link_tokens WHERE expires_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired magic links: %w", err)
+	}
+	return result.RowsAffected()
+}
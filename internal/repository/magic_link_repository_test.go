@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestMagicLinkRepository tests that only the latest link works, single use and cleanup
+func TestMagicLinkRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewMagicLinkRepository(db)
+	userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+
+	create := func(hash string) *models.MagicLinkToken {
+		token := &models.MagicLinkToken{UserID: userID, TokenHash: hash, DeviceHash: "device", ExpiresAt: time.Now().Add(15 * time.Minute)}
+		if err := repo.Create(token); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		return token
+	}
+	first := create("hash-1")
+	second := create("hash-2")
+
+	if used, _ := repo.MarkUsed(first.ID); used {
+		t.Error("Expected a newer link to invalidate the older one")
+	}
+	if count, _ := repo.CountSince(userID, time.Now().Add(-time.Minute)); count != 2 {
+		t.Errorf("Expected 2 recent links, got %d", count)
+	}
+
+	if used, err := repo.MarkUsed(second.ID); err != nil || !used {
+		t.Fatalf("Expected the latest link to be usable, got %v (%v)", used, err)
+	}
+	if used, _ := repo.MarkUsed(second.ID); used {
+		t.Error("Expected a link to be usable only once")
+	}
+	found, err := repo.FindByTokenHash("hash-2")
+	if err != nil || found == nil || found.UsedAt == nil || found.DeviceHash != "device" {
+		t.Errorf("Unexpected link: %+v (%v)", found, err)
+	}
+	if missing, _ := repo.FindByTokenHash("unknown"); missing != nil {
+		t.Error("Expected nil for an unknown token")
+	}
+
+	db.Exec(`UPDATE magic_link_tokens SET expires_at = ? WHERE id = ?`, time.Now().AddDate(0, 0, -2), first.ID)
+	if deleted, err := repo.DeleteExpiredBefore(time.Now().AddDate(0, 0, -1)); err != nil || deleted != 1 {
+		t.Errorf("Expected 1 deleted link, got %d (%v)", deleted, err)
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// OIDCLoginRepository handles single sign-on logins in progress
+type OIDCLoginRepository struct {
+	db *sql.DB
+}
+
+// NewOIDCLoginRepository creates a new OIDC login repository
+func NewOIDCLoginRepository(db *sql.DB) *OIDCLoginRepository {
+	return &OIDCLoginRepository{db: db}
+}
+
+// Create stores a login started at the provider
+func (r *OIDCLoginRepository) Create(login *models.OIDCLogin) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		INSERT INTO oidc_logins (state_hash, provider, nonce, code_verifier, redirect_path, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, login.StateHash, login.Provider, login.Nonce, login.CodeVerifier, login.RedirectPath, now, login.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create OIDC login: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get OIDC login ID: %w", err)
+	}
+	login.ID = int(id)
+	login.CreatedAt = now
+	return nil
+}
+
+// FindByStateHash returns the login of a state, or nil
+func (r *OIDCLoginRepository) FindByStateHash(hash string) (*models.OIDCLogin, error) {
+	return r.findOne(`WHERE state_hash = ?`, hash)
+}
+
+// FindByLoginCodeHash returns the login of a login code, or nil
+func (r *OIDCLoginRepository) FindByLoginCodeHash(hash string) (*models.OIDCLogin, error) {
+	return r.findOne(`WHERE login_code_hash = ?`, hash)
+}
+
+// MarkCallback records the provider's callback for a login; it returns false if the callback
+// already happened or the login expired, so a state is accepted only once
+func (r *OIDCLoginRepository) MarkCallback(id int) (bool, error) {
+	now := time.Now()
+	return r.updateOnce(`UPDATE oidc_logins SET callback_at = ? WHERE id = ? AND callback_at IS NULL AND expires_at > ?`, now, id, now)
+}
+
+// SetLoginCode stores the user and the code the browser exchanges for a session
+func (r *OIDCLoginRepository) SetLoginCode(id, userID int, codeHash string, expiresAt time.Time) error {
+	if _, err := r.db.Exec(`UPDATE oidc_logins SET user_id = ?, login_code_hash = ?, expires_at = ? WHERE id = ?`,
+		userID, codeHash, expiresAt, id); err != nil {
+		return fmt.Errorf("failed to set OIDC login code: %w", err)
+	}
+	return nil
+}
+
+// MarkUsed uses up the login code; it returns false if it was already used or has expired
+func (r *OIDCLoginRepository) MarkUsed(id int) (bool, error) {
+	now := time.Now()
+	return r.updateOnce(`UPDATE oidc_logins SET used_at = ? WHERE id = ? AND used_at IS NULL AND expires_at > ?`, now, id, now)
+}
+
+// DeleteExpiredBefore deletes logins that expired before the cutoff and returns how many were deleted
+func (r *OIDCLoginRepository) DeleteExpiredBefore(cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM oidc_logins WHERE expires_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired OIDC logins: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *OIDCLoginRepository) updateOnce(query string, args ...interface{}) (bool, error) {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to update OIDC login: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check OIDC login: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *OIDCLoginRepository) findOne(where string, args ...interface{}) (*models.OIDCLogin, error) {
+	login := &models.OIDCLogin{}
+	var redirectPath, loginCodeHash sql.NullString
+	var userID sql.NullInt64
+	var callbackAt, usedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT id, state_hash, provider, nonce, code_verifier, redirect_path, user_id, login_code_hash,
+		       created_at, expires_at, callback_at, used_at
+		FROM oidc_logins `+where, args...).Scan(&login.ID, &login.StateHash, &login.Provider, &login.Nonce,
+		&login.CodeVerifier, &redirectPath, &userID, &loginCodeHash, &login.CreatedAt, &login.ExpiresAt,
+		&callbackAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find OIDC login: %w", err)
+	}
+
+	if redirectPath.Valid {
+		login.RedirectPath = &redirectPath.String
+	}
+	if userID.Valid {
+		id := int(userID.Int64)
+		login.UserID = &id
+	}
+	if loginCodeHash.Valid {
+		login.LoginCodeHash = &loginCodeHash.String
+	}
+	if callbackAt.Valid {
+		login.CallbackAt = &callbackAt.Time
+	}
+	if usedAt.Valid {
+		login.UsedAt = &usedAt.Time
+	}
+	return login, nil
+}
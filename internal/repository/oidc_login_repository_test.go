@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestOIDCLoginRepository tests that state and login code are accepted once and cleanup
+func TestOIDCLoginRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewOIDCLoginRepository(db)
+	userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+
+	redirect := "/dogs.html"
+	login := &models.OIDCLogin{StateHash: "state", Provider: "mock", Nonce: "nonce", CodeVerifier: "verifier",
+		RedirectPath: &redirect, ExpiresAt: time.Now().Add(10 * time.Minute)}
+	if err := repo.Create(login); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	if ok, err := repo.MarkCallback(login.ID); err != nil || !ok {
+		t.Fatalf("Expected the first callback to be accepted, got %v (%v)", ok, err)
+	}
+	if ok, _ := repo.MarkCallback(login.ID); ok {
+		t.Error("Expected a state to be accepted only once")
+	}
+
+	if err := repo.SetLoginCode(login.ID, userID, "code", time.Now().Add(2*time.Minute)); err != nil {
+		t.Fatalf("SetLoginCode() failed: %v", err)
+	}
+	found, err := repo.FindByLoginCodeHash("code")
+	if err != nil || found == nil || found.UserID == nil || *found.UserID != userID || found.CallbackAt == nil ||
+		found.RedirectPath == nil || *found.RedirectPath != redirect || found.Nonce != "nonce" {
+		t.Fatalf("Unexpected login: %+v (%v)", found, err)
+	}
+	if ok, _ := repo.MarkUsed(login.ID); !ok {
+		t.Error("Expected the login code to be usable")
+	}
+	if ok, _ := repo.MarkUsed(login.ID); ok {
+		t.Error("Expected the login code to be usable only once")
+	}
+	if missing, _ := repo.FindByStateHash("unknown"); missing != nil {
+		t.Error("Expected nil for an unknown state")
+	}
+
+	db.Exec(`UPDATE oidc_logins SET expires_at = ? WHERE id = ?`, time.Now().AddDate(0, 0, -2), login.ID)
+	if deleted, err := repo.DeleteExpiredBefore(time.Now().AddDate(0, 0, -1)); err != nil || deleted != 1 {
+		t.Errorf("Expected 1 deleted login, got %d (%v)", deleted, err)
+	}
+}
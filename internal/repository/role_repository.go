@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// RoleRepository handles roles, their permissions and their assignment to users
+type RoleRepository struct {
+	db *sql.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *sql.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// ListPermissions returns all permissions with their descriptions
+func (r *RoleRepository) ListPermissions() ([]*models.PermissionInfo, error) {
+	rows, err := r.db.Query(`SELECT name, COALESCE(description, '') FROM permissions ORDER BY name ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query permissions: %w", err)
+	}
+	defer rows.Close()
+
+	permissions := []*models.PermissionInfo{}
+	for rows.Next() {
+		permission := &models.PermissionInfo{}
+		if err := rows.Scan(&permission.Name, &permission.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, nil
+}
+
+// FindAll returns all roles with their permissions, system roles first
+func (r *RoleRepository) FindAll() ([]*models.Role, error) {
+	return r.findRoles(`
+		SELECT id, name, description, is_system, created_at, updated_at
+		FROM roles
+		ORDER BY is_system DESC, name ASC
+	`)
+}
+
+// FindByUser returns the roles assigned to a user
+func (r *RoleRepository) FindByUser(userID int) ([]*models.Role, error) {
+	return r.findRoles(`
+		SELECT r.id, r.name, r.description, r.is_system, r.created_at, r.updated_at
+		FROM roles r
+		JOIN user_roles ur ON ur.role_id = r.id
+		WHERE ur.user_id = ?
+		ORDER BY r.name ASC
+	`, userID)
+}
+
+// FindUserIDs returns the IDs of the users a role is assigned to
+func (r *RoleRepository) FindUserIDs(roleID int) ([]int, error) {
+	rows, err := r.db.Query(`SELECT user_id FROM user_roles WHERE role_id = ? ORDER BY user_id ASC`, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query role users: %w", err)
+	}
+	defer rows.Close()
+
+	userIDs := []int{}
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan role user: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, nil
+}
+
+// FindByID finds a role by ID
+func (r *RoleRepository) FindByID(id int) (*models.Role, error) {
+	roles, err := r.findRoles(`
+		SELECT id, name, description, is_system, created_at, updated_at
+		FROM roles
+		WHERE id = ?
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, nil
+	}
+	return roles[0], nil
+}
+
+// NameExists checks if another role already has the name (case-insensitive)
+func (r *RoleRepository) NameExists(name string, excludeID int) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM roles WHERE LOWER(name) = LOWER(?) AND id != ?`, name, excludeID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check role name: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Create creates a new role with its permissions
+func (r *RoleRepository) Create(role *models.Role) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`INSERT INTO roles (name, description, is_system, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+		role.Name, role.Description, false, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create role: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get role ID: %w", err)
+	}
+
+	if err := setRolePermissions(tx, int(id), role.Permissions); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit role: %w", err)
+	}
+
+	role.ID = int(id)
+	role.IsSystem = false
+	role.CreatedAt = now
+	role.UpdatedAt = now
+	return nil
+}
+
+// Update changes the name, description and permissions of a role
+func (r *RoleRepository) Update(role *models.Role) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	role.UpdatedAt = time.Now()
+	if _, err := tx.Exec(`UPDATE roles SET name = ?, description = ?, updated_at = ? WHERE id = ?`,
+		role.Name, role.Description, role.UpdatedAt, role.ID); err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+	if err := setRolePermissions(tx, role.ID, role.Permissions); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit role: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a role and removes it from all users
+func (r *RoleRepository) Delete(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Assignments are removed explicitly, SQLite only cascades with foreign keys enabled
+	if _, err := tx.Exec(`DELETE FROM user_roles WHERE role_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to remove role from users: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM role_permissions WHERE role_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete role permissions: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM roles WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit role deletion: %w", err)
+	}
+	return nil
+}
+
+// SetUserRoles replaces the roles of a user; roles the user keeps keep their assignment date
+// The is_admin flag of the user follows the admin role. assignedBy is 0 for automatic
+// assignments, e.g. by the group mapping of single sign-on
+func (r *RoleRepository) SetUserRoles(userID int, roleIDs []int, assignedBy int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current := map[int]bool{}
+	rows, err := tx.Query(`SELECT role_id FROM user_roles WHERE user_id = ?`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to query user roles: %w", err)
+	}
+	for rows.Next() {
+		var roleID int
+		if err := rows.Scan(&roleID); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan user role: %w", err)
+		}
+		current[roleID] = true
+	}
+	rows.Close()
+
+	var assigner interface{}
+	if assignedBy > 0 {
+		assigner = assignedBy
+	}
+
+	wanted := map[int]bool{}
+	now := time.Now()
+	for _, roleID := range roleIDs {
+		wanted[roleID] = true
+		if current[roleID] {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO user_roles (user_id, role_id, assigned_by, assigned_at) VALUES (?, ?, ?, ?)`,
+			userID, roleID, assigner, now); err != nil {
+			return fmt.Errorf("failed to assign role: %w", err)
+		}
+	}
+	for roleID := range current {
+		if wanted[roleID] {
+			continue
+		}
+		if _, err := tx.Exec(`DELETE FROM user_roles WHERE user_id = ? AND role_id = ?`, userID, roleID); err != nil {
+			return fmt.Errorf("failed to remove role: %w", err)
+		}
+	}
+
+	var adminRoles int
+	if err := tx.QueryRow(`
+		SELECT COUNT(*) FROM user_roles ur JOIN roles r ON r.id = ur.role_id
+		WHERE ur.user_id = ? AND r.name = ?
+	`, userID, models.RoleAdmin).Scan(&adminRoles); err != nil {
+		return fmt.Errorf("failed to check admin role: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE users SET is_admin = ?, updated_at = ? WHERE id = ?`, adminRoles > 0, now, userID); err != nil {
+		return fmt.Errorf("failed to update admin flag: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit user roles: %w", err)
+	}
+	return nil
+}
+
+// GetUserPermissions returns the permissions a user has through their roles
+// Users with the is_admin flag hold the admin role even without an assignment,
+// e.g. admins created directly in the database by setup scripts
+func (r *RoleRepository) GetUserPermissions(userID int) ([]models.Permission, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT rp.permission
+		FROM role_permissions rp
+		JOIN roles r ON r.id = rp.role_id
+		WHERE rp.role_id IN (SELECT role_id FROM user_roles WHERE user_id = ?)
+		   OR (r.name = ? AND EXISTS (SELECT 1 FROM users WHERE id = ? AND is_admin = ?))
+		ORDER BY rp.permission ASC
+	`, userID, models.RoleAdmin, userID, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user permissions: %w", err)
+	}
+	defer rows.Close()
+
+	permissions := []models.Permission{}
+	for rows.Next() {
+		var permission models.Permission
+		if err := rows.Scan(&permission); err != nil {
+			return nil, fmt.Errorf("failed to scan permission: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, nil
+}
+
+// findRoles runs a role query and loads the permissions of the roles
+func (r *RoleRepository) findRoles(query string, args ...interface{}) ([]*models.Role, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := []*models.Role{}
+	byID := map[int]*models.Role{}
+	for rows.Next() {
+		role := &models.Role{Permissions: []models.Permission{}}
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.IsSystem, &role.CreatedAt, &role.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, role)
+		byID[role.ID] = role
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read roles: %w", err)
+	}
+	if len(roles) == 0 {
+		return roles, nil
+	}
+
+	ids := make([]interface{}, 0, len(roles))
+	for _, role := range roles {
+		ids = append(ids, role.ID)
+	}
+	permRows, err := r.db.Query(`
+		SELECT role_id, permission FROM role_permissions
+		WHERE role_id IN (`+placeholders(len(ids))+`)
+		ORDER BY permission ASC
+	`, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query role permissions: %w", err)
+	}
+	defer permRows.Close()
+
+	for permRows.Next() {
+		var roleID int
+		var permission models.Permission
+		if err := permRows.Scan(&roleID, &permission); err != nil {
+			return nil, fmt.Errorf("failed to scan role permission: %w", err)
+		}
+		byID[roleID].Permissions = append(byID[roleID].Permissions, permission)
+	}
+
+	return roles, nil
+}
+
+// setRolePermissions replaces the permissions of a role
+func setRolePermissions(tx *sql.Tx, roleID int, permissions []models.Permission) error {
+	if _, err := tx.Exec(`DELETE FROM role_permissions WHERE role_id = ?`, roleID); err != nil {
+		return fmt.Errorf("failed to clear role permissions: %w", err)
+	}
+	for _, permission := range permissions {
+		if _, err := tx.Exec(`INSERT INTO role_permissions (role_id, permission) VALUES (?, ?)`, roleID, permission); err != nil {
+			return fmt.Errorf("failed to add role permission: %w", err)
+		}
+	}
+	return nil
+}
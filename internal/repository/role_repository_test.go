@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestRoleRepository tests system roles, custom roles, user roles and the admin flag
+func TestRoleRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewRoleRepository(db)
+	userRepo := NewUserRepository(db)
+
+	superAdminID := testutil.SeedTestUser(t, db, "super@example.com", "Super", "orange")
+	userID := testutil.SeedTestUser(t, db, "koordinator@example.com", "Koordinatorin", "blue")
+
+	roleByName := func(name string) *models.Role {
+		roles, err := repo.FindAll()
+		if err != nil {
+			t.Fatalf("FindAll() failed: %v", err)
+		}
+		for _, role := range roles {
+			if role.Name == name {
+				return role
+			}
+		}
+		t.Fatalf("Role %s not found", name)
+		return nil
+	}
+
+	t.Run("system roles", func(t *testing.T) {
+		admin := roleByName(models.RoleAdmin)
+		if !admin.IsSystem || len(admin.Permissions) != len(models.AllPermissions) {
+			t.Errorf("Expected admin system role with all permissions, got %+v", admin)
+		}
+		coordinator := roleByName("dog_coordinator")
+		if len(coordinator.Permissions) != 2 {
+			t.Errorf("Expected dog coordinator with 2 permissions, got %v", coordinator.Permissions)
+		}
+
+		permissions, _ := repo.ListPermissions()
+		if len(permissions) != len(models.AllPermissions) {
+			t.Errorf("Expected %d permissions, got %d", len(models.AllPermissions), len(permissions))
+		}
+	})
+
+	t.Run("user roles give permissions", func(t *testing.T) {
+		coordinator := roleByName("dog_coordinator")
+		reporting := roleByName("reporting")
+		if err := repo.SetUserRoles(userID, []int{coordinator.ID, reporting.ID}, superAdminID); err != nil {
+			t.Fatalf("SetUserRoles() failed: %v", err)
+		}
+
+		permissions, err := repo.GetUserPermissions(userID)
+		if err != nil {
+			t.Fatalf("GetUserPermissions() failed: %v", err)
+		}
+		if len(permissions) != 3 {
+			t.Errorf("Expected 3 permissions, got %v", permissions)
+		}
+		roles, _ := repo.FindByUser(userID)
+		if len(roles) != 2 {
+			t.Errorf("Expected 2 roles, got %d", len(roles))
+		}
+		if userIDs, err := repo.FindUserIDs(coordinator.ID); err != nil || len(userIDs) != 1 || userIDs[0] != userID {
+			t.Errorf("Expected the user to hold the coordinator role, got %v (%v)", userIDs, err)
+		}
+
+		user, _ := userRepo.FindByID(userID)
+		if user.IsAdmin {
+			t.Error("Roles without admin must not set the admin flag")
+		}
+	})
+
+	t.Run("admin role follows the admin flag", func(t *testing.T) {
+		admin := roleByName(models.RoleAdmin)
+		repo.SetUserRoles(userID, []int{admin.ID}, superAdminID)
+		if user, _ := userRepo.FindByID(userID); !user.IsAdmin {
+			t.Error("Expected the admin role to set the admin flag")
+		}
+
+		if err := userRepo.DemoteAdmin(userID); err != nil {
+			t.Fatalf("DemoteAdmin() failed: %v", err)
+		}
+		if roles, _ := repo.FindByUser(userID); len(roles) != 0 {
+			t.Errorf("Expected demotion to remove the admin role, got %d roles", len(roles))
+		}
+
+		if err := userRepo.PromoteToAdmin(userID); err != nil {
+			t.Fatalf("PromoteToAdmin() failed: %v", err)
+		}
+		roles, _ := repo.FindByUser(userID)
+		if len(roles) != 1 || roles[0].Name != models.RoleAdmin {
+			t.Errorf("Expected promotion to assign the admin role, got %v", roles)
+		}
+	})
+
+	t.Run("admin flag without assignment", func(t *testing.T) {
+		flaggedID := testutil.SeedTestUser(t, db, "script-admin@example.com", "Skript Admin", "orange")
+		db.Exec(`UPDATE users SET is_admin = 1 WHERE id = ?`, flaggedID)
+
+		permissions, _ := repo.GetUserPermissions(flaggedID)
+		if len(permissions) != len(models.AllPermissions) {
+			t.Errorf("Expected admins created by scripts to have all permissions, got %v", permissions)
+		}
+	})
+
+	t.Run("custom roles", func(t *testing.T) {
+		role := &models.Role{Name: "Vorfälle", Permissions: []models.Permission{models.PermissionManageIncidents}}
+		if err := repo.Create(role); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		if exists, _ := repo.NameExists("vorfälle", 0); !exists {
+			t.Error("Expected name to exist")
+		}
+
+		role.Permissions = []models.Permission{models.PermissionManageIncidents, models.PermissionViewReports}
+		if err := repo.Update(role); err != nil {
+			t.Fatalf("Update() failed: %v", err)
+		}
+		if found, _ := repo.FindByID(role.ID); len(found.Permissions) != 2 {
+			t.Errorf("Expected 2 permissions after update, got %v", found.Permissions)
+		}
+
+		repo.SetUserRoles(userID, []int{role.ID}, superAdminID)
+		if err := repo.Delete(role.ID); err != nil {
+			t.Fatalf("Delete() failed: %v", err)
+		}
+		if found, _ := repo.FindByID(role.ID); found != nil {
+			t.Error("Expected role to be deleted")
+		}
+		if permissions, _ := repo.GetUserPermissions(userID); len(permissions) != 0 {
+			t.Errorf("Expected no permissions after deleting the role, got %v", permissions)
+		}
+	})
+}
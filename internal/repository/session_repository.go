@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// SessionRepository handles login sessions and their refresh tokens
+type SessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+const sessionColumns = `id, user_id, refresh_token_hash, previous_token_hash, user_agent, ip_address,
+	created_at, last_used_at, expires_at, revoked_at`
+
+// Create creates a new session
+func (r *SessionRepository) Create(session *models.UserSession) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		INSERT INTO user_sessions (user_id, refresh_token_hash, user_agent, ip_address, created_at, last_used_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, session.UserID, session.RefreshTokenHash, session.UserAgent, session.IPAddress, now, now, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get session ID: %w", err)
+	}
+
+	session.ID = int(id)
+	session.CreatedAt = now
+	session.LastUsedAt = now
+	return nil
+}
+
+// FindByID returns a session, or nil if it does not exist
+func (r *SessionRepository) FindByID(id int) (*models.UserSession, error) {
+	return r.findOne(`SELECT `+sessionColumns+` FROM user_sessions WHERE id = ?`, id)
+}
+
+// FindByTokenHash returns the session of a current refresh token, or nil
+func (r *SessionRepository) FindByTokenHash(hash string) (*models.UserSession, error) {
+	return r.findOne(`SELECT `+sessionColumns+` FROM user_sessions WHERE refresh_token_hash = ?`, hash)
+}
+
+// FindByPreviousTokenHash returns the session whose refresh token was rotated away from this one, or nil
+func (r *SessionRepository) FindByPreviousTokenHash(hash string) (*models.UserSession, error) {
+	return r.findOne(`SELECT `+sessionColumns+` FROM user_sessions WHERE previous_token_hash = ?`, hash)
+}
+
+// ListActiveByUser returns the sessions of a user that are neither revoked nor expired, most recently used first
+func (r *SessionRepository) ListActiveByUser(userID int) ([]*models.UserSession, error) {
+	rows, err := r.db.Query(`
+		SELECT `+sessionColumns+` FROM user_sessions
+		WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ?
+		ORDER BY last_used_at DESC, id DESC
+	`, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*models.UserSession{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// Rotate replaces the refresh token of a session; it returns false if the old token is no longer current,
+// so two requests with the same token cannot both rotate it
+func (r *SessionRepository) Rotate(id int, oldHash, newHash, userAgent, ipAddress string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE user_sessions
+		SET refresh_token_hash = ?, previous_token_hash = ?, user_agent = ?, ip_address = ?, last_used_at = ?
+		WHERE id = ? AND refresh_token_hash = ? AND revoked_at IS NULL
+	`, newHash, oldHash, optionalValue(userAgent), optionalValue(ipAddress), time.Now(), id, oldHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check refresh token rotation: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// IsActive returns true if the session exists and is neither revoked nor expired
+func (r *SessionRepository) IsActive(id int) (bool, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM user_sessions WHERE id = ? AND revoked_at IS NULL AND expires_at > ?`,
+		id, time.Now()).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Revoke ends a session
+func (r *SessionRepository) Revoke(id int) error {
+	if _, err := r.db.Exec(`UPDATE user_sessions SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllForUser ends all sessions of a user except exceptID (0 for none) and returns how many were ended
+func (r *SessionRepository) RevokeAllForUser(userID, exceptID int) (int64, error) {
+	result, err := r.db.Exec(`UPDATE user_sessions SET revoked_at = ? WHERE user_id = ? AND id != ? AND revoked_at IS NULL`,
+		time.Now(), userID, exceptID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// DeleteByUser deletes all sessions of a user
+func (r *SessionRepository) DeleteByUser(userID int) error {
+	if _, err := r.db.Exec(`DELETE FROM user_sessions WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return nil
+}
+
+// DeleteEndedBefore deletes sessions that expired or were revoked before the cutoff and returns how many were deleted
+func (r *SessionRepository) DeleteEndedBefore(cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM user_sessions WHERE expires_at < ? OR revoked_at < ?`, cutoff, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete ended sessions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// findOne runs a query for a single session
+func (r *SessionRepository) findOne(query string, args ...interface{}) (*models.UserSession, error) {
+	session, err := scanSession(r.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return session, err
+}
+
+// scanSession scans a row selected with sessionColumns
+func scanSession(row interface{ Scan(...interface{}) error }) (*models.UserSession, error) {
+	session := &models.UserSession{}
+	var previous, userAgent, ipAddress sql.NullString
+	var revokedAt sql.NullTime
+	err := row.Scan(&session.ID, &session.UserID, &session.RefreshTokenHash, &previous, &userAgent, &ipAddress,
+		&session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan session: %w", err)
+	}
+
+	if previous.Valid {
+		session.PreviousTokenHash = &previous.String
+	}
+	if userAgent.Valid {
+		session.UserAgent = &userAgent.String
+	}
+	if ipAddress.Valid {
+		session.IPAddress = &ipAddress.String
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	return session, nil
+}
+
+// optionalValue stores empty strings as NULL
+func optionalValue(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestSessionRepository tests creating, rotating, listing and revoking sessions
+func TestSessionRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewSessionRepository(db)
+	userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+
+	agent := "Firefox"
+	create := func(hash string, expiresAt time.Time) *models.UserSession {
+		session := &models.UserSession{UserID: userID, RefreshTokenHash: hash, UserAgent: &agent, ExpiresAt: expiresAt}
+		if err := repo.Create(session); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		return session
+	}
+	first := create("hash-1", time.Now().Add(time.Hour))
+	second := create("hash-2", time.Now().Add(time.Hour))
+	create("hash-expired", time.Now().Add(-time.Hour))
+
+	t.Run("rotate", func(t *testing.T) {
+		if ok, err := repo.Rotate(first.ID, "hash-1", "hash-1b", "Chrome", "192.0.2.1"); err != nil || !ok {
+			t.Fatalf("Expected rotation to succeed, got %v (%v)", ok, err)
+		}
+		if ok, _ := repo.Rotate(first.ID, "hash-1", "hash-1c", "Chrome", "192.0.2.1"); ok {
+			t.Error("Expected a second rotation with the old token to fail")
+		}
+
+		session, _ := repo.FindByTokenHash("hash-1b")
+		if session == nil || session.ID != first.ID || *session.UserAgent != "Chrome" || *session.IPAddress != "192.0.2.1" {
+			t.Errorf("Unexpected rotated session: %+v", session)
+		}
+		if old, _ := repo.FindByTokenHash("hash-1"); old != nil {
+			t.Error("Expected the old token to be gone")
+		}
+		if reused, _ := repo.FindByPreviousTokenHash("hash-1"); reused == nil || reused.ID != first.ID {
+			t.Error("Expected the old token to be found as previous token")
+		}
+	})
+
+	t.Run("list and revoke", func(t *testing.T) {
+		sessions, err := repo.ListActiveByUser(userID)
+		if err != nil {
+			t.Fatalf("ListActiveByUser() failed: %v", err)
+		}
+		if len(sessions) != 2 {
+			t.Fatalf("Expected 2 active sessions, got %d", len(sessions))
+		}
+
+		count, err := repo.RevokeAllForUser(userID, second.ID)
+		if err != nil {
+			t.Fatalf("RevokeAllForUser() failed: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Expected 2 revoked sessions (including the expired one), got %d", count)
+		}
+		if active, _ := repo.IsActive(first.ID); active {
+			t.Error("Expected the first session to be revoked")
+		}
+		if active, _ := repo.IsActive(second.ID); !active {
+			t.Error("Expected the excepted session to stay active")
+		}
+	})
+
+	t.Run("delete ended", func(t *testing.T) {
+		deleted, err := repo.DeleteEndedBefore(time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("DeleteEndedBefore() failed: %v", err)
+		}
+		if deleted != 2 {
+			t.Errorf("Expected the revoked and the expired session to be deleted, got %d", deleted)
+		}
+		if session, _ := repo.FindByID(second.ID); session == nil {
+			t.Error("Expected the active session to be kept")
+		}
+	})
+}
@@ -66,8 +66,8 @@ func TestSettingsRepository_GetAll(t *testing.T) {
 			t.Fatalf("GetAll() failed: %v", err)
 		}
 
-		if len(settings) != 8 {
-			t.Errorf("Expected 8 settings, got %d", len(settings))
+		if len(settings) != 21 {
+			t.Errorf("Expected 21 settings, got %d", len(settings))
 		}
 
 		// Verify all expected settings are present
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// TwoFactorRepository handles TOTP secrets and recovery codes
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+// NewTwoFactorRepository creates a new two-factor repository
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// FindByUserID returns the TOTP secret of a user, or nil if none was set up
+func (r *TwoFactorRepository) FindByUserID(userID int) (*models.UserTOTP, error) {
+	totp := &models.UserTOTP{}
+	var enabledAt, lockedUntil sql.NullTime
+	var failedAttempts sql.NullInt64
+	err := r.db.QueryRow(`
+		SELECT user_id, secret, enabled, enabled_at, last_used_step, failed_attempts, locked_until, created_at
+		FROM user_totp WHERE user_id = ?
+	`, userID).Scan(&totp.UserID, &totp.Secret, &totp.Enabled, &enabledAt, &totp.LastUsedStep, &failedAttempts, &lockedUntil, &totp.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find TOTP secret: %w", err)
+	}
+	if enabledAt.Valid {
+		totp.EnabledAt = &enabledAt.Time
+	}
+	totp.FailedAttempts = int(failedAttempts.Int64)
+	if lockedUntil.Valid {
+		totp.LockedUntil = &lockedUntil.Time
+	}
+	return totp, nil
+}
+
+// SavePending stores a new secret that is not enabled yet, replacing a previous pending one
+func (r *TwoFactorRepository) SavePending(userID int, secret string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM user_totp WHERE user_id = ? AND enabled = ?`, userID, false); err != nil {
+		return fmt.Errorf("failed to delete pending TOTP secret: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO user_totp (user_id, secret, enabled, last_used_step, created_at) VALUES (?, ?, ?, ?, ?)`,
+		userID, secret, false, 0, time.Now()); err != nil {
+		return fmt.Errorf("failed to save TOTP secret: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit TOTP secret: %w", err)
+	}
+	return nil
+}
+
+// Enable enables the pending secret of a user and stores the hashes of the first recovery codes
+func (r *TwoFactorRepository) Enable(userID int, step int64, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE user_totp SET enabled = ?, enabled_at = ?, last_used_step = ? WHERE user_id = ? AND enabled = ?`,
+		true, time.Now(), step, userID, false)
+	if err != nil {
+		return fmt.Errorf("failed to enable TOTP: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("no pending TOTP secret")
+	}
+	if err := replaceRecoveryCodes(tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit TOTP: %w", err)
+	}
+	return nil
+}
+
+// UseStep records an accepted time step; it returns false if the step or a later one was already used
+func (r *TwoFactorRepository) UseStep(userID int, step int64) (bool, error) {
+	result, err := r.db.Exec(`UPDATE user_totp SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?`, step, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to update TOTP step: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check TOTP step: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RecordFailure counts a wrong code; once maxAttempts are reached, codes are locked until lockedUntil
+// and the count starts over. It returns true if this failure locked the codes.
+func (r *TwoFactorRepository) RecordFailure(userID int, maxAttempts int, lockedUntil time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE user_totp SET failed_attempts = COALESCE(failed_attempts, 0) + 1 WHERE user_id = ?`, userID); err != nil {
+		return false, fmt.Errorf("failed to count wrong code: %w", err)
+	}
+	var attempts int
+	if err := tx.QueryRow(`SELECT COALESCE(failed_attempts, 0) FROM user_totp WHERE user_id = ?`, userID).Scan(&attempts); err != nil {
+		return false, fmt.Errorf("failed to count wrong codes: %w", err)
+	}
+	locked := attempts >= maxAttempts
+	if locked {
+		if _, err := tx.Exec(`UPDATE user_totp SET failed_attempts = 0, locked_until = ? WHERE user_id = ?`, lockedUntil, userID); err != nil {
+			return false, fmt.Errorf("failed to lock codes: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit wrong code: %w", err)
+	}
+	return locked, nil
+}
+
+// ResetFailures clears the count of wrong codes after an accepted one
+func (r *TwoFactorRepository) ResetFailures(userID int) error {
+	if _, err := r.db.Exec(`UPDATE user_totp SET failed_attempts = 0, locked_until = NULL WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to reset wrong codes: %w", err)
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes replaces all recovery codes of a user
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(userID int, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recovery codes: %w", err)
+	}
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used; it returns false if there is no such code
+func (r *TwoFactorRepository) UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	result, err := r.db.Exec(`UPDATE user_recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL`,
+		time.Now(), userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check recovery code: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// CountUnusedRecoveryCodes returns how many recovery codes a user has left
+func (r *TwoFactorRepository) CountUnusedRecoveryCodes(userID int) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM user_recovery_codes WHERE user_id = ? AND used_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+// Delete removes the secret and recovery codes of a user, which turns 2FA off
+func (r *TwoFactorRepository) Delete(userID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM user_totp WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete TOTP secret: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit 2FA removal: %w", err)
+	}
+	return nil
+}
+
+// replaceRecoveryCodes deletes all recovery codes of a user and inserts the new ones
+func replaceRecoveryCodes(tx *sql.Tx, userID int, codeHashes []string) error {
+	if _, err := tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	now := time.Now()
+	for _, hash := range codeHashes {
+		if _, err := tx.Exec(`INSERT INTO user_recovery_codes (user_id, code_hash, created_at) VALUES (?, ?, ?)`, userID, hash, now); err != nil {
+			return fmt.Errorf("failed to save recovery code: %w", err)
+		}
+	}
+	return nil
+}
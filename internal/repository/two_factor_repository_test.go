@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestTwoFactorRepository tests pending secrets, replay protection, recovery codes and the lock after wrong codes
+func TestTwoFactorRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewTwoFactorRepository(db)
+	userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+
+	if totp, err := repo.FindByUserID(userID); err != nil || totp != nil {
+		t.Fatalf("Expected no secret, got %+v (%v)", totp, err)
+	}
+
+	if err := repo.SavePending(userID, "FIRST"); err != nil {
+		t.Fatalf("SavePending() failed: %v", err)
+	}
+	if err := repo.SavePending(userID, "SECOND"); err != nil {
+		t.Fatalf("SavePending() should replace a pending secret: %v", err)
+	}
+	if err := repo.Enable(userID, 100, []string{"hash-a", "hash-b"}); err != nil {
+		t.Fatalf("Enable() failed: %v", err)
+	}
+
+	totp, _ := repo.FindByUserID(userID)
+	if totp.Secret != "SECOND" || !totp.Enabled || totp.EnabledAt == nil || totp.LastUsedStep != 100 {
+		t.Errorf("Unexpected secret: %+v", totp)
+	}
+	if err := repo.Enable(userID, 101, nil); err == nil {
+		t.Error("Expected enabling twice to fail")
+	}
+
+	t.Run("steps are used once", func(t *testing.T) {
+		if ok, _ := repo.UseStep(userID, 100); ok {
+			t.Error("Expected the step used for enabling to be rejected")
+		}
+		if ok, _ := repo.UseStep(userID, 101); !ok {
+			t.Error("Expected a later step to be accepted")
+		}
+	})
+
+	t.Run("recovery codes", func(t *testing.T) {
+		if ok, _ := repo.UseRecoveryCode(userID, "hash-a"); !ok {
+			t.Error("Expected the recovery code to be accepted")
+		}
+		if ok, _ := repo.UseRecoveryCode(userID, "hash-a"); ok {
+			t.Error("Expected a used recovery code to be rejected")
+		}
+		if count, _ := repo.CountUnusedRecoveryCodes(userID); count != 1 {
+			t.Errorf("Expected 1 unused recovery code, got %d", count)
+		}
+
+		repo.ReplaceRecoveryCodes(userID, []string{"hash-c", "hash-d", "hash-e"})
+		if count, _ := repo.CountUnusedRecoveryCodes(userID); count != 3 {
+			t.Errorf("Expected 3 new recovery codes, got %d", count)
+		}
+	})
+
+	t.Run("wrong codes lock", func(t *testing.T) {
+		until := time.Now().Add(15 * time.Minute)
+		if locked, err := repo.RecordFailure(userID, 2, until); err != nil || locked {
+			t.Fatalf("Expected the first wrong code not to lock, got %v (%v)", locked, err)
+		}
+		if locked, _ := repo.RecordFailure(userID, 2, until); !locked {
+			t.Error("Expected the second wrong code to lock")
+		}
+		totp, _ := repo.FindByUserID(userID)
+		if totp.FailedAttempts != 0 || totp.LockedUntil == nil {
+			t.Errorf("Expected a lock and the count to start over, got %d attempts until %v", totp.FailedAttempts, totp.LockedUntil)
+		}
+
+		repo.RecordFailure(userID, 2, until)
+		if err := repo.ResetFailures(userID); err != nil {
+			t.Fatalf("ResetFailures() failed: %v", err)
+		}
+		if totp, _ := repo.FindByUserID(userID); totp.FailedAttempts != 0 || totp.LockedUntil != nil {
+			t.Errorf("Expected no wrong codes and no lock, got %+v", totp)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := repo.Delete(userID); err != nil {
+			t.Fatalf("Delete() failed: %v", err)
+		}
+		if totp, _ := repo.FindByUserID(userID); totp != nil {
+			t.Error("Expected the secret to be deleted")
+		}
+		if count, _ := repo.CountUnusedRecoveryCodes(userID); count != 0 {
+			t.Errorf("Expected no recovery codes, got %d", count)
+		}
+	})
+}
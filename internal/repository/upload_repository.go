@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// UploadRepository handles queries across all tables that store upload paths
+type UploadRepository struct {
+	db *sql.DB
+}
+
+// NewUploadRepository creates a new upload repository
+func NewUploadRepository(db *sql.DB) *UploadRepository {
+	return &UploadRepository{db: db}
+}
+
+// FindReferences returns every upload path stored in the database
+// Anonymized users have no profile photo, so their old files are not referenced
+func (r *UploadRepository) FindReferences() ([]*models.UploadReference, error) {
+	query := `
+		SELECT 'users.profile_photo', id, profile_photo FROM users
+		WHERE profile_photo IS NOT NULL AND profile_photo != ''
+		UNION ALL
+		SELECT 'dogs.photo', id, photo FROM dogs
+		WHERE photo IS NOT NULL AND photo != ''
+		UNION ALL
+		SELECT 'dogs.photo_thumbnail', id, photo_thumbnail FROM dogs
+		WHERE photo_thumbnail IS NOT NULL AND photo_thumbnail != ''
+		UNION ALL
+		SELECT 'dog_photos.photo', id, photo FROM dog_photos
+		UNION ALL
+		SELECT 'dog_photos.photo_thumbnail', id, photo_thumbnail FROM dog_photos
+		WHERE photo_thumbnail IS NOT NULL AND photo_thumbnail != ''
+		UNION ALL
+		SELECT 'incident_photos.photo', id, photo FROM incident_photos
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query upload references: %w", err)
+	}
+	defer rows.Close()
+
+	references := []*models.UploadReference{}
+	for rows.Next() {
+		reference := &models.UploadReference{}
+		if err := rows.Scan(&reference.Source, &reference.RecordID, &reference.Path); err != nil {
+			return nil, fmt.Errorf("failed to scan upload reference: %w", err)
+		}
+		references = append(references, reference)
+	}
+
+	return references, rows.Err()
+}
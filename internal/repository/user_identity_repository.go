@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// UserIdentityRepository handles the accounts at identity providers linked to users
+type UserIdentityRepository struct {
+	db *sql.DB
+}
+
+// NewUserIdentityRepository creates a new user identity repository
+func NewUserIdentityRepository(db *sql.DB) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+// Create links an identity to a user
+func (r *UserIdentityRepository) Create(identity *models.UserIdentity) error {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		INSERT INTO user_identities (user_id, provider, subject, email, created_at, last_login_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, identity.UserID, identity.Provider, identity.Subject, identity.Email, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get user identity ID: %w", err)
+	}
+	identity.ID = int(id)
+	identity.CreatedAt = now
+	identity.LastLoginAt = &now
+	return nil
+}
+
+// FindByProviderSubject returns the identity of a provider's user, or nil
+func (r *UserIdentityRepository) FindByProviderSubject(provider, subject string) (*models.UserIdentity, error) {
+	identities, err := r.find(`WHERE provider = ? AND subject = ?`, provider, subject)
+	if err != nil || len(identities) == 0 {
+		return nil, err
+	}
+	return identities[0], nil
+}
+
+// FindByUser returns the identities linked to a user
+func (r *UserIdentityRepository) FindByUser(userID int) ([]*models.UserIdentity, error) {
+	return r.find(`WHERE user_id = ? ORDER BY provider ASC`, userID)
+}
+
+// UpdateLogin records a login and the email address the provider currently reports
+func (r *UserIdentityRepository) UpdateLogin(id int, email *string) error {
+	if _, err := r.db.Exec(`UPDATE user_identities SET last_login_at = ?, email = ? WHERE id = ?`, time.Now(), email, id); err != nil {
+		return fmt.Errorf("failed to update user identity: %w", err)
+	}
+	return nil
+}
+
+// DeleteByUser removes all identities of a user
+func (r *UserIdentityRepository) DeleteByUser(userID int) error {
+	if _, err := r.db.Exec(`DELETE FROM user_identities WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to delete user identities: %w", err)
+	}
+	return nil
+}
+
+func (r *UserIdentityRepository) find(where string, args ...interface{}) ([]*models.UserIdentity, error) {
+	rows, err := r.db.Query(`
+		SELECT id, user_id, provider, subject, email, created_at, last_login_at
+		FROM user_identities `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := []*models.UserIdentity{}
+	for rows.Next() {
+		identity := &models.UserIdentity{}
+		var email sql.NullString
+		var lastLoginAt sql.NullTime
+		if err := rows.Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &email,
+			&identity.CreatedAt, &lastLoginAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user identity: %w", err)
+		}
+		if email.Valid {
+			identity.Email = &email.String
+		}
+		if lastLoginAt.Valid {
+			identity.LastLoginAt = &lastLoginAt.Time
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
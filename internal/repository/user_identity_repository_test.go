@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestUserIdentityRepository tests linking, lookup by subject and deletion of identities
+func TestUserIdentityRepository(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewUserIdentityRepository(db)
+	userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+
+	email := "anna@example.com"
+	identity := &models.UserIdentity{UserID: userID, Provider: "mock", Subject: "idp-anna", Email: &email}
+	if err := repo.Create(identity); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := repo.Create(&models.UserIdentity{UserID: userID, Provider: "mock", Subject: "idp-anna"}); err == nil {
+		t.Error("Expected a subject to be linked only once per provider")
+	}
+
+	newEmail := "anna@new.example.com"
+	if err := repo.UpdateLogin(identity.ID, &newEmail); err != nil {
+		t.Fatalf("UpdateLogin() failed: %v", err)
+	}
+	found, err := repo.FindByProviderSubject("mock", "idp-anna")
+	if err != nil || found == nil || found.UserID != userID || found.Email == nil || *found.Email != newEmail || found.LastLoginAt == nil {
+		t.Fatalf("Unexpected identity: %+v (%v)", found, err)
+	}
+	if missing, _ := repo.FindByProviderSubject("other", "idp-anna"); missing != nil {
+		t.Error("Expected subjects to be scoped to the provider")
+	}
+
+	if err := repo.DeleteByUser(userID); err != nil {
+		t.Fatalf("DeleteByUser() failed: %v", err)
+	}
+	if identities, _ := repo.FindByUser(userID); len(identities) != 0 {
+		t.Errorf("Expected no identities, got %d", len(identities))
+	}
+}
@@ -377,6 +377,20 @@ func (r *UserRepository) DeleteAccount(userID int) error {
 		return fmt.Errorf("failed to delete account: %w", err)
 	}
 
+	// The row is kept, so the 2FA secret, recovery codes, sessions, login links and SSO identities are not removed by the foreign keys
+	if err := NewTwoFactorRepository(r.db).Delete(userID); err != nil {
+		return fmt.Errorf("failed to delete two-factor data: %w", err)
+	}
+	if err := NewSessionRepository(r.db).DeleteByUser(userID); err != nil {
+		return err
+	}
+	if err := NewMagicLinkRepository(r.db).DeleteByUser(userID); err != nil {
+		return err
+	}
+	if err := NewUserIdentityRepository(r.db).DeleteByUser(userID); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -556,25 +570,72 @@ func (r *UserRepository) FindAll(activeOnly *bool) ([]*models.User, error) {
 	return users, nil
 }
 
+// FindAdmins finds all active admins (including super admins), e.g. for staff notifications
+func (r *UserRepository) FindAdmins() ([]*models.User, error) {
+	active := true
+	users, err := r.FindAll(&active)
+	if err != nil {
+		return nil, err
+	}
+
+	admins := []*models.User{}
+	for _, user := range users {
+		if user.IsAdmin || user.IsSuperAdmin {
+			admins = append(admins, user)
+		}
+	}
+
+	return admins, nil
+}
+
 // PromoteToAdmin promotes a user to admin role
 // DONE
 func (r *UserRepository) PromoteToAdmin(userID int) error {
-	query := `UPDATE users SET is_admin = ?, updated_at = ? WHERE id = ?`
-	_, err := r.db.Exec(query, true, time.Now(), userID)
+	tx, err := r.db.Begin()
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.Exec(`UPDATE users SET is_admin = ?, updated_at = ? WHERE id = ?`, true, now, userID); err != nil {
 		return fmt.Errorf("failed to promote user to admin: %w", err)
 	}
+	// The admin flag and the admin role go together
+	if _, err := tx.Exec(`
+		INSERT INTO user_roles (user_id, role_id, assigned_at)
+		SELECT ?, id, ? FROM roles
+		WHERE name = ? AND NOT EXISTS (SELECT 1 FROM user_roles WHERE user_id = ? AND role_id = roles.id)
+	`, userID, now, models.RoleAdmin, userID); err != nil {
+		return fmt.Errorf("failed to assign admin role: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit promotion: %w", err)
+	}
 	return nil
 }
 
 // DemoteAdmin revokes admin privileges from a user
 // DONE
 func (r *UserRepository) DemoteAdmin(userID int) error {
-	query := `UPDATE users SET is_admin = ?, updated_at = ? WHERE id = ?`
-	_, err := r.db.Exec(query, false, time.Now(), userID)
+	tx, err := r.db.Begin()
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE users SET is_admin = ?, updated_at = ? WHERE id = ?`, false, time.Now(), userID); err != nil {
 		return fmt.Errorf("failed to demote admin: %w", err)
 	}
+	if _, err := tx.Exec(`DELETE FROM user_roles WHERE user_id = ? AND role_id IN (SELECT id FROM roles WHERE name = ?)`,
+		userID, models.RoleAdmin); err != nil {
+		return fmt.Errorf("failed to remove admin role: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit demotion: %w", err)
+	}
 	return nil
 }
 
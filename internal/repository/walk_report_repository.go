@@ -0,0 +1,280 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+)
+
+// WalkReportRepository handles walk report database operations
+type WalkReportRepository struct {
+	db *sql.DB
+}
+
+// NewWalkReportRepository creates a new walk report repository
+func NewWalkReportRepository(db *sql.DB) *WalkReportRepository {
+	return &WalkReportRepository{db: db}
+}
+
+// Save creates the report for a booking and dog, or replaces the existing one
+func (r *WalkReportRepository) Save(report *models.WalkReport) error {
+	existing, err := r.FindByBookingAndDog(report.BookingID, report.DogID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if existing != nil {
+		query := `
+			UPDATE walk_reports SET
+				user_id = ?, leash_pulling = ?, dog_reactivity = ?, people_reactivity = ?,
+				bathroom_pee = ?, bathroom_poop = ?, distance_km = ?,
+				had_incident = ?, incident_description = ?, notes = ?, updated_at = ?
+			WHERE id = ?
+		`
+		_, err := r.db.Exec(query,
+			report.UserID, report.LeashPulling, report.DogReactivity, report.PeopleReactivity,
+			report.BathroomPee, report.BathroomPoop, report.DistanceKm,
+			report.HadIncident, report.IncidentDescription, report.Notes, now,
+			existing.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update walk report: %w", err)
+		}
+
+		report.ID = existing.ID
+		report.CreatedAt = existing.CreatedAt
+		report.UpdatedAt = now
+		return nil
+	}
+
+	query := `
+		INSERT INTO walk_reports (
+			booking_id, dog_id, user_id, leash_pulling, dog_reactivity, people_reactivity,
+			bathroom_pee, bathroom_poop, distance_km, had_incident, incident_description, notes,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query,
+		report.BookingID, report.DogID, report.UserID,
+		report.LeashPulling, report.DogReactivity, report.PeopleReactivity,
+		report.BathroomPee, report.BathroomPoop, report.DistanceKm,
+		report.HadIncident, report.IncidentDescription, report.Notes,
+		now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create walk report: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get walk report ID: %w", err)
+	}
+
+	report.ID = int(id)
+	report.CreatedAt = now
+	report.UpdatedAt = now
+	return nil
+}
+
+// FindByBookingAndDog finds the report of one dog on a booking
+func (r *WalkReportRepository) FindByBookingAndDog(bookingID, dogID int) (*models.WalkReport, error) {
+	query := `
+		SELECT id, booking_id, dog_id, user_id, leash_pulling, dog_reactivity, people_reactivity,
+		       bathroom_pee, bathroom_poop, distance_km, had_incident, incident_description, notes,
+		       created_at, updated_at
+		FROM walk_reports
+		WHERE booking_id = ? AND dog_id = ?
+	`
+
+	report := &models.WalkReport{}
+	err := r.db.QueryRow(query, bookingID, dogID).Scan(
+		&report.ID,
+		&report.BookingID,
+		&report.DogID,
+		&report.UserID,
+		&report.LeashPulling,
+		&report.DogReactivity,
+		&report.PeopleReactivity,
+		&report.BathroomPee,
+		&report.BathroomPoop,
+		&report.DistanceKm,
+		&report.HadIncident,
+		&report.IncidentDescription,
+		&report.Notes,
+		&report.CreatedAt,
+		&report.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find walk report: %w", err)
+	}
+
+	return report, nil
+}
+
+// FindByBookingID returns all reports of a booking (one per walked dog)
+func (r *WalkReportRepository) FindByBookingID(bookingID int) ([]*models.WalkReport, error) {
+	return r.findWhere("wr.booking_id = ?", bookingID)
+}
+
+// FindByDogID returns all reports of a dog, newest walk first
+func (r *WalkReportRepository) FindByDogID(dogID int) ([]*models.WalkReport, error) {
+	return r.findWhere("wr.dog_id = ?", dogID)
+}
+
+// findWhere runs the common report query with the reporting user and booking date joined
+func (r *WalkReportRepository) findWhere(condition string, args ...interface{}) ([]*models.WalkReport, error) {
+	query := `
+		SELECT wr.id, wr.booking_id, wr.dog_id, wr.user_id, wr.leash_pulling, wr.dog_reactivity, wr.people_reactivity,
+		       wr.bathroom_pee, wr.bathroom_poop, wr.distance_km, wr.had_incident, wr.incident_description, wr.notes,
+		       wr.created_at, wr.updated_at,
+		       b.date, u.name
+		FROM walk_reports wr
+		JOIN bookings b ON wr.booking_id = b.id
+		LEFT JOIN users u ON wr.user_id = u.id
+		WHERE ` + condition + `
+		ORDER BY b.date DESC, b.scheduled_time DESC, wr.id DESC
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query walk reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []*models.WalkReport{}
+	for rows.Next() {
+		report := &models.WalkReport{}
+		var userName sql.NullString
+		err := rows.Scan(
+			&report.ID,
+			&report.BookingID,
+			&report.DogID,
+			&report.UserID,
+			&report.LeashPulling,
+			&report.DogReactivity,
+			&report.PeopleReactivity,
+			&report.BathroomPee,
+			&report.BathroomPoop,
+			&report.DistanceKm,
+			&report.HadIncident,
+			&report.IncidentDescription,
+			&report.Notes,
+			&report.CreatedAt,
+			&report.UpdatedAt,
+			&report.BookingDate,
+			&userName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan walk report: %w", err)
+		}
+
+		report.UserName = "Deleted User"
+		if userName.Valid {
+			report.UserName = userName.String
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// GetDogSummary aggregates all reports of a dog, overall and per month of the walk
+// Aggregation is done in Go to avoid database-specific date functions
+func (r *WalkReportRepository) GetDogSummary(dogID int) (*models.DogReportSummary, error) {
+	reports, err := r.FindByDogID(dogID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.DogReportSummary{
+		DogID:   dogID,
+		Monthly: []*models.MonthlyReportStats{},
+	}
+	if len(reports) == 0 {
+		return summary, nil
+	}
+
+	var leash, dogReact, peopleReact, pee, poop int
+	months := map[string]*models.MonthlyReportStats{}
+	monthSums := map[string][3]int{}
+
+	for _, report := range reports {
+		leash += report.LeashPulling
+		dogReact += report.DogReactivity
+		peopleReact += report.PeopleReactivity
+		if report.BathroomPee {
+			pee++
+		}
+		if report.BathroomPoop {
+			poop++
+		}
+		if report.DistanceKm != nil {
+			summary.TotalDistanceKm += *report.DistanceKm
+		}
+		if report.HadIncident {
+			summary.IncidentCount++
+		}
+		if summary.LastReportAt == nil || report.CreatedAt.After(*summary.LastReportAt) {
+			createdAt := report.CreatedAt
+			summary.LastReportAt = &createdAt
+		}
+
+		// Booking dates are YYYY-MM-DD (or RFC3339 depending on driver), so the month is the first 7 chars
+		month := report.BookingDate
+		if len(month) >= 7 {
+			month = month[:7]
+		}
+		stats, ok := months[month]
+		if !ok {
+			stats = &models.MonthlyReportStats{Month: month}
+			months[month] = stats
+		}
+		stats.ReportCount++
+		if report.HadIncident {
+			stats.IncidentCount++
+		}
+		sums := monthSums[month]
+		sums[0] += report.LeashPulling
+		sums[1] += report.DogReactivity
+		sums[2] += report.PeopleReactivity
+		monthSums[month] = sums
+	}
+
+	count := float64(len(reports))
+	summary.ReportCount = len(reports)
+	summary.AvgLeashPulling = roundRating(float64(leash) / count)
+	summary.AvgDogReactivity = roundRating(float64(dogReact) / count)
+	summary.AvgPeopleReactivity = roundRating(float64(peopleReact) / count)
+	summary.BathroomPeePercentage = roundRating(float64(pee) / count * 100)
+	summary.BathroomPoopPercentage = roundRating(float64(poop) / count * 100)
+	summary.TotalDistanceKm = roundRating(summary.TotalDistanceKm)
+
+	for month, stats := range months {
+		sums := monthSums[month]
+		n := float64(stats.ReportCount)
+		stats.AvgLeashPulling = roundRating(float64(sums[0]) / n)
+		stats.AvgDogReactivity = roundRating(float64(sums[1]) / n)
+		stats.AvgPeopleReactivity = roundRating(float64(sums[2]) / n)
+		summary.Monthly = append(summary.Monthly, stats)
+	}
+	sort.Slice(summary.Monthly, func(i, j int) bool {
+		return summary.Monthly[i].Month < summary.Monthly[j].Month
+	})
+
+	return summary, nil
+}
+
+// roundRating rounds an average to two decimals for display
+func roundRating(value float64) float64 {
+	return float64(int(value*100+0.5)) / 100
+}
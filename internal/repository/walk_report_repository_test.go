@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestWalkReportRepository_Save tests creating and replacing a walk report
+func TestWalkReportRepository_Save(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewWalkReportRepository(db)
+
+	userID := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	bookingID := testutil.SeedTestBooking(t, db, userID, dogID, "2025-11-03", "09:00", "completed")
+
+	report := &models.WalkReport{
+		BookingID:        bookingID,
+		DogID:            dogID,
+		UserID:           userID,
+		LeashPulling:     4,
+		DogReactivity:    2,
+		PeopleReactivity: 1,
+	}
+
+	t.Run("create report", func(t *testing.T) {
+		if err := repo.Save(report); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+		if report.ID == 0 {
+			t.Error("Report ID should be set after creation")
+		}
+	})
+
+	t.Run("saving again replaces the report", func(t *testing.T) {
+		distance := 2.5
+		updated := &models.WalkReport{
+			BookingID:        bookingID,
+			DogID:            dogID,
+			UserID:           userID,
+			LeashPulling:     2,
+			DogReactivity:    2,
+			PeopleReactivity: 1,
+			DistanceKm:       &distance,
+		}
+		if err := repo.Save(updated); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+		if updated.ID != report.ID {
+			t.Errorf("Expected report %d to be updated, got new ID %d", report.ID, updated.ID)
+		}
+
+		reports, err := repo.FindByBookingID(bookingID)
+		if err != nil {
+			t.Fatalf("FindByBookingID() failed: %v", err)
+		}
+		if len(reports) != 1 {
+			t.Fatalf("Expected 1 report, got %d", len(reports))
+		}
+		if reports[0].LeashPulling != 2 || reports[0].DistanceKm == nil || *reports[0].DistanceKm != 2.5 {
+			t.Errorf("Report was not updated: %+v", reports[0])
+		}
+		if reports[0].UserName != "Walker" {
+			t.Errorf("Expected user name 'Walker', got %s", reports[0].UserName)
+		}
+	})
+}
+
+// DONE: TestWalkReportRepository_GetDogSummary tests report aggregation per dog
+func TestWalkReportRepository_GetDogSummary(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewWalkReportRepository(db)
+
+	userID := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+
+	t.Run("no reports", func(t *testing.T) {
+		summary, err := repo.GetDogSummary(dogID)
+		if err != nil {
+			t.Fatalf("GetDogSummary() failed: %v", err)
+		}
+		if summary.ReportCount != 0 || len(summary.Monthly) != 0 {
+			t.Errorf("Expected empty summary, got %+v", summary)
+		}
+	})
+
+	distance := 3.0
+	walks := []struct {
+		date     string
+		leash    int
+		pee      bool
+		incident bool
+	}{
+		{"2025-10-06", 5, true, true},
+		{"2025-10-20", 3, true, false},
+		{"2025-11-03", 1, false, false},
+	}
+	for _, walk := range walks {
+		bookingID := testutil.SeedTestBooking(t, db, userID, dogID, walk.date, "09:00", "completed")
+		report := &models.WalkReport{
+			BookingID:        bookingID,
+			DogID:            dogID,
+			UserID:           userID,
+			LeashPulling:     walk.leash,
+			DogReactivity:    2,
+			PeopleReactivity: 1,
+			BathroomPee:      walk.pee,
+			DistanceKm:       &distance,
+			HadIncident:      walk.incident,
+		}
+		if err := repo.Save(report); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	t.Run("aggregates overall and per month", func(t *testing.T) {
+		summary, err := repo.GetDogSummary(dogID)
+		if err != nil {
+			t.Fatalf("GetDogSummary() failed: %v", err)
+		}
+
+		if summary.ReportCount != 3 {
+			t.Errorf("Expected 3 reports, got %d", summary.ReportCount)
+		}
+		if summary.AvgLeashPulling != 3 {
+			t.Errorf("Expected average leash pulling 3, got %v", summary.AvgLeashPulling)
+		}
+		if summary.TotalDistanceKm != 9 {
+			t.Errorf("Expected total distance 9, got %v", summary.TotalDistanceKm)
+		}
+		if summary.IncidentCount != 1 {
+			t.Errorf("Expected 1 incident, got %d", summary.IncidentCount)
+		}
+		if summary.BathroomPeePercentage != 66.67 {
+			t.Errorf("Expected pee percentage 66.67, got %v", summary.BathroomPeePercentage)
+		}
+
+		if len(summary.Monthly) != 2 {
+			t.Fatalf("Expected 2 months, got %d", len(summary.Monthly))
+		}
+		if summary.Monthly[0].Month != "2025-10" || summary.Monthly[0].AvgLeashPulling != 4 {
+			t.Errorf("Unexpected October stats: %+v", summary.Monthly[0])
+		}
+		if summary.Monthly[1].Month != "2025-11" || summary.Monthly[1].AvgLeashPulling != 1 {
+			t.Errorf("Unexpected November stats: %+v", summary.Monthly[1])
+		}
+	})
+}
+
+// DONE: TestBookingRepository_GetForReportReminders tests finding completed walks without a report
+func TestBookingRepository_GetForReportReminders(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	bookingRepo := NewBookingRepository(db)
+	reportRepo := NewWalkReportRepository(db)
+
+	userID := testutil.SeedTestUser(t, db, "walker@example.com", "Walker", "green")
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+
+	completedAt := time.Now().Add(-30 * time.Hour)
+	withoutReport := testutil.SeedTestBooking(t, db, userID, dogID, "2025-11-03", "09:00", "completed")
+	withReport := testutil.SeedTestBooking(t, db, userID, dogID, "2025-11-04", "09:00", "completed")
+	recent := testutil.SeedTestBooking(t, db, userID, dogID, "2025-11-05", "09:00", "completed")
+	db.Exec("UPDATE bookings SET completed_at = ? WHERE id IN (?, ?)", completedAt, withoutReport, withReport)
+	db.Exec("UPDATE bookings SET completed_at = ? WHERE id = ?", time.Now().Add(-1*time.Hour), recent)
+
+	reportRepo.Save(&models.WalkReport{
+		BookingID: withReport, DogID: dogID, UserID: userID,
+		LeashPulling: 1, DogReactivity: 1, PeopleReactivity: 1,
+	})
+
+	completedBefore := time.Now().Add(-24 * time.Hour)
+	bookings, err := bookingRepo.GetForReportReminders(completedBefore, completedBefore.AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("GetForReportReminders() failed: %v", err)
+	}
+	if len(bookings) != 1 || bookings[0].ID != withoutReport {
+		t.Fatalf("Expected only booking %d, got %d bookings", withoutReport, len(bookings))
+	}
+	if bookings[0].User.Email == nil || *bookings[0].User.Email != "walker@example.com" {
+		t.Error("Expected user email to be joined")
+	}
+
+	if err := bookingRepo.MarkReportReminderSent(withoutReport); err != nil {
+		t.Fatalf("MarkReportReminderSent() failed: %v", err)
+	}
+	bookings, _ = bookingRepo.GetForReportReminders(completedBefore, completedBefore.AddDate(0, 0, -7))
+	if len(bookings) != 0 {
+		t.Errorf("Expected no reminders after marking sent, got %d", len(bookings))
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+// DefaultAuditLogRetentionDays is used if the setting is missing or invalid
+const DefaultAuditLogRetentionDays = 730
+
+// auditIgnoredFields change on every write or are derived from other fields,
+// so they are left out of the before/after diff
+var auditIgnoredFields = map[string]bool{
+	"updated_at":                  true,
+	"photo_url":                   true,
+	"thumbnail_url":               true,
+	"profile_photo_url":           true,
+	"profile_photo_thumbnail_url": true,
+}
+
+// AuditService records admin actions in the audit log
+type AuditService struct {
+	auditRepo    *repository.AuditLogRepository
+	settingsRepo *repository.SettingsRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditRepo *repository.AuditLogRepository, settingsRepo *repository.SettingsRepository) *AuditService {
+	return &AuditService{
+		auditRepo:    auditRepo,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// Record writes an audit log entry for an action on a target
+// before and after are snapshots of the target (structs or maps, nil if it did not exist);
+// only the fields that differ are stored. Failures are logged and never fail the action itself
+func (s *AuditService) Record(actor *models.AuditActor, action, targetType string, targetID *int, before, after interface{}) {
+	changes, err := AuditDiff(before, after)
+	if err != nil {
+		log.Printf("Error building audit diff for %s: %v", action, err)
+	}
+
+	entry := &models.AuditLogEntry{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Changes:    changes,
+	}
+	if actor != nil {
+		entry.ActorID = actor.UserID
+		entry.ActorEmail = optionalString(actor.Email)
+		entry.IPAddress = optionalString(actor.IPAddress)
+		entry.RequestID = optionalString(actor.RequestID)
+	}
+
+	if err := s.auditRepo.Create(entry); err != nil {
+		log.Printf("Error writing audit log entry %s %s/%s: %v", action, targetType, formatTargetID(targetID), err)
+	}
+}
+
+// RetentionDays returns how many days audit log entries are kept
+func (s *AuditService) RetentionDays() int {
+	days := DefaultAuditLogRetentionDays
+	if setting, err := s.settingsRepo.Get("audit_log_retention_days"); err == nil && setting != nil {
+		if d, err := strconv.Atoi(setting.Value); err == nil && d > 0 {
+			days = d
+		}
+	}
+	return days
+}
+
+// Cleanup deletes entries older than the retention period and returns how many were deleted
+func (s *AuditService) Cleanup() (int64, error) {
+	return s.auditRepo.DeleteOlderThan(time.Now().AddDate(0, 0, -s.RetentionDays()))
+}
+
+// AuditDiff compares the JSON representation of two snapshots and returns the changed fields
+// Values that are not JSON objects are compared as a whole under the key "value"
+func AuditDiff(before, after interface{}) (map[string]models.AuditChange, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]models.AuditChange{}
+	for key, value := range beforeFields {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		if other, ok := afterFields[key]; !ok || !reflect.DeepEqual(value, other) {
+			changes[key] = models.AuditChange{Before: value, After: afterFields[key]}
+		}
+	}
+	for key, value := range afterFields {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		if _, ok := beforeFields[key]; !ok {
+			changes[key] = models.AuditChange{Before: nil, After: value}
+		}
+	}
+
+	return changes, nil
+}
+
+// auditFields converts a snapshot to a map of JSON fields
+func auditFields(snapshot interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if snapshot == nil || (reflect.ValueOf(snapshot).Kind() == reflect.Ptr && reflect.ValueOf(snapshot).IsNil()) {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to decode audit snapshot: %w", err)
+	}
+	if object, ok := value.(map[string]interface{}); ok {
+		return object, nil
+	}
+	if value != nil {
+		fields["value"] = value
+	}
+	return fields, nil
+}
+
+// optionalString returns nil for empty strings
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// formatTargetID formats an optional target ID for log messages
+func formatTargetID(id *int) string {
+	if id == nil {
+		return "-"
+	}
+	return strconv.Itoa(*id)
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestAuditDiff tests that only changed fields end up in the diff
+func TestAuditDiff(t *testing.T) {
+	before := &models.Dog{ID: 1, Name: "Bella", Breed: "Labrador", IsAvailable: true, UpdatedAt: time.Now()}
+	after := *before
+	after.Name = "Bella II"
+	after.IsAvailable = false
+	after.UpdatedAt = time.Now().Add(time.Minute)
+
+	changes, err := AuditDiff(before, &after)
+	if err != nil {
+		t.Fatalf("AuditDiff() failed: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Errorf("Expected name and is_available to change, got %v", changes)
+	}
+	if changes["name"].Before != "Bella" || changes["name"].After != "Bella II" {
+		t.Errorf("Unexpected name change: %+v", changes["name"])
+	}
+	if _, ok := changes["updated_at"]; ok {
+		t.Error("updated_at must not be part of the diff")
+	}
+
+	t.Run("created and deleted", func(t *testing.T) {
+		created, _ := AuditDiff(nil, map[string]interface{}{"date": "2026-12-24"})
+		if created["date"].Before != nil || created["date"].After != "2026-12-24" {
+			t.Errorf("Unexpected diff for created target: %v", created)
+		}
+
+		var missing *models.Dog
+		deleted, _ := AuditDiff(before, missing)
+		if deleted["breed"].Before != "Labrador" || deleted["breed"].After != nil {
+			t.Errorf("Unexpected diff for deleted target: %v", deleted["breed"])
+		}
+	})
+
+	t.Run("secrets are never included", func(t *testing.T) {
+		hash := "secret-hash"
+		changes, _ := AuditDiff(&models.User{Name: "Anna"}, &models.User{Name: "Anna", PasswordHash: &hash})
+		if len(changes) != 0 {
+			t.Errorf("Expected no changes, got %v", changes)
+		}
+	})
+}
+
+// DONE: TestAuditService tests recording entries and the retention cleanup
+func TestAuditService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	auditRepo := repository.NewAuditLogRepository(db)
+	service := NewAuditService(auditRepo, repository.NewSettingsRepository(db))
+
+	adminID := 7
+	actor := &models.AuditActor{UserID: &adminID, Email: "admin@example.com", IPAddress: "192.0.2.1", RequestID: "req-1"}
+	userID := 3
+	service.Record(actor, models.AuditUserDeactivate, models.AuditTargetUser, &userID,
+		map[string]interface{}{"is_active": true}, map[string]interface{}{"is_active": false})
+
+	page, err := auditRepo.Find(&models.AuditLogFilter{})
+	if err != nil {
+		t.Fatalf("Find() failed: %v", err)
+	}
+	if page.Total != 1 {
+		t.Fatalf("Expected 1 entry, got %d", page.Total)
+	}
+	entry := page.Entries[0]
+	if *entry.ActorID != adminID || *entry.ActorEmail != "admin@example.com" || *entry.IPAddress != "192.0.2.1" || *entry.RequestID != "req-1" {
+		t.Errorf("Unexpected actor fields: %+v", entry)
+	}
+	if entry.Changes["is_active"].Before != true || entry.Changes["is_active"].After != false {
+		t.Errorf("Unexpected changes: %v", entry.Changes)
+	}
+
+	t.Run("retention", func(t *testing.T) {
+		if days := service.RetentionDays(); days != DefaultAuditLogRetentionDays {
+			t.Errorf("Expected default retention of %d days, got %d", DefaultAuditLogRetentionDays, days)
+		}
+
+		db.Exec(`UPDATE system_settings SET value = '30' WHERE key = 'audit_log_retention_days'`)
+		old := &models.AuditLogEntry{Action: models.AuditSettingUpdate, TargetType: models.AuditTargetSetting, CreatedAt: time.Now().AddDate(0, 0, -31)}
+		if err := auditRepo.Create(old); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+
+		deleted, err := service.Cleanup()
+		if err != nil {
+			t.Fatalf("Cleanup() failed: %v", err)
+		}
+		if deleted != 1 {
+			t.Errorf("Expected 1 deleted entry, got %d", deleted)
+		}
+		if page, _ := auditRepo.Find(&models.AuditLogFilter{}); page.Total != 1 {
+			t.Errorf("Expected the recent entry to be kept, got %d entries", page.Total)
+		}
+	})
+}
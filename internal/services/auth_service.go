@@ -14,6 +14,7 @@ import (
 type AuthService struct {
 	jwtSecret          string
 	jwtExpirationHours int
+	keys               *JWTKeyRing
 }
 
 // NewAuthService creates a new auth service
@@ -24,6 +25,14 @@ func NewAuthService(jwtSecret string, jwtExpirationHours int) *AuthService {
 	}
 }
 
+// NewAuthServiceWithKeys creates an auth service that signs and verifies JWTs with rotating keys
+func NewAuthServiceWithKeys(keys *JWTKeyRing, jwtExpirationHours int) *AuthService {
+	return &AuthService{
+		jwtExpirationHours: jwtExpirationHours,
+		keys:               keys,
+	}
+}
+
 // HashPassword hashes a password using bcrypt
 func (s *AuthService) HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 12)
@@ -59,6 +68,32 @@ func (s *AuthService) GenerateJWT(userID int, email string, isAdmin bool, isSupe
 		"exp":            time.Now().Add(time.Hour * time.Duration(s.jwtExpirationHours)).Unix(),
 	}
 
+	return s.signClaims(claims)
+}
+
+// DONE: Phase 3 - JWT now includes is_admin and is_super_admin claims
+
+// GenerateAccessToken generates a short-lived JWT for a login session
+// The sid claim lets the session middleware reject tokens of revoked sessions before they expire
+func (s *AuthService) GenerateAccessToken(userID int, email string, isAdmin, isSuperAdmin bool, sessionID int, lifetime time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id":        userID,
+		"email":          email,
+		"is_admin":       isAdmin,
+		"is_super_admin": isSuperAdmin,
+		"sid":            sessionID,
+		"exp":            time.Now().Add(lifetime).Unix(),
+	}
+
+	return s.signClaims(claims)
+}
+
+// signClaims signs claims with the active key, or with the JWT secret if there is no key ring
+func (s *AuthService) signClaims(claims jwt.MapClaims) (string, error) {
+	if s.keys != nil {
+		return s.keys.Sign(claims)
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(s.jwtSecret))
 	if err != nil {
@@ -68,10 +103,46 @@ func (s *AuthService) GenerateJWT(userID int, email string, isAdmin bool, isSupe
 	return tokenString, nil
 }
 
-// DONE: Phase 3 - JWT now includes is_admin and is_super_admin claims
+// PreAuthTokenPurpose marks tokens that only allow the second login step
+const PreAuthTokenPurpose = "2fa"
+
+// preAuthTokenLifetime is how long the user has to enter the second factor
+const preAuthTokenLifetime = 5 * time.Minute
+
+// GeneratePreAuthToken generates a short-lived token for the second login step
+// It has no email or admin claims and is rejected by the auth middleware
+func (s *AuthService) GeneratePreAuthToken(userID int) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"purpose": PreAuthTokenPurpose,
+		"exp":     time.Now().Add(preAuthTokenLifetime).Unix(),
+	}
+
+	return s.signClaims(claims)
+}
+
+// ValidatePreAuthToken validates a pre-auth token and returns the user ID
+func (s *AuthService) ValidatePreAuthToken(tokenString string) (int, error) {
+	claims, err := s.ValidateJWT(tokenString)
+	if err != nil {
+		return 0, err
+	}
+	if purpose, _ := (*claims)["purpose"].(string); purpose != PreAuthTokenPurpose {
+		return 0, fmt.Errorf("not a pre-auth token")
+	}
+	userID, ok := (*claims)["user_id"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("invalid token claims")
+	}
+	return int(userID), nil
+}
 
 // ValidateJWT validates and parses a JWT token
 func (s *AuthService) ValidateJWT(tokenString string) (*jwt.MapClaims, error) {
+	if s.keys != nil {
+		return s.keys.Parse(tokenString)
+	}
+
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -262,8 +262,71 @@ func (s *EmailService) SendPasswordResetEmail(to, name, token string) error {
 	return s.SendEmail(to, subject, body.String())
 }
 
+// SendMagicLinkEmail sends a single-use login link; it only works in the browser that requested it
+func (s *EmailService) SendMagicLinkEmail(to, name, token string, expiryMinutes int) error {
+	subject := "Ihr Login-Link - Gassigeher"
+
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Titillium, Arial, sans-serif; line-height: 1.6; color: #26272b; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #82b965; color: white; padding: 20px; text-align: center; border-radius: 6px 6px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 0 0 6px 6px; }
+        .button { display: inline-block; padding: 12px 30px; background-color: #82b965; color: white; text-decoration: none; border-radius: 6px; margin: 20px 0; }
+        .warning { background-color: #fff3cd; border-left: 4px solid #ffc107; padding: 15px; margin: 20px 0; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔗 Anmelden ohne Passwort</h1>
+        </div>
+        <div class="content">
+            <p>Hallo {{.Name}},</p>
+            <p>Sie haben einen Login-Link angefordert. Klicken Sie auf den Button unten, um sich anzumelden.</p>
+            <p style="text-align: center;">
+                <a href="{{.BaseURL}}/login.html?magic_token={{.Token}}" class="button">Jetzt anmelden</a>
+            </p>
+            <p>Oder kopieren Sie diesen Link in Ihren Browser:</p>
+            <p style="word-break: break-all; font-size: 12px; color: #666;">
+                {{.BaseURL}}/login.html?magic_token={{.Token}}
+            </p>
+            <div class="warning">
+                <strong>⚠️ Wichtig:</strong> Dieser Link ist nur {{.Minutes}} Minuten gültig, kann nur einmal verwendet werden
+                und funktioniert nur im Browser, in dem Sie ihn angefordert haben.
+            </div>
+            <p>Wenn Sie diesen Link nicht angefordert haben, können Sie diese E-Mail ignorieren.</p>
+        </div>
+        <div class="footer">
+            <p>© 2025 Gassigeher. Alle Rechte vorbehalten.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t := template.Must(template.New("magic_link").Parse(tmpl))
+	var body bytes.Buffer
+	if err := t.Execute(&body, map[string]interface{}{
+		"Name":    name,
+		"Token":   token,
+		"Minutes": expiryMinutes,
+		"BaseURL": s.baseURL,
+	}); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.SendEmail(to, subject, body.String())
+}
+
 // SendBookingConfirmation sends a booking confirmation email
-func (s *EmailService) SendBookingConfirmation(to, name, dogName, date, scheduledTime string) error {
+// restrictions lists the active walk restrictions of the booked dogs (may be empty)
+func (s *EmailService) SendBookingConfirmation(to, name, dogName, date, scheduledTime string, restrictions []string) error {
 	subject := fmt.Sprintf("Buchungsbestätigung - %s", dogName)
 
 	tmpl := `
@@ -303,6 +366,14 @@ func (s *EmailService) SendBookingConfirmation(to, name, dogName, date, schedule
                     <span class="label">Uhrzeit:</span> {{.ScheduledTime}} Uhr
                 </div>
             </div>
+{{if .Restrictions}}
+            <div class="booking-details" style="border-left-color: #ffc107;">
+                <h3 style="margin-top: 0;">⚠️ Bitte beachten</h3>
+                <ul>
+                {{range .Restrictions}}<li>{{.}}</li>
+                {{end}}</ul>
+            </div>
+{{end}}
 
             <p>Sie erhalten eine Erinnerung 1 Stunde vor Ihrem Spaziergang.</p>
             <p>Falls Sie den Termin stornieren möchten, tun Sie dies bitte mindestens 12 Stunden im Voraus über Ihr Dashboard.</p>
@@ -317,11 +388,12 @@ func (s *EmailService) SendBookingConfirmation(to, name, dogName, date, schedule
 
 	t := template.Must(template.New("booking").Parse(tmpl))
 	var body bytes.Buffer
-	data := map[string]string{
+	data := map[string]interface{}{
 		"Name":          name,
 		"DogName":       dogName,
 		"Date":          date,
 		"ScheduledTime": scheduledTime,
+		"Restrictions":  restrictions,
 	}
 	if err := t.Execute(&body, data); err != nil {
 		return fmt.Errorf("failed to execute template: %w", err)
@@ -538,6 +610,286 @@ func (s *EmailService) SendBookingReminder(to, name, dogName, date, scheduledTim
 	return s.SendEmail(to, subject, body.String())
 }
 
+// SendWalkReportReminder reminds a walker to file the walk report for a completed walk
+func (s *EmailService) SendWalkReportReminder(to, name, dogName, date string) error {
+	subject := fmt.Sprintf("Wie war der Spaziergang mit %s?", dogName)
+
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #26272b; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #82b965; color: white; padding: 20px; text-align: center; border-radius: 6px 6px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 0 0 6px 6px; }
+        .booking-details { background-color: white; padding: 20px; margin: 20px 0; border-radius: 6px; border-left: 4px solid #82b965; }
+        .detail-row { margin: 10px 0; }
+        .label { font-weight: 600; color: #666; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>📝 Spaziergangsbericht</h1>
+        </div>
+        <div class="content">
+            <p>Hallo {{.Name}},</p>
+            <p>vielen Dank für Ihren Spaziergang! Für diesen Spaziergang wurde noch kein Bericht abgegeben:</p>
+
+            <div class="booking-details">
+                <div class="detail-row">
+                    <span class="label">Hund:</span> {{.DogName}}
+                </div>
+                <div class="detail-row">
+                    <span class="label">Datum:</span> {{.Date}}
+                </div>
+            </div>
+
+            <p>Ihr Bericht zu Leinenführigkeit, Verhalten und Geschäften hilft unserem Team, den Hund besser kennenzulernen.</p>
+            <p style="text-align: center;">
+                <a href="{{.BaseURL}}/dashboard.html" style="display: inline-block; padding: 12px 30px; background-color: #82b965; color: white; text-decoration: none; border-radius: 6px;">Bericht abgeben</a>
+            </p>
+        </div>
+        <div class="footer">
+            <p>© 2025 Gassigeher. Alle Rechte vorbehalten.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t := template.Must(template.New("report-reminder").Parse(tmpl))
+	var body bytes.Buffer
+	data := map[string]string{
+		"Name":    name,
+		"DogName": dogName,
+		"Date":    date,
+		"BaseURL": s.baseURL,
+	}
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.SendEmail(to, subject, body.String())
+}
+
+// SendIncidentAlert notifies an admin immediately about a newly reported incident
+func (s *EmailService) SendIncidentAlert(to, adminName, dogName, incidentType, severity, reporterName, description string) error {
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #26272b; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #dc3545; color: white; padding: 20px; text-align: center; border-radius: 6px 6px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 0 0 6px 6px; }
+        .booking-details { background-color: white; padding: 20px; margin: 20px 0; border-radius: 6px; border-left: 4px solid #dc3545; }
+        .detail-row { margin: 10px 0; }
+        .label { font-weight: 600; color: #666; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>⚠️ Vorfall gemeldet</h1>
+        </div>
+        <div class="content">
+            <p>Hallo {{.Name}},</p>
+            <p>soeben wurde ein Vorfall gemeldet, der geprüft werden muss:</p>
+
+            <div class="booking-details">
+                <div class="detail-row">
+                    <span class="label">Hund:</span> {{.DogName}}
+                </div>
+                <div class="detail-row">
+                    <span class="label">Art:</span> {{.IncidentType}}
+                </div>
+                <div class="detail-row">
+                    <span class="label">Schweregrad:</span> {{.Severity}}
+                </div>
+                <div class="detail-row">
+                    <span class="label">Gemeldet von:</span> {{.ReporterName}}
+                </div>
+                <div class="detail-row">
+                    <span class="label">Beschreibung:</span> {{.Description}}
+                </div>
+            </div>
+
+            <p style="text-align: center;">
+                <a href="{{.BaseURL}}/admin-dashboard.html" style="display: inline-block; padding: 12px 30px; background-color: #dc3545; color: white; text-decoration: none; border-radius: 6px;">Vorfall prüfen</a>
+            </p>
+        </div>
+        <div class="footer">
+            <p>© 2025 Gassigeher. Alle Rechte vorbehalten.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	incidentTypes := map[string]string{
+		"bite":   "Biss",
+		"escape": "Entlaufen",
+		"injury": "Verletzung",
+		"other":  "Sonstiges",
+	}
+	severities := map[string]string{
+		"low":      "Gering",
+		"medium":   "Mittel",
+		"high":     "Hoch",
+		"critical": "Kritisch",
+	}
+	if label, ok := incidentTypes[incidentType]; ok {
+		incidentType = label
+	}
+	if label, ok := severities[severity]; ok {
+		severity = label
+	}
+	subject := fmt.Sprintf("Vorfall gemeldet: %s (%s)", dogName, severity)
+
+	t := template.Must(template.New("incident-alert").Parse(tmpl))
+	var body bytes.Buffer
+	data := map[string]string{
+		"Name":         adminName,
+		"DogName":      dogName,
+		"IncidentType": incidentType,
+		"Severity":     severity,
+		"ReporterName": reporterName,
+		"Description":  description,
+		"BaseURL":      s.baseURL,
+	}
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.SendEmail(to, subject, body.String())
+}
+
+// SendVaccinationExpiryAlert sends admins the list of dog vaccinations that expire soon
+func (s *EmailService) SendVaccinationExpiryAlert(to, name string, vaccinations []string) error {
+	subject := fmt.Sprintf("Impfungen laufen ab (%d)", len(vaccinations))
+
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #26272b; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #ffc107; color: #26272b; padding: 20px; text-align: center; border-radius: 6px 6px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 0 0 6px 6px; }
+        .booking-details { background-color: white; padding: 20px; margin: 20px 0; border-radius: 6px; border-left: 4px solid #ffc107; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>💉 Impfungen laufen ab</h1>
+        </div>
+        <div class="content">
+            <p>Hallo {{.Name}},</p>
+            <p>folgende Impfungen laufen demnächst ab oder sind bereits abgelaufen:</p>
+
+            <div class="booking-details">
+                <ul>
+                {{range .Vaccinations}}<li>{{.}}</li>
+                {{end}}</ul>
+            </div>
+
+            <p style="text-align: center;">
+                <a href="{{.BaseURL}}/admin-dogs.html" style="display: inline-block; padding: 12px 30px; background-color: #82b965; color: white; text-decoration: none; border-radius: 6px;">Hunde verwalten</a>
+            </p>
+        </div>
+        <div class="footer">
+            <p>© 2025 Gassigeher. Alle Rechte vorbehalten.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t := template.Must(template.New("vaccination-expiry").Parse(tmpl))
+	var body bytes.Buffer
+	data := map[string]interface{}{
+		"Name":         name,
+		"Vaccinations": vaccinations,
+		"BaseURL":      s.baseURL,
+	}
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.SendEmail(to, subject, body.String())
+}
+
+// SendNeglectedDogsReport sends the daily report of dogs that have not been walked for a while to an admin
+func (s *EmailService) SendNeglectedDogsReport(to, name string, days int, dogs []string) error {
+	subject := fmt.Sprintf("Hunde ohne Spaziergang (%d)", len(dogs))
+
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #26272b; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #ffc107; color: #26272b; padding: 20px; text-align: center; border-radius: 6px 6px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 0 0 6px 6px; }
+        .booking-details { background-color: white; padding: 20px; margin: 20px 0; border-radius: 6px; border-left: 4px solid #ffc107; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🐕 Hunde ohne Spaziergang</h1>
+        </div>
+        <div class="content">
+            <p>Hallo {{.Name}},</p>
+            <p>folgende Hunde wurden seit mindestens {{.Days}} Tagen nicht ausgeführt:</p>
+
+            <div class="booking-details">
+                <ul>
+                {{range .Dogs}}<li>{{.}}</li>
+                {{end}}</ul>
+            </div>
+
+            <p style="text-align: center;">
+                <a href="{{.BaseURL}}/admin-dogs.html" style="display: inline-block; padding: 12px 30px; background-color: #82b965; color: white; text-decoration: none; border-radius: 6px;">Hunde verwalten</a>
+            </p>
+        </div>
+        <div class="footer">
+            <p>© 2025 Gassigeher. Alle Rechte vorbehalten.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t := template.Must(template.New("neglected-dogs").Parse(tmpl))
+	var body bytes.Buffer
+	data := map[string]interface{}{
+		"Name":    name,
+		"Days":    days,
+		"Dogs":    dogs,
+		"BaseURL": s.baseURL,
+	}
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.SendEmail(to, subject, body.String())
+}
+
 // SendBookingMoved sends an email when admin moves a booking
 func (s *EmailService) SendBookingMoved(to, name, dogName, oldDate, oldTime, newDate, newTime, reason string) error {
 	subject := fmt.Sprintf("Deine Buchung wurde verschoben - %s", dogName)
@@ -933,3 +1285,284 @@ func (s *EmailService) SendExperienceLevelDenied(to, name, level string, message
 
 	return s.SendEmail(to, subject, body.String())
 }
+
+// SendFavoriteDogAvailable notifies a user that a favourite dog can be booked again
+func (s *EmailService) SendFavoriteDogAvailable(to, name, dogName string) error {
+	subject := fmt.Sprintf("%s ist wieder verfügbar", dogName)
+
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #26272b; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #82b965; color: white; padding: 20px; text-align: center; border-radius: 6px 6px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 0 0 6px 6px; }
+        .booking-details { background-color: white; padding: 20px; margin: 20px 0; border-radius: 6px; border-left: 4px solid #82b965; }
+        .detail-row { margin: 10px 0; }
+        .label { font-weight: 600; color: #666; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🐕 Wieder verfügbar</h1>
+        </div>
+        <div class="content">
+            <p>Hallo {{.Name}},</p>
+            <p>gute Nachrichten: Ihr Lieblingshund <strong>{{.DogName}}</strong> ist wieder verfügbar und kann ab sofort gebucht werden.</p>
+            <p style="text-align: center;">
+                <a href="{{.BaseURL}}/dogs.html" style="display: inline-block; padding: 12px 30px; background-color: #82b965; color: white; text-decoration: none; border-radius: 6px;">Jetzt buchen</a>
+            </p>
+            <p style="font-size: 12px; color: #666;">Diese Benachrichtigungen können Sie in Ihrem Profil abbestellen.</p>
+        </div>
+        <div class="footer">
+            <p>© 2025 Gassigeher. Alle Rechte vorbehalten.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t := template.Must(template.New("favorite-available").Parse(tmpl))
+	var body bytes.Buffer
+	data := map[string]string{
+		"Name":    name,
+		"DogName": dogName,
+		"BaseURL": s.baseURL,
+	}
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.SendEmail(to, subject, body.String())
+}
+
+// SendFavoriteSlotFree notifies a user that a booked slot of a favourite dog became free
+func (s *EmailService) SendFavoriteSlotFree(to, name, dogName, date, scheduledTime string) error {
+	subject := fmt.Sprintf("Termin mit %s frei geworden", dogName)
+
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #26272b; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #82b965; color: white; padding: 20px; text-align: center; border-radius: 6px 6px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 0 0 6px 6px; }
+        .booking-details { background-color: white; padding: 20px; margin: 20px 0; border-radius: 6px; border-left: 4px solid #82b965; }
+        .detail-row { margin: 10px 0; }
+        .label { font-weight: 600; color: #666; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>📅 Termin frei geworden</h1>
+        </div>
+        <div class="content">
+            <p>Hallo {{.Name}},</p>
+            <p>ein Termin mit Ihrem Lieblingshund ist wieder frei:</p>
+
+            <div class="booking-details">
+                <div class="detail-row">
+                    <span class="label">Hund:</span> {{.DogName}}
+                </div>
+                <div class="detail-row">
+                    <span class="label">Datum:</span> {{.Date}}
+                </div>
+                <div class="detail-row">
+                    <span class="label">Uhrzeit:</span> {{.Time}}
+                </div>
+            </div>
+
+            <p>Wer zuerst bucht, geht mit {{.DogName}} spazieren.</p>
+            <p style="text-align: center;">
+                <a href="{{.BaseURL}}/dogs.html" style="display: inline-block; padding: 12px 30px; background-color: #82b965; color: white; text-decoration: none; border-radius: 6px;">Jetzt buchen</a>
+            </p>
+            <p style="font-size: 12px; color: #666;">Diese Benachrichtigungen können Sie in Ihrem Profil abbestellen.</p>
+        </div>
+        <div class="footer">
+            <p>© 2025 Gassigeher. Alle Rechte vorbehalten.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t := template.Must(template.New("favorite-slot-free").Parse(tmpl))
+	var body bytes.Buffer
+	data := map[string]string{
+		"Name":    name,
+		"DogName": dogName,
+		"Date":    date,
+		"Time":    scheduledTime,
+		"BaseURL": s.baseURL,
+	}
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.SendEmail(to, subject, body.String())
+}
+
+// SendNewDogAdded notifies a user about a new dog they can walk
+func (s *EmailService) SendNewDogAdded(to, name, dogName, breed string) error {
+	subject := fmt.Sprintf("Neu bei uns: %s", dogName)
+
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #26272b; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #82b965; color: white; padding: 20px; text-align: center; border-radius: 6px 6px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 0 0 6px 6px; }
+        .booking-details { background-color: white; padding: 20px; margin: 20px 0; border-radius: 6px; border-left: 4px solid #82b965; }
+        .detail-row { margin: 10px 0; }
+        .label { font-weight: 600; color: #666; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🐾 Neuer Hund</h1>
+        </div>
+        <div class="content">
+            <p>Hallo {{.Name}},</p>
+            <p>bei uns ist ein neuer Hund eingezogen, mit dem Sie spazieren gehen können:</p>
+
+            <div class="booking-details">
+                <div class="detail-row">
+                    <span class="label">Name:</span> {{.DogName}}
+                </div>
+                <div class="detail-row">
+                    <span class="label">Rasse:</span> {{.Breed}}
+                </div>
+            </div>
+            <p style="text-align: center;">
+                <a href="{{.BaseURL}}/dogs.html" style="display: inline-block; padding: 12px 30px; background-color: #82b965; color: white; text-decoration: none; border-radius: 6px;">Hund ansehen</a>
+            </p>
+            <p style="font-size: 12px; color: #666;">Diese Benachrichtigungen können Sie in Ihrem Profil abbestellen.</p>
+        </div>
+        <div class="footer">
+            <p>© 2025 Gassigeher. Alle Rechte vorbehalten.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t := template.Must(template.New("new-dog").Parse(tmpl))
+	var body bytes.Buffer
+	data := map[string]string{
+		"Name":    name,
+		"DogName": dogName,
+		"Breed":   breed,
+		"BaseURL": s.baseURL,
+	}
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.SendEmail(to, subject, body.String())
+}
+
+// SendDogStatusBookingCancelled tells a walker that a walk was cancelled because the dog left the shelter
+// status is the new lifecycle status of the dog (trial_adoption, adopted, deceased or archived)
+func (s *EmailService) SendDogStatusBookingCancelled(to, name, dogName, date, scheduledTime, status string) error {
+	subject := fmt.Sprintf("Spaziergang mit %s abgesagt", dogName)
+	switch status {
+	case "adopted":
+		subject = fmt.Sprintf("%s hat ein Zuhause gefunden", dogName)
+	case "deceased":
+		subject = fmt.Sprintf("Abschied von %s", dogName)
+	}
+
+	tmpl := `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #26272b; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background-color: #82b965; color: white; padding: 20px; text-align: center; border-radius: 6px 6px 0 0; }
+        .content { background-color: #f9f9f9; padding: 30px; border-radius: 0 0 6px 6px; }
+        .booking-details { background-color: white; padding: 20px; margin: 20px 0; border-radius: 6px; border-left: 4px solid #82b965; }
+        .detail-row { margin: 10px 0; }
+        .label { font-weight: 600; color: #666; }
+        .footer { text-align: center; margin-top: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            {{if eq .Status "adopted"}}<h1>🏡 {{.DogName}} hat ein Zuhause</h1>{{else if eq .Status "deceased"}}<h1>In Erinnerung an {{.DogName}}</h1>{{else}}<h1>Spaziergang abgesagt</h1>{{end}}
+        </div>
+        <div class="content">
+            <p>Hallo {{.Name}},</p>
+            {{if eq .Status "adopted"}}
+            <p>wir haben wunderbare Neuigkeiten: {{.DogName}} wurde adoptiert und ist in ein neues Zuhause gezogen!</p>
+            <p>Deshalb findet Ihr geplanter Spaziergang leider nicht statt. Vielen Dank, dass Sie {{.DogName}} auf dem Weg dorthin begleitet haben – Ihre Spaziergänge haben dazu beigetragen.</p>
+            {{else if eq .Status "trial_adoption"}}
+            <p>{{.DogName}} wohnt ab sofort zur Probe in einem möglichen neuen Zuhause. Wir drücken die Daumen, dass es passt!</p>
+            <p>Deshalb findet Ihr geplanter Spaziergang leider nicht statt. Sollte {{.DogName}} zu uns zurückkommen, können Sie wieder Spaziergänge buchen.</p>
+            {{else if eq .Status "deceased"}}
+            <p>wir müssen Ihnen die traurige Nachricht überbringen, dass {{.DogName}} verstorben ist.</p>
+            <p>Ihr geplanter Spaziergang findet deshalb nicht statt. Von Herzen danke für die Zeit und Zuwendung, die Sie {{.DogName}} geschenkt haben.</p>
+            {{else}}
+            <p>{{.DogName}} ist nicht mehr bei uns im Tierheim. Ihr geplanter Spaziergang findet deshalb leider nicht statt.</p>
+            <p>Vielen Dank für Ihre Zeit mit {{.DogName}}.</p>
+            {{end}}
+
+            <div class="booking-details">
+                <h3 style="margin-top: 0;">Abgesagter Spaziergang</h3>
+                <div class="detail-row">
+                    <span class="label">Hund:</span> {{.DogName}}
+                </div>
+                <div class="detail-row">
+                    <span class="label">Datum:</span> {{.Date}}
+                </div>
+                <div class="detail-row">
+                    <span class="label">Uhrzeit:</span> {{.ScheduledTime}} Uhr
+                </div>
+            </div>
+
+            <p style="text-align: center;">
+                <a href="{{.BaseURL}}/dogs.html" style="display: inline-block; padding: 12px 30px; background-color: #82b965; color: white; text-decoration: none; border-radius: 6px;">Andere Hunde ansehen</a>
+            </p>
+        </div>
+        <div class="footer">
+            <p>© 2025 Gassigeher. Alle Rechte vorbehalten.</p>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+	t := template.Must(template.New("dog-status-cancellation").Parse(tmpl))
+	var body bytes.Buffer
+	data := map[string]string{
+		"Name":          name,
+		"DogName":       dogName,
+		"Date":          date,
+		"ScheduledTime": scheduledTime,
+		"Status":        status,
+		"BaseURL":       s.baseURL,
+	}
+	if err := t.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return s.SendEmail(to, subject, body.String())
+}
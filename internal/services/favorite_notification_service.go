@@ -0,0 +1,143 @@
+package services
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+// FavoriteNotificationService e-mails users about their favourite dogs and about new dogs
+// Recipients are filtered by experience level, so nobody is told about a dog they cannot book
+type FavoriteNotificationService struct {
+	favoriteRepo *repository.DogFavoriteRepository
+	emailService *EmailService
+}
+
+// NewFavoriteNotificationService creates a new favourite notification service
+// emailService may be nil, in which case no notifications are sent
+func NewFavoriteNotificationService(favoriteRepo *repository.DogFavoriteRepository, emailService *EmailService) *FavoriteNotificationService {
+	return &FavoriteNotificationService{
+		favoriteRepo: favoriteRepo,
+		emailService: emailService,
+	}
+}
+
+// NotifyDogAvailable notifies subscribers that a favourite dog can be booked again
+// Returns the number of notified users
+func (s *FavoriteNotificationService) NotifyDogAvailable(dog *models.Dog) int {
+	if s.emailService == nil || dog == nil {
+		return 0
+	}
+
+	users, err := s.favoriteRepo.FindSubscribers(dog.ID, models.FavoriteNotifyAvailable)
+	if err != nil {
+		log.Printf("Failed to find favourite subscribers of dog %d: %v", dog.ID, err)
+		return 0
+	}
+
+	sent := 0
+	for _, user := range users {
+		if !repository.CanUserAccessDog(user.ExperienceLevel, dog.Category) {
+			continue
+		}
+		if err := s.emailService.SendFavoriteDogAvailable(*user.Email, user.Name, dog.Name); err != nil {
+			log.Printf("Failed to send favourite dog notification to user %d: %v", user.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+// NotifyFreeSlot notifies subscribers that a booked slot of their favourite dogs became free
+// The walkers of the freed booking are passed in excludeUserIDs and are not notified.
+// Slots in the past are ignored. Returns the number of notified users.
+func (s *FavoriteNotificationService) NotifyFreeSlot(dogs []*models.Dog, date, scheduledTime string, excludeUserIDs []int) int {
+	if s.emailService == nil || !slotInFuture(date, scheduledTime) {
+		return 0
+	}
+
+	excluded := make(map[int]bool, len(excludeUserIDs))
+	for _, id := range excludeUserIDs {
+		excluded[id] = true
+	}
+
+	// One e-mail per user, even if several of their favourites were on the walk
+	recipients := []*models.User{}
+	dogNames := map[int][]string{}
+	for _, dog := range dogs {
+		users, err := s.favoriteRepo.FindSubscribers(dog.ID, models.FavoriteNotifyFreeSlots)
+		if err != nil {
+			log.Printf("Failed to find favourite subscribers of dog %d: %v", dog.ID, err)
+			continue
+		}
+		for _, user := range users {
+			if excluded[user.ID] || !repository.CanUserAccessDog(user.ExperienceLevel, dog.Category) {
+				continue
+			}
+			if _, ok := dogNames[user.ID]; !ok {
+				recipients = append(recipients, user)
+			}
+			dogNames[user.ID] = append(dogNames[user.ID], dog.Name)
+		}
+	}
+
+	sent := 0
+	for _, user := range recipients {
+		names := strings.Join(dogNames[user.ID], " & ")
+		if err := s.emailService.SendFavoriteSlotFree(*user.Email, user.Name, names, slotDate(date), scheduledTime); err != nil {
+			log.Printf("Failed to send free slot notification to user %d: %v", user.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+// NotifyNewDog notifies subscribers whose experience level allows walking the new dog
+// Returns the number of notified users
+func (s *FavoriteNotificationService) NotifyNewDog(dog *models.Dog) int {
+	if s.emailService == nil || dog == nil {
+		return 0
+	}
+
+	users, err := s.favoriteRepo.FindNewDogSubscribers()
+	if err != nil {
+		log.Printf("Failed to find new dog subscribers: %v", err)
+		return 0
+	}
+
+	sent := 0
+	for _, user := range users {
+		if !repository.CanUserAccessDog(user.ExperienceLevel, dog.Category) {
+			continue
+		}
+		if err := s.emailService.SendNewDogAdded(*user.Email, user.Name, dog.Name, dog.Breed); err != nil {
+			log.Printf("Failed to send new dog notification to user %d: %v", user.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+// slotDate returns the YYYY-MM-DD part of a booking date
+// Dates read from the database may carry a time part (e.g., "2025-11-27T00:00:00Z")
+func slotDate(date string) string {
+	if len(date) > 10 {
+		return date[:10]
+	}
+	return date
+}
+
+// slotInFuture checks if a booking slot has not started yet
+func slotInFuture(date, scheduledTime string) bool {
+	start, err := time.ParseInLocation("2006-01-02 15:04", slotDate(date)+" "+scheduledTime, time.Local)
+	if err != nil {
+		return false
+	}
+	return start.After(time.Now())
+}
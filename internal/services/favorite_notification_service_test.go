@@ -0,0 +1,114 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// recordingEmailProvider records sent e-mails instead of sending them
+type recordingEmailProvider struct {
+	mu   sync.Mutex
+	sent []string // "to|subject"
+}
+
+func (p *recordingEmailProvider) SendEmail(to, subject, body string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, to+"|"+subject)
+	return nil
+}
+
+func (p *recordingEmailProvider) ValidateConfig() error { return nil }
+func (p *recordingEmailProvider) Close() error          { return nil }
+func (p *recordingEmailProvider) GetFromEmail() string  { return "noreply@example.com" }
+
+func (p *recordingEmailProvider) reset() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	sent := p.sent
+	p.sent = nil
+	return sent
+}
+
+// DONE: TestFavoriteNotificationService tests who is notified about favourite and new dogs
+func TestFavoriteNotificationService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	favoriteRepo := repository.NewDogFavoriteRepository(db)
+	dogRepo := repository.NewDogRepository(db)
+	provider := &recordingEmailProvider{}
+	service := NewFavoriteNotificationService(favoriteRepo, &EmailService{provider: provider, baseURL: "http://localhost"})
+
+	greenID := testutil.SeedTestUser(t, db, "green@example.com", "Green", "green")
+	orangeID := testutil.SeedTestUser(t, db, "orange@example.com", "Orange", "orange")
+
+	greenDogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	orangeDogID := testutil.SeedTestDog(t, db, "Rex", "Schäferhund", "orange")
+	greenDog, _ := dogRepo.FindByID(greenDogID)
+	orangeDog, _ := dogRepo.FindByID(orangeDogID)
+
+	for _, userID := range []int{greenID, orangeID} {
+		for _, dogID := range []int{greenDogID, orangeDogID} {
+			favoriteRepo.Set(&models.DogFavorite{UserID: userID, DogID: dogID, NotifyAvailable: true, NotifyFreeSlots: true})
+		}
+	}
+
+	t.Run("dog available again", func(t *testing.T) {
+		if sent := service.NotifyDogAvailable(greenDog); sent != 2 {
+			t.Errorf("Expected 2 notifications, got %d", sent)
+		}
+		provider.reset()
+
+		// Green walkers cannot book the orange dog
+		if sent := service.NotifyDogAvailable(orangeDog); sent != 1 {
+			t.Errorf("Expected 1 notification, got %d", sent)
+		}
+		if sent := provider.reset(); len(sent) != 1 || !strings.HasPrefix(sent[0], "orange@example.com|") {
+			t.Errorf("Expected only the orange walker to be notified, got %v", sent)
+		}
+	})
+
+	t.Run("free slot", func(t *testing.T) {
+		tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+		// The orange walker cancelled; one e-mail per user for both dogs
+		sent := service.NotifyFreeSlot([]*models.Dog{greenDog, orangeDog}, tomorrow+"T00:00:00Z", "09:00", []int{orangeID})
+		if sent != 1 {
+			t.Errorf("Expected 1 notification, got %d", sent)
+		}
+		if mails := provider.reset(); len(mails) != 1 || !strings.HasPrefix(mails[0], "green@example.com|") {
+			t.Errorf("Expected only the green walker to be notified, got %v", mails)
+		}
+
+		// Slots in the past are not announced
+		yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+		if sent := service.NotifyFreeSlot([]*models.Dog{greenDog}, yesterday, "09:00", nil); sent != 0 {
+			t.Errorf("Expected no notifications for a past slot, got %d", sent)
+		}
+	})
+
+	t.Run("new dog", func(t *testing.T) {
+		favoriteRepo.SetNewDogSubscription(greenID, true)
+		favoriteRepo.SetNewDogSubscription(orangeID, true)
+		provider.reset()
+
+		if sent := service.NotifyNewDog(orangeDog); sent != 1 {
+			t.Errorf("Expected 1 notification, got %d", sent)
+		}
+		if sent := service.NotifyNewDog(greenDog); sent != 2 {
+			t.Errorf("Expected 2 notifications, got %d", sent)
+		}
+	})
+
+	t.Run("without email service", func(t *testing.T) {
+		service := NewFavoriteNotificationService(favoriteRepo, nil)
+		if sent := service.NotifyDogAvailable(greenDog); sent != 0 {
+			t.Errorf("Expected no notifications, got %d", sent)
+		}
+	})
+}
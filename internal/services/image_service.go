@@ -2,20 +2,27 @@ package services
 
 import (
 	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"io"
 	"mime/multipart"
-	"os"
-	"path/filepath"
+	"path"
+	"regexp"
+	"strings"
 
+	"github.com/chai2010/webp"
 	"github.com/disintegration/imaging"
+	"github.com/tranmh/gassigeher/internal/models"
 )
 
 // ImageService handles image processing operations
+// Processed images are written to the configured storage backend
 type ImageService struct {
-	uploadDir string
+	storage Storage
 }
 
 // Image processing constants
@@ -24,60 +31,210 @@ const (
 	MaxImageHeight  = 800 // Max height for full-size image
 	ThumbnailSize   = 300 // Thumbnail dimensions (square max)
 	JPEGQuality     = 85  // JPEG compression quality (1-100)
+	WebPQuality     = 80  // WebP compression quality (0-100)
 )
 
-// NewImageService creates a new image service
+// contentHashLength is the number of hex characters of the file hash used in dog photo filenames
+const contentHashLength = 20
+
+// photoVariant is one generated size of a photo
+type photoVariant struct {
+	suffix    string
+	maxWidth  int
+	maxHeight int
+}
+
+// dogPhotoVariants are the sizes generated for every dog photo, smallest first
+// "full" and "thumb" are the sizes stored in the database; the others are for srcset
+var dogPhotoVariants = []photoVariant{
+	{suffix: "thumb", maxWidth: ThumbnailSize, maxHeight: ThumbnailSize},
+	{suffix: "w320", maxWidth: 320, maxHeight: 4 * 320},
+	{suffix: "w640", maxWidth: 640, maxHeight: 4 * 640},
+	{suffix: "full", maxWidth: MaxImageWidth, maxHeight: MaxImageHeight},
+	{suffix: "w1280", maxWidth: 1280, maxHeight: 4 * 1280},
+}
+
+// userPhotoVariants are the sizes generated for profile photos (JPEG only)
+var userPhotoVariants = []photoVariant{
+	{suffix: "thumb", maxWidth: ThumbnailSize, maxHeight: ThumbnailSize},
+	{suffix: "full", maxWidth: MaxImageWidth, maxHeight: MaxImageHeight},
+}
+
+var (
+	contentHashedFullRegex    = regexp.MustCompile(`_[0-9a-f]{20}_full\.jpg$`)
+	contentHashedVariantRegex = regexp.MustCompile(`dogs/dog_\d+_[0-9a-f]{20}_(thumb|full|w\d+)\.(jpg|webp)$`)
+)
+
+// NewImageService creates a new image service storing images in a local directory
 func NewImageService(uploadDir string) *ImageService {
+	return NewImageServiceWithStorage(NewLocalStorage(uploadDir))
+}
+
+// NewImageServiceWithStorage creates a new image service storing images in the given storage
+func NewImageServiceWithStorage(storage Storage) *ImageService {
 	return &ImageService{
-		uploadDir: uploadDir,
+		storage: storage,
 	}
 }
 
-// ProcessDogPhoto processes an uploaded dog photo and creates both full-size and thumbnail versions
-// Returns the relative paths (e.g., "dogs/dog_5_full.jpg", "dogs/dog_5_thumb.jpg")
+// ProcessDogPhoto processes an uploaded dog photo and creates all size variants as JPEG and WebP
+// EXIF orientation is applied and all metadata (including GPS) is dropped by re-encoding.
+// Filenames contain a hash of the uploaded file, so a re-upload never reuses a cached URL.
+// Returns the relative paths of the full-size and thumbnail JPEG
+// (e.g., "dogs/dog_5_3f2a9c0d1e4b5a6f7c8d_full.jpg", "dogs/dog_5_3f2a9c0d1e4b5a6f7c8d_thumb.jpg")
 func (s *ImageService) ProcessDogPhoto(file multipart.File, dogID int) (fullPath, thumbPath string, err error) {
+	base, err := s.processPhoto(file, "dogs", fmt.Sprintf("dog_%d", dogID), dogPhotoVariants, true)
+	if err != nil {
+		return "", "", err
+	}
+
+	return base + "_full.jpg", base + "_thumb.jpg", nil
+}
+
+// ProcessUserPhoto processes an uploaded profile photo like a dog photo, as full-size and thumbnail JPEG
+// Returns the relative paths (e.g., "users/user_3_3f2a9c0d1e4b5a6f7c8d_full.jpg", "users/user_3_3f2a9c0d1e4b5a6f7c8d_thumb.jpg")
+func (s *ImageService) ProcessUserPhoto(file multipart.File, userID int) (fullPath, thumbPath string, err error) {
+	base, err := s.processPhoto(file, "users", fmt.Sprintf("user_%d", userID), userPhotoVariants, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	return base + "_full.jpg", base + "_thumb.jpg", nil
+}
+
+// processPhoto saves all variants of an uploaded photo in dir as <prefix>_<hash>_<suffix>.jpg (and .webp)
+// Returns the relative path without suffix (e.g., "dogs/dog_5_3f2a9c0d1e4b5a6f7c8d")
+func (s *ImageService) processPhoto(file multipart.File, dir, prefix string, variants []photoVariant, withWebP bool) (string, error) {
 	// Reset file pointer to beginning
 	if _, err := file.Seek(0, io.SeekStart); err != nil {
-		return "", "", fmt.Errorf("failed to seek file: %w", err)
+		return "", fmt.Errorf("failed to seek file: %w", err)
 	}
 
-	// Decode the uploaded image
-	img, err := imaging.Decode(file)
+	data, err := io.ReadAll(file)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to decode image: %w", err)
+		return "", fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Create dogs directory if it doesn't exist
-	dogsDir := filepath.Join(s.uploadDir, "dogs")
-	if err := os.MkdirAll(dogsDir, 0755); err != nil {
-		return "", "", fmt.Errorf("failed to create dogs directory: %w", err)
+	// Decode the uploaded image, rotating it as the camera recorded it
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	hash := sha256.Sum256(data)
+	baseName := prefix + "_" + hex.EncodeToString(hash[:])[:contentHashLength]
+
+	written := []string{}
+	for _, variant := range variants {
+		resized := s.resizeImage(img, variant.maxWidth, variant.maxHeight)
+		jpegPath := path.Join(dir, baseName+"_"+variant.suffix+".jpg")
+
+		if err := s.saveJPEG(resized, jpegPath, JPEGQuality); err != nil {
+			s.removeFiles(written)
+			return "", fmt.Errorf("failed to save %s image: %w", variant.suffix, err)
+		}
+		written = append(written, jpegPath)
+
+		if withWebP {
+			webpPath := path.Join(dir, baseName+"_"+variant.suffix+".webp")
+			if err := s.saveWebP(resized, webpPath, WebPQuality); err != nil {
+				s.removeFiles(append(written, webpPath))
+				return "", fmt.Errorf("failed to save %s image: %w", variant.suffix, err)
+			}
+			written = append(written, webpPath)
+		}
 	}
 
-	// Process full-size image
-	fullImg := s.resizeImage(img, MaxImageWidth, MaxImageHeight)
-	fullFilename := fmt.Sprintf("dog_%d_full.jpg", dogID)
-	fullFilePath := filepath.Join(dogsDir, fullFilename)
+	return path.Join(dir, baseName), nil
+}
 
-	if err := s.saveJPEG(fullImg, fullFilePath, JPEGQuality); err != nil {
-		return "", "", fmt.Errorf("failed to save full-size image: %w", err)
+// removeFiles cleans up files written before an error, ignoring errors
+func (s *ImageService) removeFiles(keys []string) {
+	for _, key := range keys {
+		s.storage.Delete(key)
 	}
+}
+
+// DogPhotoVariants returns the responsive variants of a dog photo, smallest first
+// fullRelPath is the full-size path stored in the database. Photos uploaded before
+// variants existed have no content hash in their name and return nil.
+func DogPhotoVariants(fullRelPath string) []models.ImageVariant {
+	if !contentHashedFullRegex.MatchString(fullRelPath) {
+		return nil
+	}
+
+	base := strings.TrimSuffix(fullRelPath, "_full.jpg")
+	variants := make([]models.ImageVariant, 0, len(dogPhotoVariants))
+	for _, variant := range dogPhotoVariants {
+		variants = append(variants, models.ImageVariant{
+			Width: variant.maxWidth,
+			JPEG:  base + "_" + variant.suffix + ".jpg",
+			WebP:  base + "_" + variant.suffix + ".webp",
+		})
+	}
+
+	return variants
+}
+
+// IsContentHashedUpload returns true if the upload path is a dog photo variant with a
+// content hash in its name. Such files never change and can be cached forever.
+func IsContentHashedUpload(path string) bool {
+	return contentHashedVariantRegex.MatchString(path)
+}
+
+// DeleteDogPhotoVariants deletes all variants of a dog photo by its full-size path
+// Paths without variants (older uploads) are deleted as-is.
+// Does not return error if files don't exist (idempotent)
+func (s *ImageService) DeleteDogPhotoVariants(fullRelPath string) error {
+	return s.deleteVariants(fullRelPath, dogPhotoVariants, []string{".jpg", ".webp"})
+}
 
-	// Process thumbnail
-	thumbImg := s.resizeImage(img, ThumbnailSize, ThumbnailSize)
-	thumbFilename := fmt.Sprintf("dog_%d_thumb.jpg", dogID)
-	thumbFilePath := filepath.Join(dogsDir, thumbFilename)
+// DeleteUserPhoto deletes the full-size and thumbnail profile photo by its full-size path
+// Older uploads that were stored as-is are deleted as well (idempotent)
+func (s *ImageService) DeleteUserPhoto(fullRelPath string) error {
+	return s.deleteVariants(fullRelPath, userPhotoVariants, []string{".jpg"})
+}
 
-	if err := s.saveJPEG(thumbImg, thumbFilePath, JPEGQuality); err != nil {
-		// Clean up full image if thumbnail fails
-		os.Remove(fullFilePath)
-		return "", "", fmt.Errorf("failed to save thumbnail: %w", err)
+// deleteVariants deletes the given variants of a photo by its full-size path
+func (s *ImageService) deleteVariants(fullRelPath string, variants []photoVariant, exts []string) error {
+	if !strings.HasSuffix(fullRelPath, "_full.jpg") {
+		return s.DeletePhoto(fullRelPath)
 	}
 
-	// Return relative paths (as stored in database)
-	fullRelPath := filepath.Join("dogs", fullFilename)
-	thumbRelPath := filepath.Join("dogs", thumbFilename)
+	for _, file := range variantFiles(fullRelPath, variants, exts) {
+		if err := s.DeletePhoto(file); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// variantFiles returns the paths of the given variants of a photo by its full-size path
+func variantFiles(fullRelPath string, variants []photoVariant, exts []string) []string {
+	base := strings.TrimSuffix(fullRelPath, "_full.jpg")
+	files := make([]string, 0, len(variants)*len(exts))
+	for _, variant := range variants {
+		for _, ext := range exts {
+			files = append(files, base+"_"+variant.suffix+ext)
+		}
+	}
+	return files
+}
 
-	return fullRelPath, thumbRelPath, nil
+// UploadFiles returns the storage keys of all files that belong to an upload path from the database
+// Content hashed dog and profile photos have several variants; other uploads are a single file
+func UploadFiles(relPath string) []string {
+	key := storageKey(relPath)
+	if contentHashedFullRegex.MatchString(key) {
+		switch {
+		case strings.HasPrefix(key, "dogs/"):
+			return variantFiles(key, dogPhotoVariants, []string{".jpg", ".webp"})
+		case strings.HasPrefix(key, "users/"):
+			return variantFiles(key, userPhotoVariants, []string{".jpg"})
+		}
+	}
+	return []string{key}
 }
 
 // resizeImage resizes an image to fit within maxWidth x maxHeight while maintaining aspect ratio
@@ -98,44 +255,80 @@ func (s *ImageService) resizeImage(img image.Image, maxWidth, maxHeight int) ima
 	return imaging.Fit(img, maxWidth, maxHeight, imaging.Lanczos)
 }
 
-// saveJPEG saves an image as JPEG with specified quality
-func (s *ImageService) saveJPEG(img image.Image, path string, quality int) error {
-	// Create output file
-	outFile, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer outFile.Close()
-
+// saveJPEG saves an image as JPEG with specified quality under the storage key
+func (s *ImageService) saveJPEG(img image.Image, key string, quality int) error {
 	// Encode as JPEG with specified quality
+	buf := new(bytes.Buffer)
 	opts := &jpeg.Options{Quality: quality}
-	if err := jpeg.Encode(outFile, img, opts); err != nil {
+	if err := jpeg.Encode(buf, img, opts); err != nil {
 		return fmt.Errorf("failed to encode JPEG: %w", err)
 	}
 
-	return nil
+	return s.storage.Put(key, buf, int64(buf.Len()), "image/jpeg")
+}
+
+// saveWebP saves an image as lossy WebP with specified quality under the storage key
+func (s *ImageService) saveWebP(img image.Image, key string, quality float32) error {
+	buf := new(bytes.Buffer)
+	if err := webp.Encode(buf, img, &webp.Options{Quality: quality}); err != nil {
+		return fmt.Errorf("failed to encode WebP: %w", err)
+	}
+
+	return s.storage.Put(key, buf, int64(buf.Len()), "image/webp")
 }
 
 // DeleteDogPhotos deletes both full-size and thumbnail photos for a dog
 // Does not return error if files don't exist (idempotent)
 func (s *ImageService) DeleteDogPhotos(dogID int) error {
-	dogsDir := filepath.Join(s.uploadDir, "dogs")
-
 	// Delete full-size image
-	fullPath := filepath.Join(dogsDir, fmt.Sprintf("dog_%d_full.jpg", dogID))
-	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+	if err := s.storage.Delete(fmt.Sprintf("dogs/dog_%d_full.jpg", dogID)); err != nil {
 		return fmt.Errorf("failed to delete full-size image: %w", err)
 	}
 
 	// Delete thumbnail
-	thumbPath := filepath.Join(dogsDir, fmt.Sprintf("dog_%d_thumb.jpg", dogID))
-	if err := os.Remove(thumbPath); err != nil && !os.IsNotExist(err) {
+	if err := s.storage.Delete(fmt.Sprintf("dogs/dog_%d_thumb.jpg", dogID)); err != nil {
 		return fmt.Errorf("failed to delete thumbnail: %w", err)
 	}
 
 	return nil
 }
 
+// ProcessIncidentPhoto processes an uploaded incident photo and saves a resized JPEG
+// Incident photos are only served through signed URLs; the random token keeps filenames unguessable
+// Returns the relative path (e.g., "incidents/incident_3_9f86d081884c7d65.jpg")
+func (s *ImageService) ProcessIncidentPhoto(file multipart.File, incidentID int) (string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to seek file: %w", err)
+	}
+
+	img, err := imaging.Decode(file, imaging.AutoOrientation(true))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate filename: %w", err)
+	}
+
+	filename := fmt.Sprintf("incident_%d_%s.jpg", incidentID, hex.EncodeToString(token))
+	resized := s.resizeImage(img, MaxImageWidth, MaxImageHeight)
+	if err := s.saveJPEG(resized, path.Join("incidents", filename), JPEGQuality); err != nil {
+		return "", fmt.Errorf("failed to save incident photo: %w", err)
+	}
+
+	return path.Join("incidents", filename), nil
+}
+
+// DeletePhoto deletes a photo by the relative path stored in the database
+// Does not return error if the file doesn't exist (idempotent)
+func (s *ImageService) DeletePhoto(photoRelPath string) error {
+	if err := s.storage.Delete(photoRelPath); err != nil {
+		return fmt.Errorf("failed to delete photo: %w", err)
+	}
+	return nil
+}
+
 // ResizeAndCompress is a helper function that resizes and compresses an image in memory
 // Returns a buffer containing the JPEG data
 // This is useful for testing or when you need the image data without saving to disk
@@ -152,9 +345,3 @@ func (s *ImageService) ResizeAndCompress(img image.Image, maxWidth, maxHeight, q
 
 	return buf, nil
 }
-
-// GetDogPhotoPath returns the absolute filesystem path for a dog photo
-// photoRelPath should be the relative path stored in database (e.g., "dogs/dog_5_full.jpg")
-func (s *ImageService) GetDogPhotoPath(photoRelPath string) string {
-	return filepath.Join(s.uploadDir, photoRelPath)
-}
@@ -9,6 +9,7 @@ import (
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/disintegration/imaging"
@@ -431,6 +432,186 @@ func TestImageService_DeleteDogPhotos(t *testing.T) {
 	}
 }
 
+// withEXIFOrientation inserts an EXIF segment with the given orientation into JPEG data
+func withEXIFOrientation(jpegData []byte, orientation byte) []byte {
+	app1 := []byte{
+		0xFF, 0xE1, 0x00, 0x22, // APP1 marker and length
+		'E', 'x', 'i', 'f', 0x00, 0x00,
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08, // TIFF header, big endian
+		0x00, 0x01, // one IFD entry
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, orientation, 0x00, 0x00, // Orientation (SHORT)
+		0x00, 0x00, 0x00, 0x00, // no next IFD
+	}
+
+	result := append([]byte{}, jpegData[:2]...) // SOI
+	result = append(result, app1...)
+	return append(result, jpegData[2:]...)
+}
+
+// TestImageService_ProcessDogPhoto_Variants tests responsive variants, WebP and content hashed names
+func TestImageService_ProcessDogPhoto_Variants(t *testing.T) {
+	tempDir := t.TempDir()
+	service := NewImageService(tempDir)
+
+	buf, err := createTestImage(1600, 1200, "jpeg")
+	if err != nil {
+		t.Fatalf("Failed to create test image: %v", err)
+	}
+
+	fullPath, thumbPath, err := service.ProcessDogPhoto(createMultipartFile(buf), 7)
+	if err != nil {
+		t.Fatalf("ProcessDogPhoto failed: %v", err)
+	}
+
+	t.Run("content hashed names", func(t *testing.T) {
+		fullAgain, _, err := service.ProcessDogPhoto(createMultipartFile(buf), 7)
+		if err != nil {
+			t.Fatalf("Second upload failed: %v", err)
+		}
+		if fullAgain != fullPath {
+			t.Errorf("Expected the same file to get the same name, got %s and %s", fullPath, fullAgain)
+		}
+
+		other, err := createTestImage(1000, 1000, "jpeg")
+		if err != nil {
+			t.Fatalf("Failed to create test image: %v", err)
+		}
+		fullOther, _, err := service.ProcessDogPhoto(createMultipartFile(other), 7)
+		if err != nil {
+			t.Fatalf("Third upload failed: %v", err)
+		}
+		if fullOther == fullPath {
+			t.Error("Expected a different file to get a different name")
+		}
+	})
+
+	t.Run("all variants written", func(t *testing.T) {
+		variants := DogPhotoVariants(fullPath)
+		if len(variants) != 5 {
+			t.Fatalf("Expected 5 variants, got %d", len(variants))
+		}
+		if variants[0].JPEG != thumbPath {
+			t.Errorf("Expected thumbnail as smallest variant, got %s", variants[0].JPEG)
+		}
+
+		for _, variant := range variants {
+			img, err := imaging.Open(filepath.Join(tempDir, variant.JPEG))
+			if err != nil {
+				t.Fatalf("Failed to open %s: %v", variant.JPEG, err)
+			}
+			if img.Bounds().Dx() > variant.Width {
+				t.Errorf("%s is %dpx wide, expected at most %d", variant.JPEG, img.Bounds().Dx(), variant.Width)
+			}
+
+			data, err := os.ReadFile(filepath.Join(tempDir, variant.WebP))
+			if err != nil {
+				t.Fatalf("Failed to read %s: %v", variant.WebP, err)
+			}
+			if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+				t.Errorf("%s is not a WebP file", variant.WebP)
+			}
+		}
+	})
+
+	t.Run("delete all variants", func(t *testing.T) {
+		if err := service.DeleteDogPhotoVariants(fullPath); err != nil {
+			t.Fatalf("DeleteDogPhotoVariants failed: %v", err)
+		}
+		for _, variant := range DogPhotoVariants(fullPath) {
+			for _, path := range []string{variant.JPEG, variant.WebP} {
+				if _, err := os.Stat(filepath.Join(tempDir, path)); !os.IsNotExist(err) {
+					t.Errorf("Variant still exists: %s", path)
+				}
+			}
+		}
+	})
+
+	t.Run("legacy photos have no variants", func(t *testing.T) {
+		if variants := DogPhotoVariants("dogs/dog_7_full.jpg"); variants != nil {
+			t.Errorf("Expected no variants, got %d", len(variants))
+		}
+	})
+}
+
+// TestImageService_ProcessDogPhoto_EXIF tests that EXIF orientation is applied and metadata is stripped
+func TestImageService_ProcessDogPhoto_EXIF(t *testing.T) {
+	tempDir := t.TempDir()
+	service := NewImageService(tempDir)
+
+	buf, err := createTestImage(400, 200, "jpeg")
+	if err != nil {
+		t.Fatalf("Failed to create test image: %v", err)
+	}
+
+	// Orientation 6: the camera was rotated, the image must be turned 90° clockwise
+	rotated := bytes.NewBuffer(withEXIFOrientation(buf.Bytes(), 6))
+
+	fullPath, _, err := service.ProcessDogPhoto(createMultipartFile(rotated), 8)
+	if err != nil {
+		t.Fatalf("ProcessDogPhoto failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, fullPath))
+	if err != nil {
+		t.Fatalf("Failed to read result: %v", err)
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if img.Bounds().Dx() != 200 || img.Bounds().Dy() != 400 {
+		t.Errorf("Expected rotated 200x400 image, got %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
+	}
+
+	if bytes.Contains(data, []byte("Exif")) {
+		t.Error("Expected EXIF metadata to be stripped")
+	}
+}
+
+// TestImageService_ProcessUserPhoto tests profile photo processing and deletion
+func TestImageService_ProcessUserPhoto(t *testing.T) {
+	tempDir := t.TempDir()
+	service := NewImageService(tempDir)
+
+	buf, err := createTestImage(1600, 1200, "jpeg")
+	if err != nil {
+		t.Fatalf("Failed to create test image: %v", err)
+	}
+
+	fullPath, thumbPath, err := service.ProcessUserPhoto(createMultipartFile(bytes.NewBuffer(withEXIFOrientation(buf.Bytes(), 6))), 3)
+	if err != nil {
+		t.Fatalf("ProcessUserPhoto failed: %v", err)
+	}
+	if !strings.HasPrefix(fullPath, filepath.Join("users", "user_3_")) || !strings.HasSuffix(thumbPath, "_thumb.jpg") {
+		t.Errorf("Unexpected paths %q, %q", fullPath, thumbPath)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, fullPath))
+	if err != nil {
+		t.Fatalf("Failed to read result: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if img.Bounds().Dx() > 800 || img.Bounds().Dy() > 800 || img.Bounds().Dx() >= img.Bounds().Dy() {
+		t.Errorf("Expected rotated image within 800x800, got %dx%d", img.Bounds().Dx(), img.Bounds().Dy())
+	}
+	if bytes.Contains(data, []byte("Exif")) {
+		t.Error("Expected EXIF metadata to be stripped")
+	}
+
+	if err := service.DeleteUserPhoto(fullPath); err != nil {
+		t.Fatalf("DeleteUserPhoto failed: %v", err)
+	}
+	for _, path := range []string{fullPath, thumbPath} {
+		if _, err := os.Stat(filepath.Join(tempDir, path)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be deleted", path)
+		}
+	}
+}
+
 // TestImageService_ProcessDogPhoto_InvalidInput tests error cases
 func TestImageService_ProcessDogPhoto_InvalidInput(t *testing.T) {
 	tempDir := t.TempDir()
@@ -0,0 +1,560 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+const (
+	// DefaultJWTKeyGracePeriod is how long retired keys still verify if no grace period is configured
+	DefaultJWTKeyGracePeriod = 24 * time.Hour
+
+	// jwtKeyCacheTTL is how long keys are cached before they are read again,
+	// so rotations by other instances or the CLI are picked up
+	jwtKeyCacheTTL = time.Minute
+
+	// jwtKeyReloadInterval limits reloads for tokens with an unknown kid
+	jwtKeyReloadInterval = 5 * time.Second
+
+	// rsaKeyBits is the size of generated RSA keys and the minimum size of RSA key files
+	rsaKeyBits = 2048
+)
+
+// ErrJWTKeysFromFile is returned when rotating keys that are managed through JWT_PRIVATE_KEY_FILE
+var ErrJWTKeysFromFile = errors.New("JWT keys are managed through JWT_PRIVATE_KEY_FILE; replace the file and restart to rotate")
+
+// jwtKey is a parsed signing key
+type jwtKey struct {
+	info      *models.JWTSigningKey
+	signKey   interface{} // []byte, ed25519.PrivateKey or *rsa.PrivateKey; nil if this instance cannot sign with it
+	verifyKey interface{} // []byte, ed25519.PublicKey or *rsa.PublicKey
+}
+
+// JWTKeyRing signs JWTs with the active key and verifies them with all keys that are
+// active or still in their grace period. Tokens carry the key ID in the kid header.
+// Tokens without kid were signed with JWT_SECRET before keys were rotated; they verify
+// until the grace period after the first key was created has passed
+type JWTKeyRing struct {
+	repo         *repository.JWTKeyRepository
+	algorithm    string
+	legacySecret []byte
+	grace        time.Duration
+	fileKey      *jwtKey
+
+	mu          sync.Mutex
+	ensured     bool
+	keys        map[string]*jwtKey
+	active      *jwtKey
+	legacyUntil time.Time
+	loadedAt    time.Time
+}
+
+// NewJWTKeyRing creates a key ring; new keys use the algorithm (HS256 if empty) and a grace period of zero uses the default
+func NewJWTKeyRing(repo *repository.JWTKeyRepository, algorithm, legacySecret string, grace time.Duration) *JWTKeyRing {
+	if algorithm == "" {
+		algorithm = models.JWTAlgorithmHS256
+	}
+	if grace <= 0 {
+		grace = DefaultJWTKeyGracePeriod
+	}
+	return &JWTKeyRing{
+		repo:         repo,
+		algorithm:    algorithm,
+		legacySecret: []byte(legacySecret),
+		grace:        grace,
+	}
+}
+
+// NewJWTKeyRingFromConfig creates the key ring of the configuration, with JWT_PRIVATE_KEY_FILE if set.
+// On an error with the key file the key ring is still returned, using generated keys
+func NewJWTKeyRingFromConfig(repo *repository.JWTKeyRepository, cfg *config.Config) (*JWTKeyRing, error) {
+	keys := NewJWTKeyRing(repo, cfg.JWTAlgorithm, cfg.JWTSecret, time.Duration(cfg.JWTKeyGraceHours)*time.Hour)
+	if cfg.JWTPrivateKeyFile != "" {
+		if err := keys.UseKeyFile(cfg.JWTPrivateKeyFile); err != nil {
+			return keys, err
+		}
+	}
+	return keys, nil
+}
+
+// UseKeyFile reads an Ed25519 or RSA private key (PEM) to sign with instead of generated keys.
+// If the key is not known yet, it becomes the active key on the next load
+func (k *JWTKeyRing) UseKeyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read JWT key file: %w", err)
+	}
+	key, err := parsePrivateKeyPEM(data)
+	if err != nil {
+		return fmt.Errorf("invalid JWT key file %s: %w", path, err)
+	}
+	key.info.FromFile = true
+	key.info.PrivateKey = nil
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.fileKey = key
+	k.ensured = false
+	k.loadedAt = time.Time{}
+	return nil
+}
+
+// EnsureActiveKey creates the first signing key or imports the key file, if needed
+func (k *JWTKeyRing) EnsureActiveKey() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.ensureLocked()
+}
+
+// Rotate creates a new signing key with the configured algorithm and retires the active one.
+// Keys whose grace period has ended are deleted
+func (k *JWTKeyRing) Rotate() (*models.JWTSigningKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.fileKey != nil {
+		return nil, ErrJWTKeysFromFile
+	}
+
+	key, err := generateJWTKey(k.algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := k.repo.Rotate(key.info); err != nil {
+		return nil, err
+	}
+	if deleted, err := k.repo.DeleteRetiredBefore(time.Now().Add(-k.grace)); err != nil {
+		log.Printf("Error deleting expired JWT keys: %v", err)
+	} else if deleted > 0 {
+		log.Printf("Deleted %d expired JWT keys", deleted)
+	}
+
+	if err := k.loadLocked(); err != nil {
+		return nil, err
+	}
+	return k.describe(k.keys[key.info.Kid]), nil
+}
+
+// Keys returns the keys that verify tokens, the active key first
+func (k *JWTKeyRing) Keys() ([]*models.JWTSigningKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := k.refreshLocked(false); err != nil {
+		return nil, err
+	}
+
+	keys := []*models.JWTSigningKey{}
+	for _, key := range k.keys {
+		keys = append(keys, k.describe(key))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Active != keys[j].Active {
+			return keys[i].Active
+		}
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// JWKS returns the public keys of all asymmetric keys that verify tokens
+func (k *JWTKeyRing) JWKS() (*models.JWKS, error) {
+	keys, err := k.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	set := &models.JWKS{Keys: []models.JWK{}}
+	for _, info := range keys {
+		key := k.keys[info.Kid]
+		if key == nil {
+			continue
+		}
+		switch public := key.verifyKey.(type) {
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, models.JWK{Kty: "OKP", Kid: info.Kid, Alg: info.Algorithm, Use: "sig",
+				Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(public)})
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, models.JWK{Kty: "RSA", Kid: info.Kid, Alg: info.Algorithm, Use: "sig",
+				N: base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())})
+		}
+	}
+	return set, nil
+}
+
+// Sign signs claims with the active key and sets the kid header
+func (k *JWTKeyRing) Sign(claims jwt.MapClaims) (string, error) {
+	k.mu.Lock()
+	if err := k.refreshLocked(false); err != nil {
+		k.mu.Unlock()
+		return "", err
+	}
+	active := k.active
+	k.mu.Unlock()
+
+	if active == nil || active.signKey == nil {
+		return "", fmt.Errorf("no JWT signing key available")
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(active.info.Algorithm), claims)
+	token.Header["kid"] = active.info.Kid
+	tokenString, err := token.SignedString(active.signKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return tokenString, nil
+}
+
+// Parse verifies a token with the key of its kid header and returns its claims
+func (k *JWTKeyRing) Parse(tokenString string) (*jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, k.verificationKey,
+		jwt.WithValidMethods([]string{models.JWTAlgorithmHS256, models.JWTAlgorithmEdDSA, models.JWTAlgorithmRS256}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		return &claims, nil
+	}
+	return nil, fmt.Errorf("invalid token")
+}
+
+// verificationKey returns the key for a token; the algorithm of the token must match the key
+func (k *JWTKeyRing) verificationKey(token *jwt.Token) (interface{}, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := k.refreshLocked(false); err != nil {
+		return nil, err
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if len(k.legacySecret) == 0 || time.Now().After(k.legacyUntil) {
+			return nil, fmt.Errorf("token without key ID")
+		}
+		if token.Method.Alg() != models.JWTAlgorithmHS256 {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return k.legacySecret, nil
+	}
+
+	key := k.keys[kid]
+	if key == nil && time.Since(k.loadedAt) > jwtKeyReloadInterval {
+		// The key may have been created by another instance since the last load
+		if err := k.refreshLocked(true); err != nil {
+			return nil, err
+		}
+		key = k.keys[kid]
+	}
+	if key == nil {
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+	if key.info.RetiredAt != nil && time.Now().After(key.info.RetiredAt.Add(k.grace)) {
+		return nil, fmt.Errorf("key %q has expired", kid)
+	}
+	if token.Method.Alg() != key.info.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.verifyKey, nil
+}
+
+// refreshLocked loads the keys if they were never loaded, the cache is stale or force is set
+func (k *JWTKeyRing) refreshLocked(force bool) error {
+	if !k.ensured {
+		return k.ensureLocked()
+	}
+	if force || time.Since(k.loadedAt) > jwtKeyCacheTTL {
+		return k.loadLocked()
+	}
+	return nil
+}
+
+// ensureLocked imports the key file or creates the first key, then loads the keys
+func (k *JWTKeyRing) ensureLocked() error {
+	if k.fileKey != nil {
+		exists, err := k.repo.Exists(k.fileKey.info.Kid)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			info := *k.fileKey.info
+			info.CreatedAt = time.Now()
+			if err := k.repo.Rotate(&info); err != nil {
+				return err
+			}
+			log.Printf("Using JWT signing key %s from key file", info.Kid)
+		}
+	} else {
+		key, err := generateJWTKey(k.algorithm)
+		if err != nil {
+			return err
+		}
+		created, err := k.repo.CreateIfNoneActive(key.info)
+		if err != nil {
+			return err
+		}
+		if created {
+			log.Printf("Created JWT signing key %s (%s)", key.info.Kid, key.info.Algorithm)
+		}
+	}
+
+	if encrypted, err := k.repo.EncryptStoredKeys(); err != nil {
+		return err
+	} else if encrypted > 0 {
+		log.Printf("Encrypted %d stored JWT signing keys", encrypted)
+	}
+
+	if err := k.loadLocked(); err != nil {
+		return err
+	}
+
+	// The active key cannot sign if its private key cannot be decrypted, e.g. after JWT_KEY_ENCRYPTION_KEY changed
+	if k.fileKey == nil && (k.active == nil || k.active.info.Unreadable) {
+		key, err := generateJWTKey(k.algorithm)
+		if err != nil {
+			return err
+		}
+		if err := k.repo.Rotate(key.info); err != nil {
+			return err
+		}
+		log.Printf("Warning: active JWT signing key cannot be decrypted, rotated to new key %s (%s)", key.info.Kid, key.info.Algorithm)
+		if err := k.loadLocked(); err != nil {
+			return err
+		}
+	}
+	k.ensured = true
+
+	if k.fileKey != nil && (k.active == nil || k.active.info.Kid != k.fileKey.info.Kid) {
+		log.Printf("Warning: JWT key file %s is not the active signing key", k.fileKey.info.Kid)
+	} else if k.fileKey == nil && k.active != nil && k.active.info.Algorithm != k.algorithm {
+		log.Printf("JWT_ALGORITHM is %s but the active key uses %s; rotate the key to switch", k.algorithm, k.active.info.Algorithm)
+	}
+	return nil
+}
+
+// loadLocked reads the verifying keys from the database
+func (k *JWTKeyRing) loadLocked() error {
+	infos, err := k.repo.ListVerifying(time.Now().Add(-k.grace))
+	if err != nil {
+		return err
+	}
+	first, err := k.repo.FirstCreatedAt()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*jwtKey, len(infos))
+	var active *jwtKey
+	for _, info := range infos {
+		key, err := k.parseStoredKey(info)
+		if err != nil {
+			log.Printf("Skipping JWT key %s: %v", info.Kid, err)
+			continue
+		}
+		keys[info.Kid] = key
+		if active == nil && info.RetiredAt == nil {
+			active = key
+		}
+	}
+
+	k.keys = keys
+	k.active = active
+	k.legacyUntil = time.Time{}
+	if first != nil {
+		k.legacyUntil = first.Add(k.grace)
+	}
+	k.loadedAt = time.Now()
+	return nil
+}
+
+// parseStoredKey parses a key from the database; keys from the key file get the private key of the file
+func (k *JWTKeyRing) parseStoredKey(info *models.JWTSigningKey) (*jwtKey, error) {
+	if k.fileKey != nil && info.Kid == k.fileKey.info.Kid {
+		return &jwtKey{info: withFileFlag(info), signKey: k.fileKey.signKey, verifyKey: k.fileKey.verifyKey}, nil
+	}
+
+	if info.Algorithm == models.JWTAlgorithmHS256 {
+		if info.Unreadable {
+			return nil, fmt.Errorf("secret cannot be decrypted")
+		}
+		if info.PrivateKey == nil {
+			return nil, fmt.Errorf("missing secret")
+		}
+		secret, err := base64.StdEncoding.DecodeString(*info.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret: %w", err)
+		}
+		return &jwtKey{info: info, signKey: secret, verifyKey: secret}, nil
+	}
+
+	if info.PrivateKey != nil {
+		key, err := parsePrivateKeyPEM([]byte(*info.PrivateKey))
+		if err != nil {
+			return nil, err
+		}
+		key.info = info
+		return key, nil
+	}
+
+	// A key from the file of another instance or one that cannot be decrypted; this instance can only verify with it
+	if info.PublicKey == nil {
+		return nil, fmt.Errorf("missing public key")
+	}
+	block, _ := pem.Decode([]byte(*info.PublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("invalid public key")
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if info.Unreadable {
+		return &jwtKey{info: info, verifyKey: public}, nil
+	}
+	return &jwtKey{info: withFileFlag(info), verifyKey: public}, nil
+}
+
+// describe returns the key info with its state for listings
+func (k *JWTKeyRing) describe(key *jwtKey) *models.JWTSigningKey {
+	info := *key.info
+	info.Active = key == k.active
+	if info.RetiredAt != nil {
+		until := info.RetiredAt.Add(k.grace)
+		info.VerifiesUntil = &until
+	}
+	return &info
+}
+
+// withFileFlag marks a stored key without private key as loaded from a key file
+func withFileFlag(info *models.JWTSigningKey) *models.JWTSigningKey {
+	marked := *info
+	marked.FromFile = true
+	return &marked
+}
+
+// generateJWTKey creates a random key for the algorithm
+func generateJWTKey(algorithm string) (*jwtKey, error) {
+	now := time.Now()
+	switch algorithm {
+	case models.JWTAlgorithmHS256:
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate JWT secret: %w", err)
+		}
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			return nil, fmt.Errorf("failed to generate key ID: %w", err)
+		}
+		encoded := base64.StdEncoding.EncodeToString(secret)
+		info := &models.JWTSigningKey{Kid: hex.EncodeToString(id), Algorithm: algorithm, PrivateKey: &encoded, CreatedAt: now}
+		return &jwtKey{info: info, signKey: secret, verifyKey: secret}, nil
+
+	case models.JWTAlgorithmEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Ed25519 key: %w", err)
+		}
+		return newAsymmetricJWTKey(private, now)
+
+	case models.JWTAlgorithmRS256:
+		private, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate RSA key: %w", err)
+		}
+		return newAsymmetricJWTKey(private, now)
+	}
+
+	return nil, fmt.Errorf("unsupported JWT algorithm %q (use HS256, EdDSA or RS256)", algorithm)
+}
+
+// parsePrivateKeyPEM parses an Ed25519 or RSA private key in PKCS#8 or PKCS#1 PEM format
+func parsePrivateKeyPEM(data []byte) (*jwtKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	var private crypto.Signer
+	if strings.Contains(block.Type, "RSA") {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA key: %w", err)
+		}
+		private = key
+	} else {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T", key)
+		}
+		private = signer
+	}
+
+	return newAsymmetricJWTKey(private, time.Time{})
+}
+
+// newAsymmetricJWTKey wraps an Ed25519 or RSA private key; the kid is derived from the public key,
+// so the same key always gets the same ID
+func newAsymmetricJWTKey(private crypto.Signer, createdAt time.Time) (*jwtKey, error) {
+	var algorithm string
+	switch key := private.(type) {
+	case ed25519.PrivateKey:
+		algorithm = models.JWTAlgorithmEdDSA
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < rsaKeyBits {
+			return nil, fmt.Errorf("RSA keys must have at least %d bits", rsaKeyBits)
+		}
+		algorithm = models.JWTAlgorithmRS256
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (use Ed25519 or RSA)", private)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+
+	thumbprint := sha256.Sum256(publicDER)
+	privatePEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	publicPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	info := &models.JWTSigningKey{
+		Kid:        base64.RawURLEncoding.EncodeToString(thumbprint[:12]),
+		Algorithm:  algorithm,
+		PrivateKey: &privatePEM,
+		PublicKey:  &publicPEM,
+		CreatedAt:  createdAt,
+	}
+	return &jwtKey{info: info, signKey: private, verifyKey: private.Public()}, nil
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{"user_id": 1, "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+// DONE: TestJWTKeyRing_Rotation tests that rotated keys verify during the grace period only
+func TestJWTKeyRing_Rotation(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := repository.NewJWTKeyRepository(db, []string{"test-secret"})
+	keys := NewJWTKeyRing(repo, models.JWTAlgorithmHS256, "test-secret", time.Hour)
+
+	oldToken, err := keys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Sign() failed: %v", err)
+	}
+	oldKid := jwtHeader(t, oldToken)["kid"]
+
+	rotated, err := keys.Rotate()
+	if err != nil {
+		t.Fatalf("Rotate() failed: %v", err)
+	}
+	if rotated.Kid == oldKid || !rotated.Active {
+		t.Fatalf("Expected a new active key, got %+v", rotated)
+	}
+
+	newToken, _ := keys.Sign(testClaims())
+	if kid := jwtHeader(t, newToken)["kid"]; kid != rotated.Kid {
+		t.Errorf("Expected new tokens to use kid %s, got %v", rotated.Kid, kid)
+	}
+
+	// Another instance verifies both tokens while the old key is in its grace period
+	other := NewJWTKeyRing(repo, models.JWTAlgorithmHS256, "test-secret", time.Hour)
+	for _, token := range []string{oldToken, newToken} {
+		if _, err := other.Parse(token); err != nil {
+			t.Errorf("Expected token to verify: %v", err)
+		}
+	}
+
+	listed, _ := other.Keys()
+	if len(listed) != 2 || !listed[0].Active || listed[1].VerifiesUntil == nil {
+		t.Errorf("Expected the active key first and the retired key with its grace period, got %+v", listed)
+	}
+
+	db.Exec(`UPDATE jwt_signing_keys SET retired_at = ? WHERE kid = ?`, time.Now().Add(-2*time.Hour), oldKid)
+	expired := NewJWTKeyRing(repo, models.JWTAlgorithmHS256, "test-secret", time.Hour)
+	if _, err := expired.Parse(oldToken); err == nil {
+		t.Error("Expected tokens of keys past their grace period to be rejected")
+	}
+	if _, err := expired.Parse(newToken); err != nil {
+		t.Errorf("Expected tokens of the active key to verify: %v", err)
+	}
+}
+
+// DONE: TestJWTKeyRing_LegacyTokens tests that tokens signed with JWT_SECRET verify only during the grace period after the upgrade
+func TestJWTKeyRing_LegacyTokens(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := repository.NewJWTKeyRepository(db, []string{"test-secret"})
+	keys := NewJWTKeyRing(repo, models.JWTAlgorithmHS256, "test-secret", time.Hour)
+
+	legacy, _ := NewAuthService("test-secret", 24).GenerateJWT(1, "anna@example.com", false, false)
+	if _, err := keys.Parse(legacy); err != nil {
+		t.Fatalf("Expected legacy token to verify after the upgrade: %v", err)
+	}
+
+	forged, _ := NewAuthService("other-secret", 24).GenerateJWT(1, "anna@example.com", true, true)
+	if _, err := keys.Parse(forged); err == nil {
+		t.Error("Expected token with another secret to be rejected")
+	}
+
+	db.Exec(`UPDATE jwt_signing_keys SET created_at = ?`, time.Now().Add(-2*time.Hour))
+	later := NewJWTKeyRing(repo, models.JWTAlgorithmHS256, "test-secret", time.Hour)
+	if _, err := later.Parse(legacy); err == nil {
+		t.Error("Expected legacy tokens to be rejected after the grace period")
+	}
+}
+
+// DONE: TestJWTKeyRing_Asymmetric tests EdDSA and RS256 keys, the JWKS and that the algorithm must match the key
+func TestJWTKeyRing_Asymmetric(t *testing.T) {
+	for _, algorithm := range []string{models.JWTAlgorithmEdDSA, models.JWTAlgorithmRS256} {
+		t.Run(algorithm, func(t *testing.T) {
+			db := testutil.SetupTestDB(t)
+			keys := NewJWTKeyRing(repository.NewJWTKeyRepository(db, []string{"test-secret"}), algorithm, "", 0)
+
+			token, err := keys.Sign(testClaims())
+			if err != nil {
+				t.Fatalf("Sign() failed: %v", err)
+			}
+			header := jwtHeader(t, token)
+			if header["alg"] != algorithm {
+				t.Errorf("Expected alg %s, got %v", algorithm, header["alg"])
+			}
+			if _, err := keys.Parse(token); err != nil {
+				t.Errorf("Expected token to verify: %v", err)
+			}
+
+			set, err := keys.JWKS()
+			if err != nil {
+				t.Fatalf("JWKS() failed: %v", err)
+			}
+			if len(set.Keys) != 1 || set.Keys[0].Kid != header["kid"] || set.Keys[0].Alg != algorithm {
+				t.Fatalf("Unexpected JWKS: %+v", set)
+			}
+
+			// A token signed with HMAC under the kid of the asymmetric key must not verify
+			confused := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+			confused.Header["kid"] = header["kid"]
+			confusedString, _ := confused.SignedString([]byte("guess"))
+			if _, err := keys.Parse(confusedString); err == nil {
+				t.Error("Expected token with another algorithm to be rejected")
+			}
+		})
+	}
+
+	t.Run("JWKS verifies Ed25519 tokens", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		keys := NewJWTKeyRing(repository.NewJWTKeyRepository(db, []string{"test-secret"}), models.JWTAlgorithmEdDSA, "", 0)
+		token, _ := keys.Sign(testClaims())
+		set, _ := keys.JWKS()
+
+		public, _ := base64.RawURLEncoding.DecodeString(set.Keys[0].X)
+		_, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return ed25519.PublicKey(public), nil })
+		if err != nil {
+			t.Errorf("Expected token to verify with the published key: %v", err)
+		}
+	})
+
+	t.Run("HS256 keys are not published", func(t *testing.T) {
+		db := testutil.SetupTestDB(t)
+		set, _ := NewJWTKeyRing(repository.NewJWTKeyRepository(db, []string{"test-secret"}), "", "", 0).JWKS()
+		if len(set.Keys) != 0 {
+			t.Errorf("Expected no public keys, got %+v", set.Keys)
+		}
+	})
+}
+
+// DONE: TestJWTKeyRing_UnreadableKeys tests that keys which do not decrypt with JWT_KEY_ENCRYPTION_KEY
+// are skipped or verify only, and that an unreadable active key is replaced
+func TestJWTKeyRing_UnreadableKeys(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := repository.NewJWTKeyRepository(db, []string{"test-secret"})
+	keys := NewJWTKeyRing(repo, models.JWTAlgorithmEdDSA, "", time.Hour)
+
+	oldToken, err := keys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Sign() failed: %v", err)
+	}
+	oldKid := jwtHeader(t, oldToken)["kid"]
+
+	// After the encryption key changed, the old key still verifies with its public key
+	other := NewJWTKeyRing(repository.NewJWTKeyRepository(db, []string{"other-secret"}), models.JWTAlgorithmEdDSA, "", time.Hour)
+	newToken, err := other.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Expected a new signing key, got %v", err)
+	}
+	if kid := jwtHeader(t, newToken)["kid"]; kid == oldKid {
+		t.Error("Expected the unreadable key to be rotated")
+	}
+	if _, err := other.Parse(oldToken); err != nil {
+		t.Errorf("Expected the unreadable key to verify: %v", err)
+	}
+
+	// HS256 keys cannot verify without their secret and are skipped
+	hs256 := NewJWTKeyRing(repository.NewJWTKeyRepository(db, []string{"test-secret"}), models.JWTAlgorithmHS256, "", time.Hour)
+	if _, err := hs256.Rotate(); err != nil {
+		t.Fatalf("Rotate() failed: %v", err)
+	}
+	third := NewJWTKeyRing(repository.NewJWTKeyRepository(db, []string{"third-secret"}), models.JWTAlgorithmHS256, "", time.Hour)
+	if _, err := third.Sign(testClaims()); err != nil {
+		t.Errorf("Expected a new signing key, got %v", err)
+	}
+}
+
+// DONE: TestJWTKeyRing_KeyFile tests signing with a key file whose private key stays out of the database
+func TestJWTKeyRing_KeyFile(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := repository.NewJWTKeyRepository(db, []string{"test-secret"})
+
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	path := filepath.Join(t.TempDir(), "jwt-key.pem")
+	os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+
+	keys := NewJWTKeyRing(repo, models.JWTAlgorithmHS256, "", 0)
+	if err := keys.UseKeyFile(path); err != nil {
+		t.Fatalf("UseKeyFile() failed: %v", err)
+	}
+	token, err := keys.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("Sign() failed: %v", err)
+	}
+	if jwtHeader(t, token)["alg"] != models.JWTAlgorithmEdDSA {
+		t.Error("Expected the algorithm of the key file")
+	}
+
+	var stored *string
+	db.QueryRow(`SELECT private_key FROM jwt_signing_keys`).Scan(&stored)
+	if stored != nil {
+		t.Error("Expected the private key of the file not to be stored")
+	}
+
+	// An instance without the file can verify, but not sign
+	other := NewJWTKeyRing(repo, models.JWTAlgorithmHS256, "", 0)
+	if _, err := other.Parse(token); err != nil {
+		t.Errorf("Expected token to verify with the stored public key: %v", err)
+	}
+	if _, err := other.Sign(testClaims()); err == nil {
+		t.Error("Expected signing to fail without the key file")
+	}
+
+	if _, err := keys.Rotate(); !errors.Is(err, ErrJWTKeysFromFile) {
+		t.Errorf("Expected rotation to be refused with a key file, got %v", err)
+	}
+
+	os.WriteFile(path, []byte("not a key"), 0600)
+	if err := NewJWTKeyRing(repo, "", "", 0).UseKeyFile(path); err == nil {
+		t.Error("Expected an invalid key file to be rejected")
+	}
+}
+
+// jwtHeader decodes the header of a token without verifying it
+func jwtHeader(t *testing.T, tokenString string) map[string]interface{} {
+	t.Helper()
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("Failed to decode token: %v", err)
+	}
+	return token.Header
+}
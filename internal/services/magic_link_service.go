@@ -0,0 +1,180 @@
+package services
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+const (
+	// DefaultMagicLinkExpiry is used if magic_link_expiry_minutes is missing or invalid
+	DefaultMagicLinkExpiry = 15 * time.Minute
+
+	// maxMagicLinksPerUser limits how many links are sent to one account within magicLinkLimitWindow,
+	// independent of the IP address the requests come from
+	maxMagicLinksPerUser = 3
+	magicLinkLimitWindow = 15 * time.Minute
+)
+
+var (
+	// ErrMagicLinkDisabled is returned if login links are disabled in the settings
+	ErrMagicLinkDisabled = errors.New("magic link login is disabled")
+
+	// ErrMagicLinkInvalid is returned for unknown, used and expired links
+	ErrMagicLinkInvalid = errors.New("invalid or expired magic link")
+
+	// ErrMagicLinkOtherDevice is returned if a link is opened in another browser than the one that requested it
+	ErrMagicLinkOtherDevice = errors.New("magic link was requested on another device")
+)
+
+// MagicLink is a login link created by Request
+type MagicLink struct {
+	User        *models.User // nil if no link was created
+	Token       string       // sent by email; empty if no link was created
+	DeviceToken string       // kept by the requesting browser
+	ExpiresAt   time.Time
+}
+
+// MagicLinkService handles passwordless login with single-use links sent by email
+type MagicLinkService struct {
+	magicLinkRepo *repository.MagicLinkRepository
+	userRepo      *repository.UserRepository
+	settingsRepo  *repository.SettingsRepository
+	authService   *AuthService
+	emailService  *EmailService
+}
+
+// NewMagicLinkService creates a new magic link service; emailService may be nil
+func NewMagicLinkService(magicLinkRepo *repository.MagicLinkRepository, userRepo *repository.UserRepository, settingsRepo *repository.SettingsRepository, authService *AuthService, emailService *EmailService) *MagicLinkService {
+	return &MagicLinkService{
+		magicLinkRepo: magicLinkRepo,
+		userRepo:      userRepo,
+		settingsRepo:  settingsRepo,
+		authService:   authService,
+		emailService:  emailService,
+	}
+}
+
+// IsEnabled returns true if the setting magic_link_login_enabled is enabled
+func (s *MagicLinkService) IsEnabled() bool {
+	setting, err := s.settingsRepo.Get("magic_link_login_enabled")
+	return err == nil && setting != nil && setting.Value == "true"
+}
+
+// Expiry returns how long a link is valid, from the setting magic_link_expiry_minutes
+func (s *MagicLinkService) Expiry() time.Duration {
+	setting, err := s.settingsRepo.Get("magic_link_expiry_minutes")
+	if err != nil || setting == nil {
+		return DefaultMagicLinkExpiry
+	}
+	minutes, err := strconv.Atoi(setting.Value)
+	if err != nil || minutes <= 0 {
+		return DefaultMagicLinkExpiry
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// Request creates a login link for the account of an email address and sends it
+// A device token is always returned, so the response does not reveal whether the account exists;
+// links are only created for verified, active accounts and at most maxMagicLinksPerUser per window
+func (s *MagicLinkService) Request(email, ipAddress string) (*MagicLink, error) {
+	if !s.IsEnabled() {
+		return nil, ErrMagicLinkDisabled
+	}
+
+	deviceToken, err := s.authService.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	link := &MagicLink{DeviceToken: deviceToken, ExpiresAt: time.Now().Add(s.Expiry())}
+
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Email == nil || !user.IsVerified || !user.IsActive {
+		return link, nil
+	}
+
+	recent, err := s.magicLinkRepo.CountSince(user.ID, time.Now().Add(-magicLinkLimitWindow))
+	if err != nil {
+		return nil, err
+	}
+	if recent >= maxMagicLinksPerUser {
+		log.Printf("Magic link limit reached for user %d", user.ID)
+		return link, nil
+	}
+
+	token, err := s.authService.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	record := &models.MagicLinkToken{
+		UserID:     user.ID,
+		TokenHash:  hashToken(token),
+		DeviceHash: hashToken(deviceToken),
+		ExpiresAt:  link.ExpiresAt,
+	}
+	if ipAddress != "" {
+		record.IPAddress = &ipAddress
+	}
+	if err := s.magicLinkRepo.Create(record); err != nil {
+		return nil, err
+	}
+
+	link.User = user
+	link.Token = token
+
+	// Sent in the background, so the response time does not reveal whether the account exists
+	if s.emailService != nil {
+		minutes := int(s.Expiry().Minutes())
+		go func() {
+			if err := s.emailService.SendMagicLinkEmail(*user.Email, user.Name, token, minutes); err != nil {
+				log.Printf("Failed to send magic link email: %v", err)
+			}
+		}()
+	}
+
+	return link, nil
+}
+
+// Verify uses up a login link and returns its user, who must still be allowed to log in
+// The link only works in the browser that requested it; opening it elsewhere does not use it up
+func (s *MagicLinkService) Verify(token, deviceToken string) (*models.User, error) {
+	if !s.IsEnabled() {
+		return nil, ErrMagicLinkDisabled
+	}
+
+	link, err := s.magicLinkRepo.FindByTokenHash(hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || link.UsedAt != nil || time.Now().After(link.ExpiresAt) {
+		return nil, ErrMagicLinkInvalid
+	}
+	if subtle.ConstantTimeCompare([]byte(link.DeviceHash), []byte(hashToken(deviceToken))) != 1 {
+		return nil, ErrMagicLinkOtherDevice
+	}
+
+	used, err := s.magicLinkRepo.MarkUsed(link.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, ErrMagicLinkInvalid
+	}
+
+	user, err := s.userRepo.FindByID(link.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Email == nil || !user.IsVerified || !user.IsActive {
+		return nil, ErrMagicLinkInvalid
+	}
+	return user, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestMagicLinkService tests the setting, device binding, single use and the per-account limit
+func TestMagicLinkService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	settingsRepo := repository.NewSettingsRepository(db)
+	service := NewMagicLinkService(repository.NewMagicLinkRepository(db), userRepo, settingsRepo, NewAuthService("test-secret", 24), nil)
+	userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "green")
+
+	if _, err := service.Request("anna@example.com", ""); err != ErrMagicLinkDisabled {
+		t.Fatalf("Expected links to be disabled by default, got %v", err)
+	}
+	settingsRepo.Update("magic_link_login_enabled", "true")
+	settingsRepo.Update("magic_link_expiry_minutes", "5")
+
+	t.Run("unknown account", func(t *testing.T) {
+		link, err := service.Request("nobody@example.com", "")
+		if err != nil || link.DeviceToken == "" || link.Token != "" || link.User != nil {
+			t.Errorf("Expected only a device token, got %+v (%v)", link, err)
+		}
+	})
+
+	t.Run("login with the link", func(t *testing.T) {
+		link, err := service.Request("anna@example.com", "192.0.2.1")
+		if err != nil || link.Token == "" || link.User == nil || link.User.ID != userID {
+			t.Fatalf("Expected a link for the account, got %+v (%v)", link, err)
+		}
+		if remaining := time.Until(link.ExpiresAt); remaining > 5*time.Minute || remaining < 4*time.Minute {
+			t.Errorf("Expected the configured expiry, got %v", remaining)
+		}
+
+		other, _ := service.Request("nobody@example.com", "")
+		if _, err := service.Verify(link.Token, other.DeviceToken); err != ErrMagicLinkOtherDevice {
+			t.Errorf("Expected the link to be bound to the device, got %v", err)
+		}
+		user, err := service.Verify(link.Token, link.DeviceToken)
+		if err != nil || user.ID != userID {
+			t.Fatalf("Expected the login to succeed, got %v", err)
+		}
+		if _, err := service.Verify(link.Token, link.DeviceToken); err != ErrMagicLinkInvalid {
+			t.Errorf("Expected the link to work only once, got %v", err)
+		}
+	})
+
+	t.Run("per-account limit", func(t *testing.T) {
+		// One link was sent above
+		for i := 1; i < maxMagicLinksPerUser; i++ {
+			if link, _ := service.Request("anna@example.com", ""); link.Token == "" {
+				t.Fatalf("Expected link %d to be sent", i+1)
+			}
+		}
+		link, err := service.Request("anna@example.com", "")
+		if err != nil || link.Token != "" || link.DeviceToken == "" {
+			t.Errorf("Expected no more links within the window, got %+v (%v)", link, err)
+		}
+	})
+
+	t.Run("deactivated account", func(t *testing.T) {
+		db.Exec(`DELETE FROM magic_link_tokens`)
+		link, _ := service.Request("anna@example.com", "")
+		db.Exec(`UPDATE users SET is_active = 0 WHERE id = ?`, userID)
+		if _, err := service.Verify(link.Token, link.DeviceToken); err != ErrMagicLinkInvalid {
+			t.Errorf("Expected deactivated users not to log in, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+
+	"github.com/tranmh/gassigeher/internal/config"
+)
+
+const (
+	// oidcHTTPTimeout limits requests to the provider
+	oidcHTTPTimeout = 10 * time.Second
+
+	// oidcMetadataTTL is how long discovery metadata and keys are cached
+	oidcMetadataTTL = time.Hour
+
+	// oidcKeyReloadInterval limits key reloads for tokens with an unknown kid
+	oidcKeyReloadInterval = time.Minute
+)
+
+// oidcSigningMethods are the ID token algorithms that are accepted; "none" and HMAC never are
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// OIDCClaims are the claims of a verified ID token used for the login
+type OIDCClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+// oidcMetadata is the part of the discovery document that is used
+type oidcMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// OIDCProvider is the client of one OpenID Connect provider
+// Discovery and keys are loaded on first use, so the server starts even if the provider is down
+type OIDCProvider struct {
+	config      config.OIDCProviderConfig
+	redirectURL string
+	httpClient  *http.Client
+
+	mu           sync.Mutex
+	metadata     *oidcMetadata
+	metadataAt   time.Time
+	keys         map[string]crypto.PublicKey
+	keysAt       time.Time
+	keysReloaded time.Time
+}
+
+// NewOIDCProvider creates the client of a provider; redirectURL is the callback URL registered at the provider
+func NewOIDCProvider(cfg config.OIDCProviderConfig, redirectURL string) (*OIDCProvider, error) {
+	if cfg.ID == "" || cfg.IssuerURL == "" || cfg.ClientID == "" {
+		return nil, fmt.Errorf("OIDC provider %q needs an issuer and a client ID", cfg.ID)
+	}
+	return &OIDCProvider{
+		config:      cfg,
+		redirectURL: redirectURL,
+		httpClient:  &http.Client{Timeout: oidcHTTPTimeout},
+	}, nil
+}
+
+// Config returns the configuration of the provider
+func (p *OIDCProvider) Config() config.OIDCProviderConfig {
+	return p.config
+}
+
+// AuthCodeURL returns the provider's login URL with state, nonce and the PKCE challenge
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	return oauthConfig.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier), oauth2.SetAuthURLParam("nonce", nonce)), nil
+}
+
+// Exchange redeems an authorization code and returns the claims of the verified ID token
+func (p *OIDCProvider) Exchange(ctx context.Context, code, verifier, nonce string) (*OIDCClaims, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauthConfig.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.httpClient), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("token response contains no ID token")
+	}
+	return p.VerifyIDToken(ctx, rawIDToken, nonce)
+}
+
+// VerifyIDToken checks the signature, issuer, audience, expiry and nonce of an ID token
+func (p *OIDCProvider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*OIDCClaims, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, metadata.JWKSURI, kid)
+	},
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("invalid ID token: nonce mismatch")
+	}
+	// With several audiences, the token must have been issued to this client
+	if audience, _ := claims.GetAudience(); len(audience) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.config.ClientID {
+			return nil, errors.New("invalid ID token: authorized party mismatch")
+		}
+	}
+
+	result := &OIDCClaims{
+		Subject:       stringClaim(claims, "sub"),
+		Email:         strings.ToLower(strings.TrimSpace(stringClaim(claims, "email"))),
+		EmailVerified: boolClaim(claims, "email_verified"),
+		Name:          strings.TrimSpace(stringClaim(claims, "name")),
+		Groups:        listClaim(claims, p.config.GroupsClaim),
+	}
+	if result.Subject == "" {
+		return nil, errors.New("invalid ID token: no subject")
+	}
+	return result, nil
+}
+
+// oauthConfig builds the OAuth2 client configuration from the discovered endpoints
+func (p *OIDCProvider) oauthConfig(ctx context.Context) (*oauth2.Config, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		Endpoint:     oauth2.Endpoint{AuthURL: metadata.AuthorizationEndpoint, TokenURL: metadata.TokenEndpoint},
+		RedirectURL:  p.redirectURL,
+		Scopes:       p.config.Scopes,
+	}, nil
+}
+
+// discover loads the provider's discovery document, cached for oidcMetadataTTL
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcMetadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil && time.Since(p.metadataAt) < oidcMetadataTTL {
+		return p.metadata, nil
+	}
+
+	metadata := &oidcMetadata{}
+	if err := p.getJSON(ctx, p.config.IssuerURL+"/.well-known/openid-configuration", metadata); err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider %s: %w", p.config.ID, err)
+	}
+	if metadata.Issuer != p.config.IssuerURL {
+		return nil, fmt.Errorf("OIDC provider %s reports issuer %q instead of %q", p.config.ID, metadata.Issuer, p.config.IssuerURL)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC provider %s has an incomplete discovery document", p.config.ID)
+	}
+
+	p.metadata = metadata
+	p.metadataAt = time.Now()
+	return metadata, nil
+}
+
+// publicKey returns a key of the provider's JWKS; an unknown kid reloads the keys,
+// since providers rotate them, but at most once per oidcKeyReloadInterval
+func (p *OIDCProvider) publicKey(ctx context.Context, jwksURI, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, found := p.lookupKey(kid)
+	expired := time.Since(p.keysAt) >= oidcMetadataTTL
+	if found && !expired {
+		return key, nil
+	}
+	if !expired && time.Since(p.keysReloaded) < oidcKeyReloadInterval {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	p.keysReloaded = time.Now()
+	if err := p.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to load keys of OIDC provider %s: %w", p.config.ID, err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, raw := range set.Keys {
+		jwkKid, publicKey, err := parseOIDCKey(raw)
+		if err != nil {
+			continue // keys of unsupported types or for encryption are skipped
+		}
+		keys[jwkKid] = publicKey
+	}
+	p.keys = keys
+	p.keysAt = time.Now()
+
+	if key, found := p.lookupKey(kid); found {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// lookupKey finds a key by kid; tokens without a kid are accepted if the provider has a single key
+func (p *OIDCProvider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, found := p.keys[kid]
+	return key, found
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, url string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// parseOIDCKey parses an RSA, EC or Ed25519 signing key of a JWKS
+func parseOIDCKey(raw json.RawMessage) (string, crypto.PublicKey, error) {
+	var jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Crv string `json:"crv"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+	if err := json.Unmarshal(raw, &jwk); err != nil {
+		return "", nil, err
+	}
+	if jwk.Use != "" && jwk.Use != "sig" {
+		return "", nil, errors.New("not a signing key")
+	}
+
+	decode := base64.RawURLEncoding.DecodeString
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return "", nil, err
+		}
+		e, err := decode(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return "", nil, errors.New("invalid RSA exponent")
+		}
+		return jwk.Kid, &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return "", nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return "", nil, err
+		}
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return "", nil, err
+		}
+		return jwk.Kid, &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		x, err := decode(jwk.X)
+		if err != nil || jwk.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return "", nil, errors.New("invalid Ed25519 key")
+		}
+		return jwk.Kid, ed25519.PublicKey(x), nil
+	}
+	return "", nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// boolClaim reads a boolean claim; some providers send "true" as a string
+func boolClaim(claims jwt.MapClaims, name string) bool {
+	switch value := claims[name].(type) {
+	case bool:
+		return value
+	case string:
+		return value == "true"
+	}
+	return false
+}
+
+// listClaim reads a claim that is a list of strings or a single string
+func listClaim(claims jwt.MapClaims, name string) []string {
+	var values []string
+	switch value := claims[name].(type) {
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	case string:
+		values = append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+const (
+	// oidcLoginTimeout is how long a user has to log in at the provider
+	oidcLoginTimeout = 10 * time.Minute
+
+	// oidcLoginCodeLifetime is how long the browser has to exchange the login code after the callback
+	oidcLoginCodeLifetime = 2 * time.Minute
+)
+
+var (
+	// ErrOIDCUnknownProvider is returned for providers that are not configured
+	ErrOIDCUnknownProvider = errors.New("unknown OIDC provider")
+
+	// ErrOIDCInvalidState is returned for unknown, used and expired logins
+	ErrOIDCInvalidState = errors.New("invalid or expired OIDC login")
+
+	// ErrOIDCEmailNotVerified is returned if the provider did not verify the email address of a new identity
+	ErrOIDCEmailNotVerified = errors.New("email address not verified by the provider")
+
+	// ErrOIDCDomainNotAllowed is returned for email addresses outside the provider's allowed domains
+	ErrOIDCDomainNotAllowed = errors.New("email domain not allowed")
+
+	// ErrOIDCNoAccount is returned if no account matches and accounts are not created automatically
+	ErrOIDCNoAccount = errors.New("no account for this identity")
+
+	// ErrOIDCAccountNotVerified is returned if the matching account has not confirmed its email address yet;
+	// linking it would hand the account to whoever registered it
+	ErrOIDCAccountNotVerified = errors.New("account email address not verified")
+
+	// ErrOIDCAccountInactive is returned for deactivated accounts
+	ErrOIDCAccountInactive = errors.New("account is deactivated")
+)
+
+// OIDCStart is a login started at a provider
+type OIDCStart struct {
+	AuthURL string // the browser is redirected here
+	State   string // kept in a cookie of the browser, to bind the callback and login code to it
+}
+
+// OIDCService handles single sign-on with OpenID Connect providers: it links identities
+// to accounts by verified email, optionally creates accounts and syncs roles from groups
+type OIDCService struct {
+	providers    map[string]*OIDCProvider
+	order        []string
+	loginRepo    *repository.OIDCLoginRepository
+	identityRepo *repository.UserIdentityRepository
+	userRepo     *repository.UserRepository
+	roleRepo     *repository.RoleRepository
+	authService  *AuthService
+	audit        *AuditService
+	sessions     *SessionService
+}
+
+// NewOIDCService creates the service for the configured providers; callbacks go to
+// {baseURL}/api/auth/oidc/{id}/callback
+func NewOIDCService(providers []config.OIDCProviderConfig, baseURL string, loginRepo *repository.OIDCLoginRepository, identityRepo *repository.UserIdentityRepository, userRepo *repository.UserRepository, roleRepo *repository.RoleRepository, authService *AuthService, audit *AuditService, sessions *SessionService) (*OIDCService, error) {
+	if err := ValidateOIDCProviders(providers); err != nil {
+		return nil, err
+	}
+
+	s := &OIDCService{
+		providers:    map[string]*OIDCProvider{},
+		loginRepo:    loginRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		authService:  authService,
+		audit:        audit,
+		sessions:     sessions,
+	}
+	for _, cfg := range providers {
+		provider, err := NewOIDCProvider(cfg, strings.TrimSuffix(baseURL, "/")+"/api/auth/oidc/"+cfg.ID+"/callback")
+		if err != nil {
+			return nil, err
+		}
+		s.providers[cfg.ID] = provider
+		s.order = append(s.order, cfg.ID)
+	}
+	return s, nil
+}
+
+// ValidateOIDCProviders checks the provider configuration without contacting the providers
+func ValidateOIDCProviders(providers []config.OIDCProviderConfig) error {
+	seen := map[string]bool{}
+	for _, cfg := range providers {
+		if seen[cfg.ID] {
+			return fmt.Errorf("OIDC provider %q is configured twice", cfg.ID)
+		}
+		seen[cfg.ID] = true
+		if cfg.IssuerURL == "" || cfg.ClientID == "" {
+			return fmt.Errorf("OIDC provider %q needs an issuer and a client ID", cfg.ID)
+		}
+		if !isExperienceLevel(cfg.DefaultLevel) {
+			return fmt.Errorf("OIDC provider %q has an invalid default level %q", cfg.ID, cfg.DefaultLevel)
+		}
+	}
+	return nil
+}
+
+// Providers returns the configured providers for the login page
+func (s *OIDCService) Providers() []models.SSOProvider {
+	providers := []models.SSOProvider{}
+	for _, id := range s.order {
+		providers = append(providers, models.SSOProvider{
+			ID:       id,
+			Name:     s.providers[id].Config().Name,
+			LoginURL: "/api/auth/oidc/" + id + "/login",
+		})
+	}
+	return providers
+}
+
+// Start begins a login at a provider; redirectPath is where the browser goes after the login
+func (s *OIDCService) Start(ctx context.Context, providerID, redirectPath string) (*OIDCStart, error) {
+	provider, ok := s.providers[providerID]
+	if !ok {
+		return nil, ErrOIDCUnknownProvider
+	}
+
+	state, err := s.authService.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := s.authService.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	login := &models.OIDCLogin{
+		StateHash:    hashToken(state),
+		Provider:     providerID,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcLoginTimeout),
+	}
+	if redirectPath != "" {
+		login.RedirectPath = &redirectPath
+	}
+	if err := s.loginRepo.Create(login); err != nil {
+		return nil, err
+	}
+
+	return &OIDCStart{AuthURL: authURL, State: state}, nil
+}
+
+// Callback finishes the login at the provider and returns a single-use login code and the redirect path
+// state must match the state kept by the browser that started the login
+func (s *OIDCService) Callback(ctx context.Context, providerID, state, code, ipAddress string) (string, string, error) {
+	provider, ok := s.providers[providerID]
+	if !ok {
+		return "", "", ErrOIDCUnknownProvider
+	}
+
+	login, err := s.loginRepo.FindByStateHash(hashToken(state))
+	if err != nil {
+		return "", "", err
+	}
+	if login == nil || login.Provider != providerID {
+		return "", "", ErrOIDCInvalidState
+	}
+	accepted, err := s.loginRepo.MarkCallback(login.ID)
+	if err != nil {
+		return "", "", err
+	}
+	if !accepted {
+		return "", "", ErrOIDCInvalidState
+	}
+
+	claims, err := provider.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		return "", "", err
+	}
+
+	user, err := s.resolveUser(provider.Config(), claims)
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.syncRoles(provider.Config(), user, claims.Groups, ipAddress); err != nil {
+		return "", "", err
+	}
+
+	loginCode, err := s.authService.GenerateToken()
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.loginRepo.SetLoginCode(login.ID, user.ID, hashToken(loginCode), time.Now().Add(oidcLoginCodeLifetime)); err != nil {
+		return "", "", err
+	}
+
+	redirectPath := ""
+	if login.RedirectPath != nil {
+		redirectPath = *login.RedirectPath
+	}
+	return loginCode, redirectPath, nil
+}
+
+// Redeem uses up a login code and returns its user, who must still be allowed to log in
+func (s *OIDCService) Redeem(loginCode, state string) (*models.User, error) {
+	login, err := s.loginRepo.FindByLoginCodeHash(hashToken(loginCode))
+	if err != nil {
+		return nil, err
+	}
+	if login == nil || login.UserID == nil ||
+		subtle.ConstantTimeCompare([]byte(login.StateHash), []byte(hashToken(state))) != 1 {
+		return nil, ErrOIDCInvalidState
+	}
+	used, err := s.loginRepo.MarkUsed(login.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, ErrOIDCInvalidState
+	}
+
+	user, err := s.userRepo.FindByID(*login.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.IsDeleted || !user.IsActive {
+		return nil, ErrOIDCAccountInactive
+	}
+	return user, nil
+}
+
+// resolveUser finds the account of an identity: by its link, by verified email (linking it),
+// or by creating it if the provider allows that
+func (s *OIDCService) resolveUser(cfg config.OIDCProviderConfig, claims *OIDCClaims) (*models.User, error) {
+	if !emailDomainAllowed(claims.Email, cfg.AllowedDomains) {
+		return nil, ErrOIDCDomainNotAllowed
+	}
+
+	var email *string
+	if claims.Email != "" {
+		email = &claims.Email
+	}
+
+	identity, err := s.identityRepo.FindByProviderSubject(cfg.ID, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil && !user.IsDeleted {
+			if !user.IsActive {
+				return nil, ErrOIDCAccountInactive
+			}
+			if err := s.identityRepo.UpdateLogin(identity.ID, email); err != nil {
+				return nil, err
+			}
+			return user, nil
+		}
+	}
+
+	// New identity: only an email address the provider verified can match or create an account
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.userRepo.FindByEmail(claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		if !user.IsVerified {
+			return nil, ErrOIDCAccountNotVerified
+		}
+		if !user.IsActive {
+			return nil, ErrOIDCAccountInactive
+		}
+	} else {
+		if !cfg.AutoCreate {
+			return nil, ErrOIDCNoAccount
+		}
+		if user, err = s.createUser(cfg, claims); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.identityRepo.Create(&models.UserIdentity{
+		UserID:   user.ID,
+		Provider: cfg.ID,
+		Subject:  claims.Subject,
+		Email:    email,
+	}); err != nil {
+		return nil, err
+	}
+	log.Printf("Linked %s identity to user %d", cfg.ID, user.ID)
+	return user, nil
+}
+
+// createUser creates a verified account without password for a provider's user
+func (s *OIDCService) createUser(cfg config.OIDCProviderConfig, claims *OIDCClaims) (*models.User, error) {
+	name := claims.Name
+	if name == "" {
+		name = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+
+	now := time.Now()
+	user := &models.User{
+		Name:            name,
+		Email:           &claims.Email,
+		ExperienceLevel: cfg.DefaultLevel,
+		IsVerified:      true,
+		IsActive:        true,
+		TermsAcceptedAt: now,
+		LastActivityAt:  now,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+	log.Printf("Created user %d from %s single sign-on", user.ID, cfg.ID)
+	return user, nil
+}
+
+// syncRoles sets the roles named in the provider's group mapping from the user's groups;
+// other roles are left alone. Losing a permission logs the user out everywhere else
+func (s *OIDCService) syncRoles(cfg config.OIDCProviderConfig, user *models.User, groups []string, ipAddress string) error {
+	if len(cfg.GroupRoles) == 0 {
+		return nil
+	}
+
+	managed := map[string]bool{}
+	for _, roles := range cfg.GroupRoles {
+		for _, role := range roles {
+			managed[role] = true
+		}
+	}
+	wanted := map[string]bool{}
+	for _, group := range groups {
+		for _, role := range cfg.GroupRoles[group] {
+			wanted[role] = true
+		}
+	}
+
+	allRoles, err := s.roleRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	roleIDs := map[string]int{}
+	for _, role := range allRoles {
+		roleIDs[role.Name] = role.ID
+	}
+
+	current, err := s.roleRepo.FindByUser(user.ID)
+	if err != nil {
+		return err
+	}
+	var before, after []string
+	var ids []int
+	for _, role := range current {
+		before = append(before, role.Name)
+		if !managed[role.Name] {
+			after = append(after, role.Name)
+			ids = append(ids, role.ID)
+		}
+	}
+	for name := range wanted {
+		id, exists := roleIDs[name]
+		if !exists {
+			log.Printf("OIDC provider %s maps a group to unknown role %q", cfg.ID, name)
+			continue
+		}
+		after = append(after, name)
+		ids = append(ids, id)
+	}
+
+	sort.Strings(before)
+	sort.Strings(after)
+	if strings.Join(before, "\n") == strings.Join(after, "\n") {
+		return nil
+	}
+
+	permissionsBefore, err := s.roleRepo.GetUserPermissions(user.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.roleRepo.SetUserRoles(user.ID, ids, 0); err != nil {
+		return err
+	}
+	if s.audit != nil {
+		s.audit.Record(&models.AuditActor{IPAddress: ipAddress}, models.AuditUserRolesSet, models.AuditTargetUser, &user.ID,
+			map[string]interface{}{"roles": before}, map[string]interface{}{"roles": after, "sso_provider": cfg.ID})
+	}
+
+	updated, err := s.userRepo.FindByID(user.ID)
+	if err != nil {
+		return err
+	}
+	if s.sessions != nil {
+		permissionsAfter, err := s.roleRepo.GetUserPermissions(user.ID)
+		if err != nil || models.PermissionsLost(permissionsBefore, permissionsAfter) {
+			s.sessions.RevokeAllQuietly(user.ID, "role change by single sign-on")
+		}
+	}
+	*user = *updated
+	return nil
+}
+
+// emailDomainAllowed checks an email address against a list of domains; an empty list allows all
+func emailDomainAllowed(email string, domains []string) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range domains {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// isExperienceLevel returns true for the experience levels users can have
+func isExperienceLevel(level string) bool {
+	return level == "green" || level == "blue" || level == "orange"
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/tranmh/gassigeher/internal/config"
+	"github.com/tranmh/gassigeher/internal/oidcmock"
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestOIDCService tests single sign-on against a mock provider: linking by verified email,
+// just-in-time accounts, group to role mapping and single use of state and login code
+func TestOIDCService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	provider, server, err := oidcmock.NewServer("gassigeher", "secret")
+	if err != nil {
+		t.Fatalf("Failed to start mock provider: %v", err)
+	}
+	defer server.Close()
+
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	newService := func(cfg config.OIDCProviderConfig) *OIDCService {
+		cfg.ID, cfg.Name, cfg.IssuerURL, cfg.ClientID, cfg.ClientSecret = "mock", "Mock", provider.Issuer(), "gassigeher", "secret"
+		cfg.Scopes, cfg.GroupsClaim = []string{"openid", "email", "profile"}, "groups"
+		if cfg.DefaultLevel == "" {
+			cfg.DefaultLevel = "green"
+		}
+		service, err := NewOIDCService([]config.OIDCProviderConfig{cfg}, "http://localhost:8080",
+			repository.NewOIDCLoginRepository(db), repository.NewUserIdentityRepository(db), userRepo, roleRepo,
+			NewAuthService("test-secret", 24), nil, nil)
+		if err != nil {
+			t.Fatalf("NewOIDCService() failed: %v", err)
+		}
+		return service
+	}
+
+	// login runs the browser's part of the flow and returns the login code and the state of the browser
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	login := func(service *OIDCService, claims map[string]interface{}) (string, string, error) {
+		provider.SetUser(claims)
+		start, err := service.Start(context.Background(), "mock", "/dogs.html")
+		if err != nil {
+			t.Fatalf("Start() failed: %v", err)
+		}
+		resp, err := noRedirect.Get(start.AuthURL)
+		if err != nil {
+			t.Fatalf("Authorization request failed: %v", err)
+		}
+		resp.Body.Close()
+		location, _ := url.Parse(resp.Header.Get("Location"))
+		if location.Query().Get("state") != start.State || location.Path != "/api/auth/oidc/mock/callback" {
+			t.Fatalf("Unexpected redirect from the provider: %s", location)
+		}
+
+		loginCode, redirect, err := service.Callback(context.Background(), "mock", start.State, location.Query().Get("code"), "192.0.2.1")
+		if err == nil && redirect != "/dogs.html" {
+			t.Errorf("Expected the redirect path to be kept, got %q", redirect)
+		}
+		return loginCode, start.State, err
+	}
+	anna := map[string]interface{}{"sub": "idp-anna", "email": "Anna@Example.com", "email_verified": true, "name": "Anna"}
+
+	t.Run("no account", func(t *testing.T) {
+		if _, _, err := login(newService(config.OIDCProviderConfig{}), anna); err != ErrOIDCNoAccount {
+			t.Errorf("Expected ErrOIDCNoAccount, got %v", err)
+		}
+	})
+
+	t.Run("link by verified email", func(t *testing.T) {
+		service := newService(config.OIDCProviderConfig{})
+		userID := testutil.SeedTestUser(t, db, "anna@example.com", "Anna", "blue")
+
+		unverified := map[string]interface{}{"sub": "idp-anna", "email": "anna@example.com", "email_verified": false}
+		if _, _, err := login(service, unverified); err != ErrOIDCEmailNotVerified {
+			t.Errorf("Expected ErrOIDCEmailNotVerified, got %v", err)
+		}
+
+		loginCode, state, err := login(service, anna)
+		if err != nil {
+			t.Fatalf("Callback() failed: %v", err)
+		}
+		if _, err := service.Redeem(loginCode, "other-browser"); err != ErrOIDCInvalidState {
+			t.Errorf("Expected the login code to be bound to the browser, got %v", err)
+		}
+		user, err := service.Redeem(loginCode, state)
+		if err != nil || user.ID != userID {
+			t.Fatalf("Expected the linked user, got %v", err)
+		}
+		if _, err := service.Redeem(loginCode, state); err != ErrOIDCInvalidState {
+			t.Errorf("Expected the login code to work only once, got %v", err)
+		}
+
+		// Once linked, the subject identifies the user, even with another email address at the provider
+		loginCode, state, err = login(service, map[string]interface{}{"sub": "idp-anna", "email": "anna@new.example.com"})
+		if err != nil {
+			t.Fatalf("Expected the linked identity to log in, got %v", err)
+		}
+		if user, _ := service.Redeem(loginCode, state); user == nil || user.ID != userID {
+			t.Error("Expected the linked user")
+		}
+	})
+
+	t.Run("unverified local account is not linked", func(t *testing.T) {
+		userID := testutil.SeedTestUser(t, db, "ben@example.com", "Ben", "green")
+		db.Exec(`UPDATE users SET is_verified = 0 WHERE id = ?`, userID)
+		ben := map[string]interface{}{"sub": "idp-ben", "email": "ben@example.com", "email_verified": true}
+		if _, _, err := login(newService(config.OIDCProviderConfig{AutoCreate: true}), ben); err != ErrOIDCAccountNotVerified {
+			t.Errorf("Expected ErrOIDCAccountNotVerified, got %v", err)
+		}
+	})
+
+	t.Run("allowed domains", func(t *testing.T) {
+		service := newService(config.OIDCProviderConfig{AllowedDomains: []string{"tierheim.example"}})
+		if _, _, err := login(service, anna); err != ErrOIDCDomainNotAllowed {
+			t.Errorf("Expected ErrOIDCDomainNotAllowed, got %v", err)
+		}
+	})
+
+	t.Run("just-in-time account with group roles", func(t *testing.T) {
+		service := newService(config.OIDCProviderConfig{
+			AutoCreate:   true,
+			DefaultLevel: "blue",
+			GroupRoles:   map[string][]string{"staff": {"admin"}, "vets": {"dog_coordinator", "unknown"}},
+		})
+		carla := map[string]interface{}{"sub": "idp-carla", "email": "carla@example.com", "email_verified": true,
+			"name": "Carla", "groups": []string{"staff", "vets", "volunteers"}}
+
+		loginCode, state, err := login(service, carla)
+		if err != nil {
+			t.Fatalf("Callback() failed: %v", err)
+		}
+		user, err := service.Redeem(loginCode, state)
+		if err != nil {
+			t.Fatalf("Redeem() failed: %v", err)
+		}
+		if user.Name != "Carla" || user.ExperienceLevel != "blue" || !user.IsVerified || user.PasswordHash != nil || !user.IsAdmin {
+			t.Errorf("Unexpected created user: %+v", user)
+		}
+		roles, _ := roleRepo.FindByUser(user.ID)
+		if len(roles) != 2 {
+			t.Errorf("Expected the admin and dog_coordinator roles, got %d roles", len(roles))
+		}
+
+		// Leaving the group removes the mapped role on the next login; unmapped roles stay
+		approver := 0
+		all, _ := roleRepo.FindAll()
+		for _, role := range all {
+			if role.Name == "approver" {
+				approver = role.ID
+			}
+		}
+		roleRepo.SetUserRoles(user.ID, []int{approver, roles[0].ID, roles[1].ID}, 0)
+		carla["groups"] = []string{"vets"}
+		loginCode, state, _ = login(service, carla)
+		user, _ = service.Redeem(loginCode, state)
+		roles, _ = roleRepo.FindByUser(user.ID)
+		if user.IsAdmin || len(roles) != 2 {
+			t.Errorf("Expected the admin role to be removed and approver to stay, got admin=%v and %d roles", user.IsAdmin, len(roles))
+		}
+	})
+
+	t.Run("state is accepted once", func(t *testing.T) {
+		service := newService(config.OIDCProviderConfig{})
+		provider.SetUser(anna)
+		start, _ := service.Start(context.Background(), "mock", "")
+		resp, _ := noRedirect.Get(start.AuthURL)
+		resp.Body.Close()
+		location, _ := url.Parse(resp.Header.Get("Location"))
+
+		if _, _, err := service.Callback(context.Background(), "mock", start.State, location.Query().Get("code"), ""); err != nil {
+			t.Fatalf("Callback() failed: %v", err)
+		}
+		if _, _, err := service.Callback(context.Background(), "mock", start.State, location.Query().Get("code"), ""); err != ErrOIDCInvalidState {
+			t.Errorf("Expected a replayed callback to fail, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProfilePhotoURLTTL is how long a signed profile photo URL stays valid
+const ProfilePhotoURLTTL = time.Hour
+
+// IncidentPhotoURLTTL is how long a signed incident photo URL stays valid
+const IncidentPhotoURLTTL = time.Hour
+
+// PhotoURLSigner creates and verifies signed, expiring URLs for private uploads
+// Private uploads (profile photos, incident photos) are not served by the public /uploads/ file server
+type PhotoURLSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewPhotoURLSigner creates a new photo URL signer
+func NewPhotoURLSigner(secret string, ttl time.Duration) *PhotoURLSigner {
+	return &PhotoURLSigner{
+		secret: []byte(secret),
+		ttl:    ttl,
+	}
+}
+
+// SignedURL returns a URL for the relative upload path (e.g., "users/user_3_..._full.jpg")
+// like "/api/photos/users/user_3_..._full.jpg?expires=1735689600&sig=..."
+func (s *PhotoURLSigner) SignedURL(relPath string) string {
+	relPath = filepath.ToSlash(relPath)
+	expires := time.Now().Add(s.ttl).Unix()
+
+	segments := strings.Split(relPath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return fmt.Sprintf("/api/photos/%s?expires=%d&sig=%s", strings.Join(segments, "/"), expires, s.signature(relPath, expires))
+}
+
+// Verify checks the signature and expiry of a signed URL for the relative upload path
+func (s *PhotoURLSigner) Verify(relPath, expires, sig string) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	expected := s.signature(filepath.ToSlash(relPath), expiresAt)
+	return hmac.Equal([]byte(expected), []byte(sig))
+}
+
+// signature computes the HMAC of the path and expiry
+func (s *PhotoURLSigner) signature(relPath string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "photo:%s:%d", relPath, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
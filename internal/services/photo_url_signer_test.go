@@ -0,0 +1,68 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DONE: TestPhotoURLSigner tests signing and verifying photo URLs
+func TestPhotoURLSigner(t *testing.T) {
+	signer := NewPhotoURLSigner("test-secret", time.Hour)
+	relPath := "users/user_3_0123456789abcdef0123_full.jpg"
+
+	parse := func(signedURL string) url.Values {
+		u, err := url.Parse(signedURL)
+		if err != nil {
+			t.Fatalf("Failed to parse URL %q: %v", signedURL, err)
+		}
+		return u.Query()
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		signedURL := signer.SignedURL(relPath)
+		if !strings.HasPrefix(signedURL, "/api/photos/"+relPath+"?") {
+			t.Errorf("Unexpected URL %q", signedURL)
+		}
+		query := parse(signedURL)
+		if !signer.Verify(relPath, query.Get("expires"), query.Get("sig")) {
+			t.Error("Expected signature to be valid")
+		}
+	})
+
+	t.Run("other path", func(t *testing.T) {
+		query := parse(signer.SignedURL(relPath))
+		if signer.Verify("users/user_4_0123456789abcdef0123_full.jpg", query.Get("expires"), query.Get("sig")) {
+			t.Error("Expected signature to be bound to the path")
+		}
+	})
+
+	t.Run("other secret", func(t *testing.T) {
+		query := parse(NewPhotoURLSigner("other-secret", time.Hour).SignedURL(relPath))
+		if signer.Verify(relPath, query.Get("expires"), query.Get("sig")) {
+			t.Error("Expected signature of another secret to be rejected")
+		}
+	})
+
+	t.Run("extended expiry", func(t *testing.T) {
+		query := parse(signer.SignedURL(relPath))
+		expires := query.Get("expires")
+		if signer.Verify(relPath, expires+"0", query.Get("sig")) {
+			t.Error("Expected changed expiry to be rejected")
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		query := parse(NewPhotoURLSigner("test-secret", -time.Minute).SignedURL(relPath))
+		if signer.Verify(relPath, query.Get("expires"), query.Get("sig")) {
+			t.Error("Expected expired URL to be rejected")
+		}
+	})
+
+	t.Run("malformed expiry", func(t *testing.T) {
+		if signer.Verify(relPath, "tomorrow", "abc") {
+			t.Error("Expected malformed expiry to be rejected")
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+const (
+	// DefaultAccessTokenLifetime is used if no access token lifetime is configured
+	DefaultAccessTokenLifetime = 15 * time.Minute
+
+	// DefaultRefreshTokenLifetime is used if no session lifetime is configured
+	DefaultRefreshTokenLifetime = 30 * 24 * time.Hour
+
+	// refreshReuseGrace is how long a rotated refresh token is rejected without revoking the session;
+	// browser tabs sharing a token may refresh at the same time
+	refreshReuseGrace = time.Minute
+
+	// maxUserAgentLength fits the user_agent column on all databases
+	maxUserAgentLength = 255
+)
+
+// SessionService issues access tokens for login sessions and rotates their refresh tokens
+type SessionService struct {
+	sessionRepo     *repository.SessionRepository
+	userRepo        *repository.UserRepository
+	authService     *AuthService
+	accessLifetime  time.Duration
+	refreshLifetime time.Duration
+}
+
+// NewSessionService creates a new session service; lifetimes of zero use the defaults
+func NewSessionService(sessionRepo *repository.SessionRepository, userRepo *repository.UserRepository, authService *AuthService, accessLifetime, refreshLifetime time.Duration) *SessionService {
+	if accessLifetime <= 0 {
+		accessLifetime = DefaultAccessTokenLifetime
+	}
+	if refreshLifetime <= 0 {
+		refreshLifetime = DefaultRefreshTokenLifetime
+	}
+	return &SessionService{
+		sessionRepo:     sessionRepo,
+		userRepo:        userRepo,
+		authService:     authService,
+		accessLifetime:  accessLifetime,
+		refreshLifetime: refreshLifetime,
+	}
+}
+
+// Create starts a session for a user who completed all login steps
+func (s *SessionService) Create(user *models.User, userAgent, ipAddress string) (*models.SessionTokens, error) {
+	refreshToken, err := s.authService.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.UserSession{
+		UserID:           user.ID,
+		RefreshTokenHash: hashToken(refreshToken),
+		UserAgent:        optionalString(truncateUserAgent(userAgent)),
+		IPAddress:        optionalString(ipAddress),
+		ExpiresAt:        time.Now().Add(s.refreshLifetime),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return s.issue(user, session.ID, refreshToken)
+}
+
+// Refresh exchanges a refresh token for new tokens and returns them with the current user
+// Admin flags in the new access token are read from the database, so demotions apply on refresh.
+// Presenting a refresh token that was already rotated revokes the session, as it may have been stolen
+func (s *SessionService) Refresh(refreshToken, userAgent, ipAddress string) (*models.SessionTokens, *models.User, error) {
+	hash := hashToken(refreshToken)
+	session, err := s.sessionRepo.FindByTokenHash(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session == nil {
+		if reused, err := s.sessionRepo.FindByPreviousTokenHash(hash); err == nil && reused != nil &&
+			time.Since(reused.LastUsedAt) > refreshReuseGrace {
+			log.Printf("Refresh token of session %d was reused, revoking the session", reused.ID)
+			if err := s.sessionRepo.Revoke(reused.ID); err != nil {
+				log.Printf("Error revoking session %d: %v", reused.ID, err)
+			}
+		}
+		return nil, nil, fmt.Errorf("invalid refresh token")
+	}
+	if !session.IsActive(time.Now()) {
+		return nil, nil, fmt.Errorf("session ended")
+	}
+
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil || user.Email == nil || !user.IsActive || user.IsDeleted {
+		s.sessionRepo.Revoke(session.ID)
+		return nil, nil, fmt.Errorf("user cannot log in")
+	}
+
+	newToken, err := s.authService.GenerateToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	rotated, err := s.sessionRepo.Rotate(session.ID, hash, hashToken(newToken), truncateUserAgent(userAgent), ipAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rotated {
+		return nil, nil, fmt.Errorf("refresh token already used")
+	}
+
+	tokens, err := s.issue(user, session.ID, newToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, user, nil
+}
+
+// RevokeByRefreshToken ends the session of a refresh token, if it exists
+func (s *SessionService) RevokeByRefreshToken(refreshToken string) error {
+	session, err := s.sessionRepo.FindByTokenHash(hashToken(refreshToken))
+	if err != nil || session == nil {
+		return err
+	}
+	return s.sessionRepo.Revoke(session.ID)
+}
+
+// RevokeAll ends all sessions of a user except exceptSessionID (0 for none)
+func (s *SessionService) RevokeAll(userID, exceptSessionID int) (int64, error) {
+	return s.sessionRepo.RevokeAllForUser(userID, exceptSessionID)
+}
+
+// RevokeAllQuietly ends all sessions of a user after a change that must invalidate their logins
+// (deactivation, demotion); failures are logged, as the change itself already succeeded
+func (s *SessionService) RevokeAllQuietly(userID int, reason string) {
+	if _, err := s.sessionRepo.RevokeAllForUser(userID, 0); err != nil {
+		log.Printf("Error revoking sessions of user %d after %s: %v", userID, reason, err)
+	}
+}
+
+// List returns the active sessions of a user and marks the current one
+func (s *SessionService) List(userID, currentSessionID int) ([]*models.UserSession, error) {
+	sessions, err := s.sessionRepo.ListActiveByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// Revoke ends a session of a user; it returns false if the user has no such session
+func (s *SessionService) Revoke(userID, sessionID int) (bool, error) {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		return false, err
+	}
+	if session == nil || session.UserID != userID {
+		return false, nil
+	}
+	return true, s.sessionRepo.Revoke(sessionID)
+}
+
+// issue creates the access token of a session
+func (s *SessionService) issue(user *models.User, sessionID int, refreshToken string) (*models.SessionTokens, error) {
+	accessToken, err := s.authService.GenerateAccessToken(user.ID, *user.Email, user.IsAdmin, user.IsSuperAdmin, sessionID, s.accessLifetime)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SessionTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.accessLifetime.Seconds()),
+		SessionID:    sessionID,
+	}, nil
+}
+
+// hashToken hashes a refresh or login link token; tokens are random, so a fast hash is sufficient
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// truncateUserAgent shortens long user agents to the column size
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) > maxUserAgentLength {
+		return userAgent[:maxUserAgentLength]
+	}
+	return userAgent
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestSessionService tests refresh token rotation, reuse detection and fresh admin flags
+func TestSessionService(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	authService := NewAuthService("test-secret", 24)
+	sessionRepo := repository.NewSessionRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	service := NewSessionService(sessionRepo, userRepo, authService, time.Minute, 0)
+
+	userID := testutil.SeedTestUser(t, db, "admin@example.com", "Admin", "orange")
+	db.Exec(`UPDATE users SET is_admin = 1 WHERE id = ?`, userID)
+	user, _ := userRepo.FindByID(userID)
+
+	tokens, err := service.Create(user, "Firefox", "192.0.2.1")
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if tokens.ExpiresIn != 60 || tokens.RefreshToken == "" {
+		t.Errorf("Unexpected tokens: %+v", tokens)
+	}
+	claims, err := authService.ValidateJWT(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Invalid access token: %v", err)
+	}
+	if (*claims)["sid"] != float64(tokens.SessionID) || (*claims)["is_admin"] != true {
+		t.Errorf("Unexpected claims: %v", *claims)
+	}
+
+	t.Run("refresh rotates the token and reads admin flags", func(t *testing.T) {
+		db.Exec(`UPDATE users SET is_admin = 0 WHERE id = ?`, userID)
+
+		refreshed, refreshedUser, err := service.Refresh(tokens.RefreshToken, "Firefox", "192.0.2.2")
+		if err != nil {
+			t.Fatalf("Refresh() failed: %v", err)
+		}
+		if refreshed.RefreshToken == tokens.RefreshToken || refreshed.SessionID != tokens.SessionID {
+			t.Errorf("Expected a new refresh token for the same session, got %+v", refreshed)
+		}
+		if refreshedUser.IsAdmin {
+			t.Error("Expected the demotion to apply on refresh")
+		}
+		claims, _ := authService.ValidateJWT(refreshed.AccessToken)
+		if (*claims)["is_admin"] != false {
+			t.Errorf("Expected is_admin false in the new access token, got %v", (*claims)["is_admin"])
+		}
+
+		// Within the grace period the old token is only rejected
+		if _, _, err := service.Refresh(tokens.RefreshToken, "", ""); err == nil {
+			t.Error("Expected the rotated token to be rejected")
+		}
+		if active, _ := sessionRepo.IsActive(tokens.SessionID); !active {
+			t.Fatal("Expected the session to stay active within the grace period")
+		}
+
+		// Later, reuse of the old token revokes the session
+		db.Exec(`UPDATE user_sessions SET last_used_at = ? WHERE id = ?`, time.Now().Add(-time.Hour), tokens.SessionID)
+		if _, _, err := service.Refresh(tokens.RefreshToken, "", ""); err == nil {
+			t.Error("Expected the reused token to be rejected")
+		}
+		if active, _ := sessionRepo.IsActive(tokens.SessionID); active {
+			t.Error("Expected reuse of a rotated token to revoke the session")
+		}
+		if _, _, err := service.Refresh(refreshed.RefreshToken, "", ""); err == nil {
+			t.Error("Expected the current token of a revoked session to be rejected")
+		}
+	})
+
+	t.Run("deactivated users cannot refresh", func(t *testing.T) {
+		tokens, _ := service.Create(user, "", "")
+		db.Exec(`UPDATE users SET is_active = 0 WHERE id = ?`, userID)
+		defer db.Exec(`UPDATE users SET is_active = 1 WHERE id = ?`, userID)
+
+		if _, _, err := service.Refresh(tokens.RefreshToken, "", ""); err == nil {
+			t.Error("Expected refresh to fail for a deactivated user")
+		}
+	})
+
+	t.Run("list marks the current session", func(t *testing.T) {
+		current, _ := service.Create(user, "Safari", "")
+		other, _ := service.Create(user, "Chrome", "")
+
+		sessions, err := service.List(userID, current.SessionID)
+		if err != nil {
+			t.Fatalf("List() failed: %v", err)
+		}
+		for _, session := range sessions {
+			if session.Current != (session.ID == current.SessionID) {
+				t.Errorf("Unexpected current flag for session %d", session.ID)
+			}
+		}
+
+		if found, _ := service.Revoke(userID+1, other.SessionID); found {
+			t.Error("Expected sessions of other users not to be found")
+		}
+		if found, _ := service.Revoke(userID, other.SessionID); !found {
+			t.Error("Expected the session to be revoked")
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Storage defines the interface for storing uploaded files across different backends
+// Supports the local filesystem and S3-compatible object storage (AWS S3, MinIO, etc.)
+// Keys are the relative paths stored in the database (e.g., "dogs/dog_5_3f2a9c0d1e4b5a6f7c8d_full.jpg")
+type Storage interface {
+	// Put stores data under the key, replacing an existing file
+	Put(key string, data io.Reader, size int64, contentType string) error
+
+	// Get opens the file stored under the key
+	// Returns an error matching os.ErrNotExist if there is no such file
+	Get(key string) (io.ReadCloser, error)
+
+	// Delete removes the file stored under the key
+	// Does not return error if the file doesn't exist (idempotent)
+	Delete(key string) error
+
+	// URL returns the URL under which browsers can load the file
+	URL(key string) string
+
+	// List returns all files whose key starts with prefix (e.g., "dogs/")
+	List(prefix string) ([]StoredFile, error)
+}
+
+// StoredFile describes a file in a storage backend
+type StoredFile struct {
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// StorageConfig holds configuration for all storage backends
+type StorageConfig struct {
+	// Backend selection
+	Backend string // "local" or "s3"
+
+	// Local filesystem settings
+	UploadDir string
+
+	// S3-compatible settings
+	S3Endpoint  string // host[:port], e.g. "s3.eu-central-1.amazonaws.com" or "minio:9000"
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+
+	// Optional: public base URL of the bucket (e.g., a CDN)
+	// Leave empty to serve files through /uploads/
+	S3PublicURL string
+}
+
+// storageKey normalizes a relative path to a storage key with forward slashes
+// Leading slashes and ".." segments are removed, so keys never leave the storage root
+func storageKey(key string) string {
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(key)), "/")
+}
+
+// uploadURL returns the URL of a file served by the /uploads/ route
+func uploadURL(key string) string {
+	return "/uploads/" + storageKey(key)
+}
+
+// CopyStorage copies all files from one storage backend to another
+// Files that already exist in the target are overwritten. Returns the number of copied files.
+func CopyStorage(from, to Storage) (int, error) {
+	files, err := from.List("")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	copied := 0
+	for _, file := range files {
+		if err := copyFile(from, to, file.Key); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+
+	return copied, nil
+}
+
+// copyFile copies a single file between storage backends
+func copyFile(from, to Storage, key string) error {
+	reader, err := from.Get(key)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", key, err)
+	}
+
+	if err := to.Put(key, bytes.NewReader(data), int64(len(data)), contentTypeForKey(key)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// contentTypeForKey returns the content type of an uploaded file by its extension
+func contentTypeForKey(key string) string {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".jpg", ".jpeg":
+		return "image/jpeg"
+	case ".png":
+		return "image/png"
+	case ".webp":
+		return "image/webp"
+	default:
+		return "application/octet-stream"
+	}
+}
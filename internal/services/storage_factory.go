@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/tranmh/gassigeher/internal/config"
+)
+
+// NewStorage creates a storage backend based on configuration
+func NewStorage(config *StorageConfig) (Storage, error) {
+	if config == nil {
+		return nil, fmt.Errorf("storage config cannot be nil")
+	}
+
+	switch normalizeStorageBackend(config.Backend) {
+	case "local":
+		return NewLocalStorage(config.UploadDir), nil
+
+	case "s3":
+		if err := validateS3Config(config); err != nil {
+			return nil, err
+		}
+		return NewS3Storage(config)
+
+	default:
+		return nil, fmt.Errorf("unsupported storage backend: %s (supported: local, s3)", config.Backend)
+	}
+}
+
+// ValidateStorageConfig validates storage configuration before creating the backend
+func ValidateStorageConfig(config *StorageConfig) error {
+	if config == nil {
+		return fmt.Errorf("storage config cannot be nil")
+	}
+
+	switch normalizeStorageBackend(config.Backend) {
+	case "local":
+		if config.UploadDir == "" {
+			return fmt.Errorf("UPLOAD_DIR is required for local storage")
+		}
+		return nil
+	case "s3":
+		return validateS3Config(config)
+	default:
+		return fmt.Errorf("unsupported storage backend: %s", config.Backend)
+	}
+}
+
+// normalizeStorageBackend returns the backend name, defaulting to local storage
+func normalizeStorageBackend(backend string) string {
+	backend = strings.ToLower(strings.TrimSpace(backend))
+	if backend == "" {
+		return "local"
+	}
+	return backend
+}
+
+// validateS3Config validates S3-specific configuration
+func validateS3Config(config *StorageConfig) error {
+	if config.S3Endpoint == "" {
+		return fmt.Errorf("S3_ENDPOINT is required for S3 storage")
+	}
+	if strings.Contains(config.S3Endpoint, "://") {
+		return fmt.Errorf("S3_ENDPOINT must be host[:port] without scheme (use S3_USE_SSL)")
+	}
+	if config.S3Bucket == "" {
+		return fmt.Errorf("S3_BUCKET is required for S3 storage")
+	}
+	if config.S3AccessKey == "" || config.S3SecretKey == "" {
+		return fmt.Errorf("S3_ACCESS_KEY and S3_SECRET_KEY are required for S3 storage")
+	}
+	return nil
+}
+
+// ConfigToStorageConfig converts application config to storage config
+// This helper avoids circular dependency between config and services packages
+func ConfigToStorageConfig(cfg *config.Config) *StorageConfig {
+	return &StorageConfig{
+		Backend:     cfg.StorageBackend,
+		UploadDir:   cfg.UploadDir,
+		S3Endpoint:  cfg.S3Endpoint,
+		S3Region:    cfg.S3Region,
+		S3Bucket:    cfg.S3Bucket,
+		S3AccessKey: cfg.S3AccessKey,
+		S3SecretKey: cfg.S3SecretKey,
+		S3UseSSL:    cfg.S3UseSSL,
+		S3PublicURL: cfg.S3PublicURL,
+	}
+}
+
+// NewStorageFromConfig creates the configured storage backend for handlers
+// The configuration is validated at startup; if creating the backend still fails,
+// uploads fall back to the local upload directory with a warning
+func NewStorageFromConfig(cfg *config.Config) Storage {
+	storage, err := NewStorage(ConfigToStorageConfig(cfg))
+	if err != nil {
+		log.Printf("Warning: Failed to initialize %s storage, using local uploads: %v", cfg.StorageBackend, err)
+		return NewLocalStorage(cfg.UploadDir)
+	}
+	return storage
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage stores uploads in a directory on the local filesystem
+// Only suitable for a single instance; use S3Storage when running several instances
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage creates a new local filesystem storage rooted at dir
+func NewLocalStorage(dir string) *LocalStorage {
+	return &LocalStorage{
+		dir: dir,
+	}
+}
+
+// path returns the filesystem path of a key
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(storageKey(key)))
+}
+
+// Put stores data under the key
+// The file is written to a temporary file first, so readers never see a partial file
+func (s *LocalStorage) Put(key string, data io.Reader, size int64, contentType string) error {
+	target := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("failed to move file: %w", err)
+	}
+
+	return nil
+}
+
+// Get opens the file stored under the key
+func (s *LocalStorage) Get(key string) (io.ReadCloser, error) {
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		return nil, err
+	}
+
+	// Directories are not files of the storage
+	if info, err := file.Stat(); err != nil || info.IsDir() {
+		file.Close()
+		return nil, fmt.Errorf("file %s: %w", key, os.ErrNotExist)
+	}
+
+	return file, nil
+}
+
+// Delete removes the file stored under the key
+func (s *LocalStorage) Delete(key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// URL returns the URL of the file served by the /uploads/ route
+func (s *LocalStorage) URL(key string) string {
+	return uploadURL(key)
+}
+
+// List returns all files whose key starts with prefix
+func (s *LocalStorage) List(prefix string) ([]StoredFile, error) {
+	files := []StoredFile{}
+	err := filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == s.dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, StoredFile{Key: key, Size: info.Size(), ModifiedAt: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Storage stores uploads in an S3-compatible bucket (AWS S3, MinIO, etc.)
+// All instances share the bucket, so uploads are visible to every instance
+type S3Storage struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+// NewS3Storage creates a new S3 storage
+// Uses path-style requests, which MinIO and most S3-compatible services support
+func NewS3Storage(config *StorageConfig) (*S3Storage, error) {
+	client, err := minio.New(config.S3Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(config.S3AccessKey, config.S3SecretKey, ""),
+		Secure:       config.S3UseSSL,
+		Region:       config.S3Region,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return &S3Storage{
+		client:    client,
+		bucket:    config.S3Bucket,
+		publicURL: strings.TrimRight(config.S3PublicURL, "/"),
+	}, nil
+}
+
+// Put stores data under the key
+func (s *S3Storage) Put(key string, data io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(context.Background(), s.bucket, storageKey(key), data, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	return nil
+}
+
+// Get opens the file stored under the key
+func (s *S3Storage) Get(key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(context.Background(), s.bucket, storageKey(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	// GetObject is lazy; Stat sends the request so a missing file is reported here
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, fmt.Errorf("file %s: %w", key, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	return object, nil
+}
+
+// Delete removes the file stored under the key
+// S3 does not report missing keys on delete, so this is idempotent as well
+func (s *S3Storage) Delete(key string) error {
+	if err := s.client.RemoveObject(context.Background(), s.bucket, storageKey(key), minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// URL returns the public URL of the file if the bucket is public,
+// otherwise the URL of the /uploads/ route which streams the file from the bucket
+func (s *S3Storage) URL(key string) string {
+	if s.publicURL == "" {
+		return uploadURL(key)
+	}
+	return s.publicURL + "/" + storageKey(key)
+}
+
+// List returns all files whose key starts with prefix
+func (s *S3Storage) List(prefix string) ([]StoredFile, error) {
+	// Cancelling stops the listing goroutine if we return early on an error
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	files := []StoredFile{}
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list files: %w", object.Err)
+		}
+		files = append(files, StoredFile{Key: object.Key, Size: object.Size, ModifiedAt: object.LastModified})
+	}
+
+	return files, nil
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is a minimal in-memory stand-in for MinIO with path-style requests
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+}
+
+// newFakeS3Server starts a fake S3 server and returns a storage config pointing to it
+func newFakeS3Server(t *testing.T) (*fakeS3, *StorageConfig) {
+	fake := &fakeS3{bucket: "uploads", objects: map[string][]byte{}}
+	server := httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(server.Close)
+
+	return fake, &StorageConfig{
+		Backend:     "s3",
+		S3Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		S3Region:    "us-east-1",
+		S3Bucket:    fake.bucket,
+		S3AccessKey: "minioadmin",
+		S3SecretKey: "minioadmin",
+		S3UseSSL:    false,
+	}
+}
+
+func (f *fakeS3) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minioadmin/") {
+		f.error(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if path == f.bucket || path == f.bucket+"/" {
+		f.list(w, r.URL.Query().Get("prefix"))
+		return
+	}
+	if !strings.HasPrefix(path, f.bucket+"/") {
+		f.error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	key := strings.TrimPrefix(path, f.bucket+"/")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readS3Body(r)
+		if err != nil {
+			f.error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		f.objects[key] = data
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			f.error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("Content-Type", "application/octet-stream")
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix string) {
+	f.mu.Lock()
+	keys := []string{}
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	f.mu.Unlock()
+	sort.Strings(keys)
+
+	var contents strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&contents, "<Contents><Key>%s</Key><LastModified>2025-01-01T00:00:00.000Z</LastModified><ETag>\"etag\"</ETag><Size>1</Size><StorageClass>STANDARD</StorageClass></Contents>", key)
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Name>%s</Name><Prefix>%s</Prefix><KeyCount>%d</KeyCount><MaxKeys>1000</MaxKeys><IsTruncated>false</IsTruncated>%s</ListBucketResult>`,
+		f.bucket, prefix, len(keys), contents.String())
+}
+
+func (f *fakeS3) error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+}
+
+// readS3Body reads a request body, decoding the aws-chunked encoding used over plain HTTP
+func readS3Body(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	reader := bufio.NewReader(r.Body)
+	data := []byte{}
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseInt(strings.SplitN(strings.TrimSpace(header), ";", 2)[0], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data, nil
+		}
+		chunk := make([]byte, size+2) // data and CRLF
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return nil, err
+		}
+		data = append(data, chunk[:size]...)
+	}
+}
+
+// testStorage runs the common storage contract against a backend
+func testStorage(t *testing.T, storage Storage) {
+	put := func(key, content string) {
+		if err := storage.Put(key, strings.NewReader(content), int64(len(content)), "image/jpeg"); err != nil {
+			t.Fatalf("Put(%s) failed: %v", key, err)
+		}
+	}
+
+	t.Run("put and get", func(t *testing.T) {
+		put("dogs/dog_1_full.jpg", "full")
+		put("dogs/dog_1_thumb.jpg", "thumb")
+		put("incidents/incident_1.jpg", "incident")
+
+		reader, err := storage.Get("dogs/dog_1_full.jpg")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		defer reader.Close()
+		data, _ := io.ReadAll(reader)
+		if string(data) != "full" {
+			t.Errorf("Expected content 'full', got %q", data)
+		}
+	})
+
+	t.Run("put replaces file", func(t *testing.T) {
+		put("dogs/dog_1_thumb.jpg", "new thumb")
+		reader, err := storage.Get("dogs/dog_1_thumb.jpg")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		defer reader.Close()
+		data, _ := io.ReadAll(reader)
+		if string(data) != "new thumb" {
+			t.Errorf("Expected replaced content, got %q", data)
+		}
+	})
+
+	t.Run("get missing file", func(t *testing.T) {
+		_, err := storage.Get("dogs/missing.jpg")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected os.ErrNotExist, got %v", err)
+		}
+	})
+
+	t.Run("list by prefix", func(t *testing.T) {
+		files, err := storage.List("dogs/")
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		keys := []string{}
+		for _, file := range files {
+			keys = append(keys, file.Key)
+			if file.Size == 0 || file.ModifiedAt.IsZero() {
+				t.Errorf("Expected size and modification time for %s", file.Key)
+			}
+		}
+		sort.Strings(keys)
+		if strings.Join(keys, ",") != "dogs/dog_1_full.jpg,dogs/dog_1_thumb.jpg" {
+			t.Errorf("Unexpected keys %v", keys)
+		}
+	})
+
+	t.Run("delete is idempotent", func(t *testing.T) {
+		if err := storage.Delete("dogs/dog_1_thumb.jpg"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if err := storage.Delete("dogs/dog_1_thumb.jpg"); err != nil {
+			t.Errorf("Second delete should not fail: %v", err)
+		}
+		if _, err := storage.Get("dogs/dog_1_thumb.jpg"); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected deleted file to be missing, got %v", err)
+		}
+	})
+
+	t.Run("keys cannot leave the storage root", func(t *testing.T) {
+		put("../escape.jpg", "escape")
+		files, _ := storage.List("")
+		found := false
+		for _, file := range files {
+			found = found || file.Key == "escape.jpg"
+		}
+		if !found {
+			t.Errorf("Expected key to be cleaned to escape.jpg, got %v", files)
+		}
+	})
+}
+
+// DONE: TestLocalStorage tests the local filesystem storage
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	storage := NewLocalStorage(filepath.Join(dir, "uploads"))
+
+	testStorage(t, storage)
+
+	if _, err := os.Stat(filepath.Join(dir, "escape.jpg")); !os.IsNotExist(err) {
+		t.Error("Expected no file outside of the upload directory")
+	}
+	if url := storage.URL("dogs/dog_1_full.jpg"); url != "/uploads/dogs/dog_1_full.jpg" {
+		t.Errorf("Unexpected URL %q", url)
+	}
+
+	t.Run("list missing directory", func(t *testing.T) {
+		files, err := NewLocalStorage(filepath.Join(dir, "missing")).List("")
+		if err != nil || len(files) != 0 {
+			t.Errorf("Expected empty list, got %v, %v", files, err)
+		}
+	})
+}
+
+// DONE: TestS3Storage tests the S3 storage against a MinIO stand-in
+func TestS3Storage(t *testing.T) {
+	fake, config := newFakeS3Server(t)
+	storage, err := NewStorage(config)
+	if err != nil {
+		t.Fatalf("NewStorage failed: %v", err)
+	}
+
+	testStorage(t, storage)
+
+	if _, ok := fake.objects["dogs/dog_1_full.jpg"]; !ok {
+		t.Error("Expected object in the bucket")
+	}
+
+	t.Run("URL", func(t *testing.T) {
+		if url := storage.URL("dogs/dog_1_full.jpg"); url != "/uploads/dogs/dog_1_full.jpg" {
+			t.Errorf("Expected files to be served through /uploads/, got %q", url)
+		}
+
+		config.S3PublicURL = "https://cdn.example.com/uploads/"
+		public, _ := NewStorage(config)
+		if url := public.URL("dogs/dog_1_full.jpg"); url != "https://cdn.example.com/uploads/dogs/dog_1_full.jpg" {
+			t.Errorf("Expected public bucket URL, got %q", url)
+		}
+	})
+}
+
+// DONE: TestCopyStorage tests migrating local uploads to S3
+func TestCopyStorage(t *testing.T) {
+	fake, config := newFakeS3Server(t)
+	target, err := NewStorage(config)
+	if err != nil {
+		t.Fatalf("NewStorage failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	for _, file := range []string{"dogs/dog_1_full.jpg", "users/user_1_full.jpg", "incidents/incident_1.jpg"} {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(file)), 0755)
+		os.WriteFile(filepath.Join(dir, file), []byte(file), 0644)
+	}
+
+	copied, err := CopyStorage(NewLocalStorage(dir), target)
+	if err != nil {
+		t.Fatalf("CopyStorage failed: %v", err)
+	}
+	if copied != 3 {
+		t.Errorf("Expected 3 copied files, got %d", copied)
+	}
+	if !bytes.Equal(fake.objects["users/user_1_full.jpg"], []byte("users/user_1_full.jpg")) {
+		t.Error("Expected file content in the bucket")
+	}
+}
+
+// DONE: TestValidateStorageConfig tests storage configuration validation
+func TestValidateStorageConfig(t *testing.T) {
+	valid := StorageConfig{
+		Backend:     "s3",
+		S3Endpoint:  "minio:9000",
+		S3Bucket:    "uploads",
+		S3AccessKey: "key",
+		S3SecretKey: "secret",
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(c *StorageConfig)
+		wantErr bool
+	}{
+		{"valid s3", func(c *StorageConfig) {}, false},
+		{"default local", func(c *StorageConfig) { c.Backend = ""; c.UploadDir = "./uploads" }, false},
+		{"local without directory", func(c *StorageConfig) { c.Backend = "local" }, true},
+		{"unknown backend", func(c *StorageConfig) { c.Backend = "ftp" }, true},
+		{"endpoint with scheme", func(c *StorageConfig) { c.S3Endpoint = "http://minio:9000" }, true},
+		{"missing bucket", func(c *StorageConfig) { c.S3Bucket = "" }, true},
+		{"missing credentials", func(c *StorageConfig) { c.S3SecretKey = "" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+			err := ValidateStorageConfig(&config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStorageConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// DONE: TestTOTPCode tests code generation against the SHA1 test vectors of RFC 6238
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B uses the ASCII secret "12345678901234567890" and 8 digits;
+	// the 6 digit codes are the last 6 digits of the published values
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode() failed: %v", err)
+		}
+		if code != expected {
+			t.Errorf("Expected %s at %d, got %s", expected, unix, code)
+		}
+	}
+
+	if _, err := TOTPCode("not base32!", 1); err == nil {
+		t.Error("Expected an error for an invalid secret")
+	}
+}
+
+// DONE: TestValidateTOTP tests the accepted time window and replay protection
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret() failed: %v", err)
+	}
+	now := time.Now()
+	step := TOTPStep(now)
+	code := func(s int64) string {
+		c, _ := TOTPCode(secret, s)
+		return c
+	}
+
+	if got, ok := ValidateTOTP(secret, code(step), now, 0); !ok || got != step {
+		t.Errorf("Expected the current code to be valid for step %d, got %d/%v", step, got, ok)
+	}
+	if _, ok := ValidateTOTP(secret, code(step-1), now, 0); !ok {
+		t.Error("Expected the previous code to be accepted for clock drift")
+	}
+	if _, ok := ValidateTOTP(secret, code(step+2), now, 0); ok {
+		t.Error("Expected a code two steps ahead to be rejected")
+	}
+	if _, ok := ValidateTOTP(secret, code(step), now, step); ok {
+		t.Error("Expected an already used step to be rejected")
+	}
+	if _, ok := ValidateTOTP(secret, "12345", now, 0); ok {
+		t.Error("Expected a code with the wrong length to be rejected")
+	}
+
+	t.Run("otpauth URI", func(t *testing.T) {
+		uri, err := url.Parse(TOTPURI("Gassigeher", "anna@example.com", secret))
+		if err != nil {
+			t.Fatalf("Invalid URI: %v", err)
+		}
+		if uri.Scheme != "otpauth" || uri.Host != "totp" || !strings.HasSuffix(uri.Path, "Gassigeher:anna@example.com") {
+			t.Errorf("Unexpected URI: %s", uri)
+		}
+		if uri.Query().Get("secret") != secret || uri.Query().Get("issuer") != "Gassigeher" {
+			t.Errorf("Unexpected query: %s", uri.RawQuery)
+		}
+	})
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+const (
+	// TOTPIssuer is shown as account name prefix in authenticator apps
+	TOTPIssuer = "Gassigeher"
+
+	// RecoveryCodeCount is how many recovery codes are generated at once
+	RecoveryCodeCount = 10
+
+	// recoveryCodeAlphabet leaves out characters that are easily confused (0/o, 1/l/i)
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	// MaxTwoFactorAttempts is how many wrong codes are accepted before the codes of a user are locked
+	MaxTwoFactorAttempts = 5
+
+	// TwoFactorLockDuration is how long codes are locked after too many wrong ones
+	TwoFactorLockDuration = 15 * time.Minute
+)
+
+// ErrTwoFactorLocked is returned by Setup, Enable and Verify while codes are locked after too many wrong ones
+var ErrTwoFactorLocked = errors.New("too many wrong two-factor codes")
+
+// TwoFactorService handles TOTP enrolment, verification and recovery codes
+type TwoFactorService struct {
+	twoFactorRepo *repository.TwoFactorRepository
+	settingsRepo  *repository.SettingsRepository
+	roleRepo      *repository.RoleRepository
+}
+
+// NewTwoFactorService creates a new two-factor service
+func NewTwoFactorService(twoFactorRepo *repository.TwoFactorRepository, settingsRepo *repository.SettingsRepository, roleRepo *repository.RoleRepository) *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo: twoFactorRepo,
+		settingsRepo:  settingsRepo,
+		roleRepo:      roleRepo,
+	}
+}
+
+// IsRequired returns true if the user must use 2FA, which is the case for admins if the setting
+// require_2fa_for_admins is enabled. Admins are users with any admin permission through their roles.
+func (s *TwoFactorService) IsRequired(user *models.User) bool {
+	setting, err := s.settingsRepo.Get("require_2fa_for_admins")
+	if err != nil || setting == nil || setting.Value != "true" {
+		return false
+	}
+	if user.IsAdmin || user.IsSuperAdmin {
+		return true
+	}
+
+	permissions, err := s.roleRepo.GetUserPermissions(user.ID)
+	if err != nil {
+		// Rather ask for a code than let an admin log in without one
+		log.Printf("Failed to get permissions of user %d for 2FA: %v", user.ID, err)
+		return true
+	}
+	return models.HasAdminPermission(permissions)
+}
+
+// IsEnabled returns true if the user has confirmed a TOTP secret
+func (s *TwoFactorService) IsEnabled(userID int) (bool, error) {
+	totp, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+	return totp != nil && totp.Enabled, nil
+}
+
+// Status returns the 2FA state of a user
+func (s *TwoFactorService) Status(user *models.User) (*models.TwoFactorStatus, error) {
+	status := &models.TwoFactorStatus{Required: s.IsRequired(user)}
+
+	totp, err := s.twoFactorRepo.FindByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if totp == nil || !totp.Enabled {
+		return status, nil
+	}
+
+	status.Enabled = true
+	status.EnabledAt = totp.EnabledAt
+	status.RecoveryCodesRemaining, err = s.twoFactorRepo.CountUnusedRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Setup creates a new pending secret for a user; 2FA is enabled once a code for it is confirmed
+func (s *TwoFactorService) Setup(userID int, account string) (*models.TwoFactorSetupResponse, error) {
+	// A new pending secret would also reset the count of wrong codes
+	totp, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp != nil && totp.LockedUntil != nil && time.Now().Before(*totp.LockedUntil) {
+		return nil, ErrTwoFactorLocked
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.SavePending(userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &models.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: TOTPURI(TOTPIssuer, account, secret),
+	}, nil
+}
+
+// Enable confirms the pending secret with a code and returns the first recovery codes
+// It returns false if there is no pending secret or the code is wrong, and ErrTwoFactorLocked
+// after too many wrong codes, as Verify does
+func (s *TwoFactorService) Enable(userID int, code string) ([]string, bool, error) {
+	totp, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if totp == nil || totp.Enabled {
+		return nil, false, nil
+	}
+	now := time.Now()
+	if totp.LockedUntil != nil && now.Before(*totp.LockedUntil) {
+		return nil, false, ErrTwoFactorLocked
+	}
+
+	step, ok := ValidateTOTP(totp.Secret, code, now, totp.LastUsedStep)
+	if !ok {
+		locked, err := s.twoFactorRepo.RecordFailure(userID, MaxTwoFactorAttempts, now.Add(TwoFactorLockDuration))
+		if err != nil {
+			return nil, false, err
+		}
+		if locked {
+			return nil, false, ErrTwoFactorLocked
+		}
+		return nil, false, nil
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.twoFactorRepo.Enable(userID, step, hashes); err != nil {
+		return nil, false, err
+	}
+	return codes, true, nil
+}
+
+// Verify checks a TOTP code or, if that does not match, an unused recovery code of a user with 2FA enabled
+// Accepted codes are used up. After MaxTwoFactorAttempts wrong codes, all codes are refused with
+// ErrTwoFactorLocked for TwoFactorLockDuration.
+func (s *TwoFactorService) Verify(userID int, code string) (bool, error) {
+	totp, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+	if totp == nil || !totp.Enabled {
+		return false, nil
+	}
+	now := time.Now()
+	if totp.LockedUntil != nil && now.Before(*totp.LockedUntil) {
+		return false, ErrTwoFactorLocked
+	}
+
+	valid, err := s.checkCode(totp, code, now)
+	if err != nil {
+		return false, err
+	}
+	if valid {
+		return true, s.twoFactorRepo.ResetFailures(userID)
+	}
+
+	locked, err := s.twoFactorRepo.RecordFailure(userID, MaxTwoFactorAttempts, now.Add(TwoFactorLockDuration))
+	if err != nil {
+		return false, err
+	}
+	if locked {
+		return false, ErrTwoFactorLocked
+	}
+	return false, nil
+}
+
+// checkCode uses up a matching TOTP code or unused recovery code
+func (s *TwoFactorService) checkCode(totp *models.UserTOTP, code string, now time.Time) (bool, error) {
+	if step, ok := ValidateTOTP(totp.Secret, code, now, totp.LastUsedStep); ok {
+		// A concurrent request with the same code wins only once
+		return s.twoFactorRepo.UseStep(totp.UserID, step)
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	return s.twoFactorRepo.UseRecoveryCode(totp.UserID, hashRecoveryCode(normalized))
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of a user and returns the new ones
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID int) ([]string, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable turns 2FA off and deletes the secret and recovery codes
+func (s *TwoFactorService) Disable(userID int) error {
+	return s.twoFactorRepo.Delete(userID)
+}
+
+// generateRecoveryCodes returns new recovery codes formatted as xxxxx-xxxxx, and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		for j, b := range raw {
+			raw[j] = recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)]
+		}
+		codes[i] = string(raw[:5]) + "-" + string(raw[5:])
+		hashes[i] = hashRecoveryCode(string(raw))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode lowercases a recovery code and removes separators
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code))
+}
+
+// hashRecoveryCode hashes a normalized recovery code
+// The codes are random with about 50 bits of entropy, so a fast hash is sufficient
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
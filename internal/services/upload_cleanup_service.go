@@ -0,0 +1,118 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/models"
+	"github.com/tranmh/gassigeher/internal/repository"
+)
+
+// UploadDirectories are the storage prefixes written by the application
+// Files outside of them are never reported or deleted
+var UploadDirectories = []string{"dogs/", "users/", "incidents/"}
+
+// DefaultOrphanedUploadGraceHours is used if the setting is missing or invalid
+const DefaultOrphanedUploadGraceHours = 72
+
+// UploadCleanupService reconciles upload paths in the database against stored files
+type UploadCleanupService struct {
+	uploadRepo   *repository.UploadRepository
+	settingsRepo *repository.SettingsRepository
+	storage      Storage
+}
+
+// NewUploadCleanupService creates a new upload cleanup service
+func NewUploadCleanupService(uploadRepo *repository.UploadRepository, settingsRepo *repository.SettingsRepository, storage Storage) *UploadCleanupService {
+	return &UploadCleanupService{
+		uploadRepo:   uploadRepo,
+		settingsRepo: settingsRepo,
+		storage:      storage,
+	}
+}
+
+// GracePeriod returns how old an orphaned file must be before it may be deleted
+// Uploads are stored before the database record is written, so young files are never deleted
+func (s *UploadCleanupService) GracePeriod() time.Duration {
+	hours := DefaultOrphanedUploadGraceHours
+	if setting, err := s.settingsRepo.Get("orphaned_upload_grace_hours"); err == nil && setting != nil {
+		if h, err := strconv.Atoi(setting.Value); err == nil && h > 0 {
+			hours = h
+		}
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// AutoDeleteEnabled returns true if the scheduled check may delete orphaned files
+func (s *UploadCleanupService) AutoDeleteEnabled() bool {
+	setting, err := s.settingsRepo.Get("orphaned_upload_auto_delete")
+	return err == nil && setting != nil && setting.Value == "true"
+}
+
+// Check reports stored files that nothing references (orphans) and references without a file (missing)
+// If deleteOrphans is true, orphans older than the grace period are deleted
+func (s *UploadCleanupService) Check(grace time.Duration, deleteOrphans bool) (*models.UploadCheckReport, error) {
+	now := time.Now()
+
+	// Query references before listing, so files uploaded in between look young, not old
+	references, err := s.uploadRepo.FindReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := make(map[string]bool)
+	for _, reference := range references {
+		for _, file := range UploadFiles(reference.Path) {
+			referenced[file] = true
+		}
+	}
+
+	report := &models.UploadCheckReport{
+		CheckedAt:  now,
+		References: len(references),
+		Orphans:    []*models.OrphanedUpload{},
+		Missing:    []*models.UploadReference{},
+		GraceHours: int(grace.Hours()),
+	}
+
+	stored := make(map[string]bool)
+	for _, dir := range UploadDirectories {
+		files, err := s.storage.List(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+
+		for _, file := range files {
+			stored[file.Key] = true
+			report.StoredFiles++
+			if referenced[file.Key] {
+				continue
+			}
+
+			orphan := &models.OrphanedUpload{
+				Path:       file.Key,
+				Size:       file.Size,
+				ModifiedAt: file.ModifiedAt,
+			}
+			if deleteOrphans && file.ModifiedAt.Before(now.Add(-grace)) {
+				if err := s.storage.Delete(file.Key); err != nil {
+					log.Printf("Error deleting orphaned upload %s: %v", file.Key, err)
+				} else {
+					orphan.Deleted = true
+					report.DeletedCount++
+				}
+			}
+			report.Orphans = append(report.Orphans, orphan)
+		}
+	}
+
+	for _, reference := range references {
+		if !stored[storageKey(reference.Path)] {
+			report.Missing = append(report.Missing, reference)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tranmh/gassigeher/internal/repository"
+	"github.com/tranmh/gassigeher/internal/testutil"
+)
+
+// DONE: TestUploadCleanupService_Check tests reconciling database references against stored files
+func TestUploadCleanupService_Check(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	dir := t.TempDir()
+	storage := NewLocalStorage(dir)
+	service := NewUploadCleanupService(repository.NewUploadRepository(db), repository.NewSettingsRepository(db), storage)
+
+	writeFile := func(key string, age time.Duration) {
+		path := filepath.Join(dir, filepath.FromSlash(key))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(key), 0644)
+		modified := time.Now().Add(-age)
+		os.Chtimes(path, modified, modified)
+	}
+
+	// Referenced content hashed dog photo with all variants
+	dogID := testutil.SeedTestDog(t, db, "Bella", "Labrador", "green")
+	dogPhoto := "dogs/dog_1_0123456789abcdef0123_full.jpg"
+	db.Exec(`UPDATE dogs SET photo = ?, photo_thumbnail = ? WHERE id = ?`,
+		dogPhoto, "dogs/dog_1_0123456789abcdef0123_thumb.jpg", dogID)
+	for _, file := range UploadFiles(dogPhoto) {
+		writeFile(file, 100*time.Hour)
+	}
+
+	// Referenced legacy profile photo whose file is missing
+	userID := testutil.SeedTestUser(t, db, "user@example.com", "User", "green")
+	db.Exec(`UPDATE users SET profile_photo = ? WHERE id = ?`, "users/me.jpg", userID)
+
+	// Orphans: an old one from a deleted dog, a fresh one from an upload in progress
+	writeFile("dogs/dog_9_full.jpg", 100*time.Hour)
+	writeFile("incidents/incident_1_abc.jpg", time.Hour)
+
+	// Files outside of the upload directories are ignored
+	writeFile("README.txt", 100*time.Hour)
+
+	t.Run("report only", func(t *testing.T) {
+		report, err := service.Check(service.GracePeriod(), false)
+		if err != nil {
+			t.Fatalf("Check() failed: %v", err)
+		}
+
+		if report.StoredFiles != len(UploadFiles(dogPhoto))+2 {
+			t.Errorf("Expected %d stored files, got %d", len(UploadFiles(dogPhoto))+2, report.StoredFiles)
+		}
+		if len(report.Orphans) != 2 || report.DeletedCount != 0 {
+			t.Errorf("Expected 2 orphans and nothing deleted, got %d orphans, %d deleted", len(report.Orphans), report.DeletedCount)
+		}
+		if len(report.Missing) != 1 || report.Missing[0].Source != "users.profile_photo" || report.Missing[0].RecordID != userID {
+			t.Errorf("Expected the profile photo to be missing, got %+v", report.Missing)
+		}
+		if report.GraceHours != DefaultOrphanedUploadGraceHours {
+			t.Errorf("Expected default grace period, got %d hours", report.GraceHours)
+		}
+	})
+
+	t.Run("delete orphans older than grace period", func(t *testing.T) {
+		report, err := service.Check(72*time.Hour, true)
+		if err != nil {
+			t.Fatalf("Check() failed: %v", err)
+		}
+		if report.DeletedCount != 1 {
+			t.Errorf("Expected 1 deleted orphan, got %d", report.DeletedCount)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "dogs", "dog_9_full.jpg")); !os.IsNotExist(err) {
+			t.Error("Expected the old orphan to be deleted")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "incidents", "incident_1_abc.jpg")); err != nil {
+			t.Error("Expected the fresh orphan to be kept")
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(dogPhoto))); err != nil {
+			t.Error("Expected referenced files to be kept")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "README.txt")); err != nil {
+			t.Error("Expected files outside of upload directories to be kept")
+		}
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settingsRepo := repository.NewSettingsRepository(db)
+		settingsRepo.Update("orphaned_upload_grace_hours", "24")
+		settingsRepo.Update("orphaned_upload_auto_delete", "true")
+
+		if service.GracePeriod() != 24*time.Hour {
+			t.Errorf("Expected 24h grace period, got %v", service.GracePeriod())
+		}
+		if !service.AutoDeleteEnabled() {
+			t.Error("Expected auto delete to be enabled")
+		}
+	})
+}
@@ -3,15 +3,67 @@ class API {
     constructor() {
         this.baseURL = '/api';
         this.token = localStorage.getItem('gassigeher_token');
+        this.refreshPromise = null;
     }
 
-    // Set authentication token
+    // Set authentication token; clearing it also clears the refresh token
     setToken(token) {
         this.token = token;
         if (token) {
             localStorage.setItem('gassigeher_token', token);
         } else {
             localStorage.removeItem('gassigeher_token');
+            localStorage.removeItem('gassigeher_refresh_token');
+        }
+    }
+
+    // Store the tokens of a login or refresh response
+    setSession(response) {
+        if (response.token) {
+            this.setToken(response.token);
+        }
+        if (response.refresh_token) {
+            localStorage.setItem('gassigeher_refresh_token', response.refresh_token);
+        }
+    }
+
+    // Exchange the refresh token for a new access token; concurrent callers share one request.
+    // Returns false if the session has ended and the user must log in again
+    async refreshSession() {
+        if (!this.refreshPromise) {
+            this.refreshPromise = this.doRefreshSession().finally(() => {
+                this.refreshPromise = null;
+            });
+        }
+        return this.refreshPromise;
+    }
+
+    async doRefreshSession() {
+        const refreshToken = localStorage.getItem('gassigeher_refresh_token');
+        if (!refreshToken) {
+            return false;
+        }
+
+        try {
+            const response = await fetch(`${this.baseURL}/auth/refresh`, {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ refresh_token: refreshToken }),
+            });
+            if (!response.ok) {
+                // Another tab may have rotated the refresh token in the meantime
+                const storedToken = localStorage.getItem('gassigeher_token');
+                if (storedToken && storedToken !== this.token) {
+                    this.token = storedToken;
+                    return true;
+                }
+                this.setToken(null);
+                return false;
+            }
+            this.setSession(await response.json());
+            return true;
+        } catch (error) {
+            return false;
         }
     }
 
@@ -25,8 +77,8 @@ class API {
         return !!this.token;
     }
 
-    // Make HTTP request
-    async request(method, endpoint, data = null) {
+    // Make HTTP request; an expired access token is refreshed once and the request retried
+    async request(method, endpoint, data = null, retried = false) {
         const headers = {
             'Content-Type': 'application/json',
         };
@@ -46,6 +98,13 @@ class API {
 
         try {
             const response = await fetch(`${this.baseURL}${endpoint}`, options);
+
+            if (response.status === 401 && !retried && this.token && !endpoint.startsWith('/auth/')) {
+                if (await this.refreshSession()) {
+                    return this.request(method, endpoint, data, true);
+                }
+            }
+
             const responseData = await response.json();
 
             if (!response.ok) {
@@ -63,7 +122,7 @@ class API {
     }
 
     // Upload file
-    async uploadFile(endpoint, formData) {
+    async uploadFile(endpoint, formData, retried = false) {
         const headers = {};
 
         if (this.token) {
@@ -77,6 +136,12 @@ class API {
                 body: formData,
             });
 
+            if (response.status === 401 && !retried && this.token) {
+                if (await this.refreshSession()) {
+                    return this.uploadFile(endpoint, formData, true);
+                }
+            }
+
             const responseData = await response.json();
 
             if (!response.ok) {
@@ -101,17 +166,69 @@ class API {
 
     async login(email, password) {
         const response = await this.request('POST', '/auth/login', { email, password });
-        if (response.token) {
-            this.setToken(response.token);
-        }
+        this.setSession(response);
+        return response;
+    }
+
+    // Second login step with a TOTP or recovery code, if login returned two_factor_required
+    async loginTwoFactor(preAuthToken, code) {
+        const response = await this.request('POST', '/auth/login/2fa', { pre_auth_token: preAuthToken, code });
+        this.setSession(response);
+        return response;
+    }
+
+    // Creates the TOTP secret for admins who must set up 2FA before they can log in
+    async loginTwoFactorSetup(preAuthToken) {
+        return this.request('POST', '/auth/login/2fa/setup', { pre_auth_token: preAuthToken });
+    }
+
+    // Login methods enabled on this instance (password, magic_link)
+    async getAuthMethods() {
+        return this.request('GET', '/auth/methods');
+    }
+
+    // Requests a login link by email; the link only works together with the returned device token
+    async requestMagicLink(email) {
+        const response = await this.request('POST', '/auth/magic-link', { email });
+        localStorage.setItem('gassigeher_magic_device', response.device_token);
+        return response;
+    }
+
+    // Logs in with the token of a login link, in the browser that requested it
+    async loginMagicLink(token) {
+        const deviceToken = localStorage.getItem('gassigeher_magic_device') || '';
+        const response = await this.request('POST', '/auth/login/magic-link', { token, device_token: deviceToken });
+        localStorage.removeItem('gassigeher_magic_device');
+        this.setSession(response);
+        return response;
+    }
+
+    async loginSSO(code) {
+        const response = await this.request('POST', '/auth/login/sso', { code });
+        this.setSession(response);
         return response;
     }
 
     async logout() {
+        const refreshToken = localStorage.getItem('gassigeher_refresh_token');
+        if (refreshToken) {
+            try {
+                await this.request('POST', '/auth/logout', { refresh_token: refreshToken });
+            } catch (error) {
+                // The session is dropped locally in any case
+            }
+        }
         this.setToken(null);
         window.location.href = '/';
     }
 
+    // Ends all sessions of the current user, including this one
+    async logoutAll() {
+        await this.request('POST', '/auth/logout-all');
+        this.setToken(null);
+        window.location.href = '/login.html';
+    }
+
     async forgotPassword(email) {
         return this.request('POST', '/auth/forgot-password', { email });
     }
@@ -138,6 +255,34 @@ class API {
         return this.request('GET', '/users/me');
     }
 
+    async getTwoFactorStatus() {
+        return this.request('GET', '/users/me/2fa');
+    }
+
+    async setupTwoFactor() {
+        return this.request('POST', '/users/me/2fa/setup');
+    }
+
+    async enableTwoFactor(code) {
+        return this.request('POST', '/users/me/2fa/enable', { code });
+    }
+
+    async disableTwoFactor(password, code) {
+        return this.request('POST', '/users/me/2fa/disable', { password, code });
+    }
+
+    async regenerateRecoveryCodes(code) {
+        return this.request('POST', '/users/me/2fa/recovery-codes', { code });
+    }
+
+    async getSessions() {
+        return this.request('GET', '/users/me/sessions');
+    }
+
+    async revokeSession(id) {
+        return this.request('DELETE', `/users/me/sessions/${id}`);
+    }
+
     async updateMe(data) {
         return this.request('PUT', '/users/me', data);
     }